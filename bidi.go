@@ -0,0 +1,853 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// This file implements the Unicode Bidirectional Algorithm (UAX #9), which
+// determines the visual order of text that mixes scripts written from left to
+// right with scripts written from right to left.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Bidirectional character types
+const (
+	bidiL   = iota // Left-to-right
+	bidiR          // Right-to-left
+	bidiAL         // Arabic letter
+	bidiEN         // European number
+	bidiES         // European number separator
+	bidiET         // European number terminator
+	bidiAN         // Arabic number
+	bidiCS         // Common number separator
+	bidiNSM        // Nonspacing mark
+	bidiBN         // Boundary neutral
+	bidiB          // Paragraph separator
+	bidiS          // Segment separator
+	bidiWS         // Whitespace
+	bidiON         // Other neutral
+	bidiLRE        // Left-to-right embedding
+	bidiLRO        // Left-to-right override
+	bidiRLE        // Right-to-left embedding
+	bidiRLO        // Right-to-left override
+	bidiPDF        // Pop directional format
+	bidiLRI        // Left-to-right isolate
+	bidiRLI        // Right-to-left isolate
+	bidiFSI        // First strong isolate
+	bidiPDI        // Pop directional isolate
+)
+
+const bidiMaxDepth = 125
+
+// bidiRange assigns a type to the characters from lo to hi inclusive
+type bidiRange struct {
+	lo, hi rune
+	tp     uint8
+}
+
+// bidiRanges lists the types of the characters that are not of type L, in
+// order. The list covers the scripts and symbols in common use.
+var bidiRanges = []bidiRange{
+	{0x0000, 0x0008, bidiBN}, {0x0009, 0x0009, bidiS}, {0x000A, 0x000A, bidiB},
+	{0x000B, 0x000B, bidiS}, {0x000C, 0x000C, bidiWS}, {0x000D, 0x000D, bidiB},
+	{0x000E, 0x001B, bidiBN}, {0x001C, 0x001E, bidiB}, {0x001F, 0x001F, bidiS},
+	{0x0020, 0x0020, bidiWS}, {0x0021, 0x0022, bidiON}, {0x0023, 0x0025, bidiET},
+	{0x0026, 0x002A, bidiON}, {0x002B, 0x002B, bidiES}, {0x002C, 0x002C, bidiCS},
+	{0x002D, 0x002D, bidiES}, {0x002E, 0x002F, bidiCS}, {0x0030, 0x0039, bidiEN},
+	{0x003A, 0x003A, bidiCS}, {0x003B, 0x0040, bidiON}, {0x005B, 0x0060, bidiON},
+	{0x007B, 0x007E, bidiON}, {0x007F, 0x0084, bidiBN}, {0x0085, 0x0085, bidiB},
+	{0x0086, 0x009F, bidiBN}, {0x00A0, 0x00A0, bidiCS}, {0x00A1, 0x00A1, bidiON},
+	{0x00A2, 0x00A5, bidiET}, {0x00A6, 0x00A9, bidiON}, {0x00AB, 0x00AC, bidiON},
+	{0x00AD, 0x00AD, bidiBN}, {0x00AE, 0x00AF, bidiON}, {0x00B0, 0x00B1, bidiET},
+	{0x00B2, 0x00B3, bidiEN}, {0x00B4, 0x00B4, bidiON}, {0x00B6, 0x00B8, bidiON},
+	{0x00B9, 0x00B9, bidiEN}, {0x00BB, 0x00BF, bidiON}, {0x00D7, 0x00D7, bidiON},
+	{0x00F7, 0x00F7, bidiON}, {0x02B9, 0x02BA, bidiON}, {0x02C2, 0x02CF, bidiON},
+	{0x02D2, 0x02DF, bidiON}, {0x02E5, 0x02ED, bidiON}, {0x02EF, 0x02FF, bidiON},
+	{0x0300, 0x036F, bidiNSM}, {0x0374, 0x0375, bidiON}, {0x037E, 0x037E, bidiON},
+	{0x0384, 0x0385, bidiON}, {0x0387, 0x0387, bidiON}, {0x03F6, 0x03F6, bidiON},
+	{0x0483, 0x0489, bidiNSM}, {0x058A, 0x058A, bidiON}, {0x058D, 0x058E, bidiON},
+	{0x058F, 0x058F, bidiET}, {0x0590, 0x0590, bidiR}, {0x0591, 0x05BD, bidiNSM},
+	{0x05BE, 0x05BE, bidiR}, {0x05BF, 0x05BF, bidiNSM}, {0x05C0, 0x05C0, bidiR},
+	{0x05C1, 0x05C2, bidiNSM}, {0x05C3, 0x05C3, bidiR}, {0x05C4, 0x05C5, bidiNSM},
+	{0x05C6, 0x05C6, bidiR}, {0x05C7, 0x05C7, bidiNSM}, {0x05C8, 0x05FF, bidiR},
+	{0x0600, 0x0605, bidiAN}, {0x0606, 0x0607, bidiON}, {0x0608, 0x0608, bidiAL},
+	{0x0609, 0x060A, bidiET}, {0x060B, 0x060B, bidiAL}, {0x060C, 0x060C, bidiCS},
+	{0x060D, 0x060D, bidiAL}, {0x060E, 0x060F, bidiON}, {0x0610, 0x061A, bidiNSM},
+	{0x061B, 0x064A, bidiAL}, {0x064B, 0x065F, bidiNSM}, {0x0660, 0x0669, bidiAN},
+	{0x066A, 0x066A, bidiET}, {0x066B, 0x066C, bidiAN}, {0x066D, 0x066F, bidiAL},
+	{0x0670, 0x0670, bidiNSM}, {0x0671, 0x06D5, bidiAL}, {0x06D6, 0x06DC, bidiNSM},
+	{0x06DD, 0x06DD, bidiAN}, {0x06DE, 0x06DE, bidiON}, {0x06DF, 0x06E4, bidiNSM},
+	{0x06E5, 0x06E6, bidiAL}, {0x06E7, 0x06E8, bidiNSM}, {0x06E9, 0x06E9, bidiON},
+	{0x06EA, 0x06ED, bidiNSM}, {0x06EE, 0x06EF, bidiAL}, {0x06F0, 0x06F9, bidiEN},
+	{0x06FA, 0x0710, bidiAL}, {0x0711, 0x0711, bidiNSM}, {0x0712, 0x072F, bidiAL},
+	{0x0730, 0x074A, bidiNSM}, {0x074B, 0x07A5, bidiAL}, {0x07A6, 0x07B0, bidiNSM},
+	{0x07B1, 0x07BF, bidiAL}, {0x07C0, 0x07EA, bidiR}, {0x07EB, 0x07F3, bidiNSM},
+	{0x07F4, 0x07F5, bidiR}, {0x07F6, 0x07F9, bidiON}, {0x07FA, 0x07FF, bidiR},
+	{0x0800, 0x0815, bidiR}, {0x0816, 0x082D, bidiNSM}, {0x082E, 0x0858, bidiR},
+	{0x0859, 0x085B, bidiNSM}, {0x085C, 0x085F, bidiR}, {0x0860, 0x08D2, bidiAL},
+	{0x08D3, 0x08E1, bidiNSM}, {0x08E2, 0x08E2, bidiAN}, {0x08E3, 0x0902, bidiNSM},
+	{0x093A, 0x093A, bidiNSM}, {0x093C, 0x093C, bidiNSM}, {0x0941, 0x0948, bidiNSM},
+	{0x094D, 0x094D, bidiNSM}, {0x0951, 0x0957, bidiNSM}, {0x0962, 0x0963, bidiNSM},
+	{0x0E31, 0x0E31, bidiNSM}, {0x0E34, 0x0E3A, bidiNSM}, {0x0E3F, 0x0E3F, bidiET},
+	{0x0E47, 0x0E4E, bidiNSM}, {0x1680, 0x1680, bidiWS}, {0x180E, 0x180E, bidiBN},
+	{0x2000, 0x200A, bidiWS}, {0x200B, 0x200D, bidiBN}, {0x200F, 0x200F, bidiR},
+	{0x2010, 0x2027, bidiON}, {0x2028, 0x2028, bidiWS}, {0x2029, 0x2029, bidiB},
+	{0x202A, 0x202A, bidiLRE}, {0x202B, 0x202B, bidiRLE}, {0x202C, 0x202C, bidiPDF},
+	{0x202D, 0x202D, bidiLRO}, {0x202E, 0x202E, bidiRLO}, {0x202F, 0x202F, bidiCS},
+	{0x2030, 0x2034, bidiET}, {0x2035, 0x2043, bidiON}, {0x2044, 0x2044, bidiCS},
+	{0x2045, 0x205E, bidiON}, {0x205F, 0x205F, bidiWS}, {0x2060, 0x2065, bidiBN},
+	{0x2066, 0x2066, bidiLRI}, {0x2067, 0x2067, bidiRLI}, {0x2068, 0x2068, bidiFSI},
+	{0x2069, 0x2069, bidiPDI}, {0x206A, 0x206F, bidiBN}, {0x2070, 0x2070, bidiEN},
+	{0x2074, 0x2079, bidiEN}, {0x207A, 0x207B, bidiES}, {0x207C, 0x207E, bidiON},
+	{0x2080, 0x2089, bidiEN}, {0x208A, 0x208B, bidiES}, {0x208C, 0x208E, bidiON},
+	{0x20A0, 0x20CF, bidiET}, {0x20D0, 0x20F0, bidiNSM}, {0x2100, 0x2101, bidiON},
+	{0x2103, 0x2106, bidiON}, {0x2108, 0x2109, bidiON}, {0x2114, 0x2114, bidiON},
+	{0x2116, 0x2118, bidiON}, {0x211E, 0x2123, bidiON}, {0x2125, 0x2125, bidiON},
+	{0x2127, 0x2127, bidiON}, {0x2129, 0x2129, bidiON}, {0x212E, 0x212E, bidiET},
+	{0x213A, 0x213B, bidiON}, {0x2140, 0x2144, bidiON}, {0x214A, 0x214D, bidiON},
+	{0x2150, 0x215F, bidiON}, {0x2189, 0x2211, bidiON}, {0x2212, 0x2212, bidiES},
+	{0x2213, 0x2213, bidiET}, {0x2214, 0x2335, bidiON}, {0x237B, 0x2394, bidiON},
+	{0x2396, 0x2487, bidiON}, {0x2488, 0x249B, bidiEN}, {0x24EA, 0x26AB, bidiON},
+	{0x26AD, 0x27FF, bidiON}, {0x2900, 0x2B73, bidiON}, {0x2E00, 0x2E4F, bidiON},
+	{0x3000, 0x3000, bidiWS}, {0x3001, 0x3004, bidiON}, {0x3008, 0x3020, bidiON},
+	{0x302A, 0x302D, bidiNSM}, {0x3030, 0x3030, bidiON}, {0x303D, 0x303F, bidiON},
+	{0x3099, 0x309A, bidiNSM}, {0x309B, 0x309C, bidiON}, {0x30A0, 0x30A0, bidiON},
+	{0x30FB, 0x30FB, bidiON}, {0xA490, 0xA4C6, bidiON}, {0xFB1D, 0xFB1D, bidiR},
+	{0xFB1E, 0xFB1E, bidiNSM}, {0xFB1F, 0xFB28, bidiR}, {0xFB29, 0xFB29, bidiES},
+	{0xFB2A, 0xFB4F, bidiR}, {0xFB50, 0xFD3D, bidiAL}, {0xFD3E, 0xFD3F, bidiON},
+	{0xFD40, 0xFDCF, bidiAL}, {0xFDF0, 0xFDFC, bidiAL}, {0xFDFD, 0xFDFD, bidiON},
+	{0xFE00, 0xFE0F, bidiNSM}, {0xFE10, 0xFE19, bidiON}, {0xFE20, 0xFE2F, bidiNSM},
+	{0xFE30, 0xFE4F, bidiON}, {0xFE50, 0xFE50, bidiCS}, {0xFE51, 0xFE51, bidiON},
+	{0xFE52, 0xFE52, bidiCS}, {0xFE54, 0xFE54, bidiON}, {0xFE55, 0xFE55, bidiCS},
+	{0xFE56, 0xFE5E, bidiON}, {0xFE5F, 0xFE5F, bidiET}, {0xFE60, 0xFE61, bidiON},
+	{0xFE62, 0xFE63, bidiES}, {0xFE64, 0xFE66, bidiON}, {0xFE68, 0xFE68, bidiON},
+	{0xFE69, 0xFE6A, bidiET}, {0xFE6B, 0xFE6B, bidiON}, {0xFE70, 0xFEFE, bidiAL},
+	{0xFEFF, 0xFEFF, bidiBN}, {0xFF01, 0xFF02, bidiON}, {0xFF03, 0xFF05, bidiET},
+	{0xFF06, 0xFF0A, bidiON}, {0xFF0B, 0xFF0B, bidiES}, {0xFF0C, 0xFF0C, bidiCS},
+	{0xFF0D, 0xFF0D, bidiES}, {0xFF0E, 0xFF0F, bidiCS}, {0xFF10, 0xFF19, bidiEN},
+	{0xFF1A, 0xFF1A, bidiCS}, {0xFF1B, 0xFF20, bidiON}, {0xFF3B, 0xFF40, bidiON},
+	{0xFF5B, 0xFF65, bidiON}, {0xFFE0, 0xFFE1, bidiET}, {0xFFE2, 0xFFE4, bidiON},
+	{0xFFE5, 0xFFE6, bidiET}, {0xFFE8, 0xFFEE, bidiON}, {0xFFF9, 0xFFFD, bidiON},
+	{0x10800, 0x10FFF, bidiR}, {0x1E800, 0x1EDFF, bidiR}, {0x1EE00, 0x1EEFF, bidiAL},
+	{0x1EF00, 0x1EFFF, bidiR},
+}
+
+func bidiClass(r rune) uint8 {
+	j := sort.Search(len(bidiRanges), func(j int) bool { return bidiRanges[j].hi >= r })
+	if j < len(bidiRanges) && bidiRanges[j].lo <= r {
+		return bidiRanges[j].tp
+	}
+	return bidiL
+}
+
+// bidiBrackets maps each paired bracket to its counterpart. The opening
+// brackets are the keys of bidiOpening.
+var bidiBrackets = map[rune]rune{
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{',
+	0x2045: 0x2046, 0x2046: 0x2045, 0x207D: 0x207E, 0x207E: 0x207D,
+	0x208D: 0x208E, 0x208E: 0x208D, 0x2329: 0x232A, 0x232A: 0x2329,
+	0x3008: 0x3009, 0x3009: 0x3008, 0x300A: 0x300B, 0x300B: 0x300A,
+	0x300C: 0x300D, 0x300D: 0x300C, 0x300E: 0x300F, 0x300F: 0x300E,
+	0x3010: 0x3011, 0x3011: 0x3010, 0xFF08: 0xFF09, 0xFF09: 0xFF08,
+	0xFF3B: 0xFF3D, 0xFF3D: 0xFF3B, 0xFF5B: 0xFF5D, 0xFF5D: 0xFF5B,
+}
+
+var bidiOpening = map[rune]bool{
+	'(': true, '[': true, '{': true, 0x2045: true, 0x207D: true, 0x208D: true,
+	0x2329: true, 0x3008: true, 0x300A: true, 0x300C: true, 0x300E: true,
+	0x3010: true, 0xFF08: true, 0xFF3B: true, 0xFF5B: true,
+}
+
+// bidiMirrors maps characters that are displayed mirrored in right-to-left
+// text, other than the paired brackets, to their mirror images.
+var bidiMirrors = map[rune]rune{
+	'<': '>', '>': '<', 0x00AB: 0x00BB, 0x00BB: 0x00AB, 0x2039: 0x203A,
+	0x203A: 0x2039, 0x2264: 0x2265, 0x2265: 0x2264, 0x2208: 0x220B,
+	0x220B: 0x2208, 0x2282: 0x2283, 0x2283: 0x2282, 0x2286: 0x2287,
+	0x2287: 0x2286,
+}
+
+func bidiMirror(r rune) rune {
+	if m, ok := bidiBrackets[r]; ok {
+		return m
+	}
+	if m, ok := bidiMirrors[r]; ok {
+		return m
+	}
+	return r
+}
+
+func bidiIsolateInitiator(tp uint8) bool {
+	return tp == bidiLRI || tp == bidiRLI || tp == bidiFSI
+}
+
+// bidiRemoved reports whether characters of the specified type are ignored
+// by the rules that follow the determination of explicit levels (rule X9).
+func bidiRemoved(tp uint8) bool {
+	switch tp {
+	case bidiLRE, bidiRLE, bidiLRO, bidiRLO, bidiPDF, bidiBN:
+		return true
+	}
+	return false
+}
+
+// bidiStrong returns the direction, bidiL or bidiR, of the first strong
+// character in types, skipping isolated text, and false if there is none.
+func bidiStrong(types []uint8) (uint8, bool) {
+	depth := 0
+	for _, tp := range types {
+		switch {
+		case bidiIsolateInitiator(tp):
+			depth++
+		case tp == bidiPDI:
+			if depth > 0 {
+				depth--
+			}
+		case tp == bidiB:
+			return 0, false
+		case depth == 0 && tp == bidiL:
+			return bidiL, true
+		case depth == 0 && (tp == bidiR || tp == bidiAL):
+			return bidiR, true
+		}
+	}
+	return 0, false
+}
+
+// bidiBaseLevel returns the paragraph embedding level, 0 for left-to-right
+// and 1 for right-to-left, of text whose direction is given by its first
+// strong character. Text without a strong character gets level def.
+func bidiBaseLevel(runes []rune, def int) int {
+	types := make([]uint8, len(runes))
+	for j, r := range runes {
+		types[j] = bidiClass(r)
+	}
+	if dir, ok := bidiStrong(types); ok {
+		if dir == bidiR {
+			return 1
+		}
+		return 0
+	}
+	return def
+}
+
+// bidiParagraph holds the state of the algorithm for one line of text that
+// is treated as a paragraph.
+type bidiParagraph struct {
+	runes     []rune
+	initTypes []uint8 // Types of the characters
+	types     []uint8 // Resolved types
+	levels    []int
+	embedding []int // Explicit embedding levels
+	level     int   // Paragraph embedding level
+	matchPDI  []int // Index of matching PDI of isolate initiators, or -1
+	matchInit []int // Index of matching isolate initiator of PDIs, or -1
+}
+
+// bidiVisual returns runes, a single line of text in logical order, in visual
+// order from left to right. The paragraph embedding level is 0 for a
+// left-to-right and 1 for a right-to-left paragraph. Characters that are
+// displayed mirrored in right-to-left text are replaced by their mirror
+// images, and explicit directional formatting characters are removed.
+func bidiVisual(runes []rune, level int) []rune {
+	return newBidiParagraph(runes, level).reorder()
+}
+
+// newBidiParagraph returns the single line paragraph runes with its levels
+// resolved.
+func newBidiParagraph(runes []rune, level int) *bidiParagraph {
+	p := &bidiParagraph{runes: runes, level: level}
+	n := len(runes)
+	p.initTypes = make([]uint8, n)
+	for j, r := range runes {
+		p.initTypes[j] = bidiClass(r)
+	}
+	p.types = append([]uint8(nil), p.initTypes...)
+	p.levels = make([]int, n)
+	p.matchIsolates()
+	p.explicitLevels()
+	p.embedding = append([]int(nil), p.levels...)
+	for _, seq := range p.isolatingRunSequences() {
+		p.resolveSequence(seq)
+	}
+	p.lineLevels()
+	return p
+}
+
+func (p *bidiParagraph) matchIsolates() {
+	n := len(p.runes)
+	p.matchPDI = make([]int, n)
+	p.matchInit = make([]int, n)
+	var stack []int
+	for j := 0; j < n; j++ {
+		p.matchPDI[j], p.matchInit[j] = -1, -1
+		switch tp := p.initTypes[j]; {
+		case bidiIsolateInitiator(tp):
+			stack = append(stack, j)
+		case tp == bidiPDI && len(stack) > 0:
+			init := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			p.matchPDI[init] = j
+			p.matchInit[j] = init
+		}
+	}
+}
+
+// explicitLevels applies rules X1 to X8.
+func (p *bidiParagraph) explicitLevels() {
+	type entry struct {
+		level    int
+		override uint8 // bidiON if there is no override
+		isolate  bool
+	}
+	stack := []entry{{p.level, bidiON, false}}
+	overflowIsolates, overflowEmbeddings, validIsolates := 0, 0, 0
+	nextLevel := func(rtl bool) int {
+		level := stack[len(stack)-1].level
+		if rtl {
+			return (level + 1) | 1
+		}
+		return (level + 2) &^ 1
+	}
+	for j, tp := range p.initTypes {
+		top := stack[len(stack)-1]
+		switch tp {
+		case bidiRLE, bidiLRE, bidiRLO, bidiLRO:
+			p.levels[j] = top.level
+			level := nextLevel(tp == bidiRLE || tp == bidiRLO)
+			if level <= bidiMaxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				e := entry{level, bidiON, false}
+				switch tp {
+				case bidiRLO:
+					e.override = bidiR
+				case bidiLRO:
+					e.override = bidiL
+				}
+				stack = append(stack, e)
+			} else if overflowIsolates == 0 {
+				overflowEmbeddings++
+			}
+		case bidiRLI, bidiLRI, bidiFSI:
+			p.levels[j] = top.level
+			if top.override != bidiON {
+				p.types[j] = top.override
+			}
+			rtl := tp == bidiRLI
+			if tp == bidiFSI {
+				end := p.matchPDI[j]
+				if end < 0 {
+					end = len(p.runes)
+				}
+				dir, _ := bidiStrong(p.initTypes[j+1 : end])
+				rtl = dir == bidiR
+			}
+			level := nextLevel(rtl)
+			if level <= bidiMaxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				validIsolates++
+				stack = append(stack, entry{level, bidiON, true})
+			} else {
+				overflowIsolates++
+			}
+		case bidiPDI:
+			if overflowIsolates > 0 {
+				overflowIsolates--
+			} else if validIsolates > 0 {
+				overflowEmbeddings = 0
+				for !stack[len(stack)-1].isolate {
+					stack = stack[:len(stack)-1]
+				}
+				stack = stack[:len(stack)-1]
+				validIsolates--
+			}
+			top = stack[len(stack)-1]
+			p.levels[j] = top.level
+			if top.override != bidiON {
+				p.types[j] = top.override
+			}
+		case bidiPDF:
+			p.levels[j] = top.level
+			if overflowIsolates > 0 {
+			} else if overflowEmbeddings > 0 {
+				overflowEmbeddings--
+			} else if !top.isolate && len(stack) >= 2 {
+				stack = stack[:len(stack)-1]
+			}
+		case bidiB:
+			p.levels[j] = p.level
+		default:
+			p.levels[j] = top.level
+			if top.override != bidiON && tp != bidiBN {
+				p.types[j] = top.override
+			}
+		}
+	}
+}
+
+// isolatingRunSequences returns the indexes of the characters of each
+// isolating run sequence (rule X10), leaving out removed characters.
+func (p *bidiParagraph) isolatingRunSequences() (seqs [][]int) {
+	// Level runs
+	var runs [][]int
+	var run []int
+	for j := range p.runes {
+		if bidiRemoved(p.initTypes[j]) {
+			continue
+		}
+		if len(run) > 0 && p.levels[j] != p.levels[run[0]] {
+			runs = append(runs, run)
+			run = nil
+		}
+		run = append(run, j)
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+	runOf := make(map[int]int)
+	for k, run := range runs {
+		runOf[run[0]] = k
+	}
+	for _, run := range runs {
+		first := run[0]
+		if p.initTypes[first] == bidiPDI && p.matchInit[first] >= 0 {
+			// Continues the sequence of its isolate initiator
+			continue
+		}
+		var seq []int
+		for {
+			seq = append(seq, run...)
+			last := run[len(run)-1]
+			next := -1
+			if bidiIsolateInitiator(p.initTypes[last]) {
+				next = p.matchPDI[last]
+			}
+			k, ok := runOf[next]
+			if next < 0 || !ok {
+				break
+			}
+			run = runs[k]
+		}
+		seqs = append(seqs, seq)
+	}
+	return
+}
+
+// resolveSequence applies rules W1 to I2 to an isolating run sequence.
+func (p *bidiParagraph) resolveSequence(seq []int) {
+	level := p.levels[seq[0]]
+	dirOf := func(level int) uint8 {
+		if level&1 == 1 {
+			return bidiR
+		}
+		return bidiL
+	}
+	// Start and end of sequence types
+	prevLevel, nextLevel := p.level, p.level
+	for j := seq[0] - 1; j >= 0; j-- {
+		if !bidiRemoved(p.initTypes[j]) {
+			prevLevel = p.embedding[j]
+			break
+		}
+	}
+	last := seq[len(seq)-1]
+	if !bidiIsolateInitiator(p.initTypes[last]) {
+		for j := last + 1; j < len(p.runes); j++ {
+			if !bidiRemoved(p.initTypes[j]) {
+				nextLevel = p.embedding[j]
+				break
+			}
+		}
+	}
+	sos := dirOf(maxInt(level, prevLevel))
+	eos := dirOf(maxInt(level, nextLevel))
+	types := make([]uint8, len(seq))
+	for k, j := range seq {
+		types[k] = p.types[j]
+	}
+	// W1: nonspacing marks take the type of the preceding character
+	for k, tp := range types {
+		if tp != bidiNSM {
+			continue
+		}
+		switch {
+		case k == 0:
+			types[k] = sos
+		case bidiIsolateInitiator(types[k-1]) || types[k-1] == bidiPDI:
+			types[k] = bidiON
+		default:
+			types[k] = types[k-1]
+		}
+	}
+	// W2: European numbers after Arabic letters are Arabic numbers
+	strong := sos
+	for k, tp := range types {
+		switch tp {
+		case bidiL, bidiR, bidiAL:
+			strong = tp
+		case bidiEN:
+			if strong == bidiAL {
+				types[k] = bidiAN
+			}
+		}
+	}
+	// W3
+	for k, tp := range types {
+		if tp == bidiAL {
+			types[k] = bidiR
+		}
+	}
+	// W4: single separators between numbers of the same type
+	for k := 1; k < len(types)-1; k++ {
+		prev, next := types[k-1], types[k+1]
+		switch {
+		case types[k] == bidiES && prev == bidiEN && next == bidiEN:
+			types[k] = bidiEN
+		case types[k] == bidiCS && prev == next && (prev == bidiEN || prev == bidiAN):
+			types[k] = prev
+		}
+	}
+	// W5: terminators adjacent to European numbers
+	for k := 0; k < len(types); k++ {
+		if types[k] != bidiET {
+			continue
+		}
+		end := k
+		for end < len(types) && types[end] == bidiET {
+			end++
+		}
+		if k > 0 && types[k-1] == bidiEN || end < len(types) && types[end] == bidiEN {
+			for ; k < end; k++ {
+				types[k] = bidiEN
+			}
+		}
+		k = end
+	}
+	// W6: remaining separators and terminators are neutral
+	for k, tp := range types {
+		if tp == bidiES || tp == bidiET || tp == bidiCS {
+			types[k] = bidiON
+		}
+	}
+	// W7: European numbers in left-to-right context
+	strong = sos
+	for k, tp := range types {
+		switch tp {
+		case bidiL, bidiR:
+			strong = tp
+		case bidiEN:
+			if strong == bidiL {
+				types[k] = bidiL
+			}
+		}
+	}
+	p.resolveBrackets(seq, types, sos, dirOf(level))
+	// N1 and N2: neutrals take the direction of the surrounding text if it
+	// agrees and the embedding direction otherwise
+	neutral := func(tp uint8) bool {
+		switch tp {
+		case bidiB, bidiS, bidiWS, bidiON, bidiLRI, bidiRLI, bidiFSI, bidiPDI:
+			return true
+		}
+		return false
+	}
+	strongDir := func(tp uint8) uint8 {
+		if tp == bidiL {
+			return bidiL
+		}
+		return bidiR
+	}
+	for k := 0; k < len(types); k++ {
+		if !neutral(types[k]) {
+			continue
+		}
+		end := k
+		for end < len(types) && neutral(types[end]) {
+			end++
+		}
+		before, after := sos, eos
+		if k > 0 {
+			before = strongDir(types[k-1])
+		}
+		if end < len(types) {
+			after = strongDir(types[end])
+		}
+		dir := dirOf(level)
+		if before == after {
+			dir = before
+		}
+		for ; k < end; k++ {
+			types[k] = dir
+		}
+		k = end
+	}
+	// I1 and I2: implicit levels
+	for k, j := range seq {
+		switch {
+		case level&1 == 0 && types[k] == bidiR:
+			p.levels[j]++
+		case level&1 == 0 && (types[k] == bidiAN || types[k] == bidiEN):
+			p.levels[j] += 2
+		case level&1 == 1 && (types[k] == bidiL || types[k] == bidiEN || types[k] == bidiAN):
+			p.levels[j]++
+		}
+		p.types[j] = types[k]
+	}
+}
+
+// resolveBrackets applies rule N0 to the paired brackets of a sequence.
+func (p *bidiParagraph) resolveBrackets(seq []int, types []uint8, sos, embedding uint8) {
+	type pair struct{ open, close int }
+	var pairs []pair
+	type opening struct {
+		closer rune
+		k      int
+	}
+	var stack []opening
+outer:
+	for k, j := range seq {
+		r := p.runes[j]
+		if types[k] != bidiON {
+			continue
+		}
+		if r == 0x3008 || r == 0x3009 {
+			// Canonical equivalents of the angle brackets
+			r += 0x2329 - 0x3008
+		}
+		if bidiOpening[r] {
+			if len(stack) == 63 {
+				break
+			}
+			closer := bidiBrackets[r]
+			stack = append(stack, opening{closer, k})
+			continue
+		}
+		if _, ok := bidiBrackets[r]; !ok {
+			continue
+		}
+		for n := len(stack) - 1; n >= 0; n-- {
+			if stack[n].closer == r {
+				pairs = append(pairs, pair{stack[n].k, k})
+				stack = stack[:n]
+				continue outer
+			}
+		}
+	}
+	sort.Slice(pairs, func(a, b int) bool { return pairs[a].open < pairs[b].open })
+	strongDir := func(tp uint8) (uint8, bool) {
+		switch tp {
+		case bidiL:
+			return bidiL, true
+		case bidiR, bidiAN, bidiEN:
+			return bidiR, true
+		}
+		return 0, false
+	}
+	var opposite uint8 = bidiL
+	if embedding == bidiL {
+		opposite = bidiR
+	}
+	for _, pr := range pairs {
+		foundEmbedding, foundOpposite := false, false
+		for k := pr.open + 1; k < pr.close; k++ {
+			if dir, ok := strongDir(types[k]); ok {
+				if dir == embedding {
+					foundEmbedding = true
+				} else {
+					foundOpposite = true
+				}
+			}
+		}
+		var dir uint8
+		switch {
+		case foundEmbedding:
+			dir = embedding
+		case foundOpposite:
+			context := sos
+			for k := pr.open - 1; k >= 0; k-- {
+				if d, ok := strongDir(types[k]); ok {
+					context = d
+					break
+				}
+			}
+			dir = embedding
+			if context == opposite {
+				dir = opposite
+			}
+		default:
+			continue
+		}
+		for _, k := range []int{pr.open, pr.close} {
+			types[k] = dir
+			// Nonspacing marks that follow a bracket take its direction
+			for k++; k < len(types) && p.initTypes[seq[k]] == bidiNSM; k++ {
+				types[k] = dir
+			}
+		}
+	}
+}
+
+// lineLevels applies rule L1 and gives removed characters the level of the
+// preceding character.
+func (p *bidiParagraph) lineLevels() {
+	prev := p.level
+	for j := range p.runes {
+		if bidiRemoved(p.initTypes[j]) {
+			p.levels[j] = prev
+		}
+		prev = p.levels[j]
+	}
+	trailing := true
+	for j := len(p.runes) - 1; j >= 0; j-- {
+		switch tp := p.initTypes[j]; {
+		case tp == bidiS || tp == bidiB:
+			p.levels[j] = p.level
+			trailing = true
+		case trailing && (tp == bidiWS || bidiIsolateInitiator(tp) || tp == bidiPDI || bidiRemoved(tp)):
+			p.levels[j] = p.level
+		default:
+			trailing = false
+		}
+	}
+}
+
+// visualOrder returns the indexes of the characters in visual order
+// (rule L2).
+func (p *bidiParagraph) visualOrder() []int {
+	n := len(p.runes)
+	order := make([]int, n)
+	highest, lowestOdd := 0, bidiMaxDepth+2
+	for j := range order {
+		order[j] = j
+		level := p.levels[j]
+		if level > highest {
+			highest = level
+		}
+		if level&1 == 1 && level < lowestOdd {
+			lowestOdd = level
+		}
+	}
+	for level := highest; level >= lowestOdd; level-- {
+		for j := 0; j < n; j++ {
+			if p.levels[order[j]] < level {
+				continue
+			}
+			end := j
+			for end < n && p.levels[order[end]] >= level {
+				end++
+			}
+			for a, b := j, end-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			j = end
+		}
+	}
+	return order
+}
+
+// reorder returns the characters in visual order, mirrored where required by
+// rule L4, without the explicit formatting characters.
+func (p *bidiParagraph) reorder() []rune {
+	out := make([]rune, 0, len(p.runes))
+	for _, j := range p.visualOrder() {
+		r := p.runes[j]
+		switch p.initTypes[j] {
+		case bidiLRE, bidiRLE, bidiLRO, bidiRLO, bidiPDF, bidiLRI, bidiRLI, bidiFSI, bidiPDI:
+			continue
+		}
+		if r == 0x200E || r == 0x200F {
+			continue
+		}
+		if p.levels[j]&1 == 1 {
+			r = bidiMirror(r)
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// SetBaseDirection enables the ordering of text that mixes left-to-right and
+// right-to-left scripts, such as Hebrew or Arabic with embedded Latin words
+// and numbers, by the Unicode Bidirectional Algorithm. dirStr specifies the
+// base direction of paragraphs: "L" for left-to-right, "R" for right-to-left
+// or "A" for the direction of the first letter with a strong direction. The
+// default, an empty string, disables the algorithm; text is then printed in
+// the order in which it is given, or reversed in RTL() mode.
+//
+// The algorithm applies to text printed with a UTF-8 font by Cell(),
+// CellFormat(), MultiCell(), Write() and Text(). Each line is ordered
+// separately after text has been broken into lines. MultiCell() and Write()
+// determine the direction in "A" mode from their text as a whole. Characters
+// such as brackets are mirrored in right-to-left runs. The base direction does
+// not change the alignment of text, except that the last line of justified
+// text in MultiCell() is aligned right in a right-to-left paragraph.
+func (f *Fpdf) SetBaseDirection(dirStr string) {
+	switch dirStr = strings.ToUpper(dirStr); dirStr {
+	case "", "L", "R", "A":
+		f.baseDirStr = dirStr
+	default:
+		f.err = fmt.Errorf("incorrect base direction: %s", dirStr)
+	}
+}
+
+// textDirection returns "L" or "R", the direction of the first letter in
+// txtStr with a strong direction, or "L" if there is none.
+func (f *Fpdf) textDirection(txtStr string) string {
+	if bidiBaseLevel([]rune(txtStr), 0) == 1 {
+		return "R"
+	}
+	return "L"
+}
+
+// visualText returns txtStr, a line of text in logical order, in the order in
+// which it is displayed.
+func (f *Fpdf) visualText(txtStr string) string {
+	if f.baseDirStr == "" {
+		if f.isRTL {
+			return reverseText(txtStr)
+		}
+		return txtStr
+	}
+	runes := []rune(txtStr)
+	var level int
+	switch f.baseDirStr {
+	case "R":
+		level = 1
+	case "A":
+		level = bidiBaseLevel(runes, 0)
+	}
+	if level == 0 {
+		mixed := false
+		for _, r := range runes {
+			switch bidiClass(r) {
+			case bidiR, bidiAL, bidiAN, bidiRLE, bidiRLO, bidiRLI, bidiFSI:
+				mixed = true
+			}
+		}
+		if !mixed {
+			return txtStr
+		}
+	}
+	return string(bidiVisual(runes, level))
+}
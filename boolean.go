@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"sort"
+)
+
+// Polygon adds a closed subpath of the polygon of vertices points.
+func (p *PathType) Polygon(points []PointType) {
+	for j, pt := range points {
+		if j == 0 {
+			p.MoveTo(pt.X, pt.Y)
+		} else {
+			p.LineTo(pt.X, pt.Y)
+		}
+	}
+	p.Close()
+}
+
+// Union returns a path of the area covered by p, by q or by both, such as
+// the outline of overlapping shapes to be filled or stroked as one.
+//
+// The areas of p and q are those filled with the nonzero winding number
+// rule, and curves are flattened into straight lines. The result is made of
+// closed subpaths of straight lines, which wind once around the area, so
+// that it is filled alike with the nonzero winding number and the even-odd
+// rules, and can be used with Path() and ClipPath(). It is empty if the area
+// is.
+func (p PathType) Union(q PathType) PathType {
+	return pathBoolean(p, q, func(inP, inQ bool) bool { return inP || inQ })
+}
+
+// Intersection returns a path of the area covered by both p and q. See
+// Union() for the areas of paths and the result.
+//
+// The Union() example demonstrates this method.
+func (p PathType) Intersection(q PathType) PathType {
+	return pathBoolean(p, q, func(inP, inQ bool) bool { return inP && inQ })
+}
+
+// Difference returns a path of the area covered by p and not by q, such as a
+// shape with a keep-out area cut out of it. See Union() for the areas of
+// paths and the result.
+//
+// The Union() example demonstrates this method.
+func (p PathType) Difference(q PathType) PathType {
+	return pathBoolean(p, q, func(inP, inQ bool) bool { return inP && !inQ })
+}
+
+// boolEdge is a straight edge of the outline of an area, from a to b.
+type boolEdge struct {
+	a, b PointType
+}
+
+// pathBoolean returns a path of the area of the points that are in p and q
+// as keep selects.
+func pathBoolean(p, q PathType, keep func(inP, inQ bool) bool) (res PathType) {
+	polysP, polysQ := pathPolygons(p), pathPolygons(q)
+	var edges []boolEdge
+	xMin, yMin := math.Inf(1), math.Inf(1)
+	xMax, yMax := math.Inf(-1), math.Inf(-1)
+	for _, poly := range append(append([][]PointType(nil), polysP...), polysQ...) {
+		for j, pt := range poly {
+			next := poly[(j+1)%len(poly)]
+			if pt != next {
+				edges = append(edges, boolEdge{pt, next})
+			}
+			xMin, xMax = math.Min(xMin, pt.X), math.Max(xMax, pt.X)
+			yMin, yMax = math.Min(yMin, pt.Y), math.Max(yMax, pt.Y)
+		}
+	}
+	if len(edges) == 0 {
+		return
+	}
+	// Distances below tol are those of points that are the same
+	tol := math.Max(math.Hypot(xMax-xMin, yMax-yMin), 1) * 1e-9
+	key := func(pt PointType) [2]int64 {
+		return [2]int64{int64(math.Round(pt.X / tol)), int64(math.Round(pt.Y / tol))}
+	}
+	// Edges are split where they cross or touch other edges, so that each
+	// part lies wholly inside or outside of the areas
+	splits := make([][]boolSplit, len(edges))
+	for i := range edges {
+		for j := i + 1; j < len(edges); j++ {
+			boolIntersect(edges, splits, i, j, tol)
+		}
+	}
+	type edgeKey [2][2]int64
+	seen := make(map[edgeKey]bool)
+	var parts []boolEdge
+	for i, e := range edges {
+		list := splits[i]
+		sort.Slice(list, func(a, b int) bool { return list[a].t < list[b].t })
+		pts := []PointType{e.a}
+		for _, s := range list {
+			pts = append(pts, s.pt)
+		}
+		pts = append(pts, e.b)
+		for j := 1; j < len(pts); j++ {
+			ka, kb := key(pts[j-1]), key(pts[j])
+			// Edges that overlap are kept once
+			if ka == kb || seen[edgeKey{ka, kb}] || seen[edgeKey{kb, ka}] {
+				continue
+			}
+			seen[edgeKey{ka, kb}] = true
+			parts = append(parts, boolEdge{pts[j-1], pts[j]})
+		}
+	}
+	// A part is on the outline of the result if the result is on one side
+	// of it only, and it is oriented with the result on its left
+	in := func(pt PointType) bool {
+		return keep(polygonsWinding(polysP, pt) != 0, polygonsWinding(polysQ, pt) != 0)
+	}
+	eps := tol * 100
+	next := make(map[[2]int64][]int)
+	var kept []boolEdge
+	for _, e := range parts {
+		d, ok := unitVector(e.b, e.a)
+		if !ok {
+			continue
+		}
+		mid := PointType{X: (e.a.X + e.b.X) / 2, Y: (e.a.Y + e.b.Y) / 2}
+		// The left of an edge in the page, whose vertical axis points down,
+		// is on the side of (d.Y, -d.X)
+		left := in(PointType{X: mid.X + d.Y*eps, Y: mid.Y - d.X*eps})
+		right := in(PointType{X: mid.X - d.Y*eps, Y: mid.Y + d.X*eps})
+		if left == right {
+			continue
+		}
+		if right {
+			e.a, e.b = e.b, e.a
+		}
+		next[key(e.a)] = append(next[key(e.a)], len(kept))
+		kept = append(kept, e)
+	}
+	// The outline is traced along the kept edges, each of which is
+	// followed by an edge that begins where it ends
+	used := make([]bool, len(kept))
+	for j := range kept {
+		if used[j] {
+			continue
+		}
+		var loop []PointType
+		for e, ok := j, true; ok; {
+			used[e] = true
+			loop = append(loop, kept[e].a)
+			ok = false
+			for _, n := range next[key(kept[e].b)] {
+				if !used[n] {
+					e, ok = n, true
+					break
+				}
+			}
+		}
+		loop = polygonSimplify(loop, tol)
+		if len(loop) > 2 {
+			res.Polygon(loop)
+		}
+	}
+	return
+}
+
+// boolSplit is a point where an edge is split, at the fraction t of its
+// length.
+type boolSplit struct {
+	t  float64
+	pt PointType
+}
+
+// boolIntersect adds the points where the edges i and j cross or touch to
+// the splits of the edges.
+func boolIntersect(edges []boolEdge, splits [][]boolSplit, i, j int, tol float64) {
+	a, b := edges[i], edges[j]
+	r := PointType{X: a.b.X - a.a.X, Y: a.b.Y - a.a.Y}
+	s := PointType{X: b.b.X - b.a.X, Y: b.b.Y - b.a.Y}
+	w := PointType{X: b.a.X - a.a.X, Y: b.a.Y - a.a.Y}
+	lr, ls := math.Hypot(r.X, r.Y), math.Hypot(s.X, s.Y)
+	add := func(e int, t float64, pt PointType, l float64) {
+		// Points at the ends of an edge do not split it
+		if t*l > tol && (1-t)*l > tol {
+			splits[e] = append(splits[e], boolSplit{t, pt})
+		}
+	}
+	den := r.X*s.Y - r.Y*s.X
+	if math.Abs(den) > 1e-12*lr*ls {
+		t := (w.X*s.Y - w.Y*s.X) / den
+		u := (w.X*r.Y - w.Y*r.X) / den
+		if t*lr < -tol || (t-1)*lr > tol || u*ls < -tol || (u-1)*ls > tol {
+			return
+		}
+		// The point is the end of an edge if it touches it, so that both
+		// edges are split at the same point
+		pt := PointType{X: a.a.X + t*r.X, Y: a.a.Y + t*r.Y}
+		switch {
+		case math.Abs(u)*ls <= tol:
+			pt = b.a
+		case math.Abs(u-1)*ls <= tol:
+			pt = b.b
+		case math.Abs(t)*lr <= tol:
+			pt = a.a
+		case math.Abs(t-1)*lr <= tol:
+			pt = a.b
+		}
+		add(i, t, pt, lr)
+		add(j, u, pt, ls)
+		return
+	}
+	// Collinear edges that overlap are split at the ends of each other
+	if math.Abs(w.X*r.Y-w.Y*r.X) > tol*lr {
+		return
+	}
+	for _, pt := range []PointType{b.a, b.b} {
+		add(i, ((pt.X-a.a.X)*r.X+(pt.Y-a.a.Y)*r.Y)/(lr*lr), pt, lr)
+	}
+	for _, pt := range []PointType{a.a, a.b} {
+		add(j, ((pt.X-b.a.X)*s.X+(pt.Y-b.a.Y)*s.Y)/(ls*ls), pt, ls)
+	}
+}
+
+// pathPolygons returns the subpaths of p flattened into polygons, which are
+// closed implicitly.
+func pathPolygons(p PathType) (list [][]PointType) {
+	for _, sub := range pathSubpaths(p.segs) {
+		if pts := subpathPoints(p.segs[sub[0]:sub[1]], 32); len(pts) > 2 {
+			list = append(list, pts)
+		}
+	}
+	return
+}
+
+// polygonsWinding returns the number of times the polygons wind around pt,
+// counted positively counter-clockwise as seen on the page.
+func polygonsWinding(polys [][]PointType, pt PointType) (n int) {
+	for _, poly := range polys {
+		for j, a := range poly {
+			b := poly[(j+1)%len(poly)]
+			side := (b.X-a.X)*(pt.Y-a.Y) - (pt.X-a.X)*(b.Y-a.Y)
+			if a.Y <= pt.Y && b.Y > pt.Y && side > 0 {
+				n--
+			} else if a.Y > pt.Y && b.Y <= pt.Y && side < 0 {
+				n++
+			}
+		}
+	}
+	return
+}
+
+// polygonSimplify returns the polygon pts without the vertices that are in
+// line with their neighbors.
+func polygonSimplify(pts []PointType, tol float64) []PointType {
+	for again := true; again && len(pts) > 2; {
+		again = false
+		var list []PointType
+		for j, pt := range pts {
+			prev, next := pts[(j+len(pts)-1)%len(pts)], pts[(j+1)%len(pts)]
+			d1, ok1 := unitVector(pt, prev)
+			d2, ok2 := unitVector(next, pt)
+			if ok1 && ok2 && math.Abs(d1.X*d2.Y-d1.Y*d2.X) < 1e-9 && d1.X*d2.X+d1.Y*d2.Y > 0 {
+				again = true
+				continue
+			}
+			if ok1 || len(list) == 0 {
+				list = append(list, pt)
+			}
+		}
+		pts = list
+	}
+	return pts
+}
@@ -0,0 +1,432 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MeasureBounds calls fnc, which draws text, drawings and images with the
+// methods of f, and returns the bounding box of what it draws, as the
+// position (x, y) of its upper left corner and its width w and height h,
+// without painting any of it. This makes it possible, for example, to draw a
+// border or a background exactly around content whose size is not known in
+// advance, by measuring the content and then drawing it over the border.
+//
+// The box is in the unit of measure and the coordinates in effect when this
+// method is called, and encloses the content as it is transformed within fnc.
+// It holds the paths that are filled or stroked, with half of the line width
+// on either side of strokes, text from the ascent to the descent of its font,
+// and images, templates and transparency groups, as far as they are within
+// the clipping areas and soft masks set within fnc. The corners of miter
+// joins and the parts of glyphs that reach beyond the ascent or descent of
+// their font are not taken into account. Zero values are returned if nothing
+// is drawn.
+//
+// Once fnc returns, the position, the colors, the line width, the font and
+// the other parameters of the graphics state are restored to those in effect
+// when this method was called, and the links added by fnc are removed, so
+// that the measurement has no effect on what follows. Automatic page breaks
+// are disabled while fnc is called. The clipping operations,
+// transformations, transparency groups and graphics states that fnc begins
+// must be ended within it. Measurements can be nested.
+func (f *Fpdf) MeasureBounds(fnc func()) (x, y, w, h float64) {
+	content, ok := f.captureContent("measurement", func() {
+		f.measuring = true
+		fnc()
+	})
+	if !ok {
+		return
+	}
+	box := f.contentBounds(content, boundsState{ctm: TransformMatrix{A: 1, D: 1}, lineWidth: 1})
+	if !box.set {
+		return
+	}
+	return box.x0 / f.k, f.h - box.y1/f.k, (box.x1 - box.x0) / f.k, (box.y1 - box.y0) / f.k
+}
+
+// captureContent calls fnc and returns the content that it writes on the
+// current page, which is not output, for the operation nameStr. The
+// position, the graphics state and the links of the page are restored and
+// automatic page breaks are disabled as MeasureBounds() describes. ok is false
+// if fnc fails or leaves clipping operations, transformations, transparency
+// groups or graphics states begun.
+func (f *Fpdf) captureContent(nameStr string, fnc func()) (content []byte, ok bool) {
+	if f.err != nil {
+		return
+	}
+	if f.page == 0 {
+		f.SetErrorf("no page for the %s", nameStr)
+		return
+	}
+	page, pageContent := f.page, f.pages[f.page]
+	st, stateCount := f.graphicsState(), len(f.stateStack)
+	posX, posY, lastH := f.x, f.y, f.lasth
+	linkCount := len(f.pageLinks[page])
+	autoPageBreak, measuring := f.autoPageBreak, f.measuring
+	f.pages[page] = new(bytes.Buffer)
+	f.autoPageBreak = false
+	fnc()
+	content = f.pages[page].Bytes()
+	f.pages[page] = pageContent
+	f.autoPageBreak, f.measuring = autoPageBreak, measuring
+	f.x, f.y, f.lasth = posX, posY, lastH
+	f.setGraphicsState(st)
+	f.pageLinks[page] = f.pageLinks[page][:linkCount]
+	if f.err != nil {
+		return
+	}
+	switch {
+	case f.page != page:
+		f.SetErrorf("%s spans pages %d to %d", nameStr, page, f.page)
+		return
+	case f.clipNest != st.clipNest || f.transformNest != st.transformNest ||
+		len(f.groupNest) != st.groupNest || len(f.stateStack) != stateCount:
+		f.SetErrorf("clipping operation, transformation, transparency group or graphics state begun in the %s must be ended in it", nameStr)
+		return
+	}
+	return content, true
+}
+
+// measureText adds the box of the text of width w whose baseline starts at
+// (x, y) to the content being measured by MeasureBounds(), as the glyphs that
+// text shows cannot be measured from the content itself. The box is written
+// as a comment, which has no effect on the rendering of the content.
+func (f *Fpdf) measureText(x, y, w float64) {
+	if !f.measuring {
+		return
+	}
+	m := f.FontMetrics("", "")
+	y -= f.textRise
+	f.outf("%%bounds %.5f %.5f %.5f %.5f", x*f.k, (f.h-y+m.Descent)*f.k, w*f.k, (m.Ascent-m.Descent)*f.k)
+}
+
+// boundsBox is a box of the page, in points with the y axis pointing up,
+// which is empty unless set is true.
+type boundsBox struct {
+	x0, y0, x1, y1 float64
+	set            bool
+}
+
+// addPoint extends b to hold the point (x, y).
+func (b *boundsBox) addPoint(x, y float64) {
+	if !b.set {
+		*b = boundsBox{x, y, x, y, true}
+		return
+	}
+	b.x0, b.y0 = math.Min(b.x0, x), math.Min(b.y0, y)
+	b.x1, b.y1 = math.Max(b.x1, x), math.Max(b.y1, y)
+}
+
+// addBox extends b to hold the box o.
+func (b *boundsBox) addBox(o boundsBox) {
+	if o.set {
+		b.addPoint(o.x0, o.y0)
+		b.addPoint(o.x1, o.y1)
+	}
+}
+
+// boundsIntersect returns the part of the box a that is within the box b.
+func boundsIntersect(a, b boundsBox) boundsBox {
+	if !a.set || !b.set {
+		return boundsBox{}
+	}
+	r := boundsBox{math.Max(a.x0, b.x0), math.Max(a.y0, b.y0), math.Min(a.x1, b.x1), math.Min(a.y1, b.y1), true}
+	if r.x0 > r.x1 || r.y0 > r.y1 {
+		return boundsBox{}
+	}
+	return r
+}
+
+// boundsState is the part of the graphics state of a content stream that
+// bears on the bounds of what it paints. clip and mask are the bounds of the
+// clipping area and of the soft mask if clipped and masked are true.
+type boundsState struct {
+	ctm             TransformMatrix
+	lineWidth       float64
+	clip, mask      boundsBox
+	clipped, masked bool
+}
+
+// boundsRect returns the bounds of the rectangle of the content of the
+// corner (x, y), width w and height h, as transformed by ctm.
+func boundsRect(ctm TransformMatrix, x, y, w, h float64) (b boundsBox) {
+	for _, pt := range []PointType{{x, y}, {x + w, y}, {x, y + h}, {x + w, y + h}} {
+		b.addPoint(MatrixType(ctm).TransformPoint(pt.X, pt.Y))
+	}
+	return
+}
+
+// boundsCurve extends b to hold the cubic Bézier curve from p0 to p3 of
+// control points p1 and p2, at its extreme points.
+func boundsCurve(b *boundsBox, p0, p1, p2, p3 PointType) {
+	b.addPoint(p3.X, p3.Y)
+	at := func(v0, v1, v2, v3, t float64) float64 {
+		u := 1 - t
+		return u*u*u*v0 + 3*u*u*t*v1 + 3*u*t*t*v2 + t*t*t*v3
+	}
+	// The extremes are where the derivative, of which these are the
+	// coefficients divided by 3, is 0
+	extremes := func(v0, v1, v2, v3 float64) (list []float64) {
+		a, b, c := -v0+3*v1-3*v2+v3, 2*(v0-2*v1+v2), v1-v0
+		if math.Abs(a) < 1e-12 {
+			if math.Abs(b) > 1e-12 {
+				list = append(list, -c/b)
+			}
+			return
+		}
+		if d := b*b - 4*a*c; d >= 0 {
+			d = math.Sqrt(d)
+			list = append(list, (-b+d)/(2*a), (-b-d)/(2*a))
+		}
+		return
+	}
+	for _, t := range append(extremes(p0.X, p1.X, p2.X, p3.X), extremes(p0.Y, p1.Y, p2.Y, p3.Y)...) {
+		if t > 0 && t < 1 {
+			b.addPoint(at(p0.X, p1.X, p2.X, p3.X, t), at(p0.Y, p1.Y, p2.Y, p3.Y, t))
+		}
+	}
+}
+
+// boundsDelimiter reports whether c ends a token of a content stream.
+func boundsDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f\x00()<>[]{}/%", c) >= 0
+}
+
+// contentBounds returns the bounds of what the content stream content paints
+// in the graphics state st.
+func (f *Fpdf) contentBounds(content []byte, st boundsState) (box boundsBox) {
+	var stack []boundsState
+	var nums []float64
+	var nameStr string
+	var path boundsBox
+	var cur PointType // Current point, in the space of the content
+	clipping := false
+	point := func(x, y float64) PointType {
+		x, y = MatrixType(st.ctm).TransformPoint(x, y)
+		return PointType{X: x, Y: y}
+	}
+	paint := func(b boundsBox) {
+		if st.clipped {
+			b = boundsIntersect(b, st.clip)
+		}
+		if st.masked {
+			b = boundsIntersect(b, st.mask)
+		}
+		box.addBox(b)
+	}
+	clip := func(b boundsBox) {
+		if st.clipped {
+			st.clip = boundsIntersect(st.clip, b)
+		} else {
+			st.clip, st.clipped = b, true
+		}
+	}
+	for j := 0; j < len(content); {
+		c := content[j]
+		switch {
+		case strings.IndexByte(" \t\r\n\f\x00[]{}>", c) >= 0:
+			j++
+		case c == '%':
+			end := bytes.IndexByte(content[j:], '\n')
+			if end < 0 {
+				end = len(content) - j
+			}
+			var x, y, w, h float64
+			if n, _ := fmt.Sscanf(string(content[j:j+end]), "%%bounds %f %f %f %f", &x, &y, &w, &h); n == 4 {
+				paint(boundsRect(st.ctm, x, y, w, h))
+			}
+			j += end
+		case c == '(':
+			// Strings are skipped along with their nested parentheses
+			depth := 0
+			for j < len(content) {
+				switch content[j] {
+				case '\\':
+					j++
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+				j++
+				if depth == 0 {
+					break
+				}
+			}
+		case c == '<':
+			if j+1 < len(content) && content[j+1] == '<' {
+				j += 2
+			} else if end := bytes.IndexByte(content[j:], '>'); end >= 0 {
+				j += end + 1
+			} else {
+				j = len(content)
+			}
+		default:
+			k := j + 1
+			for k < len(content) && !boundsDelimiter(content[k]) {
+				k++
+			}
+			tok := string(content[j:k])
+			j = k
+			if c == '/' {
+				nameStr = tok
+				continue
+			}
+			if v, err := strconv.ParseFloat(tok, 64); err == nil {
+				nums = append(nums, v)
+				continue
+			}
+			// args returns the last count operands, or nil if there are
+			// fewer
+			args := func(count int) []float64 {
+				if len(nums) < count {
+					return nil
+				}
+				return nums[len(nums)-count:]
+			}
+			switch tok {
+			case "q":
+				stack = append(stack, st)
+			case "Q":
+				if n := len(stack); n > 0 {
+					st, stack = stack[n-1], stack[:n-1]
+				}
+			case "cm":
+				if a := args(6); a != nil {
+					st.ctm = TransformMatrix(MatrixType{a[0], a[1], a[2], a[3], a[4], a[5]}.Multiply(MatrixType(st.ctm)))
+				}
+			case "w":
+				if a := args(1); a != nil {
+					st.lineWidth = a[0]
+				}
+			case "m", "l":
+				if a := args(2); a != nil {
+					cur = PointType{X: a[0], Y: a[1]}
+					pt := point(cur.X, cur.Y)
+					path.addPoint(pt.X, pt.Y)
+				}
+			case "c", "v", "y":
+				a := args(intIf(tok == "c", 6, 4))
+				if a == nil {
+					break
+				}
+				// The v and y operators omit the first and the second
+				// control points, which are the start and the end
+				var p1, p2, p3 PointType
+				switch tok {
+				case "c":
+					p1, p2, p3 = PointType{X: a[0], Y: a[1]}, PointType{X: a[2], Y: a[3]}, PointType{X: a[4], Y: a[5]}
+				case "v":
+					p1, p2, p3 = cur, PointType{X: a[0], Y: a[1]}, PointType{X: a[2], Y: a[3]}
+				default:
+					p1, p2, p3 = PointType{X: a[0], Y: a[1]}, PointType{X: a[2], Y: a[3]}, PointType{X: a[2], Y: a[3]}
+				}
+				boundsCurve(&path, point(cur.X, cur.Y), point(p1.X, p1.Y), point(p2.X, p2.Y), point(p3.X, p3.Y))
+				cur = p3
+			case "re":
+				if a := args(4); a != nil {
+					path.addBox(boundsRect(st.ctm, a[0], a[1], a[2], a[3]))
+					cur = PointType{X: a[0], Y: a[1]}
+				}
+			case "W", "W*":
+				clipping = true
+			case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n":
+				b := path
+				if strings.ContainsAny(tok, "SsBb") && b.set {
+					// Strokes extend by half of the line width, as scaled by
+					// the transformation, on either side of the path
+					m := st.ctm
+					r := st.lineWidth / 2 * math.Sqrt(math.Abs(m.A*m.D-m.B*m.C))
+					b.x0, b.y0, b.x1, b.y1 = b.x0-r, b.y0-r, b.x1+r, b.y1+r
+				}
+				if tok != "n" {
+					paint(b)
+				}
+				if clipping {
+					clip(path)
+				}
+				path, clipping = boundsBox{}, false
+			case "sh":
+				// Shadings fill the clipping area
+				if st.clipped {
+					paint(st.clip)
+				}
+			case "Do":
+				paint(f.xobjectBounds(nameStr, st))
+			case "gs":
+				if !strings.HasPrefix(nameStr, "/SM") {
+					break
+				}
+				pos, err := strconv.Atoi(nameStr[3:])
+				if err != nil || pos <= 0 || pos >= len(f.softMaskList) {
+					break
+				}
+				// Nothing is painted where the mask is empty
+				if grp := f.softMaskList[pos].group; grp > 0 {
+					st.mask = f.contentBounds(f.groupList[grp].content, boundsState{ctm: st.ctm, lineWidth: 1})
+					st.masked = true
+				} else {
+					st.masked = false
+				}
+			}
+			nums = nums[:0]
+		}
+	}
+	return
+}
+
+// xobjectBounds returns the bounds of the external object nameStr painted in
+// the graphics state st.
+func (f *Fpdf) xobjectBounds(nameStr string, st boundsState) boundsBox {
+	switch {
+	case strings.HasPrefix(nameStr, "/TG"):
+		pos, err := strconv.Atoi(nameStr[3:])
+		if err != nil || pos <= 0 || pos >= len(f.groupList) {
+			break
+		}
+		// A group is clipped to its bounding box, and drawn without the soft
+		// mask of the page
+		grp := f.groupList[pos]
+		sub := st
+		sub.masked = false
+		bbox := boundsRect(st.ctm, grp.bbox[0], grp.bbox[1], grp.bbox[2]-grp.bbox[0], grp.bbox[3]-grp.bbox[1])
+		if sub.clipped {
+			sub.clip = boundsIntersect(sub.clip, bbox)
+		} else {
+			sub.clip, sub.clipped = bbox, true
+		}
+		return f.contentBounds(grp.content, sub)
+	case strings.HasPrefix(nameStr, "/TPL"):
+		if t, ok := f.templates[nameStr[4:]]; ok {
+			corner, size := t.Size()
+			return boundsRect(st.ctm, corner.X*f.k, corner.Y*f.k, size.Wd*f.k, size.Ht*f.k)
+		}
+	case strings.HasPrefix(nameStr, "/SY"):
+		if pos, err := strconv.Atoi(nameStr[3:]); err == nil && pos > 0 && pos < len(f.symbolList) {
+			return f.contentBounds(f.symbolList[pos].content, st)
+		}
+	case strings.HasPrefix(nameStr, "/I"):
+		// Images fill the unit square
+		return boundsRect(st.ctm, 0, 0, 1, 1)
+	}
+	return boundsBox{}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import "strings"
+
+// TextLineType is a line of text broken by BreakLines().
+type TextLineType struct {
+	// The text of the line, without the space or the newline at which it is
+	// broken.
+	Str string
+	// The width of the text of the line in the unit of measure specified in
+	// New(), as returned by GetSpacedStringWidth().
+	Width float64
+	// Forced is true for a line ended by a newline or by the end of the text,
+	// rather than broken because the text that follows does not fit on it.
+	// MultiCell() does not justify such lines.
+	Forced bool
+	// Hyphenated is true for a line that ends with a hyphen added by the
+	// hyphenation of a word, as set with SetHyphenator().
+	Hyphenated bool
+}
+
+// BreakLines breaks txtStr into lines with the current font as MultiCell()
+// does for cells of width w, so that layouts built on the lines, such as the
+// cells of a table or the text of a balloon, break text exactly as
+// MultiCell() does. A value of zero for w indicates lines that reach to the
+// right margin from the current position. The lines are at most w less the
+// cell margins wide.
+//
+// Unlike SplitLines() and SplitText(), BreakLines() takes hyphenation into
+// account and returns the width of each line and how it ends. Like them, it
+// takes character and word spacing into account.
+func (f *Fpdf) BreakLines(txtStr string, w float64) (lines []TextLineType) {
+	if f.err != nil {
+		return
+	}
+	if f.currentFont.Name == "" {
+		f.SetErrorf("font has not been set; unable to break text")
+		return
+	}
+	if w == 0 {
+		w = f.w - f.rMargin - f.x
+	}
+	for _, line := range f.multiCellLines(f.shapeText(txtStr), w, false) {
+		lines = append(lines, TextLineType{Str: line.str, Width: f.GetSpacedStringWidth(line.str),
+			Forced: line.last, Hyphenated: line.hyphen})
+	}
+	return
+}
+
+// joinTextLines returns the text of lines, as broken by BreakLines(), each
+// followed by the newline, the space or the hyphenation at which it is broken,
+// so that MultiCell() breaks the text again in the same lines.
+func joinTextLines(lines []TextLineType) string {
+	var s strings.Builder
+	for _, line := range lines {
+		switch {
+		case line.Forced:
+			s.WriteString(line.Str + "\n")
+		case line.Hyphenated:
+			s.WriteString(strings.TrimSuffix(line.Str, "-"))
+		default:
+			s.WriteString(line.Str + " ")
+		}
+	}
+	return s.String()
+}
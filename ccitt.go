@@ -0,0 +1,227 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+)
+
+// Run-length code tables of ITU-T T.4, indexed by run length (terminating
+// codes) or by run length / 64 - 1 (make-up codes)
+
+var ccittWhiteTerm = [64]string{
+	"00110101", "000111", "0111", "1000", "1011", "1100", "1110", "1111",
+	"10011", "10100", "00111", "01000", "001000", "000011", "110100", "110101",
+	"101010", "101011", "0100111", "0001100", "0001000", "0010111", "0000011", "0000100",
+	"0101000", "0101011", "0010011", "0100100", "0011000", "00000010", "00000011", "00011010",
+	"00011011", "00010010", "00010011", "00010100", "00010101", "00010110", "00010111", "00101000",
+	"00101001", "00101010", "00101011", "00101100", "00101101", "00000100", "00000101", "00001010",
+	"00001011", "01010010", "01010011", "01010100", "01010101", "00100100", "00100101", "01011000",
+	"01011001", "01011010", "01011011", "01001010", "01001011", "00110010", "00110011", "00110100",
+}
+
+var ccittBlackTerm = [64]string{
+	"0000110111", "010", "11", "10", "011", "0011", "0010", "00011",
+	"000101", "000100", "0000100", "0000101", "0000111", "00000100", "00000111", "000011000",
+	"0000010111", "0000011000", "0000001000", "00001100111", "00001101000", "00001101100", "00000110111", "00000101000",
+	"00000010111", "00000011000", "000011001010", "000011001011", "000011001100", "000011001101", "000001101000", "000001101001",
+	"000001101010", "000001101011", "000011010010", "000011010011", "000011010100", "000011010101", "000011010110", "000011010111",
+	"000001101100", "000001101101", "000011011010", "000011011011", "000001010100", "000001010101", "000001010110", "000001010111",
+	"000001100100", "000001100101", "000001010010", "000001010011", "000000100100", "000000110111", "000000111000", "000000100111",
+	"000000101000", "000001011000", "000001011001", "000000101011", "000000101100", "000001011010", "000001100110", "000001100111",
+}
+
+var ccittWhiteMakeup = [27]string{
+	"11011", "10010", "010111", "0110111", "00110110", "00110111", "01100100", "01100101",
+	"01101000", "01100111", "011001100", "011001101", "011010010", "011010011", "011010100", "011010101",
+	"011010110", "011010111", "011011000", "011011001", "011011010", "011011011", "010011000", "010011001",
+	"010011010", "011000", "010011011",
+}
+
+var ccittBlackMakeup = [27]string{
+	"0000001111", "000011001000", "000011001001", "000001011011", "000000110011", "000000110100", "000000110101", "0000001101100",
+	"0000001101101", "0000001001010", "0000001001011", "0000001001100", "0000001001101", "0000001110010", "0000001110011", "0000001110100",
+	"0000001110101", "0000001110110", "0000001110111", "0000001010010", "0000001010011", "0000001010100", "0000001010101", "0000001011010",
+	"0000001011011", "0000001100100", "0000001100101",
+}
+
+// Make-up codes for runs of 1792 through 2560, shared by both colors
+var ccittExtMakeup = [13]string{
+	"00000001000", "00000001100", "00000001101", "000000010010", "000000010011", "000000010100", "000000010101",
+	"000000010110", "000000010111", "000000011100", "000000011101", "000000011110", "000000011111",
+}
+
+// Two-dimensional mode codes of ITU-T T.6; vertical codes are indexed by
+// a1 - b1 + 3
+var ccittVertical = [7]string{"0000010", "000010", "010", "1", "011", "000011", "0000011"}
+
+const (
+	ccittPass       = "0001"
+	ccittHorizontal = "001"
+	ccittEOL        = "000000000001"
+)
+
+// ccittWriter accumulates a bit stream, most significant bit first
+type ccittWriter struct {
+	buf  bytes.Buffer
+	acc  byte
+	nBit uint
+}
+
+func (cw *ccittWriter) put(code string) {
+	for j := 0; j < len(code); j++ {
+		cw.acc <<= 1
+		if code[j] == '1' {
+			cw.acc |= 1
+		}
+		cw.nBit++
+		if cw.nBit == 8 {
+			cw.buf.WriteByte(cw.acc)
+			cw.acc, cw.nBit = 0, 0
+		}
+	}
+}
+
+func (cw *ccittWriter) bytes() []byte {
+	if cw.nBit > 0 {
+		cw.buf.WriteByte(cw.acc << (8 - cw.nBit))
+		cw.acc, cw.nBit = 0, 0
+	}
+	return cw.buf.Bytes()
+}
+
+// run writes the code words for a run of n pixels of the specified color
+func (cw *ccittWriter) run(n int, black bool) {
+	term, makeup := &ccittWhiteTerm, &ccittWhiteMakeup
+	if black {
+		term, makeup = &ccittBlackTerm, &ccittBlackMakeup
+	}
+	for n >= 2560 {
+		cw.put(ccittExtMakeup[12])
+		n -= 2560
+	}
+	if n >= 1792 {
+		cw.put(ccittExtMakeup[n/64-28])
+		n %= 64
+	} else if n >= 64 {
+		cw.put(makeup[n/64-1])
+		n %= 64
+	}
+	cw.put(term[n])
+}
+
+// ccittChange returns the position of the first changing element of line at
+// or after start, that is, the first pixel whose color differs from the
+// pixel to its left. The imaginary pixel to the left of the line is white.
+// The line width is returned if there is no such element.
+func ccittChange(line []bool, start int) int {
+	if start < 0 {
+		start = 0
+	}
+	if start >= len(line) {
+		return len(line)
+	}
+	prev := false
+	if start > 0 {
+		prev = line[start-1]
+	}
+	for j := start; j < len(line); j++ {
+		if line[j] != prev {
+			return j
+		}
+	}
+	return len(line)
+}
+
+// ccittG4Encode compresses the specified bilevel image with the CCITT Group
+// 4 (ITU-T T.6) two-dimensional scheme. pix holds w * h pixels, row by row,
+// true for black.
+func ccittG4Encode(pix []bool, w, h int) []byte {
+	var cw ccittWriter
+	ref := make([]bool, w)
+	for y := 0; y < h; y++ {
+		cur := pix[y*w : (y+1)*w]
+		a0 := -1
+		color := false
+		for a0 < w {
+			a1 := ccittChange(cur, a0+1)
+			a2 := ccittChange(cur, a1+1)
+			b1 := ccittChange(ref, a0+1)
+			for b1 < w && ref[b1] == color {
+				b1 = ccittChange(ref, b1+1)
+			}
+			b2 := ccittChange(ref, b1+1)
+			switch {
+			case b2 < a1:
+				cw.put(ccittPass)
+				a0 = b2
+			case a1-b1 >= -3 && a1-b1 <= 3:
+				cw.put(ccittVertical[a1-b1+3])
+				a0 = a1
+				color = !color
+			default:
+				start := a0
+				if start < 0 {
+					start = 0
+				}
+				cw.put(ccittHorizontal)
+				cw.run(a1-start, color)
+				cw.run(a2-a1, !color)
+				a0 = a2
+			}
+		}
+		ref = cur
+	}
+	// End of facsimile block
+	cw.put(ccittEOL)
+	cw.put(ccittEOL)
+	return cw.bytes()
+}
+
+// bilevelImage replaces the image data of info with a CCITT Group 4 encoded
+// bilevel rendition of img. Transparent regions are rendered white and
+// pixels with a gray level below threshold (128 if zero) become black.
+func bilevelImage(info *ImageInfoType, img image.Image, threshold uint8) {
+	if threshold == 0 {
+		threshold = 128
+	}
+	b := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(gray, gray.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(gray, gray.Bounds(), img, b.Min, draw.Over)
+	w, h := gray.Rect.Dx(), gray.Rect.Dy()
+	pix := make([]bool, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pix[y*w+x] = gray.Pix[y*gray.Stride+x] < threshold
+		}
+	}
+	info.data = ccittG4Encode(pix, w, h)
+	info.w = float64(w)
+	info.h = float64(h)
+	info.cs = "DeviceGray"
+	info.bpc = 1
+	info.f = "CCITTFaxDecode"
+	info.dp = sprintf("/K -1 /Columns %d /Rows %d", w, h)
+	info.smask = nil
+	info.pal = nil
+	info.trns = nil
+	info.icc = nil
+	info.dec = ""
+}
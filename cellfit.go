@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"strings"
+)
+
+// Values for the fitStr argument of CellFit()
+const (
+	CellFitScale    = "scale"
+	CellFitShrink   = "shrink"
+	CellFitEllipsis = "ellipsis"
+)
+
+// CellFit prints a cell as CellFormat() does, fitting text that is wider than
+// the cell, less its cell margins, into it. This suits fixed boxes, such as the
+// fields of a form, that hold text of variable length. Text that fits is
+// printed as is.
+//
+// fitStr specifies how text that does not fit is fitted: CellFitScale
+// ("scale") condenses it horizontally, keeping the font size, and
+// CellFitShrink ("shrink") reduces the font size, which makes the text
+// smaller in both directions. In both cases the text fills the width of the
+// cell, so that its horizontal alignment has no effect. CellFitEllipsis
+// ("ellipsis") truncates it instead, printing as many of its first characters
+// as fit followed by the ellipsis set with SetEllipsis(), aligned as alignStr
+// specifies; this suits the fixed columns of tables. The current font is
+// not changed. Text with tab characters is not fitted if tab stops are set
+// with SetTabStops().
+//
+// See CellFormat() for the other arguments.
+func (f *Fpdf) CellFit(w, h float64, txtStr, borderStr string, ln int,
+	alignStr string, fill bool, link int, linkStr string, fitStr string) {
+	if f.err != nil {
+		return
+	}
+	fitStr = strings.ToLower(fitStr)
+	if fitStr != CellFitScale && fitStr != CellFitShrink && fitStr != CellFitEllipsis {
+		f.SetErrorf("invalid cell fit mode: %s", fitStr)
+		return
+	}
+	if w == 0 {
+		w = f.w - f.rMargin - f.x
+	}
+	textW := f.GetSpacedStringWidth(txtStr)
+	if f.currentFont.Name == "" || textW <= w-2*f.cMargin || w <= 2*f.cMargin ||
+		len(f.tabStops) > 0 && strings.Contains(txtStr, "\t") {
+		f.CellFormat(w, h, txtStr, borderStr, ln, alignStr, fill, link, linkStr)
+		return
+	}
+	if fitStr == CellFitEllipsis {
+		f.CellFormat(w, h, f.truncate(txtStr, w-2*f.cMargin), borderStr, ln, alignStr, fill, link, linkStr)
+		return
+	}
+	scale := (w - 2*f.cMargin) / textW
+	if fitStr == CellFitShrink {
+		st := f.spanStateGet()
+		f.SetFont(st.familyStr, st.styleStr, st.sizePt*scale)
+		f.CellFormat(w, h, txtStr, borderStr, ln, alignStr, fill, link, linkStr)
+		f.spanStatePut(st)
+		return
+	}
+	// The border and background of the cell, which breaks the page if needed,
+	// are not scaled
+	f.CellFormat(w, h, "", borderStr, 0, "", fill, 0, "")
+	if f.err != nil {
+		return
+	}
+	x, y := f.x-w, f.y
+	vAlignStr := ""
+	for _, r := range "TMBA" {
+		if strings.ContainsRune(strings.ToUpper(alignStr), r) {
+			vAlignStr = string(r)
+		}
+	}
+	links := len(f.pageLinks[f.page])
+	f.x = x
+	f.TransformBegin()
+	f.TransformScaleX(scale*100, x+f.cMargin, y)
+	f.CellFormat(w, h, txtStr, "", 0, "L"+vAlignStr, false, link, linkStr)
+	f.TransformEnd()
+	if list := f.pageLinks[f.page]; len(list) > links {
+		// The area of the link is that of the scaled text
+		list[links].wd *= scale
+	}
+	f.x = x
+	if ln > 0 {
+		f.y += h
+		if ln == 1 {
+			f.x = f.lMargin
+		}
+	} else {
+		f.x += w
+	}
+}
@@ -0,0 +1,560 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CFF DICT operators used when rebuilding a font. Two-byte operators are
+// represented as 1200 plus their second byte.
+const (
+	cffOpFontBBox       = 5
+	cffOpCharset        = 15
+	cffOpCharStrings    = 17
+	cffOpPrivate        = 18
+	cffOpSubrs          = 19
+	cffOpCharstringType = 1206
+	cffOpFontMatrix     = 1207
+	cffOpROS            = 1230
+	cffOpCIDCount       = 1234
+	cffOpFDArray        = 1236
+	cffOpFDSelect       = 1237
+)
+
+// Number of standard strings, which precede the strings of the String INDEX
+// in the numbering of string identifiers
+const cffStdStrings = 391
+
+// cffDictEntry is an operator of a CFF DICT with its operands, both decoded
+// and in their original encoding.
+type cffDictEntry struct {
+	op   int
+	args []float64
+	raw  []byte
+}
+
+type cffDict []cffDictEntry
+
+func (d cffDict) get(op int) *cffDictEntry {
+	for j := range d {
+		if d[j].op == op {
+			return &d[j]
+		}
+	}
+	return nil
+}
+
+// cffFontDict is a font DICT of a CID-keyed font, or the equivalent of the
+// single one of a name-keyed font, with its private DICT and local subroutines.
+type cffFontDict struct {
+	font    cffDict
+	private cffDict
+	subrs   [][]byte
+}
+
+// cffFont is the content of a CFF table needed to rebuild it as a CID-keyed
+// subset.
+type cffFont struct {
+	name        []byte
+	top         cffDict
+	globalSubrs [][]byte
+	charStrings [][]byte
+	fds         []cffFontDict
+	fdSelect    []byte // Font DICT of each glyph; nil for a name-keyed font
+}
+
+// parseCFF parses the CFF table in data.
+func parseCFF(data []byte) (c *cffFont, err error) {
+	if len(data) < 4 || data[0] != 1 {
+		return nil, fmt.Errorf("unsupported CFF version")
+	}
+	c = new(cffFont)
+	pos := int(data[2])
+	var list [][]byte
+	if list, pos, err = cffIndex(data, pos); err != nil {
+		return
+	}
+	if len(list) != 1 {
+		return nil, fmt.Errorf("CFF table must contain exactly one font")
+	}
+	c.name = list[0]
+	if list, pos, err = cffIndex(data, pos); err != nil {
+		return
+	}
+	if len(list) != 1 {
+		return nil, fmt.Errorf("CFF table must contain exactly one font")
+	}
+	if c.top, err = cffParseDict(list[0]); err != nil {
+		return
+	}
+	// The String INDEX is not needed as the rebuilt font supplies its own
+	if _, pos, err = cffIndex(data, pos); err != nil {
+		return
+	}
+	if c.globalSubrs, _, err = cffIndex(data, pos); err != nil {
+		return
+	}
+	if e := c.top.get(cffOpCharstringType); e != nil && len(e.args) == 1 && e.args[0] != 2 {
+		return nil, fmt.Errorf("unsupported CFF charstring type %g", e.args[0])
+	}
+	e := c.top.get(cffOpCharStrings)
+	if e == nil || len(e.args) != 1 {
+		return nil, fmt.Errorf("CFF font has no charstrings")
+	}
+	if c.charStrings, _, err = cffIndex(data, int(e.args[0])); err != nil {
+		return
+	}
+	if c.top.get(cffOpROS) == nil {
+		var fd cffFontDict
+		if fd, err = cffPrivate(data, c.top); err == nil {
+			c.fds = []cffFontDict{fd}
+		}
+		return
+	}
+	// CID-keyed font
+	e = c.top.get(cffOpFDArray)
+	if e == nil || len(e.args) != 1 {
+		return nil, fmt.Errorf("CID-keyed CFF font has no font DICTs")
+	}
+	if list, _, err = cffIndex(data, int(e.args[0])); err != nil {
+		return
+	}
+	for _, buf := range list {
+		var fd cffFontDict
+		var font cffDict
+		if font, err = cffParseDict(buf); err != nil {
+			return
+		}
+		if fd, err = cffPrivate(data, font); err != nil {
+			return
+		}
+		fd.font = font
+		c.fds = append(c.fds, fd)
+	}
+	e = c.top.get(cffOpFDSelect)
+	if e == nil || len(e.args) != 1 {
+		return nil, fmt.Errorf("CID-keyed CFF font has no FDSelect")
+	}
+	c.fdSelect, err = cffFDSelect(data, int(e.args[0]), len(c.charStrings), len(c.fds))
+	return
+}
+
+// cffPrivate reads the private DICT, and the local subroutines if any, to
+// which the Private operator of dict refers.
+func cffPrivate(data []byte, dict cffDict) (fd cffFontDict, err error) {
+	e := dict.get(cffOpPrivate)
+	if e == nil || len(e.args) != 2 {
+		return
+	}
+	size, offset := int(e.args[0]), int(e.args[1])
+	if size < 0 || offset < 0 || offset+size > len(data) {
+		return fd, fmt.Errorf("invalid CFF private DICT")
+	}
+	if fd.private, err = cffParseDict(data[offset : offset+size]); err != nil {
+		return
+	}
+	if e = fd.private.get(cffOpSubrs); e != nil && len(e.args) == 1 {
+		fd.subrs, _, err = cffIndex(data, offset+int(e.args[0]))
+	}
+	return
+}
+
+// cffFDSelect returns the index of the font DICT of each of the n glyphs.
+func cffFDSelect(data []byte, pos, n, fdCount int) (sel []byte, err error) {
+	bad := fmt.Errorf("invalid CFF FDSelect")
+	if pos >= len(data) {
+		return nil, bad
+	}
+	sel = make([]byte, n)
+	switch data[pos] {
+	case 0:
+		if pos+1+n > len(data) {
+			return nil, bad
+		}
+		copy(sel, data[pos+1:])
+	case 3:
+		if pos+3 > len(data) {
+			return nil, bad
+		}
+		count := int(binary.BigEndian.Uint16(data[pos+1:]))
+		p := pos + 3
+		if p+count*3+2 > len(data) {
+			return nil, bad
+		}
+		for j := 0; j < count; j++ {
+			first := int(binary.BigEndian.Uint16(data[p:]))
+			fd := data[p+2]
+			next := int(binary.BigEndian.Uint16(data[p+3:]))
+			for g := first; g < next && g < n; g++ {
+				sel[g] = fd
+			}
+			p += 3
+		}
+	default:
+		return nil, fmt.Errorf("unsupported CFF FDSelect format %d", data[pos])
+	}
+	for _, fd := range sel {
+		if int(fd) >= fdCount {
+			return nil, bad
+		}
+	}
+	return
+}
+
+// cffIndex reads the INDEX at pos and returns its objects and the position
+// that follows it.
+func cffIndex(data []byte, pos int) (list [][]byte, next int, err error) {
+	bad := fmt.Errorf("invalid CFF INDEX")
+	if pos < 0 || pos+2 > len(data) {
+		return nil, 0, bad
+	}
+	count := int(binary.BigEndian.Uint16(data[pos:]))
+	if count == 0 {
+		return nil, pos + 2, nil
+	}
+	if pos+3 > len(data) {
+		return nil, 0, bad
+	}
+	offSize := int(data[pos+2])
+	if offSize < 1 || offSize > 4 || pos+3+(count+1)*offSize > len(data) {
+		return nil, 0, bad
+	}
+	offset := func(j int) int {
+		var v int
+		for _, b := range data[pos+3+j*offSize : pos+3+(j+1)*offSize] {
+			v = v<<8 | int(b)
+		}
+		return v
+	}
+	// Offsets are relative to the byte that precedes the object data
+	base := pos + 3 + (count+1)*offSize - 1
+	list = make([][]byte, count)
+	for j := 0; j < count; j++ {
+		start, end := base+offset(j), base+offset(j+1)
+		if start > end || start <= base || end > len(data) {
+			return nil, 0, bad
+		}
+		list[j] = data[start:end]
+	}
+	return list, base + offset(count), nil
+}
+
+// cffParseDict decodes the operators and operands of a DICT.
+func cffParseDict(buf []byte) (dict cffDict, err error) {
+	bad := fmt.Errorf("invalid CFF DICT")
+	var args []float64
+	start := 0
+	for pos := 0; pos < len(buf); {
+		b0 := buf[pos]
+		switch {
+		case b0 <= 21:
+			op := int(b0)
+			pos++
+			if b0 == 12 {
+				if pos >= len(buf) {
+					return nil, bad
+				}
+				op = 1200 + int(buf[pos])
+				pos++
+			}
+			dict = append(dict, cffDictEntry{op: op, args: args, raw: buf[start:pos]})
+			args = nil
+			start = pos
+		case b0 == 28:
+			if pos+3 > len(buf) {
+				return nil, bad
+			}
+			args = append(args, float64(int16(binary.BigEndian.Uint16(buf[pos+1:]))))
+			pos += 3
+		case b0 == 29:
+			if pos+5 > len(buf) {
+				return nil, bad
+			}
+			args = append(args, float64(int32(binary.BigEndian.Uint32(buf[pos+1:]))))
+			pos += 5
+		case b0 == 30:
+			var v float64
+			if v, pos, err = cffReal(buf, pos+1); err != nil {
+				return
+			}
+			args = append(args, v)
+		case b0 >= 32 && b0 <= 246:
+			args = append(args, float64(int(b0)-139))
+			pos++
+		case b0 >= 247 && b0 <= 254:
+			if pos+2 > len(buf) {
+				return nil, bad
+			}
+			v := (int(b0)-247)*256 + int(buf[pos+1]) + 108
+			if b0 >= 251 {
+				v = -(int(b0)-251)*256 - int(buf[pos+1]) - 108
+			}
+			args = append(args, float64(v))
+			pos += 2
+		default:
+			return nil, bad
+		}
+	}
+	return
+}
+
+// cffReal decodes the nibbles of a real number operand starting at pos.
+func cffReal(buf []byte, pos int) (v float64, next int, err error) {
+	var s []byte
+	for ; pos < len(buf); pos++ {
+		for _, nib := range []byte{buf[pos] >> 4, buf[pos] & 15} {
+			switch {
+			case nib <= 9:
+				s = append(s, '0'+nib)
+			case nib == 0xa:
+				s = append(s, '.')
+			case nib == 0xb:
+				s = append(s, 'E')
+			case nib == 0xc:
+				s = append(s, 'E', '-')
+			case nib == 0xe:
+				s = append(s, '-')
+			case nib == 0xf:
+				_, err = fmt.Sscan(string(s), &v)
+				if len(s) == 0 {
+					err = nil
+				}
+				return v, pos + 1, err
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid CFF real number")
+}
+
+// cffInt encodes v as a five-byte integer operand, so that the size of a
+// DICT does not depend on the offsets it holds.
+func cffInt(v int) []byte {
+	return []byte{29, byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
+
+func cffOp(op int) []byte {
+	if op >= 1200 {
+		return []byte{12, byte(op - 1200)}
+	}
+	return []byte{byte(op)}
+}
+
+// cffWriteIndex appends an INDEX with the specified objects to buf.
+func cffWriteIndex(buf []byte, list [][]byte) []byte {
+	buf = append(buf, byte(len(list)>>8), byte(len(list)))
+	if len(list) == 0 {
+		return buf
+	}
+	buf = append(buf, 4)
+	offset := 1
+	buf = append(buf, byte(offset>>24), byte(offset>>16), byte(offset>>8), byte(offset))
+	for _, obj := range list {
+		offset += len(obj)
+		buf = append(buf, byte(offset>>24), byte(offset>>16), byte(offset>>8), byte(offset))
+	}
+	for _, obj := range list {
+		buf = append(buf, obj...)
+	}
+	return buf
+}
+
+// subset returns a CID-keyed CFF font with the specified glyphs of c, glyph
+// j of the result being glyph glyphs[j] of c with the CID cids[j]. The first
+// glyph must be the .notdef glyph with CID 0. Subroutines are retained in
+// full.
+func (c *cffFont) subset(glyphs, cids []int) []byte {
+	// Font DICTs used by the subset, in their original order
+	fdMap := make([]int, len(c.fds))
+	for j := range fdMap {
+		fdMap[j] = -1
+	}
+	fdOf := func(gid int) int {
+		if c.fdSelect == nil {
+			return 0
+		}
+		return int(c.fdSelect[gid])
+	}
+	var fdList []int
+	for _, gid := range glyphs {
+		if fd := fdOf(gid); fdMap[fd] < 0 {
+			fdMap[fd] = 0
+		}
+	}
+	for fd, used := range fdMap {
+		if used == 0 {
+			fdMap[fd] = len(fdList)
+			fdList = append(fdList, fd)
+		}
+	}
+	maxCID := 0
+	charStrings := make([][]byte, len(glyphs))
+	for j, gid := range glyphs {
+		charStrings[j] = c.charStrings[gid]
+		if cids[j] > maxCID {
+			maxCID = cids[j]
+		}
+	}
+	// Top DICT, with placeholders for offsets that are filled in once the
+	// layout is known
+	top := func(charset, fdSelect, charStringsPos, fdArray int) []byte {
+		var d []byte
+		d = append(d, cffInt(cffStdStrings)...)
+		d = append(d, cffInt(cffStdStrings+1)...)
+		d = append(d, cffInt(0)...)
+		d = append(d, cffOp(cffOpROS)...)
+		for _, op := range []int{cffOpFontMatrix, cffOpFontBBox} {
+			if e := c.top.get(op); e != nil {
+				d = append(d, e.raw...)
+			}
+		}
+		d = append(d, cffInt(maxCID+1)...)
+		d = append(d, cffOp(cffOpCIDCount)...)
+		d = append(d, cffInt(charset)...)
+		d = append(d, cffOp(cffOpCharset)...)
+		d = append(d, cffInt(fdSelect)...)
+		d = append(d, cffOp(cffOpFDSelect)...)
+		d = append(d, cffInt(charStringsPos)...)
+		d = append(d, cffOp(cffOpCharStrings)...)
+		d = append(d, cffInt(fdArray)...)
+		d = append(d, cffOp(cffOpFDArray)...)
+		return d
+	}
+	// Private DICTs with their local subroutines following each
+	privates := make([][]byte, len(fdList))
+	for j, fd := range fdList {
+		src := c.fds[fd]
+		var d []byte
+		for _, e := range src.private {
+			if e.op != cffOpSubrs {
+				d = append(d, e.raw...)
+			}
+		}
+		if len(src.subrs) > 0 {
+			// The subroutines are placed right after the DICT
+			d = append(d, cffInt(len(d)+5+1)...)
+			d = append(d, cffOp(cffOpSubrs)...)
+		}
+		privates[j] = d
+	}
+	fontDicts := func(privatePos int) (list [][]byte) {
+		for j, fd := range fdList {
+			var d []byte
+			if e := c.fds[fd].font.get(cffOpFontMatrix); e != nil {
+				d = append(d, e.raw...)
+			}
+			d = append(d, cffInt(len(privates[j]))...)
+			d = append(d, cffInt(privatePos)...)
+			d = append(d, cffOp(cffOpPrivate)...)
+			list = append(list, d)
+			privatePos += len(privates[j])
+			if subrs := c.fds[fd].subrs; len(subrs) > 0 {
+				privatePos += len(cffWriteIndex(nil, subrs))
+			}
+		}
+		return
+	}
+	var head []byte
+	head = append(head, 1, 0, 4, 4)
+	head = cffWriteIndex(head, [][]byte{c.name})
+	topSize := len(cffWriteIndex(nil, [][]byte{top(0, 0, 0, 0)}))
+	var rest []byte
+	rest = cffWriteIndex(rest, [][]byte{[]byte("Adobe"), []byte("Identity")})
+	rest = cffWriteIndex(rest, c.globalSubrs)
+	base := len(head) + topSize
+	charsetPos := base + len(rest)
+	// Charset format 0 lists the CID of each glyph but .notdef
+	rest = append(rest, 0)
+	for _, cid := range cids[1:] {
+		rest = append(rest, byte(cid>>8), byte(cid))
+	}
+	fdSelectPos := base + len(rest)
+	rest = append(rest, 0)
+	for _, gid := range glyphs {
+		rest = append(rest, byte(fdMap[fdOf(gid)]))
+	}
+	charStringsPos := base + len(rest)
+	rest = cffWriteIndex(rest, charStrings)
+	fdArrayPos := base + len(rest)
+	fdArraySize := len(cffWriteIndex(nil, fontDicts(0)))
+	rest = cffWriteIndex(rest, fontDicts(fdArrayPos+fdArraySize))
+	for j, fd := range fdList {
+		rest = append(rest, privates[j]...)
+		if subrs := c.fds[fd].subrs; len(subrs) > 0 {
+			rest = cffWriteIndex(rest, subrs)
+		}
+	}
+	out := cffWriteIndex(head, [][]byte{top(charsetPos, fdSelectPos, charStringsPos, fdArrayPos)})
+	return append(out, rest...)
+}
+
+// generateCutCFF returns the CFF table of an OpenType font with PostScript
+// outlines rebuilt as a CID-keyed font that contains only the glyphs of
+// usedRunes. Each glyph has the CID of the rune that selects it, so that text
+// is encoded as it is for TrueType outlines: the rune itself in the Basic
+// Multilingual Plane and the CID assigned to it by cids outside of it. A glyph
+// mapped to several runes is included once for each of them.
+func (utf *utf8FontFile) generateCutCFF(usedRunes, cids map[int]int) ([]byte, error) {
+	utf.fileReader.readerPosition = 0
+	utf.skip(4)
+	utf.generateTableDescriptions()
+	if utf.fileReader.err != nil {
+		return nil, utf.fileReader.err
+	}
+	if utf.generateCMAP() == nil {
+		return nil, fmt.Errorf("font does not have cmap for Unicode")
+	}
+	c, err := parseCFF(utf.getTableData("CFF "))
+	if err != nil {
+		return nil, err
+	}
+	runes := make([]int, 0, len(usedRunes))
+	for _, r := range usedRunes {
+		runes = append(runes, r)
+	}
+	sort.Ints(runes)
+	glyphs, glyphCIDs := []int{0}, []int{0}
+	utf.CodeSymbolDictionary = make(map[int]int)
+	utf.LastRune = 0
+	for _, r := range runes {
+		gid, ok := utf.charSymbolDictionary[r]
+		if r <= 0 || !ok || gid >= len(c.charStrings) {
+			continue
+		}
+		cid := r
+		if r > math.MaxUint16 {
+			if cid, ok = cids[r]; !ok {
+				continue
+			}
+		}
+		utf.CodeSymbolDictionary[r] = len(glyphs)
+		glyphs = append(glyphs, gid)
+		glyphCIDs = append(glyphCIDs, cid)
+		utf.LastRune = max(utf.LastRune, r)
+	}
+	return c.subset(glyphs, glyphCIDs), nil
+}
+
+// isCFF reports whether the font has PostScript (CFF) rather than TrueType
+// outlines.
+func (utf *utf8FontFile) isCFF() bool {
+	_, ok := utf.tableDescriptions["CFF "]
+	return ok
+}
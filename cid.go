@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"sort"
+	"unicode/utf16"
+)
+
+// Text printed with a UTF-8 font is encoded with two-byte codes through the
+// Identity-H encoding, the code of a character being its CID. The CID of a
+// character of the Basic Multilingual Plane is its code point. Characters
+// outside of it, such as emoji and rare CJK ideographs, are assigned CIDs in
+// the range of the UTF-16 surrogates, which are not characters, in the order
+// in which the document uses them.
+const (
+	cidAstralFirst = 0xD800
+	cidAstralLast  = 0xDFFF
+)
+
+// utf8toCID returns txtStr encoded with the CIDs of the UTF-8 font, and
+// records its characters as used by the font.
+func (f *Fpdf) utf8toCID(font fontDefType, txtStr string) string {
+	buf := make([]byte, 0, 2*len(txtStr))
+	for _, r := range txtStr {
+		if ignorable(r) && !hasGlyph(font, r) {
+			continue
+		}
+		font.usedRunes[int(r)] = int(r)
+		cid := int(r)
+		if cid > 0xFFFF {
+			var ok bool
+			cid, ok = font.cids[int(r)]
+			if !ok {
+				cid = cidAstralFirst + len(font.cids)
+				if cid > cidAstralLast {
+					f.SetErrorf("font %s uses more than %d characters outside the Basic Multilingual Plane",
+						font.Name, cidAstralLast-cidAstralFirst+1)
+					cid = 0
+				} else {
+					font.cids[int(r)] = cid
+				}
+			}
+		}
+		buf = append(buf, byte(cid>>8), byte(cid))
+	}
+	return string(buf)
+}
+
+// cid returns the CID of the character r in the UTF-8 font, or -1 if the
+// document has not used r.
+func (font *fontDefType) cid(r int) int {
+	if r <= 0xFFFF {
+		return r
+	}
+	if cid, ok := font.cids[r]; ok {
+		return cid
+	}
+	return -1
+}
+
+// cidWidths returns the character widths of the UTF-8 font by CID.
+func (font *fontDefType) cidWidths() []int {
+	cw := font.Cw
+	if len(cw) > 0x10000 {
+		cw = cw[:0x10000]
+	}
+	if len(font.cids) == 0 {
+		return cw
+	}
+	cw = append([]int(nil), cw...)
+	for r, cid := range font.cids {
+		if r < len(font.Cw) {
+			cw[cid] = font.Cw[r]
+		}
+	}
+	return cw
+}
+
+// Glyphs that no character maps to, such as ligatures, are assigned codes
+// of the Private Use Area of the Basic Multilingual Plane that the font does
+// not map, so that they are measured, encoded and embedded like characters.
+const (
+	glyphCodeFirst = 0xE000
+	glyphCodeLast  = 0xF8FF
+)
+
+// freeCode returns a code of the Private Use Area that the UTF-8 font does
+// not use, or -1 if there is none. The code is taken once it is given a
+// width.
+func (font *fontDefType) freeCode() rune {
+	for code := glyphCodeFirst; code <= glyphCodeLast; code++ {
+		if _, ok := font.utf8File.charSymbolDictionary[code]; !ok && font.Cw[code] == 0 {
+			return rune(code)
+		}
+	}
+	return -1
+}
+
+// glyphCode returns the code of a glyph of the UTF-8 font that represents
+// the characters chars, assigning a free code to the glyph if it has none.
+// -1 is returned if there is no free code.
+func (font *fontDefType) glyphCode(glyph int, chars string) rune {
+	utf := font.utf8File
+	if code, ok := utf.assignedCodes[glyph]; ok {
+		return code
+	}
+	code := font.freeCode()
+	if code < 0 {
+		return code
+	}
+	if utf.assignedCodes == nil {
+		utf.assignedCodes = make(map[int]rune)
+		utf.assignedSymbols = make(map[int]int)
+		utf.assignedChars = make(map[int]string)
+	}
+	utf.assignedCodes[glyph] = code
+	utf.assignedSymbols[int(code)] = glyph
+	utf.assignedChars[int(code)] = chars
+	font.Cw[code] = utf.symbolWidth(glyph)
+	return code
+}
+
+// toUnicodeCMap returns the CMap that maps the CIDs of the UTF-8 font to the
+// characters they represent, so that text can be extracted from the
+// document.
+func (font *fontDefType) toUnicodeCMap() string {
+	// The characters of the CIDs that do not represent their own code point
+	chars := make(map[int]string, len(font.cids))
+	for r, cid := range font.cids {
+		chars[cid] = string(rune(r))
+	}
+	for code, str := range font.utf8File.assignedChars {
+		if _, ok := font.usedRunes[code]; ok {
+			chars[code] = str
+		}
+	}
+	if len(chars) == 0 {
+		return toUnicode
+	}
+	cids := make([]int, 0, len(chars))
+	for cid := range chars {
+		cids = append(cids, cid)
+	}
+	sort.Ints(cids)
+	// The other CIDs, apart from the surrogates, represent their code point
+	excluded := [][2]int{{cidAstralFirst, cidAstralLast}, {0x10000, 0x10000}}
+	for _, cid := range cids {
+		if cid < cidAstralFirst || cid > cidAstralLast {
+			excluded = append(excluded, [2]int{cid, cid})
+		}
+	}
+	sort.Slice(excluded, func(i, j int) bool { return excluded[i][0] < excluded[j][0] })
+	// Codes of glyphs that represent no characters are left unmapped
+	mapped := cids[:0]
+	for _, cid := range cids {
+		if chars[cid] != "" {
+			mapped = append(mapped, cid)
+		}
+	}
+	cids = mapped
+	var ranges [][2]int
+	start := 0
+	for _, e := range excluded {
+		if e[0] > start {
+			ranges = append(ranges, [2]int{start, e[0] - 1})
+		}
+		start = e[1] + 1
+	}
+	var s fmtBuffer
+	s.printf("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n/CIDSystemInfo\n" +
+		"<</Registry (Adobe)\n/Ordering (UCS)\n/Supplement 0\n>> def\n/CMapName /Adobe-Identity-UCS def\n" +
+		"/CMapType 2 def\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// A bfrange or bfchar block has at most 100 entries
+	for len(ranges) > 0 {
+		n := minInt(len(ranges), 100)
+		s.printf("%d beginbfrange\n", n)
+		for _, rg := range ranges[:n] {
+			s.printf("<%04X> <%04X> <%04X>\n", rg[0], rg[1], rg[0])
+		}
+		s.printf("endbfrange\n")
+		ranges = ranges[n:]
+	}
+	for len(cids) > 0 {
+		n := minInt(len(cids), 100)
+		s.printf("%d beginbfchar\n", n)
+		for _, cid := range cids[:n] {
+			// The characters are written in UTF-16, with surrogate pairs
+			s.printf("<%04X> <", cid)
+			for _, unit := range utf16.Encode([]rune(chars[cid])) {
+				s.printf("%04X", unit)
+			}
+			s.printf(">\n")
+		}
+		s.printf("endbfchar\n")
+		cids = cids[n:]
+	}
+	s.printf("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	return s.String()
+}
@@ -0,0 +1,374 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+)
+
+// colorGlyph is the color representation of a glyph of a color font.
+type colorGlyph struct {
+	layers []colorLayer // Layers of a COLR glyph, drawn in order
+	png    []byte       // PNG bitmap of a CBDT or sbix glyph
+	name   string       // Name of the image of the bitmap
+	// Position of the lower left corner and size of the bitmap, relative to
+	// the origin of the glyph, in ems
+	x, y, w, h float64
+}
+
+// colorLayer is a glyph drawn in a color as a layer of a COLR glyph.
+type colorLayer struct {
+	glyph int
+	color string // Nonstroking color operator, empty for the text color
+}
+
+// haveColorGlyphs reports whether any of fonts has color glyphs.
+func (f *Fpdf) haveColorGlyphs(fonts []fontDefType) bool {
+	for _, font := range fonts {
+		if len(f.fontColorGlyphs(font)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// colorGlyph returns the color glyph that font shows for the code r, or nil
+// if the glyph of r has no color.
+func (f *Fpdf) colorGlyph(font fontDefType, r rune) *colorGlyph {
+	glyphs := f.fontColorGlyphs(font)
+	if len(glyphs) == 0 {
+		return nil
+	}
+	utf := font.utf8File
+	symbol, ok := utf.charSymbolDictionary[int(r)]
+	if !ok {
+		symbol = utf.assignedSymbols[int(r)]
+	}
+	if symbol == 0 {
+		return nil
+	}
+	return glyphs[symbol]
+}
+
+// fontColorGlyphs returns the color glyphs of a UTF-8 font by glyph, reading
+// them on first use.
+func (f *Fpdf) fontColorGlyphs(font fontDefType) map[int]*colorGlyph {
+	ft := font.features
+	if ft == nil {
+		return nil
+	}
+	if ft.colorGlyphs == nil {
+		ft.colorGlyphs = make(map[int]*colorGlyph)
+		if err := loadColorGlyphs(font.utf8File, ft.colorGlyphs); err != nil {
+			f.SetError(err)
+		}
+	}
+	return ft.colorGlyphs
+}
+
+// loadColorGlyphs reads the color glyphs of a font into glyphs. A glyph that
+// several tables have takes its color from the first of the COLR, CBDT and
+// sbix tables.
+func loadColorGlyphs(utf *utf8FontFile, glyphs map[int]*colorGlyph) error {
+	colr, cpal := utf.getTableData("COLR"), utf.getTableData("CPAL")
+	cblc, cbdt := utf.getTableData("CBLC"), utf.getTableData("CBDT")
+	sbix := utf.getTableData("sbix")
+	if (colr == nil || cpal == nil) && (cblc == nil || cbdt == nil) && sbix == nil {
+		return nil
+	}
+	if utf.generateCMAP() == nil {
+		return fmt.Errorf("font does not have cmap for Unicode")
+	}
+	if colr != nil && cpal != nil {
+		if err := colrGlyphs(colr, cpal, glyphs); err != nil {
+			return err
+		}
+	}
+	if cblc != nil && cbdt != nil {
+		if err := cbdtGlyphs(cblc, cbdt, glyphs); err != nil {
+			return err
+		}
+	}
+	if maxp := utf.getTableData("maxp"); sbix != nil && len(maxp) >= 6 {
+		if err := sbixGlyphs(sbix, int(binary.BigEndian.Uint16(maxp[4:])), glyphs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// colrGlyphs reads the glyphs of a version 0 COLR table, in the colors of
+// the first palette of the CPAL table.
+func colrGlyphs(colr, cpal []byte, glyphs map[int]*colorGlyph) error {
+	p := sfntReader{data: cpal, pos: 2}
+	numEntries := p.u16()
+	p.u16()
+	p.u16()
+	records := p.u32()
+	first := p.u16()
+	colors := make([]string, numEntries)
+	transparent := make([]bool, numEntries)
+	for j := range colors {
+		p.pos = records + 4*(first+j)
+		bgra := p.bytes(4)
+		colors[j] = sprintf("%.3f %.3f %.3f rg", float64(bgra[2])/255, float64(bgra[1])/255, float64(bgra[0])/255)
+		transparent[j] = bgra[3] == 0
+	}
+	r := sfntReader{data: colr, pos: 2}
+	numBase := r.u16()
+	baseRecords, layerRecords := r.u32(), r.u32()
+	numLayers := r.u16()
+	for j := 0; j < numBase && r.err == nil; j++ {
+		r.pos = baseRecords + 6*j
+		glyph, firstLayer, count := r.u16(), r.u16(), r.u16()
+		g := new(colorGlyph)
+		for k := firstLayer; k < firstLayer+count && k < numLayers && r.err == nil; k++ {
+			r.pos = layerRecords + 4*k
+			layer := colorLayer{glyph: r.u16()}
+			switch index := r.u16(); {
+			case index == 0xFFFF:
+				// Text color
+			case index < numEntries && !transparent[index]:
+				layer.color = colors[index]
+			default:
+				continue
+			}
+			g.layers = append(g.layers, layer)
+		}
+		glyphs[glyph] = g
+	}
+	if r.err != nil || p.err != nil {
+		return fmt.Errorf("invalid COLR or CPAL table")
+	}
+	return nil
+}
+
+// cbdtGlyphs reads the PNG bitmaps of the strike with the most pixels per em
+// of the CBLC and CBDT tables.
+func cbdtGlyphs(cblc, cbdt []byte, glyphs map[int]*colorGlyph) error {
+	bad := fmt.Errorf("invalid CBLC or CBDT table")
+	r := sfntReader{data: cblc, pos: 4}
+	strike, ppem := -1, 0
+	for j, n := 0, r.u32(); j < n && r.err == nil; j++ {
+		r.pos = 8 + 48*j + 45
+		if p := r.u8(); p > ppem {
+			strike, ppem = j, p
+		}
+	}
+	if r.err != nil {
+		return bad
+	}
+	if strike < 0 {
+		return nil
+	}
+	r.pos = 8 + 48*strike + 44
+	ppemX, ppemY := float64(r.u8()), float64(ppem)
+	r.pos = 8 + 48*strike
+	array := r.u32()
+	r.u32()
+	count := r.u32()
+	for j := 0; j < count && r.err == nil; j++ {
+		r.pos = array + 8*j
+		firstGlyph, lastGlyph := r.u16(), r.u16()
+		r.pos = array + r.u32()
+		indexFormat, imageFormat, imageData := r.u16(), r.u16(), r.u32()
+		// Glyphs of the subtable with the offsets of their data from imageData
+		var ids, offsets []int
+		var metrics []byte
+		switch indexFormat {
+		case 1, 3:
+			for g := firstGlyph; g <= lastGlyph+1 && r.err == nil; g++ {
+				ids = append(ids, g)
+				if indexFormat == 1 {
+					offsets = append(offsets, r.u32())
+				} else {
+					offsets = append(offsets, r.u16())
+				}
+			}
+		case 2:
+			size := r.u32()
+			metrics = r.bytes(8)
+			for g := firstGlyph; g <= lastGlyph+1; g++ {
+				ids = append(ids, g)
+				offsets = append(offsets, (g-firstGlyph)*size)
+			}
+		case 4:
+			for k, n := 0, r.u32(); k <= n && r.err == nil; k++ {
+				ids = append(ids, r.u16())
+				offsets = append(offsets, r.u16())
+			}
+		case 5:
+			size := r.u32()
+			metrics = r.bytes(8)
+			for k, n := 0, r.u32(); k <= n && r.err == nil; k++ {
+				if k < n {
+					ids = append(ids, r.u16())
+				} else {
+					ids = append(ids, 0)
+				}
+				offsets = append(offsets, k*size)
+			}
+		default:
+			continue
+		}
+		for k := 0; k+1 < len(ids); k++ {
+			start, end := imageData+offsets[k], imageData+offsets[k+1]
+			if _, ok := glyphs[ids[k]]; ok || end <= start {
+				continue
+			}
+			if end > len(cbdt) {
+				return bad
+			}
+			data := sfntReader{data: cbdt[start:end]}
+			switch imageFormat {
+			case 17:
+				metrics = data.bytes(5)
+			case 18:
+				metrics = data.bytes(8)
+			case 19:
+			default:
+				continue
+			}
+			png := data.bytes(data.u32())
+			if data.err != nil || len(metrics) < 4 {
+				return bad
+			}
+			// Height, width, horizontal bearings of the big and small metrics
+			h, w := float64(metrics[0]), float64(metrics[1])
+			bx, by := float64(int8(metrics[2])), float64(int8(metrics[3]))
+			glyphs[ids[k]] = &colorGlyph{png: png, name: bitmapName(png),
+				x: bx / ppemX, y: (by - h) / ppemY, w: w / ppemX, h: h / ppemY}
+		}
+	}
+	if r.err != nil {
+		return bad
+	}
+	return nil
+}
+
+// sbixGlyphs reads the PNG bitmaps of the strike with the most pixels per em
+// of the sbix table, given the number of glyphs of the font.
+func sbixGlyphs(sbix []byte, numGlyphs int, glyphs map[int]*colorGlyph) error {
+	r := sfntReader{data: sbix, pos: 4}
+	strike, ppem := -1, 0
+	for j, n := 0, r.u32(); j < n && r.err == nil; j++ {
+		r.pos = 8 + 4*j
+		offset := r.u32()
+		r.pos = offset
+		if p := r.u16(); p > ppem {
+			strike, ppem = offset, p
+		}
+	}
+	for g := 0; g < numGlyphs && strike >= 0 && r.err == nil; g++ {
+		r.pos = strike + 4 + 4*g
+		start, end := strike+r.u32(), strike+r.u32()
+		if _, ok := glyphs[g]; ok || end-start < 8 {
+			continue
+		}
+		r.pos = start
+		x, y := float64(int16(r.u16())), float64(int16(r.u16()))
+		if string(r.bytes(4)) != "png " {
+			continue
+		}
+		png := r.bytes(end - start - 8)
+		if r.err != nil || len(png) < 24 {
+			break
+		}
+		// Width and height of the IHDR chunk
+		w, h := float64(binary.BigEndian.Uint32(png[16:])), float64(binary.BigEndian.Uint32(png[20:]))
+		p := float64(ppem)
+		glyphs[g] = &colorGlyph{png: png, name: bitmapName(png), x: x / p, y: y / p, w: w / p, h: h / p}
+	}
+	if r.err != nil {
+		return fmt.Errorf("invalid sbix table")
+	}
+	return nil
+}
+
+// bitmapName returns the name of the image of a glyph bitmap, which is
+// derived from its data so that the bitmaps of different fonts do not clash
+// in an image cache.
+func bitmapName(png []byte) string {
+	return fmt.Sprintf("glyph %x", sha1.Sum(png))
+}
+
+// colorGlyphs returns the operators that draw the color glyphs of txtStr,
+// printed with the current font at (x, y) on the baseline, or an empty
+// string if it has none. wordSpace is the space added to each space
+// character when text is justified.
+func (f *Fpdf) colorGlyphs(x, y float64, txtStr string, wordSpace float64) string {
+	if !f.isCurrentUTF8 {
+		return ""
+	}
+	fonts := f.fallbackFonts()
+	if !f.haveColorGlyphs(fonts) {
+		return ""
+	}
+	cw := f.charWidths()
+	codes := []rune(txtStr)
+	runes := make([]rune, len(codes))
+	for j, code := range codes {
+		runes[j] = code
+		if upper, ok := f.currentFont.features.syntheticCaps[code]; ok {
+			runes[j] = upper
+		}
+	}
+	idx := fallbackIndices(fonts, runes)
+	var s fmtBuffer
+	for j, r := range runes {
+		font, size := fonts[idx[j]], f.fontSize
+		if r != codes[j] {
+			size *= smallCapsScale
+		}
+		if g := f.colorGlyph(font, r); g != nil {
+			for _, layer := range g.layers {
+				segs, err := font.symbolOutline(layer.glyph)
+				if err != nil {
+					f.SetError(err)
+					return ""
+				}
+				color := layer.color
+				if color == "" {
+					color = f.color.text.str
+				}
+				s.printf("q %s ", color)
+				f.outlinePath(&s, segs, x, y, size/float64(font.utf8File.fontElementSize), 0)
+				s.printf("f Q ")
+			}
+			if g.png != nil {
+				info := f.RegisterImageOptionsReader(g.name, ImageOptions{ImageType: "png"}, bytes.NewReader(g.png))
+				if f.err != nil {
+					return ""
+				}
+				s.printf("q %.5f 0 0 %.5f %.5f %.5f cm /I%s Do Q ", g.w*size*f.k, g.h*size*f.k,
+					(x+g.x*size)*f.k, (f.h-y+g.y*size)*f.k, info.i)
+			}
+		}
+		x += f.runeAdvance(cw, codes[j])
+		if r == ' ' {
+			x += wordSpace
+		}
+	}
+	if s.Len() == 0 {
+		return ""
+	}
+	str := s.String()
+	return str[:len(str)-1]
+}
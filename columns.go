@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+)
+
+// columnsType is the state of the column layout of a document.
+type columnsType struct {
+	n                int     // Number of columns, 0 if there is no column layout
+	gutter, w        float64 // Space between the columns and width of the columns
+	lMargin, rMargin float64 // Margins of the area of the columns
+	col              int     // Current column
+	y0               float64 // Top of the columns on the current page
+	bottom           float64 // Bottom of the columns filled on the current page
+	balanceHt        float64 // Height of the balanced content left, as a single column from the top of the current one
+	trigger          float64 // Page break trigger while shortened for balancing, 0 otherwise
+}
+
+// SetColumns lays out the text and images that follow in n columns
+// separated by gutter, in the unit of measure specified in New(), between the
+// current left and right margins. The columns start at the current ordinate.
+// Write(), MultiCell(), CellFormat() and the other methods that flow with the
+// current position continue at the top of the next column instead of
+// breaking the page when the current column is full, and in the first column
+// of the next page, below its header, when the last column is. The margins of
+// the current column are those of the document while the layout is in
+// effect, except in the header and footer.
+//
+// A value of n of 1 or less ends the column layout, in which case the margins
+// are restored and the current position set at the left margin below the
+// longest column of the page. See BalancedColumns() to end the columns of
+// the last page at about the same height.
+func (f *Fpdf) SetColumns(n int, gutter float64) {
+	if f.err != nil {
+		return
+	}
+	c := &f.columns
+	if c.n > 1 {
+		// End the current layout
+		f.columnsUntrigger()
+		y := math.Max(c.bottom, f.y)
+		f.lMargin, f.rMargin = c.lMargin, c.rMargin
+		*c = columnsType{}
+		f.x, f.y = f.lMargin, y
+	}
+	if n <= 1 {
+		return
+	}
+	w := (f.w - f.lMargin - f.rMargin - float64(n-1)*gutter) / float64(n)
+	if w <= 0 {
+		f.SetErrorf("columns do not fit between the margins")
+		return
+	}
+	*c = columnsType{n: n, gutter: gutter, w: w, lMargin: f.lMargin, rMargin: f.rMargin, y0: f.y, bottom: f.y}
+	f.columnSet(0)
+}
+
+// GetColumns returns the number of columns and the current column, counted
+// from 0, of the column layout set with SetColumns(). The number of columns
+// is 0 if there is no column layout.
+func (f *Fpdf) GetColumns() (n, col int) {
+	return f.columns.n, f.columns.col
+}
+
+// BalancedColumns calls render to print content in the columns set with
+// SetColumns() and balances the columns of the last page, so that they end at
+// about the same height rather than the last column being the shortest. The
+// column layout ends afterwards, as if SetColumns() were called with a value
+// of n of 1.
+//
+// render is called twice. The first call measures the height of the content,
+// laid out in a single column, and its output is discarded. render must print
+// the same content on both calls and is meant for the flowing methods such
+// as Write(), MultiCell() and Ln(); content such as links and bookmarks that
+// is not printed on the page is added twice. Without a column layout, render
+// is called once.
+func (f *Fpdf) BalancedColumns(render func()) {
+	c := &f.columns
+	if f.err != nil {
+		return
+	}
+	if c.n <= 1 {
+		render()
+		return
+	}
+	c.balanceHt = f.columnsMeasure(render) + f.y - c.y0
+	if f.err != nil {
+		return
+	}
+	f.columnsBalance()
+	render()
+	f.SetColumns(1, 0)
+}
+
+// columnsMeasure returns the height of the content that render prints in a
+// single column of unlimited height, discarding its output.
+func (f *Fpdf) columnsMeasure(render func()) float64 {
+	c := &f.columns
+	buf := f.pages[f.page]
+	bufLen, linkCount := buf.Len(), len(f.pageLinks[f.page])
+	saved := *c
+	x, y, ws := f.x, f.y, f.ws
+	st := f.spanStateGet()
+	color, lineWidth := f.color, f.lineWidth
+	auto, accept := f.autoPageBreak, f.acceptPageBreak
+	// Page breaks are disabled
+	c.n = 0
+	f.autoPageBreak = false
+	f.acceptPageBreak = func() bool { return false }
+	render()
+	ht := f.y - y
+	*c = saved
+	f.x, f.y, f.ws = x, y, ws
+	f.autoPageBreak, f.acceptPageBreak = auto, accept
+	buf.Truncate(bufLen)
+	f.pageLinks[f.page] = f.pageLinks[f.page][:linkCount]
+	if f.err == nil {
+		f.spanStatePut(st)
+	}
+	f.color, f.lineWidth = color, lineWidth
+	return ht
+}
+
+// columnsBalance shortens the columns but the last of the current page if
+// the balanced content left to print fits on the page, so that the columns
+// end at about the same height. The last column extends to the bottom of the
+// page, as the columns may hold less than their height.
+func (f *Fpdf) columnsBalance() {
+	c := &f.columns
+	if c.balanceHt <= 0 || c.col > 0 || c.balanceHt > float64(c.n)*(f.pageBreakTrigger-c.y0) {
+		return
+	}
+	c.trigger = f.pageBreakTrigger
+	f.pageBreakTrigger = c.y0 + c.balanceHt/float64(c.n)
+}
+
+// columnsUntrigger restores the page break trigger shortened for balancing.
+func (f *Fpdf) columnsUntrigger() {
+	c := &f.columns
+	if c.trigger != 0 {
+		f.pageBreakTrigger = c.trigger
+		c.trigger = 0
+	}
+}
+
+// columnSet makes col the current column, keeping the current position
+// relative to the column.
+func (f *Fpdf) columnSet(col int) {
+	c := &f.columns
+	dx := float64(col-c.col) * (c.w + c.gutter)
+	c.col = col
+	f.lMargin = c.lMargin + float64(col)*(c.w+c.gutter)
+	f.rMargin = f.w - f.lMargin - c.w
+	f.x += dx
+	if col == c.n-1 {
+		f.columnsUntrigger()
+	}
+}
+
+// acceptBreak is called when a page break condition is met, and returns
+// true if the page is to be broken. In a column layout, it moves to the top
+// of the next column instead if the current column is not the last.
+func (f *Fpdf) acceptBreak() bool {
+	c := &f.columns
+	if c.n > 1 && c.col < c.n-1 && !f.inHeader && !f.inFooter {
+		if c.balanceHt > 0 {
+			c.balanceHt -= f.y - c.y0
+		}
+		c.bottom = math.Max(c.bottom, f.y)
+		f.columnSet(c.col + 1)
+		f.y = c.y0
+		return false
+	}
+	if !f.acceptPageBreak() {
+		return false
+	}
+	if c.n > 1 {
+		if c.balanceHt > 0 {
+			c.balanceHt -= f.y - c.y0
+		}
+		f.columnSet(0)
+	}
+	return true
+}
+
+// columnsPageEnd restores the margins of the area of the columns before the
+// footer of a page.
+func (f *Fpdf) columnsPageEnd() {
+	c := &f.columns
+	if c.n > 1 {
+		f.columnsUntrigger()
+		f.lMargin, f.rMargin = c.lMargin, c.rMargin
+	}
+}
+
+// columnsPageStart starts the columns of a new page below its header.
+func (f *Fpdf) columnsPageStart() {
+	c := &f.columns
+	if c.n > 1 {
+		c.col = 0
+		c.y0, c.bottom = f.y, f.y
+		f.columnSet(0)
+		f.x = f.lMargin
+		f.columnsBalance()
+	}
+}
@@ -1,7 +1,10 @@
 package barcode_test
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/boombuler/barcode/code128"
 	"github.com/boombuler/barcode/qr"
@@ -220,3 +223,90 @@ func TestBarcodeNonIntegerScalingFactors(t *testing.T) {
 	// Output:
 	// Successfully generated ../../pdf/contrib_barcode_BarcodeScaling.pdf
 }
+
+// ExampleBarcodeVector demonstrates barcodes drawn as vector content, sized
+// from the width of their modules.
+func ExampleBarcodeVector() {
+	pdf := createPdf()
+	pdf.SetFillColor(0, 0, 0)
+	x := 15.0
+	for _, key := range []string{
+		barcode.RegisterCode128(pdf, "gofpdf"),
+		barcode.RegisterEAN(pdf, "5901234123457"),
+		barcode.RegisterCode39(pdf, "GOFPDF", false, false),
+	} {
+		w, h := barcode.GetVectorBarcodeDimensions(pdf, key, 0.33, 15)
+		barcode.BarcodeVector(pdf, key, x, 15, w, h)
+		x += w + 10
+	}
+	x = 15
+	for _, key := range []string{
+		barcode.RegisterDataMatrix(pdf, "gofpdf"),
+		barcode.RegisterQR(pdf, "https://github.com/phpdave11/gofpdf", qr.M, qr.Auto),
+	} {
+		w, h := barcode.GetVectorBarcodeDimensions(pdf, key, 0.5, 0)
+		barcode.BarcodeVector(pdf, key, x, 45, w, h)
+		x += w + 10
+	}
+	fileStr := example.Filename("contrib_barcode_BarcodeVector")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated ../../pdf/contrib_barcode_BarcodeVector.pdf
+}
+
+// TestBarcodeVector verifies that the modules of vector barcodes are drawn as
+// rectangles merged across the rows and columns of dark modules.
+func TestBarcodeVector(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	key := barcode.RegisterCode128(pdf, "vector")
+	w, h := barcode.GetVectorBarcodeDimensions(pdf, key, 1, 20)
+	barcode.BarcodeVector(pdf, key, 10, 10, w, h)
+	// A solid square of QR code finder pattern, 3 modules wide
+	qrKey := barcode.RegisterQR(pdf, "vector", qr.L, qr.Auto)
+	qw, qh := barcode.GetVectorBarcodeDimensions(pdf, qrKey, 2, 0)
+	if qw != qh || qw != 42 {
+		t.Fatalf("QR code of %.2f by %.2f", qw, qh)
+	}
+	barcode.BarcodeVector(pdf, qrKey, 100, 100, qw, qh)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	if strings.Contains(str, "/Subtype /Image") {
+		t.Error("barcode embedded as an image")
+	}
+	// Code128 begins with a bar 2 modules wide
+	for _, s := range []string{"10.00 831.89 2.00 -20.00 re f", "104.00 737.89 6.00 -6.00 re f"} {
+		if !strings.Contains(str, s) {
+			t.Errorf("%q missing", s)
+		}
+	}
+	barcode.BarcodeVector(pdf, "missing", 0, 0, 10, 10)
+	if pdf.Err() == false {
+		t.Error("missing barcode accepted")
+	}
+	// The rectangles are drawn in the same order every time
+	var first string
+	for j := 0; j < 10; j++ {
+		pdf := gofpdf.New("P", "pt", "A4", "")
+		pdf.SetCompression(false)
+		pdf.SetCreationDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		pdf.SetModificationDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		pdf.AddPage()
+		key := barcode.RegisterQR(pdf, "vector", qr.L, qr.Auto)
+		barcode.BarcodeVector(pdf, key, 100, 100, 42, 42)
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if j == 0 {
+			first = buf.String()
+		} else if buf.String() != first {
+			t.Fatal("barcode output differs between calls")
+		}
+	}
+}
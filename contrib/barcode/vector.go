@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package barcode
+
+import (
+	"errors"
+	"image/color"
+	"sort"
+
+	"github.com/boombuler/barcode"
+)
+
+// barcodeVectorPdf is a partial PDF implementation that only implements the
+// functions that are required to draw a barcode as vector content.
+type barcodeVectorPdf interface {
+	Rect(x, y, w, h float64, styleStr string)
+	SetError(err error)
+}
+
+// BarcodeVector puts a registered barcode in the current page as vector
+// content rather than as an image: its dark modules are drawn as rectangles
+// filled with the current fill color, so that the barcode stays crisp at any
+// size and in print, however small, and no image is embedded in the document.
+//
+// The barcode fills the box of width w and height h, in the units used to
+// create the PDF document, whose upper left corner is at (x, y). Use
+// GetVectorBarcodeDimensions() to size the box from the width of a module. No
+// quiet zone is added around the barcode.
+func BarcodeVector(pdf barcodeVectorPdf, code string, x, y, w, h float64) {
+	bcode, ok := registeredBarcode(code)
+	if !ok {
+		pdf.SetError(errors.New("Barcode not found"))
+		return
+	}
+	cols, rows := bcode.Bounds().Dx(), bcode.Bounds().Dy()
+	moduleW, moduleH := w/float64(cols), h/float64(rows)
+	min := bcode.Bounds().Min
+	// Runs of dark modules of a row are drawn as one rectangle, which is
+	// extended down the rows that have the same run, so that no seams show
+	// between adjacent modules
+	type run struct{ start, end int }
+	open := make(map[run]int) // Row at which each run began
+	flush := func(row int, keep map[run]bool) {
+		// Ended runs are drawn in order of position rather than in map
+		// order so that the output is the same from one call to the next
+		var ended []run
+		for r := range open {
+			if !keep[r] {
+				ended = append(ended, r)
+			}
+		}
+		sort.Slice(ended, func(i, j int) bool {
+			if open[ended[i]] != open[ended[j]] {
+				return open[ended[i]] < open[ended[j]]
+			}
+			return ended[i].start < ended[j].start
+		})
+		for _, r := range ended {
+			top := open[r]
+			pdf.Rect(x+float64(r.start)*moduleW, y+float64(top)*moduleH,
+				float64(r.end-r.start)*moduleW, float64(row-top)*moduleH, "F")
+			delete(open, r)
+		}
+	}
+	for row := 0; row < rows; row++ {
+		runs := make(map[run]bool)
+		for col := 0; col < cols; {
+			if !dark(bcode.At(min.X+col, min.Y+row)) {
+				col++
+				continue
+			}
+			start := col
+			for col < cols && dark(bcode.At(min.X+col, min.Y+row)) {
+				col++
+			}
+			runs[run{start, col}] = true
+		}
+		flush(row, runs)
+		for r := range runs {
+			if _, ok := open[r]; !ok {
+				open[r] = row
+			}
+		}
+	}
+	flush(rows, nil)
+}
+
+// GetVectorBarcodeDimensions returns the width and height of the registered
+// barcode drawn with BarcodeVector() with modules of width moduleW. Linear
+// barcodes have the height barH, and two-dimensional barcodes have square
+// modules.
+func GetVectorBarcodeDimensions(pdf barcodeVectorPdf, code string, moduleW, barH float64) (w, h float64) {
+	bcode, ok := registeredBarcode(code)
+	if !ok {
+		pdf.SetError(errors.New("Barcode not found"))
+		return
+	}
+	w = float64(bcode.Bounds().Dx()) * moduleW
+	if rows := bcode.Bounds().Dy(); rows > 1 {
+		return w, float64(rows) * moduleW
+	}
+	return w, barH
+}
+
+// registeredBarcode returns the barcode registered with the key code.
+func registeredBarcode(code string) (bcode barcode.Barcode, ok bool) {
+	barcodes.Lock()
+	bcode, ok = barcodes.cache[code]
+	barcodes.Unlock()
+	return
+}
+
+// dark reports whether the module of the color c is dark.
+func dark(c color.Color) bool {
+	return color.GrayModel.Convert(c).(color.Gray).Y < 128
+}
@@ -0,0 +1,21 @@
+package bmp_test
+
+import (
+	"github.com/phpdave11/gofpdf"
+	"github.com/phpdave11/gofpdf/contrib/bmp"
+	"github.com/phpdave11/gofpdf/internal/example"
+)
+
+// ExampleRegisterFile demonstrates the loading and display of a BMP image.
+func ExampleRegisterFile() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	opt := gofpdf.ImageOptions{ImageType: "bmp"}
+	_ = bmp.RegisterFile(pdf, "logo", opt, "../../image/logo.bmp")
+	pdf.Image("logo", 10, 10, 60, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_Contrib_BMP")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated ../../pdf/Fpdf_Contrib_BMP.pdf
+}
@@ -0,0 +1,452 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+// Package charts draws bar, line, pie and scatter charts and sparklines in
+// documents generated with gofpdf. Charts are vector content, which stays
+// sharp when printed and takes little room in the document, with axes,
+// labels in the current font and legends.
+package charts
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/phpdave11/gofpdf"
+)
+
+// DefaultColors are the colors of the series of charts, or of the slices of
+// pie charts, that do not specify their own.
+var DefaultColors = []gofpdf.RGBType{
+	{R: 66, G: 114, B: 196}, {R: 237, G: 125, B: 49}, {R: 165, G: 165, B: 165},
+	{R: 255, G: 192, B: 0}, {R: 91, G: 155, B: 213}, {R: 112, G: 173, B: 71},
+	{R: 38, G: 68, B: 120}, {R: 158, G: 72, B: 14},
+}
+
+// SeriesType is a series of data of a chart.
+type SeriesType struct {
+	Name   string             // Name of the series in the legend
+	Values []float64          // Values for the categories of bar, line and pie charts
+	Points []gofpdf.PointType // Points of scatter charts
+}
+
+// ChartType describes the data of a chart and how it is presented.
+type ChartType struct {
+	Title      string               // Title printed above the chart, if not empty
+	Categories []string             // Labels of the categories of bar and line charts, or of the slices of pie charts
+	Series     []SeriesType         // Series of the chart, of which pie charts use the first
+	Colors     []gofpdf.RGBType     // Colors of the series, or of the slices of pie charts, DefaultColors if nil
+	Legend     bool                 // A legend of the series, or of the slices of pie charts, is printed below the chart
+	YMin, YMax float64              // Range of the vertical axis, computed from the values if equal
+	XMin, XMax float64              // Range of the horizontal axis of scatter charts, computed from the points if equal
+	FormatFnc  func(float64) string // Formats the labels of the axes, with the precision of their steps if nil
+}
+
+// Bar draws the chart as a bar chart in the box of width w and height h whose
+// upper left corner is at (x, y). The bars of the series are grouped by
+// category, and rise from zero, or from the bottom of the vertical axis if
+// zero is not in its range.
+func Bar(pdf *gofpdf.Fpdf, x, y, w, h float64, chart ChartType) {
+	categoryChart(pdf, x, y, w, h, chart, true)
+}
+
+// Line draws the chart as a line chart in the box of width w and height h
+// whose upper left corner is at (x, y). The values of each series are marked
+// at the centers of the categories and joined by lines.
+func Line(pdf *gofpdf.Fpdf, x, y, w, h float64, chart ChartType) {
+	categoryChart(pdf, x, y, w, h, chart, false)
+}
+
+// Pie draws the chart as a pie chart in the box of width w and height h whose
+// upper left corner is at (x, y). The values of the first series, which must
+// not be negative, are the slices, clockwise from the top, and are labeled
+// with their percentages of the whole. The legend lists the categories.
+func Pie(pdf *gofpdf.Fpdf, x, y, w, h float64, chart ChartType) {
+	if !pdf.Ok() {
+		return
+	}
+	if len(chart.Series) == 0 {
+		pdf.SetErrorf("charts: pie chart has no series")
+		return
+	}
+	if !valuesCheck(pdf, chart) {
+		return
+	}
+	total := 0.0
+	for _, v := range chart.Series[0].Values {
+		if v < 0 {
+			pdf.SetErrorf("charts: negative value %g in pie chart", v)
+			return
+		}
+		total += v
+	}
+	if total == 0 {
+		pdf.SetErrorf("charts: pie chart of zero values")
+		return
+	}
+	st := stateGet(pdf)
+	defer st.put(pdf)
+	x, y, w, h = frame(pdf, x, y, w, h, chart, chart.Categories)
+	_, th := pdf.GetFontSize()
+	r := math.Min(w, h) / 2
+	cx, cy := x+w/2, y+h/2
+	pdf.SetDrawColor(255, 255, 255)
+	pdf.SetLineWidth(th / 15)
+	start := 90.0
+	for j, v := range chart.Series[0].Values {
+		sweep := 360 * v / total
+		var path gofpdf.PathType
+		path.MoveTo(cx, cy)
+		path.ArcTo(cx, cy, r, r, 0, start, start-sweep)
+		path.Close()
+		fillColorSet(pdf, chart.color(j))
+		pdf.Path(path, "FD")
+		// Slices that are wide enough are labeled inside
+		if sweep >= 15 {
+			mid := (start - sweep/2) * math.Pi / 180
+			s := strconv.Itoa(int(math.Round(100*v/total))) + "%"
+			pdf.Text(cx+0.65*r*math.Cos(mid)-pdf.GetStringWidth(s)/2, cy-0.65*r*math.Sin(mid)+0.35*th, s)
+		}
+		start -= sweep
+	}
+}
+
+// Scatter draws the chart as a scatter chart in the box of width w and height
+// h whose upper left corner is at (x, y). The points of each series are
+// marked with dots.
+func Scatter(pdf *gofpdf.Fpdf, x, y, w, h float64, chart ChartType) {
+	if !pdf.Ok() {
+		return
+	}
+	var xs, ys []float64
+	for _, s := range chart.Series {
+		for _, pt := range s.Points {
+			xs, ys = append(xs, pt.X), append(ys, pt.Y)
+		}
+	}
+	if len(xs) == 0 {
+		pdf.SetErrorf("charts: scatter chart has no points")
+		return
+	}
+	st := stateGet(pdf)
+	defer st.put(pdf)
+	x, y, w, h = frame(pdf, x, y, w, h, chart, chart.names())
+	xAxis := axisNew(chart.XMin, chart.XMax, xs, false)
+	yAxis := axisNew(chart.YMin, chart.YMax, ys, false)
+	px, py, pw, ph := plotArea(pdf, x, y, w, h, chart, yAxis)
+	_, th := pdf.GetFontSize()
+	for j, v := range xAxis.ticks() {
+		xx := px + pw*(v-xAxis.lo)/(xAxis.hi-xAxis.lo)
+		if j > 0 {
+			gridColorSet(pdf, th)
+			pdf.Line(xx, py, xx, py+ph)
+		}
+		s := chart.format(v, xAxis.step)
+		pdf.Text(xx-pdf.GetStringWidth(s)/2, py+ph+1.2*th, s)
+	}
+	gridPut(pdf, px, py, pw, ph, chart, yAxis)
+	for j, s := range chart.Series {
+		fillColorSet(pdf, chart.color(j))
+		for _, pt := range s.Points {
+			if pt.X < xAxis.lo || pt.X > xAxis.hi || pt.Y < yAxis.lo || pt.Y > yAxis.hi {
+				continue
+			}
+			pdf.Circle(px+pw*(pt.X-xAxis.lo)/(xAxis.hi-xAxis.lo), py+ph*(yAxis.hi-pt.Y)/(yAxis.hi-yAxis.lo), th/6, "F")
+		}
+	}
+}
+
+// Sparkline draws values as a small line chart without axes or labels, such
+// as one that fits in a line of text or a table cell, in the box of width w
+// and height h whose upper left corner is at (x, y). The line is drawn in the
+// color clr with the current line width, and its last point is marked with a
+// dot.
+func Sparkline(pdf *gofpdf.Fpdf, x, y, w, h float64, values []float64, clr gofpdf.RGBType) {
+	if !pdf.Ok() || len(values) == 0 {
+		return
+	}
+	st := stateGet(pdf)
+	defer st.put(pdf)
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+	var pts []gofpdf.PointType
+	for j, v := range values {
+		xx := x + w/2
+		if len(values) > 1 {
+			xx = x + w*float64(j)/float64(len(values)-1)
+		}
+		pts = append(pts, gofpdf.PointType{X: xx, Y: y + h*(hi-v)/(hi-lo)})
+	}
+	pdf.SetDrawColor(clr.R, clr.G, clr.B)
+	for j := 1; j < len(pts); j++ {
+		pdf.Line(pts[j-1].X, pts[j-1].Y, pts[j].X, pts[j].Y)
+	}
+	fillColorSet(pdf, clr)
+	last := pts[len(pts)-1]
+	pdf.Circle(last.X, last.Y, math.Max(st.lineWidth*1.5, h/15), "F")
+}
+
+// categoryChart draws the chart as a bar chart if bars is true, or as a line
+// chart otherwise.
+func categoryChart(pdf *gofpdf.Fpdf, x, y, w, h float64, chart ChartType, bars bool) {
+	if !pdf.Ok() {
+		return
+	}
+	if len(chart.Categories) == 0 || len(chart.Series) == 0 {
+		pdf.SetErrorf("charts: chart has no categories or no series")
+		return
+	}
+	if !valuesCheck(pdf, chart) {
+		return
+	}
+	var values []float64
+	for _, s := range chart.Series {
+		values = append(values, s.Values...)
+	}
+	st := stateGet(pdf)
+	defer st.put(pdf)
+	x, y, w, h = frame(pdf, x, y, w, h, chart, chart.names())
+	yAxis := axisNew(chart.YMin, chart.YMax, values, bars)
+	px, py, pw, ph := plotArea(pdf, x, y, w, h, chart, yAxis)
+	_, th := pdf.GetFontSize()
+	gw := pw / float64(len(chart.Categories))
+	for j, s := range chart.Categories {
+		pdf.Text(px+(float64(j)+0.5)*gw-pdf.GetStringWidth(s)/2, py+ph+1.2*th, s)
+	}
+	gridPut(pdf, px, py, pw, ph, chart, yAxis)
+	yOf := func(v float64) float64 {
+		return py + ph*(yAxis.hi-math.Max(yAxis.lo, math.Min(yAxis.hi, v)))/(yAxis.hi-yAxis.lo)
+	}
+	for k, s := range chart.Series {
+		clr := chart.color(k)
+		fillColorSet(pdf, clr)
+		if bars {
+			bw := 0.8 * gw / float64(len(chart.Series))
+			base := yOf(0)
+			for j, v := range s.Values {
+				top := yOf(v)
+				pdf.Rect(px+(float64(j)+0.1)*gw+float64(k)*bw, math.Min(base, top), bw, math.Abs(base-top), "F")
+			}
+			continue
+		}
+		pdf.SetDrawColor(clr.R, clr.G, clr.B)
+		pdf.SetLineWidth(th / 10)
+		for j, v := range s.Values {
+			xx := px + (float64(j)+0.5)*gw
+			if j > 0 {
+				pdf.Line(xx-gw, yOf(s.Values[j-1]), xx, yOf(v))
+			}
+			pdf.Circle(xx, yOf(v), th/6, "F")
+		}
+	}
+}
+
+// valuesCheck reports whether each series of chart has a value for each
+// category, and sets the error of pdf otherwise.
+func valuesCheck(pdf *gofpdf.Fpdf, chart ChartType) bool {
+	for _, s := range chart.Series {
+		if len(s.Values) != len(chart.Categories) {
+			pdf.SetErrorf("charts: series %q has %d values for %d categories", s.Name, len(s.Values), len(chart.Categories))
+			return false
+		}
+	}
+	return true
+}
+
+// frame prints the title and the legend of the items names of chart in the
+// box of width w and height h whose upper left corner is at (x, y), and
+// returns the box left for the chart.
+func frame(pdf *gofpdf.Fpdf, x, y, w, h float64, chart ChartType, names []string) (float64, float64, float64, float64) {
+	_, th := pdf.GetFontSize()
+	lineHt := 1.5 * th
+	if chart.Title != "" {
+		pdf.Text(x+(w-pdf.GetStringWidth(chart.Title))/2, y+th, chart.Title)
+		y, h = y+lineHt, h-lineHt
+	}
+	if !chart.Legend {
+		return x, y, w, h
+	}
+	// The items of the legend are centered in rows, a row ending when the
+	// next item does not fit
+	var rows [][]int
+	var rowWidths []float64
+	itemW := func(j int) float64 {
+		return 2*th + pdf.GetStringWidth(names[j])
+	}
+	for j := range names {
+		if len(rows) == 0 || rowWidths[len(rows)-1]+itemW(j) > w {
+			rows, rowWidths = append(rows, nil), append(rowWidths, 0)
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], j)
+		rowWidths[len(rows)-1] += itemW(j)
+	}
+	h -= float64(len(rows)) * lineHt
+	for k, row := range rows {
+		xx := x + (w-rowWidths[k])/2
+		yy := y + h + float64(k)*lineHt + 0.5*th
+		for _, j := range row {
+			fillColorSet(pdf, chart.color(j))
+			pdf.Rect(xx, yy, 0.7*th, 0.7*th, "F")
+			pdf.Text(xx+th, yy+0.7*th, names[j])
+			xx += itemW(j)
+		}
+	}
+	return x, y, w, h
+}
+
+// plotArea returns the area of the plot of a chart with the vertical axis a in
+// the box of width w and height h whose upper left corner is at (x, y), which
+// leaves room for the labels of the axes.
+func plotArea(pdf *gofpdf.Fpdf, x, y, w, h float64, chart ChartType, a axis) (px, py, pw, ph float64) {
+	_, th := pdf.GetFontSize()
+	labelW := 0.0
+	for _, v := range a.ticks() {
+		labelW = math.Max(labelW, pdf.GetStringWidth(chart.format(v, a.step)))
+	}
+	px, py = x+labelW+th/2, y+th/2
+	return px, py, x + w - px - th/2, y + h - 1.5*th - py
+}
+
+// gridPut draws the horizontal grid lines of the vertical axis a of the plot
+// of width pw and height ph whose upper left corner is at (px, py), with
+// their labels, and the axes of the plot.
+func gridPut(pdf *gofpdf.Fpdf, px, py, pw, ph float64, chart ChartType, a axis) {
+	_, th := pdf.GetFontSize()
+	for _, v := range a.ticks() {
+		yy := py + ph*(a.hi-v)/(a.hi-a.lo)
+		gridColorSet(pdf, th)
+		pdf.Line(px, yy, px+pw, yy)
+		s := chart.format(v, a.step)
+		pdf.Text(px-th/2-pdf.GetStringWidth(s), yy+0.35*th, s)
+	}
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(th / 20)
+	pdf.Line(px, py, px, py+ph)
+	pdf.Line(px, py+ph, px+pw, py+ph)
+}
+
+// gridColorSet sets the color and width of grid lines of text of height th.
+func gridColorSet(pdf *gofpdf.Fpdf, th float64) {
+	pdf.SetDrawColor(220, 220, 220)
+	pdf.SetLineWidth(th / 30)
+}
+
+func fillColorSet(pdf *gofpdf.Fpdf, clr gofpdf.RGBType) {
+	pdf.SetFillColor(clr.R, clr.G, clr.B)
+}
+
+// names returns the names of the series of chart.
+func (chart ChartType) names() (list []string) {
+	for _, s := range chart.Series {
+		list = append(list, s.Name)
+	}
+	return
+}
+
+// color returns the color of the series, or slice, numbered j.
+func (chart ChartType) color(j int) gofpdf.RGBType {
+	colors := chart.Colors
+	if len(colors) == 0 {
+		colors = DefaultColors
+	}
+	return colors[j%len(colors)]
+}
+
+// format returns the label of the value v of an axis of steps step.
+func (chart ChartType) format(v, step float64) string {
+	if chart.FormatFnc != nil {
+		return chart.FormatFnc(v)
+	}
+	if math.Abs(v) < step/1e6 {
+		v = 0
+	}
+	decimals := int(-math.Floor(math.Log10(step)))
+	if decimals < 0 {
+		decimals = 0
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+// axis is the range of an axis, from lo to hi, divided in steps of step.
+type axis struct {
+	lo, hi, step float64
+}
+
+// axisNew returns the axis of the range from min to max, or of the range of
+// values rounded to steps if they are equal, which includes zero if zero is
+// true.
+func axisNew(min, max float64, values []float64, zero bool) (a axis) {
+	if min == max {
+		min, max = values[0], values[0]
+		for _, v := range values {
+			min, max = math.Min(min, v), math.Max(max, v)
+		}
+		if zero {
+			min, max = math.Min(min, 0), math.Max(max, 0)
+		}
+	}
+	if min == max {
+		min, max = min-1, max+1
+	}
+	// Steps of 1, 2 or 5 times a power of ten divide the range in about five
+	raw := (max - min) / 5
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch r := raw / mag; {
+	case r <= 1:
+		a.step = mag
+	case r <= 2:
+		a.step = 2 * mag
+	case r <= 5:
+		a.step = 5 * mag
+	default:
+		a.step = 10 * mag
+	}
+	a.lo = math.Floor(min/a.step+1e-9) * a.step
+	a.hi = math.Ceil(max/a.step-1e-9) * a.step
+	return
+}
+
+// ticks returns the values of the steps of the axis.
+func (a axis) ticks() (list []float64) {
+	n := int(math.Round((a.hi - a.lo) / a.step))
+	for j := 0; j <= n; j++ {
+		list = append(list, a.lo+float64(j)*a.step)
+	}
+	return
+}
+
+// state is the drawing state of a document that charts change.
+type state struct {
+	draw, fill gofpdf.RGBType
+	lineWidth  float64
+}
+
+func stateGet(pdf *gofpdf.Fpdf) (st state) {
+	st.draw.R, st.draw.G, st.draw.B = pdf.GetDrawColor()
+	st.fill.R, st.fill.G, st.fill.B = pdf.GetFillColor()
+	st.lineWidth = pdf.GetLineWidth()
+	return
+}
+
+func (st state) put(pdf *gofpdf.Fpdf) {
+	pdf.SetDrawColor(st.draw.R, st.draw.G, st.draw.B)
+	pdf.SetFillColor(st.fill.R, st.fill.G, st.fill.B)
+	pdf.SetLineWidth(st.lineWidth)
+}
@@ -0,0 +1,106 @@
+package charts_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/phpdave11/gofpdf"
+	"github.com/phpdave11/gofpdf/contrib/charts"
+	"github.com/phpdave11/gofpdf/internal/example"
+)
+
+// ExampleBar demonstrates bar, line, pie and scatter charts, and sparklines
+// in the lines of a table.
+func ExampleBar() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.AddPage()
+	quarters := []string{"Q1", "Q2", "Q3", "Q4"}
+	sales := charts.ChartType{
+		Title:      "Sales by region",
+		Categories: quarters,
+		Series: []charts.SeriesType{
+			{Name: "North", Values: []float64{120, 135, 150, 170}},
+			{Name: "South", Values: []float64{90, 110, 95, 130}},
+			{Name: "West", Values: []float64{60, 80, 105, 115}},
+		},
+		Legend: true,
+	}
+	charts.Bar(pdf, 10, 10, 90, 70, sales)
+	sales.Title = "Sales trend"
+	charts.Line(pdf, 110, 10, 90, 70, sales)
+	charts.Pie(pdf, 10, 95, 90, 70, charts.ChartType{
+		Title:      "Market share",
+		Categories: []string{"Product A", "Product B", "Product C", "Other"},
+		Series:     []charts.SeriesType{{Values: []float64{45, 25, 20, 10}}},
+		Legend:     true,
+	})
+	var heights, weights []gofpdf.PointType
+	for j := 0; j < 40; j++ {
+		v := float64(j)
+		heights = append(heights, gofpdf.PointType{X: 150 + v, Y: 45 + 0.6*v + 8*math.Sin(v*1.7)})
+		weights = append(weights, gofpdf.PointType{X: 155 + v, Y: 55 + 0.7*v + 6*math.Cos(v*2.3)})
+	}
+	charts.Scatter(pdf, 110, 95, 90, 70, charts.ChartType{
+		Title:  "Weight by height",
+		Series: []charts.SeriesType{{Name: "Group A", Points: heights}, {Name: "Group B", Points: weights}},
+		Legend: true,
+	})
+	pdf.SetXY(10, 180)
+	for j, name := range []string{"Alpha", "Beta", "Gamma"} {
+		var values []float64
+		for k := 0; k < 20; k++ {
+			values = append(values, math.Sin(float64(k*(j+1))/5)+float64(k)/10)
+		}
+		pdf.CellFormat(30, 8, name, "1", 0, "L", false, 0, "")
+		charts.Sparkline(pdf, pdf.GetX()+2, pdf.GetY()+1.5, 36, 5, values, gofpdf.RGBType{R: 40, G: 80, B: 160})
+		pdf.CellFormat(40, 8, "", "1", 1, "", false, 0, "")
+	}
+	fileStr := example.Filename("contrib_charts_Bar")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated ../../pdf/contrib_charts_Bar.pdf
+}
+
+// TestChecks verifies that charts of invalid data are reported and that the
+// drawing state is restored after charts.
+func TestChecks(t *testing.T) {
+	for _, fn := range []func(pdf *gofpdf.Fpdf){
+		func(pdf *gofpdf.Fpdf) {
+			charts.Bar(pdf, 10, 10, 100, 50, charts.ChartType{})
+		},
+		func(pdf *gofpdf.Fpdf) {
+			charts.Line(pdf, 10, 10, 100, 50, charts.ChartType{Categories: []string{"a", "b"},
+				Series: []charts.SeriesType{{Values: []float64{1}}}})
+		},
+		func(pdf *gofpdf.Fpdf) {
+			charts.Pie(pdf, 10, 10, 100, 50, charts.ChartType{Categories: []string{"a", "b"},
+				Series: []charts.SeriesType{{Values: []float64{1, -1}}}})
+		},
+		func(pdf *gofpdf.Fpdf) {
+			charts.Scatter(pdf, 10, 10, 100, 50, charts.ChartType{})
+		},
+	} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.AddPage()
+		fn(pdf)
+		if pdf.Ok() {
+			t.Error("invalid chart accepted")
+		}
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.AddPage()
+	pdf.SetFillColor(1, 2, 3)
+	pdf.SetLineWidth(0.3)
+	charts.Bar(pdf, 10, 10, 100, 50, charts.ChartType{Categories: []string{"a", "b"},
+		Series: []charts.SeriesType{{Values: []float64{-1, 2.5}}}, Legend: true})
+	if r, g, b := pdf.GetFillColor(); r != 1 || g != 2 || b != 3 || pdf.GetLineWidth() != 0.3 {
+		t.Errorf("state not restored")
+	}
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,322 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+// Package fontfinder locates the fonts installed on the system by family and
+// style, so that they can be used in a document without their paths being
+// known in advance.
+package fontfinder
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// SearchDirs, if not nil, lists the directories that are searched for fonts
+// in place of the system font directories returned by SystemDirs().
+var SearchDirs []string
+
+// Font describes an installed font.
+type Font struct {
+	// Family is the family name of the font, for example "Noto Sans".
+	Family string
+	// Style is the style of the font in the form accepted by
+	// gofpdf.SetFont(): "" for regular, "B" for bold, "I" for italic and
+	// "BI" for bold italic.
+	Style string
+	// Path is the name of the font file.
+	Path string
+}
+
+// fontfinderPdf is a partial interface that only implements the functions we
+// need from the PDF generator to register the fonts.
+type fontfinderPdf interface {
+	AddUTF8FontFromReader(familyStr, styleStr string, r io.Reader)
+	SetError(err error)
+}
+
+// SystemDirs returns the directories in which fonts are installed on the
+// current platform. On Linux and other Unix systems, these are the
+// directories named in the fontconfig configuration, or the usual
+// directories if there is none. Directories that do not exist are included.
+func SystemDirs() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		dirs := []string{filepath.Join(os.Getenv("WINDIR"), "Fonts")}
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			dirs = append(dirs, filepath.Join(local, "Microsoft", "Windows", "Fonts"))
+		}
+		return dirs
+	case "darwin":
+		return []string{
+			filepath.Join(home, "Library", "Fonts"),
+			"/Library/Fonts",
+			"/System/Library/Fonts",
+			"/Network/Library/Fonts",
+		}
+	}
+	configFile := os.Getenv("FONTCONFIG_FILE")
+	if configFile == "" {
+		configFile = "/etc/fonts/fonts.conf"
+	}
+	if !filepath.IsAbs(configFile) {
+		configFile = filepath.Join("/etc/fonts", configFile)
+	}
+	var dirs []string
+	fontconfigDirs(configFile, home, &dirs, 0)
+	if len(dirs) == 0 {
+		dirs = []string{
+			filepath.Join(xdgDataHome(home), "fonts"),
+			filepath.Join(home, ".fonts"),
+			"/usr/local/share/fonts",
+			"/usr/share/fonts",
+		}
+	}
+	return dirs
+}
+
+func xdgDataHome(home string) string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, ".local", "share")
+}
+
+// fontconfigDirs appends the font directories named by the <dir> elements of
+// the fontconfig configuration file fileStr, and of the files it includes,
+// to dirs. Missing and malformed files are ignored.
+func fontconfigDirs(fileStr, home string, dirs *[]string, depth int) {
+	if depth > 8 {
+		return
+	}
+	info, err := os.Stat(fileStr)
+	if err != nil {
+		return
+	}
+	if info.IsDir() {
+		// An included directory contributes its files named *.conf in order
+		names, _ := filepath.Glob(filepath.Join(fileStr, "*.conf"))
+		sort.Strings(names)
+		for _, name := range names {
+			fontconfigDirs(name, home, dirs, depth+1)
+		}
+		return
+	}
+	fl, err := os.Open(fileStr)
+	if err != nil {
+		return
+	}
+	defer fl.Close()
+	var elem struct {
+		Prefix string `xml:"prefix,attr"`
+		Value  string `xml:",chardata"`
+	}
+	dec := xml.NewDecoder(fl)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "dir" && start.Name.Local != "include" {
+			continue
+		}
+		elem.Prefix, elem.Value = "", ""
+		if dec.DecodeElement(&elem, &start) != nil {
+			return
+		}
+		name := strings.TrimSpace(elem.Value)
+		switch {
+		case name == "":
+			continue
+		case elem.Prefix == "xdg":
+			name = filepath.Join(xdgDataHome(home), name)
+		case strings.HasPrefix(name, "~"):
+			if home == "" {
+				continue
+			}
+			name = filepath.Join(home, name[1:])
+		case !filepath.IsAbs(name):
+			name = filepath.Join(filepath.Dir(fileStr), name)
+		}
+		if start.Name.Local == "dir" {
+			*dirs = append(*dirs, name)
+		} else {
+			fontconfigDirs(name, home, dirs, depth+1)
+		}
+	}
+}
+
+// List returns the TrueType and OpenType fonts found in the searched
+// directories and their subdirectories, ordered by family, style and path.
+// Files that cannot be parsed are skipped. Font collections are not listed
+// because they cannot be embedded by gofpdf.
+func List() (list []Font, err error) {
+	dirs := SearchDirs
+	if dirs == nil {
+		dirs = SystemDirs()
+	}
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		err = filepath.Walk(dir, func(pathStr string, info os.FileInfo, err error) error {
+			if err != nil {
+				if pathStr == dir || info != nil && info.IsDir() {
+					// Unreadable or missing directories are not an error
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() || seen[pathStr] {
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(pathStr)) {
+			case ".ttf", ".otf":
+				seen[pathStr] = true
+				if fnt, ok := readFont(pathStr); ok {
+					list = append(list, fnt)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Family != b.Family {
+			return a.Family < b.Family
+		}
+		if a.Style != b.Style {
+			return len(a.Style) < len(b.Style) || len(a.Style) == len(b.Style) && a.Style < b.Style
+		}
+		return a.Path < b.Path
+	})
+	return
+}
+
+// readFont returns the description of the font file at pathStr. Only the
+// tables that are needed to find the names are read.
+func readFont(pathStr string) (fnt Font, ok bool) {
+	fl, err := os.Open(pathStr)
+	if err != nil {
+		return
+	}
+	defer fl.Close()
+	f, err := sfnt.ParseReaderAt(fl)
+	if err != nil {
+		return
+	}
+	var buf sfnt.Buffer
+	fnt.Family, err = f.Name(&buf, sfnt.NameIDFamily)
+	if err != nil || fnt.Family == "" {
+		return
+	}
+	subfamily, _ := f.Name(&buf, sfnt.NameIDSubfamily)
+	// The family and subfamily names follow the model in which a family has
+	// at most a regular, bold, italic and bold italic member
+	subfamily = strings.ToLower(subfamily)
+	if strings.Contains(subfamily, "bold") {
+		fnt.Style += "B"
+	}
+	if strings.Contains(subfamily, "italic") || strings.Contains(subfamily, "oblique") {
+		fnt.Style += "I"
+	}
+	fnt.Path = pathStr
+	return fnt, true
+}
+
+// Find returns the installed font of the specified family and style. The
+// family is matched without regard to case and may be a pattern of the form
+// accepted by path.Match(), such as "Noto Sans*". If more than one font
+// matches, the first in the order of List() is returned. style is
+// interpreted as it is by gofpdf.SetFont().
+func Find(family, style string) (fnt Font, err error) {
+	list, err := List()
+	if err != nil {
+		return
+	}
+	return find(list, family, style)
+}
+
+func find(list []Font, family, style string) (fnt Font, err error) {
+	style = strings.ToUpper(style)
+	if style == "IB" {
+		style = "BI"
+	}
+	pattern := strings.ToLower(family)
+	for _, fnt = range list {
+		if fnt.Style != style {
+			continue
+		}
+		match, err := path.Match(pattern, strings.ToLower(fnt.Family))
+		if err != nil {
+			return Font{}, err
+		}
+		if match {
+			return fnt, nil
+		}
+	}
+	return Font{}, fmt.Errorf("no installed font of family %q and style %q", family, style)
+}
+
+// Register adds the first installed font that matches one of the specified
+// families to the document as a UTF-8 font named familyStr with the style
+// styleStr. The families are tried in order and are matched as they are by
+// Find(). If none is given, familyStr itself is looked up. For example, a
+// document may use whatever Noto font is installed with
+//
+//	fontfinder.Register(pdf, "body", "", "Noto Sans", "Noto Serif", "Noto*")
+//
+// The font that has been registered is returned. An error is set on the
+// document if there is no matching font.
+func Register(f fontfinderPdf, familyStr, styleStr string, families ...string) (fnt Font) {
+	list, err := List()
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	if len(families) == 0 {
+		families = []string{familyStr}
+	}
+	for _, family := range families {
+		fnt, err = find(list, family, styleStr)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	fl, err := os.Open(fnt.Path)
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	defer fl.Close()
+	f.AddUTF8FontFromReader(familyStr, styleStr, fl)
+	return
+}
@@ -0,0 +1,54 @@
+package fontfinder_test
+
+import (
+	"testing"
+
+	"github.com/phpdave11/gofpdf"
+	"github.com/phpdave11/gofpdf/contrib/fontfinder"
+	"github.com/phpdave11/gofpdf/internal/example"
+)
+
+// ExampleRegister demonstrates the registration of installed fonts by family.
+// The font directory of this repository stands in for the system font
+// directories.
+func ExampleRegister() {
+	fontfinder.SearchDirs = []string{"../../font"}
+	defer func() { fontfinder.SearchDirs = nil }()
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	fontfinder.Register(pdf, "body", "", "Noto Sans", "DejaVu Sans*")
+	fontfinder.Register(pdf, "body", "B", "Noto Sans", "DejaVu Sans*")
+	pdf.AddPage()
+	pdf.SetFont("body", "B", 16)
+	pdf.Cell(0, 10, "Installed fonts")
+	pdf.Ln(10)
+	pdf.SetFont("body", "", 12)
+	pdf.Cell(0, 10, "Здравствуйте, Γειά σας")
+	fileStr := example.Filename("contrib_fontfinder_Register")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated ../../pdf/contrib_fontfinder_Register.pdf
+}
+
+func TestFind(t *testing.T) {
+	fontfinder.SearchDirs = []string{"../../font"}
+	defer func() { fontfinder.SearchDirs = nil }()
+
+	fnt, err := fontfinder.Find("dejavu sans condensed", "ib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fnt.Family != "DejaVu Sans Condensed" || fnt.Style != "BI" {
+		t.Fatalf("unexpected font %+v", fnt)
+	}
+	_, err = fontfinder.Find("DejaVu Sans Condensed", "X")
+	if err == nil {
+		t.Fatal("expecting error for missing style")
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	fontfinder.Register(pdf, "Missing Family", "")
+	if pdf.Err() == false {
+		t.Fatal("expecting error for missing family")
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package tiff
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// TIFF tags and field types used when locating pages and resolution
+const (
+	tagXResolution    = 282
+	tagResolutionUnit = 296
+	typeShort         = 3
+	typeRational      = 5
+	unitInch          = 2
+	unitCentimeter    = 3
+)
+
+// ifdType locates an image file directory within a TIFF buffer
+type ifdType struct {
+	data  []byte
+	order binary.ByteOrder
+	off   uint32
+}
+
+func (d ifdType) count() int {
+	return int(d.order.Uint16(d.data[d.off:]))
+}
+
+// entry returns the type, count and raw value field of the specified tag
+func (d ifdType) entry(tag uint16) (tp uint16, count uint32, val []byte, ok bool) {
+	n := d.count()
+	for j := 0; j < n; j++ {
+		e := d.data[int(d.off)+2+12*j:]
+		if d.order.Uint16(e) == tag {
+			return d.order.Uint16(e[2:]), d.order.Uint32(e[4:]), e[8:12], true
+		}
+	}
+	return
+}
+
+// dpi returns the horizontal resolution of the directory in dots per inch.
+// ok is false if the resolution is absent or has no absolute unit.
+func (d ifdType) dpi() (dpi float64, ok bool) {
+	unit := uint16(unitInch)
+	if tp, _, val, found := d.entry(tagResolutionUnit); found && tp == typeShort {
+		unit = d.order.Uint16(val)
+	}
+	tp, _, val, found := d.entry(tagXResolution)
+	if !found || tp != typeRational {
+		return
+	}
+	pos := d.order.Uint32(val)
+	if uint64(pos)+8 > uint64(len(d.data)) {
+		return
+	}
+	num := d.order.Uint32(d.data[pos:])
+	den := d.order.Uint32(d.data[pos+4:])
+	if num == 0 || den == 0 {
+		return
+	}
+	dpi = float64(num) / float64(den)
+	switch unit {
+	case unitInch:
+		ok = true
+	case unitCentimeter:
+		dpi *= 2.54
+		ok = true
+	}
+	return
+}
+
+// selectPage locates the zero-based page of the TIFF image in data. If page
+// is not the first page, the header of data is modified in place to point to
+// the selected directory so that it is the one decoded.
+func selectPage(data []byte, page int) (d ifdType, err error) {
+	if len(data) < 8 {
+		err = fmt.Errorf("TIFF buffer is too short")
+		return
+	}
+	switch string(data[0:4]) {
+	case "II\x2A\x00":
+		d.order = binary.LittleEndian
+	case "MM\x00\x2A":
+		d.order = binary.BigEndian
+	default:
+		err = fmt.Errorf("not a TIFF buffer")
+		return
+	}
+	if page < 0 {
+		err = fmt.Errorf("invalid TIFF page %d", page)
+		return
+	}
+	d.data = data
+	d.off = d.order.Uint32(data[4:])
+	for j := 0; ; j++ {
+		if d.off < 8 || uint64(d.off)+2 > uint64(len(data)) ||
+			uint64(d.off)+2+12*uint64(d.count())+4 > uint64(len(data)) {
+			err = fmt.Errorf("TIFF page %d not found", page)
+			return
+		}
+		if j == page {
+			break
+		}
+		d.off = d.order.Uint32(data[int(d.off)+2+12*d.count():])
+	}
+	d.order.PutUint32(data[4:], d.off)
+	return
+}
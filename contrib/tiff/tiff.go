@@ -24,6 +24,7 @@ import (
 	"image"
 	"image/png"
 	"io"
+	"io/ioutil"
 	"os"
 
 	"github.com/phpdave11/gofpdf"
@@ -34,20 +35,36 @@ import (
 // adding it to the page. imgName specifies the name that will be used in the
 // call to Image() that actually places the image in the document. options
 // specifies various image properties; in this case, the ImageType property
-// should be set to "tiff". The TIFF image is a reader from the reader
-// specified by r.
+// should be set to "tiff". The Page property selects the zero-based page
+// (image file directory) of a multi-page TIFF. If the ReadDpi property is
+// true, the image resolution is taken from the XResolution and
+// ResolutionUnit tags of the selected page. The TIFF image is a reader from
+// the reader specified by r.
 func RegisterReader(fpdf *gofpdf.Fpdf, imgName string, options gofpdf.ImageOptions, r io.Reader) (info *gofpdf.ImageInfoType) {
 	var err error
 	var img image.Image
 	var buf bytes.Buffer
+	var data []byte
+	var dir ifdType
 	if fpdf.Ok() {
 		if options.ImageType == "tiff" || options.ImageType == "tif" {
-			img, err = tiff.Decode(r)
+			data, err = ioutil.ReadAll(r)
+			if err == nil {
+				dir, err = selectPage(data, options.Page)
+			}
+			if err == nil {
+				img, err = tiff.Decode(bytes.NewReader(data))
+			}
 			if err == nil {
 				err = png.Encode(&buf, img)
 				if err == nil {
 					options.ImageType = "png"
 					info = fpdf.RegisterImageOptionsReader(imgName, options, &buf)
+					if info != nil && options.ReadDpi {
+						if dpi, ok := dir.dpi(); ok {
+							info.SetDpi(dpi)
+						}
+					}
 				}
 			}
 		} else {
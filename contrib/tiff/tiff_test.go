@@ -1,6 +1,8 @@
 package tiff_test
 
 import (
+	"os"
+
 	"github.com/phpdave11/gofpdf"
 	"github.com/phpdave11/gofpdf/contrib/tiff"
 	"github.com/phpdave11/gofpdf/internal/example"
@@ -21,3 +23,27 @@ func ExampleRegisterFile() {
 	// Output:
 	// Successfully generated ../../pdf/Fpdf_Contrib_Tiff.pdf
 }
+
+// ExampleRegisterReader demonstrates the selection of individual pages of a
+// multi-page TIFF image.
+func ExampleRegisterReader() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	for page, name := range []string{"first", "second"} {
+		fl, err := os.Open("../../image/multipage.tiff")
+		if err == nil {
+			opt := gofpdf.ImageOptions{ImageType: "tiff", Page: page}
+			_ = tiff.RegisterReader(pdf, name, opt, fl)
+			fl.Close()
+		} else {
+			pdf.SetError(err)
+		}
+	}
+	pdf.Image("first", 10, 10, 80, 0, false, "", 0, "")
+	pdf.Image("second", 110, 10, 80, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_Contrib_TiffPage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated ../../pdf/Fpdf_Contrib_TiffPage.pdf
+}
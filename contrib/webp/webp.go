@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+// Package webp allows WebP images, both lossy and lossless, to be used in
+// documents generated with gofpdf.
+package webp
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+
+	"github.com/phpdave11/gofpdf"
+	"golang.org/x/image/webp"
+)
+
+// JPEGQuality is the quality used when a lossy WebP image is transcoded to
+// JPEG for embedding with the DCTDecode filter.
+var JPEGQuality = 90
+
+// RegisterReader registers a WebP image, adding it to the PDF file but not
+// adding it to the page. imgName specifies the name that will be used in the
+// call to Image() that actually places the image in the document. options
+// specifies various image properties; in this case, the ImageType property
+// should be set to "webp". The WebP image is read from the reader specified
+// by r.
+//
+// Lossy images without an alpha channel are transcoded to JPEG and embedded
+// with the DCTDecode filter. Lossless images and lossy images with an alpha
+// channel are transcoded to PNG and embedded with the FlateDecode filter.
+func RegisterReader(fpdf *gofpdf.Fpdf, imgName string, options gofpdf.ImageOptions, r io.Reader) (info *gofpdf.ImageInfoType) {
+	var err error
+	var img image.Image
+	var buf bytes.Buffer
+	if fpdf.Ok() {
+		if options.ImageType == "webp" {
+			img, err = webp.Decode(r)
+			if err == nil {
+				if _, ok := img.(*image.YCbCr); ok {
+					err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality})
+					options.ImageType = "jpg"
+				} else {
+					err = png.Encode(&buf, img)
+					options.ImageType = "png"
+				}
+				if err == nil {
+					info = fpdf.RegisterImageOptionsReader(imgName, options, &buf)
+				}
+			}
+		} else {
+			err = fmt.Errorf("expecting \"webp\" as image type, got \"%s\"", options.ImageType)
+		}
+		if err != nil {
+			fpdf.SetError(err)
+		}
+	}
+	return
+}
+
+// RegisterFile registers a WebP image, adding it to the PDF file but not
+// adding it to the page. imgName specifies the name that will be used in the
+// call to Image() that actually places the image in the document. options
+// specifies various image properties; in this case, the ImageType property
+// should be set to "webp". The WebP image is read from the file specified by
+// webpFileStr.
+func RegisterFile(fpdf *gofpdf.Fpdf, imgName string, options gofpdf.ImageOptions, webpFileStr string) (info *gofpdf.ImageInfoType) {
+	var f *os.File
+	var err error
+
+	if fpdf.Ok() {
+		f, err = os.Open(webpFileStr)
+		if err == nil {
+			info = RegisterReader(fpdf, imgName, options, f)
+			f.Close()
+		} else {
+			fpdf.SetError(err)
+		}
+	}
+	return
+}
@@ -0,0 +1,24 @@
+package webp_test
+
+import (
+	"github.com/phpdave11/gofpdf"
+	"github.com/phpdave11/gofpdf/contrib/webp"
+	"github.com/phpdave11/gofpdf/internal/example"
+)
+
+// ExampleRegisterFile demonstrates the loading and display of lossy and
+// lossless WebP images.
+func ExampleRegisterFile() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	opt := gofpdf.ImageOptions{ImageType: "webp"}
+	_ = webp.RegisterFile(pdf, "rose", opt, "../../image/rose-lossy.webp")
+	_ = webp.RegisterFile(pdf, "gopher", opt, "../../image/gopher-doc-lossless.webp")
+	pdf.Image("rose", 10, 10, 90, 0, false, "", 0, "")
+	pdf.Image("gopher", 110, 10, 90, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_Contrib_WebP")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated ../../pdf/Fpdf_Contrib_WebP.pdf
+}
@@ -0,0 +1,304 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"strings"
+)
+
+// LineDecorationType describes the decorations of the lines drawn with
+// Line(), Polygon() and Path(), set with SetLineDecoration().
+//
+// Start and End are the shapes drawn at the start and the end of a line, or
+// of an open path: "arrow" for a filled arrowhead, "open" for an open
+// arrowhead made of two strokes, "dot" for a filled circle, "bar" for a
+// stroke across the line, as at the ends of the dimension lines of technical
+// drawings, or an empty string for none. Size is the length of arrowheads and
+// the diameter of dots and length of bars, in the unit of measure specified
+// in New(). If Size is 0, the shapes scale with the line width, and are six
+// times as large.
+//
+// Marker is the shape repeated along lines, including the sides of polygons
+// and closed paths: "dot" for a filled circle, "tick" for a stroke across the
+// line, or an empty string for none. Markers are MarkerSpacing apart, from
+// the start of each subpath, and MarkerSize is their size, which is three
+// times the line width if 0.
+type LineDecorationType struct {
+	Start, End    string
+	Size          float64
+	Marker        string
+	MarkerSize    float64
+	MarkerSpacing float64
+}
+
+// SetLineDecoration sets the decorations drawn with the lines drawn
+// afterwards with Line(), Polygon() and Path() when they are outlined.
+// Decorations are drawn with the current draw color and line width, with
+// solid strokes, and the line is shortened under a filled arrowhead so that
+// it does not show past its tip. Call this method with the zero value of
+// LineDecorationType to draw plain lines. The decorations are retained from
+// page to page.
+func (f *Fpdf) SetLineDecoration(dec LineDecorationType) {
+	if f.err != nil {
+		return
+	}
+	for _, s := range []string{dec.Start, dec.End} {
+		switch s {
+		case "", "arrow", "open", "dot", "bar":
+		default:
+			f.SetErrorf("invalid line decoration %q", s)
+			return
+		}
+	}
+	switch dec.Marker {
+	case "":
+	case "dot", "tick":
+		if dec.MarkerSpacing <= 0 {
+			f.SetErrorf("invalid line marker spacing %.3f", dec.MarkerSpacing)
+			return
+		}
+	default:
+		f.SetErrorf("invalid line marker %q", dec.Marker)
+		return
+	}
+	if dec.Size < 0 || dec.MarkerSize < 0 {
+		f.SetErrorf("invalid line decoration size %.3f or marker size %.3f", dec.Size, dec.MarkerSize)
+		return
+	}
+	f.lineDecoration = dec
+}
+
+// GetLineDecoration returns the decorations of lines set with
+// SetLineDecoration().
+func (f *Fpdf) GetLineDecoration() LineDecorationType {
+	return f.lineDecoration
+}
+
+// decorated reports whether lines are drawn with decorations.
+func (f *Fpdf) decorated() bool {
+	return f.lineDecoration != LineDecorationType{}
+}
+
+// decoratedPath draws the path as op, a path painting operator, with the
+// decorations of lines if op strokes the path.
+func (f *Fpdf) decoratedPath(path PathType, op string) {
+	if !strings.ContainsAny(op, "SsBb") || !f.decorated() {
+		f.out(f.pathOps(path) + op)
+		return
+	}
+	dec := f.lineDecoration
+	size, markerSize := dec.Size, dec.MarkerSize
+	if size == 0 {
+		size = 6 * f.lineWidth
+	}
+	if markerSize == 0 {
+		markerSize = 3 * f.lineWidth
+	}
+	var filled, stroked PathType
+	// The segments are copied, as the path is shortened under arrowheads
+	segs := append([]pathSegment(nil), path.segs...)
+	subs := pathSubpaths(segs)
+	if len(subs) > 0 {
+		first, last := subs[0], subs[len(subs)-1]
+		if tip, d, _, _, ok := pathEnds(segs[first[0]:first[1]]); ok && dec.Start != "" {
+			if lineEnd(&filled, &stroked, dec.Start, tip, d, size) {
+				pathShift(&segs[first[0]], &segs[first[0]+1], d, -size/2, true)
+			}
+		}
+		if _, _, tip, d, ok := pathEnds(segs[last[0]:last[1]]); ok && dec.End != "" {
+			if lineEnd(&filled, &stroked, dec.End, tip, d, size) {
+				pathShift(&segs[last[1]-1], nil, d, -size/2, false)
+			}
+		}
+	}
+	if dec.Marker != "" {
+		for _, sub := range pathSubpaths(path.segs) {
+			lineMarkers(&filled, &stroked, dec.Marker, path.segs[sub[0]:sub[1]], markerSize, dec.MarkerSpacing)
+		}
+	}
+	f.out(f.pathOps(PathType{segs: segs}) + op)
+	f.outf("q [] 0 d %s", drawColorFillStr(f.color.draw.str))
+	if len(filled.segs) > 0 {
+		f.out(f.pathOps(filled) + "f")
+	}
+	if len(stroked.segs) > 0 {
+		f.out(f.pathOps(stroked) + "S")
+	}
+	f.out("Q")
+}
+
+// drawColorFillStr returns the operators that set the fill color to the draw
+// color set by the operators drawStr.
+func drawColorFillStr(drawStr string) string {
+	list := strings.Fields(drawStr)
+	for j, s := range list {
+		if !strings.HasPrefix(s, "/") {
+			list[j] = strings.ToLower(s)
+		}
+	}
+	return strings.Join(list, " ")
+}
+
+// pathSubpaths returns the start and end indexes of the subpaths of segs.
+func pathSubpaths(segs []pathSegment) (list [][2]int) {
+	for j, seg := range segs {
+		if seg.op == 'm' {
+			list = append(list, [2]int{j, j + 1})
+		} else if len(list) > 0 {
+			list[len(list)-1][1] = j + 1
+		}
+	}
+	return
+}
+
+// pathEnds returns the first and last points of the subpath sub and the
+// directions, unit vectors, in which the subpath leaves the first point
+// backwards and arrives at the last point. ok is false if the subpath is
+// closed or has no length.
+func pathEnds(sub []pathSegment) (start, startDir, end, endDir PointType, ok bool) {
+	if sub[len(sub)-1].op == 'h' {
+		return
+	}
+	// The directions at the ends are those of the lines to the nearest
+	// distinct points, or control points of curves, of the subpath
+	var pts []PointType
+	for _, seg := range sub {
+		pts = append(pts, seg.pts[:intIf(seg.op == 'c', 3, 1)]...)
+	}
+	start, end = pts[0], pts[len(pts)-1]
+	var endOk bool
+	for j := 1; j < len(pts) && !ok; j++ {
+		startDir, ok = unitVector(start, pts[j])
+	}
+	for j := len(pts) - 2; j >= 0 && !endOk; j-- {
+		endDir, endOk = unitVector(end, pts[j])
+	}
+	return start, startDir, end, endDir, ok && endOk
+}
+
+// unitVector returns the unit vector from pt to tip. ok is false if the
+// points are the same.
+func unitVector(tip, pt PointType) (d PointType, ok bool) {
+	dx, dy := tip.X-pt.X, tip.Y-pt.Y
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return
+	}
+	return PointType{X: dx / l, Y: dy / l}, true
+}
+
+// pathShift moves the end point of a line along d by the distance dist, and
+// the adjacent control point of the segment next along with it if it is a
+// curve. The end point is the point that begins the subpath if start is true,
+// and the end of the segment seg otherwise.
+func pathShift(seg, next *pathSegment, d PointType, dist float64, start bool) {
+	dx, dy := d.X*dist, d.Y*dist
+	shift := func(pt *PointType) {
+		pt.X += dx
+		pt.Y += dy
+	}
+	if start {
+		shift(&seg.pts[0])
+		if next.op == 'c' {
+			shift(&next.pts[0])
+		}
+	} else if seg.op == 'c' {
+		shift(&seg.pts[2])
+		shift(&seg.pts[1])
+	} else {
+		shift(&seg.pts[0])
+	}
+}
+
+// lineEnd adds the shape styleStr at the end tip of a line arriving in the
+// direction d to the shapes that are filled or stroked, and reports whether
+// the line is to be shortened under it.
+func lineEnd(filled, stroked *PathType, styleStr string, tip, d PointType, size float64) bool {
+	n := PointType{X: -d.Y, Y: d.X}
+	base := PointType{X: tip.X - d.X*size, Y: tip.Y - d.Y*size}
+	w := size / 3
+	switch styleStr {
+	case "arrow":
+		filled.MoveTo(tip.X, tip.Y)
+		filled.LineTo(base.X+n.X*w, base.Y+n.Y*w)
+		filled.LineTo(base.X-n.X*w, base.Y-n.Y*w)
+		filled.Close()
+		return true
+	case "open":
+		stroked.MoveTo(base.X+n.X*w, base.Y+n.Y*w)
+		stroked.LineTo(tip.X, tip.Y)
+		stroked.LineTo(base.X-n.X*w, base.Y-n.Y*w)
+	case "dot":
+		filled.Ellipse(tip.X, tip.Y, size/2, size/2)
+	case "bar":
+		stroked.MoveTo(tip.X+n.X*size/2, tip.Y+n.Y*size/2)
+		stroked.LineTo(tip.X-n.X*size/2, tip.Y-n.Y*size/2)
+	}
+	return false
+}
+
+// lineMarkers adds the markers styleStr, spacing apart along the subpath
+// sub, to the shapes that are filled or stroked.
+func lineMarkers(filled, stroked *PathType, styleStr string, sub []pathSegment, size, spacing float64) {
+	// Curves are flattened into lines along which the markers are placed
+	pts := subpathPoints(sub, 16)
+	pos := spacing // Distance along the subpath of the next marker
+	done := 0.0    // Distance along the subpath of the start of the line
+	for j := 1; j < len(pts); j++ {
+		d, ok := unitVector(pts[j], pts[j-1])
+		if !ok {
+			continue
+		}
+		l := math.Hypot(pts[j].X-pts[j-1].X, pts[j].Y-pts[j-1].Y)
+		for ; pos < done+l; pos += spacing {
+			x, y := pts[j-1].X+d.X*(pos-done), pts[j-1].Y+d.Y*(pos-done)
+			if styleStr == "dot" {
+				filled.Ellipse(x, y, size/2, size/2)
+			} else {
+				stroked.MoveTo(x+d.Y*size/2, y-d.X*size/2)
+				stroked.LineTo(x-d.Y*size/2, y+d.X*size/2)
+			}
+		}
+		done += l
+	}
+}
+
+// subpathPoints returns the points of the subpath sub, whose curves are
+// flattened into steps lines each. A closed subpath ends with its first
+// point.
+func subpathPoints(sub []pathSegment, steps int) (pts []PointType) {
+	for _, seg := range sub {
+		switch seg.op {
+		case 'm', 'l':
+			pts = append(pts, seg.pts[0])
+		case 'c':
+			p0 := pts[len(pts)-1]
+			for j := 1; j <= steps; j++ {
+				t := float64(j) / float64(steps)
+				u := 1 - t
+				pts = append(pts, PointType{
+					X: u*u*u*p0.X + 3*u*u*t*seg.pts[0].X + 3*u*t*t*seg.pts[1].X + t*t*t*seg.pts[2].X,
+					Y: u*u*u*p0.Y + 3*u*u*t*seg.pts[0].Y + 3*u*t*t*seg.pts[1].Y + t*t*t*seg.pts[2].Y,
+				})
+			}
+		case 'h':
+			pts = append(pts, pts[0])
+		}
+	}
+	return
+}
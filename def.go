@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"time"
 )
 
@@ -37,9 +38,13 @@ type blendModeType struct {
 }
 
 type gradientType struct {
-	tp                int // 2: linear, 3: radial
-	clr1Str, clr2Str  string
+	tp                int       // 2: linear, 3: radial, 4 to 6: mesh
+	clrStrs           []string  // Colors of the stops
+	posList           []float64 // Positions of the stops, from 0 to 1
 	x1, y1, x2, y2, r float64
+	extend            bool
+	meshStr           string // Entries of the dictionary of a mesh
+	mesh              []byte // Data of a mesh
 	objNum            int
 }
 
@@ -116,12 +121,33 @@ const (
 	AlignBaseline = "B"
 )
 
+const (
+	// TextModeFill fills text
+	TextModeFill = iota
+	// TextModeStroke strokes text
+	TextModeStroke
+	// TextModeFillStroke fills, then strokes text
+	TextModeFillStroke
+	// TextModeInvisible neither fills nor strokes text
+	TextModeInvisible
+	// TextModeFillClip fills text and adds it to the clipping path
+	TextModeFillClip
+	// TextModeStrokeClip strokes text and adds it to the clipping path
+	TextModeStrokeClip
+	// TextModeFillStrokeClip fills, then strokes text and adds it to the
+	// clipping path
+	TextModeFillStrokeClip
+	// TextModeClip adds text to the clipping path
+	TextModeClip
+)
+
 type colorMode int
 
 const (
 	colorModeRGB colorMode = iota
 	colorModeSpot
 	colorModeCMYK
+	colorModePattern
 )
 
 type colorType struct {
@@ -165,31 +191,62 @@ func (p PointType) XY() (float64, float64) {
 // Changes to this structure should be reflected in its GobEncode and GobDecode
 // methods.
 type ImageInfoType struct {
-	data  []byte  // Raw image data
-	smask []byte  // Soft Mask, an 8bit per-pixel transparency mask
-	n     int     // Image object number
-	w     float64 // Width
-	h     float64 // Height
-	cs    string  // Color space
-	pal   []byte  // Image color palette
-	bpc   int     // Bits Per Component
-	f     string  // Image filter
-	dp    string  // DecodeParms
-	trns  []int   // Transparency mask
-	scale float64 // Document scale factor
-	dpi   float64 // Dots-per-inch found from image file (png only)
-	i     string  // SHA-1 checksum of the above values.
+	data    []byte                    // Raw image data
+	smask   []byte                    // Soft Mask, an 8bit per-pixel transparency mask
+	n       int                       // Image object number
+	w       float64                   // Width
+	h       float64                   // Height
+	cs      string                    // Color space
+	pal     []byte                    // Image color palette
+	bpc     int                       // Bits Per Component
+	f       string                    // Image filter
+	dp      string                    // DecodeParms
+	trns    []int                     // Transparency mask
+	scale   float64                   // Document scale factor
+	dpi     float64                   // Dots-per-inch found from image file (png only)
+	icc     []byte                    // Uncompressed ICC color profile, if any
+	dec     string                    // Decode array, if any
+	ori     int                       // EXIF orientation (jpg only); 0 or 1 if upright
+	stencil bool                      // Image is a stencil mask
+	mask    *ImageInfoType            // Image attached as mask, if any
+	alts    []imageAltType            // Alternate images
+	open    func() (io.Reader, error) // Source of a deferred image not yet loaded
+	src     func() (io.Reader, error) // Source of data passed through from a file, if any
+	srcLen  int64                     // Length of the data of src
+	srcSum  []byte                    // SHA-1 checksum of the data of src
+	opt     ImageOptions              // Options for loading a deferred image
+	placed  bool                      // Image has been placed in the document
+	rsRatio float64                   // Ratio of the pixels of a resampled image to those of its file
+	i       string                    // SHA-1 checksum of the above values.
+}
+
+// imageAltType is an alternate of an image, optionally the default for
+// printing
+type imageAltType struct {
+	img   *ImageInfoType
+	print bool
 }
 
 func generateImageID(info *ImageInfoType) (string, error) {
+	if info.src != nil {
+		// Data passed through from a file is identified by its checksum
+		return fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("source:%x:%s:%d", info.srcSum, info.dec, info.ori)))), nil
+	}
 	b, err := info.GobEncode()
 	return fmt.Sprintf("%x", sha1.Sum(b)), err
 }
 
 // GobEncode encodes the receiving image to a byte slice.
 func (info *ImageInfoType) GobEncode() (buf []byte, err error) {
-	fields := []interface{}{info.data, info.smask, info.n, info.w, info.h, info.cs,
-		info.pal, info.bpc, info.f, info.dp, info.trns, info.scale, info.dpi}
+	data := info.data
+	if info.src != nil {
+		if data, err = info.sourceData(); err != nil {
+			return
+		}
+	}
+	fields := []interface{}{data, info.smask, info.n, info.w, info.h, info.cs,
+		info.pal, info.bpc, info.f, info.dp, info.trns, info.scale, info.dpi, info.icc, info.dec, info.ori,
+		info.stencil}
 	w := new(bytes.Buffer)
 	encoder := gob.NewEncoder(w)
 	for j := 0; j < len(fields) && err == nil; j++ {
@@ -205,7 +262,8 @@ func (info *ImageInfoType) GobEncode() (buf []byte, err error) {
 // the receiving image.
 func (info *ImageInfoType) GobDecode(buf []byte) (err error) {
 	fields := []interface{}{&info.data, &info.smask, &info.n, &info.w, &info.h,
-		&info.cs, &info.pal, &info.bpc, &info.f, &info.dp, &info.trns, &info.scale, &info.dpi}
+		&info.cs, &info.pal, &info.bpc, &info.f, &info.dp, &info.trns, &info.scale, &info.dpi,
+		&info.icc, &info.dec, &info.ori, &info.stencil}
 	r := bytes.NewBuffer(buf)
 	decoder := gob.NewDecoder(r)
 	for j := 0; j < len(fields) && err == nil; j++ {
@@ -245,12 +303,23 @@ func (info *ImageInfoType) Extent() (wd, ht float64) {
 
 // Width returns the width of the image in the units of the Fpdf object.
 func (info *ImageInfoType) Width() float64 {
-	return info.w / (info.scale * info.dpi / 72)
+	w, _ := info.size()
+	return w / (info.scale * info.dpi / 72)
 }
 
 // Height returns the height of the image in the units of the Fpdf object.
 func (info *ImageInfoType) Height() float64 {
-	return info.h / (info.scale * info.dpi / 72)
+	_, h := info.size()
+	return h / (info.scale * info.dpi / 72)
+}
+
+// size returns the width and height of the image in pixels as displayed,
+// that is, after any rotation called for by its EXIF orientation.
+func (info *ImageInfoType) size() (w, h float64) {
+	if info.ori >= 5 {
+		return info.h, info.w
+	}
+	return info.w, info.h
 }
 
 // SetDpi sets the dots per inch for an image. PNG images MAY have their dpi
@@ -548,6 +617,7 @@ type Fpdf struct {
 	fontSize         float64                    // current font size in user unit
 	ws               float64                    // word spacing
 	images           map[string]*ImageInfoType  // array of used images
+	imageData        map[string]*ImageInfoType  // first image registered with each content checksum
 	aliasMap         map[string]string          // map of alias->replacement
 	pageLinks        [][]linkType               // pageLinks[page][link], both 1-based
 	links            []intLinkType              // array of internal links
@@ -582,17 +652,29 @@ type Fpdf struct {
 	joinStyle        int                        // line segment join style: miter 0, round 1, bevel 2
 	dashArray        []float64                  // dash array
 	dashPhase        float64                    // dash phase
+	lineDecoration   LineDecorationType         // Arrowheads and markers of lines
 	blendList        []blendModeType            // slice[idx] of alpha transparency modes, 1-based
 	blendMap         map[string]int             // map into blendList
 	blendMode        string                     // current blend mode
 	alpha            float64                    // current transpacency
 	gradientList     []gradientType             // slice[idx] of gradient records
+	patternList      []patternType              // slice[idx] of image and hatch tiling patterns, 1-based
+	groupList        []transparencyGroupType    // slice[idx] of transparency groups, 1-based
+	groupNest        []transparencyGroupNest    // Transparency groups being collected
+	softMaskList     []softMaskType             // slice[idx] of soft mask graphics states, 1-based
+	stateStack       []graphicsStateType        // Graphics states saved by PushState()
+	ctm              TransformMatrix            // Current transformation, in points
+	ctmStack         []TransformMatrix          // Transformations saved with the graphics state
+	measuring        bool                       // Content is measured by MeasureBounds() rather than output
+	symbolList       []symbolType               // slice[idx] of symbols, 1-based
+	symbolMap        map[string]int             // Positions of the symbols in symbolList by name
 	clipNest         int                        // Number of active clipping contexts
 	transformNest    int                        // Number of active transformation contexts
 	err              error                      // Set if error occurs during life cycle of instance
 	protect          protectType                // document protection structure
 	layer            layerRecType               // manages optional layers in document
 	catalogSort      bool                       // sort resource catalogs in document
+	imagePassThrough bool                       // copy the data of JPEG files when the document is written
 	nJs              int                        // JavaScript object number
 	javascript       *string                    // JavaScript code to include in the PDF
 	colorFlag        bool                       // indicates whether fill and text colors are different
@@ -602,6 +684,32 @@ type Fpdf struct {
 	}
 	spotColorMap           map[string]spotColorType // Map of named ink-based colors
 	userUnderlineThickness float64                  // A custom user underline thickness multiplier.
+	imageCache             *ImageCache              // Cache of parsed images shared with other documents
+	fontCache              FontCache                // Cache of parsed UTF-8 font metadata shared with other documents
+	fontEmbeddingEnforced  bool                     // Enforce the embedding permissions of UTF-8 fonts
+	fontSpools             []*os.File               // Temporary files of fonts copied from readers
+	hyphenator             *HyphenatorType          // Hyphenator of the words of text, nil if disabled
+	orphans, widows        int                      // Minimum lines of a paragraph at the bottom and top of a page
+	columns                columnsType              // Column layout
+	tabStops               []TabStopType            // Tab stops of text, in increasing order of position
+	lineHt                 float64                  // Height of lines of text when not given, if not relative
+	lineHtFactor           float64                  // Height of lines of text relative to the font size
+	charSpacing            float64                  // Space added after each character of text (Tc)
+	wordSpacing            float64                  // Space added to each space of text (Tw)
+	cs                     float64                  // Character spacing of justified text
+	letterJustify          bool                     // Justify text by spacing its characters too
+	maxWordSpace           float64                  // Space added to spaces of justified text before characters
+	textRise               float64                  // Rise of the baseline of text (Ts)
+	textMode               int                      // Text rendering mode (Tr)
+	markup                 bool                     // Interpret inline markup in text
+	ellipsis               string                   // Mark of truncated text
+	fontFallbacks          []string                 // Families used for characters the current font lacks
+	fallbackWidths         map[string][]int         // Character widths of fonts combined with their fallbacks
+	textShaping            bool                     // Shape Arabic, Devanagari and Thai text
+	baseDirStr             string                   // Base direction of bidirectional text: "L", "R", "A" or "" for none
+	syntheticStyle         bool                     // Synthesize the styles that font families lack
+	fontSynthStr           string                   // Synthesized styles of the current font: "B", "I", "BI" or ""
+	textTransform          string                   // Transform of the case of text, one of the TextTransform values
 }
 
 type encType struct {
@@ -670,6 +778,10 @@ type FontDescType struct {
 	// The vertical coordinate of the top of flat capital letters,
 	// measured from the baseline (for example "H").
 	CapHeight int
+	// The vertical coordinate of the top of flat nonascending lowercase
+	// letters, measured from the baseline (for example "x"). (Default
+	// value: 0.)
+	XHeight int
 	// A collection of flags defining various characteristics of the
 	// font. (See the FontFlag* constants.)
 	Flags int
@@ -713,6 +825,20 @@ type fontDefType struct {
 	i            string        // 1-based position in font list, set by font loader, not this program
 	utf8File     *utf8FontFile // UTF-8 font
 	usedRunes    map[int]int   // Array of used runes
+	cids         map[int]int   // CIDs of the used runes outside the Basic Multilingual Plane
+	features     *fontFeatures // Typographic features of the UTF-8 font
+	embedFull    bool          // UTF-8 font embedded with all its characters rather than as a subset
+}
+
+// fontFeatures holds the typographic features of a UTF-8 font that are
+// applied to text. It is shared by the copies of the font definition.
+type fontFeatures struct {
+	ligatures       bool                  // Standard ligatures enabled
+	ligatureLookups []map[rune][]ligature // Ligatures by first character, by lookup, nil until loaded
+	smallCaps       map[rune]rune         // Codes of the small capitals of characters, nil until loaded
+	syntheticCaps   map[rune]rune         // Capitals shown at a reduced size for the codes of synthetic small capitals
+	colorGlyphs     map[int]*colorGlyph   // Color glyphs by glyph, nil until loaded
+	shaper          *complexShaper        // Shaper of Devanagari and Thai text, nil until loaded
 }
 
 // generateFontID generates a font Id from the font definition
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"sort"
+	"strings"
+)
+
+// SetEllipsis sets the string that marks the end of text truncated by
+// CellFit() with the CellFitEllipsis mode and by MultiCellMaxLines(). The
+// string is in the encoding of the font of the text, as other text is. If
+// ellipsisStr is empty, the default, the horizontal ellipsis character ("…")
+// is used, in UTF-8 for UTF-8 fonts and in the cp1252 encoding of the core
+// fonts for other fonts.
+func (f *Fpdf) SetEllipsis(ellipsisStr string) {
+	f.ellipsis = ellipsisStr
+}
+
+// GetEllipsis returns the string set with SetEllipsis().
+func (f *Fpdf) GetEllipsis() string {
+	return f.ellipsis
+}
+
+// ellipsisStr returns the ellipsis of text in the current font.
+func (f *Fpdf) ellipsisStr() string {
+	switch {
+	case f.ellipsis != "":
+		return f.ellipsis
+	case f.isCurrentUTF8:
+		return "…"
+	}
+	return "\x85"
+}
+
+// truncate returns txtStr, or as many of its first characters as fit in the
+// width w followed by the ellipsis if it does not fit. Spaces before the
+// ellipsis are dropped.
+func (f *Fpdf) truncate(txtStr string, w float64) string {
+	if f.GetSpacedStringWidth(txtStr) <= w {
+		return txtStr
+	}
+	ellipsis := f.ellipsisStr()
+	w -= f.GetSpacedStringWidth(ellipsis)
+	// The text is truncated between grapheme clusters
+	runes := f.textRunes(txtStr)
+	var ends []int
+	for k, bound := range graphemeBounds(runes) {
+		if bound && k > 0 {
+			ends = append(ends, k)
+		}
+	}
+	n := sort.Search(len(ends), func(n int) bool {
+		return f.GetSpacedStringWidth(f.runesString(runes[:ends[n]])) > w
+	})
+	end := 0
+	if n > 0 {
+		end = ends[n-1]
+	}
+	return strings.TrimRight(f.runesString(runes[:end]), " ") + ellipsis
+}
+
+// MultiCellMaxLines prints text as MultiCell() does, in at most maxLines
+// lines. If the text does not fit in them, the last line ends with as much of
+// the text that follows as fits followed by the ellipsis set with
+// SetEllipsis(), so that text of variable length, such as the descriptions of
+// the rows of a report, takes a bounded height. A value of zero or less for
+// maxLines leaves the number of lines unlimited.
+//
+// See MultiCell() for details about the other arguments.
+func (f *Fpdf) MultiCellMaxLines(w, h float64, txtStr, borderStr, alignStr string, fill bool, maxLines int) {
+	if f.err != nil {
+		return
+	}
+	if w == 0 {
+		w = f.w - f.rMargin - f.x
+	}
+	lines := f.BreakLines(txtStr, w)
+	if maxLines <= 0 || len(lines) <= maxLines || f.markupText(txtStr) {
+		f.MultiCell(w, h, txtStr, borderStr, alignStr, fill)
+		return
+	}
+	last := lines[maxLines-1].Str
+	if lines[maxLines-1].Hyphenated {
+		last = strings.TrimSuffix(last, "-")
+	} else {
+		last += " "
+	}
+	last += lines[maxLines].Str
+	f.MultiCell(w, h, joinTextLines(lines[:maxLines-1])+f.truncate(last, w-2*f.cMargin),
+		borderStr, alignStr, fill)
+}
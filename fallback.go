@@ -0,0 +1,207 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SetFontFallbacks specifies the font families that are used, in order, for
+// characters that the current font lacks. This allows text that mixes
+// scripts, such as Chinese within Latin text, to be printed without the
+// missing glyphs of the current font showing as empty boxes.
+//
+// Fallbacks only apply when the current font is a UTF-8 font, and only UTF-8
+// fonts are used as fallbacks. A fallback family is used in the style of the
+// current font if it has been added in that style, and in its regular style
+// otherwise. Families that have not been added are skipped. The fallbacks
+// are taken into account by text output methods such as Cell(), MultiCell(),
+// Write() and Text() and by GetStringWidth() and SplitText(). Call this method
+// without arguments to remove the fallbacks.
+func (f *Fpdf) SetFontFallbacks(families ...string) {
+	f.fontFallbacks = f.fontFallbacks[:0]
+	for _, family := range families {
+		f.fontFallbacks = append(f.fontFallbacks, strings.ToLower(fontFamilyEscape(family)))
+	}
+	f.fallbackWidths = nil
+}
+
+// fallbackFonts returns the current font followed by the fallback fonts that
+// are available for it.
+func (f *Fpdf) fallbackFonts() (fonts []fontDefType) {
+	fonts = append(fonts, f.currentFont)
+	if !f.isCurrentUTF8 {
+		return
+	}
+	for _, family := range f.fontFallbacks {
+		font, ok := f.fonts[family+f.fontStyle]
+		if !ok {
+			font, ok = f.fonts[family]
+		}
+		if ok && font.Tp == "UTF8" && font.i != f.currentFont.i {
+			fonts = append(fonts, font)
+		}
+	}
+	return
+}
+
+func hasGlyph(font fontDefType, r rune) bool {
+	return r > 0 && int(r) < len(font.Cw) && font.Cw[r] > 0
+}
+
+// charWidths returns the character widths of the current font in which the
+// widths of the characters it lacks are taken from the fallback fonts.
+func (f *Fpdf) charWidths() []int {
+	fonts := f.fallbackFonts()
+	if len(fonts) < 2 {
+		return f.currentFont.Cw
+	}
+	var key strings.Builder
+	for _, font := range fonts {
+		key.WriteString(font.i + " ")
+	}
+	cw, ok := f.fallbackWidths[key.String()]
+	if ok {
+		return cw
+	}
+	// The table is as long as the longest one, so that fallbacks can provide
+	// characters outside the Basic Multilingual Plane
+	size := 0
+	for _, font := range fonts {
+		if len(font.Cw) > size {
+			size = len(font.Cw)
+		}
+	}
+	cw = make([]int, size)
+	copy(cw, f.currentFont.Cw)
+	for r := range cw {
+		if r == 0 || cw[r] > 0 {
+			continue
+		}
+		for _, font := range fonts[1:] {
+			if hasGlyph(font, rune(r)) {
+				cw[r] = font.Cw[r]
+				break
+			}
+		}
+	}
+	if f.fallbackWidths == nil {
+		f.fallbackWidths = make(map[string][]int)
+	}
+	f.fallbackWidths[key.String()] = cw
+	return cw
+}
+
+// fallbackIndices returns the index in fonts of the font that shows each of
+// runes. Spaces and characters that no font has are shown with the font of
+// the preceding character.
+func fallbackIndices(fonts []fontDefType, runes []rune) []int {
+	idx := make([]int, len(runes))
+	for j, r := range runes {
+		if hasGlyph(fonts[0], r) {
+			continue
+		}
+		if j > 0 {
+			idx[j] = idx[j-1]
+		}
+		if !hasGlyph(fonts[idx[j]], r) && !unicode.IsSpace(r) {
+			for n := 1; n < len(fonts); n++ {
+				if hasGlyph(fonts[n], r) {
+					idx[j] = n
+					break
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// fallbackText returns the operators that show txtStr within a text object
+// when some of its characters are shown with fallback fonts, as synthetic
+// small capitals or as color glyphs. Each run of characters is shown with its
+// own Tj operator, synthetic small capitals as capitals at a reduced size and
+// color glyphs as invisible text, over which colorGlyphs() draws them. The
+// current font and text rendering mode are restored at the end. The
+// characters are recorded as used by the fonts that show them. False is
+// returned if txtStr has none of these characters, in which case nothing is
+// recorded.
+func (f *Fpdf) fallbackText(txtStr string) (string, bool) {
+	if !f.isCurrentUTF8 {
+		return "", false
+	}
+	fonts := f.fallbackFonts()
+	caps := f.currentFont.features.syntheticCaps
+	colors := f.haveColorGlyphs(fonts)
+	if len(fonts) < 2 && len(caps) == 0 && !colors {
+		return "", false
+	}
+	runes := []rune(txtStr)
+	small := make([]bool, len(runes))
+	for j, r := range runes {
+		if upper, ok := caps[r]; ok {
+			runes[j], small[j] = upper, true
+		}
+	}
+	idx := fallbackIndices(fonts, runes)
+	color := make([]bool, len(runes))
+	special := false
+	for j, r := range runes {
+		color[j] = colors && f.colorGlyph(fonts[idx[j]], r) != nil
+		special = special || idx[j] > 0 || small[j] || color[j]
+	}
+	if !special {
+		return "", false
+	}
+	// Text rendering mode of visible text
+	mode := f.syntheticMode()
+	var s fmtBuffer
+	cur, curSmall, curColor := 0, false, false
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && idx[end] == idx[start] && small[end] == small[start] && color[end] == color[start] {
+			end++
+		}
+		font := fonts[idx[start]]
+		if idx[start] != cur || small[start] != curSmall {
+			cur, curSmall = idx[start], small[start]
+			size := f.fontSizePt
+			if curSmall {
+				size *= smallCapsScale
+			}
+			s.printf("/F%s %.2f Tf ", font.i, size)
+		}
+		if color[start] != curColor {
+			curColor = color[start]
+			if curColor {
+				s.printf("3 Tr ")
+			} else {
+				s.printf("%d Tr ", mode)
+			}
+		}
+		s.printf("(%s)Tj ", f.escape(f.utf8toCID(font, string(runes[start:end]))))
+		start = end
+	}
+	if cur != 0 || curSmall {
+		s.printf("/F%s %.2f Tf ", f.currentFont.i, f.fontSizePt)
+	}
+	if curColor {
+		s.printf("%d Tr ", mode)
+	}
+	str := s.String()
+	return str[:len(str)-1], true
+}
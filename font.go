@@ -46,23 +46,35 @@ func baseNoExt(fileStr string) string {
 	return str
 }
 
+// loadMap reads an encoding file. If the file does not exist, the code page
+// map embedded in the package with the same base name is read instead.
 func loadMap(encodingFileStr string) (encList encListType, err error) {
 	// printf("Encoding file string [%s]\n", encodingFileStr)
+	var r io.Reader
 	var f *os.File
 	// f, err = os.Open(encodingFilepath(encodingFileStr))
 	f, err = os.Open(encodingFileStr)
 	if err == nil {
 		defer f.Close()
+		r = f
+	} else if str, ok := embeddedMapList[baseNoExt(encodingFileStr)]; ok && os.IsNotExist(err) {
+		r, err = strings.NewReader(str), nil
+	}
+	if err == nil {
 		for j := range encList {
 			encList[j].uv = -1
 			encList[j].name = ".notdef"
 		}
-		scanner := bufio.NewScanner(f)
+		scanner := bufio.NewScanner(r)
 		var enc encType
 		var pos int
 		for scanner.Scan() {
+			lineStr := strings.TrimSpace(scanner.Text())
+			if lineStr == "" {
+				continue
+			}
 			// "!3F U+003F question"
-			_, err = fmt.Sscanf(scanner.Text(), "!%x U+%x %s", &pos, &enc.uv, &enc.name)
+			_, err = fmt.Sscanf(lineStr, "!%x U+%x %s", &pos, &enc.uv, &enc.name)
 			if err == nil {
 				if pos < 256 {
 					encList[pos] = enc
@@ -118,6 +130,7 @@ func getInfoFromTrueType(fileStr string, msgWriter io.Writer, embed bool, encLis
 	// printf("FontBBox\n")
 	// dump(info.Desc.FontBBox)
 	info.Desc.CapHeight = round(k * float64(ttf.CapHeight))
+	info.Desc.XHeight = round(k * float64(ttf.XHeight))
 	info.Desc.MissingWidth = round(k * float64(ttf.Widths[0]))
 	var wd int
 	for j := 0; j < len(info.Widths); j++ {
@@ -250,6 +263,8 @@ func getInfoFromType1(fileStr string, msgWriter io.Writer, embed bool, encList e
 				}
 			case "CapHeight":
 				info.Desc.CapHeight, err = strconv.Atoi(fields[1])
+			case "XHeight":
+				info.Desc.XHeight, err = strconv.Atoi(fields[1])
 			case "StdVW":
 				info.Desc.StemV, err = strconv.Atoi(fields[1])
 			}
@@ -394,7 +409,10 @@ func makeDefinitionFile(fileStr, tpStr, encodingFileStr string, embed bool, encL
 // present.
 //
 // encodingFileStr is the name of the encoding file that corresponds to the
-// font.
+// font. The code page maps packaged in the font directory of gofpdf are
+// embedded in the package, and are used if the named file does not exist, so
+// that for example "cp1251.map" or "koi8-r" designate those encodings
+// wherever the font directory is.
 //
 // dstDirStr is the name of the directory in which to save the definition file
 // and, if embed is true, the compressed font file.
@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FontCache stores the metadata parsed from UTF-8 fonts, such as their
+// metrics and character widths, so that fonts added by other documents or by
+// other runs of a program need not be parsed again. The data stored for a
+// font is opaque and is keyed by a string derived from the content of the
+// font file, so that a changed font is parsed anew. A cache is attached to a
+// document with SetFontCache(). Implementations used by concurrent documents
+// must be safe for concurrent use.
+type FontCache interface {
+	// Get returns the data stored with key, and false if there is none.
+	Get(key string) ([]byte, bool)
+	// Put stores data with key. A cache may fail to store the data, in
+	// which case the font is parsed again the next time it is added.
+	Put(key string, data []byte)
+}
+
+// fontCacheVersion is the version of the format of cached font metadata,
+// which is part of the cache keys.
+const fontCacheVersion = 2
+
+// fontCacheEntry is the metadata of a UTF-8 font held by a font cache.
+type fontCacheEntry struct {
+	UnitsPerEm         int
+	Ascent             int
+	Descent            int
+	Bbox               fontBoxType
+	CapHeight          int
+	XHeight            int
+	StemV              int
+	ItalicAngle        int
+	Flags              int
+	EmbeddingFlags     int
+	UnderlinePosition  float64
+	UnderlineThickness float64
+	CharWidths         []int
+	DefaultWidth       float64
+}
+
+// fontCacheDir is a FontCache that stores font metadata as files of a
+// directory.
+type fontCacheDir string
+
+// NewFontCacheDir returns a FontCache that stores font metadata as files of
+// the directory dir, which is created on first use if it does not exist.
+// Files that cannot be read or written are ignored, the fonts concerned being
+// parsed instead. The directory may be shared by concurrent documents and
+// processes.
+func NewFontCacheDir(dir string) FontCache {
+	return fontCacheDir(dir)
+}
+
+func (dir fontCacheDir) Get(key string) ([]byte, bool) {
+	data, err := ioutil.ReadFile(filepath.Join(string(dir), key+".json"))
+	return data, err == nil
+}
+
+func (dir fontCacheDir) Put(key string, data []byte) {
+	if os.MkdirAll(string(dir), 0755) != nil {
+		return
+	}
+	// Write to a temporary file first so that readers never see a partial
+	// file
+	file, err := ioutil.TempFile(string(dir), key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(string(dir), key+".json"))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+}
+
+// SetFontCache attaches the specified font cache to the document. UTF-8
+// fonts added subsequently take their metadata from the cache if present, and
+// add it otherwise. The font files themselves are still read, since their
+// glyphs are embedded in the document. Pass nil to detach the cache.
+func (f *Fpdf) SetFontCache(cache FontCache) {
+	f.fontCache = cache
+}
+
+// parseUTF8Font parses the TrueType or OpenType font read by fr, taking its
+// metadata from the attached font cache if possible.
+func (f *Fpdf) parseUTF8Font(fr *fileReader) (*utf8FontFile, error) {
+	utf8File := newUTF8Font(fr)
+	if f.fontCache == nil {
+		return utf8File, utf8File.parseFile()
+	}
+	sum, err := fr.sum()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("utf8font-%d-%x", fontCacheVersion, sum)
+	var entry fontCacheEntry
+	if data, ok := f.fontCache.Get(key); ok && json.Unmarshal(data, &entry) == nil && len(entry.CharWidths) > 0 {
+		utf8File.parseCachedFile(entry)
+		return utf8File, fr.err
+	}
+	if err := utf8File.parseFile(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(fontCacheEntry{
+		UnitsPerEm:         utf8File.fontElementSize,
+		Ascent:             utf8File.Ascent,
+		Descent:            utf8File.Descent,
+		Bbox:               utf8File.Bbox,
+		CapHeight:          utf8File.CapHeight,
+		XHeight:            utf8File.XHeight,
+		StemV:              utf8File.StemV,
+		ItalicAngle:        utf8File.ItalicAngle,
+		Flags:              utf8File.Flags,
+		EmbeddingFlags:     utf8File.EmbeddingFlags,
+		UnderlinePosition:  utf8File.UnderlinePosition,
+		UnderlineThickness: utf8File.UnderlineThickness,
+		CharWidths:         utf8File.CharWidths,
+		DefaultWidth:       utf8File.DefaultWidth,
+	})
+	if err == nil {
+		f.fontCache.Put(key, data)
+	}
+	return utf8File, nil
+}
+
+// parseCachedFile reads the table directory of the font and takes the rest
+// of its metadata from entry, as parseFile() would have found it.
+func (utf *utf8FontFile) parseCachedFile(entry fontCacheEntry) {
+	utf.fileReader.readerPosition = 4
+	utf.symbolPosition = make([]int, 0)
+	utf.charSymbolDictionary = make(map[int]int)
+	utf.outTablesData = make(map[string][]byte)
+	utf.generateTableDescriptions()
+	utf.fontElementSize = entry.UnitsPerEm
+	utf.Ascent = entry.Ascent
+	utf.Descent = entry.Descent
+	utf.Bbox = entry.Bbox
+	utf.CapHeight = entry.CapHeight
+	utf.XHeight = entry.XHeight
+	utf.StemV = entry.StemV
+	utf.ItalicAngle = entry.ItalicAngle
+	utf.Flags = entry.Flags
+	utf.EmbeddingFlags = entry.EmbeddingFlags
+	utf.UnderlinePosition = entry.UnderlinePosition
+	utf.UnderlineThickness = entry.UnderlineThickness
+	utf.CharWidths = entry.CharWidths
+	utf.DefaultWidth = entry.DefaultWidth
+}
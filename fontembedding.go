@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"fmt"
+)
+
+// Font embedding permissions, the fsType flags of the OS/2 table of a
+// TrueType or OpenType font, as returned by FontEmbeddingPermissions().
+const (
+	// FontEmbeddingInstallable allows the font to be embedded and installed
+	// permanently.
+	FontEmbeddingInstallable = 0x0000
+	// FontEmbeddingRestricted forbids the font to be embedded.
+	FontEmbeddingRestricted = 0x0002
+	// FontEmbeddingPreviewPrint allows the font to be embedded in documents
+	// that are opened read-only.
+	FontEmbeddingPreviewPrint = 0x0004
+	// FontEmbeddingEditable allows the font to be embedded in documents
+	// that are edited.
+	FontEmbeddingEditable = 0x0008
+	// FontEmbeddingNoSubsetting forbids the font to be embedded as a subset.
+	FontEmbeddingNoSubsetting = 0x0100
+	// FontEmbeddingBitmapOnly only allows the bitmaps of the font to be
+	// embedded.
+	FontEmbeddingBitmapOnly = 0x0200
+)
+
+// FontEmbeddingPermissions returns the embedding permissions of the UTF-8
+// font with the specified family and style, a combination of the
+// FontEmbedding* flags. FontEmbeddingInstallable is returned for other fonts,
+// which MakeFont() only embeds if their license allows it, and -1 if no font
+// has been added with familyStr and styleStr. See AddFont() for details about
+// familyStr and styleStr.
+func (f *Fpdf) FontEmbeddingPermissions(familyStr, styleStr string) int {
+	font, ok := f.fonts[getFontKey(fontFamilyEscape(familyStr), styleStr)]
+	switch {
+	case !ok:
+		return -1
+	case font.Tp != "UTF8":
+		return FontEmbeddingInstallable
+	}
+	return font.utf8File.EmbeddingFlags
+}
+
+// SetFontEmbeddingEnforced determines whether the embedding permissions of
+// UTF-8 fonts are enforced, which they are not by default. When they are,
+// adding a font that may not be embedded, or whose bitmaps only may be
+// embedded, sets an error, and a font that may not be subset is embedded with
+// the glyphs of all the characters of the Basic Multilingual Plane that it
+// maps rather than only those used by the document. The setting applies to
+// fonts added subsequently.
+func (f *Fpdf) SetFontEmbeddingEnforced(enforced bool) {
+	f.fontEmbeddingEnforced = enforced
+}
+
+// fontEmbedding returns an error if enforced embedding permissions forbid
+// the embedding of the UTF-8 font, and whether it is to be embedded in full.
+func (f *Fpdf) fontEmbedding(utf8File *utf8FontFile) (full bool, err error) {
+	if !f.fontEmbeddingEnforced {
+		return
+	}
+	flags := utf8File.EmbeddingFlags
+	if flags&0x000E == FontEmbeddingRestricted || flags&FontEmbeddingBitmapOnly != 0 {
+		return false, fmt.Errorf("font license does not allow embedding")
+	}
+	return flags&FontEmbeddingNoSubsetting != 0, nil
+}
+
+// embeddedRunes returns the characters whose glyphs are embedded for the
+// UTF-8 font, which are the used characters unless the font is embedded in
+// full.
+func (font *fontDefType) embeddedRunes() map[int]int {
+	if !font.embedFull {
+		return font.usedRunes
+	}
+	utf := font.utf8File
+	if len(utf.charSymbolDictionary) == 0 {
+		utf.generateCMAP()
+	}
+	runes := make(map[int]int, len(utf.charSymbolDictionary)+len(font.usedRunes))
+	for r := range utf.charSymbolDictionary {
+		if r > 0 && r <= 0xFFFF {
+			runes[r] = r
+		}
+	}
+	for r, v := range font.usedRunes {
+		runes[r] = v
+	}
+	return runes
+}
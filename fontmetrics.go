@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// FontMetricsType holds the metrics of a font at a particular size, in the
+// unit of measure of the document. Vertical metrics are measured upwards from
+// the baseline, so that the descent and usually the underline position are
+// negative. See FontMetrics().
+type FontMetricsType struct {
+	// The size of the font.
+	Size float64
+	// The height above the baseline reached by the glyphs of the font.
+	Ascent float64
+	// The depth below the baseline reached by the glyphs of the font, a
+	// negative number.
+	Descent float64
+	// The height of flat capital letters such as "H".
+	CapHeight float64
+	// The height of flat lowercase letters without ascenders such as "x",
+	// or zero if it is unknown.
+	XHeight float64
+	// The position of the middle of the underline.
+	UnderlinePosition float64
+	// The thickness of the underline.
+	UnderlineThickness float64
+
+	widths       []int
+	missingWidth int
+	utf8         bool
+}
+
+// Advance returns the advance width of the character r, the distance by
+// which the current point moves when r is printed. For fonts other than
+// UTF-8 fonts, r is the code of the character in the encoding of the font.
+// Characters that the font lacks have the width that they are measured with
+// by GetStringWidth().
+func (m FontMetricsType) Advance(r rune) float64 {
+	w := 0
+	switch {
+	case !m.utf8:
+		if r >= 0 && int(r) < len(m.widths) {
+			w = m.widths[r]
+		}
+	case r >= 0 && int(r) < len(m.widths) && m.widths[r] > 0:
+		if m.widths[r] != 65535 {
+			w = m.widths[r]
+		}
+	case m.missingWidth != 0:
+		w = m.missingWidth
+	default:
+		w = 500
+	}
+	return float64(w) * m.Size / 1000
+}
+
+// coreFontMetrics holds the ascent, descent, cap height and x-height of the
+// core fonts, whose definitions lack font descriptors. ZapfDingbats has its
+// bounding box as ascent, descent and cap height.
+var coreFontMetrics = map[string][4]int{
+	"Courier":               {629, -157, 562, 426},
+	"Courier-Bold":          {629, -157, 562, 439},
+	"Courier-BoldOblique":   {629, -157, 562, 439},
+	"Courier-Oblique":       {629, -157, 562, 426},
+	"Helvetica":             {718, -207, 718, 523},
+	"Helvetica-Bold":        {718, -207, 718, 532},
+	"Helvetica-BoldOblique": {718, -207, 718, 532},
+	"Helvetica-Oblique":     {718, -207, 718, 523},
+	"Times-Roman":           {683, -217, 662, 450},
+	"Times-Bold":            {683, -217, 676, 461},
+	"Times-BoldItalic":      {683, -217, 669, 462},
+	"Times-Italic":          {683, -217, 653, 441},
+	"ZapfDingbats":          {820, -143, 820, 0},
+}
+
+// FontMetrics returns the metrics of a font at the current font size, which
+// can be used for example to place text on a baseline or to align it on the
+// height of its capitals. If familyStr is empty, the metrics of the current
+// font are returned. Otherwise the font must have been added or selected
+// with SetFont(), and zero metrics are returned if it has not. See AddFont()
+// for details about familyStr and styleStr.
+func (f *Fpdf) FontMetrics(familyStr, styleStr string) FontMetricsType {
+	font := f.currentFont
+	if familyStr != "" {
+		font = f.fonts[getFontKey(fontFamilyEscape(familyStr), styleStr)]
+	}
+	size := f.fontSize
+	k := size / 1000
+	desc := font.Desc
+	if core, ok := coreFontMetrics[font.Name]; ok && font.Tp == "Core" {
+		desc.Ascent, desc.Descent, desc.CapHeight, desc.XHeight = core[0], core[1], core[2], core[3]
+	}
+	return FontMetricsType{
+		Size:               size,
+		Ascent:             float64(desc.Ascent) * k,
+		Descent:            float64(desc.Descent) * k,
+		CapHeight:          float64(desc.CapHeight) * k,
+		XHeight:            float64(desc.XHeight) * k,
+		UnderlinePosition:  float64(font.Up) * k,
+		UnderlineThickness: float64(font.Ut) * k,
+		widths:             font.Cw,
+		missingWidth:       desc.MissingWidth,
+		utf8:               font.Tp == "UTF8",
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"sort"
+)
+
+// FontUsageType holds the characters and glyphs of a UTF-8 font that a
+// document has printed so far. See FontUsage().
+type FontUsageType struct {
+	// The characters printed with the font, in increasing order. The
+	// characters of a ligature are included even though the ligature is
+	// printed in their place.
+	Runes []rune
+	// The indices of the glyphs of the font that the printed text shows, in
+	// increasing order. Glyphs that only shaping selects, such as ligatures
+	// and small capitals, are included. The components of composite glyphs
+	// and the .notdef glyph shown for characters that the font lacks are
+	// not.
+	Glyphs []int
+}
+
+// FontUsage returns the characters and glyphs of the UTF-8 font with the
+// specified family and style that the document has printed so far, with any
+// of the text output methods such as Cell(), Write(), MultiCell() and Text(),
+// so that the font can be subset to exactly the glyphs it needs. The font
+// embedded in the document is subset in this way when the document is
+// output. Characters printed with a fallback font are recorded as used by the
+// fallback font. Text that is only measured, for example by
+// GetStringWidth(), is not recorded.
+//
+// A zero value is returned if no UTF-8 font has been added with familyStr
+// and styleStr. See AddFont() for details about familyStr and styleStr.
+func (f *Fpdf) FontUsage(familyStr, styleStr string) (usage FontUsageType) {
+	font, ok := f.fonts[getFontKey(fontFamilyEscape(familyStr), styleStr)]
+	if !ok || font.Tp != "UTF8" {
+		return
+	}
+	utf := font.utf8File
+	if len(utf.charSymbolDictionary) == 0 && utf.generateCMAP() == nil {
+		f.SetErrorf("font does not have cmap for Unicode")
+		return
+	}
+	runes := make(map[rune]bool)
+	glyphs := make(map[int]bool)
+	for code, r := range font.usedRunes {
+		// The first characters are reserved for the subset and only used
+		// once recorded with their own code
+		if code == 0 || code != r {
+			continue
+		}
+		symbol, ok := utf.assignedSymbols[code]
+		if ok {
+			for _, c := range utf.assignedChars[code] {
+				runes[c] = true
+			}
+		} else {
+			runes[rune(code)] = true
+			symbol = utf.charSymbolDictionary[code]
+		}
+		if symbol > 0 {
+			glyphs[symbol] = true
+		}
+	}
+	for r := range runes {
+		usage.Runes = append(usage.Runes, r)
+	}
+	sort.Slice(usage.Runes, func(i, j int) bool { return usage.Runes[i] < usage.Runes[j] })
+	for symbol := range glyphs {
+		usage.Glyphs = append(usage.Glyphs, symbol)
+	}
+	sort.Ints(usage.Glyphs)
+	return
+}
@@ -23,13 +23,12 @@ package gofpdf
 
 import (
 	"bytes"
+	"crypto/sha1"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"image"
-	"image/color"
 	"image/gif"
-	"image/jpeg"
 	"image/png"
 	"io"
 	"io/ioutil"
@@ -91,6 +90,7 @@ func fpdfNew(orientationStr, unitStr, sizeStr, fontDirStr string, size SizeType)
 	f.importedTplObjs = make(map[string]string)
 	f.importedTplIDs = make(map[string]int, 0)
 	f.images = make(map[string]*ImageInfoType)
+	f.imageData = make(map[string]*ImageInfoType)
 	f.pageLinks = make([][]linkType, 0, 8)
 	f.pageLinks = append(f.pageLinks, make([]linkType, 0, 0)) // pageLinks[0] is unused (1-based)
 	f.links = make([]intLinkType, 0, 8)
@@ -200,6 +200,12 @@ func fpdfNew(orientationStr, unitStr, sizeStr, fontDirStr string, size SizeType)
 	f.alpha = 1
 	f.gradientList = make([]gradientType, 0, 8)
 	f.gradientList = append(f.gradientList, gradientType{}) // gradientList[0] is unused
+	f.patternList = make([]patternType, 1)                  // patternList[0] is unused
+	f.groupList = make([]transparencyGroupType, 1)          // groupList[0] is unused
+	f.softMaskList = make([]softMaskType, 1)                // softMaskList[0] is unused
+	f.symbolList = make([]symbolType, 1)                    // symbolList[0] is unused
+	f.symbolMap = make(map[string]int)
+	f.ctm = TransformMatrix{A: 1, D: 1}
 	// Set default PDF version number
 	f.pdfVersion = "1.3"
 	f.SetProducer("FPDF "+cnFpdfVersion, true)
@@ -638,7 +644,8 @@ func (f *Fpdf) AliasNbPages(aliasStr string) {
 	f.aliasNbPagesStr = aliasStr
 }
 
-// RTL enables right-to-left mode
+// RTL enables right-to-left mode, in which text is reversed in its entirety.
+// See SetBaseDirection() for the ordering of text that mixes directions.
 func (f *Fpdf) RTL() {
 	f.isRTL = true
 }
@@ -658,11 +665,17 @@ func (f *Fpdf) open() {
 // automatically. If the document contains no page, AddPage() is called to
 // prevent the generation of an invalid document.
 func (f *Fpdf) Close() {
+	// The fonts of the document are no longer read once it is closed
+	defer f.fontSpoolsRemove()
 	if f.err == nil {
 		if f.clipNest > 0 {
 			f.err = fmt.Errorf("clip procedure must be explicitly ended")
 		} else if f.transformNest > 0 {
 			f.err = fmt.Errorf("transformation procedure must be explicitly ended")
+		} else if len(f.groupNest) > 0 {
+			f.err = fmt.Errorf("transparency group, soft mask or shadow must be explicitly ended")
+		} else if len(f.stateStack) > 0 {
+			f.err = fmt.Errorf("graphics state must be explicitly restored")
 		}
 	}
 	if f.err != nil {
@@ -678,6 +691,7 @@ func (f *Fpdf) Close() {
 		}
 	}
 	// Page footer
+	f.columnsPageEnd()
 	f.inFooter = true
 	if f.footerFnc != nil {
 		f.footerFnc()
@@ -741,6 +755,7 @@ func (f *Fpdf) AddPageFormat(orientationStr string, size SizeType) {
 	tc := f.color.text
 	cf := f.colorFlag
 
+	f.columnsPageEnd()
 	if f.page > 0 {
 		f.inFooter = true
 		// Page footer avoid double call on footer.
@@ -756,6 +771,19 @@ func (f *Fpdf) AddPageFormat(orientationStr string, size SizeType) {
 	}
 	// Start new page
 	f.beginpage(orientationStr, size)
+	// Set character and word spacing
+	if f.charSpacing != 0 {
+		f.outf("%.5f Tc", f.charSpacing*f.k)
+	}
+	if f.wordSpacing != 0 {
+		f.wordSpacingPut()
+	}
+	if f.textRise != 0 {
+		f.outf("%.5f Ts", f.textRise*f.k)
+	}
+	if f.textMode != TextModeFill {
+		f.outf("%d Tr", f.textMode)
+	}
 	// 	Set line cap style to current value
 	// f.out("2 J")
 	f.outf("%d J", f.capStyle)
@@ -795,6 +823,7 @@ func (f *Fpdf) AddPageFormat(orientationStr string, size SizeType) {
 			f.SetHomeXY()
 		}
 	}
+	f.columnsPageStart()
 	// 	Restore line width
 	if f.lineWidth != lw {
 		f.lineWidth = lw
@@ -943,7 +972,10 @@ func (f *Fpdf) GetTextColor() (int, int, int) {
 }
 
 // GetStringWidth returns the length of a string in user units. A font must be
-// currently selected.
+// currently selected. Invisible formatting characters that a UTF-8 font
+// lacks, such as zero width joiners and variation selectors, are neither
+// measured nor printed. The spacing set with SetCharSpacing() and
+// SetWordSpacing() is not included; see GetSpacedStringWidth().
 func (f *Fpdf) GetStringWidth(s string) float64 {
 	if f.err != nil {
 		return 0
@@ -952,6 +984,41 @@ func (f *Fpdf) GetStringWidth(s string) float64 {
 	return float64(w) * f.fontSize / 1000
 }
 
+// GetSpacedStringWidth returns the length of a string in user units as it is
+// printed, that is the width returned by GetStringWidth() plus the spacing set
+// with SetCharSpacing() and SetWordSpacing(). A font must be currently
+// selected.
+func (f *Fpdf) GetSpacedStringWidth(s string) float64 {
+	return f.GetStringWidth(s) + f.stringSpacing(s)
+}
+
+// stringSpacing returns the width that character and word spacing add to s.
+func (f *Fpdf) stringSpacing(s string) (w float64) {
+	if f.charSpacing == 0 && f.wordSpacing == 0 {
+		return
+	}
+	for _, c := range f.textRunes(f.shapeText(s)) {
+		w += f.charSpacing
+		if c == ' ' {
+			w += f.wordSpacing
+		}
+	}
+	return
+}
+
+// spacingWidth returns the width that character and word spacing add to the
+// character c, in thousandths of the font size as GetStringSymbolWidth().
+func (f *Fpdf) spacingWidth(c rune) float64 {
+	w := f.charSpacing
+	if c == ' ' {
+		w += f.wordSpacing
+	}
+	if w == 0 {
+		return 0
+	}
+	return w * 1000 / f.fontSize
+}
+
 // GetStringSymbolWidth returns the length of a string in glyf units. A font must be
 // currently selected.
 func (f *Fpdf) GetStringSymbolWidth(s string) int {
@@ -959,14 +1026,18 @@ func (f *Fpdf) GetStringSymbolWidth(s string) int {
 		return 0
 	}
 	w := 0
+	s = f.shapeText(s)
 	if f.isCurrentUTF8 {
+		cw := f.charWidths()
 		unicode := []rune(s)
 		for _, char := range unicode {
 			intChar := int(char)
-			if len(f.currentFont.Cw) >= intChar && f.currentFont.Cw[intChar] > 0 {
-				if f.currentFont.Cw[intChar] != 65535 {
-					w += f.currentFont.Cw[intChar]
+			if intChar < len(cw) && cw[intChar] > 0 {
+				if cw[intChar] != 65535 {
+					w += cw[intChar]
 				}
+			} else if ignorable(char) {
+				// Not printed
 			} else if f.currentFont.Desc.MissingWidth != 0 {
 				w += f.currentFont.Desc.MissingWidth
 			} else {
@@ -1081,8 +1152,16 @@ func (f *Fpdf) outputDashPattern() {
 }
 
 // Line draws a line between points (x1, y1) and (x2, y2) using the current
-// draw color, line width and cap style.
+// draw color, line width and cap style, with the decorations set with
+// SetLineDecoration().
 func (f *Fpdf) Line(x1, y1, x2, y2 float64) {
+	if f.decorated() {
+		var path PathType
+		path.MoveTo(x1, y1)
+		path.LineTo(x2, y2)
+		f.decoratedPath(path, "S")
+		return
+	}
 	f.outf("%.2f %.2f m %.2f %.2f l S", x1*f.k, (f.h-y1)*f.k, x2*f.k, (f.h-y2)*f.k)
 }
 
@@ -1152,7 +1231,8 @@ func (f *Fpdf) RoundedRect(x, y, w, h, r float64, corners string, stylestr strin
 
 // RoundedRectExt behaves the same as RoundedRect() but supports a different
 // radius for each corner. A zero radius means squared corner. See
-// RoundedRect() for more details. This method is demonstrated in the
+// RoundedRect() for more details. SuperellipseRect() draws corners that blend
+// more smoothly into the sides. This method is demonstrated in the
 // RoundedRect() example.
 func (f *Fpdf) RoundedRectExt(x, y, w, h, rTL, rTR, rBR, rBL float64, stylestr string) {
 	f.roundedRectPath(x, y, w, h, rTL, rTR, rBR, rBL)
@@ -1192,10 +1272,18 @@ func (f *Fpdf) Ellipse(x, y, rx, ry, degRotate float64, styleStr string) {
 //
 // styleStr can be "F" for filled, "D" for outlined only, or "DF" or "FD" for
 // outlined and filled. An empty string will be replaced with "D". Drawing uses
-// the current draw color and line width centered on the ellipse's perimeter.
-// Filling uses the current fill color.
+// the current draw color and line width centered on the ellipse's perimeter,
+// with the markers set with SetLineDecoration(). Filling uses the current
+// fill color.
 func (f *Fpdf) Polygon(points []PointType, styleStr string) {
-	if len(points) > 2 {
+	if len(points) > 2 && f.decorated() {
+		var path PathType
+		for _, pt := range points {
+			path.LineTo(pt.X, pt.Y)
+		}
+		path.Close()
+		f.decoratedPath(path, fillDrawOp(styleStr))
+	} else if len(points) > 2 {
 		for j, pt := range points {
 			if j == 0 {
 				f.point(pt.X, pt.Y)
@@ -1361,17 +1449,35 @@ func (f *Fpdf) SetAlpha(alpha float64, blendModeStr string) {
 		f.err = fmt.Errorf("alpha value (0.0 - 1.0) is out of range: %.3f", alpha)
 		return
 	}
+	blendModeStr = bl.modeStr
 	f.alpha = alpha
 	f.blendMode = blendModeStr
-	alphaStr := sprintf("%.3f", alpha)
-	keyStr := sprintf("%s %s", alphaStr, blendModeStr)
+	f.outf("/GS%d gs", f.blendStatePos(alpha, alpha, blendModeStr))
+}
+
+// blendStatePos returns the position in the list of blend graphics states of
+// the one of the fill and stroke alpha values and the valid blend mode
+// blendModeStr, which is added to the list if need be.
+func (f *Fpdf) blendStatePos(fillAlpha, strokeAlpha float64, blendModeStr string) int {
+	fillStr := sprintf("%.3f", fillAlpha)
+	strokeStr := sprintf("%.3f", strokeAlpha)
+	keyStr := sprintf("%s %s %s", fillStr, strokeStr, blendModeStr)
 	pos, ok := f.blendMap[keyStr]
 	if !ok {
 		pos = len(f.blendList) // at least 1
-		f.blendList = append(f.blendList, blendModeType{alphaStr, alphaStr, blendModeStr, 0})
+		f.blendList = append(f.blendList, blendModeType{strokeStr, fillStr, blendModeStr, 0})
 		f.blendMap[keyStr] = pos
 	}
-	f.outf("/GS%d gs", pos)
+	return pos
+}
+
+// SetBlendMode sets the blend mode with which text, drawings and images that
+// follow are composited with what lies below them, such as "Multiply" for
+// watermarks that darken the page without hiding it. The current alpha
+// transparency value set with SetAlpha() is kept. See SetAlpha() for the blend
+// modes. Call this method with "Normal" to reset normal rendering.
+func (f *Fpdf) SetBlendMode(blendModeStr string) {
+	f.SetAlpha(f.alpha, blendModeStr)
 }
 
 func (f *Fpdf) gradientClipStart(x, y, w, h float64) {
@@ -1390,8 +1496,8 @@ func (f *Fpdf) gradient(tp, r1, g1, b1, r2, g2, b2 int, x1, y1, x2, y2, r float6
 	pos := len(f.gradientList)
 	clr1 := rgbColorValue(r1, g1, b1, "", "")
 	clr2 := rgbColorValue(r2, g2, b2, "", "")
-	f.gradientList = append(f.gradientList, gradientType{tp, clr1.str, clr2.str,
-		x1, y1, x2, y2, r, 0})
+	f.gradientList = append(f.gradientList, gradientType{tp, []string{clr1.str, clr2.str},
+		[]float64{0, 1}, x1, y1, x2, y2, r, true, "", nil, 0})
 	f.outf("/Sh%d sh", pos)
 }
 
@@ -1410,7 +1516,8 @@ func (f *Fpdf) gradient(tp, r1, g1, b1, r2, g2, b2 int, x1, y1, x2, y2, r float6
 // perpendicularly to the vector. The vector does not necessarily need to be
 // anchored on the rectangle edge. Color 1 is used up to the origin of the
 // vector and color 2 is used beyond the vector's end point. Between the points
-// the colors are gradually blended.
+// the colors are gradually blended. See LinearGradientStops() for gradients of
+// more than two colors.
 func (f *Fpdf) LinearGradient(x, y, w, h float64, r1, g1, b1, r2, g2, b2 int, x1, y1, x2, y2 float64) {
 	f.gradientClipStart(x, y, w, h)
 	f.gradient(2, r1, g1, b1, r2, g2, b2, x1, y1, x2, y2, 0)
@@ -1452,6 +1559,7 @@ func (f *Fpdf) RadialGradient(x, y, w, h float64, r1, g1, b1, r2, g2, b2 int, x1
 // This ClipText() example demonstrates this method.
 func (f *Fpdf) ClipRect(x, y, w, h float64, outline bool) {
 	f.clipNest++
+	f.ctmPush()
 	f.outf("q %.2f %.2f %.2f %.2f re W %s", x*f.k, (f.h-y)*f.k, w*f.k, -h*f.k, strIf(outline, "S", "n"))
 }
 
@@ -1465,6 +1573,7 @@ func (f *Fpdf) ClipRect(x, y, w, h float64, outline bool) {
 // restore unclipped operations.
 func (f *Fpdf) ClipText(x, y float64, txtStr string, outline bool) {
 	f.clipNest++
+	f.ctmPush()
 	f.outf("q BT %.5f %.5f Td %d Tr (%s) Tj ET", x*f.k, (f.h-y)*f.k, intIf(outline, 5, 7), f.escape(txtStr))
 }
 
@@ -1494,6 +1603,7 @@ func (f *Fpdf) ClipRoundedRect(x, y, w, h, r float64, outline bool) {
 // details. This method is demonstrated in the ClipText() example.
 func (f *Fpdf) ClipRoundedRectExt(x, y, w, h, rTL, rTR, rBR, rBL float64, outline bool) {
 	f.clipNest++
+	f.ctmPush()
 	f.roundedRectPath(x, y, w, h, rTL, rTR, rBR, rBL)
 	f.outf(" W %s", strIf(outline, "S", "n"))
 }
@@ -1543,6 +1653,7 @@ func (f *Fpdf) roundedRectPath(x, y, w, h, rTL, rTR, rBR, rBL float64) {
 // This ClipText() example demonstrates this method.
 func (f *Fpdf) ClipEllipse(x, y, rx, ry float64, outline bool) {
 	f.clipNest++
+	f.ctmPush()
 	lx := (4.0 / 3.0) * rx * (math.Sqrt2 - 1)
 	ly := (4.0 / 3.0) * ry * (math.Sqrt2 - 1)
 	k := f.k
@@ -1592,6 +1703,7 @@ func (f *Fpdf) ClipCircle(x, y, r float64, outline bool) {
 // The ClipText() example demonstrates this method.
 func (f *Fpdf) ClipPolygon(points []PointType, outline bool) {
 	f.clipNest++
+	f.ctmPush()
 	var s fmtBuffer
 	h := f.h
 	k := f.k
@@ -1604,15 +1716,16 @@ func (f *Fpdf) ClipPolygon(points []PointType, outline bool) {
 }
 
 // ClipEnd ends a clipping operation that was started with a call to
-// ClipRect(), ClipRoundedRect(), ClipText(), ClipEllipse(), ClipCircle() or
-// ClipPolygon(). Clipping operations can be nested. The document cannot be
-// successfully output while a clipping operation is active.
+// ClipRect(), ClipRoundedRect(), ClipText(), ClipEllipse(), ClipCircle(),
+// ClipPolygon() or ClipPath(). Clipping operations can be nested. The document
+// cannot be successfully output while a clipping operation is active.
 //
 // The ClipText() example demonstrates this method.
 func (f *Fpdf) ClipEnd() {
 	if f.err == nil {
 		if f.clipNest > 0 {
 			f.clipNest--
+			f.ctmPop()
 			f.out("Q")
 		} else {
 			f.err = fmt.Errorf("error attempting to end clip operation out of sequence")
@@ -1620,6 +1733,35 @@ func (f *Fpdf) ClipEnd() {
 	}
 }
 
+// ClipDepth returns the number of clipping operations in effect, which are
+// nested so that the clipping area is the intersection of their areas. The
+// depth can be saved before clipping operations are begun, to end them all
+// with ClipRestore().
+func (f *Fpdf) ClipDepth() int {
+	return f.clipNest
+}
+
+// ClipRestore ends the clipping operations begun since the number of
+// clipping operations in effect, as returned by ClipDepth(), was depth, as
+// ClipEnd() does for each of them. The clipping area in effect at that depth
+// is restored, however many clipping operations were begun since. A
+// transformation begun after those clipping operations must be ended first.
+//
+// The ClipPath() example demonstrates this method.
+func (f *Fpdf) ClipRestore(depth int) {
+	if f.err != nil {
+		return
+	}
+	if depth < 0 || depth > f.clipNest {
+		f.err = fmt.Errorf("clip depth %d out of range 0 to %d", depth, f.clipNest)
+		return
+	}
+	for ; f.clipNest > depth; f.clipNest-- {
+		f.ctmPop()
+		f.out("Q")
+	}
+}
+
 // AddFont imports a TrueType, OpenType or Type1 font and makes it available.
 // It is necessary to generate a font definition file first with the makefont
 // utility. It is not necessary to call this function for the core PDF fonts
@@ -1644,7 +1786,8 @@ func (f *Fpdf) AddFont(familyStr, styleStr, fileStr string) {
 	f.addFont(fontFamilyEscape(familyStr), styleStr, fileStr, false)
 }
 
-// AddUTF8Font imports a TrueType font with utf-8 symbols and makes it available.
+// AddUTF8Font imports a TrueType font, or an OpenType font with TrueType or
+// PostScript (CFF) outlines, with utf-8 symbols and makes it available.
 // It is necessary to generate a font definition file first with the makefont
 // utility. It is not necessary to call this function for the core PDF fonts
 // (courier, helvetica, times, zapfdingbats).
@@ -1664,6 +1807,23 @@ func (f *Fpdf) AddFont(familyStr, styleStr, fileStr string) {
 // fileStr specifies the base name with ".json" extension of the font
 // definition file to be added. The file will be loaded from the font directory
 // specified in the call to New() or SetFontLocation().
+//
+// The font may also be packaged as a WOFF or WOFF2 web font, from which the
+// TrueType or OpenType font is extracted. WOFF2 fonts require a Brotli
+// decoder to be registered with SetBrotliDecoder().
+//
+// Only the glyphs of the characters used in the document are embedded. The
+// font program is rebuilt as a subset when the document is output. PostScript
+// outlines are embedded as a CID-keyed CFF font, with their subroutines
+// retained in full.
+//
+// The color glyphs of a font, such as the emoji of an emoji font, are
+// printed in color. They are the layered glyphs of the COLR table, drawn in
+// the colors of the first palette of the CPAL table, and the PNG bitmaps of
+// the CBDT and sbix tables at their largest size. A color glyph is shown as
+// invisible text, so that it can still be selected and extracted, over which
+// it is drawn. Layers in the foreground color are drawn in the text color.
+// Color glyphs are neither slanted nor emboldened by synthetic styles.
 func (f *Fpdf) AddUTF8Font(familyStr, styleStr, fileStr string) {
 	f.addFont(fontFamilyEscape(familyStr), styleStr, fileStr, true)
 }
@@ -1694,13 +1854,20 @@ func (f *Fpdf) addFont(familyStr, styleStr, fileStr string, isUTF8 bool) {
 		Type := "UTF8"
 		var utf8Bytes []byte
 		utf8Bytes, err = ioutil.ReadFile(fileStr)
+		if err == nil {
+			utf8Bytes, err = sfntFromWebFont(utf8Bytes)
+		}
+		if err != nil {
+			f.SetError(err)
+			return
+		}
+		originalSize = int64(len(utf8Bytes))
+		utf8File, err := f.parseUTF8Font(&fileReader{array: utf8Bytes})
 		if err != nil {
 			f.SetError(err)
 			return
 		}
-		reader := fileReader{readerPosition: 0, array: utf8Bytes}
-		utf8File := newUTF8Font(&reader)
-		err = utf8File.parseFile()
+		embedFull, err := f.fontEmbedding(utf8File)
 		if err != nil {
 			f.SetError(err)
 			return
@@ -1710,6 +1877,7 @@ func (f *Fpdf) addFont(familyStr, styleStr, fileStr string, isUTF8 bool) {
 			Ascent:       int(utf8File.Ascent),
 			Descent:      int(utf8File.Descent),
 			CapHeight:    utf8File.CapHeight,
+			XHeight:      utf8File.XHeight,
 			Flags:        utf8File.Flags,
 			FontBBox:     utf8File.Bbox,
 			ItalicAngle:  utf8File.ItalicAngle,
@@ -1731,6 +1899,9 @@ func (f *Fpdf) addFont(familyStr, styleStr, fileStr string, isUTF8 bool) {
 			Ut:        round(utf8File.UnderlineThickness),
 			Cw:        utf8File.CharWidths,
 			usedRunes: sbarr,
+			cids:      make(map[int]int),
+			features:  &fontFeatures{},
+			embedFull: embedFull,
 			File:      fileStr,
 			utf8File:  utf8File,
 		}
@@ -1794,9 +1965,10 @@ func (f *Fpdf) AddFontFromBytes(familyStr, styleStr string, jsonFileBytes, zFile
 	f.addFontFromBytes(fontFamilyEscape(familyStr), styleStr, jsonFileBytes, zFileBytes, nil)
 }
 
-// AddUTF8FontFromBytes  imports a TrueType font with utf-8 symbols from static
-// bytes within the executable and makes it available for use in the generated
-// document.
+// AddUTF8FontFromBytes  imports a TrueType or OpenType font with utf-8 symbols
+// from static bytes within the executable and makes it available for use in
+// the generated document. WOFF and WOFF2 web fonts are accepted as described
+// for AddUTF8Font().
 //
 // family specifies the font family. The name can be chosen arbitrarily. If it
 // is a standard family name, it will override the corresponding font. This
@@ -1813,6 +1985,86 @@ func (f *Fpdf) AddUTF8FontFromBytes(familyStr, styleStr string, utf8Bytes []byte
 	f.addFontFromBytes(fontFamilyEscape(familyStr), styleStr, nil, nil, utf8Bytes)
 }
 
+// AddUTF8FontFromReader imports a TrueType or OpenType font with utf-8
+// symbols from the specified reader and makes it available for use in the
+// generated document. This allows fonts to be loaded from sources such as an
+// embed.FS, a zip archive or a network stream. See AddUTF8Font for details
+// about familyStr and styleStr.
+//
+// Since the tables of a font can only be located by seeking, the font is
+// copied to a temporary file, from which it is read as by
+// AddUTF8FontFromReaderAt, so that it is not held in memory. r may be closed
+// once this method returns. The temporary file is removed when the document
+// is closed. The reader is not read if a font has already been added with
+// the same family and style.
+func (f *Fpdf) AddUTF8FontFromReader(familyStr, styleStr string, r io.Reader) {
+	if f.err != nil {
+		return
+	}
+	if _, ok := f.fonts[getFontKey(fontFamilyEscape(familyStr), styleStr)]; ok {
+		return
+	}
+	file, err := ioutil.TempFile("", "gofpdf-font-*.tmp")
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	f.fontSpools = append(f.fontSpools, file)
+	size, err := io.Copy(file, r)
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	f.AddUTF8FontFromReaderAt(familyStr, styleStr, file, size)
+}
+
+// fontSpoolsRemove closes and removes the temporary files of the fonts added
+// with AddUTF8FontFromReader().
+func (f *Fpdf) fontSpoolsRemove() {
+	for _, file := range f.fontSpools {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	f.fontSpools = nil
+}
+
+// AddUTF8FontFromReaderAt imports a TrueType or OpenType font with utf-8
+// symbols of size bytes from the specified reader and makes it available for
+// use in the generated document. Files opened from the file system or from
+// an embed.FS can be passed as r. See AddUTF8Font for details about
+// familyStr and styleStr.
+//
+// The font is not held in memory. Only the table directory and the tables
+// needed for its metrics are read when it is added, and the tables of the
+// glyphs used are read when the document is output, so r must remain
+// readable until then. If a font cache is attached with SetFontCache(), the
+// whole font is read once to compute its cache key. WOFF and WOFF2 fonts are
+// compressed and are therefore read and decompressed in full.
+func (f *Fpdf) AddUTF8FontFromReaderAt(familyStr, styleStr string, r io.ReaderAt, size int64) {
+	if f.err != nil {
+		return
+	}
+	fontkey := getFontKey(fontFamilyEscape(familyStr), styleStr)
+	if _, ok := f.fonts[fontkey]; ok {
+		return
+	}
+	signature := make([]byte, 4)
+	if _, err := r.ReadAt(signature, 0); err != nil {
+		f.SetError(err)
+		return
+	}
+	if bytes.Equal(signature, woffSignature) || bytes.Equal(signature, woff2Signature) {
+		utf8Bytes, err := ioutil.ReadAll(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			f.SetError(err)
+			return
+		}
+		f.AddUTF8FontFromBytes(familyStr, styleStr, utf8Bytes)
+		return
+	}
+	f.addUTF8Font(fontkey, &fileReader{r: r, size: size})
+}
+
 func (f *Fpdf) addFontFromBytes(familyStr, styleStr string, jsonFileBytes, zFileBytes, utf8Bytes []byte) {
 	if f.err != nil {
 		return
@@ -1833,45 +2085,13 @@ func (f *Fpdf) addFontFromBytes(familyStr, styleStr string, jsonFileBytes, zFile
 		// 	styleStr = "BI"
 		// }
 
-		Type := "UTF8"
-		reader := fileReader{readerPosition: 0, array: utf8Bytes}
-
-		utf8File := newUTF8Font(&reader)
-
-		err := utf8File.parseFile()
+		var err error
+		utf8Bytes, err = sfntFromWebFont(utf8Bytes)
 		if err != nil {
-			fmt.Printf("get metrics Error: %e\n", err)
+			f.SetError(err)
 			return
 		}
-		desc := FontDescType{
-			Ascent:       int(utf8File.Ascent),
-			Descent:      int(utf8File.Descent),
-			CapHeight:    utf8File.CapHeight,
-			Flags:        utf8File.Flags,
-			FontBBox:     utf8File.Bbox,
-			ItalicAngle:  utf8File.ItalicAngle,
-			StemV:        utf8File.StemV,
-			MissingWidth: round(utf8File.DefaultWidth),
-		}
-
-		var sbarr map[int]int
-		if f.aliasNbPagesStr == "" {
-			sbarr = makeSubsetRange(57)
-		} else {
-			sbarr = makeSubsetRange(32)
-		}
-		def := fontDefType{
-			Tp:        Type,
-			Name:      fontkey,
-			Desc:      desc,
-			Up:        int(round(utf8File.UnderlinePosition)),
-			Ut:        round(utf8File.UnderlineThickness),
-			Cw:        utf8File.CharWidths,
-			utf8File:  utf8File,
-			usedRunes: sbarr,
-		}
-		def.i, _ = generateFontID(def)
-		f.fonts[fontkey] = def
+		f.addUTF8Font(fontkey, &fileReader{array: utf8Bytes})
 	} else {
 		// load font definitions
 		var info fontDefType
@@ -1931,6 +2151,54 @@ func (f *Fpdf) addFontFromBytes(familyStr, styleStr string, jsonFileBytes, zFile
 	}
 }
 
+// addUTF8Font adds the TrueType or OpenType font read by fr as fontkey.
+func (f *Fpdf) addUTF8Font(fontkey string, fr *fileReader) {
+	Type := "UTF8"
+	utf8File, err := f.parseUTF8Font(fr)
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	embedFull, err := f.fontEmbedding(utf8File)
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	desc := FontDescType{
+		Ascent:       int(utf8File.Ascent),
+		Descent:      int(utf8File.Descent),
+		CapHeight:    utf8File.CapHeight,
+		XHeight:      utf8File.XHeight,
+		Flags:        utf8File.Flags,
+		FontBBox:     utf8File.Bbox,
+		ItalicAngle:  utf8File.ItalicAngle,
+		StemV:        utf8File.StemV,
+		MissingWidth: round(utf8File.DefaultWidth),
+	}
+
+	var sbarr map[int]int
+	if f.aliasNbPagesStr == "" {
+		sbarr = makeSubsetRange(57)
+	} else {
+		sbarr = makeSubsetRange(32)
+	}
+	def := fontDefType{
+		Tp:        Type,
+		Name:      fontkey,
+		Desc:      desc,
+		Up:        int(round(utf8File.UnderlinePosition)),
+		Ut:        round(utf8File.UnderlineThickness),
+		Cw:        utf8File.CharWidths,
+		utf8File:  utf8File,
+		usedRunes: sbarr,
+		cids:      make(map[int]int),
+		features:  &fontFeatures{},
+		embedFull: embedFull,
+	}
+	def.i, _ = generateFontID(def)
+	f.fonts[fontkey] = def
+}
+
 // getFontKey is used by AddFontFromReader and GetFontDesc
 func getFontKey(familyStr, styleStr string) string {
 	familyStr = strings.ToLower(familyStr)
@@ -2061,6 +2329,12 @@ func (f *Fpdf) SetFont(familyStr, styleStr string, size float64) {
 	// Test if font is already loaded
 	fontKey := familyStr + styleStr
 	_, ok = f.fonts[fontKey]
+	synthStr := ""
+	if !ok {
+		if key, str := f.syntheticFontKey(familyStr, styleStr); key != "" {
+			fontKey, synthStr, ok = key, str, true
+		}
+	}
 	if !ok {
 		// Test if one of the core fonts
 		if familyStr == "arial" {
@@ -2096,6 +2370,7 @@ func (f *Fpdf) SetFont(familyStr, styleStr string, size float64) {
 	f.fontSizePt = size
 	f.fontSize = size / f.k
 	f.currentFont = f.fonts[fontKey]
+	f.fontSynthStr = synthStr
 	if f.currentFont.Tp == "UTF8" {
 		f.isCurrentUTF8 = true
 	} else {
@@ -2207,19 +2482,26 @@ func (f *Fpdf) Bookmark(txtStr string, level int, y float64) {
 // or Write() which are the standard methods to print text.
 func (f *Fpdf) Text(x, y float64, txtStr string) {
 	var txt2 string
+	txtStr = f.shapeText(txtStr)
 	if f.isCurrentUTF8 {
 		if f.isRTL {
-			txtStr = reverseText(txtStr)
-			x -= f.GetStringWidth(txtStr)
-		}
-		txt2 = f.escape(utf8toutf16(txtStr, false))
-		for _, uni := range []rune(txtStr) {
-			f.currentFont.usedRunes[int(uni)] = int(uni)
+			x -= f.GetSpacedStringWidth(txtStr)
 		}
+		txtStr = f.visualText(txtStr)
+		txt2 = f.escape(f.utf8toCID(f.currentFont, txtStr))
 	} else {
 		txt2 = f.escape(txtStr)
 	}
-	s := sprintf("BT %.2f %.2f Td (%s) Tj ET", x*f.k, (f.h-y)*f.k, txt2)
+	s := sprintf("BT %s (%s) Tj ET", f.textOrigin(x*f.k, (f.h-y)*f.k), txt2)
+	if ops, ok := f.fallbackText(txtStr); ok {
+		s = sprintf("BT %s %s ET", f.textOrigin(x*f.k, (f.h-y)*f.k), ops)
+	}
+	if bold := f.syntheticBold(); bold != "" {
+		s = sprintf("q %s%s Q", bold, s)
+	}
+	if glyphs := f.colorGlyphs(x, y, txtStr, 0); glyphs != "" {
+		s += " " + glyphs
+	}
 	if f.underline && txtStr != "" {
 		s += " " + f.dounderline(x, y, txtStr)
 	}
@@ -2227,34 +2509,110 @@ func (f *Fpdf) Text(x, y float64, txtStr string) {
 		s += " " + f.dostrikeout(x, y, txtStr)
 	}
 	if f.colorFlag {
-		s = sprintf("q %s %s Q", f.color.text.str, s)
+		if f.textMode >= TextModeFillClip {
+			// Restoring the graphics state would discard the clipping path
+			s = sprintf("%s %s %s", f.color.text.str, s, f.color.fill.str)
+		} else {
+			s = sprintf("q %s %s Q", f.color.text.str, s)
+		}
 	}
+	f.measureText(x, y, f.GetSpacedStringWidth(txtStr))
 	f.out(s)
 }
 
-// SetWordSpacing sets spacing between words of following text. See the
-// WriteAligned() example for a demonstration of its use.
+// SetWordSpacing sets the space, in the unit of measure specified in New(),
+// that is added to each space character of following text. A negative value
+// brings words closer together. Like the character spacing of
+// SetCharSpacing(), the spacing is kept from page to page and is taken into
+// account by GetSpacedStringWidth(), by the alignment of text within cells
+// and by the line breaking of Write(), MultiCell() and the methods that build
+// on them, but not by GetStringWidth(). Justified text is stretched beyond
+// this spacing. With UTF-8 fonts, for which the text operators of PDF do not
+// space words, the spacing is applied by CellFormat() and the methods that build on
+// it, but not by Text(). See the WriteAligned() example for a demonstration of
+// its use.
 func (f *Fpdf) SetWordSpacing(space float64) {
-	f.out(sprintf("%.5f Tw", space*f.k))
+	f.wordSpacing = space
+	if f.page > 0 {
+		f.out(sprintf("%.5f Tw", (f.ws+space)*f.k))
+	}
+}
+
+// GetWordSpacing returns the word spacing set with SetWordSpacing().
+func (f *Fpdf) GetWordSpacing() float64 {
+	return f.wordSpacing
+}
+
+// SetCharSpacing sets the space, in the unit of measure specified in New(),
+// that is added after each character of following text, to track text out
+// for headings or small capitals for example. A negative value tightens the
+// text. The spacing is kept from page to page and is taken into account by
+// GetSpacedStringWidth(), by the alignment of text within cells and by the
+// line breaking of Write(), MultiCell() and the methods that build on them,
+// but not by GetStringWidth(). The default value is 0.
+func (f *Fpdf) SetCharSpacing(space float64) {
+	f.charSpacing = space
+	if f.page > 0 {
+		f.outf("%.5f Tc", space*f.k)
+	}
+}
+
+// GetCharSpacing returns the character spacing set with SetCharSpacing().
+func (f *Fpdf) GetCharSpacing() float64 {
+	return f.charSpacing
+}
+
+// wordSpacingPut outputs the word spacing of following text, that set with
+// SetWordSpacing() plus the spacing of justified text.
+func (f *Fpdf) wordSpacingPut() {
+	if ws := f.ws + f.wordSpacing; ws != 0 {
+		f.outf("%.3f Tw", ws*f.k)
+	} else {
+		f.out("0 Tw")
+	}
 }
 
 // SetTextRenderingMode sets the rendering mode of following text.
 // The mode can be as follows:
-// 0: Fill text
-// 1: Stroke text
-// 2: Fill, then stroke text
-// 3: Neither fill nor stroke text (invisible)
-// 4: Fill text and add to path for clipping
-// 5: Stroke text and add to path for clipping
-// 6: Fills then stroke text and add to path for clipping
-// 7: Add text to path for clipping
+// 0: Fill text (TextModeFill)
+// 1: Stroke text (TextModeStroke)
+// 2: Fill, then stroke text (TextModeFillStroke)
+// 3: Neither fill nor stroke text (invisible) (TextModeInvisible)
+// 4: Fill text and add to path for clipping (TextModeFillClip)
+// 5: Stroke text and add to path for clipping (TextModeStrokeClip)
+// 6: Fills then stroke text and add to path for clipping (TextModeFillStrokeClip)
+// 7: Add text to path for clipping (TextModeClip)
+//
+// Text is filled with the text color and stroked with the draw color and the
+// line width, so that outlined text can be of a different color than its
+// fill. Invisible text can be selected and searched, as the text layer over
+// the scanned image of a page. The mode is kept from page to page and other
+// modes are ignored. The default mode is 0.
+//
+// In the clipping modes, the text printed by Text() or CellFormat() and the
+// methods that build on them is added to the clipping path, which confines
+// the rendering that follows to the characters of the text. The text of each
+// call clips separately, within the clipping path of the previous calls. The
+// clipping lasts until the graphics state is restored: enclose the text and
+// what it clips between TransformBegin() and TransformEnd(), and set the mode
+// back when the clipping ends. ClipText() clips with a single string.
+//
 // This method is demonstrated in the SetTextRenderingMode example.
 func (f *Fpdf) SetTextRenderingMode(mode int) {
-	if mode >= 0 && mode <= 7 {
-		f.out(sprintf("%d Tr", mode))
+	if mode >= TextModeFill && mode <= TextModeClip {
+		f.textMode = mode
+		if f.page > 0 {
+			f.out(sprintf("%d Tr", mode))
+		}
 	}
 }
 
+// GetTextRenderingMode returns the text rendering mode set with
+// SetTextRenderingMode().
+func (f *Fpdf) GetTextRenderingMode() int {
+	return f.textMode
+}
+
 // SetAcceptPageBreakFunc allows the application to control where page breaks
 // occur.
 //
@@ -2265,7 +2623,8 @@ func (f *Fpdf) SetTextRenderingMode(mode int) {
 // called by the application.
 //
 // See the example for SetLeftMargin() to see how this function can be used to
-// manage multiple columns.
+// manage multiple columns. SetColumns() manages columns without it; fnc is
+// only called when the last column is full.
 func (f *Fpdf) SetAcceptPageBreakFunc(fnc func() bool) {
 	f.acceptPageBreak = fnc
 }
@@ -2286,7 +2645,8 @@ func (f *Fpdf) SetAcceptPageBreakFunc(fnc func() bool) {
 // extends up to the right margin. Specifying 0 for h will result in no output,
 // but the current position will be advanced by w.
 //
-// txtStr specifies the text to display.
+// txtStr specifies the text to display. See CellFit() to fit text that is
+// wider than the cell into it.
 //
 // borderStr specifies how the cell border will be drawn. An empty string
 // indicates no border, "1" indicates a full border, and one or more of "L",
@@ -2301,7 +2661,9 @@ func (f *Fpdf) SetAcceptPageBreakFunc(fnc func() bool) {
 // Horizontal alignment is controlled by including "L", "C" or "R" (left,
 // center, right) in alignStr. Vertical alignment is controlled by including
 // "T", "M", "B" or "A" (top, middle, bottom, baseline) in alignStr. The default
-// alignment is left middle.
+// alignment is left middle. Text with tab characters is aligned at the tab
+// stops set with SetTabStops(). Inline markup in txtStr styles parts of the
+// text if it is enabled with SetMarkup().
 //
 // fill is true to paint the cell background or false to leave it transparent.
 //
@@ -2320,26 +2682,36 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 		f.err = fmt.Errorf("font has not been set; unable to render text")
 		return
 	}
+	if f.markupText(txtStr) {
+		f.markupCellFormat(w, h, txtStr, borderStr, ln, alignStr, fill, link, linkStr)
+		return
+	}
+	if len(f.tabStops) > 0 && strings.Contains(txtStr, "\t") {
+		f.tabCellFormat(w, h, txtStr, borderStr, ln, alignStr, fill, link, linkStr)
+		return
+	}
 
 	borderStr = strings.ToUpper(borderStr)
 	k := f.k
-	if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreak() {
+	if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptBreak() {
 		// Automatic page break
 		x := f.x
-		ws := f.ws
+		ws, cs := f.ws, f.cs
 		// dbg("auto page break, x %.2f, ws %.2f", x, ws)
 		if ws > 0 {
 			f.ws = 0
-			f.out("0 Tw")
+			f.wordSpacingPut()
 		}
+		f.cs = 0
 		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		f.cs = cs
 		if f.err != nil {
 			return
 		}
 		f.x = x
 		if ws > 0 {
 			f.ws = ws
-			f.outf("%.3f Tw", ws*k)
+			f.wordSpacingPut()
 		}
 	}
 	if w == 0 {
@@ -2386,12 +2758,13 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 	}
 	if len(txtStr) > 0 {
 		var dx, dy float64
+		txtStr = f.shapeText(txtStr)
 		// Horizontal alignment
 		switch {
 		case strings.Contains(alignStr, "R"):
-			dx = w - f.cMargin - f.GetStringWidth(txtStr)
+			dx = w - f.cMargin - f.GetSpacedStringWidth(txtStr)
 		case strings.Contains(alignStr, "C"):
-			dx = (w - f.GetStringWidth(txtStr)) / 2
+			dx = (w - f.GetSpacedStringWidth(txtStr)) / 2
 		default:
 			dx = f.cMargin
 		}
@@ -2415,43 +2788,65 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 		default:
 			dy = 0
 		}
+		f.measureText(f.x+dx, f.y+dy+.5*h+.3*f.fontSize, f.GetSpacedStringWidth(txtStr)+f.ws*float64(blankCount(txtStr)))
+		// Restoring the graphics state would discard the clipping path of text
+		// in clipping modes
+		clip := f.textMode >= TextModeFillClip
 		if f.colorFlag {
-			s.printf("q %s ", f.color.text.str)
+			if clip {
+				s.printf("%s ", f.color.text.str)
+			} else {
+				s.printf("q %s ", f.color.text.str)
+			}
+		}
+		bold := f.syntheticBold()
+		if bold != "" {
+			s.printf("q %s", bold)
+		}
+		if f.cs != 0 {
+			// Character spacing of justified text
+			s.printf("%.5f Tc ", (f.charSpacing+f.cs)*k)
 		}
 		//If multibyte, Tw has no effect - do word spacing using an adjustment before each space
-		if (f.ws != 0 || alignStr == "J") && f.isCurrentUTF8 { // && f.ws != 0
-			if f.isRTL {
-				txtStr = reverseText(txtStr)
-			}
+		if (f.ws != 0 || alignStr == "J" || f.wordSpacing != 0) && f.isCurrentUTF8 { // && f.ws != 0
+			txtStr = f.visualText(txtStr)
 			wmax := int(math.Ceil((w - 2*f.cMargin) * 1000 / f.fontSize))
-			for _, uni := range []rune(txtStr) {
-				f.currentFont.usedRunes[int(uni)] = int(uni)
-			}
-			space := f.escape(utf8toutf16(" ", false))
-			strSize := f.GetStringSymbolWidth(txtStr)
-			s.printf("BT 0 Tw %.2f %.2f Td [", (f.x+dx)*k, (f.h-(f.y+.5*h+.3*f.fontSize))*k)
+			space := f.escape(f.utf8toCID(f.currentFont, " "))
+			// The size includes character and word spacing
+			strSize := int(math.Round((f.GetSpacedStringWidth(txtStr) +
+				f.cs*float64(len([]rune(txtStr))-1)) * 1000 / f.fontSize))
+			s.printf("BT 0 Tw %s [", f.textOrigin((f.x+dx)*k, (f.h-(f.y+.5*h+.3*f.fontSize))*k))
 			t := strings.Split(txtStr, " ")
-			shift := float64((wmax - strSize)) / float64(len(t)-1)
+			shift := f.wordSpacing * 1000 / f.fontSize
+			if f.ws != 0 || alignStr == "J" {
+				shift += float64((wmax - strSize)) / float64(len(t)-1)
+			}
 			numt := len(t)
 			for i := 0; i < numt; i++ {
 				tx := t[i]
-				tx = "(" + f.escape(utf8toutf16(tx, false)) + ")"
+				if ops, ok := f.fallbackText(tx); ok {
+					// Show the word outside of the array to switch fonts
+					tx = "] TJ " + ops + " ["
+				} else {
+					tx = "(" + f.escape(f.utf8toCID(f.currentFont, tx)) + ")"
+				}
 				s.printf("%s ", tx)
 				if (i + 1) < numt {
 					s.printf("%.3f(%s) ", -shift, space)
 				}
 			}
 			s.printf("] TJ ET")
+			if ws := f.ws + f.wordSpacing; ws != 0 {
+				s.printf(" %.3f Tw", ws*k)
+			}
+			if glyphs := f.colorGlyphs(f.x+dx, f.y+.5*h+.3*f.fontSize, txtStr, shift*f.fontSize/1000); glyphs != "" {
+				s.printf(" %s", glyphs)
+			}
 		} else {
 			var txt2 string
 			if f.isCurrentUTF8 {
-				if f.isRTL {
-					txtStr = reverseText(txtStr)
-				}
-				txt2 = f.escape(utf8toutf16(txtStr, false))
-				for _, uni := range []rune(txtStr) {
-					f.currentFont.usedRunes[int(uni)] = int(uni)
-				}
+				txtStr = f.visualText(txtStr)
+				txt2 = f.escape(f.utf8toCID(f.currentFont, txtStr))
 			} else {
 
 				txt2 = strings.Replace(txtStr, "\\", "\\\\", -1)
@@ -2460,9 +2855,22 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 			}
 			bt := (f.x + dx) * k
 			td := (f.h - (f.y + dy + .5*h + .3*f.fontSize)) * k
-			s.printf("BT %.2f %.2f Td (%s)Tj ET", bt, td, txt2)
+			if ops, ok := f.fallbackText(txtStr); ok {
+				s.printf("BT %s %s ET", f.textOrigin(bt, td), ops)
+			} else {
+				s.printf("BT %s (%s)Tj ET", f.textOrigin(bt, td), txt2)
+			}
+			if glyphs := f.colorGlyphs(f.x+dx, f.y+dy+.5*h+.3*f.fontSize, txtStr, 0); glyphs != "" {
+				s.printf(" %s", glyphs)
+			}
 			//BT %.2F %.2F Td (%s) Tj ET',(f.x+dx)*k,(f.h-(f.y+.5*h+.3*f.FontSize))*k,txt2);
 		}
+		if f.cs != 0 {
+			s.printf(" %.5f Tc", f.charSpacing*k)
+		}
+		if bold != "" {
+			s.printf(" Q")
+		}
 
 		if f.underline {
 			s.printf(" %s", f.dounderline(f.x+dx, f.y+dy+.5*h+.3*f.fontSize, txtStr))
@@ -2471,10 +2879,14 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 			s.printf(" %s", f.dostrikeout(f.x+dx, f.y+dy+.5*h+.3*f.fontSize, txtStr))
 		}
 		if f.colorFlag {
-			s.printf(" Q")
+			if clip {
+				s.printf(" %s", f.color.fill.str)
+			} else {
+				s.printf(" Q")
+			}
 		}
 		if link > 0 || len(linkStr) > 0 {
-			f.newLink(f.x+dx, f.y+dy+.5*h-.5*f.fontSize, f.GetStringWidth(txtStr), f.fontSize, link, linkStr)
+			f.newLink(f.x+dx, f.y+dy+.5*h-.5*f.fontSize, f.GetSpacedStringWidth(txtStr), f.fontSize, link, linkStr)
 		}
 	}
 	str := s.String()
@@ -2527,7 +2939,7 @@ func (f *Fpdf) Cellf(w, h float64, fmtStr string, args ...interface{}) {
 // use SplitText().
 //
 // You can use MultiCell if you want to print a text on several lines in a
-// simple way.
+// simple way. BreakLines() breaks text exactly as MultiCell() does.
 func (f *Fpdf) SplitLines(txt []byte, w float64) [][]byte {
 	// Function contributed by Bruno Michel
 	lines := [][]byte{}
@@ -2542,14 +2954,14 @@ func (f *Fpdf) SplitLines(txt []byte, w float64) [][]byte {
 	sep := -1
 	i := 0
 	j := 0
-	l := 0
+	l := 0.0
 	for i < nb {
 		c := s[i]
-		l += cw[c]
+		l += float64(cw[c]) + f.spacingWidth(rune(c))
 		if c == ' ' || c == '\t' || c == '\n' {
 			sep = i
 		}
-		if c == '\n' || l > wmax {
+		if c == '\n' || l > float64(wmax) {
 			if sep == -1 {
 				if i == j {
 					i++
@@ -2575,6 +2987,16 @@ func (f *Fpdf) SplitLines(txt []byte, w float64) [][]byte {
 // MultiCell supports printing text with line breaks. They can be automatic (as
 // soon as the text reaches the right border of the cell) or explicit (via the
 // \n character). As many cells as necessary are output, one below the other.
+// Lines are broken at the break opportunities of the Unicode line breaking
+// algorithm (UAX #14): after spaces, hyphens and dashes, after the slashes of
+// URLs and between Chinese, Japanese and Korean characters, but not at
+// non-breaking spaces or before closing punctuation and small kana, for
+// example. Text in single-byte encodings is taken to be in cp1252. Spaces at
+// the end of a line are not printed, even if they do not fit on it.
+// Words that do not fit on a line are hyphenated if a hyphenator has been set
+// with SetHyphenator(). Automatic page breaks between lines avoid widows
+// and orphans as set with SetOrphansWidows(). Inline markup styles parts of
+// the text if it is enabled with SetMarkup().
 //
 // Text can be aligned, centered or justified. The cell block can be framed and
 // the background painted. See CellFormat() for more details.
@@ -2586,6 +3008,8 @@ func (f *Fpdf) SplitLines(txt []byte, w float64) [][]byte {
 // the right margin.
 //
 // h indicates the line height of each cell in the unit of measure specified in New().
+// If h is 0, the line height set with SetLineHeight() or
+// SetLineHeightFactor() is used.
 //
 // Note: this method has a known bug that treats UTF-8 fonts differently than
 // non-UTF-8 fonts. With UTF-8 fonts, all trailing newlines in txtStr are
@@ -2600,13 +3024,96 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 		return
 	}
 	// dbg("MultiCell")
+	if f.markupText(txtStr) {
+		f.markupMultiCell(w, h, txtStr, borderStr, alignStr, fill)
+		return
+	}
+	h = f.textLineHt(h)
 	if alignStr == "" {
 		alignStr = "J"
 	}
-	cw := f.currentFont.Cw
+	txtStr = f.shapeText(txtStr)
+	if f.baseDirStr == "A" && f.isCurrentUTF8 {
+		// All lines take the direction of the text as a whole
+		f.baseDirStr = f.textDirection(txtStr)
+		defer func() { f.baseDirStr = "A" }()
+	}
 	if w == 0 {
 		w = f.w - f.rMargin - f.x
 	}
+	var b, b2 string
+	b = "0"
+	if len(borderStr) > 0 {
+		if borderStr == "1" {
+			borderStr = "LTRB"
+			b = "LRT"
+			b2 = "LR"
+		} else {
+			b2 = ""
+			if strings.Contains(borderStr, "L") {
+				b2 += "L"
+			}
+			if strings.Contains(borderStr, "R") {
+				b2 += "R"
+			}
+			if strings.Contains(borderStr, "T") {
+				b = b2 + "T"
+			} else {
+				b = b2
+			}
+		}
+	}
+	// The lines are output once all are known so that page breaks can avoid
+	// widows and orphans
+	lines := f.multiCellLines(txtStr, w, alignStr == "J")
+	for j := range lines {
+		line := &lines[j]
+		line.border, line.align = b, alignStr
+		if len(borderStr) > 0 && j > 0 {
+			line.border = b2
+		}
+		if j == len(lines)-1 && len(borderStr) > 0 && strings.Contains(borderStr, "B") {
+			line.border += "B"
+		}
+		if f.isCurrentUTF8 && alignStr == "J" && line.last {
+			switch {
+			case f.isRTL || f.baseDirStr == "R":
+				line.align = "R"
+			case j < len(lines)-1:
+				line.align = "L"
+			default:
+				line.align = ""
+			}
+		}
+	}
+	lasts := make([]bool, len(lines))
+	for j, line := range lines {
+		lasts[j] = line.last
+	}
+	keep := f.lineKeeper(lasts, h)
+	for _, line := range lines {
+		keep.next()
+		if line.ws != f.ws {
+			f.ws = line.ws
+			f.wordSpacingPut()
+		}
+		f.cs = line.cs
+		f.CellFormat(w, h, line.str, line.border, 2, line.align, fill, 0, "")
+	}
+	f.cs = 0
+	if f.ws > 0 {
+		f.ws = 0
+		f.wordSpacingPut()
+	}
+	f.x = f.lMargin
+}
+
+// multiCellLines breaks the shaped text txtStr into the lines of cells of
+// width w, as MultiCell() does. The word spacing of the lines, and their
+// character spacing with letter justification, is set if the text is
+// justified.
+func (f *Fpdf) multiCellLines(txtStr string, w float64, justify bool) (lines []multiCellLine) {
+	cw := f.charWidths()
 	wmax := int(math.Ceil((w - 2*f.cMargin) * 1000 / f.fontSize))
 	s := strings.Replace(txtStr, "\r", "", -1)
 	srune := []rune(s)
@@ -2636,35 +3143,34 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 		s = s[0:nb]
 	}
 	// dbg("[%s]\n", s)
-	var b, b2 string
-	b = "0"
-	if len(borderStr) > 0 {
-		if borderStr == "1" {
-			borderStr = "LTRB"
-			b = "LRT"
-			b2 = "LR"
-		} else {
-			b2 = ""
-			if strings.Contains(borderStr, "L") {
-				b2 += "L"
-			}
-			if strings.Contains(borderStr, "R") {
-				b2 += "R"
-			}
-			if strings.Contains(borderStr, "T") {
-				b = b2 + "T"
-			} else {
-				b = b2
-			}
+	// Characters of the text when words are hyphenated
+	var hyRunes []rune
+	if f.hyphenator != nil {
+		hyRunes = srune
+		if !f.isCurrentUTF8 {
+			hyRunes = f.textRunes(s)
 		}
 	}
-	sep := -1
+	ws := 0.0
+	cell := func(str string) {
+		lines = append(lines, multiCellLine{str: str, ws: ws})
+	}
+	// The line can be broken before brk, the last break opportunity of the
+	// Unicode line breaking algorithm, ending with the text up to sep, where
+	// the spaces before brk begin
+	var lb lineBreaker
+	var bounds []bool
+	if f.isCurrentUTF8 {
+		bounds = graphemeBounds(srune)
+	}
+	brk := -1
+	sep := 0
 	i := 0
 	j := 0
-	l := 0
-	ls := 0
+	l := 0.0
+	ls := 0.0
 	ns := 0
-	nl := 1
+	end, endW, endSpaces, spaces := 0, 0.0, 0, 0
 	for i < nb {
 		// Get next character
 		var c rune
@@ -2675,119 +3181,132 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 		}
 		if c == '\n' {
 			// Explicit line break
-			if f.ws > 0 {
-				f.ws = 0
-				f.out("0 Tw")
-			}
+			ws = 0
 
 			if f.isCurrentUTF8 {
-				newAlignStr := alignStr
-				if newAlignStr == "J" {
-					if f.isRTL {
-						newAlignStr = "R"
-					} else {
-						newAlignStr = "L"
-					}
-				}
-				f.CellFormat(w, h, string(srune[j:i]), b, 2, newAlignStr, fill, 0, "")
+				cell(string(srune[j:i]))
 			} else {
-				f.CellFormat(w, h, s[j:i], b, 2, alignStr, fill, 0, "")
+				cell(s[j:i])
 			}
+			lines[len(lines)-1].last = true
 			i++
-			sep = -1
+			brk = -1
 			j = i
 			l = 0
-			ns = 0
-			nl++
-			if len(borderStr) > 0 && nl == 2 {
-				b = b2
-			}
+			lb = lineBreaker{}
+			end, endW, endSpaces, spaces = i, 0, 0, 0
 			continue
 		}
-		if c == ' ' || isChinese(c) {
-			sep = i
-			ls = l
-			ns++
+		// Lines are only broken between grapheme clusters
+		if lb.next(c, !f.isCurrentUTF8) && i > j && (bounds == nil || bounds[i]) {
+			brk, sep, ls, ns = i, end, endW, endSpaces
 		}
-		if int(c) >= len(cw) {
-			f.err = fmt.Errorf("character outside the supported range: %s", string(c))
-			return
-		}
-		if cw[int(c)] == 0 { //Marker width 0 used for missing symbols
-			l += f.currentFont.Desc.MissingWidth
+		if int(c) >= len(cw) || cw[int(c)] == 0 { //Marker width 0 used for missing symbols
+			if !f.isCurrentUTF8 || !ignorable(c) {
+				l += float64(f.currentFont.Desc.MissingWidth)
+			}
 		} else if cw[int(c)] != 65535 { //Marker width 65535 used for zero width symbols
-			l += cw[int(c)]
+			l += float64(cw[int(c)])
 		}
-		if l > wmax {
+		l += f.spacingWidth(c)
+		if c == ' ' {
+			spaces++
+		} else {
+			end, endW, endSpaces = i+1, l, spaces
+		}
+		// Spaces at the end of a line may overflow it
+		if l > float64(wmax) && c != ' ' {
 			// Automatic line break
-			if sep == -1 {
-				if i == j {
-					i++
-				}
-				if f.ws > 0 {
-					f.ws = 0
-					f.out("0 Tw")
+			hySep := -1
+			if brk > j {
+				hySep = brk - 1
+			}
+			if k := f.hyphenBreak(hyRunes, j, hySep, i, wmax); k > 0 {
+				lineStr := f.runesString(hyRunes[j:k]) + "-"
+				ws = 0
+				if spaces := strings.Count(lineStr, " "); justify && !f.isCurrentUTF8 && spaces > 0 {
+					ws = (float64(wmax)/1000*f.fontSize - f.GetSpacedStringWidth(lineStr)) / float64(spaces)
 				}
+				cell(lineStr)
+				lines[len(lines)-1].hyphen = true
+				i = k
+			} else if brk == -1 {
+				i = graphemeBreak(bounds, j, i)
+				ws = 0
 				if f.isCurrentUTF8 {
-					f.CellFormat(w, h, string(srune[j:i]), b, 2, alignStr, fill, 0, "")
+					cell(string(srune[j:i]))
 				} else {
-					f.CellFormat(w, h, s[j:i], b, 2, alignStr, fill, 0, "")
+					cell(s[j:i])
 				}
 			} else {
-				if alignStr == "J" {
-					if ns > 1 {
-						f.ws = float64((wmax-ls)/1000) * f.fontSize / float64(ns-1)
+				if justify {
+					if ns > 0 {
+						ws = (float64(wmax) - ls) / 1000 * f.fontSize / float64(ns)
 					} else {
-						f.ws = 0
+						ws = 0
 					}
-					f.outf("%.3f Tw", f.ws*f.k)
 				}
 				if f.isCurrentUTF8 {
-					f.CellFormat(w, h, string(srune[j:sep]), b, 2, alignStr, fill, 0, "")
+					cell(string(srune[j:sep]))
 				} else {
-					f.CellFormat(w, h, s[j:sep], b, 2, alignStr, fill, 0, "")
+					cell(s[j:sep])
 				}
-				i = sep + 1
+				i = brk
 			}
-			sep = -1
+			brk = -1
 			j = i
 			l = 0
-			ns = 0
-			nl++
-			if len(borderStr) > 0 && nl == 2 {
-				b = b2
-			}
+			lb = lineBreaker{}
+			end, endW, endSpaces, spaces = i, 0, 0, 0
 		} else {
 			i++
 		}
 	}
 	// Last chunk
-	if f.ws > 0 {
-		f.ws = 0
-		f.out("0 Tw")
-	}
-	if len(borderStr) > 0 && strings.Contains(borderStr, "B") {
-		b += "B"
-	}
+	ws = 0
 	if f.isCurrentUTF8 {
-		if alignStr == "J" {
-			if f.isRTL {
-				alignStr = "R"
-			} else {
-				alignStr = ""
+		cell(string(srune[j:i]))
+	} else {
+		cell(s[j:i])
+	}
+	lines[len(lines)-1].last = true
+	if justify && f.letterJustify {
+		for j := range lines {
+			if line := &lines[j]; !line.last {
+				line.ws, line.cs = f.justifySpacing(line.str, w-2*f.cMargin)
 			}
 		}
-		f.CellFormat(w, h, string(srune[j:i]), b, 2, alignStr, fill, 0, "")
-	} else {
-		f.CellFormat(w, h, s[j:i], b, 2, alignStr, fill, 0, "")
 	}
-	f.x = f.lMargin
+	return
+}
+
+// multiCellLine is a line of text output by MultiCell().
+type multiCellLine struct {
+	str, border, align string
+	ws                 float64 // Word spacing
+	cs                 float64 // Character spacing
+	last               bool    // Line ended by a newline or by the end of the text
+	hyphen             bool    // Line ended by a hyphen of a hyphenated word
 }
 
 // write outputs text in flowing mode
 func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 	// dbg("Write")
-	cw := f.currentFont.Cw
+	if f.markupText(txtStr) {
+		f.markupWrite(h, txtStr, link, linkStr)
+		return
+	}
+	h = f.textLineHt(h)
+	if len(f.tabStops) > 0 && strings.Contains(txtStr, "\t") {
+		f.tabWrite(h, txtStr, link, linkStr)
+		return
+	}
+	txtStr = f.shapeText(txtStr)
+	if f.baseDirStr == "A" && f.isCurrentUTF8 {
+		f.baseDirStr = f.textDirection(txtStr)
+		defer func() { f.baseDirStr = "A" }()
+	}
+	cw := f.charWidths()
 	w := f.w - f.rMargin - f.x
 	wmax := (w - 2*f.cMargin) * 1000 / f.fontSize
 	s := strings.Replace(txtStr, "\r", "", -1)
@@ -2801,6 +3320,10 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 	} else {
 		nb = len(s)
 	}
+	var bounds []bool
+	if f.isCurrentUTF8 {
+		bounds = graphemeBounds([]rune(s))
+	}
 	sep := -1
 	i := 0
 	j := 0
@@ -2836,7 +3359,10 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 		if c == ' ' {
 			sep = i
 		}
-		l += float64(cw[int(c)])
+		if int(c) < len(cw) && cw[int(c)] != 65535 { // Marker width 65535 used for zero width symbols
+			l += float64(cw[int(c)])
+		}
+		l += f.spacingWidth(c)
 		if l > wmax {
 			// Automatic line break
 			if sep == -1 {
@@ -2850,9 +3376,7 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 					nl++
 					continue
 				}
-				if i == j {
-					i++
-				}
+				i = graphemeBreak(bounds, j, i)
 				if f.isCurrentUTF8 {
 					f.CellFormat(w, h, string([]rune(s)[j:i]), "", 2, "", false, link, linkStr)
 				} else {
@@ -2892,11 +3416,15 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 // Write prints text from the current position. When the right margin is
 // reached (or the \n character is met) a line break occurs and text continues
 // from the left margin. Upon method exit, the current position is left just at
-// the end of the text.
+// the end of the text. Text that follows a tab character is aligned at the
+// tab stops set with SetTabStops(). Inline markup styles parts of the text if
+// it is enabled with SetMarkup().
 //
 // It is possible to put a link on the text.
 //
-// h indicates the line height in the unit of measure specified in New().
+// h indicates the line height in the unit of measure specified in New(). If h
+// is 0, the line height set with SetLineHeight() or SetLineHeightFactor() is
+// used.
 func (f *Fpdf) Write(h float64, txtStr string) {
 	f.write(h, txtStr, 0, "")
 }
@@ -2952,7 +3480,7 @@ func (f *Fpdf) WriteAligned(width, lineHeight float64, textStr, alignStr string)
 
 	for _, lineBt := range lines {
 		lineStr := string(lineBt)
-		lineWidth := f.GetStringWidth(lineStr)
+		lineWidth := f.GetSpacedStringWidth(lineStr)
 
 		switch alignStr {
 		case "C":
@@ -2985,6 +3513,20 @@ func (f *Fpdf) Ln(h float64) {
 	}
 }
 
+// normalizeImageType returns the canonical, lower case form of the image type
+// tp, which may be given in any case and with any of the common file name
+// extensions of the type.
+func normalizeImageType(tp string) string {
+	tp = strings.ToLower(tp)
+	switch tp {
+	case "jpeg":
+		return "jpg"
+	case "jp2", "jpf", "j2k", "j2c", "jpc":
+		return "jpx"
+	}
+	return tp
+}
+
 // ImageTypeFromMime returns the image type used in various image-related
 // functions (for example, Image()) that is associated with the specified MIME
 // type. For example, "jpg" is returned if mimeStr is "image/jpeg". An error is
@@ -2999,18 +3541,30 @@ func (f *Fpdf) ImageTypeFromMime(mimeStr string) (tp string) {
 		tp = "jpg"
 	case "image/gif":
 		tp = "gif"
+	case "image/jp2", "image/jpx":
+		tp = "jpx"
 	default:
 		f.SetErrorf("unsupported image type: %s", mimeStr)
 	}
 	return
 }
 
-func (f *Fpdf) imageOut(info *ImageInfoType, x, y, w, h float64, allowNegativeX, flow bool, link int, linkStr string) {
+// imageExtent resolves the width and height, in user units, at which the
+// specified image is placed, following the conventions of ImageOptions().
+func (f *Fpdf) imageExtent(info *ImageInfoType, w, h float64) (float64, float64) {
+	if w <= 0 || h <= 0 {
+		// The intrinsic size of a deferred image is needed now
+		f.loadImage(info)
+	}
 	// Automatic width and height calculation if needed
 	if w == 0 && h == 0 {
-		// Put image at 96 dpi
+		// Put image at 96 dpi, counted in pixels of its file if it has been
+		// resampled
 		w = -96
 		h = -96
+		if info.rsRatio > 0 {
+			w, h = -96*info.rsRatio, -96*info.rsRatio
+		}
 	}
 	if w == -1 {
 		// Set image width to whatever value for dpi we read
@@ -3022,31 +3576,89 @@ func (f *Fpdf) imageOut(info *ImageInfoType, x, y, w, h float64, allowNegativeX,
 		// from the image or that was set manually
 		h = -info.dpi
 	}
+	iw, ih := info.size()
 	if w < 0 {
-		w = -info.w * 72.0 / w / f.k
+		w = -iw * 72.0 / w / f.k
 	}
 	if h < 0 {
-		h = -info.h * 72.0 / h / f.k
+		h = -ih * 72.0 / h / f.k
 	}
 	if w == 0 {
-		w = h * info.w / info.h
+		w = h * iw / ih
 	}
 	if h == 0 {
-		h = w * info.h / info.w
+		h = w * ih / iw
+	}
+	return w, h
+}
+
+// imageFlow returns the ordinate at which an image of height h is placed in
+// flowing mode, issuing a page break if necessary, and advances the current
+// position past the image.
+func (f *Fpdf) imageFlow(h float64) (y float64) {
+	if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptBreak() {
+		// Automatic page break
+		x2 := f.x
+		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		if f.err != nil {
+			return
+		}
+		f.x = x2
+	}
+	y = f.y
+	f.y += h
+	return
+}
+
+// imageFit returns the position, relative to a box of width w and height h,
+// and the extent at which the specified image is placed in the box according
+// to the Fit and AlignStr fields of options. clip is true if the image extends
+// beyond the box.
+func (f *Fpdf) imageFit(info *ImageInfoType, w, h float64, options ImageOptions) (dx, dy, iw, ih float64, clip bool) {
+	nw, nh := f.imageExtent(info, 0, 0)
+	switch strings.ToLower(options.Fit) {
+	case ImageFitStretch:
+		return 0, 0, w, h, false
+	case ImageFitContain:
+		scale := math.Min(w/nw, h/nh)
+		iw, ih = nw*scale, nh*scale
+	case ImageFitCover:
+		scale := math.Max(w/nw, h/nh)
+		iw, ih = nw*scale, nh*scale
+	case ImageFitNone:
+		iw, ih = nw, nh
+	default:
+		f.err = fmt.Errorf("unsupported image fit: %s", options.Fit)
+		return
+	}
+	alignStr := strings.ToUpper(options.AlignStr)
+	switch {
+	case strings.Contains(alignStr, "L"):
+	case strings.Contains(alignStr, "R"):
+		dx = w - iw
+	default:
+		dx = (w - iw) / 2
+	}
+	switch {
+	case strings.Contains(alignStr, "T"):
+	case strings.Contains(alignStr, "B"):
+		dy = h - ih
+	default:
+		dy = (h - ih) / 2
 	}
+	clip = iw > w || ih > h
+	return
+}
+
+func (f *Fpdf) imageOut(info *ImageInfoType, x, y, w, h float64, allowNegativeX, flow bool, link int, linkStr string) {
+	w, h = f.imageExtent(info, w, h)
+	info.placed = true
 	// Flowing mode
 	if flow {
-		if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreak() {
-			// Automatic page break
-			x2 := f.x
-			f.AddPageFormat(f.curOrientation, f.curPageSize)
-			if f.err != nil {
-				return
-			}
-			f.x = x2
+		y = f.imageFlow(h)
+		if f.err != nil {
+			return
 		}
-		y = f.y
-		f.y += h
 	}
 	if !allowNegativeX {
 		if x < 0 {
@@ -3055,7 +3667,25 @@ func (f *Fpdf) imageOut(info *ImageInfoType, x, y, w, h float64, allowNegativeX,
 	}
 	// dbg("h %.2f", h)
 	// q 85.04 0 0 NaN 28.35 NaN cm /I2 Do Q
-	f.outf("q %.5f 0 0 %.5f %.5f %.5f cm /I%s Do Q", w*f.k, h*f.k, x*f.k, (f.h-(y+h))*f.k, info.i)
+	wk, hk, xk, yk := w*f.k, h*f.k, x*f.k, (f.h-(y+h))*f.k
+	switch info.ori {
+	case 2: // Mirrored horizontally
+		f.outf("q %.5f 0 0 %.5f %.5f %.5f cm /I%s Do Q", -wk, hk, xk+wk, yk, info.i)
+	case 3: // Rotated 180 degrees
+		f.outf("q %.5f 0 0 %.5f %.5f %.5f cm /I%s Do Q", -wk, -hk, xk+wk, yk+hk, info.i)
+	case 4: // Mirrored vertically
+		f.outf("q %.5f 0 0 %.5f %.5f %.5f cm /I%s Do Q", wk, -hk, xk, yk+hk, info.i)
+	case 5: // Transposed
+		f.outf("q 0 %.5f %.5f 0 %.5f %.5f cm /I%s Do Q", -hk, -wk, xk+wk, yk+hk, info.i)
+	case 6: // Rotated 90 degrees clockwise
+		f.outf("q 0 %.5f %.5f 0 %.5f %.5f cm /I%s Do Q", -hk, wk, xk, yk+hk, info.i)
+	case 7: // Transversed
+		f.outf("q 0 %.5f %.5f 0 %.5f %.5f cm /I%s Do Q", hk, wk, xk, yk, info.i)
+	case 8: // Rotated 90 degrees counter-clockwise
+		f.outf("q 0 %.5f %.5f 0 %.5f %.5f cm /I%s Do Q", hk, -wk, xk+wk, yk, info.i)
+	default:
+		f.outf("q %.5f 0 0 %.5f %.5f %.5f cm /I%s Do Q", wk, hk, xk, yk, info.i)
+	}
 	if link > 0 || len(linkStr) > 0 {
 		f.newLink(x, y, w, h, link, linkStr)
 	}
@@ -3086,10 +3716,13 @@ func (f *Fpdf) Image(imageNameStr string, x, y, w, h float64, flow bool, tp stri
 // If w and h are any other negative value, their absolute values
 // indicate their dpi extents.
 //
-// Supported JPEG formats are 24 bit, 32 bit and gray scale. Supported PNG
-// formats are 24 bit, indexed color, and 8 bit indexed gray scale. If a GIF
-// image is animated, only the first frame is rendered. Transparency is
-// supported. It is possible to put a link on the image.
+// Supported JPEG formats are 24 bit, 32 bit and gray scale, with either
+// baseline or progressive encoding. Supported PNG formats are 24 bit, indexed
+// color, and 8 bit indexed gray scale. Interlaced PNG images are supported,
+// and PNG images with 16 bit samples are reduced to 8 bits per sample. If a
+// GIF image is animated, only the first frame is rendered. JPEG 2000 images,
+// either JP2 files or raw codestreams, are embedded without decoding.
+// Transparency is supported. It is possible to put a link on the image.
 //
 // imageNameStr may be the name of an image as registered with a call to either
 // RegisterImageReader() or RegisterImage(). In the first case, the image is
@@ -3101,6 +3734,10 @@ func (f *Fpdf) Image(imageNameStr string, x, y, w, h float64, flow bool, tp stri
 // However the image is loaded, if it is used more than once only one copy is
 // embedded in the file.
 //
+// If pass-through is enabled with SetImagePassThrough(), the data of a JPEG
+// image loaded from a file, or from an io.Reader that is an *os.File, is not
+// held in memory but copied from the file when the document is written.
+//
 // If x is negative, the current abscissa is used.
 //
 // If flow is true, the current y value is advanced after placing the image and
@@ -3117,8 +3754,77 @@ func (f *Fpdf) ImageOptions(imageNameStr string, x, y, w, h float64, flow bool,
 	if f.err != nil {
 		return
 	}
-	f.imageOut(info, x, y, w, h, options.AllowNegativePosition, flow, link, linkStr)
-	return
+	transform := options.Rotate != 0 || options.FlipH || options.FlipV
+	if options.Fit == "" && !transform {
+		if options.MaxDPI > 0 && !info.placed {
+			w, h = f.imageExtent(info, w, h)
+			info = f.downsampleImage(imageNameStr, info, w, h, options)
+			if f.err != nil {
+				return
+			}
+		}
+		f.imageOut(info, x, y, w, h, options.AllowNegativePosition, flow, link, linkStr)
+		return
+	}
+	// The image is placed at offset (dx, dy) with extent (iw, ih) in the box
+	// (x, y, w, h), which flows and carries the link
+	var dx, dy, iw, ih float64
+	var clip bool
+	if options.Fit != "" {
+		if w <= 0 || h <= 0 {
+			f.err = fmt.Errorf("image fit requires a positive width and height")
+			return
+		}
+		dx, dy, iw, ih, clip = f.imageFit(info, w, h, options)
+		if f.err != nil {
+			return
+		}
+	} else {
+		w, h = f.imageExtent(info, w, h)
+		iw, ih = w, h
+	}
+	if options.MaxDPI > 0 && !info.placed {
+		info = f.downsampleImage(imageNameStr, info, iw, ih, options)
+		if f.err != nil {
+			return
+		}
+	}
+	if flow {
+		y = f.imageFlow(h)
+		if f.err != nil {
+			return
+		}
+	}
+	if !options.AllowNegativePosition && x < 0 {
+		x = f.x
+	}
+	if clip {
+		f.ClipRect(x, y, w, h, false)
+	}
+	if transform {
+		cx, cy := x+dx+iw/2, y+dy+ih/2
+		f.TransformBegin()
+		// The image is mirrored first, then rotated
+		if options.Rotate != 0 {
+			f.TransformRotate(options.Rotate, cx, cy)
+		}
+		if options.FlipH {
+			f.TransformMirrorHorizontal(cx)
+		}
+		if options.FlipV {
+			f.TransformMirrorVertical(cy)
+		}
+	}
+	f.imageOut(info, x+dx, y+dy, iw, ih, true, false, 0, "")
+	if transform {
+		f.TransformEnd()
+	}
+	if clip {
+		f.ClipEnd()
+	}
+	if link > 0 || len(linkStr) > 0 {
+		f.newLink(x, y, w, h, link, linkStr)
+	}
 }
 
 // RegisterImageReader registers an image, reading it from Reader r, adding it
@@ -3137,8 +3843,8 @@ func (f *Fpdf) RegisterImageReader(imgName, tp string, r io.Reader) (info *Image
 // parsing an image.
 //
 // ImageType's possible values are (case insensitive):
-// "JPG", "JPEG", "PNG" and "GIF". If empty, the type is inferred from
-// the file extension.
+// "JPG", "JPEG", "PNG", "GIF" and, for JPEG 2000 images, "JPX", "JP2" and
+// "J2K". If empty, the type is inferred from the file extension.
 //
 // ReadDpi defines whether to attempt to automatically read the image
 // dpi information from the image file. Normally, this should be set
@@ -3148,16 +3854,119 @@ func (f *Fpdf) RegisterImageReader(imgName, tp string, r io.Reader) (info *Image
 //
 // AllowNegativePosition can be set to true in order to prevent the default
 // coercion of negative x values to the current x position.
+//
+// IgnoreOrientation can be set to true in order to place a JPEG image as
+// stored, disregarding the rotation or mirroring called for by its EXIF
+// orientation tag.
+//
+// MaxDPI, if greater than zero, limits the resolution of an image file placed
+// with ImageOptions(). If the image, at the size it is placed, has a higher
+// resolution, it is resampled to MaxDPI the first time it is placed, in its
+// own color space, whether it is registered then or has been registered
+// beforehand, as with RegisterImageReader(). The resampled copy replaces the
+// image and keeps its natural size, so subsequent placements of the same
+// image use it. An image that has already been placed is not resampled, as
+// its earlier placements refer to it. JPEG 2000 and bilevel images are not
+// resampled.
+//
+// Bilevel can be set to true in order to convert the image to black and white
+// and embed it with CCITT Group 4 compression, which is far more compact than
+// the default compression for scanned documents and line art. Pixels with a
+// gray level below Threshold are rendered black; a Threshold of zero selects
+// the default of 128. Transparent regions are rendered white.
+//
+// Recompress, if its ImageType is set, re-encodes the image in the specified
+// format when it is registered. This can considerably reduce the size of
+// documents containing photographs or screenshots stored as PNG images. The
+// only supported format is "jpg"; see ImageRecompressType for the quality
+// setting. Transparent regions are rendered white. Bilevel takes precedence
+// over Recompress.
+//
+// ImageMask can be set to true in order to register the image as a stencil
+// mask. The image is converted to black and white as with Bilevel. When the
+// mask is placed, its black regions are painted with the current fill color
+// and its white regions leave the page unchanged. A stencil mask can also be
+// attached to another image with SetImageMask().
+//
+// SMaskImage, if not empty, names a registered gray scale image that is
+// attached to the image as a soft mask; see SetImageMask(). This allows color
+// and transparency to be delivered as separate files. The mask must be
+// registered before the image.
+//
+// Fit, if not empty, places the image within the box specified by the x, y,
+// w and h arguments of ImageOptions(), which must have a positive width and
+// height. ImageFitContain scales the image to the largest size that fits the
+// box, ImageFitCover scales it to the smallest size that covers the box,
+// ImageFitStretch scales it to the box without regard to its aspect ratio and
+// ImageFitNone places it at its natural size. Any part of the image that
+// extends beyond the box is clipped. AlignStr positions the image within the
+// box: horizontally with "L", "C" or "R" (left, center, right) and vertically
+// with "T", "M" or "B" (top, middle, bottom). By default, the image is
+// centered in both directions. In flowing mode, the box height is used to
+// advance the current position.
+//
+// Rotate specifies an angle in degrees, measured counter-clockwise, by which
+// the image is rotated about its center. FlipH and FlipV can be set to true
+// in order to mirror the image horizontally or vertically about its center.
+// Mirroring precedes rotation. The position and size of the image, and so its
+// link area and the advance in flowing mode, are those of the image before it
+// is transformed.
+//
+// Page selects the zero-based page of a multi-page image, such as a TIFF
+// file registered with the tiff contrib package. It defaults to the first
+// page.
+//
+// Frame selects the zero-based frame of an animated PNG (APNG) image. It
+// defaults to the first frame, which is the default image shown by viewers
+// that do not support animation, whether or not that image is part of the
+// animation. APNG frames are embedded as stored, with the dimensions given in
+// their frame control chunks; they are not composited with preceding frames.
+//
+// MaxSize, if greater than zero, is the largest size in bytes of an image
+// that RegisterImageURL() accepts.
 type ImageOptions struct {
 	ImageType             string
 	ReadDpi               bool
 	AllowNegativePosition bool
+	IgnoreOrientation     bool
+	MaxDPI                float64
+	Bilevel               bool
+	Threshold             uint8
+	ImageMask             bool
+	Recompress            ImageRecompressType
+	SMaskImage            string
+	Fit                   string
+	AlignStr              string
+	Rotate                float64
+	FlipH                 bool
+	FlipV                 bool
+	Page                  int
+	Frame                 int
+	MaxSize               int64
+}
+
+// Values for the Fit field of ImageOptions
+const (
+	ImageFitContain = "contain"
+	ImageFitCover   = "cover"
+	ImageFitStretch = "stretch"
+	ImageFitNone    = "none"
+)
+
+// ImageRecompressType specifies the format, and for JPEG the quality, in
+// which an image is re-encoded. ImageType may be "jpg" or "jpeg". Quality
+// ranges from 1 to 100, with higher values giving better fidelity at the
+// expense of size; zero selects the default of 75.
+type ImageRecompressType struct {
+	ImageType string
+	Quality   int
 }
 
 // RegisterImageOptionsReader registers an image, reading it from Reader r, adding it
 // to the PDF file but not adding it to the page. Use Image() with the same
 // name to add the image to the page. Note that tp should be specified in this
-// case.
+// case. If an image with identical content has already been registered under
+// another name, both names refer to a single image object in the document.
 //
 // See Image() for restrictions on the image and the options parameters.
 func (f *Fpdf) RegisterImageOptionsReader(imgName string, options ImageOptions, r io.Reader) (info *ImageInfoType) {
@@ -3175,29 +3984,156 @@ func (f *Fpdf) RegisterImageOptionsReader(imgName string, options ImageOptions,
 		f.err = fmt.Errorf("image type should be specified if reading from custom reader")
 		return
 	}
-	options.ImageType = strings.ToLower(options.ImageType)
-	if options.ImageType == "jpeg" {
-		options.ImageType = "jpg"
+	options.ImageType = normalizeImageType(options.ImageType)
+	if info = f.cachedImage(imgName, options); info == nil {
+		info = f.parseImageOptions(options, r)
+		if f.err != nil {
+			return
+		}
+		info = f.addImage(imgName, info)
+		if f.imageCache != nil && f.err == nil {
+			f.imageCache.put(imgName, options, info)
+		}
+	}
+	return f.softMaskOption(imgName, info, options)
+}
+
+// addImage assigns an identifier to the newly parsed image info and registers
+// it as imgName.
+func (f *Fpdf) addImage(imgName string, info *ImageInfoType) *ImageInfoType {
+	if info.i, f.err = generateImageID(info); f.err != nil {
+		return nil
+	}
+	f.storeImage(imgName, info)
+	return info
+}
+
+// storeImage registers info as imgName. If identical content has been
+// registered under another name, info takes over its data, so that the
+// content is held once and written as a single object, while each name keeps
+// a descriptor of its own for settings such as the resolution and masks.
+func (f *Fpdf) storeImage(imgName string, info *ImageInfoType) {
+	if img, ok := f.imageData[info.i]; ok {
+		info.data, info.smask, info.pal, info.icc = img.data, img.smask, img.pal, img.icc
+	} else {
+		f.imageData[info.i] = info
+	}
+	f.images[imgName] = info
+}
+
+// RegisterImageOptionsReaderFunc registers an image like
+// RegisterImageOptionsReader() but defers reading it until the document is
+// output. The function open is called at that time to obtain the image
+// data; if the returned reader implements io.Closer, it is closed when the
+// image has been read. This allows a large number of images to be registered
+// without holding their data or connections open in the meantime.
+//
+// The dimensions of a deferred image are not known until it is read. If the
+// image is placed with an explicit width and height, reading is deferred
+// until output. Otherwise, the image is read when it is first placed. The
+// EXIF orientation of a deferred JPEG image is disregarded. Since the content
+// of a deferred image is not known at registration, it is not shared with
+// identical images registered under other names.
+func (f *Fpdf) RegisterImageOptionsReaderFunc(imgName string, options ImageOptions, open func() (io.Reader, error)) (info *ImageInfoType) {
+	if f.err != nil {
+		return
+	}
+	info, ok := f.images[imgName]
+	if ok {
+		return
+	}
+	options.ImageType = normalizeImageType(options.ImageType)
+	switch options.ImageType {
+	case "jpg", "png", "gif", "jpx":
+	default:
+		f.err = fmt.Errorf("unsupported image type: %s", options.ImageType)
+		return
+	}
+	info = f.newImageInfo()
+	info.open = open
+	info.opt = options
+	// The content is not yet available, so the identifier is derived from the
+	// name, which is unique among registered images
+	info.i = fmt.Sprintf("%x", sha1.Sum([]byte("deferred:"+imgName)))
+	f.images[imgName] = info
+	return f.softMaskOption(imgName, info, options)
+}
+
+// loadImage reads and parses a deferred image, replacing the placeholder
+// fields of info with those of the image. The identifier of info, which may
+// already be referenced in page content, is retained.
+func (f *Fpdf) loadImage(info *ImageInfoType) {
+	if info.open == nil || f.err != nil {
+		return
+	}
+	r, err := info.open()
+	if err != nil {
+		f.err = err
+		return
+	}
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+	options := info.opt
+	options.IgnoreOrientation = true
+	img := f.parseImageOptions(options, r)
+	if f.err != nil {
+		return
+	}
+	img.i = info.i
+	if info.mask != nil {
+		// An attached mask replaces the transparency of the image
+		img.mask = info.mask
+		img.smask, img.trns = nil, nil
+	}
+	img.alts = info.alts
+	*info = *img
+}
+
+// parseImageOptions parses the image read from r according to options, the
+// image type of which has been normalized.
+func (f *Fpdf) parseImageOptions(options ImageOptions, r io.Reader) (info *ImageInfoType) {
+	var buf []byte
+	bilevel := options.Bilevel || options.ImageMask
+	recompress := options.Recompress.ImageType != ""
+	if bilevel || recompress {
+		// The image is decoded a second time for thresholding or re-encoding
+		if buf, f.err = ioutil.ReadAll(r); f.err != nil {
+			return
+		}
+		r = bytes.NewReader(buf)
 	}
 	switch options.ImageType {
 	case "jpg":
 		info = f.parsejpg(r)
 	case "png":
-		info = f.parsepng(r, options.ReadDpi)
+		info = f.parsepng(r, options.ReadDpi, options.Frame)
 	case "gif":
 		info = f.parsegif(r)
+	case "jpx":
+		info = f.parsejpx(r)
 	default:
 		f.err = fmt.Errorf("unsupported image type: %s", options.ImageType)
 	}
 	if f.err != nil {
 		return
 	}
-
-	if info.i, f.err = generateImageID(info); f.err != nil {
-		return
+	if options.IgnoreOrientation {
+		info.ori = 0
+	}
+	if bilevel || recompress {
+		img, _, err := image.Decode(bytes.NewReader(buf))
+		if err != nil {
+			f.err = err
+			return
+		}
+		if bilevel {
+			bilevelImage(info, img, options.Threshold)
+			info.stencil = options.ImageMask
+		} else {
+			info = f.recompressImage(info, img, options.Recompress)
+		}
 	}
-	f.images[imgName] = info
-
 	return
 }
 
@@ -3227,13 +4163,6 @@ func (f *Fpdf) RegisterImageOptions(fileStr string, options ImageOptions) (info
 		return
 	}
 
-	file, err := os.Open(fileStr)
-	if err != nil {
-		f.err = err
-		return
-	}
-	defer file.Close()
-
 	// First use of this image, get info
 	if options.ImageType == "" {
 		pos := strings.LastIndex(fileStr, ".")
@@ -3243,6 +4172,16 @@ func (f *Fpdf) RegisterImageOptions(fileStr string, options ImageOptions) (info
 		}
 		options.ImageType = fileStr[pos+1:]
 	}
+	if info = f.cachedImage(fileStr, options); info != nil {
+		return f.softMaskOption(fileStr, info, options)
+	}
+
+	file, err := os.Open(fileStr)
+	if err != nil {
+		f.err = err
+		return
+	}
+	defer file.Close()
 
 	return f.RegisterImageOptionsReader(fileStr, options, file)
 }
@@ -3254,6 +4193,124 @@ func (f *Fpdf) GetImageInfo(imageStr string) (info *ImageInfoType) {
 	return f.images[imageStr]
 }
 
+// SetImageMask attaches the registered image maskName as a mask to the
+// registered image imgName. If the mask was registered with the ImageMask
+// option, it is applied as a stencil mask: the image is painted where the
+// mask is black and masked out where it is white. Otherwise, the mask must be
+// a gray scale image, which is applied as a soft mask: its gray levels
+// specify the opacity of the image, from transparent for black to opaque for
+// white. The mask replaces any transparency of the image itself. The two
+// images need not have the same dimensions.
+//
+// The mask applies to every placement of imgName, so SetImageMask should be
+// called before the image is placed. Other names under which the same image
+// content has been registered are not affected.
+func (f *Fpdf) SetImageMask(imgName, maskName string) {
+	f.setImageMask(imgName, maskName, false)
+}
+
+// softMaskOption attaches the soft mask specified by options.SMaskImage, if
+// any, to the image registered as imgName and returns the resulting image.
+func (f *Fpdf) softMaskOption(imgName string, info *ImageInfoType, options ImageOptions) *ImageInfoType {
+	if options.SMaskImage == "" || f.err != nil {
+		return info
+	}
+	return f.setImageMask(imgName, options.SMaskImage, true)
+}
+
+// setImageMask implements SetImageMask(). If softOnly is true, a stencil
+// mask is rejected. The masked image is returned.
+func (f *Fpdf) setImageMask(imgName, maskName string, softOnly bool) (info *ImageInfoType) {
+	if f.err != nil {
+		return
+	}
+	info, ok := f.images[imgName]
+	if !ok {
+		f.err = fmt.Errorf("image %s has not been registered", imgName)
+		return
+	}
+	mask, ok := f.images[maskName]
+	if !ok {
+		f.err = fmt.Errorf("mask image %s has not been registered", maskName)
+		return
+	}
+	if info.stencil {
+		f.err = fmt.Errorf("a mask cannot be attached to stencil mask %s", imgName)
+		return
+	}
+	if mask.open != nil {
+		f.loadImage(mask)
+		if f.err != nil {
+			return
+		}
+	}
+	if mask.stencil && softOnly {
+		f.err = fmt.Errorf("soft mask image %s is a stencil mask", maskName)
+		return
+	}
+	if !mask.stencil {
+		if mask.cs != "DeviceGray" || len(mask.smask) > 0 || mask.mask != nil {
+			f.err = fmt.Errorf("mask image %s is neither a stencil mask nor an opaque gray scale image", maskName)
+			return
+		}
+		if f.pdfVersion < "1.4" {
+			f.pdfVersion = "1.4"
+		}
+	}
+	// The masked image is a distinct object, leaving other names that share
+	// the unmasked content undisturbed
+	masked := *info
+	masked.smask = nil
+	masked.trns = nil
+	masked.mask = mask
+	masked.i = attachedImageID(info.i, "mask:"+mask.i)
+	f.images[imgName] = &masked
+	return &masked
+}
+
+// AddImageAlternate adds the registered image altName as an alternate of the
+// registered image imgName. A PDF reader may substitute an alternate for the
+// image; for example, a low resolution image intended for display on screen
+// can carry a high resolution alternate for printing. If forPrinting is true,
+// the alternate is marked as the default for printing. An image may have
+// several alternates but an alternate may not have alternates of its own.
+//
+// The alternate applies to every placement of imgName, so AddImageAlternate
+// should be called before the image is placed. Other names under which the
+// same image content has been registered are not affected. Note that not all
+// PDF readers honor alternate images.
+func (f *Fpdf) AddImageAlternate(imgName, altName string, forPrinting bool) {
+	if f.err != nil {
+		return
+	}
+	info, ok := f.images[imgName]
+	if !ok {
+		f.err = fmt.Errorf("image %s has not been registered", imgName)
+		return
+	}
+	alt, ok := f.images[altName]
+	if !ok {
+		f.err = fmt.Errorf("alternate image %s has not been registered", altName)
+		return
+	}
+	if len(alt.alts) > 0 {
+		f.err = fmt.Errorf("alternate image %s has alternates of its own", altName)
+		return
+	}
+	// As with masks, the image with alternates is a distinct object
+	cp := *info
+	cp.alts = append(append([]imageAltType(nil), info.alts...), imageAltType{img: alt, print: forPrinting})
+	cp.i = attachedImageID(info.i, sprintf("alt:%s:%v", alt.i, forPrinting))
+	f.images[imgName] = &cp
+}
+
+// attachedImageID returns the identifier of an image with the specified
+// attachment, such as a mask, derived from the identifier id of the image
+// without it
+func attachedImageID(id, attachment string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(id+"/"+attachment)))
+}
+
 // ImportObjects imports objects from gofpdi into current document
 func (f *Fpdf) ImportObjects(objs map[string][]byte) {
 	for k, v := range objs {
@@ -3523,6 +4580,7 @@ func (f *Fpdf) beginpage(orientationStr string, size SizeType) {
 	f.x = f.lMargin
 	f.y = f.tMargin
 	f.fontFamily = ""
+	f.ctm = TransformMatrix{A: 1, D: 1}
 	// Check page size and orientation
 	if orientationStr == "" {
 		orientationStr = f.defOrientation
@@ -3619,7 +4677,8 @@ func (f *Fpdf) SetUnderlineThickness(thickness float64) {
 func (f *Fpdf) dounderline(x, y float64, txt string) string {
 	up := float64(f.currentFont.Up)
 	ut := float64(f.currentFont.Ut) * f.userUnderlineThickness
-	w := f.GetStringWidth(txt) + f.ws*float64(blankCount(txt))
+	w := f.GetSpacedStringWidth(txt) + f.ws*float64(blankCount(txt))
+	y -= f.textRise
 	return sprintf("%.2f %.2f %.2f %.2f re f", x*f.k,
 		(f.h-(y-up/1000*f.fontSize))*f.k, w*f.k, -ut/1000*f.fontSizePt)
 }
@@ -3627,7 +4686,8 @@ func (f *Fpdf) dounderline(x, y float64, txt string) string {
 func (f *Fpdf) dostrikeout(x, y float64, txt string) string {
 	up := float64(f.currentFont.Up)
 	ut := float64(f.currentFont.Ut)
-	w := f.GetStringWidth(txt) + f.ws*float64(blankCount(txt))
+	w := f.GetSpacedStringWidth(txt) + f.ws*float64(blankCount(txt))
+	y -= f.textRise
 	return sprintf("%.2f %.2f %.2f %.2f re f", x*f.k,
 		(f.h-(y+4*up/1000*f.fontSize))*f.k, w*f.k, -ut/1000*f.fontSizePt)
 }
@@ -3649,48 +4709,72 @@ func (f *Fpdf) newImageInfo() *ImageInfoType {
 // Thank you, Bruno Michel, for providing this code.
 func (f *Fpdf) parsejpg(r io.Reader) (info *ImageInfoType) {
 	info = f.newImageInfo()
-	var (
-		data bytes.Buffer
-		err  error
-	)
-	_, err = data.ReadFrom(r)
+	var data bytes.Buffer
+	var dst io.Writer = &data
+	// With pass-through, the data of a JPEG file is not held in memory; it is
+	// copied from the file when the document is written
+	var jf *jpegFileType
+	if f.imagePassThrough {
+		jf = newJpegFile(r)
+	}
+	if jf != nil {
+		dst = jf.sum
+	}
+	hdr, err := parsejpgstream(r, dst)
 	if err != nil {
 		f.err = err
 		return
 	}
-	info.data = data.Bytes()
-
-	config, err := jpeg.DecodeConfig(bytes.NewReader(info.data))
-	if err != nil {
-		f.err = err
+	if jf != nil {
+		if f.err = jf.source(info); f.err != nil {
+			return
+		}
+	} else {
+		info.data = data.Bytes()
+	}
+	// The DCTDecode filter handles Huffman-coded baseline, extended sequential
+	// and progressive images. Progressive images are embedded as is.
+	if hdr.sof > 0xC2 {
+		f.err = fmt.Errorf("image JPEG buffer uses an unsupported coding process (SOF%d)", hdr.sof-0xC0)
+		return
+	}
+	if hdr.precision != 8 {
+		f.err = fmt.Errorf("image JPEG buffer has unsupported sample precision (%d)", hdr.precision)
 		return
 	}
-	info.w = float64(config.Width)
-	info.h = float64(config.Height)
+	info.w = float64(hdr.width)
+	info.h = float64(hdr.height)
+	info.ori = exifOrientation(hdr.exif)
 	info.f = "DCTDecode"
 	info.bpc = 8
-	switch config.ColorModel {
-	case color.GrayModel:
+	switch hdr.components {
+	case 1:
 		info.cs = "DeviceGray"
-	case color.YCbCrModel:
+	case 3:
 		info.cs = "DeviceRGB"
-	case color.CMYKModel:
+	case 4:
 		info.cs = "DeviceCMYK"
+		if hdr.adobe {
+			// Adobe applications write CMYK and YCCK samples inverted. The
+			// DCTDecode filter converts YCCK to CMYK as directed by the APP14
+			// transform flag, but the inversion must be undone here.
+			info.dec = "1 0 1 0 1 0 1 0"
+		}
 	default:
-		f.err = fmt.Errorf("image JPEG buffer has unsupported color space (%v)", config.ColorModel)
+		f.err = fmt.Errorf("image JPEG buffer has unsupported number of color components (%d)", hdr.components)
 		return
 	}
 	return
 }
 
 // parsepng extracts info from a PNG data
-func (f *Fpdf) parsepng(r io.Reader, readdpi bool) (info *ImageInfoType) {
+func (f *Fpdf) parsepng(r io.Reader, readdpi bool, frame int) (info *ImageInfoType) {
 	buf, err := bufferFromReader(r)
 	if err != nil {
 		f.err = err
 		return
 	}
-	return f.parsepngstream(buf, readdpi)
+	return f.parsepngstream(buf, readdpi, frame)
 }
 
 func (f *Fpdf) readBeInt32(r io.Reader) (val int32) {
@@ -3711,13 +4795,9 @@ func (f *Fpdf) readByte(r io.Reader) (val byte) {
 
 // parsegif extracts info from a GIF data (via PNG conversion)
 func (f *Fpdf) parsegif(r io.Reader) (info *ImageInfoType) {
-	data, err := bufferFromReader(r)
-	if err != nil {
-		f.err = err
-		return
-	}
-	var img image.Image
-	img, err = gif.Decode(data)
+	// Only the first frame is decoded. It is re-encoded as an indexed PNG so
+	// that the palette and transparent color are handled by the PNG parser.
+	img, err := gif.Decode(r)
 	if err != nil {
 		f.err = err
 		return
@@ -3728,7 +4808,7 @@ func (f *Fpdf) parsegif(r io.Reader) (info *ImageInfoType) {
 		f.err = err
 		return
 	}
-	return f.parsepngstream(pngBuf, false)
+	return f.parsepngstream(pngBuf, false, 0)
 }
 
 // newobj begins a new object
@@ -3752,6 +4832,38 @@ func (f *Fpdf) putstream(b []byte) {
 	f.out("endstream")
 }
 
+// putsourcestream writes the data of an image passed through from a file as
+// a stream, copying it from the file to the document.
+func (f *Fpdf) putsourcestream(info *ImageInfoType) {
+	r, err := info.src()
+	if err != nil {
+		f.err = err
+		return
+	}
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+	// The data is checked against the checksum computed when the image was
+	// registered
+	sum := sha1.New()
+	r = io.TeeReader(r, sum)
+	if f.protect.encrypted {
+		r = f.protect.rc4Reader(uint32(f.n), r)
+	}
+	f.out("stream")
+	n, err := io.CopyN(&f.buffer, r, info.srcLen)
+	if err != nil {
+		f.err = fmt.Errorf("image data ended after %d of %d bytes: %s", n, info.srcLen, err)
+		return
+	}
+	if !bytes.Equal(sum.Sum(nil), info.srcSum) {
+		f.err = fmt.Errorf("image data has changed since the image was registered")
+		return
+	}
+	f.out("")
+	f.out("endstream")
+}
+
 // out; Add a line to the document
 func (f *Fpdf) out(s string) {
 	if f.state == 2 {
@@ -3802,6 +4914,18 @@ func SetDefaultCatalogSort(flag bool) {
 	gl.catalogSort = flag
 }
 
+// SetImagePassThrough sets whether the data of JPEG images loaded from files,
+// or from io.Readers that are *os.File values, is passed through to the
+// document when it is written rather than held in memory. When enabled, the
+// file is read through when the image is registered, to parse its headers and
+// compute its checksum, and opened again by name to copy its data when the
+// document is written, so the file must remain unchanged until then. The
+// document fails with an error if the data has changed. Pass-through is
+// disabled by default.
+func (f *Fpdf) SetImagePassThrough(enabled bool) {
+	f.imagePassThrough = enabled
+}
+
 // SetCatalogSort sets a flag that will be used, if true, to consistently order
 // the document's internal resource catalogs. This method is typically only
 // used for test purposes to facilitate PDF comparison.
@@ -4105,36 +5229,69 @@ func (f *Fpdf) putfonts() {
 				f.out(s.String())
 				f.out("endobj")
 			case "UTF8":
-				fontName := "utf8" + font.Name
-				usedRunes := font.usedRunes
-				delete(usedRunes, 0)
-				utf8FontStream := font.utf8File.GenerateCutFont(usedRunes)
+				delete(font.usedRunes, 0)
+				usedRunes := font.embeddedRunes()
+				// The tag marks the embedded font program as a subset
+				fontName := subsetTag(font.Name, usedRunes) + "+utf8" + font.Name
+				if font.embedFull {
+					fontName = "utf8" + font.Name
+				}
+				// Fonts with PostScript outlines are embedded as CFF, without a
+				// CIDToGIDMap
+				cff := font.utf8File.isCFF()
+				var utf8FontStream []byte
+				if cff {
+					utf8FontStream, f.err = font.utf8File.generateCutCFF(usedRunes, font.cids)
+					if f.err != nil {
+						return
+					}
+				} else {
+					utf8FontStream = font.utf8File.GenerateCutFont(usedRunes)
+				}
+				if f.err = font.utf8File.fileReader.err; f.err != nil {
+					return
+				}
 				utf8FontSize := len(utf8FontStream)
 				compressedFontStream := sliceCompress(utf8FontStream)
 				CodeSignDictionary := font.utf8File.CodeSymbolDictionary
 				delete(CodeSignDictionary, 0)
+				cidToGlyph := make(map[int]int, len(CodeSignDictionary))
+				for r, glyph := range CodeSignDictionary {
+					if cid := font.cid(r); cid > 0 {
+						cidToGlyph[cid] = glyph
+					}
+				}
 
 				f.newobj()
 				f.out(fmt.Sprintf("<</Type /Font\n/Subtype /Type0\n/BaseFont /%s\n/Encoding /Identity-H\n/DescendantFonts [%d 0 R]\n/ToUnicode %d 0 R>>\n"+"endobj", fontName, f.n+1, f.n+2))
 
+				cidFontType, ordering := "CIDFontType2", "UCS"
+				if cff {
+					cidFontType, ordering = "CIDFontType0", "Identity"
+				}
 				f.newobj()
-				f.out("<</Type /Font\n/Subtype /CIDFontType2\n/BaseFont /" + fontName + "\n" +
+				f.out("<</Type /Font\n/Subtype /" + cidFontType + "\n/BaseFont /" + fontName + "\n" +
 					"/CIDSystemInfo " + strconv.Itoa(f.n+2) + " 0 R\n/FontDescriptor " + strconv.Itoa(f.n+3) + " 0 R")
 				if font.Desc.MissingWidth != 0 {
 					f.out("/DW " + strconv.Itoa(font.Desc.MissingWidth) + "")
 				}
-				f.generateCIDFontMap(&font, font.utf8File.LastRune)
-				f.out("/CIDToGIDMap " + strconv.Itoa(f.n+4) + " 0 R>>")
+				f.generateCIDFontMap(&font, font.cidWidths(), font.utf8File.LastRune)
+				if cff {
+					f.out(">>")
+				} else {
+					f.out("/CIDToGIDMap " + strconv.Itoa(f.n+4) + " 0 R>>")
+				}
 				f.out("endobj")
 
 				f.newobj()
-				f.out("<</Length " + strconv.Itoa(len(toUnicode)) + ">>")
-				f.putstream([]byte(toUnicode))
+				toUnicodeCMap := font.toUnicodeCMap()
+				f.out("<</Length " + strconv.Itoa(len(toUnicodeCMap)) + ">>")
+				f.putstream([]byte(toUnicodeCMap))
 				f.out("endobj")
 
 				// CIDInfo
 				f.newobj()
-				f.out("<</Registry (Adobe)\n/Ordering (UCS)\n/Supplement 0>>")
+				f.out("<</Registry (Adobe)\n/Ordering (" + ordering + ")\n/Supplement 0>>")
 				f.out("endobj")
 
 				// Font descriptor
@@ -4152,33 +5309,52 @@ func (f *Fpdf) putfonts() {
 				s.printf(" /ItalicAngle %d", font.Desc.ItalicAngle)
 				s.printf(" /StemV %d", font.Desc.StemV)
 				s.printf(" /MissingWidth %d", font.Desc.MissingWidth)
-				s.printf("/FontFile2 %d 0 R", f.n+2)
+				if cff {
+					s.printf("/FontFile3 %d 0 R", f.n+1)
+					s.printf(" /CIDSet %d 0 R", f.n+2)
+				} else {
+					s.printf("/FontFile2 %d 0 R", f.n+2)
+					s.printf(" /CIDSet %d 0 R", f.n+3)
+				}
 				s.printf(">>")
 				f.out(s.String())
 				f.out("endobj")
 
-				// Embed CIDToGIDMap
-				cidToGidMap := make([]byte, 256*256*2)
+				if !cff {
+					// Embed CIDToGIDMap
+					cidToGidMap := make([]byte, 256*256*2)
 
-				for cc, glyph := range CodeSignDictionary {
-					cidToGidMap[cc*2] = byte(glyph >> 8)
-					cidToGidMap[cc*2+1] = byte(glyph & 0xFF)
-				}
+					for cid, glyph := range cidToGlyph {
+						cidToGidMap[cid*2] = byte(glyph >> 8)
+						cidToGidMap[cid*2+1] = byte(glyph & 0xFF)
+					}
 
-				cidToGidMap = sliceCompress(cidToGidMap)
-				f.newobj()
-				f.out("<</Length " + strconv.Itoa(len(cidToGidMap)) + "/Filter /FlateDecode>>")
-				f.putstream(cidToGidMap)
-				f.out("endobj")
+					cidToGidMap = sliceCompress(cidToGidMap)
+					f.newobj()
+					f.out("<</Length " + strconv.Itoa(len(cidToGidMap)) + "/Filter /FlateDecode>>")
+					f.putstream(cidToGidMap)
+					f.out("endobj")
+				}
 
 				//Font file
 				f.newobj()
 				f.out("<</Length " + strconv.Itoa(len(compressedFontStream)))
 				f.out("/Filter /FlateDecode")
-				f.out("/Length1 " + strconv.Itoa(utf8FontSize))
+				if cff {
+					f.out("/Subtype /CIDFontType0C")
+				} else {
+					f.out("/Length1 " + strconv.Itoa(utf8FontSize))
+				}
 				f.out(">>")
 				f.putstream(compressedFontStream)
 				f.out("endobj")
+
+				// CIDSet, the CIDs present in the subset
+				cidSet := sliceCompress(cidSetBits(cidToGlyph))
+				f.newobj()
+				f.out("<</Length " + strconv.Itoa(len(cidSet)) + "/Filter /FlateDecode>>")
+				f.putstream(cidSet)
+				f.out("endobj")
 			default:
 				f.err = fmt.Errorf("unsupported font type: %s", tp)
 				return
@@ -4188,7 +5364,49 @@ func (f *Fpdf) putfonts() {
 	return
 }
 
-func (f *Fpdf) generateCIDFontMap(font *fontDefType, LastRune int) {
+// subsetTag returns the six uppercase letters that prefix the name of a font
+// subset. The tag is derived from the font and the runes of the subset so
+// that the same document yields the same output.
+func subsetTag(name string, runes map[int]int) string {
+	keys := make([]int, 0, len(runes))
+	for r := range runes {
+		keys = append(keys, r)
+	}
+	sort.Ints(keys)
+	h := sha1.New()
+	h.Write([]byte(name))
+	for _, r := range keys {
+		h.Write([]byte{byte(r >> 16), byte(r >> 8), byte(r)})
+	}
+	sum := h.Sum(nil)
+	tag := make([]byte, 6)
+	for j := range tag {
+		tag[j] = 'A' + sum[j]%26
+	}
+	return string(tag)
+}
+
+// cidSetBits returns the CIDSet stream of a subset with the specified
+// CID-to-glyph map. Each CID is represented by a bit, the high-order bit of
+// the first byte being CID 0, which is always present.
+func cidSetBits(cidToGlyph map[int]int) []byte {
+	last := 0
+	for cid := range cidToGlyph {
+		if cid > last {
+			last = cid
+		}
+	}
+	bits := make([]byte, last/8+1)
+	bits[0] = 0x80
+	for cid := range cidToGlyph {
+		if cid >= 0 {
+			bits[cid/8] |= 0x80 >> uint(cid%8)
+		}
+	}
+	return bits
+}
+
+func (f *Fpdf) generateCIDFontMap(font *fontDefType, cw []int, LastRune int) {
 	rangeID := 0
 	cidArray := make(map[int]*untypedKeyMap)
 	cidArrayKeys := make([]int, 0)
@@ -4197,17 +5415,23 @@ func (f *Fpdf) generateCIDFontMap(font *fontDefType, LastRune int) {
 	interval := false
 	startCid := 1
 	cwLen := LastRune + 1
+	if cwLen > len(cw) {
+		cwLen = len(cw)
+	}
 
 	// for each character
 	for cid := startCid; cid < cwLen; cid++ {
-		if font.Cw[cid] == 0x00 {
+		if cw[cid] == 0x00 {
 			continue
 		}
-		width := font.Cw[cid]
+		width := cw[cid]
 		if width == 65535 {
 			width = 0
 		}
-		if numb, OK := font.usedRunes[cid]; cid > 255 && (!OK || numb == 0) {
+		// The CIDs of characters outside the Basic Multilingual Plane are only
+		// assigned when they are used
+		astral := cid >= cidAstralFirst && cid <= cidAstralLast
+		if numb, OK := font.usedRunes[cid]; cid > 255 && !astral && (!OK || numb == 0) {
 			continue
 		}
 
@@ -4357,39 +5581,81 @@ func (f *Fpdf) putimages() {
 	insertedImages := map[string]int{}
 
 	for _, key = range keyList {
-		image := f.images[key]
+		f.putimageOnce(f.images[key], insertedImages)
+		if f.err != nil {
+			return
+		}
+	}
+}
 
-		// Check if this image has already been inserted using it's SHA-1 hash.
-		insertedImageObjN, isFound := insertedImages[image.i]
+// putimageOnce inserts the specified image, preceded by any mask attached to
+// it, unless insertedImages shows that it has already been inserted.
+func (f *Fpdf) putimageOnce(image *ImageInfoType, insertedImages map[string]int) {
+	// Check if this image has already been inserted using it's SHA-1 hash.
+	insertedImageObjN, isFound := insertedImages[image.i]
 
-		// If found, skip inserting the image as a new object, and
-		// use the object ID from the insertedImages map.
-		// If not, insert the image into the PDF and store the object ID.
-		if isFound {
-			image.n = insertedImageObjN
-		} else {
-			f.putimage(image)
-			insertedImages[image.i] = image.n
+	// If found, skip inserting the image as a new object, and
+	// use the object ID from the insertedImages map.
+	// If not, insert the image into the PDF and store the object ID.
+	if isFound {
+		image.n = insertedImageObjN
+		return
+	}
+	if image.mask != nil {
+		f.putimageOnce(image.mask, insertedImages)
+	}
+	for _, alt := range image.alts {
+		f.putimageOnce(alt.img, insertedImages)
+	}
+	if image.open != nil {
+		// Deferred images are read now and released once written
+		f.loadImage(image)
+		if f.err != nil {
+			return
 		}
+		f.putimage(image)
+		insertedImages[image.i] = image.n
+		image.data, image.smask = nil, nil
+	} else {
+		f.putimage(image)
+		insertedImages[image.i] = image.n
 	}
 }
 
 func (f *Fpdf) putimage(info *ImageInfoType) {
+	// 	ICC profile
+	csStr := ""
+	if len(info.icc) > 0 {
+		f.puticcprofile(info)
+		csStr = sprintf("[/ICCBased %d 0 R]", f.n)
+	}
 	f.newobj()
 	info.n = f.n
 	f.out("<</Type /XObject")
 	f.out("/Subtype /Image")
 	f.outf("/Width %d", int(info.w))
 	f.outf("/Height %d", int(info.h))
-	if info.cs == "Indexed" {
-		f.outf("/ColorSpace [/Indexed /DeviceRGB %d %d 0 R]", len(info.pal)/3-1, f.n+1)
+	if info.stencil {
+		f.out("/ImageMask true")
+	} else if info.cs == "Indexed" {
+		if csStr == "" {
+			csStr = "/DeviceRGB"
+		}
+		f.outf("/ColorSpace [/Indexed %s %d %d 0 R]", csStr, len(info.pal)/3-1, f.n+1)
 	} else {
-		f.outf("/ColorSpace /%s", info.cs)
-		if info.cs == "DeviceCMYK" {
-			f.out("/Decode [1 0 1 0 1 0 1 0]")
+		if csStr == "" && info.cs != "" {
+			csStr = "/" + info.cs
+		}
+		if csStr != "" {
+			f.outf("/ColorSpace %s", csStr)
 		}
+		if len(info.dec) > 0 {
+			f.outf("/Decode [%s]", info.dec)
+		}
+	}
+	if info.bpc > 0 {
+		f.outf("/BitsPerComponent %d", info.bpc)
 	}
-	f.outf("/BitsPerComponent %d", info.bpc)
 	if len(info.f) > 0 {
 		f.outf("/Filter /%s", info.f)
 	}
@@ -4405,9 +5671,31 @@ func (f *Fpdf) putimage(info *ImageInfoType) {
 	}
 	if info.smask != nil {
 		f.outf("/SMask %d 0 R", f.n+1)
+	} else if info.mask != nil {
+		if info.mask.stencil {
+			f.outf("/Mask %d 0 R", info.mask.n)
+		} else {
+			f.outf("/SMask %d 0 R", info.mask.n)
+		}
+	}
+	if len(info.alts) > 0 {
+		var alts fmtBuffer
+		for _, alt := range info.alts {
+			alts.printf("<</Image %d 0 R", alt.img.n)
+			if alt.print {
+				alts.printf(" /DefaultForPrinting true")
+			}
+			alts.printf(">> ")
+		}
+		f.outf("/Alternates [%s]", alts.String())
+	}
+	if info.src != nil {
+		f.outf("/Length %d>>", info.srcLen)
+		f.putsourcestream(info)
+	} else {
+		f.outf("/Length %d>>", len(info.data))
+		f.putstream(info.data)
 	}
-	f.outf("/Length %d>>", len(info.data))
-	f.putstream(info.data)
 	f.out("endobj")
 	// 	Soft mask
 	if len(info.smask) > 0 {
@@ -4438,6 +5726,31 @@ func (f *Fpdf) putimage(info *ImageInfoType) {
 	}
 }
 
+// puticcprofile writes the ICC color profile of the specified image as an
+// ICCBased color space stream
+func (f *Fpdf) puticcprofile(info *ImageInfoType) {
+	var n int
+	var alt string
+	switch info.cs {
+	case "DeviceGray":
+		n, alt = 1, "DeviceGray"
+	case "DeviceCMYK":
+		n, alt = 4, "DeviceCMYK"
+	default:
+		n, alt = 3, "DeviceRGB"
+	}
+	f.newobj()
+	if f.compress {
+		icc := sliceCompress(info.icc)
+		f.outf("<</N %d /Alternate /%s /Filter /FlateDecode /Length %d>>", n, alt, len(icc))
+		f.putstream(icc)
+	} else {
+		f.outf("<</N %d /Alternate /%s /Length %d>>", n, alt, len(info.icc))
+		f.putstream(info.icc)
+	}
+	f.out("endobj")
+}
+
 func (f *Fpdf) putxobjectdict() {
 	{
 		var image *ImageInfoType
@@ -4449,9 +5762,13 @@ func (f *Fpdf) putxobjectdict() {
 		if f.catalogSort {
 			sort.SliceStable(keyList, func(i, j int) bool { return f.images[keyList[i]].i < f.images[keyList[j]].i })
 		}
+		done := make(map[string]bool)
 		for _, key = range keyList {
 			image = f.images[key]
-			f.outf("/I%s %d 0 R", image.i, image.n)
+			if !done[image.i] {
+				f.outf("/I%s %d 0 R", image.i, image.n)
+				done[image.i] = true
+			}
 		}
 	}
 	{
@@ -4474,6 +5791,8 @@ func (f *Fpdf) putxobjectdict() {
 			f.outf("%s %d 0 R", tplName, f.importedTplIDs[objID])
 		}
 	}
+	f.transparencyGroupPutXObjectDict()
+	f.symbolPutXObjectDict()
 }
 
 func (f *Fpdf) putresourcedict() {
@@ -4499,11 +5818,12 @@ func (f *Fpdf) putresourcedict() {
 	f.putxobjectdict()
 	f.out(">>")
 	count := len(f.blendList)
-	if count > 1 {
+	if count > 1 || len(f.softMaskList) > 1 {
 		f.out("/ExtGState <<")
 		for j := 1; j < count; j++ {
 			f.outf("/GS%d %d 0 R", j, f.blendList[j].objNum)
 		}
+		f.softMaskPutExtGStateDict()
 		f.out(">>")
 	}
 	count = len(f.gradientList)
@@ -4517,6 +5837,7 @@ func (f *Fpdf) putresourcedict() {
 	// Layers
 	f.layerPutResourceDict()
 	f.spotColorPutResourceDict()
+	f.patternPutResourceDict()
 }
 
 func (f *Fpdf) putBlendModes() {
@@ -4536,20 +5857,26 @@ func (f *Fpdf) putGradients() {
 	for j := 1; j < count; j++ {
 		var f1 int
 		gr := f.gradientList[j]
+		if gr.tp >= 4 {
+			f.putMesh(gr)
+			f.gradientList[j].objNum = f.n
+			continue
+		}
 		if gr.tp == 2 || gr.tp == 3 {
 			f.newobj()
-			f.outf("<</FunctionType 2 /Domain [0.0 1.0] /C0 [%s] /C1 [%s] /N 1>>", gr.clr1Str, gr.clr2Str)
+			f.out(gradientFunction(gr.clrStrs, gr.posList))
 			f.out("endobj")
 			f1 = f.n
 		}
+		extendStr := strIf(gr.extend, "true", "false")
 		f.newobj()
 		f.outf("<</ShadingType %d /ColorSpace /DeviceRGB", gr.tp)
 		if gr.tp == 2 {
-			f.outf("/Coords [%.5f %.5f %.5f %.5f] /Function %d 0 R /Extend [true true]>>",
-				gr.x1, gr.y1, gr.x2, gr.y2, f1)
+			f.outf("/Coords [%.5f %.5f %.5f %.5f] /Function %d 0 R /Extend [%s %s]>>",
+				gr.x1, gr.y1, gr.x2, gr.y2, f1, extendStr, extendStr)
 		} else if gr.tp == 3 {
-			f.outf("/Coords [%.5f %.5f 0 %.5f %.5f %.5f] /Function %d 0 R /Extend [true true]>>",
-				gr.x1, gr.y1, gr.x2, gr.y2, gr.r, f1)
+			f.outf("/Coords [%.5f %.5f 0 %.5f %.5f %.5f] /Function %d 0 R /Extend [%s %s]>>",
+				gr.x1, gr.y1, gr.x2, gr.y2, gr.r, f1, extendStr, extendStr)
 		}
 		f.out("endobj")
 		f.gradientList[j].objNum = f.n
@@ -4588,7 +5915,11 @@ func (f *Fpdf) putresources() {
 		return
 	}
 	f.putimages()
+	f.putPatterns()
 	f.putTemplates()
+	f.putTransparencyGroups()
+	f.putSymbols()
+	f.putSoftMasks()
 	f.putImportedTemplates() // gofpdi
 	// 	Resource dictionary
 	f.offsets[2] = f.buffer.Len()
@@ -4696,7 +6027,7 @@ func (f *Fpdf) putcatalog() {
 }
 
 func (f *Fpdf) putheader() {
-	if len(f.blendMap) > 0 && f.pdfVersion < "1.4" {
+	if (len(f.blendMap) > 0 || len(f.groupList) > 1) && f.pdfVersion < "1.4" {
 		f.pdfVersion = "1.4"
 	}
 	f.outf("%%PDF-%s", f.pdfVersion)
@@ -19,14 +19,20 @@ package gofpdf_test
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/binary"
 	"fmt"
+	"image"
+	"image/color"
 	"io"
 	"io/ioutil"
 	"math"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -144,6 +150,37 @@ func TestIssue0193(t *testing.T) {
 
 }
 
+// TestImageDeduplication ensures that identical images registered under
+// different names are embedded only once
+func TestImageDeduplication(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	buf, err := ioutil.ReadFile(example.ImageFile("logo.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; j < 3; j++ {
+		name := fmt.Sprintf("logo%d", j)
+		pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "png"}, bytes.NewReader(buf))
+		pdf.Image(name, 10, 10+float64(j)*30, 30, 0, false, "", 0, "")
+	}
+	// Each name has a descriptor of its own
+	pdf.GetImageInfo("logo0").SetDpi(300)
+	if pdf.GetImageInfo("logo1").Width() == pdf.GetImageInfo("logo0").Width() {
+		t.Fatalf("resolution of one name applied to another")
+	}
+	var out bytes.Buffer
+	err = pdf.Output(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := strings.Count(out.String(), "/Subtype /Image")
+	if count != 1 {
+		t.Fatalf("expecting 1 image object, got %d", count)
+	}
+}
+
 // TestIssue0209SplitLinesEqualMultiCell addresses issue 209
 // make SplitLines and MultiCell split at the same place
 func TestIssue0209SplitLinesEqualMultiCell(t *testing.T) {
@@ -807,7 +844,10 @@ func ExampleFpdf_WriteAligned() {
 	leftMargin, _, rightMargin, _ := pdf.GetMargins()
 	pageWidth, _ := pdf.GetPageSize()
 	pageWidth -= leftMargin + rightMargin
-	pdf.SetWordSpacing((pageWidth - pdf.GetStringWidth(line)) / float64(strings.Count(line, " ")))
+	// The word spacing fills the line within the cell margins, rounded down so
+	// that the line is not broken by rounding errors
+	ws := (pageWidth - 2*pdf.GetCellMargin() - pdf.GetStringWidth(line)) / float64(strings.Count(line, " "))
+	pdf.SetWordSpacing(math.Floor(ws*100) / 100)
 	pdf.WriteAligned(pageWidth, 35, line, "L")
 	fileStr := example.Filename("Fpdf_WriteAligned")
 	err := pdf.OutputFileAndClose(fileStr)
@@ -1534,6 +1574,40 @@ func ExampleFpdf_SVGBasicWrite() {
 	// Successfully generated pdf/Fpdf_SVGBasicWrite.pdf
 }
 
+// ExampleFpdf_SVGWrite demonstrates how to render SVG images, such as logos and
+// charts, as vector content with their fills, strokes, transforms, arcs,
+// opacity and text.
+func ExampleFpdf_SVGWrite() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.Write(6, "A chart with arcs, groups, transforms, opacity and text:")
+	chart, err := gofpdf.SVGFileParse(example.ImageFile("chart.svg"))
+	if err == nil {
+		pdf.SVGWrite(&chart, 10, 20, 190, 0)
+	} else {
+		pdf.SetError(err)
+	}
+	pdf.SetXY(10, 140)
+	pdf.Write(6, "Badges at their natural size and enlarged:")
+	y := 150.0
+	for _, name := range []string{"mit.svg", "doc.svg"} {
+		badge, err := gofpdf.SVGFileParse(example.ImageFile(name))
+		if err != nil {
+			pdf.SetError(err)
+			break
+		}
+		pdf.SVGWrite(&badge, 10, y, 0, 0)
+		pdf.SVGWrite(&badge, 50, y, 100, 0)
+		y += 30
+	}
+	fileStr := example.Filename("Fpdf_SVGWrite")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SVGWrite.pdf
+}
+
 // ExampleFpdf_CellFormat_align demonstrates Stefan Schroeder's code to control vertical
 // alignment.
 func ExampleFpdf_CellFormat_align() {
@@ -2638,6 +2712,140 @@ func ExampleFpdf_AddUTF8Font() {
 	// Successfully generated pdf/Fpdf_AddUTF8Font.pdf
 }
 
+// ExampleFpdf_AddUTF8Font_openType demonstrates the use of an OpenType font
+// with PostScript outlines. Only the glyphs used in the document are embedded.
+func ExampleFpdf_AddUTF8Font_openType() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("go", "", example.FontFile("Go-Regular.otf"))
+	pdf.AddPage()
+	pdf.SetFont("go", "", 24)
+	pdf.Cell(0, 12, "OpenType with CFF outlines")
+	pdf.Ln(14)
+	pdf.SetFont("go", "", 14)
+	pdf.MultiCell(0, 7, "Zwölf Boxkämpfer jagen Viktor quer über den großen Sylter Deich. "+
+		"Pchnąć w tę łódź jeża lub ośm skrzyń fig. “Quoted” text — and a price of €42.", "", "L", false)
+	fileStr := example.Filename("Fpdf_AddUTF8Font_openType")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddUTF8Font_openType.pdf
+}
+
+// ExampleFpdf_AddUTF8FontFromReader demonstrates loading a UTF-8 font from a
+// reader rather than a file name.
+func ExampleFpdf_AddUTF8FontFromReader() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	fl, err := os.Open(example.FontFile("DejaVuSansCondensed.ttf"))
+	if err == nil {
+		pdf.AddUTF8FontFromReader("dejavu", "", fl)
+		fl.Close()
+		pdf.AddPage()
+		pdf.SetFont("dejavu", "", 16)
+		pdf.Cell(0, 10, "Font read from an io.Reader: Здравствуйте, Γειά σας")
+	} else {
+		pdf.SetError(err)
+	}
+	fileStr := example.Filename("Fpdf_AddUTF8FontFromReader")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddUTF8FontFromReader.pdf
+}
+
+// ExampleFpdf_AddUTF8FontFromReaderAt demonstrates adding a UTF-8 font that is
+// read on demand rather than held in memory. The file remains open until the
+// document has been written.
+func ExampleFpdf_AddUTF8FontFromReaderAt() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	fl, err := os.Open(example.FontFile("DejaVuSansCondensed.ttf"))
+	if err == nil {
+		defer fl.Close()
+		var st os.FileInfo
+		if st, err = fl.Stat(); err == nil {
+			pdf.AddUTF8FontFromReaderAt("dejavu", "", fl, st.Size())
+		}
+	}
+	if err != nil {
+		pdf.SetError(err)
+	}
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 16)
+	pdf.Cell(0, 10, "Font read on demand: Здравствуйте, Γειά σας")
+	fileStr := example.Filename("Fpdf_AddUTF8FontFromReaderAt")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddUTF8FontFromReaderAt.pdf
+}
+
+// ExampleFpdf_SetFontFallbacks demonstrates the use of a fallback font for
+// characters that the current font lacks. The Calligrapher font has no Greek
+// or Cyrillic letters, so these are printed with DejaVu.
+func ExampleFpdf_SetFontFallbacks() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("calligra", "", example.FontFile("calligra.ttf"))
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFontFallbacks("dejavu")
+	pdf.AddPage()
+	pdf.SetFont("calligra", "", 20)
+	pdf.CellFormat(0, 12, "Greetings: Γειά σας and Здравствуйте", "1", 1, "C", false, 0, "")
+	pdf.Ln(4)
+	pdf.SetFont("calligra", "", 16)
+	pdf.MultiCell(0, 8, "The word for peace is мир in Russian and ειρήνη in Greek. "+
+		"Text that mixes scripts is wrapped and justified using the widths of the "+
+		"glyphs in the fonts that actually print them.", "", "J", false)
+	pdf.Ln(4)
+	pdf.Write(8, "Flowing text with Write(): Ελληνικά, Русский, English.")
+	fileStr := example.Filename("Fpdf_SetFontFallbacks")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFontFallbacks.pdf
+}
+
+// ExampleFpdf_SetTextShaping demonstrates the printing of Arabic text, whose
+// letters take the forms that join them to their neighbors.
+func ExampleFpdf_SetTextShaping() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 20)
+	pdf.SetTextShaping(true)
+	pdf.RTL()
+	pdf.CellFormat(0, 12, "السلام عليكم", "", 1, "R", false, 0, "")
+	pdf.CellFormat(0, 12, "مرحبا بالعالم", "", 1, "R", false, 0, "")
+	pdf.SetTextShaping(false)
+	pdf.CellFormat(0, 12, "مرحبا بالعالم", "", 1, "R", false, 0, "")
+	pdf.LTR()
+	fileStr := example.Filename("Fpdf_SetTextShaping")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetTextShaping.pdf
+}
+
+// ExampleFpdf_SetBaseDirection demonstrates the ordering of right-to-left text
+// that contains left-to-right words and numbers.
+func ExampleFpdf_SetBaseDirection() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 16)
+	pdf.SetTextShaping(true)
+	pdf.SetBaseDirection("R")
+	pdf.CellFormat(0, 10, "הספר \"Go Programming\" עולה 45 שקלים.", "", 1, "R", false, 0, "")
+	pdf.CellFormat(0, 10, "اشترى 3 كتب (PDF) في 2019.", "", 1, "R", false, 0, "")
+	pdf.SetBaseDirection("A")
+	pdf.MultiCell(0, 8, "The word שלום (shalom) and the word سلام (salaam) "+
+		"both mean peace.", "", "L", false)
+	pdf.MultiCell(0, 8, "המילה peace (שלום) נכתבת כך באנגלית.", "", "R", false)
+	fileStr := example.Filename("Fpdf_SetBaseDirection")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetBaseDirection.pdf
+}
+
 // ExampleUTF8CutFont demonstrates how generate a TrueType font subset.
 func ExampleUTF8CutFont() {
 	var pdfFileStr, fullFontFileStr, subFontFileStr string
@@ -2834,6 +3042,242 @@ func TestIssue0316(t *testing.T) {
 	}
 }
 
+// TestUTF8FontSubset verifies that a UTF-8 font is embedded as a tagged subset
+// with a CIDSet.
+func TestUTF8FontSubset(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFont("dejavu", "", 16)
+	pdf.AddPage()
+	pdf.Cell(40, 10, "Hello, Здравствуйте")
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(example.FontFile("DejaVuSansCondensed.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() > int(info.Size())/10 {
+		t.Fatalf("document of %d bytes does not embed a subset of the font", buf.Len())
+	}
+	out := buf.String()
+	if !regexp.MustCompile(`/BaseFont /[A-Z]{6}\+utf8dejavu`).MatchString(out) {
+		t.Fatal("font name lacks a subset tag")
+	}
+	if !strings.Contains(out, "/CIDSet ") {
+		t.Fatal("font descriptor lacks a CIDSet")
+	}
+}
+
+// TestOpenTypeCFFFont verifies that an OpenType font with PostScript outlines
+// is embedded as a CFF font program.
+func TestOpenTypeCFFFont(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("go", "", example.FontFile("Go-Regular.otf"))
+	pdf.SetFont("go", "", 16)
+	pdf.AddPage()
+	pdf.Cell(40, 10, "Łódź €5")
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, str := range []string{"/Subtype /CIDFontType0\n", "/FontFile3 ", "/Subtype /CIDFontType0C"} {
+		if !strings.Contains(out, str) {
+			t.Fatalf("expecting %q in output", str)
+		}
+	}
+	if strings.Contains(out, "/CIDToGIDMap") {
+		t.Fatal("unexpected CIDToGIDMap for CFF font")
+	}
+}
+
+// TestWebFont verifies that a font packaged as a WOFF file is embedded just
+// like the TrueType font it contains, and that a WOFF2 font is rejected while
+// no Brotli decoder is registered.
+func TestWebFont(t *testing.T) {
+	generate := func(fontFileStr string) ([]byte, error) {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCreationDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		pdf.AddUTF8Font("calligra", "", example.FontFile(fontFileStr))
+		pdf.SetFont("calligra", "", 16)
+		pdf.AddPage()
+		pdf.Cell(40, 10, "Hello, World")
+		var buf bytes.Buffer
+		err := pdf.Output(&buf)
+		return buf.Bytes(), err
+	}
+	ttf, err := generate("calligra.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	woff, err := generate("calligra.woff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ttf, woff) {
+		t.Fatal("WOFF font is not embedded like its TrueType font")
+	}
+	woff2, err := ioutil.ReadFile(example.FontFile("calligra.woff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(woff2, "wOF2")
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("calligra", "", woff2)
+	if err = pdf.Error(); err == nil || !strings.Contains(err.Error(), "Brotli") {
+		t.Fatalf("unexpected error for WOFF2 font without decoder: %v", err)
+	}
+}
+
+// TestFontFallbacks verifies that characters missing from the current font
+// are measured and shown with a fallback font.
+func TestFontFallbacks(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("calligra", "", example.FontFile("calligra.ttf"))
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 12)
+	wd := pdf.GetStringWidth("Ж")
+	pdf.SetFont("calligra", "", 12)
+	if pdf.GetStringWidth("aЖ") == pdf.GetStringWidth("a")+wd {
+		t.Fatal("unexpected width of missing character without fallback")
+	}
+	pdf.SetFontFallbacks("dejavu")
+	if diff := pdf.GetStringWidth("aЖ") - pdf.GetStringWidth("a") - wd; math.Abs(diff) > 1e-9 {
+		t.Fatalf("width of fallback character is off by %f", diff)
+	}
+	pdf.Cell(0, 10, "aЖb")
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`Td \(.+\)Tj /F\w+ 12.00 Tf \(.+\)Tj /F\w+ 12.00 Tf \(.+\)Tj ET`).Match(buf.Bytes()) {
+		t.Fatal("fallback font is not selected for missing character")
+	}
+}
+
+// TestTextShaping verifies that Arabic letters are replaced by their joining
+// forms and that lam and alef are replaced by their ligature.
+func TestTextShaping(t *testing.T) {
+	generate := func(shaping bool) []byte {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(false)
+		pdf.SetTextShaping(shaping)
+		pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+		pdf.SetFont("dejavu", "", 12)
+		pdf.AddPage()
+		// beh, beh with kasra, lam, alef
+		pdf.Cell(0, 10, "\u0628\u0628\u0650\u0644\u0627")
+		var buf bytes.Buffer
+		err := pdf.Output(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	// Initial beh, medial beh with kasra and final lam-alef ligature
+	shaped := []byte{0xfe, 0x91, 0xfe, 0x92, 0x06, 0x50, 0xfe, 0xfc}
+	if !bytes.Contains(generate(true), shaped) {
+		t.Fatal("text is not shaped")
+	}
+	if bytes.Contains(generate(false), shaped) {
+		t.Fatal("text is shaped although shaping is disabled")
+	}
+}
+
+func TestBaseDirection(t *testing.T) {
+	generate := func(dirStr, txtStr string) []byte {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(false)
+		pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+		pdf.SetFont("dejavu", "", 12)
+		pdf.SetBaseDirection(dirStr)
+		pdf.AddPage()
+		pdf.Cell(0, 10, txtStr)
+		var buf bytes.Buffer
+		err := pdf.Output(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	// utf16 returns the string as it appears in a content stream
+	utf16 := func(txtStr string) []byte {
+		var b []byte
+		for _, r := range txtStr {
+			b = append(b, byte(r>>8))
+			if r == '(' || r == ')' || r == '\\' {
+				b = append(b, '\\')
+			}
+			b = append(b, byte(r))
+		}
+		return b
+	}
+	logical := "\u05e9\u05dc\u05d5\u05dd (abc) 123"
+	// The Hebrew word is reversed and the parentheses are mirrored
+	visual := "123 (abc) \u05dd\u05d5\u05dc\u05e9"
+	for _, dirStr := range []string{"R", "A"} {
+		if !bytes.Contains(generate(dirStr, logical), utf16(visual)) {
+			t.Fatalf("text is not in visual order in direction %s", dirStr)
+		}
+	}
+	if !bytes.Contains(generate("", logical), utf16(logical)) {
+		t.Fatal("text is reordered although the algorithm is disabled")
+	}
+	// A left-to-right paragraph keeps the Latin words in place
+	logical = "abc \u05d0\u05d1 1 \u05d2 def"
+	visual = "abc \u05d2 1 \u05d1\u05d0 def"
+	if !bytes.Contains(generate("L", logical), utf16(visual)) {
+		t.Fatal("text is not in visual order in direction L")
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetBaseDirection("X")
+	if pdf.Err() == false {
+		t.Fatal("expecting error for incorrect base direction")
+	}
+}
+
+// TestUTF8Astral verifies that characters outside the Basic Multilingual Plane
+// are encoded with CIDs of their own that map to their glyphs and back to the
+// characters.
+func TestUTF8Astral(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFont("dejavu", "", 12)
+	pdf.AddPage()
+	// Double-struck capital A and B
+	txtStr := "\U0001D538\U0001D539 \U0001D538"
+	if pdf.GetStringWidth(txtStr) <= pdf.GetStringWidth("AB A") {
+		t.Fatal("characters outside the Basic Multilingual Plane have no width")
+	}
+	pdf.Cell(0, 10, txtStr)
+	pdf.MultiCell(0, 10, txtStr, "", "J", false)
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The characters are encoded as CIDs instead of surrogate pairs
+	if !bytes.Contains(buf.Bytes(), []byte{0xd8, 0x00, 0xd8, 0x01, 0x00, ' ', 0xd8, 0x00}) {
+		t.Fatal("characters are not encoded with their CIDs")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<D800> <D835DD38>\n<D801> <D835DD39>")) {
+		t.Fatal("ToUnicode CMap does not map the CIDs to the characters")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(" 55296 [ 667 658 ]")) {
+		t.Fatal("widths of the CIDs are missing")
+	}
+}
+
 func TestMultiCellUnsupportedChar(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
@@ -2922,3 +3366,5626 @@ func ExampleFpdf_SetModificationDate() {
 	// Output:
 	// Successfully generated pdf/Fpdf_SetModificationDate.pdf
 }
+
+// ExampleFpdf_Image_interlaced demonstrates the placement of an
+// Adam7-interlaced PNG image and a PNG image with 16 bit samples next to
+// their 8 bit, non-interlaced counterpart.
+func ExampleFpdf_Image_interlaced() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.Image(example.ImageFile("logo.png"), 10, 10, 30, 0, false, "", 0, "")
+	pdf.Image(example.ImageFile("logo-interlaced.png"), 50, 10, 30, 0, false, "", 0, "")
+	pdf.Image(example.ImageFile("logo-16bit.png"), 90, 10, 30, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_Image_interlaced")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Image_interlaced.pdf
+}
+
+// TestPngFrame verifies that the default image of an animated PNG is its
+// first frame when it is not part of the animation, and that the frames of
+// the animation follow it.
+func TestPngFrame(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	for frame, size := range [][2]float64{{2, 1}, {1, 3}, {}} {
+		fl, err := os.Open(example.ImageFile("apng-hidden-default.png"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		opt := gofpdf.ImageOptions{ImageType: "png", Frame: frame}
+		info := pdf.RegisterImageOptionsReader(fmt.Sprintf("frame%d", frame), opt, fl)
+		fl.Close()
+		if size == [2]float64{} {
+			if pdf.Error() == nil {
+				t.Errorf("frame %d of %d accepted", frame, frame)
+			}
+			pdf.ClearError()
+			continue
+		}
+		if info == nil {
+			t.Fatalf("frame %d: %s", frame, pdf.Error())
+		}
+		if w, h := info.Width(), info.Height(); w != size[0] || h != size[1] {
+			t.Errorf("frame %d is %.0f by %.0f, expected %.0f by %.0f", frame, w, h, size[0], size[1])
+		}
+	}
+}
+
+// ExampleFpdf_RegisterImageOptionsReader_animated demonstrates the selection
+// of individual frames of an animated PNG image.
+func ExampleFpdf_RegisterImageOptionsReader_animated() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	for frame, name := range []string{"first", "second"} {
+		fl, err := os.Open(example.ImageFile("logo-animated.png"))
+		if err == nil {
+			opt := gofpdf.ImageOptions{ImageType: "png", Frame: frame}
+			pdf.RegisterImageOptionsReader(name, opt, fl)
+			fl.Close()
+		} else {
+			pdf.SetError(err)
+		}
+	}
+	pdf.Image("first", 10, 10, 30, 0, false, "", 0, "")
+	pdf.Image("second", 50, 10, 30, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_RegisterImageOptionsReader_animated")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RegisterImageOptionsReader_animated.pdf
+}
+
+// ExampleFpdf_Image_iccProfile demonstrates the placement of a PNG image with
+// an embedded ICC color profile. The profile is attached to the image as an
+// ICCBased color space.
+func ExampleFpdf_Image_iccProfile() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.Image(example.ImageFile("logo-icc.png"), 10, 10, 30, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_Image_iccProfile")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Image_iccProfile.pdf
+}
+
+// ExampleFpdf_Image_cmyk demonstrates the placement of a CMYK JPEG image
+// written by an Adobe application. The inverted samples of such images are
+// restored with a Decode array.
+func ExampleFpdf_Image_cmyk() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.Image(example.ImageFile("video-cmyk.jpg"), 10, 10, 60, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_Image_cmyk")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Image_cmyk.pdf
+}
+
+// ExampleFpdf_ImageOptions_orientation demonstrates the placement of a JPEG
+// image that is stored sideways and carries an EXIF orientation tag. By
+// default the image is rotated upright; the IgnoreOrientation option places
+// it as stored.
+func ExampleFpdf_ImageOptions_orientation() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	fileStr := example.ImageFile("logo-exif-rotated.jpg")
+	pdf.ImageOptions(fileStr, 10, 10, 30, 0, false, gofpdf.ImageOptions{}, 0, "")
+	fl, err := os.Open(fileStr)
+	if err == nil {
+		opt := gofpdf.ImageOptions{ImageType: "jpg", IgnoreOrientation: true}
+		pdf.RegisterImageOptionsReader("stored", opt, fl)
+		fl.Close()
+		pdf.Image("stored", 50, 10, 0, 30, false, "", 0, "")
+	} else {
+		pdf.SetError(err)
+	}
+	fileStr = example.Filename("Fpdf_ImageOptions_orientation")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ImageOptions_orientation.pdf
+}
+
+// ExampleFpdf_ImageOptions_maxDPI demonstrates the resampling of an image
+// whose resolution, at the size it is placed, exceeds a given limit.
+func ExampleFpdf_ImageOptions_maxDPI() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	opt := gofpdf.ImageOptions{MaxDPI: 72}
+	fileStr := example.ImageFile("golang-gopher.png")
+	pdf.ImageOptions(fileStr, 10, 10, 20, 0, false, opt, 0, "")
+	// The resampled image keeps the natural size of the file, with fewer
+	// pixels per inch
+	wd, ht := pdf.GetImageInfo(fileStr).Extent()
+	fmt.Printf("Natural size %.1f x %.1f mm\n", wd, ht)
+	fileStr = example.Filename("Fpdf_ImageOptions_maxDPI")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Natural size 352.8 x 352.8 mm
+	// Successfully generated pdf/Fpdf_ImageOptions_maxDPI.pdf
+}
+
+// TestMaxDPI verifies that images resampled for MaxDPI keep their color
+// space, including the four channels of CMYK JPEG images.
+func TestMaxDPI(t *testing.T) {
+	for _, c := range []struct {
+		file, cs string
+	}{
+		{"video-cmyk.jpg", "/ColorSpace /DeviceCMYK"},
+		{"logo.gif", "/ColorSpace [/Indexed /DeviceRGB"},
+		{"logo-gray.png", "/ColorSpace /DeviceGray"},
+		{"logo.jpg", "/ColorSpace /DeviceRGB"},
+		{"golang-gopher.png", "/SMask"},
+	} {
+		pdf := gofpdf.New("P", "pt", "A4", "")
+		pdf.SetCompression(false)
+		pdf.AddPage()
+		fileStr := example.ImageFile(c.file)
+		pdf.ImageOptions(fileStr, 10, 10, 20, 0, false, gofpdf.ImageOptions{MaxDPI: 72}, 0, "")
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatalf("%s: %s", c.file, err)
+		}
+		str := buf.String()
+		for _, want := range []string{c.cs, "/Width 20\n"} {
+			if !strings.Contains(str, want) {
+				t.Errorf("%s: %q missing", c.file, want)
+			}
+		}
+	}
+}
+
+// TestMaxDPISize verifies that an image resampled for MaxDPI keeps its natural
+// size, that images registered beforehand are resampled and that images
+// already placed are not.
+func TestMaxDPISize(t *testing.T) {
+	fileStr := example.ImageFile("golang-gopher.png")
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	fl, err := os.Open(fileStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fl.Close()
+	opt := gofpdf.ImageOptions{ImageType: "png", MaxDPI: 72}
+	for _, name := range []string{"file", "reader", "placed"} {
+		if _, err := fl.Seek(0, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "png"}, fl)
+	}
+	wd, ht := pdf.GetImageInfo("file").Extent()
+	pdf.ImageOptions("placed", 10, 10, 0, 0, false, gofpdf.ImageOptions{}, 0, "")
+	for _, name := range []string{"reader", "placed"} {
+		pdf.ImageOptions(name, 10, 10, 20, 0, false, opt, 0, "")
+	}
+	for _, name := range []string{"reader", "placed"} {
+		w, h := pdf.GetImageInfo(name).Extent()
+		if math.Abs(w-wd) > 0.1 || math.Abs(h-ht) > 0.1 {
+			t.Errorf("%s: natural size %.2f x %.2f mm, want %.2f x %.2f mm", name, w, h, wd, ht)
+		}
+	}
+	// A later placement at the natural size uses the size of the file
+	pdf.ImageOptions("reader", 10, 100, 0, 0, false, gofpdf.ImageOptions{}, 0, "")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	// The image registered beforehand is resampled to 57 pixels, 20 mm at 72
+	// dpi, while the image placed before keeps the pixels of the file
+	for _, want := range []string{"/Width 57\n", fmt.Sprintf("/Width %.0f\n", wd*72/25.4)} {
+		if !strings.Contains(str, want) {
+			t.Errorf("%q missing", want)
+		}
+	}
+	sizes := regexp.MustCompile(`q (\S+) 0 0 (\S+) `).FindAllString(str, -1)
+	if len(sizes) != 4 || sizes[3] != sizes[0] {
+		t.Errorf("resampled image placed as %v, want as the file", sizes)
+	}
+}
+
+// ExampleFpdf_ImageOptions_bilevel demonstrates the conversion of an image to
+// black and white with CCITT Group 4 compression.
+func ExampleFpdf_ImageOptions_bilevel() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	fileStr := example.ImageFile("doc.png")
+	pdf.ImageOptions(fileStr, 10, 10, 80, 0, false, gofpdf.ImageOptions{}, 0, "")
+	fl, err := os.Open(fileStr)
+	if err == nil {
+		opt := gofpdf.ImageOptions{ImageType: "png", Bilevel: true, Threshold: 160}
+		pdf.RegisterImageOptionsReader("bilevel", opt, fl)
+		fl.Close()
+		pdf.Image("bilevel", 110, 10, 80, 0, false, "", 0, "")
+	} else {
+		pdf.SetError(err)
+	}
+	fileStr = example.Filename("Fpdf_ImageOptions_bilevel")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ImageOptions_bilevel.pdf
+}
+
+// ExampleFpdf_RegisterImageOptionsReaderFunc demonstrates the registration of
+// images that are not read until the document is output. Since the images are
+// placed with an explicit width and height, none of them is opened before
+// then.
+func ExampleFpdf_RegisterImageOptionsReaderFunc() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	var opened int
+	names := []string{"logo.png", "logo.jpg", "logo.gif"}
+	for j, name := range names {
+		fileStr := example.ImageFile(name)
+		opt := gofpdf.ImageOptions{ImageType: filepath.Ext(name)[1:]}
+		pdf.RegisterImageOptionsReaderFunc(name, opt, func() (io.Reader, error) {
+			opened++
+			return os.Open(fileStr)
+		})
+		pdf.Image(name, 10+float64(j)*40, 10, 30, 30, false, "", 0, "")
+	}
+	fmt.Printf("Opened %d of %d images before output\n", opened, len(names))
+	fileStr := example.Filename("Fpdf_RegisterImageOptionsReaderFunc")
+	err := pdf.OutputFileAndClose(fileStr)
+	fmt.Printf("Opened %d of %d images after output\n", opened, len(names))
+	example.Summary(err, fileStr)
+	// Output:
+	// Opened 0 of 3 images before output
+	// Opened 3 of 3 images after output
+	// Successfully generated pdf/Fpdf_RegisterImageOptionsReaderFunc.pdf
+}
+
+// ExampleFpdf_RegisterGoImage demonstrates the registration of an image
+// generated by the application, in this case a translucent color gradient.
+func ExampleFpdf_RegisterGoImage() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	img := image.NewNRGBA(image.Rect(0, 0, 256, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 256; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(4 * y), 255 - uint8(x), 255 - uint8(2*y)})
+		}
+	}
+	pdf.SetFont("Helvetica", "B", 24)
+	pdf.Text(14, 26, "Generated image")
+	pdf.RegisterGoImage("gradient", img, gofpdf.ImageOptions{})
+	pdf.Image("gradient", 10, 10, 120, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_RegisterGoImage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RegisterGoImage.pdf
+}
+
+// ExampleFpdf_ImageOptions_recompress demonstrates the conversion of a PNG
+// image to JPEG when it is embedded, trading some fidelity for a smaller
+// document.
+func ExampleFpdf_ImageOptions_recompress() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	fileStr := example.ImageFile("golang-gopher.png")
+	pdf.ImageOptions(fileStr, 10, 10, 80, 0, false, gofpdf.ImageOptions{}, 0, "")
+	fl, err := os.Open(fileStr)
+	if err == nil {
+		opt := gofpdf.ImageOptions{
+			ImageType:  "png",
+			Recompress: gofpdf.ImageRecompressType{ImageType: "jpg", Quality: 60},
+		}
+		pdf.RegisterImageOptionsReader("recompressed", opt, fl)
+		fl.Close()
+		pdf.Image("recompressed", 110, 10, 80, 0, false, "", 0, "")
+	} else {
+		pdf.SetError(err)
+	}
+	fileStr = example.Filename("Fpdf_ImageOptions_recompress")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ImageOptions_recompress.pdf
+}
+
+// ExampleFpdf_SetImageCache demonstrates the sharing of parsed images among
+// several documents. The logo is read and parsed only for the first document;
+// the others take it from the cache.
+func ExampleFpdf_SetImageCache() {
+	cache := gofpdf.NewImageCache()
+	var err error
+	var fileStr string
+	for j := 1; j <= 3 && err == nil; j++ {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetImageCache(cache)
+		pdf.AddPage()
+		pdf.Image(example.ImageFile("logo.png"), 10, 10, 30, 0, false, "", 0, "")
+		pdf.SetFont("Arial", "", 14)
+		pdf.Text(50, 20, fmt.Sprintf("Report %d", j))
+		fileStr = example.Filename(fmt.Sprintf("Fpdf_SetImageCache_%d", j))
+		err = pdf.OutputFileAndClose(fileStr)
+	}
+	fmt.Printf("Images in cache: %d\n", cache.Len())
+	example.Summary(err, fileStr)
+	// Output:
+	// Images in cache: 1
+	// Successfully generated pdf/Fpdf_SetImageCache_3.pdf
+}
+
+// TestImageCache verifies that documents sharing an image cache receive
+// copies of the cached images that are scaled for their units and unaffected
+// by changes made by other documents.
+func TestImageCache(t *testing.T) {
+	cache := gofpdf.NewImageCache()
+	pdf1 := gofpdf.New("P", "mm", "A4", "")
+	pdf1.SetImageCache(cache)
+	info1 := pdf1.RegisterImageOptions(example.ImageFile("logo.png"), gofpdf.ImageOptions{})
+	info1.SetDpi(300)
+	pdf1.RegisterGoImage("mask", image.NewGray(image.Rect(0, 0, 104, 71)), gofpdf.ImageOptions{})
+	pdf1.SetImageMask(example.ImageFile("logo.png"), "mask")
+	pdf2 := gofpdf.New("P", "pt", "A4", "")
+	pdf2.SetImageCache(cache)
+	info2 := pdf2.RegisterImageOptions(example.ImageFile("logo.png"), gofpdf.ImageOptions{})
+	if pdf1.Err() || pdf2.Err() {
+		t.Fatal(pdf1.Error(), pdf2.Error())
+	}
+	// The logo is 104 by 71 pixels, 104 by 71 points at 72 dpi
+	if w, h := info2.Extent(); w != 104 || h != 71 {
+		t.Errorf("cached image of %.2f by %.2f points, expected 104 by 71", w, h)
+	}
+	var buf bytes.Buffer
+	if err := pdf2.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "/SMask") {
+		t.Error("mask attached by one document applied to another")
+	}
+	// Options that only govern placement or fetching share the cached image
+	n := cache.Len()
+	for _, opt := range []gofpdf.ImageOptions{{MaxSize: 1 << 20}, {MaxSize: 1 << 16}, {MaxDPI: 150}} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetImageCache(cache)
+		pdf.RegisterImageOptions(example.ImageFile("logo.png"), opt)
+		if pdf.Err() {
+			t.Fatal(pdf.Error())
+		}
+	}
+	if cache.Len() != n {
+		t.Errorf("%d cached images, expected %d", cache.Len(), n)
+	}
+}
+
+// ExampleFpdf_SetImageMask demonstrates stencil masks and the masking of one
+// image with another. The masks in this example are generated, but they may
+// equally be read from files.
+func ExampleFpdf_SetImageMask() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	// A black disc on a white background, used as a stencil mask
+	disc := image.NewGray(image.Rect(0, 0, 200, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			dx, dy := x-100, y-100
+			if dx*dx+dy*dy > 90*90 {
+				disc.SetGray(x, y, color.Gray{255})
+			}
+		}
+	}
+	pdf.RegisterGoImage("disc", disc, gofpdf.ImageOptions{ImageMask: true})
+	// A horizontal gradient, used as a soft mask
+	ramp := image.NewGray(image.Rect(0, 0, 256, 1))
+	for x := 0; x < 256; x++ {
+		ramp.SetGray(x, 0, color.Gray{uint8(x)})
+	}
+	pdf.RegisterGoImage("ramp", ramp, gofpdf.ImageOptions{})
+	// The stencil mask is painted with the fill color
+	pdf.SetFillColor(200, 60, 60)
+	pdf.Image("disc", 10, 10, 40, 40, false, "", 0, "")
+	pdf.SetFillColor(60, 60, 200)
+	pdf.Image("disc", 60, 10, 40, 40, false, "", 0, "")
+	// Images clipped by the disc and faded by the gradient
+	clipped := example.ImageFile("logo_gofpdf.jpg")
+	pdf.RegisterImage(clipped, "")
+	pdf.SetImageMask(clipped, "disc")
+	pdf.Image(clipped, 10, 60, 80, 80, false, "", 0, "")
+	faded := example.ImageFile("logo.jpg")
+	pdf.RegisterImage(faded, "")
+	pdf.SetImageMask(faded, "ramp")
+	pdf.Image(faded, 100, 60, 100, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_SetImageMask")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetImageMask.pdf
+}
+
+// ExampleFpdf_ImageOptions_sMaskImage demonstrates the transparency of an
+// image being supplied by a separate gray scale image, shown to the right.
+func ExampleFpdf_ImageOptions_sMaskImage() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFillColor(230, 200, 120)
+	pdf.Rect(10, 10, 190, 70, "F")
+	maskStr := example.ImageFile("logo-mask.png")
+	pdf.RegisterImage(maskStr, "")
+	opt := gofpdf.ImageOptions{SMaskImage: maskStr}
+	pdf.ImageOptions(example.ImageFile("logo.jpg"), 20, 15, 80, 0, false, opt, 0, "")
+	pdf.Image(maskStr, 110, 15, 80, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_ImageOptions_sMaskImage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ImageOptions_sMaskImage.pdf
+}
+
+// ExampleFpdf_AddImageAlternate demonstrates an image displayed on screen
+// with a high resolution alternate for printing. Only readers that honor
+// alternate images print the alternate.
+func ExampleFpdf_AddImageAlternate() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	screenStr := example.ImageFile("logo.gif")
+	printStr := example.ImageFile("logo-16bit.png")
+	pdf.RegisterImage(screenStr, "")
+	pdf.RegisterImage(printStr, "")
+	pdf.AddImageAlternate(screenStr, printStr, true)
+	pdf.Image(screenStr, 10, 10, 60, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_AddImageAlternate")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddImageAlternate.pdf
+}
+
+// ExampleFpdf_SetFillPatternImage demonstrates filling shapes with a tiled
+// image, scaled from its natural size.
+func ExampleFpdf_SetFillPatternImage() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFillPatternImage(example.ImageFile("logo.png"), 0.25, 0.25)
+	pdf.Rect(10, 10, 90, 60, "FD")
+	pdf.Polygon([]gofpdf.PointType{{X: 155, Y: 10}, {X: 200, Y: 70}, {X: 110, Y: 70}}, "FD")
+	pdf.SetFillPatternImage(example.ImageFile("logo.png"), 0.75, 0.5)
+	pdf.Circle(55, 120, 40, "FD")
+	pdf.SetFillColor(200, 220, 255)
+	pdf.Circle(155, 120, 40, "FD")
+	fileStr := example.Filename("Fpdf_SetFillPatternImage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFillPatternImage.pdf
+}
+
+// TestSetFillPatternImage verifies that image patterns are tiled at the
+// natural size of the image scaled by the given factors.
+func TestSetFillPatternImage(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	// The logo is 104 by 71 pixels, 104 by 71 points at 72 dpi
+	pdf.RegisterImageOptions(example.ImageFile("logo.png"), gofpdf.ImageOptions{}).SetDpi(72)
+	pdf.SetFillPatternImage(example.ImageFile("logo.png"), 0.5, 2)
+	pdf.Rect(0, 0, 100, 100, "F")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if str := "/Matrix [52.00000 0 0 142.00000 0 841.89000]"; !strings.Contains(buf.String(), str) {
+		t.Errorf("%q missing", str)
+	}
+	pdf = gofpdf.New("P", "pt", "A4", "")
+	pdf.AddPage()
+	pdf.SetFillPatternImage(example.ImageFile("logo.png"), 0, 1)
+	if pdf.Error() == nil {
+		t.Error("zero scale accepted")
+	}
+}
+
+// ExampleFpdf_Image_jpeg2000 demonstrates the placement of JPEG 2000 images,
+// which are embedded without being decoded. The first image is a JP2 file and
+// the second a raw codestream; both are plain gray squares.
+func ExampleFpdf_Image_jpeg2000() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.Image(example.ImageFile("gray.jp2"), 10, 10, 40, 0, false, "", 0, "")
+	pdf.Image(example.ImageFile("gray.j2k"), 60, 10, 40, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_Image_jpeg2000")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Image_jpeg2000.pdf
+}
+
+// ExampleFpdf_ImageOptions_fit demonstrates the placement of an image within
+// a box according to the various fit modes and alignments.
+func ExampleFpdf_ImageOptions_fit() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	fileStr := example.ImageFile("logo_gofpdf.jpg")
+	fits := []string{gofpdf.ImageFitContain, gofpdf.ImageFitCover,
+		gofpdf.ImageFitStretch, gofpdf.ImageFitNone}
+	for row, alignStr := range []string{"LT", "", "RB"} {
+		for col, fit := range fits {
+			x := 10 + float64(col)*48
+			y := 20 + float64(row)*60
+			pdf.Text(x, y-2, fmt.Sprintf("%s %s", fit, alignStr))
+			opt := gofpdf.ImageOptions{Fit: fit, AlignStr: alignStr}
+			pdf.ImageOptions(fileStr, x, y, 40, 40, false, opt, 0, "")
+			pdf.Rect(x, y, 40, 40, "D")
+		}
+	}
+	fileStr = example.Filename("Fpdf_ImageOptions_fit")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ImageOptions_fit.pdf
+}
+
+// ExampleFpdf_ImageOptions_rotate demonstrates the rotation and mirroring of
+// images about their centers.
+func ExampleFpdf_ImageOptions_rotate() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	fileStr := example.ImageFile("logo.png")
+	for j, opt := range []gofpdf.ImageOptions{
+		{},
+		{Rotate: 30},
+		{Rotate: 90},
+		{FlipH: true},
+		{FlipV: true},
+		{FlipH: true, Rotate: 45},
+	} {
+		x := 15 + float64(j%3)*65
+		y := 20 + float64(j/3)*65
+		pdf.Text(x, y-5, fmt.Sprintf("Rotate %.0f, FlipH %v, FlipV %v", opt.Rotate, opt.FlipH, opt.FlipV))
+		pdf.ImageOptions(fileStr, x, y, 40, 0, false, opt, 0, "")
+	}
+	fileStr = example.Filename("Fpdf_ImageOptions_rotate")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ImageOptions_rotate.pdf
+}
+
+// ExampleFpdf_AddUTF8FontInstance demonstrates the use of several instances
+// of a variable font. The test font has weight and width axes.
+func ExampleFpdf_AddUTF8FontInstance() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	fontFileStr := example.FontFile("calligra-var.ttf")
+	pdf.AddUTF8FontInstance("calligra", "", fontFileStr, nil)
+	pdf.AddUTF8FontInstance("calligra", "B", fontFileStr, map[string]float64{"wght": 700})
+	pdf.AddUTF8FontInstance("calligra-condensed", "", fontFileStr, map[string]float64{"wdth": 75})
+	pdf.AddUTF8FontInstance("calligra-expanded", "", fontFileStr, map[string]float64{"wdth": 150})
+	pdf.AddPage()
+	for _, font := range []struct{ familyStr, styleStr string }{
+		{"calligra", ""}, {"calligra", "B"}, {"calligra-condensed", ""}, {"calligra-expanded", ""},
+	} {
+		pdf.SetFont(font.familyStr, font.styleStr, 24)
+		pdf.CellFormat(0, 14, "Hamburgefonstiv", "", 1, "", false, 0, "")
+	}
+	fileStr := example.Filename("Fpdf_AddUTF8FontInstance")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddUTF8FontInstance.pdf
+}
+
+// TestVariableFontInstance verifies the advance widths of instances of a
+// variable font, the mapping of axis values by its avar table and the errors
+// for unknown axes and static fonts.
+func TestVariableFontInstance(t *testing.T) {
+	font, err := ioutil.ReadFile(example.FontFile("calligra-var.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	width := func(axes map[string]float64) float64 {
+		instance, err := gofpdf.VariableFontInstance(font, axes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.AddUTF8FontFromBytes("calligra", "", instance)
+		pdf.SetFont("calligra", "", 20)
+		if err = pdf.Error(); err != nil {
+			t.Fatal(err)
+		}
+		return pdf.GetStringWidth("Hamburgefonstiv")
+	}
+	// The width axis scales the glyphs horizontally, and the weight axis
+	// widens them by 20% at its maximum, reached at 650 for 25%.
+	wd := width(nil)
+	for _, tc := range []struct {
+		axes  map[string]float64
+		ratio float64
+	}{
+		{map[string]float64{"wdth": 100, "wght": 400}, 1},
+		{map[string]float64{"wdth": 200}, 2},
+		{map[string]float64{"wdth": 500}, 2},
+		{map[string]float64{"wdth": 50}, 0.5},
+		{map[string]float64{"wght": 900}, 1.2},
+		{map[string]float64{"wght": 650}, 1.05},
+	} {
+		if ratio := width(tc.axes) / wd; math.Abs(ratio-tc.ratio) > 0.005 {
+			t.Errorf("width ratio %v for %v, expected %v", ratio, tc.axes, tc.ratio)
+		}
+	}
+	if _, err = gofpdf.VariableFontInstance(font, map[string]float64{"slnt": -10}); err == nil {
+		t.Error("expected error for unknown axis")
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontInstance("calligra", "", example.FontFile("calligra.ttf"), nil)
+	if err = pdf.Error(); err == nil || !strings.Contains(err.Error(), "not a variable font") {
+		t.Errorf("unexpected error for static font: %v", err)
+	}
+}
+
+// ExampleFpdf_SetFontLigatures demonstrates the standard ligatures of a
+// font.
+func ExampleFpdf_SetFontLigatures() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 24)
+	txtStr := "The official fluffy waffles"
+	pdf.CellFormat(0, 14, txtStr, "", 1, "", false, 0, "")
+	pdf.SetFontLigatures("dejavu", "", true)
+	pdf.CellFormat(0, 14, txtStr, "", 1, "", false, 0, "")
+	fileStr := example.Filename("Fpdf_SetFontLigatures")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFontLigatures.pdf
+}
+
+// TestFontLigatures verifies that ligatures are measured and shown when
+// enabled, that they are not formed across a zero width non-joiner and that
+// they map back to their characters for text extraction.
+func TestFontLigatures(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 12)
+	wd := pdf.GetStringWidth("office")
+	pdf.SetFontLigatures("dejavu", "", true)
+	if pdf.GetStringWidth("office") == wd {
+		t.Fatal("expected ligature width")
+	}
+	if sum := pdf.GetStringWidth("of‌") + pdf.GetStringWidth("fice"); math.Abs(pdf.GetStringWidth("of‌fice")-sum) > 1e-9 {
+		t.Fatal("unexpected ligature across zero width non-joiner")
+	}
+	pdf.Cell(0, 10, "office")
+	pdf.SetFontLigatures("dejavu", "", false)
+	if pdf.GetStringWidth("office") != wd {
+		t.Fatal("unexpected ligature width when disabled")
+	}
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`\n<E0[0-9A-F]{2}> <006600660069>\n`).Match(buf.Bytes()) {
+		t.Fatal("ligature missing from ToUnicode CMap")
+	}
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFontLigatures("helvetica", "", true)
+	if pdf.Error() == nil {
+		t.Fatal("expected error for undefined font")
+	}
+}
+
+// ExampleFpdf_SetSyntheticStyle demonstrates the bold and italic styles of a
+// font family of which only the regular style has been added.
+func ExampleFpdf_SetSyntheticStyle() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("calligra", "", example.FontFile("calligra.ttf"))
+	pdf.SetSyntheticStyle(true)
+	pdf.AddPage()
+	pdf.SetTextColor(0, 0, 128)
+	for _, styleStr := range []string{"", "B", "I", "BI"} {
+		pdf.SetFont("calligra", styleStr, 24)
+		pdf.CellFormat(0, 14, "Synthetic style \""+styleStr+"\"", "", 1, "", false, 0, "")
+	}
+	pdf.SetFont("calligra", "BI", 24)
+	pdf.Text(10, 80, "Text() in synthetic bold italic")
+	fileStr := example.Filename("Fpdf_SetSyntheticStyle")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetSyntheticStyle.pdf
+}
+
+// TestSyntheticStyle verifies that missing styles are synthesized only when
+// enabled and that styles that have been added are used as they are.
+func TestSyntheticStyle(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddUTF8Font("dejavu", "I", example.FontFile("DejaVuSansCondensed-Oblique.ttf"))
+	pdf.AddUTF8Font("calligra", "", example.FontFile("calligra.ttf"))
+	pdf.AddPage()
+	pdf.SetSyntheticStyle(true)
+	pdf.SetTextColor(255, 0, 0)
+	pdf.SetFont("dejavu", "B", 10)
+	pdf.Cell(0, 10, "bold")
+	pdf.SetFont("dejavu", "BI", 20)
+	pdf.Text(10, 40, "bold italic")
+	pdf.SetFont("dejavu", "I", 10)
+	pdf.Cell(0, 10, "italic")
+	pdf.SetFont("calligra", "I", 10)
+	pdf.Text(10, 50, "italic")
+	pdf.SetFont("helvetica", "B", 10)
+	pdf.Cell(0, 10, "helvetica")
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, str := range []string{
+		"q 1.000 0.000 0.000 RG 0.30 w 2 Tr BT ",
+		"q 1.000 0.000 0.000 RG 0.60 w 2 Tr BT 28.35 728.50 Td (",
+		"BT 1 0 0.21256 1 28.35 700.16 Tm (",
+	} {
+		if !strings.Contains(out, str) {
+			t.Errorf("missing %q", str)
+		}
+	}
+	// Only the bold style of the oblique font is synthesized
+	if strings.Count(out, " Tm") != 1 || strings.Count(out, " 2 Tr ") != 2 {
+		t.Error("unexpected synthetic style")
+	}
+	if !strings.Contains(out, "/BaseFont /Helvetica-Bold") {
+		t.Error("core font style not used")
+	}
+	pdf.SetSyntheticStyle(false)
+	pdf.SetFont("dejavu", "B", 10)
+	if pdf.Error() == nil {
+		t.Fatal("expected error for undefined style")
+	}
+}
+
+// ExampleFpdf_SetTextTransform demonstrates text printed in uppercase,
+// lowercase and small capitals.
+func ExampleFpdf_SetTextTransform() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 16)
+	for _, transformStr := range []string{
+		gofpdf.TextTransformNone,
+		gofpdf.TextTransformUppercase,
+		gofpdf.TextTransformLowercase,
+		gofpdf.TextTransformSmallCaps,
+	} {
+		pdf.SetTextTransform(transformStr)
+		pdf.CellFormat(0, 10, "The Quick Brown Fox Jumps over Łódź", "1", 1, "C", false, 0, "")
+	}
+	pdf.SetFont("helvetica", "", 16)
+	pdf.MultiCell(0, 8, "Core fonts show capitals in place of small capitals.", "", "", false)
+	fileStr := example.Filename("Fpdf_SetTextTransform")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetTextTransform.pdf
+}
+
+// TestTextTransform verifies the widths of transformed text and the size of
+// synthetic small capitals.
+func TestTextTransform(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	for _, family := range []string{"dejavu", "helvetica"} {
+		pdf.SetFont(family, "", 12)
+		pdf.SetTextTransform(gofpdf.TextTransformNone)
+		lower, upper := pdf.GetStringWidth("abc"), pdf.GetStringWidth("ABC")
+		pdf.SetTextTransform(gofpdf.TextTransformUppercase)
+		if pdf.GetStringWidth("abc") != upper {
+			t.Errorf("%s: unexpected uppercase width", family)
+		}
+		pdf.SetTextTransform(gofpdf.TextTransformLowercase)
+		if pdf.GetStringWidth("ABC") != lower {
+			t.Errorf("%s: unexpected lowercase width", family)
+		}
+	}
+	pdf.SetFont("dejavu", "", 12)
+	pdf.SetTextTransform(gofpdf.TextTransformNone)
+	wdA, wdB := pdf.GetStringWidth("A"), pdf.GetStringWidth("B")
+	pdf.SetTextTransform(gofpdf.TextTransformSmallCaps)
+	if wd := pdf.GetStringWidth("Ab"); math.Abs(wd-wdA-wdB*0.7) > 0.01 {
+		t.Errorf("unexpected small caps width %.3f", wd)
+	}
+	pdf.Cell(0, 10, "Ab")
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), " 8.40 Tf ") {
+		t.Error("synthetic small capitals not reduced")
+	}
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTextTransform("title")
+	if pdf.Error() == nil {
+		t.Fatal("expected error for invalid transform")
+	}
+}
+
+// ExampleFpdf_FontMetrics demonstrates the vertical metrics of fonts, drawn
+// as lines about the baseline of some text.
+func ExampleFpdf_FontMetrics() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetLineWidth(0.1)
+	y := 40.0
+	for _, family := range []string{"dejavu", "times"} {
+		pdf.SetFont(family, "", 48)
+		m := pdf.FontMetrics("", "")
+		txtStr := "Baseline fix"
+		w := pdf.GetStringWidth(txtStr)
+		pdf.Text(20, y, txtStr)
+		for _, line := range []struct {
+			height  float64
+			r, g, b int
+		}{
+			{0, 0, 0, 0},
+			{m.Ascent, 200, 0, 0},
+			{m.CapHeight, 0, 150, 0},
+			{m.XHeight, 0, 0, 200},
+			{m.Descent, 200, 0, 0},
+		} {
+			pdf.SetDrawColor(line.r, line.g, line.b)
+			pdf.Line(15, y-line.height, 25+w, y-line.height)
+		}
+		// Advance widths of the first character
+		x := 20 + m.Advance('B')
+		pdf.SetDrawColor(128, 128, 128)
+		pdf.Line(x, y-m.Ascent, x, y-m.Descent)
+		y += 40
+	}
+	fileStr := example.Filename("Fpdf_FontMetrics")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_FontMetrics.pdf
+}
+
+// TestFontMetrics verifies the metrics of UTF-8 and core fonts.
+func TestFontMetrics(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFont("helvetica", "B", 10)
+	m := pdf.FontMetrics("", "")
+	if m.Size != 10 || m.Ascent != 7.18 || m.Descent != -2.07 || m.CapHeight != 7.18 || m.XHeight != 5.32 {
+		t.Errorf("unexpected core font metrics %+v", m)
+	}
+	if m.UnderlinePosition != -1 || m.UnderlineThickness != 0.5 {
+		t.Errorf("unexpected core font underline %f %f", m.UnderlinePosition, m.UnderlineThickness)
+	}
+	if m.Advance('W') != pdf.GetStringWidth("W") {
+		t.Error("unexpected core font advance")
+	}
+	m = pdf.FontMetrics("dejavu", "")
+	if !(m.Ascent > m.CapHeight && m.CapHeight > m.XHeight && m.XHeight > 0 && m.Descent < 0) {
+		t.Errorf("unexpected UTF-8 font metrics %+v", m)
+	}
+	pdf.SetFont("dejavu", "", 10)
+	if m.Advance('Ж')+m.Advance('🙂') != pdf.GetStringWidth("Ж🙂") {
+		t.Error("unexpected UTF-8 font advance")
+	}
+	if m = pdf.FontMetrics("times", ""); m.Ascent != 0 || m.Advance('A') != 0 {
+		t.Error("expected zero metrics for font that has not been added")
+	}
+}
+
+// ExampleFpdf_TextToPath demonstrates text drawn as the outlines of its
+// glyphs, which can be painted and transformed like any other path.
+func ExampleFpdf_TextToPath() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "B", example.FontFile("DejaVuSansCondensed-Bold.ttf"))
+	pdf.AddUTF8Font("go", "", example.FontFile("Go-Regular.otf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "B", 48)
+	pdf.SetFillColor(255, 220, 0)
+	pdf.SetDrawColor(0, 0, 160)
+	pdf.SetLineWidth(0.8)
+	pdf.TextToPath(20, 40, "Outlined text", "DF")
+	pdf.TransformBegin()
+	pdf.TransformSkewX(-20, 20, 80)
+	pdf.SetFillColor(200, 0, 0)
+	pdf.TextToPath(20, 80, "Skewed path", "F")
+	pdf.TransformEnd()
+	// The outline of a single glyph, drawn with its points
+	pdf.SetFont("go", "", 96)
+	x, y := 30.0, 150.0
+	pdf.SetLineWidth(0.2)
+	for _, seg := range pdf.GlyphOutline('g') {
+		switch seg.Cmd {
+		case 'M':
+			pdf.MoveTo(x+seg.Arg[0], y+seg.Arg[1])
+		case 'L':
+			pdf.LineTo(x+seg.Arg[0], y+seg.Arg[1])
+		case 'C':
+			pdf.CurveBezierCubicTo(x+seg.Arg[0], y+seg.Arg[1], x+seg.Arg[2], y+seg.Arg[3], x+seg.Arg[4], y+seg.Arg[5])
+		case 'Z':
+			pdf.ClosePath()
+		}
+	}
+	pdf.DrawPath("D")
+	fileStr := example.Filename("Fpdf_TextToPath")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_TextToPath.pdf
+}
+
+// TestGlyphOutline verifies the outlines of TrueType and PostScript glyphs
+// and text drawn as paths.
+func TestGlyphOutline(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddUTF8Font("go", "", example.FontFile("Go-Regular.otf"))
+	pdf.AddPage()
+	for _, family := range []string{"dejavu", "go"} {
+		pdf.SetFont(family, "", 100)
+		m := pdf.FontMetrics("", "")
+		segs := pdf.GlyphOutline('o')
+		contours := 0
+		top := 0.0
+		for _, seg := range segs {
+			switch seg.Cmd {
+			case 'Z':
+				contours++
+			case 'M', 'L':
+				top = math.Min(top, seg.Arg[1])
+			case 'Q':
+				top = math.Min(top, seg.Arg[3])
+			case 'C':
+				top = math.Min(top, seg.Arg[5])
+			}
+		}
+		if contours != 2 || math.Abs(top+m.XHeight) > 2 {
+			t.Errorf("%s: unexpected outline of o: %d contours, top %.2f", family, contours, top)
+		}
+		if pdf.GlyphOutline(' ') != nil || pdf.GlyphOutline('࿿') != nil {
+			t.Errorf("%s: unexpected outline of space or missing glyph", family)
+		}
+	}
+	pdf.TextToPath(10, 100, "Path", "F")
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`\n[0-9. ]+ m .* c .*h f\n`).Match(buf.Bytes()) {
+		t.Error("text path missing")
+	}
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("helvetica", "", 12)
+	pdf.TextToPath(10, 10, "Path", "F")
+	if pdf.Error() == nil {
+		t.Fatal("expected error for core font")
+	}
+}
+
+// ExampleFpdf_AddUTF8Font_color demonstrates the color glyphs of a color
+// font, such as the emoji of an emoji font, which are printed in color and
+// remain extractable as text.
+func ExampleFpdf_AddUTF8Font_color() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("calligra", "", example.FontFile("calligra-color.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("calligra", "", 36)
+	pdf.SetTextColor(0, 0, 120)
+	// '&' and 'o' are layered glyphs, '*' and '#' are bitmaps
+	pdf.Text(20, 40, "Rock & roll * #")
+	pdf.SetFont("calligra", "", 20)
+	pdf.SetX(20)
+	pdf.CellFormat(120, 20, "Color & more * glyphs", "1", 1, "J", false, 0, "")
+	fileStr := example.Filename("Fpdf_AddUTF8Font_color")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddUTF8Font_color.pdf
+}
+
+// TestColorGlyphs verifies that the layers and bitmaps of color glyphs are
+// drawn over invisible text.
+func TestColorGlyphs(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("calligra", "", example.FontFile("calligra-color.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("calligra", "", 20)
+	pdf.SetTextColor(0, 128, 0)
+	pdf.Text(10, 100, "a&o*#")
+	pdf.Text(10, 200, "plain")
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	for _, re := range []string{
+		`\) ?Tj 3 Tr \(`,
+		`q 1\.000 0\.000 0\.000 rg [0-9. ]+ m .* f Q`,
+		`q 0\.000 0\.000 1\.000 rg [0-9. ]+ m `,
+		`q 0\.000 0\.502 0\.000 rg [0-9. ]+ m `,
+		`q 20\.00000 0 0 20\.00000 [0-9.]+ [0-9.]+ cm /I\w+ Do Q`,
+		`q 16\.00000 0 0 16\.00000 [0-9.]+ [0-9.]+ cm /I\w+ Do Q`,
+	} {
+		if !regexp.MustCompile(re).MatchString(s) {
+			t.Errorf("missing %s", re)
+		}
+	}
+	if n := strings.Count(s, " 3 Tr "); n != 1 {
+		t.Errorf("expected 1 run of invisible text, found %d", n)
+	}
+	if n := strings.Count(s, " Do Q"); n != 2 {
+		t.Errorf("expected 2 glyph bitmaps, found %d", n)
+	}
+}
+
+// ExampleFpdf_SetFontCache demonstrates the caching of the metadata of UTF-8
+// fonts in a directory, so that documents generated later, possibly by other
+// runs of the program, do not parse the fonts again.
+func ExampleFpdf_SetFontCache() {
+	dir, err := ioutil.TempDir("", "fontcache")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	cache := gofpdf.NewFontCacheDir(dir)
+	var fileStr string
+	for j := 1; j <= 3 && err == nil; j++ {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetFontCache(cache)
+		pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+		pdf.AddPage()
+		pdf.SetFont("dejavu", "", 14)
+		pdf.Text(20, 20, fmt.Sprintf("Report %d", j))
+		fileStr = example.Filename(fmt.Sprintf("Fpdf_SetFontCache_%d", j))
+		err = pdf.OutputFileAndClose(fileStr)
+	}
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFontCache_3.pdf
+}
+
+// mapFontCache is a FontCache that counts the fonts found in it.
+type mapFontCache struct {
+	data map[string][]byte
+	hits int
+}
+
+func (c *mapFontCache) Get(key string) ([]byte, bool) {
+	data, ok := c.data[key]
+	if ok {
+		c.hits++
+	}
+	return data, ok
+}
+
+func (c *mapFontCache) Put(key string, data []byte) {
+	c.data[key] = data
+}
+
+// TestFontCache verifies that a document whose fonts are taken from a font
+// cache is identical to one whose fonts are parsed.
+func TestFontCache(t *testing.T) {
+	generate := func(cache gofpdf.FontCache) ([]byte, error) {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCreationDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		if cache != nil {
+			pdf.SetFontCache(cache)
+		}
+		pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+		pdf.AddUTF8Font("go", "", example.FontFile("Go-Regular.otf"))
+		pdf.AddPage()
+		pdf.SetFont("dejavu", "", 16)
+		pdf.Cell(60, 10, "Hello, Wörld")
+		pdf.SetFont("go", "", 16)
+		pdf.Cell(60, 10, fmt.Sprintf("%.3f", pdf.GetStringWidth("Hello")))
+		var buf bytes.Buffer
+		err := pdf.Output(&buf)
+		return buf.Bytes(), err
+	}
+	parsed, err := generate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := &mapFontCache{data: make(map[string][]byte)}
+	for j := 0; j < 2; j++ {
+		cached, err := generate(cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(parsed, cached) {
+			t.Fatalf("document %d differs with font cache", j)
+		}
+	}
+	if len(cache.data) != 2 || cache.hits != 2 {
+		t.Fatalf("expected 2 cached fonts and 2 hits, found %d and %d", len(cache.data), cache.hits)
+	}
+}
+
+// ExampleFpdf_FontUsage demonstrates the characters and glyphs of a font
+// that a document uses, as needed to subset the font.
+func ExampleFpdf_FontUsage() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFontLigatures("dejavu", "", true)
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 24)
+	pdf.MultiCell(0, 12, "The official fluffy waffles", "", "", false)
+	usage := pdf.FontUsage("dejavu", "")
+	fmt.Printf("Characters: %q\n", string(usage.Runes))
+	fmt.Printf("Glyphs: %d\n", len(usage.Glyphs))
+	fileStr := example.Filename("Fpdf_FontUsage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Characters: " Tacefhilosuwy"
+	// Glyphs: 17
+	// Successfully generated pdf/Fpdf_FontUsage.pdf
+}
+
+// TestFontUsage verifies that the glyphs of printed text are recorded,
+// including ligatures, while measured text is not.
+func TestFontUsage(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 12)
+	if usage := pdf.FontUsage("dejavu", ""); len(usage.Runes) != 0 || len(usage.Glyphs) != 0 {
+		t.Fatalf("unexpected usage before output: %v", usage)
+	}
+	pdf.GetStringWidth("xyz")
+	pdf.Cell(0, 10, "oce")
+	plain := pdf.FontUsage("dejavu", "")
+	pdf.SetFontLigatures("dejavu", "", true)
+	pdf.Cell(0, 10, "ffi")
+	usage := pdf.FontUsage("dejavu", "")
+	if string(plain.Runes) != "ceo" || len(plain.Glyphs) != 3 {
+		t.Fatalf("unexpected usage: %v", plain)
+	}
+	if string(usage.Runes) != "cefio" || len(usage.Glyphs) != 4 {
+		t.Fatalf("unexpected usage with ligature: %v", usage)
+	}
+	if usage := pdf.FontUsage("helvetica", ""); usage.Runes != nil {
+		t.Fatal("unexpected usage of core font")
+	}
+}
+
+// TestEmbeddedCodePages verifies that the code page maps packaged with
+// gofpdf are available without a font directory, both to translate text and
+// to generate font definitions.
+func TestEmbeddedCodePages(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "/nonexistent")
+	for _, c := range []struct{ cpStr, str, want string }{
+		{"koi8-r", "Яд", "\xf1\xc4"},
+		{"cp1253", "Ωμέγα", "\xd9\xec\xdd\xe3\xe1"},
+		{"cp874", "ไทย", "\xe4\xb7\xc2"},
+		{"iso-8859-15", "€", "\xa4"},
+	} {
+		tr := pdf.UnicodeTranslatorFromDescriptor(c.cpStr)
+		if pdf.Err() {
+			t.Fatalf("%s: %v", c.cpStr, pdf.Error())
+		}
+		if got := tr(c.str); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.cpStr, got, c.want)
+		}
+	}
+	dir, err := ioutil.TempDir("", "codepage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = gofpdf.MakeFont(example.FontFile("calligra.ttf"), "koi8-r.map", dir, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := ioutil.ReadFile(filepath.Join(dir, "calligra.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(def, []byte("/afii10049")) {
+		t.Fatal("KOI8-R differences missing from font definition")
+	}
+}
+
+// fontWithEmbeddingFlags returns the TrueType font fileStr with its
+// embedding permissions replaced by flags.
+func fontWithEmbeddingFlags(t *testing.T, fileStr string, flags uint16) []byte {
+	data, err := ioutil.ReadFile(example.FontFile(fileStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for j := 0; j < numTables; j++ {
+		record := data[12+16*j:]
+		if string(record[:4]) == "OS/2" {
+			binary.BigEndian.PutUint16(data[binary.BigEndian.Uint32(record[8:])+8:], flags)
+			return data
+		}
+	}
+	t.Fatal("font has no OS/2 table")
+	return nil
+}
+
+// TestFontEmbeddingPermissions verifies that the embedding permissions of
+// fonts are reported and, when enforced, respected.
+func TestFontEmbeddingPermissions(t *testing.T) {
+	restricted := fontWithEmbeddingFlags(t, "calligra.ttf", gofpdf.FontEmbeddingRestricted)
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("restricted", "", restricted)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	if flags := pdf.FontEmbeddingPermissions("restricted", ""); flags != gofpdf.FontEmbeddingRestricted {
+		t.Fatalf("unexpected permissions of restricted font: %#x", flags)
+	}
+	if flags := pdf.FontEmbeddingPermissions("dejavu", ""); flags != gofpdf.FontEmbeddingInstallable {
+		t.Fatalf("unexpected permissions of installable font: %#x", flags)
+	}
+	if pdf.FontEmbeddingPermissions("helvetica", "") != -1 || pdf.Err() {
+		t.Fatal("unexpected permissions of undefined font")
+	}
+	pdf.SetFontEmbeddingEnforced(true)
+	pdf.AddUTF8FontFromBytes("restricted", "B", restricted)
+	if pdf.Error() == nil || !strings.Contains(pdf.Error().Error(), "does not allow embedding") {
+		t.Fatalf("expected error for restricted font, got %v", pdf.Error())
+	}
+	generate := func(flags uint16) string {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(false)
+		pdf.SetFontEmbeddingEnforced(true)
+		pdf.AddUTF8FontFromBytes("calligra", "", fontWithEmbeddingFlags(t, "calligra.ttf", flags))
+		pdf.AddPage()
+		pdf.SetFont("calligra", "", 16)
+		pdf.Cell(40, 10, "Hello")
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	subset, full := generate(gofpdf.FontEmbeddingEditable), generate(gofpdf.FontEmbeddingNoSubsetting)
+	if !regexp.MustCompile(`/FontName /[A-Z]{6}\+utf8calligra\b`).MatchString(subset) {
+		t.Fatal("subset font name missing")
+	}
+	if !strings.Contains(full, "/FontName /utf8calligra") || len(full) <= len(subset) {
+		t.Fatal("font that may not be subset not embedded in full")
+	}
+}
+
+// ExampleFpdf_ParagraphNew demonstrates paragraphs laid out as a whole, with
+// full justification and ragged alignments.
+func ExampleFpdf_ParagraphNew() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	txtStr := "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod " +
+		"tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis " +
+		"nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat. Duis " +
+		"aute irure dolor in reprehenderit in voluptate velit esse cillum dolore eu fugiat " +
+		"nulla pariatur."
+	pdf.SetFont("Times", "", 12)
+	for _, alignStr := range []string{"J", "L", "C", "R"} {
+		par := pdf.ParagraphNew(90, 5, alignStr)
+		ht := par.Write(txtStr)
+		pdf.SetFont("Times", "I", 9)
+		pdf.CellFormat(90, 6, fmt.Sprintf("Alignment %s, %.0f mm high", alignStr, ht), "", 1, "R", false, 0, "")
+		pdf.SetFont("Times", "", 12)
+	}
+	pdf.SetFont("dejavu", "", 11)
+	par := pdf.ParagraphNew(0, 5.5, "J")
+	par.Write("Zwölf Boxkämpfer jagen Viktor quer über den großen Sylter Deich. " + txtStr)
+	fileStr := example.Filename("Fpdf_ParagraphNew")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ParagraphNew.pdf
+}
+
+// TestParagraph verifies that paragraph lines fit, that they are more even
+// than the lines of SplitText() and that writing a paragraph advances the
+// current position by its height.
+func TestParagraph(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 12)
+	txtStr := "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod " +
+		"tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis " +
+		"nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat."
+	w := 60.0
+	wmax := w - 2*pdf.GetCellMargin()
+	raggedness := func(lines []string) (sum float64) {
+		for _, line := range lines[:len(lines)-1] {
+			slack := wmax - pdf.GetStringWidth(line)
+			if slack < -1e-9 {
+				t.Fatalf("line too wide: %q", line)
+			}
+			sum += slack * slack
+		}
+		return
+	}
+	par := pdf.ParagraphNew(w, 5, "J")
+	lines := par.Lines(txtStr)
+	if raggedness(lines) >= raggedness(pdf.SplitText(txtStr, w)) {
+		t.Fatal("paragraph lines not more even than greedy lines")
+	}
+	if got := strings.Join(par.Lines("one\n\ntwo\n"), "|"); got != "one||two" {
+		t.Fatalf("unexpected lines %q", got)
+	}
+	y := pdf.GetY()
+	ht := par.Write(txtStr)
+	if ht != 5*float64(len(lines)) || math.Abs(pdf.GetY()-y-ht) > 1e-9 || ht != par.Height(txtStr) {
+		t.Fatalf("unexpected paragraph height %.2f", ht)
+	}
+}
+
+// ExampleFpdf_SetHyphenator demonstrates the hyphenation of words in narrow
+// columns. The few patterns used here are those with which Liang's algorithm
+// is commonly illustrated; a real application would read the patterns of its
+// language from a file of the hyph-utf8 project.
+func ExampleFpdf_SetHyphenator() {
+	hyph, err := gofpdf.NewHyphenator(strings.NewReader(`
+		% Patterns
+		\patterns{ hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n 1ca 1tion 4nc }
+		\hyphenation{ pro-ject ta-ble }`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	txtStr := "The hyphenation of a project in a table is an application of hyphenation. " +
+		"Justification without hyphenation is a complication."
+	pdf.MultiCell(40, 5, txtStr, "1", "J", false)
+	pdf.Ln(5)
+	pdf.SetHyphenator(hyph)
+	pdf.MultiCell(40, 5, txtStr, "1", "J", false)
+	pdf.Ln(5)
+	par := pdf.ParagraphNew(40, 5, "J")
+	par.Write(txtStr)
+	fmt.Println(strings.Join(hyph.Hyphenate("hyphenation"), "-"))
+	fileStr := example.Filename("Fpdf_SetHyphenator")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// hy-phen-ation
+	// Successfully generated pdf/Fpdf_SetHyphenator.pdf
+}
+
+// TestHyphenator verifies the hyphenation of words by patterns and
+// exceptions, and that hyphenated lines of MultiCell() and paragraphs fit.
+func TestHyphenator(t *testing.T) {
+	hyph, err := gofpdf.NewHyphenator(strings.NewReader("hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n ta-ble"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, want := range map[string]string{
+		"hyphenation":  "hy-phen-ation",
+		"Hyphenation.": "Hy-phen-ation.",
+		"table":        "ta-ble",
+		"nation":       "na-tion",
+		"on":           "on",
+	} {
+		if got := strings.Join(hyph.Hyphenate(word), "-"); got != want {
+			t.Errorf("%s: got %s, want %s", word, got, want)
+		}
+	}
+	if _, err := gofpdf.NewHyphenator(strings.NewReader("a1b \\foo")); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetHyphenator(hyph)
+	w := 22.0
+	par := pdf.ParagraphNew(w, 5, "J")
+	txtStr := "the hyphenation hyphenation"
+	lines := par.Lines(txtStr)
+	hyphenated, joined := false, ""
+	for _, line := range lines {
+		if pdf.GetStringWidth(line) > w-2*pdf.GetCellMargin() {
+			t.Fatalf("line too wide: %q", line)
+		}
+		if strings.HasSuffix(line, "-") {
+			hyphenated = true
+			joined += strings.TrimSuffix(line, "-")
+		} else {
+			joined += line + " "
+		}
+	}
+	if !hyphenated || strings.TrimSpace(joined) != txtStr {
+		t.Fatalf("unexpected paragraph lines %q", lines)
+	}
+	pdf.MultiCell(w, 5, "the hyphenation", "", "L", false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "(the hy-)") || !strings.Contains(buf.String(), "(phenation)") {
+		t.Fatal("hyphenated MultiCell lines missing")
+	}
+}
+
+// ExampleFpdf_SetOrphansWidows demonstrates the control of widows and orphans
+// at automatic page breaks. Without it, the first paragraph would leave a
+// single line at the bottom of the first page and the second paragraph a
+// single line at the top of the third page.
+func ExampleFpdf_SetOrphansWidows() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetFont("Times", "", 12)
+	pdf.SetOrphansWidows(2, 2)
+	pdf.AddPage()
+	pdf.SetY(184)
+	txtStr := strings.Repeat(lorem()+" ", 2)
+	pdf.MultiCell(0, 5, txtStr, "", "J", false)
+	pdf.SetY(130)
+	par := pdf.ParagraphNew(0, 5, "J")
+	par.Write(txtStr)
+	fileStr := example.Filename("Fpdf_SetOrphansWidows")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetOrphansWidows.pdf
+}
+
+// TestOrphansWidows verifies the page breaks of MultiCell() and paragraphs
+// that avoid widows and orphans.
+func TestOrphansWidows(t *testing.T) {
+	const h = 10.0
+	txtStr := "aa bb cc dd ee"
+	for _, test := range []struct {
+		orphans, widows int
+		fit             int // Lines that fit on the first page
+		lines           int // Lines printed on the last page
+		pages           int
+	}{
+		{1, 1, 1, 4, 2},
+		{2, 1, 1, 5, 2},
+		{1, 1, 4, 1, 2},
+		{1, 2, 4, 2, 2},
+		{3, 3, 4, 5, 2}, // Both cannot be avoided on the first page
+		{1, 5, 4, 5, 2},
+		{2, 2, 5, 5, 1},
+	} {
+		for _, par := range []bool{false, true} {
+			pdf := gofpdf.New("P", "mm", "A4", "")
+			pdf.SetFont("Helvetica", "", 12)
+			pdf.SetOrphansWidows(test.orphans, test.widows)
+			pdf.AddPage()
+			_, _, _, bottom := pdf.GetMargins()
+			_, pageHt := pdf.GetPageSize()
+			y := pageHt - bottom - float64(test.fit)*h
+			pdf.SetY(y)
+			if par {
+				p := pdf.ParagraphNew(10, h, "L")
+				p.Write(txtStr)
+			} else {
+				pdf.MultiCell(10, h, txtStr, "", "L", false)
+			}
+			if pdf.PageNo() > 1 {
+				_, y, _, _ = pdf.GetMargins()
+			}
+			lines := int((pdf.GetY()-y)/h + 0.5)
+			if pdf.PageNo() != test.pages || lines != test.lines {
+				t.Errorf("orphans %d, widows %d, %d lines fit, paragraph %v: got %d lines on page %d, want %d on page %d",
+					test.orphans, test.widows, test.fit, par, lines, pdf.PageNo(), test.lines, test.pages)
+			}
+		}
+	}
+}
+
+// ExampleFpdf_MultiCellSpans demonstrates text with inline styles, colors,
+// sizes and links that wraps within a paragraph.
+func ExampleFpdf_MultiCellSpans() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 12)
+	red := &gofpdf.RGBType{R: 200, G: 0, B: 0}
+	spans := []gofpdf.TextSpan{
+		{Text: "Spans of text can be "},
+		{Text: "bold", FontStyle: "B"},
+		{Text: ", "},
+		{Text: "italic", FontStyle: "I"},
+		{Text: ", "},
+		{Text: "underlined", FontStyle: "U"},
+		{Text: ", "},
+		{Text: "red", TextColor: red},
+		{Text: ", "},
+		{Text: "larger", FontSize: 16},
+		{Text: " or in "},
+		{Text: "another font", FontFamily: "Helvetica"},
+		{Text: ". A span can also be a "},
+		{Text: "link", FontStyle: "U", TextColor: &gofpdf.RGBType{B: 200}, LinkStr: "https://github.com/phpdave11/gofpdf"},
+		{Text: ". Words are wrapped as a whole even when spans split them: un"},
+		{Text: "break", FontStyle: "B"},
+		{Text: "able. " + lorem()},
+	}
+	for _, alignStr := range []string{"L", "C", "R", "J"} {
+		pdf.MultiCellSpans(90, 6, spans, "1", alignStr, false)
+		pdf.Ln(6)
+	}
+	pdf.WriteSpans(6, spans)
+	fileStr := example.Filename("Fpdf_MultiCellSpans")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_MultiCellSpans.pdf
+}
+
+// TestTextSpans verifies the line breaks of spans and that the font and text
+// color are restored after printing them.
+func TestTextSpans(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetTextColor(0, 0, 200)
+	// The width of "aa bbcc" with cell margins fits, that of "aa bbcc dd" does
+	// not
+	w := pdf.GetStringWidth("aa ") + 2*pdf.GetCellMargin() + 1
+	pdf.SetFont("", "B", 16)
+	w += pdf.GetStringWidth("bb")
+	pdf.SetFont("", "I", 12)
+	w += pdf.GetStringWidth("cc")
+	pdf.SetFont("", "", 12)
+	spans := []gofpdf.TextSpan{
+		{Text: "aa "},
+		{Text: "bb", FontStyle: "B", FontSize: 16},
+		{Text: "cc dd", FontStyle: "I", TextColor: &gofpdf.RGBType{R: 255}},
+	}
+	y := pdf.GetY()
+	pdf.MultiCellSpans(w, 10, spans, "", "L", false)
+	if lines := (pdf.GetY() - y) / 10; lines != 2 {
+		t.Fatalf("got %.1f lines, want 2", lines)
+	}
+	if pt, _ := pdf.GetFontSize(); pt != 12 {
+		t.Fatalf("font size not restored: %.1f", pt)
+	}
+	if r, g, b := pdf.GetTextColor(); r != 0 || g != 0 || b != 200 {
+		t.Fatalf("text color not restored: %d %d %d", r, g, b)
+	}
+	// Text in a single span is written as by Write()
+	txtStr := "The quick brown fox jumps over the lazy dog. "
+	pdf.SetXY(50, 100)
+	pdf.Write(5, strings.Repeat(txtStr, 5))
+	x, y := pdf.GetXY()
+	pdf.SetXY(50, 150)
+	pdf.WriteSpans(5, []gofpdf.TextSpan{{Text: strings.Repeat(txtStr, 5)}})
+	if x2, y2 := pdf.GetXY(); math.Abs(x2-x) > 0.01 || math.Abs(y2-150-(y-100)) > 0.01 {
+		t.Fatalf("WriteSpans ends at %.2f, %.2f, Write at %.2f, %.2f", x2, y2-50, x, y)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"(aa )", "(bb)", "(cc)", "(dd)"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Fatalf("%s missing", str)
+		}
+	}
+}
+
+// ExampleTextSpan_image demonstrates images that flow with text, such as
+// icons, including one that is taller than the line height.
+func ExampleTextSpan_image() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	icon := func(name string, ht float64) gofpdf.TextSpan {
+		return gofpdf.TextSpan{ImageName: example.ImageFile(name), ImageHeight: ht}
+	}
+	spans := []gofpdf.TextSpan{
+		{Text: "Images such as the "},
+		icon("golang-gopher.png", 4),
+		{Text: " gopher or the "},
+		icon("logo.png", 4),
+		{Text: " logo flow with the text of a paragraph and stand on its baseline. "},
+		{Text: "An image taller than the line height, such as this larger "},
+		icon("golang-gopher.png", 15),
+		{Text: " gopher, makes its line taller. " + lorem()},
+	}
+	pdf.MultiCellSpans(100, 5, spans, "1", "J", false)
+	pdf.Ln(5)
+	pdf.WriteSpans(5, spans)
+	fileStr := example.Filename("TextSpan_image")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/TextSpan_image.pdf
+}
+
+// TestTextSpanImage verifies that lines with images taller than the line
+// height grow to fit them.
+func TestTextSpanImage(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	image := example.ImageFile("logo.png")
+	for _, test := range []struct {
+		imageHt float64
+		ht      float64
+	}{
+		{3, 10},
+		{20, 20 + 10 - (5 + 0.3*12/pdf.GetConversionRatio())},
+	} {
+		y := pdf.GetY()
+		pdf.MultiCellSpans(50, 10, []gofpdf.TextSpan{{Text: "Logo "}, {ImageName: image, ImageHeight: test.imageHt}}, "", "L", false)
+		if ht := pdf.GetY() - y; math.Abs(ht-test.ht) > 1e-6 {
+			t.Errorf("image height %.1f: got line height %.3f, want %.3f", test.imageHt, ht, test.ht)
+		}
+	}
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+}
+
+// ExampleFpdf_SetColumns demonstrates text that flows in columns from page
+// to page, with the columns of the last page balanced.
+func ExampleFpdf_SetColumns() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(0, 10, "The gofpdf Gazette", "B", 1, "C", false, 0, "")
+		pdf.Ln(5)
+	})
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 11)
+	pdf.MultiCell(0, 5, lorem(), "", "J", false)
+	pdf.Ln(5)
+	pdf.SetColumns(3, 6)
+	pdf.BalancedColumns(func() {
+		for j := 0; j < 16; j++ {
+			pdf.SetFont("Times", "B", 11)
+			pdf.CellFormat(0, 6, fmt.Sprintf("Article %d", j+1), "", 1, "", false, 0, "")
+			pdf.SetFont("Times", "", 11)
+			pdf.MultiCell(0, 5, lorem(), "", "J", false)
+			pdf.Ln(3)
+		}
+	})
+	pdf.MultiCell(0, 5, "The columns of the last page end at about the same height.", "T", "C", false)
+	fileStr := example.Filename("Fpdf_SetColumns")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetColumns.pdf
+}
+
+// TestColumns verifies the flow of text from column to column and page to
+// page, and the balancing of columns.
+func TestColumns(t *testing.T) {
+	const h = 10.0
+	lines := func(pdf *gofpdf.Fpdf, n int) {
+		for j := 0; j < n; j++ {
+			pdf.MultiCell(0, h, fmt.Sprintf("line %d", j), "", "L", false)
+		}
+	}
+	newPdf := func() (*gofpdf.Fpdf, float64) {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetFont("Helvetica", "", 12)
+		pdf.AddPage()
+		_, _, _, bottom := pdf.GetMargins()
+		_, pageHt := pdf.GetPageSize()
+		// Eight lines fit in the columns of the first page
+		y0 := pageHt - bottom - 8*h
+		pdf.SetY(y0)
+		return pdf, y0
+	}
+
+	pdf, y0 := newPdf()
+	left, _, right, _ := pdf.GetMargins()
+	pdf.SetColumns(2, 10)
+	lines(pdf, 10)
+	if n, col := pdf.GetColumns(); n != 2 || col != 1 || pdf.PageNo() != 1 {
+		t.Fatalf("got column %d of %d on page %d, want column 1 of 2 on page 1", col, n, pdf.PageNo())
+	}
+	if x := pdf.GetX(); math.Abs(x-(10+90+10)) > 0.01 {
+		t.Fatalf("second column starts at %.2f", x)
+	}
+	pdf.SetColumns(1, 0)
+	if y := pdf.GetY(); math.Abs(y-(y0+8*h)) > 1e-6 {
+		t.Fatalf("got %.2f below the columns, want %.2f", y, y0+8*h)
+	}
+	if l, _, r, _ := pdf.GetMargins(); l != left || r != right {
+		t.Fatalf("margins not restored")
+	}
+	// The third column flows to the first column of the next page
+	pdf, _ = newPdf()
+	pdf.SetColumns(2, 10)
+	lines(pdf, 18)
+	if _, col := pdf.GetColumns(); col != 0 || pdf.PageNo() != 2 {
+		t.Fatalf("got column %d on page %d, want column 0 on page 2", col, pdf.PageNo())
+	}
+	// Balanced columns
+	pdf, y0 = newPdf()
+	pdf.SetColumns(2, 10)
+	pdf.BalancedColumns(func() {
+		lines(pdf, 10)
+	})
+	if pdf.PageNo() != 1 {
+		t.Fatalf("balanced columns continued on page %d", pdf.PageNo())
+	}
+	if y := pdf.GetY(); math.Abs(y-(y0+5*h)) > 1e-6 {
+		t.Fatalf("balanced columns end at %.2f, want %.2f", y, y0+5*h)
+	}
+	if n, _ := pdf.GetColumns(); n != 0 {
+		t.Fatalf("columns not ended")
+	}
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+}
+
+// ExampleFpdf_SetTabStops demonstrates text aligned at left, right, centered
+// and decimal tab stops.
+func ExampleFpdf_SetTabStops() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetTabStops(
+		gofpdf.TabStopType{Pos: 50},
+		gofpdf.TabStopType{Pos: 100, AlignStr: "C"},
+		gofpdf.TabStopType{Pos: 140, AlignStr: "D"},
+		gofpdf.TabStopType{Pos: 185, AlignStr: "R"},
+	)
+	rows := [][]string{
+		{"Item", "Description", "Qty", "Price", "Total"},
+		{"1", "Widget", "2", "12.50", "25.00"},
+		{"2", "Gadget with a longer name", "10", "1.255", "12.55"},
+		{"3", "Sprocket", "125", "100", "12500.00"},
+	}
+	for j, row := range rows {
+		pdf.CellFormat(0, 8, strings.Join(row, "\t"), "B", 1, "", j == 0, 0, "")
+	}
+	pdf.Ln(5)
+	pdf.SetTabStops(gofpdf.TabStopType{Pos: 40})
+	pdf.Write(6, "Name:\tJane Doe\nAddress:\t1 Main Street\nCity:\tSpringfield\n")
+	fileStr := example.Filename("Fpdf_SetTabStops")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetTabStops.pdf
+}
+
+// TestTabStops verifies the positions of text aligned at tab stops.
+func TestTabStops(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetCellMargin(0)
+	pdf.AddPage()
+	pdf.SetFont("Courier", "", 10)
+	// Characters of Courier are 6 points wide at 10 points
+	pdf.SetTabStops(
+		gofpdf.TabStopType{Pos: 100, AlignStr: "R"},
+		gofpdf.TabStopType{Pos: 200, AlignStr: "D"},
+		gofpdf.TabStopType{Pos: 300, AlignStr: "C"},
+		gofpdf.TabStopType{Pos: 400},
+	)
+	pdf.SetXY(50, 100)
+	pdf.CellFormat(0, 20, "a\tbb\t12.345\tcccc\tdd", "", 1, "", false, 0, "")
+	// A tab beyond the last tab stop is followed by a space
+	pdf.SetXY(50, 200)
+	pdf.Write(20, "x\ty\t\t\t\tz")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	_, pageHt := pdf.GetPageSize()
+	left, _, _, _ := pdf.GetMargins()
+	for _, test := range []struct {
+		str  string
+		x, y float64
+	}{
+		{"a", 50, 100},
+		{"bb", 50 + 100 - 12, 100},
+		{"12.345", 50 + 200 - 12, 100},
+		{"cccc", 50 + 300 - 12, 100},
+		{"dd", 50 + 400, 100},
+		{"x", 50, 200},
+		{"y", left + 100 - 6, 200},
+		{"z", left + 400 + 6, 200},
+	} {
+		re := regexp.MustCompile(`BT ([0-9.]+) ([0-9.]+) Td \(` + regexp.QuoteMeta(test.str) + `\)Tj`)
+		m := re.FindStringSubmatch(buf.String())
+		if m == nil {
+			t.Fatalf("%s not found", test.str)
+		}
+		x, _ := strconv.ParseFloat(m[1], 64)
+		y, _ := strconv.ParseFloat(m[2], 64)
+		if math.Abs(x-test.x) > 0.01 || y < pageHt-test.y-20 || y > pageHt-test.y {
+			t.Errorf("%s: got %.2f, %.2f, want %.2f on the line at %.2f", test.str, x, y, test.x, test.y)
+		}
+	}
+}
+
+// ExampleFpdf_SetLineHeightFactor demonstrates a line height that follows
+// the size of the font, used by the text methods given a line height of 0.
+func ExampleFpdf_SetLineHeightFactor() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetLineHeightFactor(1.4)
+	for _, size := range []float64{9, 12, 16} {
+		pdf.SetFont("Times", "", size)
+		pdf.MultiCell(0, 0, lorem(), "", "J", false)
+		pdf.Ln(pdf.GetLineHeight())
+	}
+	pdf.SetLineHeight(6)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.Write(0, lorem())
+	pdf.Ln(10)
+	html := pdf.HTMLBasicNew()
+	html.Write(0, "HTML text <b>follows</b> the <i>same</i> line height. "+lorem())
+	fileStr := example.Filename("Fpdf_SetLineHeightFactor")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetLineHeightFactor.pdf
+}
+
+// TestLineHeight verifies that the text methods use the line height of the
+// document when given a line height of 0.
+func TestLineHeight(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	if ht := pdf.GetLineHeight(); ht != 0 {
+		t.Fatalf("got default line height %.2f", ht)
+	}
+	pdf.SetLineHeightFactor(1.5)
+	if ht := pdf.GetLineHeight(); ht != 15 {
+		t.Fatalf("got line height %.2f, want 15", ht)
+	}
+	check := func(name string, lines, ht float64, print func()) {
+		y := pdf.GetY()
+		print()
+		if got := pdf.GetY() - y; math.Abs(got-lines*ht) > 1e-6 {
+			t.Errorf("%s: got height %.2f, want %.2f", name, got, lines*ht)
+		}
+	}
+	check("MultiCell", 2, 15, func() { pdf.MultiCell(0, 0, "a\nb", "", "L", false) })
+	check("Write", 1, 15, func() { pdf.Write(0, "a\nb") })
+	pdf.Ln(0)
+	par := pdf.ParagraphNew(0, 0, "L")
+	check("Paragraph", 3, 15, func() { par.Write("a\nb\nc") })
+	pdf.SetLineHeight(20)
+	check("MultiCellSpans", 2, 20, func() {
+		pdf.MultiCellSpans(0, 0, []gofpdf.TextSpan{{Text: "a\n"}, {Text: "b", FontStyle: "B"}}, "", "L", false)
+	})
+	pdf.SetFont("Helvetica", "", 20)
+	if ht := pdf.GetLineHeight(); ht != 20 {
+		t.Fatalf("got line height %.2f, want 20", ht)
+	}
+	// An explicit line height takes precedence
+	check("MultiCell", 2, 8, func() { pdf.MultiCell(0, 8, "a\nb", "", "L", false) })
+}
+
+// ExampleFpdf_HTMLNew demonstrates the rendering of HTML with headings,
+// nested lists, a table, an image, a horizontal rule and styles set with
+// attributes and a style element.
+func ExampleFpdf_HTMLNew() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 12)
+	html := pdf.HTMLNew()
+	html.Write(6, `<style>
+		h1 { color: navy; font-family: sans-serif }
+		.note { background-color: #ffffc0; font-style: italic }
+		th { background: #d0d0ff }
+	</style>
+	<h1>HTML rendering</h1>
+	<p>Text may be <b>bold</b>, <i>italic</i>, <u>underlined</u>,
+	<span style="color: #c00000">colored</span>,
+	<span style="background-color: silver">highlighted</span>,
+	<span style="font-size: 150%">larger</span> or
+	<code>monospaced</code>, and link to
+	<a href="https://github.com/phpdave11/gofpdf">a web page</a>.</p>
+	<p class="note" align="center">A centered note with a background.</p>
+	<ul>
+		<li>Lists may be nested
+			<ol type="a"><li>with numbers<li>or letters</ol>
+		<li>and have <img src="`+example.ImageFile("golang-gopher.png")+`" height="16"> images
+	</ul>
+	<hr width="50%">
+	<table border="1" width="80%">
+		<tr><th width="30%">Element</th><th>Rendering</th></tr>
+		<tr><td>table</td><td>Rows of cells as high as their highest cell, with
+		text wrapped within the width of its column</td></tr>
+		<tr bgcolor="#e0e0e0"><td colspan="2" align="right">A cell may span
+		columns</td></tr>
+	</table>
+	<p style="text-align: justify">`+lorem()+`</p>`)
+	fileStr := example.Filename("Fpdf_HTMLNew")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_HTMLNew.pdf
+}
+
+// TestHTML verifies the layout of HTML blocks, the styles of their text and
+// that the font and text color are restored afterwards.
+func TestHTML(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetTextColor(0, 0, 200)
+	html := pdf.HTMLNew()
+	y := pdf.GetY()
+	html.Write(5, "<div>one</div><div>two<br>three</div>")
+	if lines := (pdf.GetY() - y) / 5; math.Abs(lines-3) > 1e-6 {
+		t.Fatalf("got %.2f lines, want 3", lines)
+	}
+	y = pdf.GetY()
+	html.Write(5, "<table><tr><td>a</td><td>b<br>c<br>d</td></tr><tr><td>e</td></tr></table>")
+	if lines := (pdf.GetY() - y) / 5; math.Abs(lines-4.5) > 1e-6 {
+		t.Fatalf("got %.2f lines of table and space, want 4.5", lines)
+	}
+	html.Write(5, `<style>.x { color: red }</style>
+		<ul><li>item&nbsp;1<ol start="9"><li>nested</ol></ul>
+		<p class="x" style="font-size: 20pt">big red <b>bold</b></p>`)
+	if pt, _ := pdf.GetFontSize(); pt != 12 {
+		t.Fatalf("font size not restored: %.1f", pt)
+	}
+	if r, g, b := pdf.GetTextColor(); r != 0 || g != 0 || b != 200 {
+		t.Fatalf("text color not restored: %d %d %d", r, g, b)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"(\x95)", "(item\xa01)", "(9.)", "(nested)", "1.000 0.000 0.000 rg",
+		"20.00 Tf", "(big red )", "(bold)"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+}
+
+// ExampleFpdf_FlowText demonstrates text that flows from a box on the first
+// page to the two columns of a second page and then to a box that is filled
+// later with the rest of the text.
+func ExampleFpdf_FlowText() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 12)
+	spans := []gofpdf.TextSpan{
+		{Text: "Linked regions. ", FontStyle: "B"},
+		{Text: strings.Repeat(lorem()+" ", 4)},
+	}
+	regions := []gofpdf.TextRegionType{
+		{X: 10, Y: 20, Wd: 120, Ht: 60},
+		{X: 10, Y: 20, Wd: 90, Ht: 250},
+		{X: 110, Y: 20, Wd: 90, Ht: 100},
+	}
+	for _, region := range regions[:1] {
+		pdf.Rect(region.X, region.Y, region.Wd, region.Ht, "D")
+	}
+	rest := pdf.FlowText(regions[0], 5, spans, "J", func(n int) (gofpdf.TextRegionType, bool) {
+		if n >= len(regions) {
+			return gofpdf.TextRegionType{}, false
+		}
+		if n == 1 {
+			pdf.AddPage()
+		}
+		return regions[n], true
+	})
+	// The text left is continued in a box on the first page
+	pdf.SetPage(1)
+	box := gofpdf.TextRegionType{X: 140, Y: 20, Wd: 60, Ht: 250}
+	pdf.Rect(box.X, box.Y, box.Wd, box.Ht, "D")
+	pdf.FlowText(box, 5, rest, "L", nil)
+	fileStr := example.Filename("Fpdf_FlowText")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_FlowText.pdf
+}
+
+// TestFlowText verifies that text flows through regions of different widths
+// without losing or adding words, and that the text that overflows the last
+// region is returned.
+func TestFlowText(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	words := strings.Fields(strings.Repeat(lorem()+" ", 2))
+	spans := []gofpdf.TextSpan{
+		{Text: strings.Join(words[:10], " ") + " "},
+		{Text: strings.Join(words[10:20], " "), FontStyle: "B"},
+		{Text: "\n\n" + strings.Join(words[20:], " ")},
+	}
+	var calls []int
+	regions := []gofpdf.TextRegionType{
+		{X: 10, Y: 10, Wd: 50, Ht: 30},
+		{X: 70, Y: 10, Wd: 80, Ht: 12},
+		{X: 10, Y: 50, Wd: 190, Ht: 20},
+	}
+	rest := pdf.FlowText(regions[0], 6, spans, "L", func(n int) (gofpdf.TextRegionType, bool) {
+		calls = append(calls, n)
+		if n >= len(regions) {
+			return gofpdf.TextRegionType{}, false
+		}
+		return regions[n], true
+	})
+	if len(calls) != 3 || calls[0] != 1 || calls[2] != 3 {
+		t.Fatalf("got calls %v, want [1 2 3]", calls)
+	}
+	if x, y := pdf.GetXY(); x != 10 || y > 70 {
+		t.Fatalf("ends at %.2f, %.2f, outside of the last region", x, y)
+	}
+	if len(rest) == 0 {
+		t.Fatal("no text left")
+	}
+	// The text left, printed in a large region, completes that printed
+	var printed []string
+	pdf.SetFont("Helvetica", "", 4)
+	rest = pdf.FlowText(gofpdf.TextRegionType{X: 10, Y: 80, Wd: 190, Ht: 200}, 2, rest, "L", nil)
+	if rest != nil {
+		t.Fatal("text left in a large region")
+	}
+	pdf.SetCompression(false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range regexp.MustCompile(`Td \((.*)\)Tj`).FindAllStringSubmatch(buf.String(), -1) {
+		printed = append(printed, strings.Fields(m[1])...)
+	}
+	if strings.Join(printed, " ") != strings.Join(words, " ") {
+		t.Fatalf("got text\n%s\nwant\n%s", strings.Join(printed, " "), strings.Join(words, " "))
+	}
+}
+
+// ExampleFpdf_CellFit demonstrates the fitting of names of variable length
+// into the fixed boxes of a form, either by condensing the text or by
+// reducing its font size.
+func ExampleFpdf_CellFit() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	names := []string{"Ann Lee", "Maximilian Alexander von Habsburg-Lothringen",
+		"Marie-Josephine Rosalie Bonaparte-Beauharnais de la Pagerie"}
+	for _, fitStr := range []string{gofpdf.CellFitScale, gofpdf.CellFitShrink} {
+		pdf.CellFormat(0, 8, "Fitted with "+fitStr, "", 1, "L", false, 0, "")
+		for _, name := range names {
+			pdf.CellFormat(30, 8, "Name", "1", 0, "L", true, 0, "")
+			pdf.CellFit(70, 8, name, "1", 1, "L", false, 0, "", fitStr)
+		}
+		pdf.Ln(8)
+	}
+	fileStr := example.Filename("Fpdf_CellFit")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_CellFit.pdf
+}
+
+// TestCellFit verifies the scaling and font size of fitted text and that the
+// current position and font follow the cell.
+func TestCellFit(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetCellMargin(1)
+	w := pdf.GetStringWidth("0123456789")
+	pdf.SetXY(20, 20)
+	pdf.CellFit(w/2+2, 10, "0123456789", "1", 0, "R", false, 0, "https://example.com", gofpdf.CellFitScale)
+	if x, y := pdf.GetXY(); math.Abs(x-(22+w/2)) > 1e-6 || y != 20 {
+		t.Fatalf("scaled cell ends at %.2f, %.2f", x, y)
+	}
+	pdf.CellFit(w/4+2, 10, "0123456789", "1", 2, "", false, 0, "", gofpdf.CellFitShrink)
+	if x, y := pdf.GetXY(); math.Abs(x-(22+w/2)) > 1e-6 || y != 30 {
+		t.Fatalf("shrunk cell ends at %.2f, %.2f", x, y)
+	}
+	if pt, _ := pdf.GetFontSize(); pt != 10 {
+		t.Fatalf("font size not restored: %.2f", pt)
+	}
+	// Text that fits is not fitted
+	pdf.CellFit(2*w, 10, "0123456789", "1", 1, "", false, 0, "", gofpdf.CellFitShrink)
+	pdf.CellFit(10, 10, "a", "", 1, "", false, 0, "", "squeeze")
+	if pdf.Err() == false {
+		t.Fatal("invalid fit mode accepted")
+	}
+	pdf.ClearError()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"0.50000 0.00000 0.00000 1.00000 ", "2.50 Tf", "10.00 Tf"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+}
+
+// ExampleFpdf_TextOnPath demonstrates text set along the arcs of a seal and
+// along a wave made of Bézier curves.
+func ExampleFpdf_TextOnPath() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetDrawColor(160, 0, 0)
+	pdf.SetTextColor(160, 0, 0)
+	pdf.SetLineWidth(1)
+	pdf.Circle(60, 70, 40, "D")
+	pdf.SetLineWidth(.3)
+	pdf.Circle(60, 70, 28, "D")
+	pdf.SetFont("Helvetica", "B", 14)
+	// Text reads upright along the top of the circle from left to right and
+	// along its bottom in the other direction
+	pdf.TextOnPath(gofpdf.ArcPath(60, 70, 32, 32, 180, 0), "OFFICIAL SEAL", "C")
+	pdf.TextOnPath(gofpdf.ArcPath(60, 70, 36.5, 36.5, 225, 315), "APPROVED", "C")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.TextOnPath(gofpdf.ArcPath(60, 70, 20, 20, 180, 0), "* 2024 *", "C")
+	pdf.SetTextColor(0, 0, 128)
+	wave := []gofpdf.SVGBasicSegmentType{
+		{Cmd: 'M', Arg: [6]float64{20, 160}},
+		{Cmd: 'C', Arg: [6]float64{50, 120, 80, 200, 110, 160}},
+		{Cmd: 'c', Arg: [6]float64{30, -40, 60, 40, 90, 0}},
+	}
+	pdf.SetFont("Times", "I", 16)
+	pdf.TextOnPath(wave, "Text may follow any path of lines and curves.", "L")
+	fileStr := example.Filename("Fpdf_TextOnPath")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_TextOnPath.pdf
+}
+
+// TestTextOnPath verifies the rotation and position of characters set along
+// paths and the points of arc paths.
+func TestTextOnPath(t *testing.T) {
+	path := gofpdf.ArcPath(100, 100, 50, 20, 180, 0)
+	if len(path) != 3 || path[0].Cmd != 'M' || path[2].Cmd != 'C' {
+		t.Fatalf("got arc path %v", path)
+	}
+	for j, want := range [][2]float64{{50, 100}, {100, 80}, {150, 100}} {
+		got := path[j].Arg[:2]
+		if j > 0 {
+			got = path[j].Arg[4:]
+		}
+		if math.Abs(got[0]-want[0]) > 1e-9 || math.Abs(got[1]-want[1]) > 1e-9 {
+			t.Fatalf("point %d of arc at %v, want %v", j, got, want)
+		}
+	}
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Courier", "", 10)
+	// Courier characters are 6 points wide: four of them fit on lines of 25
+	// points
+	pdf.TextOnPath([]gofpdf.SVGBasicSegmentType{
+		{Cmd: 'M', Arg: [6]float64{100, 100}},
+		{Cmd: 'v', Arg: [6]float64{25}},
+	}, "abcdef", "")
+	pdf.TextOnPath([]gofpdf.SVGBasicSegmentType{
+		{Cmd: 'M', Arg: [6]float64{200, 100}},
+		{Cmd: 'H', Arg: [6]float64{225}},
+	}, "abcd", "C")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	if n := strings.Count(str, " Tj ET"); n != 8 {
+		t.Fatalf("got %d characters, want 8", n)
+	}
+	_, h := pdf.GetPageSize()
+	for _, want := range []string{
+		"0.00000 -1.00000 1.00000 0.00000 ", // Rotated down the page
+		fmt.Sprintf("BT 100.00 %.2f Td (a) Tj ET", h-100),
+		fmt.Sprintf("BT 100.00 %.2f Td (d) Tj ET", h-118),
+		fmt.Sprintf("BT 200.50 %.2f Td (a) Tj ET", h-100),
+		fmt.Sprintf("BT 218.50 %.2f Td (d) Tj ET", h-100),
+	} {
+		if !strings.Contains(str, want) {
+			t.Errorf("%q missing", want)
+		}
+	}
+}
+
+// ExampleFpdf_SetCharSpacing demonstrates character spacing that tracks out a
+// heading and word spacing that opens up a paragraph, both of which are taken
+// into account when text is measured and broken into lines.
+func ExampleFpdf_SetCharSpacing() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	for _, space := range []float64{0, 1, 2.5} {
+		pdf.SetCharSpacing(space)
+		pdf.CellFormat(0, 12, "TRACKED HEADING", "1", 1, "C", false, 0, "")
+	}
+	pdf.SetCharSpacing(0)
+	pdf.Ln(6)
+	pdf.SetFont("Times", "", 12)
+	for _, space := range []float64{0, 1.5} {
+		pdf.SetWordSpacing(space)
+		pdf.MultiCell(120, 5, lorem(), "1", "L", false)
+		pdf.Ln(4)
+	}
+	pdf.SetFont("dejavu", "", 11)
+	pdf.SetCharSpacing(.3)
+	pdf.SetWordSpacing(1)
+	pdf.Write(5, "Spacing also applies to UTF-8 fonts: Ελληνικά, Русский, Français. ")
+	pdf.Write(5, lorem())
+	fileStr := example.Filename("Fpdf_SetCharSpacing")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetCharSpacing.pdf
+}
+
+// TestCharSpacing verifies that character and word spacing are measured by
+// GetSpacedStringWidth() but not GetStringWidth(), are kept from page to page
+// and narrow the lines of wrapped text so that they fit their width.
+func TestCharSpacing(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetCharSpacing(.5)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	txt := "one two three four five six seven eight nine ten"
+	pdf.SetCharSpacing(0)
+	w := pdf.GetStringWidth(txt)
+	lines := len(pdf.SplitLines([]byte(txt), 40))
+	pdf.SetCharSpacing(.5)
+	pdf.SetWordSpacing(2)
+	if got := pdf.GetStringWidth(txt); got != w {
+		t.Fatalf("width %.3f with spacing, expected %.3f", got, w)
+	}
+	if got, want := pdf.GetSpacedStringWidth(txt), w+float64(len(txt))*.5+9*2; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("spaced width %.3f, expected %.3f", got, want)
+	}
+	if pdf.GetCharSpacing() != .5 || pdf.GetWordSpacing() != 2 {
+		t.Fatalf("spacing not kept")
+	}
+	if n := len(pdf.SplitLines([]byte(txt), 40)); n <= lines {
+		t.Fatalf("%d spaced lines, expected more than %d", n, lines)
+	}
+	wmax := 60 - 2*pdf.GetCellMargin()
+	for _, line := range pdf.BreakLines(txt, 60) {
+		if want := pdf.GetSpacedStringWidth(line.Str); math.Abs(line.Width-want) > 1e-6 {
+			t.Fatalf("line %q %.3f wide, expected %.3f", line.Str, line.Width, want)
+		}
+		if line.Width > wmax {
+			t.Fatalf("line %q %.3f wide, wider than %.3f", line.Str, line.Width, wmax)
+		}
+	}
+	for _, line := range pdf.SplitLines([]byte(txt), 60) {
+		if w := pdf.GetSpacedStringWidth(string(line)); w > wmax {
+			t.Fatalf("split line %q %.3f wide, wider than %.3f", line, w, wmax)
+		}
+	}
+	pdf.MultiCell(60, 5, txt, "", "J", false)
+	pdf.AddPage()
+	pdf.Write(5, txt)
+	if x, right := pdf.GetX(), 210-10-pdf.GetCellMargin(); x > right {
+		t.Fatalf("written text ends at %.3f, past %.3f", x, right)
+	}
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	// The spacing is set again on the second page
+	for want, count := range map[string]int{"1.41732 Tc": 3, "5.66929 Tw": 1, "5.669 Tw": 1} {
+		if n := strings.Count(str, want); n < count {
+			t.Errorf("%q found %d times", want, n)
+		}
+	}
+}
+
+// ExampleFpdf_WriteSuperscript demonstrates footnote markers, exponents and
+// chemical formulas written inline and among spans.
+func ExampleFpdf_WriteSuperscript() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 14)
+	pdf.SetLineHeightFactor(1.4)
+	pdf.Write(0, "Water")
+	pdf.WriteSuperscript(0, "1", 0, "")
+	pdf.Write(0, " is H")
+	pdf.WriteSubscript(0, "2", 0, "")
+	pdf.Write(0, "O and the energy of a body at rest is E = mc")
+	pdf.WriteSuperscript(0, "2", 0, "")
+	pdf.Write(0, ".\n")
+	pdf.Ln(4)
+	pdf.WriteSpans(0, []gofpdf.TextSpan{
+		{Text: "Sulfuric acid, H"}, {Text: "2", Subscript: true},
+		{Text: "SO"}, {Text: "4", Subscript: true},
+		{Text: ", dissociates into sulfate, SO"}, {Text: "4", Subscript: true},
+		{Text: "2-", Superscript: true}, {Text: ", and hydrogen ions, H"},
+		{Text: "+", Superscript: true}, {Text: "."},
+		{Text: "2", Superscript: true, TextColor: &gofpdf.RGBType{R: 0, G: 0, B: 160}, LinkStr: "https://github.com/phpdave11/gofpdf"},
+	})
+	pdf.Ln(-1)
+	pdf.Ln(6)
+	pdf.SetTextRise(-1)
+	pdf.Write(0, "Text with its baseline lowered by one millimeter.\n")
+	pdf.SetTextRise(0)
+	pdf.Ln(10)
+	pdf.SetFont("Times", "", 10)
+	pdf.WriteSuperscript(0, "1", 0, "")
+	pdf.Write(0, " Water is a substance composed of the chemical elements hydrogen and oxygen.\n")
+	pdf.WriteSuperscript(0, "2", 0, "")
+	pdf.Write(0, " The ions are hydrated in water.")
+	fileStr := example.Filename("Fpdf_WriteSuperscript")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_WriteSuperscript.pdf
+}
+
+// TestTextRise verifies the rise and size of superscripts and subscripts and
+// that the text rise is kept from page to page.
+func TestTextRise(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 20)
+	pdf.Write(30, "x")
+	pdf.WriteSuperscript(30, "2", 0, "")
+	pdf.WriteSubscript(30, "i", 0, "")
+	if pt, _ := pdf.GetFontSize(); pt != 20 || pdf.GetTextRise() != 0 {
+		t.Fatalf("font size %.2f and rise %.2f not restored", pt, pdf.GetTextRise())
+	}
+	pdf.Ln(30)
+	pdf.WriteSpans(30, []gofpdf.TextSpan{{Text: "y"}, {Text: "2", Superscript: true}, {Text: "z", TextRise: 3}})
+	if pdf.GetTextRise() != 0 {
+		t.Fatalf("rise %.2f not restored after spans", pdf.GetTextRise())
+	}
+	pdf.SetTextRise(2)
+	pdf.AddPage()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	// The baseline of a 13 point superscript written on a 20 point line is
+	// raised by 7 points, 2.1 of which by centering it on the line, and that
+	// of a subscript lowered by 3 points
+	for want, count := range map[string]int{"4.90000 Ts": 1, "-5.10000 Ts": 1, "7.00000 Ts": 1,
+		"\n3.00000 Ts": 1, "13.00 Tf": 4, "2.00000 Ts": 2} {
+		if n := strings.Count(str, want); n < count {
+			t.Errorf("%q found %d times", want, n)
+		}
+	}
+}
+
+// ExampleFpdf_SetTextRenderingMode_clip demonstrates outlined text stroked
+// in another color than its fill, invisible text over a drawing and text that
+// clips a gradient.
+func ExampleFpdf_SetTextRenderingMode_clip() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 48)
+	pdf.SetTextColor(255, 220, 0)
+	pdf.SetDrawColor(160, 0, 0)
+	pdf.SetLineWidth(.6)
+	pdf.SetTextRenderingMode(gofpdf.TextModeFillStroke)
+	pdf.CellFormat(0, 24, "Outlined", "", 1, "C", false, 0, "")
+	pdf.SetTextRenderingMode(gofpdf.TextModeStroke)
+	pdf.CellFormat(0, 24, "Hollow", "", 1, "C", false, 0, "")
+	// The invisible text can be selected and searched
+	pdf.SetFillColor(220, 220, 220)
+	pdf.Rect(40, 70, 130, 20, "F")
+	pdf.SetTextRenderingMode(gofpdf.TextModeInvisible)
+	pdf.SetFontSize(24)
+	pdf.Text(50, 84, "Hidden text")
+	// The gradient shows through the text
+	pdf.SetFontSize(72)
+	pdf.TransformBegin()
+	pdf.SetTextRenderingMode(gofpdf.TextModeClip)
+	pdf.Text(30, 130, "Gradient")
+	pdf.LinearGradient(30, 100, 150, 40, 0, 60, 200, 220, 0, 120, 0, 0, 1, 0)
+	pdf.TransformEnd()
+	pdf.SetTextRenderingMode(gofpdf.TextModeFill)
+	fileStr := example.Filename("Fpdf_SetTextRenderingMode_clip")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetTextRenderingMode_clip.pdf
+}
+
+// TestTextRenderingMode verifies that the text rendering mode is kept from
+// page to page, that it disables synthetic bold text and that text in clipping
+// modes is not enclosed in a saved graphics state.
+func TestTextRenderingMode(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetSyntheticStyle(true)
+	pdf.SetTextRenderingMode(gofpdf.TextModeInvisible)
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "B", 12)
+	pdf.Text(10, 10, "invisible")
+	pdf.SetTextRenderingMode(9)
+	if mode := pdf.GetTextRenderingMode(); mode != gofpdf.TextModeInvisible {
+		t.Fatalf("mode %d, expected %d", mode, gofpdf.TextModeInvisible)
+	}
+	pdf.AddPage()
+	pdf.SetTextColor(200, 0, 0)
+	pdf.SetTextRenderingMode(gofpdf.TextModeFillClip)
+	pdf.CellFormat(40, 10, "clip", "", 1, "", false, 0, "")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	if n := strings.Count(str, "\n3 Tr\n"); n != 2 {
+		t.Errorf("mode set %d times, expected 2", n)
+	}
+	if strings.Contains(str, "2 Tr") {
+		t.Error("invisible text emboldened")
+	}
+	if !regexp.MustCompile(`\n0\.784 0\.000 0\.000 rg BT [^\n]*ET 0\.000 g\n`).MatchString(str) {
+		t.Error("clipping text enclosed in saved graphics state")
+	}
+}
+
+// ExampleFpdf_SetMarkup demonstrates inline markup in the text of cells and
+// of flowing text.
+func ExampleFpdf_SetMarkup() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetMarkup(true)
+	pdf.CellFormat(60, 10, "Item", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 10, "Status", "1", 1, "L", false, 0, "")
+	rows := [][]string{
+		{"Invoice <b>#1042</b>", "<color #080>paid</color>"},
+		{"Invoice <b>#1043</b>", "<color red><b>overdue</b></color> since <i>March 3</i>"},
+		{"Invoice <b>#1044</b>", "<bg yellow>disputed</bg>, see <a https://github.com/phpdave11/gofpdf>details</a>"},
+	}
+	for _, row := range rows {
+		pdf.CellFormat(60, 10, row[0], "1", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 10, row[1], "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(6)
+	pdf.MultiCell(0, 6, "Markup also applies to <b>MultiCell()</b>, whose text is <i>broken into lines</i> "+
+		"and justified as usual, with <u>underlined</u>, <s>struck out</s> and <size 16>larger</size> text, "+
+		"text in <font times>another family</font>, formulas such as H<sub>2</sub>O and x<sup>2</sup> and "+
+		"the literal characters &lt;, &gt; and &amp;.", "1", "J", false)
+	pdf.Ln(6)
+	pdf.Write(6, "And to <b>Write()</b>, as in this <color #36c>sentence</color>. A < b and c > d are printed as is.")
+	fileStr := example.Filename("Fpdf_SetMarkup")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetMarkup.pdf
+}
+
+// TestMarkup verifies the styles, the current position and the errors of
+// markup text.
+func TestMarkup(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(40, 10, "<b>plain</b>", "", 0, "", false, 0, "")
+	pdf.SetMarkup(true)
+	pdf.SetXY(10, 20)
+	pdf.CellFormat(40, 10, "a <b>bold</b> &lt;b&gt;", "1", 0, "", false, 0, "")
+	if x, y := pdf.GetXY(); x != 50 || y != 20 {
+		t.Fatalf("cell ends at %.2f, %.2f", x, y)
+	}
+	pdf.CellFormat(40, 10, "<i>x</i>", "", 2, "", false, 0, "")
+	if x, y := pdf.GetXY(); x != 50 || y != 30 {
+		t.Fatalf("cell below ends at %.2f, %.2f", x, y)
+	}
+	pdf.MultiCell(40, 5, "<size 14>big</size> <u>text</u>", "", "", false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	for _, want := range []string{"(<b>plain</b>)Tj", "(bold)Tj", "( <b>)Tj", "14.00 Tf"} {
+		if !strings.Contains(str, want) {
+			t.Errorf("%q missing", want)
+		}
+	}
+	for _, txtStr := range []string{"a</b>", "<color nocolor>a</color>", "<size -1>a"} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.SetMarkup(true)
+		pdf.Write(5, txtStr)
+		if !pdf.Err() {
+			t.Errorf("%q accepted", txtStr)
+		}
+	}
+}
+
+// ExampleFpdf_ListNew demonstrates nested bulleted and numbered lists that
+// continue across a page break.
+func ExampleFpdf_ListNew() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 12)
+	list := pdf.ListNew(0, 6, "L")
+	list.Begin("1.")
+	for j := 0; j < 20; j++ {
+		list.Item(fmt.Sprintf("Step %d of the procedure. %s", j+1, lorem()[:200+40*(j%4)]))
+		if j%4 == 1 {
+			list.Begin("a)")
+			list.Item("A first point of detail about this step.")
+			list.Begin("")
+			list.Item("A bullet nested two levels deep.")
+			list.Item("Another bullet, with a longer text that wraps and shows the hanging indent of the items.")
+			list.End()
+			list.Item("A second point of detail.")
+			list.End()
+		}
+	}
+	list.End()
+	pdf.Ln(4)
+	list.Begin("(i)")
+	list.ItemSpans([]gofpdf.TextSpan{{Text: "Items can be "}, {Text: "styled", FontStyle: "B"}, {Text: " with spans."}})
+	list.Item("Roman numerals are supported.")
+	list.End()
+	fileStr := example.Filename("Fpdf_ListNew")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ListNew.pdf
+}
+
+// TestList verifies the markers of list items and that an item that does not
+// fit on a page is moved to the next page with its marker.
+func TestList(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	list := pdf.ListNew(10, 5, "")
+	list.Begin("A.")
+	list.SetNumber(27)
+	list.Item("first")
+	list.Begin("(i)")
+	list.SetNumber(4)
+	list.Item("nested")
+	if left, _, _, _ := pdf.GetMargins(); pdf.GetX() != left {
+		t.Fatalf("item ends at x %.2f", pdf.GetX())
+	}
+	list.End()
+	_, pageHt := pdf.GetPageSize()
+	pdf.SetY(pageHt - 20)
+	list.Item("last")
+	if pdf.PageNo() != 2 {
+		t.Fatalf("item printed on page %d", pdf.PageNo())
+	}
+	list.End()
+	list.End()
+	if !pdf.Err() {
+		t.Fatal("list level ended twice")
+	}
+	pdf.ClearError()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pages := strings.SplitN(buf.String(), "endstream", 2)
+	for _, want := range []string{"(AA.)Tj", "(\\(iv\\))Tj", "(first)Tj", "(nested)Tj"} {
+		if !strings.Contains(pages[0], want) {
+			t.Errorf("%q missing", want)
+		}
+	}
+	if strings.Contains(pages[0], "(AB.)Tj") || !strings.Contains(pages[1], "(AB.)Tj") {
+		t.Error("marker not moved with its item")
+	}
+}
+
+// ExampleFpdf_BreakLines demonstrates speech balloons sized to the lines in
+// which their text is broken.
+func ExampleFpdf_BreakLines() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.SetFillColor(235, 240, 255)
+	lineHt := 5.0
+	balloon := func(x, y, maxW float64, txtStr string) {
+		lines := pdf.BreakLines(txtStr, maxW)
+		w := 0.0
+		for _, line := range lines {
+			w = math.Max(w, line.Width)
+		}
+		w += 2 * pdf.GetCellMargin()
+		h := float64(len(lines)) * lineHt
+		pdf.RoundedRect(x, y, w+4, h+4, 3, "1234", "DF")
+		pdf.Polygon([]gofpdf.PointType{{X: x + 6, Y: y + h + 4}, {X: x + 4, Y: y + h + 10},
+			{X: x + 12, Y: y + h + 4}}, "F")
+		for j, line := range lines {
+			pdf.SetXY(x+2, y+2+float64(j)*lineHt)
+			pdf.CellFormat(w, lineHt, line.Str, "", 0, "C", false, 0, "")
+		}
+	}
+	balloon(20, 20, 60, "Hello!")
+	balloon(20, 50, 60, "Each balloon is as wide as its longest line, which BreakLines() measures.")
+	balloon(100, 50, 80, "Lines are broken exactly as MultiCell() breaks them.\nNewlines force breaks.")
+	fileStr := example.Filename("Fpdf_BreakLines")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_BreakLines.pdf
+}
+
+// TestBreakLines verifies that text is broken into lines as MultiCell()
+// breaks it, and the widths and ends of the lines.
+func TestBreakLines(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	hyph, err := gofpdf.NewHyphenator(strings.NewReader("hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetHyphenator(hyph)
+	txtStr := "A line of text with hyphenation\nand a newline"
+	lines := pdf.BreakLines(txtStr, 50)
+	var strs []string
+	for _, line := range lines {
+		strs = append(strs, line.Str)
+		if line.Width > 50-2*pdf.GetCellMargin() || line.Width != pdf.GetStringWidth(line.Str) {
+			t.Errorf("line %q of width %.2f", line.Str, line.Width)
+		}
+	}
+	if got, want := strings.Join(strs, "|"), "A line of text with hy-|phenation|and a newline"; got != want {
+		t.Fatalf("lines %q, expected %q", got, want)
+	}
+	if lines[0].Forced || !lines[0].Hyphenated || !lines[1].Forced || !lines[2].Forced {
+		t.Fatalf("line ends %+v", lines)
+	}
+	pdf.MultiCell(50, 5, txtStr, "", "L", false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range strs {
+		if !strings.Contains(buf.String(), "("+str+")Tj") {
+			t.Errorf("line %q not printed by MultiCell()", str)
+		}
+	}
+}
+
+// ExampleFpdf_MultiCellMaxLines demonstrates a table with fixed columns whose
+// names and descriptions are truncated with an ellipsis.
+func ExampleFpdf_MultiCellMaxLines() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 11)
+	rows := [][]string{
+		{"Widget", "A small widget."},
+		{"Industrial-strength widget assembly kit",
+			"Everything needed to assemble widgets of all sizes, including brackets, " +
+				"fasteners, a torque wrench and an illustrated manual in twelve languages."},
+		{"Gadget", "A gadget that does whatever gadgets do, in every color that " +
+			"anyone has ever asked for and in several that nobody has."},
+	}
+	for _, row := range rows {
+		x, y := pdf.GetXY()
+		pdf.CellFit(50, 18, row[0], "1", 0, "LT", false, 0, "", gofpdf.CellFitEllipsis)
+		pdf.MultiCellMaxLines(120, 6, row[1], "1", "J", false, 3)
+		pdf.SetXY(x, y+18)
+	}
+	pdf.Ln(6)
+	pdf.SetEllipsis("...")
+	pdf.CellFit(50, 8, "Truncated with three periods", "1", 1, "R", false, 0, "", gofpdf.CellFitEllipsis)
+	fileStr := example.Filename("Fpdf_MultiCellMaxLines")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_MultiCellMaxLines.pdf
+}
+
+// TestEllipsis verifies the truncation of cells and of multi-line cells.
+func TestEllipsis(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	w := pdf.GetStringWidth("0123456789")
+	pdf.SetCellMargin(1)
+	pdf.CellFit(w/2+2, 10, "0123456789", "", 1, "", false, 0, "", gofpdf.CellFitEllipsis)
+	pdf.CellFit(w+2, 10, "9876543210", "", 1, "", false, 0, "", gofpdf.CellFitEllipsis)
+	pdf.SetEllipsis("~")
+	pdf.CellFit(pdf.GetStringWidth("ab ~")+2, 10, "ab cdefgh", "", 1, "", false, 0, "", gofpdf.CellFitEllipsis)
+	pdf.SetEllipsis("")
+	txtStr := "one two three four five six seven eight nine ten"
+	lineW := pdf.GetStringWidth("one two three")
+	pdf.MultiCellMaxLines(lineW+2, 5, txtStr, "", "L", false, 2)
+	pdf.MultiCellMaxLines(lineW+2, 5, "one two", "", "L", false, 2)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"(012\x85)Tj", "(9876543210)Tj", "(ab~)Tj",
+		"(one two three)Tj", "(four five six\x85)Tj", "(one two)Tj"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	if strings.Contains(buf.String(), "seven") {
+		t.Error("truncated text printed")
+	}
+}
+
+// ExampleTextSpan_fillPadding demonstrates highlighted words whose
+// backgrounds extend beyond their text, including a highlight that wraps from
+// one line to the next.
+func ExampleTextSpan_fillPadding() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	yellow := &gofpdf.RGBType{R: 255, G: 240, B: 120}
+	green := &gofpdf.RGBType{R: 180, G: 240, B: 180}
+	spans := []gofpdf.TextSpan{
+		{Text: "Words can be "},
+		{Text: "highlighted", FillColor: yellow, FillPadding: 0.8},
+		{Text: " with a background that extends beyond the text, as a marker pen " +
+			"would draw it. A highlight that is "},
+		{Text: "long enough to be wrapped from one line to the next", FillColor: green, FillPadding: 0.8},
+		{Text: " is drawn on each of its lines. Backgrounds without padding, such as "},
+		{Text: "this one", FillColor: yellow},
+		{Text: ", fill the part of the line that their text takes. " + lorem()},
+	}
+	pdf.MultiCellSpans(100, 7, spans, "", "J", false)
+	pdf.Ln(7)
+	pdf.WriteSpans(7, spans[:4])
+	fileStr := example.Filename("TextSpan_fillPadding")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/TextSpan_fillPadding.pdf
+}
+
+// TestTextSpanFillPadding verifies the size of the padded background of a span
+// and that it is painted before the text on each of its lines.
+func TestTextSpanFillPadding(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	w := pdf.GetStringWidth("marked")
+	pdf.SetXY(50, 100)
+	pdf.MultiCellSpans(w+2*pdf.GetCellMargin(), 12, []gofpdf.TextSpan{
+		{Text: "marked marked", FillColor: &gofpdf.RGBType{R: 255}, FillPadding: 2},
+	}, "", "L", false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	rect := fmt.Sprintf("%.2f -14.00 re f", w+4)
+	if n := strings.Count(s, rect); n != 2 {
+		t.Fatalf("%d backgrounds %q, expected 2", n, rect)
+	}
+	if strings.Index(s, rect) > strings.Index(s, "(marked)Tj") {
+		t.Fatal("background painted after text")
+	}
+}
+
+// ExampleParagraphType_SetIndent demonstrates book-style paragraphs with a
+// first-line indent and a bibliography whose entries have hanging indents and
+// are set apart by the space after each of them.
+func ExampleParagraphType_SetIndent() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 12)
+	par := pdf.ParagraphNew(120, 5, "J")
+	par.SetIndent(8, 0)
+	for j := 0; j < 3; j++ {
+		par.Write(lorem())
+	}
+	pdf.SetFont("Times", "B", 14)
+	heading := pdf.ParagraphNew(120, 6, "L")
+	heading.SetSpacing(8, 3)
+	heading.Write("References")
+	pdf.SetFont("Times", "", 11)
+	refs := pdf.ParagraphNew(120, 5, "L")
+	refs.SetIndent(0, 10)
+	refs.SetSpacing(0, 2)
+	for _, ref := range []string{
+		"Knuth, D. E., and Plass, M. F. Breaking paragraphs into lines. Software: Practice " +
+			"and Experience 11, 11 (1981), 1119-1184.",
+		"Liang, F. M. Word Hy-phen-a-tion by Com-put-er. PhD thesis, Stanford University, 1983.",
+		"Bringhurst, R. The Elements of Typographic Style. Hartley & Marks, Vancouver, 1992.",
+	} {
+		refs.Write(ref)
+	}
+	fileStr := example.Filename("ParagraphType_SetIndent")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/ParagraphType_SetIndent.pdf
+}
+
+// TestParagraphIndent verifies the widths and positions of indented lines and
+// that the spaces around paragraphs are part of their height.
+func TestParagraphIndent(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	txtStr := "aaa bbb ccc ddd eee fff ggg hhh iii jjj kkk lll mmm nnn ooo ppp"
+	wordW := pdf.GetStringWidth("aaa bbb ccc")
+	w := wordW + 2*pdf.GetCellMargin() + 0.1
+	par := pdf.ParagraphNew(w+15, 5, "L")
+	par.SetIndent(15, 5)
+	par.SetSpacing(3, 4)
+	lines := par.Lines(txtStr)
+	if got := lines[0]; got != "aaa bbb ccc" {
+		t.Fatalf("first line %q", got)
+	}
+	for _, line := range lines[1:] {
+		if pdf.GetStringWidth(line) > wordW+10+1e-9 {
+			t.Fatalf("line %q too wide", line)
+		}
+	}
+	if len(lines[1]) <= len(lines[0]) {
+		t.Fatalf("lines %q not wider after the first", lines)
+	}
+	pdf.SetXY(20, 20)
+	ht := par.Write(txtStr)
+	if want := 7 + 5*float64(len(lines)); ht != want || ht != par.Height(txtStr) ||
+		math.Abs(pdf.GetY()-20-ht) > 1e-9 {
+		t.Fatalf("paragraph height %.2f, expected %.2f", ht, want)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// The first line begins at 20 + 15 mm and the next one at 20 + 5 mm,
+	// plus the cell margin
+	k := 72 / 25.4
+	for _, x := range []float64{35, 25} {
+		if str := fmt.Sprintf("BT %.2f ", (x+pdf.GetCellMargin())*k); !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+}
+
+// ExampleFpdf_SetLetterJustification demonstrates narrow justified columns
+// with and without letter justification.
+func ExampleFpdf_SetLetterJustification() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 11)
+	txtStr := "Justification by word spacing alone leaves wide gaps in narrow columns, " +
+		"and cannot justify a line such as https://github.com/phpdave11/gofpdf/blob/master/README.md " +
+		"at all. " + lorem()
+	for j, enabled := range []bool{false, true} {
+		pdf.SetLetterJustification(enabled, 1)
+		pdf.SetXY(15+float64(j)*70, 20)
+		pdf.MultiCell(55, 5, txtStr, "1", "J", false)
+	}
+	fileStr := example.Filename("Fpdf_SetLetterJustification")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetLetterJustification.pdf
+}
+
+// TestLetterJustification verifies the word and character spacing of lines
+// justified with letter justification and that the character spacing is
+// restored after each line.
+func TestLetterJustification(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetCellMargin(0)
+	pdf.SetLetterJustification(true, 1)
+	if enabled, space := pdf.GetLetterJustification(); !enabled || space != 1 {
+		t.Fatalf("letter justification %v, %.2f", enabled, space)
+	}
+	// The first line is stretched by 1 point at its space and by the rest of
+	// its width between its 3 characters, and the second line, of 20 x
+	// 5 points wide, between its 20 characters
+	pdf.MultiCell(102, 12, "a b "+strings.Repeat("x", 40), "", "J", false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	cs := (102 - pdf.GetStringWidth("a b") - 1) / 2
+	for _, str := range []string{"1.000 Tw", fmt.Sprintf("%.5f Tc", cs), fmt.Sprintf("%.5f Tc", 2.0/19),
+		"(a b)Tj ET 0.00000 Tc"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	if n := strings.Count(buf.String(), " Tc"); n != 4 {
+		t.Errorf("%d character spacing operators, expected 4", n)
+	}
+}
+
+// ExampleFpdf_MultiCell_lineBreaking demonstrates the break opportunities of
+// the Unicode line breaking algorithm: after the slashes of a URL, around em
+// dashes and after hyphens, but not at non-breaking spaces or before
+// punctuation.
+func ExampleFpdf_MultiCell_lineBreaking() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 11)
+	txtStr := "The source code of the library—its fonts, examples and tests—is at " +
+		"https://github.com/phpdave11/gofpdf/tree/master/contrib (see the README.md file). " +
+		"A distance such as 100 km or a well-known date such as 14 July stays on one line!"
+	for j, w := range []float64{30, 45, 60} {
+		pdf.SetXY(15+float64(j)*60, 20)
+		pdf.MultiCell(w, 5.5, txtStr, "1", "L", false)
+	}
+	fileStr := example.Filename("Fpdf_MultiCell_lineBreaking")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_MultiCell_lineBreaking.pdf
+}
+
+// TestLineBreaking verifies that MultiCell() breaks lines of Japanese text,
+// URLs, dashes and non-breaking spaces at the break opportunities of the
+// Unicode line breaking algorithm.
+func TestLineBreaking(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 10)
+	for _, test := range []struct {
+		txtStr string
+		w      float64
+		want   string
+	}{
+		// Punctuation and small kana do not begin lines
+		{"日本語のテキストです。改行は、句読点の前ではしません。ちょっと待ってください。", 30,
+			"日本語のテキストです。改行|は、句読点の前ではしません。|ちょっと待ってください。"},
+		{"well-known 3.14 -5 $100 50% a—b—c", 14, "well-|known|3.14 -5|$100|50% a|—b—c"},
+		{"see https://github.com/phpdave11/gofpdf now (really)!", 30,
+			"see https://|github.com/|phpdave11/|gofpdf now|(really)!"},
+	} {
+		var strs []string
+		for _, line := range pdf.BreakLines(test.txtStr, test.w) {
+			strs = append(strs, line.Str)
+		}
+		if got := strings.Join(strs, "|"); got != test.want {
+			t.Errorf("lines %q, expected %q", got, test.want)
+		}
+	}
+	// Single-byte text, with a non-breaking space and an em dash in cp1252
+	pdf.SetFont("Helvetica", "", 10)
+	var strs []string
+	for _, line := range pdf.BreakLines("Price: 100\xa0km \x97 or less", 25) {
+		strs = append(strs, line.Str)
+	}
+	if got, want := strings.Join(strs, "|"), "Price: 100\xa0km|\x97 or less"; got != want {
+		t.Errorf("lines %q, expected %q", got, want)
+	}
+}
+
+// ExampleFpdf_MultiCell_graphemeClusters demonstrates that letters with
+// combining accents are kept whole when words wider than a cell are broken
+// between characters.
+func ExampleFpdf_MultiCell_graphemeClusters() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 14)
+	// Vietnamese with decomposed accents: each letter is followed by one or
+	// two combining marks
+	txtStr := "Tie\u0302\u0301ng Vie\u0323\u0302t co\u0301 du\u031b\u0301\u0323c da\u0300i " +
+		"kho\u0302ngnga\u0306\u0301tdo\u0300ngdu\u031bo\u031b\u0300nghie\u0302\u0300u"
+	pdf.MultiCell(30, 7, txtStr, "1", "L", false)
+	pdf.Ln(5)
+	pdf.Write(7, txtStr)
+	fileStr := example.Filename("Fpdf_MultiCell_graphemeClusters")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_MultiCell_graphemeClusters.pdf
+}
+
+// TestGraphemeClusters verifies that lines are not broken within grapheme
+// clusters and that invisible characters add no width.
+func TestGraphemeClusters(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 10)
+	if w := pdf.GetStringWidth("a\u200d\ufe0f"); w != pdf.GetStringWidth("a") {
+		t.Errorf("joiner and variation selector measured %.3f wide", w-pdf.GetStringWidth("a"))
+	}
+	for _, test := range []struct {
+		txtStr, want string
+	}{
+		{strings.Repeat("e\u0301", 10), "e\u0301e\u0301e\u0301|e\u0301e\u0301e\u0301|e\u0301e\u0301e\u0301|e\u0301"},
+		// Hangul syllables of jamo
+		{"\u1112\u1161\u11ab\u1100\u1173\u11af\u1112\u1161\u11ab",
+			"\u1112\u1161\u11ab|\u1100\u1173\u11af|\u1112\u1161\u11ab"},
+		// Flags of pairs of regional indicators
+		{"\U0001F1EB\U0001F1F7\U0001F1E9\U0001F1EA\U0001F1EE\U0001F1F9",
+			"\U0001F1EB\U0001F1F7|\U0001F1E9\U0001F1EA|\U0001F1EE\U0001F1F9"},
+		// Emoji sequences with zero width joiners
+		{strings.Repeat("\U0001F468\u200d\U0001F469\u200d\U0001F467", 2),
+			"\U0001F468\u200d\U0001F469\u200d\U0001F467|\U0001F468\u200d\U0001F469\u200d\U0001F467"},
+	} {
+		var strs []string
+		for _, line := range pdf.BreakLines(test.txtStr, 8) {
+			strs = append(strs, line.Str)
+		}
+		if got := strings.Join(strs, "|"); got != test.want {
+			t.Errorf("lines %+q, expected %+q", got, test.want)
+		}
+		if got := strings.Join(pdf.SplitText(test.txtStr, 8), ""); got != test.txtStr {
+			t.Errorf("split text %+q", got)
+		}
+	}
+	if got := pdf.SplitText(strings.Repeat("e\u0301", 4), 8); len(got) != 2 || got[1] != "e\u0301" {
+		t.Errorf("split text %+q", got)
+	}
+}
+
+// ExampleParagraphType_SetDropCap demonstrates paragraphs that begin with a
+// drop cap spanning three lines, the text of which wraps around it.
+func ExampleParagraphType_SetDropCap() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 12)
+	par := pdf.ParagraphNew(120, 5, "J")
+	par.SetDropCap(3)
+	par.SetSpacing(0, 4)
+	for j := 0; j < 3; j++ {
+		par.Write(lorem())
+	}
+	par.Write("Short.")
+	fileStr := example.Filename("ParagraphType_SetDropCap")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/ParagraphType_SetDropCap.pdf
+}
+
+// TestParagraphDropCap verifies that the lines beside a drop cap are indented
+// by its width, that the drop cap is printed large at the left of the
+// paragraph and that a short paragraph is as high as its drop cap.
+func TestParagraphDropCap(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	txtStr := "  Once upon a time there were four little rabbits, and their names were " +
+		"Flopsy, Mopsy, Cotton-tail, and Peter."
+	par := pdf.ParagraphNew(60, 5, "L")
+	plain := par.Lines(txtStr)
+	par.SetDropCap(3)
+	lines := par.Lines(txtStr)
+	if !strings.HasPrefix(lines[0], "nce") {
+		t.Fatalf("first line %q", lines[0])
+	}
+	if len(lines) <= len(plain) {
+		t.Fatalf("lines %q not narrower than %q", lines, plain)
+	}
+	for j, line := range lines[:3] {
+		if len(line) >= len(lines[3]) && j < 2 {
+			t.Errorf("line %q beside the drop cap not narrower than %q", line, lines[3])
+		}
+	}
+	pdf.SetXY(20, 20)
+	ht := par.Write(txtStr)
+	if want := 5 * float64(len(lines)); ht != want || ht != par.Height(txtStr) {
+		t.Fatalf("paragraph height %.2f, expected %.2f", ht, want)
+	}
+	pdf.SetXY(20, 100)
+	if ht = par.Write("Hi"); ht != 15 || par.Height("Hi") != 15 || pdf.GetY() != 115 {
+		t.Fatalf("short paragraph height %.2f, expected 15", ht)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// The drop cap is set so that its cap height spans two lines and the cap
+	// height of the first one
+	size := 2*5/0.718*72/25.4 + 12
+	for _, str := range []string{fmt.Sprintf(" %.2f Tf", size), "(O) Tj", "(H) Tj", "(i)Tj"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+}
+
+// ExampleFpdf_TableNew demonstrates a table with a header row that is
+// repeated on each page, banded rows, wrapped and aligned cells and a cell
+// that spans columns.
+func ExampleFpdf_TableNew() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	tbl := pdf.TableNew(5,
+		gofpdf.TableColumnType{Width: 12, AlignStr: "RM"},
+		gofpdf.TableColumnType{Width: 40, AlignStr: "M"},
+		gofpdf.TableColumnType{AlignStr: "J"},
+		gofpdf.TableColumnType{Width: 25, AlignStr: "CM", FillColor: &gofpdf.RGBType{R: 255, G: 250, B: 220}})
+	tbl.SetPadding(2, 1.5)
+	tbl.SetBorder("1")
+	tbl.SetRowFillColors(gofpdf.RGBType{R: 255, G: 255, B: 255}, gofpdf.RGBType{R: 235, G: 240, B: 250})
+	pdf.SetDrawColor(160, 160, 160)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.SetTextColor(255, 255, 255)
+	blue := &gofpdf.RGBType{R: 40, G: 70, B: 130}
+	tbl.HeaderCells(gofpdf.TableCellType{Text: "#", FillColor: blue, AlignStr: "R"},
+		gofpdf.TableCellType{Text: "Name", FillColor: blue},
+		gofpdf.TableCellType{Text: "Description", FillColor: blue},
+		gofpdf.TableCellType{Text: "Status", FillColor: blue, AlignStr: "C"})
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(0, 0, 0)
+	words := strings.Fields(lorem())
+	for j := 1; j <= 30; j++ {
+		status := "Open"
+		if j%3 == 0 {
+			status = "Closed"
+		}
+		tbl.Row(strconv.Itoa(j), strings.Join(words[j%7:j%7+2], " "),
+			strings.Join(words[:2+j*7%(len(words)-2)], " "), status)
+		if j == 10 {
+			tbl.RowCells(gofpdf.TableCellType{ColSpan: 4, AlignStr: "C", Spans: []gofpdf.TextSpan{
+				{Text: "Milestone: ", FontStyle: "B"}, {Text: "the first ten items are reviewed."}}})
+		}
+	}
+	fileStr := example.Filename("Fpdf_TableNew")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_TableNew.pdf
+}
+
+// TestTable verifies the heights of table rows, the repetition of header
+// rows after page breaks and the widths of columns.
+func TestTable(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 10)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 12)
+	tbl := pdf.TableNew(5, gofpdf.TableColumnType{Width: 30}, gofpdf.TableColumnType{},
+		gofpdf.TableColumnType{})
+	tbl.SetPadding(1, 2)
+	tbl.Header("Key", "Value", "Note")
+	if y := pdf.GetY(); y != 19 {
+		t.Fatalf("header row ends at %.2f, expected 19", y)
+	}
+	pdf.SetFont("Helvetica", "", 12)
+	txtStr := "one two three four five six seven eight nine ten eleven twelve"
+	lines := len(pdf.BreakLines(txtStr, 30))
+	if lines < 2 {
+		t.Fatalf("text broken in %d lines", lines)
+	}
+	y := pdf.GetY()
+	tbl.Row(txtStr, "short")
+	if want := y + 5*float64(lines) + 4; math.Abs(pdf.GetY()-want) > 1e-9 {
+		t.Fatalf("row ends at %.2f, expected %.2f", pdf.GetY(), want)
+	}
+	for pdf.PageNo() == 1 {
+		tbl.Row("row", "", "third")
+	}
+	// The header row heads the second page, followed by the row moved to it
+	if y := pdf.GetY(); y != 10+9+9 {
+		t.Fatalf("first row of the second page ends at %.2f", y)
+	}
+	tbl.RowCells(gofpdf.TableCellType{Text: "too", ColSpan: 2}, gofpdf.TableCellType{Text: "many"},
+		gofpdf.TableCellType{Text: "cells"})
+	if pdf.Error() == nil {
+		t.Fatal("row with too many cells accepted")
+	}
+	pdf.ClearError()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "(Key)Tj"); n != 2 {
+		t.Fatalf("header row printed %d times, expected 2", n)
+	}
+	// The columns of width 0 share the rest of the width, 190 - 30 mm, and
+	// the third one begins at 10 + 30 + 80 mm, plus the padding
+	if str := fmt.Sprintf("BT %.2f ", (10+30+80+1)*72/25.4); !strings.Contains(buf.String(), str) {
+		t.Fatalf("%q missing", str)
+	}
+}
+
+// ExampleTableType_FitColumns demonstrates tables whose columns are sized to
+// fit their content, one of them with a percentage width and another one of
+// a fixed total width.
+func ExampleTableType_FitColumns() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	rows := [][]string{
+		{"Go", "2009", "Statically typed, compiled language designed at Google"},
+		{"Python", "1991", "Dynamically typed language with an emphasis on readability"},
+		{"C", "1972", "General-purpose systems programming language"},
+	}
+	for _, w := range []float64{0, 150} {
+		tbl := pdf.TableNew(5, gofpdf.TableColumnType{}, gofpdf.TableColumnType{AlignStr: "R"},
+			gofpdf.TableColumnType{})
+		tbl.SetWidth(w)
+		tbl.SetBorder("1")
+		tbl.FitColumns(rows...)
+		for _, row := range rows {
+			tbl.Row(row...)
+		}
+		pdf.Ln(8)
+	}
+	tbl := pdf.TableNew(5, gofpdf.TableColumnType{Percent: 25}, gofpdf.TableColumnType{},
+		gofpdf.TableColumnType{})
+	tbl.SetBorder("1")
+	tbl.FitColumns(rows...)
+	for _, row := range rows {
+		tbl.Row(row...)
+	}
+	fileStr := example.Filename("TableType_FitColumns")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/TableType_FitColumns.pdf
+}
+
+// TestTableFitColumns verifies the widths of columns sized to fit their
+// content.
+func TestTableFitColumns(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pad := pdf.GetCellMargin()
+	long := strings.Repeat("word ", 40)
+	newTable := func(cols ...gofpdf.TableColumnType) gofpdf.TableType {
+		tbl := pdf.TableNew(5, cols...)
+		tbl.FitColumns([]string{"a", "bb"}, []string{"ccc", long})
+		return tbl
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	// Columns whose content fits get their widest line
+	tbl := newTable(gofpdf.TableColumnType{}, gofpdf.TableColumnType{Width: 20})
+	w := tbl.ColumnWidths()
+	if !near(w[0], pdf.GetStringWidth("ccc")+2*pad) || w[1] != 20 {
+		t.Fatalf("widths %v", w)
+	}
+	if n := len(pdf.BreakLines("ccc", w[0])); n != 1 {
+		t.Fatalf("widest line broken in %d lines", n)
+	}
+	// A column whose content does not fit takes the width the others leave
+	tbl = newTable(gofpdf.TableColumnType{}, gofpdf.TableColumnType{})
+	w = tbl.ColumnWidths()
+	pageW, _ := pdf.GetPageSize()
+	if !near(w[0]+w[1], pageW-20) || !near(w[0], pdf.GetStringWidth("ccc")+2*pad) {
+		t.Fatalf("widths %v", w)
+	}
+	// A table of a fixed width shares the extra width in proportion to the
+	// widths of the columns
+	tbl = newTable(gofpdf.TableColumnType{}, gofpdf.TableColumnType{Percent: 50})
+	tbl.SetWidth(100)
+	if w := tbl.ColumnWidths(); !near(w[0], 50) || !near(w[1], 50) {
+		t.Fatalf("widths %v", w)
+	}
+	tbl = pdf.TableNew(5, gofpdf.TableColumnType{}, gofpdf.TableColumnType{})
+	tbl.SetWidth(100)
+	tbl.FitColumns([]string{"aaaa", "bbbbbbbbbbbb"})
+	a, b := pdf.GetStringWidth("aaaa")+2*pad, pdf.GetStringWidth("bbbbbbbbbbbb")+2*pad
+	if w := tbl.ColumnWidths(); !near(w[0], 100*a/(a+b)) || !near(w[1], 100*b/(a+b)) {
+		t.Fatalf("widths %v", w)
+	}
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// ExampleTableType_RowCells demonstrates an invoice whose description cells
+// span the rows of the items they describe and whose total spans columns.
+func ExampleTableType_RowCells() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 10)
+	tbl := pdf.TableNew(5, gofpdf.TableColumnType{Width: 60, AlignStr: "M"},
+		gofpdf.TableColumnType{}, gofpdf.TableColumnType{Width: 20, AlignStr: "R"},
+		gofpdf.TableColumnType{Width: 30, AlignStr: "R"})
+	tbl.SetPadding(2, 1)
+	tbl.SetBorder("1")
+	tbl.Header("Service", "Item", "Hours", "Amount")
+	pdf.SetFont("Helvetica", "", 10)
+	type item struct {
+		name  string
+		hours int
+	}
+	services := []struct {
+		desc  string
+		items []item
+	}{
+		{"Design of the new storefront, including two rounds of revisions",
+			[]item{{"Wireframes", 6}, {"Visual design", 14}, {"Revisions", 4}}},
+		{"Development", []item{{"Templates", 20}, {"Checkout integration", 12}}},
+		{"Hosting setup", []item{{"Server configuration", 3}}},
+	}
+	total := 0
+	for _, service := range services {
+		for j, it := range service.items {
+			var cells []gofpdf.TableCellType
+			if j == 0 {
+				cells = append(cells, gofpdf.TableCellType{Text: service.desc, RowSpan: len(service.items)})
+			}
+			cells = append(cells, gofpdf.TableCellType{Text: it.name},
+				gofpdf.TableCellType{Text: strconv.Itoa(it.hours)},
+				gofpdf.TableCellType{Text: fmt.Sprintf("%d.00", it.hours*80)})
+			tbl.RowCells(cells...)
+			total += it.hours * 80
+		}
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	tbl.RowCells(gofpdf.TableCellType{Text: "Total", ColSpan: 3, AlignStr: "R"},
+		gofpdf.TableCellType{Text: fmt.Sprintf("%d.00", total)})
+	fileStr := example.Filename("TableType_RowCells")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/TableType_RowCells.pdf
+}
+
+// TestTableRowSpan verifies that the rows that cells span are printed
+// together, as high as the text of the spanning cells needs, and moved
+// together to the next page.
+func TestTableRowSpan(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 10)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	tbl := pdf.TableNew(5, gofpdf.TableColumnType{Width: 30}, gofpdf.TableColumnType{Width: 30},
+		gofpdf.TableColumnType{Width: 30})
+	tbl.Header("H1", "H2", "H3")
+	txtStr := "one two three four five six seven eight"
+	lines := len(pdf.BreakLines(txtStr, 30))
+	if lines < 3 {
+		t.Fatalf("text broken in %d lines", lines)
+	}
+	y := pdf.GetY()
+	tbl.RowCells(gofpdf.TableCellType{Text: txtStr, RowSpan: 2}, gofpdf.TableCellType{Text: "x"},
+		gofpdf.TableCellType{Text: "y"})
+	if pdf.GetY() != y {
+		t.Fatalf("row printed before the rows its cells span")
+	}
+	tbl.Row("z", "w")
+	if want := y + 5*float64(lines); math.Abs(pdf.GetY()-want) > 1e-9 {
+		t.Fatalf("rows end at %.2f, expected %.2f", pdf.GetY(), want)
+	}
+	// Rows whose cells span rows that do not follow are printed by End()
+	pdf.SetY(280)
+	tbl.RowCells(gofpdf.TableCellType{Text: "a", RowSpan: 3}, gofpdf.TableCellType{Text: "b"})
+	tbl.Row("c")
+	if pdf.GetY() != 280 {
+		t.Fatalf("row printed before the rows its cells span")
+	}
+	tbl.End()
+	// The two rows do not fit below 280 mm and move with the header row to
+	// the next page
+	if pdf.PageNo() != 2 || pdf.GetY() != 10+5+10 {
+		t.Fatalf("rows end at %.2f on page %d", pdf.GetY(), pdf.PageNo())
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	k := 72 / 25.4
+	for _, str := range []string{
+		// The cells of the second row are set in the columns left by the
+		// spanning cell
+		fmt.Sprintf("BT %.2f %.2f Td (z)Tj", (10+30+pdf.GetCellMargin())*k, (297-y-5-2.5-0.3*12/k)*k),
+		"(H1)Tj", "(c)Tj"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	if n := strings.Count(buf.String(), "(H1)Tj"); n != 2 {
+		t.Errorf("header row printed %d times, expected 2", n)
+	}
+}
+
+// ExampleTableType_SetRowSplit demonstrates a table of notes whose long rows
+// are split across pages.
+func ExampleTableType_SetRowSplit() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Times", "B", 11)
+	tbl := pdf.TableNew(5, gofpdf.TableColumnType{Width: 25}, gofpdf.TableColumnType{AlignStr: "J"})
+	tbl.SetPadding(2, 1.5)
+	tbl.SetBorder("1")
+	tbl.SetRowSplit(true)
+	tbl.SetRowFillColors(gofpdf.RGBType{R: 255, G: 255, B: 255}, gofpdf.RGBType{R: 240, G: 240, B: 240})
+	tbl.Header("Date", "Notes")
+	pdf.SetFont("Times", "", 11)
+	for j := 1; j <= 6; j++ {
+		tbl.Row(fmt.Sprintf("2024-03-%02d", j), strings.Repeat(lorem()+" ", j%3+1))
+	}
+	fileStr := example.Filename("TableType_SetRowSplit")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/TableType_SetRowSplit.pdf
+}
+
+// TestTableRowSplit verifies that a row split across pages prints each line
+// of its cells once, filling the page before the page break.
+func TestTableRowSplit(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 10)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	tbl := pdf.TableNew(5, gofpdf.TableColumnType{Width: 40}, gofpdf.TableColumnType{Width: 40})
+	tbl.SetPadding(1, 1)
+	tbl.SetRowSplit(true)
+	tbl.Header("Head")
+	var words []string
+	for j := 0; j < 400; j++ {
+		words = append(words, fmt.Sprintf("w%d", j))
+	}
+	txtStr := strings.Join(words, " ")
+	lines := len(pdf.BreakLines(txtStr, 40))
+	pdf.SetY(250)
+	tbl.RowCells(gofpdf.TableCellType{Text: txtStr}, gofpdf.TableCellType{Spans: []gofpdf.TextSpan{
+		{Text: "bold", FontStyle: "B"}, {Text: " and\nregular"}}})
+	// The lines of the page that begins at 250 mm are followed by pages of
+	// the header row and as many lines as fit, 53 of them
+	first := int((287 - 250 - 2) / 5)
+	pages := 1 + (lines-first+52)/53
+	if pdf.PageNo() != pages {
+		t.Fatalf("row of %d lines printed on %d pages, expected %d", lines, pdf.PageNo(), pages)
+	}
+	if want := 10 + 7 + 5*float64((lines-first-1)%53+1) + 2; math.Abs(pdf.GetY()-want) > 1e-9 {
+		t.Fatalf("row ends at %.2f, expected %.2f", pdf.GetY(), want)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range append(words, "bold", "regular") {
+		if n := strings.Count(buf.String(), "("+word+" ") + strings.Count(buf.String(), " "+word+" ") +
+			strings.Count(buf.String(), " "+word+")") + strings.Count(buf.String(), "("+word+")"); n != 1 {
+			t.Fatalf("%q printed %d times", word, n)
+		}
+	}
+	if n := strings.Count(buf.String(), "(Head)Tj"); n != pages {
+		t.Fatalf("header row printed %d times, expected %d", n, pages)
+	}
+}
+
+// ExampleTableType_SetCellStyleFunc demonstrates a ledger whose negative
+// amounts are red, whose rows are banded in groups of three and whose total
+// row has a double border above it.
+func ExampleTableType_SetCellStyleFunc() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	amounts := []float64{1250, -320.5, 89.99, -1200, 430, 15.75, -60, 980, 0, -12.3}
+	tbl := pdf.TableNew(6, gofpdf.TableColumnType{Width: 20, AlignStr: "C"},
+		gofpdf.TableColumnType{Width: 60}, gofpdf.TableColumnType{Width: 35, AlignStr: "R"})
+	tbl.SetBorder("B")
+	red := &gofpdf.RGBType{R: 200, G: 0, B: 0}
+	band := &gofpdf.RGBType{R: 235, G: 245, B: 235}
+	tbl.SetCellStyleFunc(func(row, col int) (style gofpdf.TableCellStyleType) {
+		switch {
+		case row < 0:
+			style.BorderStr = "TB"
+		case row == len(amounts):
+			style.BorderStr = "T"
+		case row/3%2 == 1:
+			style.FillColor = band
+		}
+		if col == 2 && row >= 0 && row < len(amounts) && amounts[row] < 0 {
+			style.TextColor = red
+		}
+		return
+	})
+	tbl.Header("#", "Entry", "Amount")
+	total := 0.0
+	for j, amount := range amounts {
+		tbl.Row(strconv.Itoa(j+1), fmt.Sprintf("Entry %c", 'A'+j), fmt.Sprintf("%.2f", amount))
+		total += amount
+	}
+	tbl.Row("", "Total", fmt.Sprintf("%.2f", total))
+	fileStr := example.Filename("TableType_SetCellStyleFunc")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/TableType_SetCellStyleFunc.pdf
+}
+
+// TestTableCellStyle verifies the rows and columns that the style function
+// of a table is called for and that its styles are applied.
+func TestTableCellStyle(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	tbl := pdf.TableNew(5, gofpdf.TableColumnType{Width: 30}, gofpdf.TableColumnType{Width: 30})
+	tbl.SetBorder("1")
+	var calls []string
+	tbl.SetCellStyleFunc(func(row, col int) (style gofpdf.TableCellStyleType) {
+		calls = append(calls, fmt.Sprintf("%d,%d", row, col))
+		switch {
+		case row == 0 && col == 1:
+			style.TextColor = &gofpdf.RGBType{R: 255, G: 0, B: 0}
+		case row == 1 && col == 1:
+			style.BorderStr = "0"
+		}
+		return
+	})
+	tbl.Header("h1", "h2")
+	tbl.Row("a", "b")
+	tbl.RowCells(gofpdf.TableCellType{Text: "c"})
+	tbl.RowCells(gofpdf.TableCellType{Text: "d", ColSpan: 2})
+	if got, want := strings.Join(calls, " "), "-1,0 -1,1 0,0 0,1 1,0 1,1 2,0"; got != want {
+		t.Fatalf("style function called for %q, expected %q", got, want)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// The empty cell of the third row has no border, unlike the six other
+	// cells
+	if n := strings.Count(buf.String(), " re S"); n != 6 {
+		t.Fatalf("%d borders, expected 6", n)
+	}
+	if !strings.Contains(buf.String(), "1.000 0.000 0.000 rg") {
+		t.Fatal("text color missing")
+	}
+}
+
+// ExampleFpdf_TOCNew demonstrates a table of contents, printed on a page
+// reserved for it before the chapters, whose entries link to their headings.
+func ExampleFpdf_TOCNew() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(0, 20, "Contents", "", 1, "L", false, 0, "")
+	toc := pdf.TOCNew(7, 8)
+	toc.Reserve(1)
+	chapter := func(level int, titleStr string) {
+		toc.Entry(titleStr, level)
+		pdf.Bookmark(titleStr, level, -1)
+		pdf.SetFont("Helvetica", "B", float64(16-2*level))
+		pdf.CellFormat(0, 12, titleStr, "", 1, "L", false, 0, "")
+		pdf.SetFont("Times", "", 12)
+		for j := 0; j < 4-level; j++ {
+			pdf.MultiCell(0, 5, lorem(), "", "J", false)
+			pdf.Ln(3)
+		}
+	}
+	for j := 1; j <= 4; j++ {
+		if j > 1 {
+			pdf.AddPage()
+		}
+		chapter(0, fmt.Sprintf("Chapter %d", j))
+		for k := 1; k <= 3; k++ {
+			chapter(1, fmt.Sprintf("Section %d.%d", j, k))
+		}
+	}
+	pdf.SetFont("Times", "", 12)
+	toc.Write()
+	fileStr := example.Filename("Fpdf_TOCNew")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_TOCNew.pdf
+}
+
+// TestTOC verifies the page numbers and links of the entries of a table of
+// contents printed on reserved pages or at the current position.
+func TestTOC(t *testing.T) {
+	newDoc := func() (*gofpdf.Fpdf, gofpdf.TOCType) {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(false)
+		pdf.SetFont("Helvetica", "", 12)
+		pdf.AddPage()
+		toc := pdf.TOCNew(6, 5)
+		return pdf, toc
+	}
+	pdf, toc := newDoc()
+	toc.Reserve(2)
+	for j := 0; j < 3; j++ {
+		toc.Entry(fmt.Sprintf("Heading%d", j), j%2)
+		pdf.AddPage()
+	}
+	if got := fmt.Sprint(toc.Entries()); got != "[{Heading0 0 3 1} {Heading1 1 4 2} {Heading2 0 5 3}]" {
+		t.Fatalf("entries %s", got)
+	}
+	pdf.SetXY(50, 60)
+	toc.Write()
+	if pdf.PageNo() != 6 || pdf.GetX() != 50 || pdf.GetY() != 60 {
+		t.Fatalf("position %d %.2f %.2f not restored", pdf.PageNo(), pdf.GetX(), pdf.GetY())
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"(Heading0)Tj", "(Heading1)Tj", "(3)Tj", "(4)Tj", "(5)Tj"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	if n := strings.Count(buf.String(), "/Subtype /Link"); n != 3 {
+		t.Errorf("%d links, expected 3", n)
+	}
+	// Entries that do not fit on the reserved pages
+	pdf, toc = newDoc()
+	toc.Reserve(1)
+	for j := 0; j < 100; j++ {
+		toc.Entry("Heading", 0)
+	}
+	toc.Write()
+	if pdf.Error() == nil {
+		t.Fatal("table of contents overflowing the reserved pages accepted")
+	}
+	// A table of contents at the end of the document
+	pdf, toc = newDoc()
+	for j := 0; j < 100; j++ {
+		toc.Entry("Heading", 0)
+	}
+	toc.Write()
+	if pdf.PageNo() != 3 || pdf.Error() != nil {
+		t.Fatalf("table of contents ends on page %d: %v", pdf.PageNo(), pdf.Error())
+	}
+}
+
+// ExampleFpdf_Path demonstrates figures of several subpaths filled using the
+// nonzero winding number and even-odd rules, and a path used for clipping.
+func ExampleFpdf_Path() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetFillColor(120, 160, 220)
+	// star returns a five-pointed star centered at (x, y), drawn as a single
+	// closed subpath whose edges cross
+	star := func(x, y, r float64) (path gofpdf.PathType) {
+		for j := 0; j < 5; j++ {
+			a := math.Pi/2 + float64(j*4)*math.Pi/5
+			path.LineTo(x+r*math.Cos(a), y-r*math.Sin(a))
+		}
+		path.Close()
+		return
+	}
+	pdf.Text(20, 20, "Nonzero winding number rule")
+	pdf.Path(star(45, 50, 25), "FD")
+	pdf.Text(110, 20, "Even-odd rule")
+	pdf.Path(star(135, 50, 25), "FD*")
+	// A ring: the inner circle cuts a hole out of the outer one
+	var ring gofpdf.PathType
+	ring.Ellipse(45, 120, 25, 25)
+	ring.Ellipse(45, 120, 12, 12)
+	pdf.Text(20, 90, "Ring")
+	pdf.Path(ring, "FD*")
+	// An open figure of lines, curves and an arc, outlined
+	var wave gofpdf.PathType
+	wave.MoveTo(110, 120)
+	wave.QuadTo(120, 95, 130, 120)
+	wave.CubicTo(135, 140, 145, 100, 150, 120)
+	wave.ArcTo(160, 120, 10, 10, 0, 180, 0)
+	pdf.Text(110, 90, "Lines, curves and arcs")
+	pdf.SetLineWidth(1)
+	pdf.Path(wave, "D")
+	// Text clipped by a frame with a rounded hole
+	var frame gofpdf.PathType
+	frame.Rect(20, 160, 170, 60)
+	frame.MoveTo(50, 175)
+	frame.LineTo(160, 175)
+	frame.ArcTo(160, 190, 15, 15, 0, 90, -90)
+	frame.LineTo(50, 205)
+	frame.ArcTo(50, 190, 15, 15, 0, 270, 90)
+	frame.Close()
+	pdf.ClipPath(frame, true, true)
+	pdf.SetXY(20, 160)
+	pdf.MultiCell(170, 5, strings.Repeat(lorem()+" ", 2), "", "J", false)
+	pdf.ClipEnd()
+	fileStr := example.Filename("Fpdf_Path")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Path.pdf
+}
+
+// TestPath verifies the operators with which paths are drawn, filled and
+// used for clipping.
+func TestPath(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	_, h := pdf.GetPageSize()
+	var path gofpdf.PathType
+	path.LineTo(10, 10)
+	path.LineTo(20, 10)
+	path.QuadTo(30, 10, 30, 40)
+	path.Close()
+	path.Rect(50, 60, 10, 20)
+	pdf.Path(path, "F*")
+	var arc gofpdf.PathType
+	arc.MoveTo(0, 0)
+	arc.ArcTo(100, 100, 10, 10, 0, 0, 90)
+	pdf.ClipPath(arc, true, false)
+	pdf.ClipEnd()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pt := func(x, y float64) string {
+		return fmt.Sprintf("%.5f %.5f", x, h-y)
+	}
+	for _, str := range []string{
+		pt(10, 10) + " m " + pt(20, 10) + " l " + pt(20+20.0/3, 10) + " " + pt(30, 20) + " " + pt(30, 40) + " c h " +
+			pt(50, 60) + " m " + pt(60, 60) + " l " + pt(60, 80) + " l " + pt(50, 80) + " l h f*",
+		"q " + pt(0, 0) + " m " + pt(110, 100) + " l ",
+		pt(100, 90) + " c W* n",
+	} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+}
+
+// ExamplePathType_EllipticalArcTo demonstrates the arcs of SVG paths, with
+// the pie chart of the SVG specification.
+func ExamplePathType_EllipticalArcTo() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	// M300,200 h-150 a150,150 0 1,0 150,-150 z, scaled to a tenth
+	var pie gofpdf.PathType
+	pie.MoveTo(60, 50)
+	pie.LineTo(45, 50)
+	pie.EllipticalArcTo(15, 15, 0, true, false, 60, 35)
+	pie.Close()
+	pdf.SetFillColor(255, 0, 0)
+	pdf.SetDrawColor(0, 0, 255)
+	pdf.SetLineWidth(1)
+	pdf.Path(pie, "FD")
+	// M275,175 v-150 a150,150 0 0,0 -150,150 z
+	var slice gofpdf.PathType
+	slice.MoveTo(57.5, 47.5)
+	slice.LineTo(57.5, 32.5)
+	slice.EllipticalArcTo(15, 15, 0, false, false, 42.5, 47.5)
+	slice.Close()
+	pdf.SetFillColor(255, 255, 0)
+	pdf.Path(slice, "FD")
+	// The four arcs of rotated ellipses that join two points
+	pdf.SetLineWidth(0.5)
+	for j, flags := range [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}} {
+		var arc gofpdf.PathType
+		arc.MoveTo(110, 60)
+		arc.EllipticalArcTo(25, 15, -30, flags[0], flags[1], 150, 40)
+		pdf.SetDrawColor(60*j, 100, 255-60*j)
+		pdf.Path(arc, "D")
+	}
+	fileStr := example.Filename("PathType_EllipticalArcTo")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/PathType_EllipticalArcTo.pdf
+}
+
+// TestPathEllipticalArc verifies the arcs of paths given by their ends.
+func TestPathEllipticalArc(t *testing.T) {
+	pt := func(x, y float64) string {
+		return fmt.Sprintf("%.5f %.5f", x, 841.89-y)
+	}
+	for _, arc := range []struct {
+		rx, ry          float64
+		largeArc, sweep bool
+		str             string
+	}{
+		// Half circles end at the top or bottom after a quarter
+		{10, 10, false, true, pt(10, 90) + " c "},
+		{10, 10, false, false, pt(10, 110) + " c "},
+		// Radii too small are scaled up
+		{1, 1, true, true, pt(10, 90) + " c "},
+		// A radius of 0 makes a straight line
+		{0, 10, false, true, pt(0, 100) + " m " + pt(20, 100) + " l f"},
+	} {
+		pdf := gofpdf.New("P", "pt", "A4", "")
+		pdf.SetCompression(false)
+		pdf.AddPage()
+		var path gofpdf.PathType
+		path.MoveTo(0, 100)
+		path.EllipticalArcTo(arc.rx, arc.ry, 0, arc.largeArc, arc.sweep, 20, 100)
+		pdf.Path(path, "F")
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), arc.str) {
+			t.Errorf("arc %v: %q missing", arc, arc.str)
+		}
+	}
+}
+
+// ExampleFpdf_LinearGradientStops demonstrates gradients of several colors.
+func ExampleFpdf_LinearGradientStops() {
+	pdf := gofpdf.New("", "", "", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	rainbow := []gofpdf.GradientStopType{
+		{Pos: 0, Color: gofpdf.RGBType{R: 228, G: 3, B: 3}},
+		{Pos: 0.2, Color: gofpdf.RGBType{R: 255, G: 140, B: 0}},
+		{Pos: 0.4, Color: gofpdf.RGBType{R: 255, G: 237, B: 0}},
+		{Pos: 0.6, Color: gofpdf.RGBType{R: 0, G: 128, B: 38}},
+		{Pos: 0.8, Color: gofpdf.RGBType{R: 36, G: 64, B: 142}},
+		{Pos: 1, Color: gofpdf.RGBType{R: 115, G: 41, B: 130}},
+	}
+	pdf.LinearGradientStops(20, 20, 170, 30, rainbow, 0, 0, 1, 0, true)
+	pdf.Rect(20, 20, 170, 30, "D")
+	// Stops at the same positions make bands of solid colors
+	bands := []gofpdf.GradientStopType{
+		{Pos: 0.25, Color: gofpdf.RGBType{R: 30, G: 60, B: 120}},
+		{Pos: 0.5, Color: gofpdf.RGBType{R: 30, G: 60, B: 120}},
+		{Pos: 0.5, Color: gofpdf.RGBType{R: 240, G: 200, B: 80}},
+		{Pos: 0.75, Color: gofpdf.RGBType{R: 240, G: 200, B: 80}},
+	}
+	pdf.LinearGradientStops(20, 60, 75, 75, bands, 0, 1, 1, 0, true)
+	pdf.Rect(20, 60, 75, 75, "D")
+	// Without extension, nothing is painted beyond the circle
+	pdf.RadialGradientStops(115, 60, 75, 75, rainbow, 0.5, 0.5, 0.5, 0.5, 0.5, false)
+	pdf.Rect(115, 60, 75, 75, "D")
+	fileStr := example.Filename("Fpdf_LinearGradientStops")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_LinearGradientStops.pdf
+}
+
+// TestGradientStops verifies the functions of gradients of several colors
+// and the checking of their stops.
+func TestGradientStops(t *testing.T) {
+	pdf := gofpdf.New("", "", "", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.LinearGradient(0, 0, 10, 10, 0, 0, 0, 255, 255, 255, 0, 0, 1, 0)
+	pdf.RadialGradientStops(0, 0, 10, 10, []gofpdf.GradientStopType{
+		{Pos: 0.2, Color: gofpdf.RGBType{R: 255}},
+		{Pos: 0.5, Color: gofpdf.RGBType{G: 255}},
+		{Pos: 0.6, Color: gofpdf.RGBType{B: 255}},
+	}, 0.5, 0.5, 0.5, 0.5, 0.5, false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{
+		"/C0 [0.000 0.000 0.000] /C1 [1.000 1.000 1.000] /N 1>>",
+		"/Extend [true true]>>",
+		"<</FunctionType 3 /Domain [0.0 1.0] /Functions [" +
+			"<</FunctionType 2 /Domain [0.0 1.0] /C0 [1.000 0.000 0.000] /C1 [1.000 0.000 0.000] /N 1>> " +
+			"<</FunctionType 2 /Domain [0.0 1.0] /C0 [1.000 0.000 0.000] /C1 [0.000 1.000 0.000] /N 1>> " +
+			"<</FunctionType 2 /Domain [0.0 1.0] /C0 [0.000 1.000 0.000] /C1 [0.000 0.000 1.000] /N 1>> " +
+			"<</FunctionType 2 /Domain [0.0 1.0] /C0 [0.000 0.000 1.000] /C1 [0.000 0.000 1.000] /N 1>> ] " +
+			"/Bounds [0.20000 0.50000 0.60000 ] /Encode [0 1 0 1 0 1 0 1 ]>>",
+		"/Extend [false false]>>",
+	} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	for _, stops := range [][]gofpdf.GradientStopType{
+		{{Pos: 0}},
+		{{Pos: 0.5}, {Pos: 0.4}},
+		{{Pos: 0}, {Pos: 1.5}},
+	} {
+		pdf := gofpdf.New("", "", "", "")
+		pdf.AddPage()
+		pdf.LinearGradientStops(0, 0, 10, 10, stops, 0, 0, 1, 0, true)
+		if pdf.Error() == nil {
+			t.Errorf("stops %v accepted", stops)
+		}
+	}
+}
+
+// ExampleFpdf_LatticeMesh demonstrates smooth shadings of meshes: a heatmap
+// of a function sampled on a grid, triangles and a curved patch.
+func ExampleFpdf_LatticeMesh() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	// heat returns the color of the value v, from 0 to 1, from blue to red
+	heat := func(v float64) gofpdf.RGBType {
+		return gofpdf.RGBType{R: int(255 * v), G: int(255 * (1 - math.Abs(2*v-1))), B: int(255 * (1 - v))}
+	}
+	pdf.Text(20, 15, "Heatmap")
+	var rows [][]gofpdf.MeshVertexType
+	for j := 0; j <= 20; j++ {
+		var row []gofpdf.MeshVertexType
+		for k := 0; k <= 20; k++ {
+			dx, dy := float64(k-7)/10, float64(j-12)/10
+			v := math.Exp(-dx*dx-dy*dy) * (0.75 + 0.25*math.Sin(float64(j+k)/3))
+			row = append(row, gofpdf.MeshVertexType{X: 20 + float64(k)*8.5, Y: 20 + float64(j)*5, Color: heat(v)})
+		}
+		rows = append(rows, row)
+	}
+	pdf.LatticeMesh(rows)
+	pdf.Rect(20, 20, 170, 100, "D")
+	pdf.Text(20, 135, "Triangles")
+	pdf.TriangleMesh([][3]gofpdf.MeshVertexType{
+		{{X: 20, Y: 200, Color: gofpdf.RGBType{R: 255}}, {X: 60, Y: 140, Color: gofpdf.RGBType{G: 255}},
+			{X: 100, Y: 200, Color: gofpdf.RGBType{B: 255}}},
+		{{X: 60, Y: 140, Color: gofpdf.RGBType{G: 255}}, {X: 100, Y: 200, Color: gofpdf.RGBType{B: 255}},
+			{X: 100, Y: 140, Color: gofpdf.RGBType{R: 255, G: 255}}},
+	})
+	pdf.Text(115, 135, "Coons patch")
+	pdf.CoonsPatchMesh([]gofpdf.CoonsPatchType{{
+		Points: [12]gofpdf.PointType{
+			{X: 115, Y: 200}, {X: 130, Y: 185}, {X: 160, Y: 215}, {X: 190, Y: 200},
+			{X: 175, Y: 180}, {X: 205, Y: 160}, {X: 190, Y: 140},
+			{X: 160, Y: 155}, {X: 130, Y: 125}, {X: 115, Y: 140},
+			{X: 100, Y: 160}, {X: 130, Y: 180},
+		},
+		Colors: [4]gofpdf.RGBType{{R: 255, G: 255}, {R: 255}, {B: 255}, {G: 200, B: 100}},
+	}})
+	fileStr := example.Filename("Fpdf_LatticeMesh")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_LatticeMesh.pdf
+}
+
+// TestMesh verifies the shading dictionaries of meshes and the checking of
+// their vertices.
+func TestMesh(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	red, blue := gofpdf.RGBType{R: 255}, gofpdf.RGBType{B: 255}
+	pdf.TriangleMesh([][3]gofpdf.MeshVertexType{{{X: 10, Y: 10, Color: red}, {X: 20, Y: 10, Color: blue}, {X: 10, Y: 20}}})
+	pdf.LatticeMesh([][]gofpdf.MeshVertexType{
+		{{X: 0, Y: 0, Color: red}, {X: 10, Y: 0}, {X: 20, Y: 0}},
+		{{X: 0, Y: 10}, {X: 10, Y: 10}, {X: 20, Y: 10, Color: blue}},
+	})
+	var patch gofpdf.CoonsPatchType
+	for j := range patch.Points {
+		patch.Points[j] = gofpdf.PointType{X: float64(j), Y: float64(j)}
+	}
+	pdf.CoonsPatchMesh([]gofpdf.CoonsPatchType{patch})
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{
+		"/Sh1 sh", "/Sh2 sh", "/Sh3 sh",
+		"<</ShadingType 4 /ColorSpace /DeviceRGB /BitsPerFlag 8 /BitsPerCoordinate 32 /BitsPerComponent 8 " +
+			"/Decode [10.00000 20.00000 821.89000 831.89000 0 1 0 1 0 1] /Filter /FlateDecode",
+		"<</ShadingType 5 /ColorSpace /DeviceRGB /VerticesPerRow 3 /BitsPerCoordinate 32 /BitsPerComponent 8 " +
+			"/Decode [0.00000 20.00000 831.89000 841.89000 0 1 0 1 0 1]",
+		"<</ShadingType 6 /ColorSpace /DeviceRGB /BitsPerFlag 8 /BitsPerCoordinate 32 /BitsPerComponent 8 " +
+			"/Decode [0.00000 11.00000 830.89000 841.89000 0 1 0 1 0 1]",
+	} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	pdf = gofpdf.New("P", "pt", "A4", "")
+	pdf.AddPage()
+	pdf.LatticeMesh([][]gofpdf.MeshVertexType{{{}, {}}, {{}}})
+	if pdf.Error() == nil {
+		t.Error("lattice of rows of different lengths accepted")
+	}
+}
+
+// ExampleFpdf_BeginTransparencyGroup demonstrates overlapping shapes faded as
+// a unit, and the isolated and knockout options of transparency groups.
+func ExampleFpdf_BeginTransparencyGroup() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.SetFillColor(240, 200, 60)
+	pdf.Rect(10, 10, 190, 130, "F")
+	// circles draws three overlapping circles below the label labelStr
+	circles := func(x, y float64, labelStr string) {
+		pdf.Text(x, y, labelStr)
+		for j, clr := range [][3]int{{220, 40, 40}, {40, 160, 40}, {40, 40, 220}} {
+			pdf.SetFillColor(clr[0], clr[1], clr[2])
+			pdf.Circle(x+15+float64(j%2)*20, y+20+float64(j/2)*15, 15, "F")
+		}
+	}
+	pdf.SetAlpha(0.5, "Normal")
+	circles(20, 20, "Each circle faded")
+	pdf.BeginTransparencyGroup(false, false)
+	circles(110, 20, "Faded as a group")
+	pdf.EndTransparencyGroup()
+	pdf.SetAlpha(1, "Normal")
+	// Within a knockout group, the circles do not show through each other
+	pdf.BeginTransparencyGroup(false, true)
+	pdf.SetAlpha(0.5, "Normal")
+	circles(20, 80, "Knockout group")
+	pdf.EndTransparencyGroup()
+	// Within an isolated group, the circles do not blend with the page
+	pdf.BeginTransparencyGroup(true, false)
+	pdf.SetAlpha(1, "Multiply")
+	circles(110, 80, "Isolated group")
+	pdf.EndTransparencyGroup()
+	fileStr := example.Filename("Fpdf_BeginTransparencyGroup")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_BeginTransparencyGroup.pdf
+}
+
+// TestTransparencyGroup verifies the content of transparency groups, the
+// state restored after them and the checking of their nesting.
+func TestTransparencyGroup(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetAlpha(0.5, "Multiply")
+	pdf.BeginTransparencyGroup(true, true)
+	if alpha, mode := pdf.GetAlpha(); alpha != 1 || mode != "Normal" {
+		t.Fatalf("alpha %.2f %s within group", alpha, mode)
+	}
+	pdf.Rect(1, 2, 3, 4, "F")
+	pdf.BeginTransparencyGroup(false, false)
+	pdf.SetLineWidth(2)
+	pdf.Rect(5, 6, 7, 8, "D")
+	pdf.EndTransparencyGroup()
+	pdf.EndTransparencyGroup()
+	if alpha, mode := pdf.GetAlpha(); alpha != 0.5 || mode != "Multiply" {
+		t.Fatalf("alpha %.2f %s after group", alpha, mode)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	for _, s := range []string{
+		"/TG2 Do\n0.00000 Tc\n0 Tw\n0.00000 Ts\n0 Tr\n0 J 0 j 2.00 w\n[] 0.00 d\n0.000 G\n0.000 g",
+		"/Group <</Type /Group /S /Transparency /CS /DeviceRGB /I true /K true>>",
+		"/Group <</Type /Group /S /Transparency /CS /DeviceRGB /I false /K false>>",
+		"/TG1 ", "/TG2 ", "%PDF-1.4",
+	} {
+		if !strings.Contains(str, s) {
+			t.Errorf("%q missing", s)
+		}
+	}
+	// Pages are written before the groups
+	if strings.Index(str, "1.00 839.89 3.00 -4.00 re") < strings.Index(str, "/TG2 Do") {
+		t.Error("group content in page")
+	}
+	for _, fn := range []func(pdf *gofpdf.Fpdf){
+		func(pdf *gofpdf.Fpdf) { pdf.EndTransparencyGroup() },
+		func(pdf *gofpdf.Fpdf) {
+			pdf.BeginTransparencyGroup(false, false)
+			pdf.AddPage()
+			pdf.EndTransparencyGroup()
+		},
+		func(pdf *gofpdf.Fpdf) { pdf.BeginTransparencyGroup(false, false); pdf.Close() },
+	} {
+		pdf := gofpdf.New("P", "pt", "A4", "")
+		pdf.AddPage()
+		fn(pdf)
+		if pdf.Error() == nil {
+			t.Error("invalid transparency group accepted")
+		}
+	}
+}
+
+// ExampleFpdf_SetBlendMode demonstrates a watermark that darkens the text and
+// image below it, and blend modes combined with transparency.
+func ExampleFpdf_SetBlendMode() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.Image(example.ImageFile("logo.png"), 120, 20, 70, 0, false, "", 0, "")
+	pdf.SetXY(20, 20)
+	pdf.MultiCell(90, 5, lorem(), "", "J", false)
+	// The watermark multiplies the colors below it instead of covering them
+	pdf.SetBlendMode("Multiply")
+	pdf.SetFont("Helvetica", "B", 60)
+	pdf.SetTextColor(250, 200, 60)
+	pdf.TransformBegin()
+	pdf.TransformRotate(30, 105, 80)
+	pdf.Text(30, 90, "APPROVED")
+	pdf.TransformEnd()
+	pdf.SetBlendMode("Normal")
+	// Modes apply to drawings with the current alpha
+	pdf.SetAlpha(0.8, "Normal")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(0, 0, 0)
+	for j, modeStr := range []string{"Normal", "Multiply", "Screen", "Overlay", "Darken", "Lighten", "Difference", "Exclusion"} {
+		x := 20 + float64(j%4)*45
+		y := 160 + float64(j/4)*50
+		pdf.SetBlendMode("Normal")
+		pdf.SetFillColor(60, 120, 200)
+		pdf.Rect(x, y, 25, 25, "F")
+		pdf.Text(x, y+42, modeStr)
+		pdf.SetBlendMode(modeStr)
+		pdf.SetFillColor(230, 120, 60)
+		pdf.Circle(x+25, y+20, 12, "F")
+	}
+	fileStr := example.Filename("Fpdf_SetBlendMode")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetBlendMode.pdf
+}
+
+// TestSetBlendMode verifies that the blend mode is set without changing the
+// alpha transparency value.
+func TestSetBlendMode(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetAlpha(0.4, "Normal")
+	pdf.SetBlendMode("Multiply")
+	if alpha, modeStr := pdf.GetAlpha(); alpha != 0.4 || modeStr != "Multiply" {
+		t.Fatalf("alpha %.2f, blend mode %s", alpha, modeStr)
+	}
+	pdf.SetBlendMode("")
+	if _, modeStr := pdf.GetAlpha(); modeStr != "Normal" {
+		t.Fatalf("blend mode %q", modeStr)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"/ca 0.400 /CA 0.400 /BM /Multiply", "/ca 0.400 /CA 0.400 /BM /Normal"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	if strings.Contains(buf.String(), "/BM />>") {
+		t.Error("empty blend mode")
+	}
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetBlendMode("Brighten")
+	if pdf.Error() == nil {
+		t.Error("unknown blend mode accepted")
+	}
+}
+
+// ExampleFpdf_BeginSoftMask demonstrates vector art faded out by a gradient
+// and text feathered by a mask of graded shapes.
+func ExampleFpdf_BeginSoftMask() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "B", 24)
+	pdf.AddPage()
+	pdf.SetFillColor(250, 230, 160)
+	pdf.Rect(10, 10, 190, 250, "F")
+	// Stripes that fade out from left to right
+	pdf.BeginSoftMask()
+	pdf.LinearGradient(20, 20, 170, 60, 255, 255, 255, 0, 0, 0, 0, 0, 1, 0)
+	pdf.EndSoftMask()
+	pdf.SetFillColor(40, 80, 160)
+	for j := 0; j < 6; j++ {
+		pdf.Rect(20, 20+float64(j)*10, 170, 5, "F")
+	}
+	pdf.ClearSoftMask()
+	// A disc whose edge is feathered by concentric circles of lighter grays
+	pdf.BeginSoftMask()
+	for j := 0; j <= 10; j++ {
+		v := 255 * j / 10
+		pdf.SetFillColor(v, v, v)
+		pdf.Circle(105, 160, 50-float64(j)*2, "F")
+	}
+	pdf.EndSoftMask()
+	pdf.SetFillColor(180, 40, 40)
+	pdf.Rect(50, 105, 110, 110, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.Text(75, 163, "Feathered")
+	pdf.ClearSoftMask()
+	fileStr := example.Filename("Fpdf_BeginSoftMask")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_BeginSoftMask.pdf
+}
+
+// TestSoftMask verifies the graphics states of soft masks and the checking of
+// their nesting with transparency groups.
+func TestSoftMask(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.BeginSoftMask()
+	pdf.Rect(1, 2, 3, 4, "F")
+	pdf.EndSoftMask()
+	pdf.Rect(5, 6, 7, 8, "F")
+	pdf.ClearSoftMask()
+	pdf.ClearSoftMask()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	for _, s := range []string{
+		"/SM1 gs", "/SM2 gs\n/SM2 gs",
+		"/SMask <</Type /Mask /S /Luminosity /G ",
+		"<</Type /ExtGState /SMask /None>>",
+		"/ExtGState <<\n/SM1 ",
+		"/I true /K false",
+	} {
+		if !strings.Contains(str, s) {
+			t.Errorf("%q missing", s)
+		}
+	}
+	if strings.Index(str, "1.00 839.89 3.00 -4.00 re") < strings.Index(str, "/SM1 gs") {
+		t.Error("mask content in page")
+	}
+	pdf = gofpdf.New("P", "pt", "A4", "")
+	pdf.AddPage()
+	pdf.BeginSoftMask()
+	pdf.EndTransparencyGroup()
+	if pdf.Error() == nil {
+		t.Error("soft mask ended as transparency group")
+	}
+}
+
+// ExampleFpdf_ClipPath demonstrates the intersection of nested clipping paths
+// and the restoring of a saved clipping depth.
+func ExampleFpdf_ClipPath() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.AddPage()
+	depth := pdf.ClipDepth()
+	// Text clipped by the intersection of a ring and a wave
+	var ring, wave gofpdf.PathType
+	ring.Ellipse(105, 80, 60, 60)
+	ring.Ellipse(105, 80, 30, 30)
+	pdf.ClipPath(ring, true, true)
+	wave.MoveTo(20, 20)
+	wave.LineTo(190, 20)
+	wave.LineTo(190, 90)
+	wave.CubicTo(150, 50, 60, 130, 20, 90)
+	wave.Close()
+	pdf.ClipPath(wave, false, false)
+	pdf.SetXY(40, 20)
+	pdf.MultiCell(130, 4, strings.Repeat(lorem()+" ", 3), "", "J", false)
+	// Both clipping operations end at once
+	pdf.ClipRestore(depth)
+	pdf.SetFillColor(200, 220, 255)
+	pdf.SetXY(20, 150)
+	pdf.MultiCell(170, 4, lorem(), "", "J", true)
+	fileStr := example.Filename("Fpdf_ClipPath")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ClipPath.pdf
+}
+
+// TestClipRestore verifies that clipping operations are ended down to a saved
+// depth.
+func TestClipRestore(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.ClipRect(0, 0, 100, 100, false)
+	depth := pdf.ClipDepth()
+	var path gofpdf.PathType
+	path.Rect(10, 10, 50, 50)
+	pdf.ClipPath(path, false, false)
+	pdf.ClipCircle(30, 30, 20, false)
+	if pdf.ClipDepth() != 3 {
+		t.Fatalf("depth %d", pdf.ClipDepth())
+	}
+	pdf.ClipRestore(depth)
+	if pdf.ClipDepth() != 1 {
+		t.Fatalf("depth %d after restore", pdf.ClipDepth())
+	}
+	pdf.ClipRestore(5)
+	if pdf.Error() == nil {
+		t.Fatal("depth beyond the clipping operations accepted")
+	}
+	pdf = gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.ClipPath(path, false, false)
+	pdf.ClipPath(path, true, false)
+	pdf.ClipRestore(0)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "h W n\nq ") || !strings.Contains(buf.String(), "h W* n\nQ\nQ\n") {
+		t.Errorf("clipping operators missing")
+	}
+}
+
+// ExampleFpdf_SetFillPatternHatch demonstrates filling shapes with hatch
+// patterns drawn with the current draw color.
+func ExampleFpdf_SetFillPatternHatch() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetDrawColor(0, 0, 128)
+	pdf.SetFillPatternHatch(45, 3, 0.3, false)
+	pdf.Rect(10, 10, 90, 60, "FD")
+	pdf.SetFillPatternHatch(-45, 3, 0.3, false)
+	pdf.Rect(100, 10, 90, 60, "FD")
+	pdf.SetDrawColor(128, 0, 0)
+	pdf.SetFillPatternHatch(0, 4, 0.2, true)
+	pdf.Circle(55, 120, 40, "FD")
+	pdf.SetFillPatternHatch(30, 2, 0.5, true)
+	pdf.Circle(155, 120, 40, "FD")
+	fileStr := example.Filename("Fpdf_SetFillPatternHatch")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFillPatternHatch.pdf
+}
+
+// TestSetFillPatternHatch verifies that hatch patterns are written as tiling
+// patterns and shared by fills with the same parameters.
+func TestSetFillPatternHatch(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetDrawColor(255, 0, 0)
+	pdf.SetFillPatternHatch(90, 10, 1, true)
+	pdf.Rect(0, 0, 100, 100, "F")
+	pdf.SetFillPatternHatch(90, 10, 1, true)
+	pdf.Rect(100, 0, 100, 100, "F")
+	pdf.SetFillPatternHatch(0, 10, 1, false)
+	pdf.Rect(200, 0, 100, 100, "F")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	if strings.Count(s, "/PatternType 1") != 2 || !strings.Contains(s, "/P2 scn") || strings.Contains(s, "/P3 scn") {
+		t.Errorf("hatch patterns not shared")
+	}
+	for _, str := range []string{"/BBox [0 0 10.00000 10.00000] /XStep 10.00000 /YStep 10.00000",
+		"/Matrix [0.00000 1.00000 -1.00000 0.00000 0 841.89000]",
+		"1.000 0.000 0.000 RG 1.00000 w 0 5.00000 m 10.00000 5.00000 l S 5.00000 0 m 5.00000 10.00000 l S"} {
+		if !strings.Contains(s, str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	pdf = gofpdf.New("P", "pt", "A4", "")
+	pdf.AddPage()
+	pdf.SetFillPatternHatch(0, 0, 1, false)
+	if pdf.Error() == nil {
+		t.Errorf("zero spacing accepted")
+	}
+}
+
+// ExampleFpdf_PushState demonstrates a helper function that makes temporary
+// changes to the graphics state without affecting its caller.
+func ExampleFpdf_PushState() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	badge := func(x, y float64, txtStr string) {
+		pdf.PushState()
+		defer pdf.PopState()
+		pdf.SetFillColor(200, 40, 40)
+		pdf.SetDrawColor(120, 0, 0)
+		pdf.SetLineWidth(1)
+		pdf.SetDashPattern([]float64{2, 1}, 0)
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.TransformBegin()
+		pdf.TransformRotate(10, x, y)
+		pdf.SetXY(x, y)
+		pdf.CellFormat(50, 12, txtStr, "1", 0, "C", true, 0, "")
+	}
+	pdf.Text(10, 20, "Before the badge")
+	badge(60, 30, "NEW")
+	pdf.Text(10, 60, "After the badge, in the same font and colors")
+	pdf.Rect(10, 70, 80, 20, "D")
+	fileStr := example.Filename("Fpdf_PushState")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_PushState.pdf
+}
+
+// TestPushState verifies that saved graphics states are restored along with
+// the clipping operations and transformations begun since.
+func TestPushState(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetLineWidth(2)
+	pdf.ClipRect(0, 0, 100, 100, false)
+	pdf.PushState()
+	pdf.SetFont("Courier", "B", 20)
+	pdf.SetLineWidth(5)
+	pdf.SetDrawColor(255, 0, 0)
+	pdf.SetAlpha(0.5, "Multiply")
+	pdf.ClipCircle(50, 50, 20, false)
+	pdf.TransformBegin()
+	pdf.PopState()
+	if pdf.GetLineWidth() != 2 || pdf.ClipDepth() != 1 {
+		t.Errorf("line width %.1f, clip depth %d", pdf.GetLineWidth(), pdf.ClipDepth())
+	}
+	if size, _ := pdf.GetFontSize(); size != 12 {
+		t.Errorf("font size %.1f", size)
+	}
+	if r, g, b := pdf.GetDrawColor(); r != 0 || g != 0 || b != 0 {
+		t.Errorf("draw color %d %d %d", r, g, b)
+	}
+	if alpha, mode := pdf.GetAlpha(); alpha != 1 || mode != "Normal" {
+		t.Errorf("alpha %.1f %s", alpha, mode)
+	}
+	pdf.ClipEnd()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "q\nQ\nQ\nQ\nQ\n") {
+		t.Errorf("restorations missing")
+	}
+	pdf = gofpdf.New("P", "pt", "A4", "")
+	pdf.AddPage()
+	pdf.ClipRect(0, 0, 100, 100, false)
+	pdf.PushState()
+	pdf.ClipEnd()
+	pdf.PopState()
+	if pdf.Error() == nil {
+		t.Errorf("clipping operation ended out of sequence accepted")
+	}
+	pdf = gofpdf.New("P", "pt", "A4", "")
+	pdf.AddPage()
+	pdf.PushState()
+	if err := pdf.Output(&bytes.Buffer{}); err == nil {
+		t.Errorf("unrestored graphics state accepted")
+	}
+}
+
+// ExampleFpdf_TransformMatrix demonstrates transformations composed with
+// MatrixType, here a fan of labels rotated around a common center.
+func ExampleFpdf_TransformMatrix() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	cx, cy := 105.0, 100.0
+	for j := 0; j < 12; j++ {
+		m := gofpdf.IdentityMatrix().Translate(-cx, -cy).Rotate(float64(j)*30).Scale(1, 0.6).Translate(cx, cy)
+		pdf.TransformBegin()
+		pdf.TransformMatrix(m)
+		pdf.SetFillColor(20*j, 100, 255-20*j)
+		pdf.Rect(cx+10, cy-3, 60, 6, "F")
+		pdf.Text(cx+72, cy+1.5, fmt.Sprintf("label %d", j+1))
+		pdf.TransformEnd()
+	}
+	// The inverse of the transformation maps a point of the page back to
+	// the coordinates of the content
+	pdf.TransformBegin()
+	pdf.TransformMatrix(gofpdf.IdentityMatrix().Skew(20, 0).Translate(0, 60))
+	inv, _ := pdf.GetTransform().Invert()
+	x, y := inv.TransformPoint(105, 250)
+	pdf.Circle(x, y, 10, "D")
+	pdf.TransformEnd()
+	fileStr := example.Filename("Fpdf_TransformMatrix")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_TransformMatrix.pdf
+}
+
+// TestMatrix verifies the elements of composed transformations, their
+// inversion and the tracking of the current transformation.
+func TestMatrix(t *testing.T) {
+	near := func(m, n gofpdf.MatrixType) bool {
+		a, b := []float64{m.A, m.B, m.C, m.D, m.E, m.F}, []float64{n.A, n.B, n.C, n.D, n.E, n.F}
+		for j := range a {
+			if math.Abs(a[j]-b[j]) > 1e-9 {
+				return false
+			}
+		}
+		return true
+	}
+	// Each method applies its transformation after that of the receiver
+	id := gofpdf.IdentityMatrix()
+	for j, c := range []struct{ got, want gofpdf.MatrixType }{
+		{gofpdf.MatrixType{A: 1, B: 2, C: 3, D: 4, E: 5, F: 6}.Multiply(gofpdf.MatrixType{A: 7, B: 8, C: 9, D: 10, E: 11, F: 12}),
+			gofpdf.MatrixType{A: 25, B: 28, C: 57, D: 64, E: 100, F: 112}},
+		{id.Translate(3, 4), gofpdf.MatrixType{A: 1, D: 1, E: 3, F: 4}},
+		{id.Scale(2, 3).Translate(3, 4), gofpdf.MatrixType{A: 2, D: 3, E: 3, F: 4}},
+		{id.Translate(3, 4).Scale(2, 3), gofpdf.MatrixType{A: 2, D: 3, E: 6, F: 12}},
+		{id.Rotate(90), gofpdf.MatrixType{B: -1, C: 1}},
+		{id.Translate(1, 0).Rotate(90), gofpdf.MatrixType{B: -1, C: 1, F: -1}},
+		{id.Skew(45, 0), gofpdf.MatrixType{A: 1, C: -1, D: 1}},
+		{id.Skew(0, 45), gofpdf.MatrixType{A: 1, B: -1, D: 1}},
+	} {
+		if !near(c.got, c.want) {
+			t.Errorf("case %d: matrix %v instead of %v", j, c.got, c.want)
+		}
+	}
+	m := gofpdf.IdentityMatrix().Translate(-10, -20).Rotate(90).Scale(2, 3).Translate(10, 20)
+	if x, y := m.TransformPoint(20, 20); math.Abs(x-10) > 1e-9 || math.Abs(y-(-10)) > 1e-9 {
+		t.Errorf("point (%.3f, %.3f) instead of (10, -10)", x, y)
+	}
+	inv, ok := m.Invert()
+	if !ok || !near(m.Multiply(inv), gofpdf.IdentityMatrix()) {
+		t.Errorf("inverse %v of %v", inv, m)
+	}
+	if _, ok = gofpdf.IdentityMatrix().Scale(0, 1).Invert(); ok {
+		t.Errorf("singular matrix inverted")
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.TransformBegin()
+	pdf.TransformRotate(30, 50, 60)
+	rot := gofpdf.IdentityMatrix().Translate(-50, -60).Rotate(30).Translate(50, 60)
+	if !near(pdf.GetTransform(), rot) {
+		t.Errorf("transform %v after TransformRotate instead of %v", pdf.GetTransform(), rot)
+	}
+	pdf.ClipRect(0, 0, 10, 10, false)
+	pdf.TransformBegin()
+	pdf.TransformMatrix(gofpdf.IdentityMatrix().Translate(5, 0))
+	if !near(pdf.GetTransform(), gofpdf.IdentityMatrix().Translate(5, 0).Multiply(rot)) {
+		t.Errorf("transform %v not composed", pdf.GetTransform())
+	}
+	pdf.TransformEnd()
+	pdf.ClipEnd()
+	if !near(pdf.GetTransform(), rot) {
+		t.Errorf("transform %v not restored", pdf.GetTransform())
+	}
+	pdf.TransformEnd()
+	if !near(pdf.GetTransform(), gofpdf.IdentityMatrix()) {
+		t.Errorf("transform %v not restored to identity", pdf.GetTransform())
+	}
+	pdf.TransformBegin()
+	pdf.TransformMatrix(gofpdf.IdentityMatrix().Translate(10, 20))
+	pdf.TransformEnd()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "1.00000 0.00000 0.00000 1.00000 28.34646 -56.69291 cm") {
+		t.Errorf("translation matrix missing")
+	}
+}
+
+// ExampleFpdf_SetLineDecoration demonstrates arrowheads and markers drawn
+// along lines, polygons and paths, as in flow diagrams and dimension lines.
+func ExampleFpdf_SetLineDecoration() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetLineWidth(0.4)
+	// Flow diagram
+	box := func(x, y float64, txtStr string) {
+		pdf.SetXY(x, y)
+		pdf.CellFormat(40, 12, txtStr, "1", 0, "C", false, 0, "")
+	}
+	box(20, 20, "Start")
+	box(85, 20, "Process")
+	box(150, 20, "End")
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{End: "arrow", Size: 3})
+	pdf.Line(60, 26, 85, 26)
+	pdf.Line(125, 26, 150, 26)
+	var back gofpdf.PathType
+	back.MoveTo(170, 32)
+	back.CubicTo(170, 60, 40, 60, 40, 32)
+	pdf.SetDrawColor(0, 90, 180)
+	pdf.Path(back, "D")
+	// Dimension line
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.2)
+	pdf.Rect(20, 80, 120, 40, "D")
+	pdf.Line(20, 122, 20, 132)
+	pdf.Line(140, 122, 140, 132)
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{Start: "arrow", End: "arrow", Size: 3})
+	pdf.Line(20, 128, 140, 128)
+	pdf.Text(74, 126, "120 mm")
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{Start: "bar", End: "bar", Size: 4})
+	pdf.Line(150, 80, 150, 120)
+	// Markers
+	pdf.SetLineWidth(0.3)
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{Marker: "dot", MarkerSize: 1.5, MarkerSpacing: 8})
+	pdf.Polygon([]gofpdf.PointType{{X: 30, Y: 150}, {X: 90, Y: 150}, {X: 60, Y: 200}}, "D")
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{Start: "dot", End: "open", Size: 4,
+		Marker: "tick", MarkerSize: 3, MarkerSpacing: 5})
+	var wave gofpdf.PathType
+	wave.MoveTo(110, 175)
+	wave.QuadTo(130, 145, 150, 175)
+	wave.QuadTo(170, 205, 190, 175)
+	pdf.Path(wave, "D")
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{})
+	fileStr := example.Filename("Fpdf_SetLineDecoration")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetLineDecoration.pdf
+}
+
+// TestLineDecoration verifies that lines are shortened under arrowheads and
+// that markers are spaced along them.
+func TestLineDecoration(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetDrawColor(255, 0, 0)
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{End: "arrow", Size: 10})
+	pdf.Line(0, 100, 100, 100)
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{Marker: "tick", MarkerSize: 4, MarkerSpacing: 30})
+	pdf.Line(0, 200, 100, 200)
+	pdf.Polygon([]gofpdf.PointType{{X: 0, Y: 300}, {X: 100, Y: 300}, {X: 100, Y: 400}}, "F")
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{})
+	pdf.Line(0, 500, 100, 500)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	h := 841.89
+	for _, str := range []string{
+		// The line ends at the middle of the arrowhead, which is filled
+		// with the draw color
+		fmt.Sprintf("0.00000 %.5f m 95.00000 %.5f l S", h-100, h-100),
+		fmt.Sprintf("q [] 0 d 1.000 0.000 0.000 rg\n100.00000 %.5f m 90.00000 %.5f l 90.00000 %.5f l h f", h-100, h-103.33333, h-96.66667),
+		// Ticks at 30, 60 and 90
+		fmt.Sprintf("30.00000 %.5f m 30.00000 %.5f l 60.00000 %.5f m 60.00000 %.5f l 90.00000 %.5f m 90.00000 %.5f l S",
+			h-198, h-202, h-198, h-202, h-198, h-202),
+		"0.00 341.89 m 100.00 341.89 l S",
+	} {
+		if !strings.Contains(s, str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	if strings.Count(s, "q [] 0 d") != 2 {
+		t.Errorf("filled polygon decorated")
+	}
+	pdf.SetLineDecoration(gofpdf.LineDecorationType{Marker: "dot"})
+	if pdf.Error() == nil {
+		t.Errorf("marker without spacing accepted")
+	}
+}
+
+// ExampleFpdf_SuperellipseRect demonstrates cards with asymmetric rounded
+// corners shaped as quarters of superellipses, and superellipses of various
+// exponents.
+func ExampleFpdf_SuperellipseRect() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetFillColor(230, 236, 250)
+	pdf.SetDrawColor(90, 110, 160)
+	for j, n := range []float64{2, 4, 8} {
+		x := 15 + float64(j)*62
+		pdf.SuperellipseRect(x, 20, 55, 80, 12, 12, 2, 12, n, "FD")
+		pdf.Text(x+5, 30, fmt.Sprintf("Corners of exponent %g", n))
+	}
+	pdf.RoundedRectExt(15, 110, 55, 40, 12, 12, 2, 12, "D")
+	pdf.Text(20, 120, "RoundedRectExt()")
+	for j, n := range []float64{0.7, 1, 2, 3, 5, 20} {
+		pdf.Superellipse(30+float64(j)*30, 180, 13, 13, n, "FD")
+		pdf.Text(24+float64(j)*30, 200, fmt.Sprintf("n = %g", n))
+	}
+	fileStr := example.Filename("Fpdf_SuperellipseRect")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SuperellipseRect.pdf
+}
+
+// TestSuperellipse verifies that superellipses pass through their points at
+// multiples of 45 degrees and that corner radii are scaled to fit the sides.
+func TestSuperellipse(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.Superellipse(100, 100, 100, 50, 4, "D")
+	pdf.SuperellipseRect(0, 300, 100, 50, 10, 0, 20, 60, 4, "D")
+	pdf.Superellipse(0, 0, 10, 10, 0, "D")
+	if pdf.Error() == nil {
+		t.Errorf("exponent 0 accepted")
+	}
+	pdf.ClearError()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	h := 841.89
+	d := math.Pow(math.Sqrt2/2, 0.5)
+	// The left side is 50 long and its corners of radii 60 and 10 are scaled
+	// by 5/7
+	for _, str := range []string{
+		fmt.Sprintf("200.00000 %.5f m", h-100),
+		fmt.Sprintf("%.5f %.5f c", 100+100*d, h-(100-50*d)),
+		fmt.Sprintf("0.00000 %.5f c", h-100),
+		fmt.Sprintf("%.5f %.5f m %.5f %.5f l", 50.0/7, h-300, 100.0, h-300),
+		fmt.Sprintf("%.5f %.5f l", 300.0/7, h-350),
+	} {
+		if !strings.Contains(s, str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+}
+
+// ExamplePathType_Union demonstrates boolean operations on paths: a label
+// filled except for a keep-out area around a logo, the union of overlapping
+// circles outlined as one shape, and their intersection.
+func ExamplePathType_Union() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	var label, keepOut gofpdf.PathType
+	label.RoundedRect(20, 20, 100, 60, 6, 6, 6, 6, 2)
+	keepOut.Ellipse(90, 50, 22, 22)
+	pdf.SetFillColor(250, 200, 60)
+	pdf.Path(label.Difference(keepOut), "FD")
+	pdf.ImageOptions(example.ImageFile("logo.png"), 76, 36, 28, 0, false, gofpdf.ImageOptions{}, 0, "")
+	var a, b, c gofpdf.PathType
+	a.Ellipse(50, 140, 25, 25)
+	b.Ellipse(80, 140, 25, 25)
+	c.Polygon([]gofpdf.PointType{{X: 65, Y: 100}, {X: 95, Y: 175}, {X: 35, Y: 175}})
+	pdf.SetLineWidth(1)
+	pdf.SetFillColor(180, 210, 250)
+	pdf.Path(a.Union(b).Union(c), "FD")
+	pdf.SetFillColor(60, 90, 180)
+	pdf.Path(a.Intersection(b).Difference(c), "F")
+	fileStr := example.Filename("PathType_Union")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/PathType_Union.pdf
+}
+
+// TestPathBoolean verifies the areas and subpaths of the results of boolean
+// operations on paths with overlapping, touching and coincident edges.
+func TestPathBoolean(t *testing.T) {
+	opRe := regexp.MustCompile(`(-?[\d.]+) (-?[\d.]+) ([ml])|(h)`)
+	// measure returns the area and number of subpaths of the path
+	measure := func(path gofpdf.PathType) (area float64, subpaths int) {
+		pdf := gofpdf.New("P", "pt", "A4", "")
+		pdf.SetCompression(false)
+		pdf.AddPage()
+		pdf.Path(path, "F")
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		s := buf.String()
+		s = s[strings.Index(s, "stream\n"):strings.Index(s, "endstream")]
+		var pts []gofpdf.PointType
+		for _, m := range opRe.FindAllStringSubmatch(s, -1) {
+			if m[4] == "h" {
+				for j, pt := range pts {
+					next := pts[(j+1)%len(pts)]
+					area += (pt.X*next.Y - next.X*pt.Y) / 2
+				}
+				pts = nil
+				subpaths++
+				continue
+			}
+			x, _ := strconv.ParseFloat(m[1], 64)
+			y, _ := strconv.ParseFloat(m[2], 64)
+			pts = append(pts, gofpdf.PointType{X: x, Y: y})
+		}
+		return math.Abs(area), subpaths
+	}
+	rect := func(x, y, w, h float64) (p gofpdf.PathType) {
+		p.Rect(x, y, w, h)
+		return
+	}
+	a, b := rect(0, 0, 10, 10), rect(5, 5, 10, 10)
+	side, below := rect(10, 0, 10, 10), rect(0, 10, 10, 10)
+	var circle gofpdf.PathType
+	circle.Ellipse(50, 50, 40, 40)
+	for j, c := range []struct {
+		path     gofpdf.PathType
+		area     float64
+		subpaths int
+	}{
+		{a.Union(b), 175, 1},
+		{a.Intersection(b), 25, 1},
+		{a.Difference(b), 75, 1},
+		{b.Difference(a), 75, 1},
+		{a.Union(side), 200, 1},
+		{a.Union(side).Union(below), 300, 1},
+		{a.Intersection(side), 0, 0},
+		{a.Union(a), 100, 1},
+		{a.Difference(a), 0, 0},
+		{rect(0, 0, 100, 100).Difference(rect(40, 40, 20, 20)), 9600, 2},
+		{rect(0, 0, 100, 100).Difference(rect(0, 40, 20, 20)), 9600, 1},
+		{rect(0, 0, 100, 100).Intersection(circle), math.Pi * 1600, 1},
+		{rect(0, 0, 50, 100).Intersection(circle), math.Pi * 800, 1},
+	} {
+		area, subpaths := measure(c.path)
+		if math.Abs(area-c.area) > c.area*0.002+1e-3 || subpaths != c.subpaths {
+			t.Errorf("case %d: area %.3f in %d subpaths instead of %.3f in %d", j, area, subpaths,
+				c.area, c.subpaths)
+		}
+	}
+}
+
+// ExampleFpdf_CurveThroughPoints demonstrates smooth curves drawn through the
+// points of a chart line with various tensions, and a closed curve.
+func ExampleFpdf_CurveThroughPoints() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 10)
+	var pts []gofpdf.PointType
+	for j, v := range []float64{20, 45, 30, 70, 55, 85, 60, 40} {
+		pts = append(pts, gofpdf.PointType{X: 20 + float64(j)*24, Y: 110 - v})
+	}
+	pdf.SetLineWidth(0.5)
+	for j, tension := range []float64{1, 0.5, 0} {
+		clr := [][3]int{{180, 180, 180}, {90, 160, 90}, {40, 80, 200}}[j]
+		pdf.SetDrawColor(clr[0], clr[1], clr[2])
+		pdf.CurveThroughPoints(pts, tension, false, "D")
+		pdf.Text(20+float64(j)*40, 120, fmt.Sprintf("tension %g", tension))
+	}
+	for _, pt := range pts {
+		pdf.Circle(pt.X, pt.Y, 1, "F")
+	}
+	pdf.SetFillColor(250, 220, 200)
+	pdf.SetDrawColor(200, 80, 40)
+	pdf.CurveThroughPoints([]gofpdf.PointType{{X: 60, Y: 150}, {X: 110, Y: 140}, {X: 140, Y: 180},
+		{X: 100, Y: 220}, {X: 50, Y: 200}}, 0, true, "FD")
+	fileStr := example.Filename("Fpdf_CurveThroughPoints")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_CurveThroughPoints.pdf
+}
+
+// TestCurveThroughPoints verifies the control points of cardinal splines.
+func TestCurveThroughPoints(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pts := []gofpdf.PointType{{X: 0, Y: 0}, {X: 10, Y: 10}, {X: 20, Y: 0}}
+	pdf.CurveThroughPoints(pts, 0, false, "D")
+	pdf.CurveThroughPoints(pts, 1, false, "D")
+	pdf.CurveThroughPoints(pts, 0, true, "F")
+	pdf.CurveThroughPoints(pts[:1], 0, false, "D")
+	if pdf.Error() == nil {
+		t.Errorf("curve through a single point accepted")
+	}
+	pdf.ClearError()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	h := 841.89
+	for _, str := range []string{
+		// The tangent at the first point is toward the second one, and at
+		// the second point parallel to the line joining the others
+		fmt.Sprintf("0.00000 %.5f m 1.66667 %.5f 6.66667 %.5f 10.00000 %.5f c", h, h-1.66667, h-10, h-10),
+		fmt.Sprintf("0.00000 %.5f m 0.00000 %.5f 10.00000 %.5f 10.00000 %.5f c", h, h, h-10, h-10),
+		// The tangent at the first point of a closed curve is parallel to
+		// the line joining the last and second points
+		fmt.Sprintf("0.00000 %.5f m -1.66667 %.5f", h, h-1.66667),
+		"c h f",
+	} {
+		if !strings.Contains(s, str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+}
+
+// ExampleFpdf_Star demonstrates regular polygons and a rating made of stars.
+func ExampleFpdf_Star() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFillColor(200, 220, 250)
+	for j := 3; j <= 8; j++ {
+		pdf.RegularPolygon(float64(j-2)*30, 40, 12, j, 0, "FD")
+	}
+	pdf.RegularPolygon(30, 80, 12, 4, 45, "FD")
+	pdf.SetFillColor(250, 190, 30)
+	pdf.SetDrawColor(200, 140, 0)
+	for j := 0; j < 5; j++ {
+		style := "FD"
+		if j >= 4 {
+			style = "D"
+		}
+		pdf.Star(70+float64(j)*20, 80, 9, 9*0.382, 5, style)
+	}
+	pdf.SetFillColor(220, 60, 60)
+	pdf.Star(40, 130, 25, 18, 16, "F")
+	pdf.Star(110, 130, 25, 8, 4, "F")
+	fileStr := example.Filename("Fpdf_Star")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Star.pdf
+}
+
+// TestRegularPolygon verifies the vertices of regular polygons and stars.
+func TestRegularPolygon(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.RegularPolygon(100, 100, 10, 4, 0, "D")
+	pdf.Star(200, 100, 10, 5, 2, "F")
+	pdf.RegularPolygon(100, 100, 10, 2, 0, "D")
+	if pdf.Error() == nil {
+		t.Errorf("polygon of 2 sides accepted")
+	}
+	pdf.ClearError()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	h := 841.89
+	for _, str := range []string{
+		fmt.Sprintf("100.00000 %.5f m 90.00000 %.5f l 100.00000 %.5f l 110.00000 %.5f l h S",
+			h-90, h-100, h-110, h-100),
+		fmt.Sprintf("200.00000 %.5f m 195.00000 %.5f l 200.00000 %.5f l 205.00000 %.5f l h f",
+			h-90, h-100, h-110, h-100),
+	} {
+		if !strings.Contains(s, str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+}
+
+// ExampleFpdf_BeginShadow demonstrates drop shadows cast by a card, a star and
+// a caption.
+func ExampleFpdf_BeginShadow() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.AddPage()
+	pdf.SetFillColor(235, 240, 245)
+	pdf.Rect(10, 10, 190, 200, "F")
+	// A card with a soft shadow, with its title and text
+	pdf.BeginShadow(gofpdf.ShadowType{OffsetX: 2, OffsetY: 3, Blur: 4, Alpha: 0.5})
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SuperellipseRect(25, 25, 80, 60, 6, 6, 6, 6, 4, "F")
+	pdf.EndShadow()
+	pdf.SetXY(30, 30)
+	pdf.Cell(70, 10, "Lifted card")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.SetXY(30, 45)
+	pdf.MultiCell(70, 5, "The shadow of the card fades out over its edge, "+
+		"and the card is painted once over it.", "", "L", false)
+	// A star with a sharp colored shadow
+	pdf.BeginShadow(gofpdf.ShadowType{OffsetX: 3, OffsetY: 3, Color: gofpdf.RGBType{R: 200, G: 120, B: 0}, Alpha: 0.8})
+	pdf.SetFillColor(250, 200, 40)
+	pdf.Star(150, 55, 28, 12, 5, "F")
+	pdf.EndShadow()
+	// A caption whose letters cast the shadow
+	pdf.SetFont("Helvetica", "B", 48)
+	pdf.BeginShadow(gofpdf.ShadowType{OffsetX: 1, OffsetY: 1.5, Blur: 1.5, Alpha: 0.6})
+	pdf.SetTextColor(40, 90, 170)
+	pdf.Text(30, 140, "Shadowed text")
+	pdf.EndShadow()
+	fileStr := example.Filename("Fpdf_BeginShadow")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_BeginShadow.pdf
+}
+
+// TestShadow verifies the layers and the alpha soft mask of shadows and the
+// checking of their nesting.
+func TestShadow(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.BeginShadow(gofpdf.ShadowType{OffsetX: 2, OffsetY: 3, Color: gofpdf.RGBType{R: 255}, Alpha: 0.5})
+	pdf.Rect(1, 2, 3, 4, "F")
+	pdf.EndShadow()
+	pdf.BeginShadow(gofpdf.ShadowType{Blur: 4, Alpha: 0.5})
+	pdf.Rect(5, 6, 7, 8, "F")
+	pdf.EndShadow()
+	pdf.BeginShadow(gofpdf.ShadowType{})
+	pdf.EndTransparencyGroup()
+	if pdf.Error() == nil {
+		t.Errorf("shadow ended as a transparency group")
+	}
+	pdf.ClearError()
+	pdf.EndShadow()
+	pdf.BeginShadow(gofpdf.ShadowType{Alpha: 2})
+	if pdf.Error() == nil {
+		t.Errorf("shadow alpha of 2 accepted")
+	}
+	pdf.ClearError()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	for _, str := range []string{
+		"q 1.000 0.000 0.000 rg\nq 1 0 0 1 2.00 -3.00 cm /SM1 gs /GS1 gs 0 0 595.28 841.89 re f Q\nQ\n/TG1 Do",
+		"/SMask <</Type /Mask /S /Alpha /G ",
+		"/ca 0.500 /CA 0.500 /BM /Normal",
+		"/ca 0.032 /CA 0.032 /BM /Normal",
+	} {
+		if !strings.Contains(s, str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	// The blurred shadow is made of 21 layers
+	if n := strings.Count(s, "/SM2 gs"); n != 21 {
+		t.Errorf("%d layers of the blurred shadow, 21 expected", n)
+	}
+}
+
+// ExampleFpdf_MeasureBounds demonstrates a frame drawn around content whose
+// size is measured beforehand.
+func ExampleFpdf_MeasureBounds() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	content := func() {
+		pdf.SetFillColor(250, 190, 30)
+		pdf.Star(40, 40, 15, 6, 5, "F")
+		pdf.SetXY(60, 30)
+		pdf.MultiCell(60, 6, "The frame around this text and the star "+
+			"is drawn from their bounding box, measured before they are "+
+			"drawn.", "", "L", false)
+	}
+	x, y, w, h := pdf.MeasureBounds(content)
+	pdf.SetFillColor(230, 240, 250)
+	pdf.SetDrawColor(40, 90, 170)
+	pdf.RoundedRect(x-4, y-4, w+8, h+8, 4, "1234", "FD")
+	content()
+	// A rotated label, whose frame is upright around it
+	pdf.SetFont("Helvetica", "B", 20)
+	label := func() {
+		pdf.TransformBegin()
+		pdf.TransformRotate(20, 50, 120)
+		pdf.Text(50, 120, "Rotated label")
+		pdf.TransformEnd()
+	}
+	x, y, w, h = pdf.MeasureBounds(label)
+	pdf.Rect(x, y, w, h, "D")
+	label()
+	fileStr := example.Filename("Fpdf_MeasureBounds")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_MeasureBounds.pdf
+}
+
+// TestMeasureBounds verifies the bounds of drawings and text, the effect of
+// transformations and clipping on them, and that a measurement leaves no
+// trace.
+func TestMeasureBounds(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.AddPage()
+	pdf.SetXY(15, 25)
+	pdf.SetFillColor(10, 20, 30)
+	check := func(nameStr string, fnc func(), bx, by, bw, bh float64) {
+		x, y, w, h := pdf.MeasureBounds(fnc)
+		for _, d := range []float64{x - bx, y - by, w - bw, h - bh} {
+			if math.Abs(d) > 0.01 {
+				t.Errorf("%s: bounds %.4f %.4f %.4f %.4f, expected %.4f %.4f %.4f %.4f",
+					nameStr, x, y, w, h, bx, by, bw, bh)
+				break
+			}
+		}
+	}
+	check("rectangle", func() {
+		pdf.SetFillColor(200, 0, 0)
+		pdf.Rect(10, 20, 30, 40, "F")
+	}, 10, 20, 30, 40)
+	check("stroke", func() {
+		pdf.SetLineWidth(2)
+		pdf.Rect(10, 20, 30, 40, "D")
+	}, 9, 19, 32, 42)
+	check("curve", func() {
+		pdf.CurveBezierCubic(10, 50, 10, 30, 50, 30, 50, 50, "F")
+	}, 10, 35, 40, 15)
+	check("rotation", func() {
+		pdf.TransformBegin()
+		pdf.TransformRotate(90, 25, 40)
+		pdf.Rect(10, 20, 30, 40, "F")
+		pdf.TransformEnd()
+	}, 5, 25, 40, 30)
+	check("clipping", func() {
+		pdf.ClipRect(0, 0, 15, 100, false)
+		pdf.Rect(10, 20, 30, 40, "F")
+		pdf.ClipEnd()
+	}, 10, 20, 5, 40)
+	check("shadow", func() {
+		pdf.BeginShadow(gofpdf.ShadowType{OffsetX: 2, OffsetY: 3, Alpha: 0.5})
+		pdf.Rect(10, 20, 30, 40, "F")
+		pdf.EndShadow()
+	}, 10, 20, 32, 43)
+	m := pdf.FontMetrics("", "")
+	check("text", func() {
+		pdf.Text(10, 50, "Hello")
+	}, 10, 50-m.Ascent, pdf.GetStringWidth("Hello"), m.Ascent-m.Descent)
+	check("nothing", func() {}, 0, 0, 0, 0)
+	if r, g, b := pdf.GetFillColor(); r != 10 || g != 20 || b != 30 {
+		t.Errorf("fill color %d %d %d after measurement", r, g, b)
+	}
+	if x, y := pdf.GetXY(); x != 15 || y != 25 {
+		t.Errorf("position %.2f %.2f after measurement", x, y)
+	}
+	pdf.MeasureBounds(func() {
+		pdf.ClipRect(0, 0, 15, 100, false)
+	})
+	if pdf.Error() == nil {
+		t.Errorf("clipping operation left begun by measurement accepted")
+	}
+	pdf.ClearError()
+	pdf.ClipEnd()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// The group of the shadow is output, but not painted
+	if s := buf.String(); strings.Contains(s, "/TG1 Do") || strings.Contains(s, "Hello") {
+		t.Errorf("measured content output")
+	}
+}
+
+// ExampleFpdf_UseSymbol demonstrates map markers defined once as a symbol and
+// drawn at many places, sizes and angles.
+func ExampleFpdf_UseSymbol() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "B", 6)
+	pdf.AddPage()
+	// A pin whose tip is at the origin of the symbol
+	pdf.DefineSymbol("pin", func() {
+		var path gofpdf.PathType
+		path.MoveTo(0, 0)
+		path.CubicTo(-6, -8, -5, -14, 0, -14)
+		path.CubicTo(5, -14, 6, -8, 0, 0)
+		path.Close()
+		pdf.SetFillColor(220, 50, 50)
+		pdf.SetDrawColor(120, 20, 20)
+		pdf.SetLineWidth(0.4)
+		pdf.Path(path, "FD")
+		pdf.SetFillColor(255, 255, 255)
+		pdf.Circle(0, -9, 2, "F")
+	})
+	pdf.DefineSymbol("label", func() {
+		pdf.SetTextColor(40, 40, 40)
+		pdf.Text(-4, 2, "HERE")
+	})
+	pdf.SetFillColor(225, 235, 215)
+	pdf.Rect(10, 10, 190, 140, "F")
+	for j := 0; j < 12; j++ {
+		x, y := 25+float64(j%4)*45, 40+float64(j/4)*40
+		pdf.UseSymbol("pin", x, y, 0.6+float64(j)*0.05, 0)
+		pdf.UseSymbol("label", x, y, 1, 0)
+	}
+	// A pin knocked over, and one mirrored and skewed
+	pdf.UseSymbol("pin", 40, 180, 1, 90)
+	pdf.UseSymbolTransform("pin", gofpdf.IdentityMatrix().Skew(30, 0).Scale(1, -1).Translate(100, 170))
+	fileStr := example.Filename("Fpdf_UseSymbol")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_UseSymbol.pdf
+}
+
+// TestSymbol verifies that a symbol is output once as a form XObject, its
+// bounding box and the transformations of its uses.
+func TestSymbol(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.SetFillColor(10, 20, 30)
+	pdf.DefineSymbol("dot", func() {
+		pdf.SetFillColor(255, 0, 0)
+		pdf.Rect(-5, -5, 10, 10, "F")
+	})
+	if r, g, b := pdf.GetFillColor(); r != 10 || g != 20 || b != 30 {
+		t.Errorf("fill color %d %d %d after the definition of a symbol", r, g, b)
+	}
+	pdf.UseSymbol("dot", 100, 200, 1, 0)
+	pdf.UseSymbol("dot", 300, 400, 2, 90)
+	pdf.AddPageFormat("P", gofpdf.SizeType{Wd: 300, Ht: 400})
+	pdf.UseSymbol("dot", 100, 200, 1, 0)
+	pdf.UseSymbol("missing", 0, 0, 1, 0)
+	if pdf.Error() == nil {
+		t.Errorf("undefined symbol accepted")
+	}
+	pdf.ClearError()
+	pdf.DefineSymbol("dot", func() {})
+	if pdf.Error() == nil {
+		t.Errorf("symbol defined twice accepted")
+	}
+	pdf.ClearError()
+	// The symbol is measured like other content
+	x, y, w, h := pdf.MeasureBounds(func() {
+		pdf.UseSymbol("dot", 100, 200, 2, 0)
+	})
+	if math.Abs(x-90) > 0.01 || math.Abs(y-190) > 0.01 || math.Abs(w-20) > 0.01 || math.Abs(h-20) > 0.01 {
+		t.Errorf("bounds of symbol %.2f %.2f %.2f %.2f", x, y, w, h)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	h1 := 841.89
+	for _, str := range []string{
+		fmt.Sprintf("/BBox [-6.00 %.2f 6.00 %.2f]", h1-6, h1+6),
+		"/XObject <<\n/SY1 ",
+		"q 1.00000 0.00000 0.00000 1.00000 100.00000 -200.00000 cm /SY1 Do Q",
+		fmt.Sprintf("q 0.00000 2.00000 -2.00000 0.00000 %.5f %.5f cm /SY1 Do Q", 300+2*h1, h1-400),
+		// On a page of another height, the symbol is moved along
+		fmt.Sprintf("q 1.00000 0.00000 0.00000 1.00000 100.00000 %.5f cm /SY1 Do Q", 400-200-h1),
+	} {
+		if !strings.Contains(s, str) {
+			t.Errorf("%q missing", str)
+		}
+	}
+	if n := strings.Count(s, "/Subtype /Form"); n != 1 {
+		t.Errorf("%d form XObjects, 1 expected", n)
+	}
+}
+
+// ExampleFpdf_RegisterImageURL demonstrates the registration of an image
+// fetched with a deadline and a size limit. A local server stands in for a
+// remote one.
+func ExampleFpdf_RegisterImageURL() {
+	srv := httptest.NewServer(http.FileServer(http.Dir(example.ImageFile(""))))
+	defer srv.Close()
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pdf.RegisterImageURL(ctx, "logo", srv.URL+"/logo.png", gofpdf.ImageOptions{MaxSize: 1 << 20})
+	pdf.Image("logo", 10, 10, 30, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_RegisterImageURL")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RegisterImageURL.pdf
+}
+
+// TestRegisterImageURL verifies that images fetched with RegisterImageURL()
+// are limited in size whether or not the server announces their length, and
+// that failed requests are reported.
+func TestRegisterImageURL(t *testing.T) {
+	data, err := ioutil.ReadFile(example.ImageFile("logo.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/length.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Write(data)
+		case "/chunked.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			w.Write(data[len(data)/2:])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	for _, c := range []struct {
+		path    string
+		maxSize int64
+		ok      bool
+	}{
+		{"/length.png", 0, true},
+		{"/chunked.png", 0, true},
+		{"/length.png", int64(len(data)), true},
+		{"/chunked.png", int64(len(data)), true},
+		{"/length.png", int64(len(data)) - 1, false},
+		{"/chunked.png", int64(len(data)) - 1, false},
+		{"/missing.png", 0, false},
+	} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		info := pdf.RegisterImageURL(ctx, "logo", srv.URL+c.path, gofpdf.ImageOptions{MaxSize: c.maxSize})
+		if c.ok && (info == nil || pdf.Err()) {
+			t.Errorf("%s with limit %d: %v", c.path, c.maxSize, pdf.Error())
+		} else if !c.ok && !pdf.Err() {
+			t.Errorf("%s with limit %d accepted", c.path, c.maxSize)
+		}
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	pdf.RegisterImageURL(canceled, "logo", srv.URL+"/length.png", gofpdf.ImageOptions{})
+	if !pdf.Err() {
+		t.Error("canceled request accepted")
+	}
+}
+
+// TestJpegFilePassThrough verifies that, with pass-through enabled, the data
+// of a JPEG file is copied from the file when the document is written rather
+// than held in memory from the time the image is registered, and that the
+// document fails if the file has changed by then.
+func TestJpegFilePassThrough(t *testing.T) {
+	data, err := ioutil.ReadFile(example.ImageFile("logo.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "gofpdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileStr := filepath.Join(dir, "logo.jpg")
+	for _, protect := range []bool{false, true} {
+		if err = ioutil.WriteFile(fileStr, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetImagePassThrough(true)
+		if protect {
+			pdf.SetProtection(0, "", "owner")
+		}
+		pdf.AddPage()
+		pdf.Image(fileStr, 10, 10, 30, 0, false, "", 0, "")
+		pdf.Image(fileStr, 50, 10, 30, 0, false, "", 0, "")
+		var buf bytes.Buffer
+		if err = pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if n := strings.Count(buf.String(), string(data)); protect && n != 0 || !protect && n != 1 {
+			t.Errorf("JPEG data found %d times with protection %v", n, protect)
+		}
+	}
+	// generate registers the image with or without pass-through, changes the
+	// file with change and writes the document
+	generate := func(passThrough bool, change func() error) (string, error) {
+		if err := ioutil.WriteFile(fileStr, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetImagePassThrough(passThrough)
+		pdf.AddPage()
+		pdf.Image(fileStr, 10, 10, 30, 0, false, "", 0, "")
+		if err := change(); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		err := pdf.Output(&buf)
+		return buf.String(), err
+	}
+	remove := func() error { return os.Remove(fileStr) }
+	// Without pass-through, the file may be removed once the image is
+	// registered
+	if str, err := generate(false, remove); err != nil || !strings.Contains(str, string(data)) {
+		t.Errorf("image data not held in memory: %v", err)
+	}
+	// With pass-through, the file is read again when the document is written
+	if _, err := generate(true, remove); err == nil {
+		t.Error("image data held in memory")
+	}
+	changed := append([]byte(nil), data...)
+	changed[len(changed)-3] ^= 0xFF
+	for _, content := range [][]byte{changed, data[:len(data)-10], append(changed, 0, 0)} {
+		if _, err := generate(true, func() error { return ioutil.WriteFile(fileStr, content, 0600) }); err == nil {
+			t.Errorf("change of %d byte file to %d bytes not detected", len(data), len(content))
+		}
+	}
+}
+
+// countingReaderAt counts the bytes read from a reader and fails once it has
+// been closed.
+type countingReaderAt struct {
+	r      *bytes.Reader
+	n      int64
+	closed bool
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if c.closed {
+		return 0, os.ErrClosed
+	}
+	n, err := c.r.ReadAt(p, off)
+	c.n += int64(n)
+	return n, err
+}
+
+// TestAddUTF8FontFromReaderAt verifies that a font added from an io.ReaderAt
+// is read only in part, that a font added from an io.Reader is spooled to a
+// temporary file, and that both produce the same document as the font added
+// from bytes.
+func TestAddUTF8FontFromReaderAt(t *testing.T) {
+	data, err := ioutil.ReadFile(example.FontFile("DejaVuSansCondensed.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	generate := func(add func(pdf *gofpdf.Fpdf)) (*gofpdf.Fpdf, []byte) {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCreationDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		pdf.SetModificationDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		add(pdf)
+		pdf.AddPage()
+		pdf.SetFont("dejavu", "", 16)
+		pdf.Cell(0, 10, "Здравствуйте, Γειά σας")
+		var buf bytes.Buffer
+		pdf.Output(&buf)
+		return pdf, buf.Bytes()
+	}
+	_, want := generate(func(pdf *gofpdf.Fpdf) {
+		pdf.AddUTF8FontFromBytes("dejavu", "", data)
+	})
+	r := &countingReaderAt{r: bytes.NewReader(data)}
+	pdf, got := generate(func(pdf *gofpdf.Fpdf) {
+		pdf.AddUTF8FontFromReaderAt("dejavu", "", r, int64(len(data)))
+		if r.n >= int64(len(data))/2 {
+			t.Errorf("%d of %d bytes read when adding font", r.n, len(data))
+		}
+	})
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("document differs from the one with the font added from bytes")
+	}
+	// The font is read again when the document is written
+	r = &countingReaderAt{r: bytes.NewReader(data)}
+	pdf, _ = generate(func(pdf *gofpdf.Fpdf) {
+		pdf.AddUTF8FontFromReaderAt("dejavu", "", r, int64(len(data)))
+		r.closed = true
+	})
+	if !pdf.Err() {
+		t.Error("font held in memory")
+	}
+	// A font read from a stream is spooled to a temporary file, which is
+	// removed when the document is closed
+	dir, err := ioutil.TempDir("", "gofpdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tmpStr := os.Getenv("TMPDIR")
+	os.Setenv("TMPDIR", dir)
+	defer os.Setenv("TMPDIR", tmpStr)
+	pdf, got = generate(func(pdf *gofpdf.Fpdf) {
+		pdf.AddUTF8FontFromReader("dejavu", "", ioutil.NopCloser(bytes.NewReader(data)))
+		if files, _ := ioutil.ReadDir(dir); len(files) != 1 || files[0].Size() != int64(len(data)) {
+			t.Errorf("font not spooled to a temporary file")
+		}
+	})
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("document differs from the one with the font added from bytes")
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d temporary files left", len(files))
+	}
+}
+
+// TestComplexShaping verifies that Devanagari and Thai text is shaped with
+// the GSUB table of the font, and left as is unless shaping is enabled. The
+// test font maps the characters to Latin glyphs, and its GSUB table forms a
+// conjunct, a reph, a half form and a below-base ra, and substitutes a tone
+// mark after a tall consonant.
+func TestComplexShaping(t *testing.T) {
+	text := []string{
+		"\u0915\u094D\u0937",       // Conjunct kssa
+		"\u0930\u094D\u0915",       // Reph
+		"\u0915\u093F",             // Short i moved before ka
+		"\u0915\u094D\u092E",       // Half ka
+		"\u0915\u094D\u0930",       // Below-base ra
+		"\u0930\u094D\u0915\u093F", // Reph and short i
+		"\u0E1B\u0E48",             // Mai ek after po pla
+		"\u0E01\u0E48",             // Mai ek after ko kai
+		"\u0E01\u0E48\u0E33",       // Sara am after mai ek
+	}
+	generate := func(shaping bool) string {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(false)
+		pdf.SetTextShaping(shaping)
+		pdf.AddUTF8Font("calligra", "", example.FontFile("calligra-shaping.ttf"))
+		pdf.SetFont("calligra", "", 12)
+		pdf.AddPage()
+		for _, s := range text {
+			pdf.Cell(0, 10, s)
+			pdf.Ln(10)
+		}
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	// char returns the encoding of the characters
+	char := func(chars string) (s string) {
+		for _, r := range chars {
+			s += string([]byte{byte(r >> 8), byte(r)})
+		}
+		return
+	}
+	// glyph returns the encoding of the code assigned to the glyph that
+	// text extracted from the document maps to the characters
+	glyph := func(doc, chars string) string {
+		var hex string
+		for _, r := range chars {
+			hex += fmt.Sprintf("%04X", r)
+		}
+		m := regexp.MustCompile(`<([0-9A-F]{4})> <` + hex + `>`).FindStringSubmatch(doc)
+		if m == nil {
+			t.Fatalf("no glyph for %q", chars)
+		}
+		c, _ := strconv.ParseUint(m[1], 16, 16)
+		return string([]byte{byte(c >> 8), byte(c)})
+	}
+	doc := generate(true)
+	want := []string{
+		glyph(doc, "\u0915\u094D\u0937"),
+		char("\u0915") + glyph(doc, "\u0930\u094D"),
+		glyph(doc, "\u093F") + char("\u0915"),
+		glyph(doc, "\u0915\u094D") + char("\u092E"),
+		char("\u0915") + glyph(doc, "\u094D\u0930"),
+		glyph(doc, "\u093F") + char("\u0915") + glyph(doc, "\u0930\u094D"),
+		char("\u0E1B") + glyph(doc, "\u0E48"),
+		char("\u0E01\u0E48"),
+		char("\u0E01") + glyph(doc, "\u0E4D") + char("\u0E48\u0E32"),
+	}
+	for j, codes := range want {
+		if !strings.Contains(doc, "("+codes+")") {
+			t.Errorf("%q is not shaped", text[j])
+		}
+	}
+	doc = generate(false)
+	for _, s := range text {
+		if !strings.Contains(doc, "("+char(s)+")") {
+			t.Errorf("%q is shaped with shaping disabled", s)
+		}
+	}
+}
+
+// TestSVGWrite verifies that SVG content is aligned as its preserveAspectRatio
+// value specifies, and that group opacity is applied with a transparency
+// group.
+func TestSVGWrite(t *testing.T) {
+	generate := func(aspectStr string) string {
+		svg, err := gofpdf.SVGParse([]byte(`<svg xmlns="http://www.w3.org/2000/svg" ` +
+			`width="100" height="50" viewBox="0 0 100 50" preserveAspectRatio="` + aspectStr + `">` +
+			`<g opacity="0.5"><rect width="20" height="10" fill="red" fill-opacity="0.8"/></g></svg>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdf := gofpdf.New("P", "pt", "A4", "")
+		pdf.SetCompression(false)
+		pdf.AddPage()
+		pdf.SVGWrite(&svg, 10, 10, 200, 200)
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	for _, rec := range []struct{ aspectStr, cmStr string }{
+		{"", "2 0 0 -2 10 781.89 cm"},
+		{"xMinYMin", "2 0 0 -2 10 831.89 cm"},
+		{"xMaxYMax meet", "2 0 0 -2 10 731.89 cm"},
+		{"xMidYMid slice", "4 0 0 -4 -90 831.89 cm"},
+		{"xMaxYMin slice", "4 0 0 -4 -190 831.89 cm"},
+		{"none", "2 0 0 -4 10 831.89 cm"},
+	} {
+		doc := generate(rec.aspectStr)
+		if !strings.Contains(doc, rec.cmStr) {
+			t.Errorf("%q: no %q in document", rec.aspectStr, rec.cmStr)
+		}
+	}
+	doc := generate("")
+	if !strings.Contains(doc, "/TG1 Do") {
+		t.Error("group with opacity is not painted as a transparency group")
+	}
+	if !strings.Contains(doc, "/ca 0.500 /CA 0.500") || !strings.Contains(doc, "/ca 0.800 /CA 1.000") {
+		t.Error("opacity of group is not separate from opacity of its fill")
+	}
+}
@@ -22,6 +22,7 @@ type TransformMatrix struct {
 // contexts must be properly ended prior to outputting the document.
 func (f *Fpdf) TransformBegin() {
 	f.transformNest++
+	f.ctmPush()
 	f.out("q")
 }
 
@@ -195,6 +196,7 @@ func (f *Fpdf) Transform(tm TransformMatrix) {
 	if f.transformNest > 0 {
 		f.outf("%.5f %.5f %.5f %.5f %.5f %.5f cm",
 			tm.A, tm.B, tm.C, tm.D, tm.E, tm.F)
+		f.ctm = TransformMatrix(MatrixType(tm).Multiply(MatrixType(f.ctm)))
 	} else if f.err == nil {
 		f.err = fmt.Errorf("transformation context is not active")
 	}
@@ -206,6 +208,7 @@ func (f *Fpdf) Transform(tm TransformMatrix) {
 func (f *Fpdf) TransformEnd() {
 	if f.transformNest > 0 {
 		f.transformNest--
+		f.ctmPop()
 		f.out("Q")
 	} else {
 		f.err = fmt.Errorf("error attempting to end transformation operation out of sequence")
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+)
+
+// RegisterGoImage registers the specified image, adding it to the PDF file
+// but not adding it to the page. Use Image() with the same name to add the
+// image to the page. The pixels of img are converted and compressed row by
+// row, so that no intermediate encoding of the image, such as a PNG file, is
+// held in memory. This is useful for images generated by the application,
+// such as charts.
+//
+// Images with a gray color model are embedded as gray scale and all others as
+// RGB. Unless img reports itself as opaque, its alpha channel is embedded as a
+// soft mask. Of the options, only Bilevel, ImageMask and Threshold apply.
+func (f *Fpdf) RegisterGoImage(imgName string, img image.Image, options ImageOptions) (info *ImageInfoType) {
+	if f.err != nil {
+		return
+	}
+	info, ok := f.images[imgName]
+	if ok {
+		return
+	}
+	info = f.newImageInfo()
+	if options.Bilevel || options.ImageMask {
+		bilevelImage(info, img, options.Threshold)
+		info.stencil = options.ImageMask
+	} else {
+		goImageData(info, img)
+		if len(info.smask) > 0 && f.pdfVersion < "1.4" {
+			f.pdfVersion = "1.4"
+		}
+	}
+	return f.addImage(imgName, info)
+}
+
+// goImageData fills in the image data of info from img. The color samples
+// and, if img is not opaque, the alpha samples are compressed as each row is
+// converted. Rows are laid out as unfiltered PNG scanlines, the form in which
+// soft masks are written.
+func goImageData(info *ImageInfoType, img image.Image) {
+	b := img.Bounds()
+	gray := img.ColorModel() == color.GrayModel || img.ColorModel() == color.Gray16Model
+	opaque := false
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		opaque = o.Opaque()
+	}
+	channels := 3
+	if gray {
+		channels = 1
+	}
+	var colorBuf, alphaBuf bytes.Buffer
+	colorCmp, _ := zlib.NewWriterLevel(&colorBuf, zlib.BestSpeed)
+	alphaCmp, _ := zlib.NewWriterLevel(&alphaBuf, zlib.BestSpeed)
+	// The leading byte of each row selects no filter
+	row := make([]byte, 1+b.Dx()*channels)
+	alphaRow := make([]byte, 1+b.Dx())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		pos := 1
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if gray {
+				row[pos] = c.R
+			} else {
+				row[pos], row[pos+1], row[pos+2] = c.R, c.G, c.B
+			}
+			pos += channels
+			alphaRow[1+x-b.Min.X] = c.A
+		}
+		colorCmp.Write(row)
+		if !opaque {
+			alphaCmp.Write(alphaRow)
+		}
+	}
+	colorCmp.Close()
+	alphaCmp.Close()
+	info.w = float64(b.Dx())
+	info.h = float64(b.Dy())
+	info.cs = "DeviceRGB"
+	if gray {
+		info.cs = "DeviceGray"
+	}
+	info.bpc = 8
+	info.f = "FlateDecode"
+	info.dp = sprintf("/Predictor 15 /Colors %d /BitsPerComponent 8 /Columns %d", channels, b.Dx())
+	info.data = colorBuf.Bytes()
+	if !opaque {
+		info.smask = alphaBuf.Bytes()
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// GradientStopType is a color stop of a gradient drawn with
+// LinearGradientStops() or RadialGradientStops(): the color Color at the
+// position Pos along the gradient, from 0 at its origin to 1 at its end.
+type GradientStopType struct {
+	Pos   float64
+	Color RGBType
+}
+
+// LinearGradientStops draws a rectangular area with a blending of the colors
+// of stops, as LinearGradient() does with two colors. The rectangle is of
+// width w and height h, and its upper left corner is positioned at point
+// (x, y). The gradient vector from (x1, y1) to (x2, y2) is specified in the
+// normalized coordinates of the rectangle described for LinearGradient().
+//
+// Each color is used at the position of its stop along the vector, and
+// colors are blended between consecutive stops. Positions range from 0 at
+// the origin of the vector to 1 at its end, and must not decrease from one
+// stop to the next, so that two stops at the same position change the color
+// abruptly. At least two stops are needed. The first color is used from the
+// origin to the first stop and the last one from the last stop to the end.
+// If extend is true, the first and last colors are also used beyond the
+// origin and the end of the vector, and the rest of the rectangle is left
+// unpainted otherwise.
+func (f *Fpdf) LinearGradientStops(x, y, w, h float64, stops []GradientStopType, x1, y1, x2, y2 float64, extend bool) {
+	f.gradientStops(2, x, y, w, h, stops, x1, y1, x2, y2, 0, extend)
+}
+
+// RadialGradientStops draws a rectangular area with a blending of the colors
+// of stops, as RadialGradient() does with two colors. The rectangle is of
+// width w and height h, and its upper left corner is positioned at point
+// (x, y). The origin (x1, y1) and the circle of center (x2, y2) and radius r
+// are specified in the normalized coordinates of the rectangle described for
+// RadialGradient().
+//
+// Positions of stops range from 0 at the origin to 1 at the circle. See
+// LinearGradientStops() for the stops and the extend argument.
+//
+// The LinearGradientStops() example demonstrates this method.
+func (f *Fpdf) RadialGradientStops(x, y, w, h float64, stops []GradientStopType, x1, y1, x2, y2, r float64, extend bool) {
+	f.gradientStops(3, x, y, w, h, stops, x1, y1, x2, y2, r, extend)
+}
+
+// gradientStops draws a gradient of type tp, 2 for linear and 3 for radial,
+// with the colors of stops.
+func (f *Fpdf) gradientStops(tp int, x, y, w, h float64, stops []GradientStopType, x1, y1, x2, y2, r float64, extend bool) {
+	if f.err != nil {
+		return
+	}
+	if len(stops) < 2 {
+		f.SetErrorf("gradient has %d color stops, at least 2 are needed", len(stops))
+		return
+	}
+	gr := gradientType{tp: tp, x1: x1, y1: y1, x2: x2, y2: y2, r: r, extend: extend}
+	prev := 0.0
+	for j, stop := range stops {
+		if stop.Pos < prev || stop.Pos > 1 {
+			f.SetErrorf("gradient color stop %d at invalid position %.3f", j, stop.Pos)
+			return
+		}
+		prev = stop.Pos
+		clr := rgbColorValue(stop.Color.R, stop.Color.G, stop.Color.B, "", "")
+		// The colors of the first and last stops are repeated at the ends
+		// of the gradient
+		if j == 0 && stop.Pos > 0 {
+			gr.clrStrs, gr.posList = append(gr.clrStrs, clr.str), append(gr.posList, 0)
+		}
+		gr.clrStrs, gr.posList = append(gr.clrStrs, clr.str), append(gr.posList, stop.Pos)
+		if j == len(stops)-1 && stop.Pos < 1 {
+			gr.clrStrs, gr.posList = append(gr.clrStrs, clr.str), append(gr.posList, 1)
+		}
+	}
+	f.gradientClipStart(x, y, w, h)
+	f.gradientList = append(f.gradientList, gr)
+	f.outf("/Sh%d sh", len(f.gradientList)-1)
+	f.gradientClipEnd()
+}
+
+// gradientFunction returns the function dictionary of a gradient that blends
+// the colors clrStrs at the positions posList: an exponential interpolation
+// function for two colors, and a stitching function of them otherwise.
+func gradientFunction(clrStrs []string, posList []float64) string {
+	if len(clrStrs) == 2 {
+		return sprintf("<</FunctionType 2 /Domain [0.0 1.0] /C0 [%s] /C1 [%s] /N 1>>", clrStrs[0], clrStrs[1])
+	}
+	var funcs, bounds, encode fmtBuffer
+	for j := 1; j < len(clrStrs); j++ {
+		funcs.printf("<</FunctionType 2 /Domain [0.0 1.0] /C0 [%s] /C1 [%s] /N 1>> ", clrStrs[j-1], clrStrs[j])
+		if j < len(clrStrs)-1 {
+			bounds.printf("%.5f ", posList[j])
+		}
+		encode.printf("0 1 ")
+	}
+	return sprintf("<</FunctionType 3 /Domain [0.0 1.0] /Functions [%s] /Bounds [%s] /Encode [%s]>>",
+		funcs.String(), bounds.String(), encode.String())
+}
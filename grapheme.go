@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"unicode"
+)
+
+// Grapheme cluster break properties of the Unicode text segmentation
+// algorithm (UAX #29) that clusters are made of
+const (
+	gcOther = iota
+	gcCR
+	gcLF
+	gcControl
+	gcExtend
+	gcZWJ
+	gcRegional     // Regional indicator, of which pairs are flags
+	gcSpacingMark  // Spacing combining mark
+	gcL            // Hangul leading consonant jamo
+	gcV            // Hangul vowel jamo
+	gcT            // Hangul trailing consonant jamo
+	gcLV           // Hangul syllable without trailing consonant
+	gcLVT          // Hangul syllable with trailing consonant
+	gcPictographic // Extended pictographic character, such as an emoji
+)
+
+// graphemeProperty returns the grapheme cluster break property of r.
+func graphemeProperty(r rune) int {
+	switch {
+	case r == '\r':
+		return gcCR
+	case r == '\n':
+		return gcLF
+	case r == 0x200D:
+		return gcZWJ
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F,
+		unicode.In(r, unicode.Mn, unicode.Me):
+		return gcExtend
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gcRegional
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gcControl
+	case unicode.Is(unicode.Mc, r):
+		return gcSpacingMark
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gcL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gcV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gcT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gcLV
+		}
+		return gcLVT
+	case r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139,
+		r >= 0x2194 && r <= 0x21AA, r >= 0x231A && r <= 0x23FF, r >= 0x25A0 && r <= 0x25FF,
+		r >= 0x2600 && r <= 0x27BF, r >= 0x2934 && r <= 0x2935, r >= 0x2B05 && r <= 0x2B55,
+		r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299, r >= 0x1F000 && r <= 0x1FAFF,
+		r >= 0x1FC00 && r <= 0x1FFFD:
+		return gcPictographic
+	}
+	return gcOther
+}
+
+// graphemeBounds reports, for each position of runes from 0 to len(runes),
+// whether it is a boundary between grapheme clusters: the characters that
+// are perceived as one, such as a letter and its combining accents, a Hangul
+// syllable of jamo, a flag or an emoji sequence joined with zero width
+// joiners. Text is only broken between characters at such boundaries.
+func graphemeBounds(runes []rune) []bool {
+	bounds := make([]bool, len(runes)+1)
+	bounds[0], bounds[len(runes)] = true, true
+	regionals := 0        // Regional indicators that end the text read
+	pictographic := false // Text read ends with a pictographic character and extenders
+	var prev int
+	for j, r := range runes {
+		p := graphemeProperty(r)
+		if j > 0 {
+			bounds[j] = graphemeBoundary(prev, p, regionals, pictographic)
+		}
+		switch p {
+		case gcRegional:
+			regionals++
+		default:
+			regionals = 0
+		}
+		switch p {
+		case gcPictographic:
+			pictographic = true
+		case gcExtend, gcZWJ:
+		default:
+			pictographic = false
+		}
+		prev = p
+	}
+	return bounds
+}
+
+// graphemeBoundary reports whether there is a boundary between a character
+// of the property prev and one of the property next, given the number of
+// regional indicators that end the text before and whether it ends with a
+// pictographic character followed by extenders.
+func graphemeBoundary(prev, next, regionals int, pictographic bool) bool {
+	switch {
+	case prev == gcCR && next == gcLF:
+		return false
+	case prev == gcCR, prev == gcLF, prev == gcControl, next == gcCR, next == gcLF, next == gcControl:
+		return true
+	case prev == gcL && (next == gcL || next == gcV || next == gcLV || next == gcLVT),
+		(prev == gcLV || prev == gcV) && (next == gcV || next == gcT),
+		(prev == gcLVT || prev == gcT) && next == gcT:
+		return false
+	case next == gcExtend, next == gcZWJ, next == gcSpacingMark:
+		return false
+	case prev == gcZWJ && next == gcPictographic && pictographic:
+		return false
+	case prev == gcRegional && next == gcRegional:
+		return regionals%2 == 0
+	}
+	return true
+}
+
+// graphemeBreak returns the position at which the line of text that starts
+// at the character j and overflows at the character i is broken between
+// characters: the last boundary of bounds after j and up to i, or the end of
+// the cluster that begins at j if it does not fit on the line. bounds is nil
+// for text in single-byte encodings, whose characters are clusters.
+func graphemeBreak(bounds []bool, j, i int) int {
+	if bounds == nil {
+		if i == j {
+			return i + 1
+		}
+		return i
+	}
+	k := i
+	for k > j && !bounds[k] {
+		k--
+	}
+	if k > j {
+		return k
+	}
+	for k = j + 1; !bounds[k]; k++ {
+	}
+	return k
+}
+
+// ignorable reports whether r is an invisible formatting character, such as a
+// joiner or a variation selector, which is neither measured nor printed with
+// a font that lacks it rather than shown as a missing glyph.
+func ignorable(r rune) bool {
+	return r >= 0x200B && r <= 0x200F || r >= 0x202A && r <= 0x202E || r >= 0x2060 && r <= 0x206F ||
+		r >= 0xFE00 && r <= 0xFE0F || r == 0xFEFF || r >= 0xE0000 && r <= 0xE0FFF
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"math"
+)
+
+// transparencyGroupType is a transparency group, a form XObject whose content
+// is composited as a unit before it is blended with the page.
+type transparencyGroupType struct {
+	content            []byte
+	bbox               [4]float64 // Page of the group in its own coordinates, in points
+	isolated, knockout bool
+	objNum             int
+}
+
+// transparencyGroupNest is a transparency group being collected.
+type transparencyGroupNest struct {
+	page               int
+	content            *bytes.Buffer // Content of the page or enclosing group
+	isolated, knockout bool
+	kindStr            string     // "transparency group", "soft mask" or "shadow"
+	shadow             ShadowType // Shadow cast by the group, if a shadow
+	alpha              float64    // Alpha of the group
+	blendMode          string     // Blend mode of the group
+}
+
+// softMaskType is the graphics state of a luminosity soft mask, or of an
+// alpha soft mask if alpha is true, that is the transparency group numbered
+// group, or of no soft mask if group is 0.
+type softMaskType struct {
+	group  int
+	alpha  bool
+	objNum int
+}
+
+// BeginTransparencyGroup begins a transparency group: the text, drawings and
+// images that follow, up to the call of EndTransparencyGroup(), are
+// composited with each other, and the result is blended with the page as a
+// single object, with the alpha and blend mode set with SetAlpha() at the
+// time of this call. Within the group, drawing begins fully opaque with the
+// "Normal" blend mode. Overlapping shapes of a group faded by its alpha thus
+// do not show through each other, as they do when each is painted with that
+// alpha.
+//
+// If isolated is true, the objects of the group are composited with each
+// other on a transparent backdrop rather than on the page, so that blend
+// modes within the group do not blend them with what lies below it. If
+// knockout is true, each object of the group is composited with the backdrop
+// of the group rather than with the objects of the group that precede it,
+// so that overlapping semi-transparent objects knock each other out instead
+// of accumulating.
+//
+// A group lies on a single page, and groups can be nested. The document cannot
+// be successfully output while a group is active.
+func (f *Fpdf) BeginTransparencyGroup(isolated, knockout bool) {
+	f.groupBegin(transparencyGroupNest{isolated: isolated, knockout: knockout, kindStr: "transparency group"})
+}
+
+// EndTransparencyGroup ends the transparency group begun by the last call of
+// BeginTransparencyGroup() and paints it on the page. The alpha and blend
+// mode in effect when the group was begun are restored, while the other
+// parameters set within the group, such as the colors, the line width and
+// the font, remain in effect after it.
+//
+// The BeginTransparencyGroup() example demonstrates this method.
+func (f *Fpdf) EndTransparencyGroup() {
+	if pos := f.groupEnd("transparency group"); pos > 0 {
+		f.outf("/TG%d Do", pos)
+		f.groupStatePut()
+	}
+}
+
+// BeginSoftMask begins a luminosity soft mask: the text, drawings and images
+// that follow, up to the call of EndSoftMask(), are not painted on the page
+// but make up a mask that sets the opacity of the content that follows
+// EndSoftMask(). The luminosity of the mask at each point of the page is the
+// opacity of that content there, from fully opaque where the mask is white to
+// fully transparent where it is black or where nothing is drawn. A white to
+// black gradient thus fades out what is drawn over it, and a shape drawn
+// with a blurred or graded edge feathers it.
+//
+// The content of the mask begins fully opaque with the "Normal" blend mode. A
+// mask lies on a single page. The document cannot be successfully output
+// while a mask is being drawn.
+func (f *Fpdf) BeginSoftMask() {
+	f.groupBegin(transparencyGroupNest{isolated: true, kindStr: "soft mask"})
+}
+
+// EndSoftMask ends the soft mask begun by the last call of BeginSoftMask() and
+// applies it to the text, drawings and images that follow, until
+// ClearSoftMask() is called or the graphics state is restored by
+// TransformEnd() or ClipEnd(). The alpha and blend mode in effect when the
+// mask was begun are restored, and they apply along with the mask, while the
+// other parameters set while the mask was drawn, such as the colors, the line
+// width and the font, remain in effect after it.
+//
+// The BeginSoftMask() example demonstrates this method.
+func (f *Fpdf) EndSoftMask() {
+	if pos := f.groupEnd("soft mask"); pos > 0 {
+		f.softMaskList = append(f.softMaskList, softMaskType{group: pos})
+		f.outf("/SM%d gs", len(f.softMaskList)-1)
+		f.groupStatePut()
+	}
+}
+
+// ClearSoftMask removes the soft mask applied with EndSoftMask(), so that the
+// content that follows is painted with the current alpha alone.
+//
+// The BeginSoftMask() example demonstrates this method.
+func (f *Fpdf) ClearSoftMask() {
+	if f.err != nil {
+		return
+	}
+	pos := 0
+	for j := 1; j < len(f.softMaskList) && pos == 0; j++ {
+		if f.softMaskList[j].group == 0 {
+			pos = j
+		}
+	}
+	if pos == 0 {
+		pos = len(f.softMaskList)
+		f.softMaskList = append(f.softMaskList, softMaskType{})
+	}
+	f.outf("/SM%d gs", pos)
+}
+
+// groupBegin begins collecting the content of the transparency group nest.
+func (f *Fpdf) groupBegin(nest transparencyGroupNest) {
+	if f.err != nil {
+		return
+	}
+	if f.page == 0 {
+		f.SetErrorf("no page for the transparency group")
+		return
+	}
+	nest.page, nest.content = f.page, f.pages[f.page]
+	nest.alpha, nest.blendMode = f.alpha, f.blendMode
+	f.groupNest = append(f.groupNest, nest)
+	f.pages[f.page] = new(bytes.Buffer)
+	f.alpha, f.blendMode = 1, "Normal"
+}
+
+// groupEnd ends collecting the content of the last transparency group begun,
+// which is of the kind nameStr, and returns its position in the list of
+// groups, or 0 if it fails.
+func (f *Fpdf) groupEnd(nameStr string) int {
+	if f.err != nil {
+		return 0
+	}
+	if len(f.groupNest) == 0 || f.groupNest[len(f.groupNest)-1].kindStr != nameStr {
+		f.SetErrorf("no %s to end", nameStr)
+		return 0
+	}
+	nest := f.groupNest[len(f.groupNest)-1]
+	f.groupNest = f.groupNest[:len(f.groupNest)-1]
+	if f.page != nest.page {
+		f.SetErrorf("%s spans pages %d to %d", nameStr, nest.page, f.page)
+		return 0
+	}
+	f.groupList = append(f.groupList, transparencyGroupType{content: f.pages[f.page].Bytes(),
+		bbox: f.groupBBox(), isolated: nest.isolated, knockout: nest.knockout})
+	f.pages[f.page] = nest.content
+	f.alpha, f.blendMode = nest.alpha, nest.blendMode
+	return len(f.groupList) - 1
+}
+
+// groupBBox returns the bounding box of the page in the coordinates in which a
+// group painted under the current transformation is drawn.
+func (f *Fpdf) groupBBox() (bbox [4]float64) {
+	bbox = [4]float64{0, 0, f.wPt, f.hPt}
+	inv, ok := MatrixType(f.ctm).Invert()
+	if !ok {
+		return
+	}
+	for j, corner := range [][2]float64{{0, 0}, {f.wPt, 0}, {0, f.hPt}, {f.wPt, f.hPt}} {
+		x, y := inv.TransformPoint(corner[0], corner[1])
+		if j == 0 {
+			bbox = [4]float64{x, y, x, y}
+			continue
+		}
+		bbox[0], bbox[1] = math.Min(bbox[0], x), math.Min(bbox[1], y)
+		bbox[2], bbox[3] = math.Max(bbox[2], x), math.Max(bbox[3], y)
+	}
+	return
+}
+
+// groupStatePut sets the parameters set within a transparency group in the
+// page too, as the group does not change the graphics state of the page.
+func (f *Fpdf) groupStatePut() {
+	f.outf("%.5f Tc", f.charSpacing*f.k)
+	f.wordSpacingPut()
+	f.outf("%.5f Ts", f.textRise*f.k)
+	f.outf("%d Tr", f.textMode)
+	f.outf("%d J %d j %.2f w", f.capStyle, f.joinStyle, f.lineWidth*f.k)
+	f.outputDashPattern()
+	if f.currentFont.Name != "" {
+		f.outf("BT /F%s %.2f Tf ET", f.currentFont.i, f.fontSizePt)
+	}
+	f.out(f.color.draw.str)
+	f.out(f.color.fill.str)
+}
+
+func (f *Fpdf) putTransparencyGroups() {
+	for j := 1; j < len(f.groupList); j++ {
+		grp := f.groupList[j]
+		content := grp.content
+		filter := ""
+		if f.compress {
+			content = sliceCompress(content)
+			filter = "/Filter /FlateDecode "
+		}
+		f.newobj()
+		f.groupList[j].objNum = f.n
+		f.outf("<<%s/Type /XObject /Subtype /Form /BBox [%.2f %.2f %.2f %.2f] /Resources 2 0 R", filter,
+			grp.bbox[0], grp.bbox[1], grp.bbox[2], grp.bbox[3])
+		f.outf("/Group <</Type /Group /S /Transparency /CS /DeviceRGB /I %s /K %s>>",
+			strIf(grp.isolated, "true", "false"), strIf(grp.knockout, "true", "false"))
+		f.outf("/Length %d>>", len(content))
+		f.putstream(content)
+		f.out("endobj")
+	}
+}
+
+func (f *Fpdf) transparencyGroupPutXObjectDict() {
+	for j := 1; j < len(f.groupList); j++ {
+		f.outf("/TG%d %d 0 R", j, f.groupList[j].objNum)
+	}
+}
+
+func (f *Fpdf) putSoftMasks() {
+	for j := 1; j < len(f.softMaskList); j++ {
+		sm := f.softMaskList[j]
+		f.newobj()
+		f.softMaskList[j].objNum = f.n
+		if sm.group == 0 {
+			f.out("<</Type /ExtGState /SMask /None>>")
+		} else if sm.alpha {
+			f.outf("<</Type /ExtGState /SMask <</Type /Mask /S /Alpha /G %d 0 R>>>>", f.groupList[sm.group].objNum)
+		} else {
+			f.outf("<</Type /ExtGState /SMask <</Type /Mask /S /Luminosity /G %d 0 R /BC [0 0 0]>>>>",
+				f.groupList[sm.group].objNum)
+		}
+		f.out("endobj")
+	}
+}
+
+func (f *Fpdf) softMaskPutExtGStateDict() {
+	for j := 1; j < len(f.softMaskList); j++ {
+		f.outf("/SM%d %d 0 R", j, f.softMaskList[j].objNum)
+	}
+}
@@ -0,0 +1,697 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"fmt"
+	"sort"
+)
+
+// gsubSubtable is a subtable of a lookup of a GSUB table.
+type gsubSubtable struct {
+	lookupType int
+	offset     int
+}
+
+// gsubLatinScripts are the scripts of the GSUB table whose features apply
+// to text of no particular script.
+var gsubLatinScripts = []string{"DFLT", "latn"}
+
+// gsubFeatureLookups returns the subtables of the lookups of the feature with
+// the specified tag of the default language systems of the default and Latin
+// scripts of the GSUB table, in the order of the lookups. Extension subtables
+// are resolved to the subtables they contain.
+func gsubFeatureLookups(r *sfntReader, featureTag string) (lookups [][]gsubSubtable) {
+	for _, index := range gsubLookupIndices(r, gsubLatinScripts, featureTag) {
+		_, subtables := gsubLookup(r, index)
+		lookups = append(lookups, subtables)
+	}
+	return
+}
+
+// gsubScripts returns the tags of the scripts of the GSUB table.
+func gsubScripts(r *sfntReader) (tags []string) {
+	r.pos = 4
+	scriptList := r.u16()
+	r.pos = scriptList
+	for j, n := 0, r.u16(); j < n && r.err == nil; j++ {
+		r.pos = scriptList + 2 + 6*j
+		tags = append(tags, string(r.bytes(4)))
+	}
+	return
+}
+
+// gsubLookupIndices returns the indices of the lookups of the features with
+// the specified tags of the default language systems of the specified
+// scripts of the GSUB table, in ascending order.
+func gsubLookupIndices(r *sfntReader, scripts []string, featureTags ...string) (indices []int) {
+	r.pos = 4
+	scriptList, featureList, lookupList := r.u16(), r.u16(), r.u16()
+	r.pos = featureList
+	featureCount := r.u16()
+	r.pos = lookupList
+	lookupCount := r.u16()
+	r.pos = scriptList
+	for j, n := 0, r.u16(); j < n && r.err == nil; j++ {
+		r.pos = scriptList + 2 + 6*j
+		tag := string(r.bytes(4))
+		script := scriptList + r.u16()
+		if !gsubHasTag(scripts, tag) {
+			continue
+		}
+		r.pos = script
+		langSys := r.u16()
+		if langSys == 0 {
+			continue
+		}
+		// Skip lookupOrderOffset and requiredFeatureIndex
+		r.pos = script + langSys + 4
+		features := make([]int, r.u16())
+		for k := range features {
+			features[k] = r.u16()
+		}
+		for _, feature := range features {
+			if feature >= featureCount {
+				continue
+			}
+			r.pos = featureList + 2 + 6*feature
+			if !gsubHasTag(featureTags, string(r.bytes(4))) {
+				continue
+			}
+			// Skip featureParamsOffset
+			r.pos = featureList + r.u16() + 2
+			for k, m := 0, r.u16(); k < m && r.err == nil; k++ {
+				if index := r.u16(); index < lookupCount {
+					indices = append(indices, index)
+				}
+			}
+		}
+	}
+	sort.Ints(indices)
+	unique := indices[:0]
+	for j, index := range indices {
+		if j == 0 || index != indices[j-1] {
+			unique = append(unique, index)
+		}
+	}
+	return unique
+}
+
+// gsubLookup returns the lookup flag and the subtables of the lookup with the
+// specified index of the GSUB table. Extension subtables are resolved to the
+// subtables they contain.
+func gsubLookup(r *sfntReader, index int) (flag int, subtables []gsubSubtable) {
+	r.pos = 8
+	lookupList := r.u16()
+	r.pos = lookupList + 2 + 2*index
+	lookup := lookupList + r.u16()
+	r.pos = lookup
+	lookupType := r.u16()
+	flag = r.u16()
+	for k, n := 0, r.u16(); k < n && r.err == nil; k++ {
+		r.pos = lookup + 6 + 2*k
+		subtable := gsubSubtable{lookupType: lookupType, offset: lookup + r.u16()}
+		if lookupType == 7 {
+			// Extension substitution
+			r.pos = subtable.offset + 2
+			subtable.lookupType = r.u16()
+			subtable.offset += r.u32()
+		}
+		subtables = append(subtables, subtable)
+	}
+	return
+}
+
+// gsubLigature is a ligature substitution of a GSUB table.
+type gsubLigature struct {
+	glyph      int
+	components []int
+}
+
+// gsubLigatures returns the ligature substitutions of the lookups of the
+// "liga" feature of the GSUB table, in the order of the lookups.
+func gsubLigatures(gsub []byte) ([][]gsubLigature, error) {
+	r := sfntReader{data: gsub}
+	var ligatures [][]gsubLigature
+	for _, lookup := range gsubFeatureLookups(&r, "liga") {
+		var list []gsubLigature
+		for _, subtable := range lookup {
+			if subtable.lookupType == 4 {
+				list = append(list, gsubLigatureSubst(&r, subtable.offset)...)
+			}
+		}
+		if len(list) > 0 {
+			ligatures = append(ligatures, list)
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid GSUB table")
+	}
+	return ligatures, nil
+}
+
+// gsubLigatureSubst returns the ligatures of a ligature substitution
+// subtable.
+func gsubLigatureSubst(r *sfntReader, subtable int) (list []gsubLigature) {
+	r.pos = subtable
+	if r.u16() != 1 {
+		return
+	}
+	firstGlyphs := gsubCoverage(r, subtable+r.u16())
+	r.pos = subtable + 4
+	for j, n := 0, r.u16(); j < n && j < len(firstGlyphs) && r.err == nil; j++ {
+		r.pos = subtable + 6 + 2*j
+		set := subtable + r.u16()
+		r.pos = set
+		for k, m := 0, r.u16(); k < m && r.err == nil; k++ {
+			r.pos = set + 2 + 2*k
+			r.pos = set + r.u16()
+			l := gsubLigature{glyph: r.u16(), components: []int{firstGlyphs[j]}}
+			for c := r.u16(); c > 1 && r.err == nil; c-- {
+				l.components = append(l.components, r.u16())
+			}
+			list = append(list, l)
+		}
+	}
+	return
+}
+
+// gsubSingleSubstitutions returns the glyphs that the single substitutions
+// of the feature with the specified tag of the GSUB table substitute for
+// glyphs. A glyph takes the substitution of the first lookup that covers it.
+func gsubSingleSubstitutions(gsub []byte, featureTag string) (map[int]int, error) {
+	r := sfntReader{data: gsub}
+	substitutes := make(map[int]int)
+	add := func(glyph, substitute int) {
+		if _, ok := substitutes[glyph]; !ok {
+			substitutes[glyph] = substitute
+		}
+	}
+	for _, lookup := range gsubFeatureLookups(&r, featureTag) {
+		for _, subtable := range lookup {
+			if subtable.lookupType != 1 {
+				continue
+			}
+			r.pos = subtable.offset
+			format, coverage := r.u16(), subtable.offset+r.u16()
+			switch format {
+			case 1:
+				delta := r.u16()
+				for _, glyph := range gsubCoverage(&r, coverage) {
+					add(glyph, (glyph+delta)&0xffff)
+				}
+			case 2:
+				glyphs := make([]int, r.u16())
+				for j := range glyphs {
+					glyphs[j] = r.u16()
+				}
+				for j, glyph := range gsubCoverage(&r, coverage) {
+					if j < len(glyphs) {
+						add(glyph, glyphs[j])
+					}
+				}
+			}
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid GSUB table")
+	}
+	return substitutes, nil
+}
+
+// gsubCoverage returns the glyphs of a coverage table by coverage index.
+func gsubCoverage(r *sfntReader, coverage int) (glyphs []int) {
+	r.pos = coverage
+	switch r.u16() {
+	case 1:
+		for j, n := 0, r.u16(); j < n && r.err == nil; j++ {
+			glyphs = append(glyphs, r.u16())
+		}
+	case 2:
+		for j, n := 0, r.u16(); j < n && r.err == nil; j++ {
+			start, end, index := r.u16(), r.u16(), r.u16()
+			for glyph := start; glyph <= end; glyph++ {
+				for len(glyphs) <= index+glyph-start {
+					glyphs = append(glyphs, 0)
+				}
+				glyphs[index+glyph-start] = glyph
+			}
+		}
+	}
+	return
+}
+
+// gsubHasTag reports whether tags contains tag.
+func gsubHasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// gsubGlyph is a glyph of text being shaped with the lookups of a GSUB
+// table.
+type gsubGlyph struct {
+	glyph int
+	chars string // Characters that the glyph represents
+	mask  uint   // Features that apply to the glyph
+	moved bool   // Glyph moved from the position of its characters
+}
+
+// gsubMaxNesting is the greatest depth of the lookups that contextual
+// substitutions apply.
+const gsubMaxNesting = 8
+
+// gsubTable applies the lookups of a GSUB table to glyphs.
+type gsubTable struct {
+	r         sfntReader
+	classes   map[int]int         // GDEF glyph classes
+	coverages map[int]map[int]int // Coverage indices by glyph, by coverage table
+	classDefs map[int]map[int]int // Classes by glyph, by class definition table
+}
+
+// newGsubTable returns the GSUB table gsub, whose lookups skip glyphs by the
+// glyph classes of the GDEF table gdef, which may be nil.
+func newGsubTable(gsub, gdef []byte) (*gsubTable, error) {
+	t := &gsubTable{
+		r:         sfntReader{data: gsub},
+		classes:   make(map[int]int),
+		coverages: make(map[int]map[int]int),
+		classDefs: make(map[int]map[int]int),
+	}
+	if len(gdef) > 0 {
+		r := sfntReader{data: gdef}
+		r.pos = 4
+		if classDef := r.u16(); classDef > 0 {
+			t.classes = gsubClassDef(&r, classDef)
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("invalid GDEF table")
+		}
+	}
+	return t, nil
+}
+
+// lookupIndices returns the indices of the lookups of the features of the
+// scripts, in ascending order.
+func (t *gsubTable) lookupIndices(scripts []string, featureTags ...string) []int {
+	return gsubLookupIndices(&t.r, scripts, featureTags...)
+}
+
+// coverage returns the coverage indices of the glyphs of a coverage table.
+// The position of the reader is preserved.
+func (t *gsubTable) coverage(offset int) map[int]int {
+	m, ok := t.coverages[offset]
+	if !ok {
+		defer func(pos int) { t.r.pos = pos }(t.r.pos)
+		m = make(map[int]int)
+		for index, glyph := range gsubCoverage(&t.r, offset) {
+			if _, ok := m[glyph]; !ok {
+				m[glyph] = index
+			}
+		}
+		t.coverages[offset] = m
+	}
+	return m
+}
+
+// classDef returns the classes of the glyphs of a class definition table.
+// The position of the reader is preserved.
+func (t *gsubTable) classDef(offset int) map[int]int {
+	m, ok := t.classDefs[offset]
+	if !ok {
+		defer func(pos int) { t.r.pos = pos }(t.r.pos)
+		m = gsubClassDef(&t.r, offset)
+		t.classDefs[offset] = m
+	}
+	return m
+}
+
+// gsubClassDef returns the classes of the glyphs of a class definition
+// table. Glyphs that are not listed are of class 0.
+func gsubClassDef(r *sfntReader, offset int) map[int]int {
+	classes := make(map[int]int)
+	r.pos = offset
+	switch r.u16() {
+	case 1:
+		start := r.u16()
+		for j, n := 0, r.u16(); j < n && r.err == nil; j++ {
+			classes[start+j] = r.u16()
+		}
+	case 2:
+		for j, n := 0, r.u16(); j < n && r.err == nil; j++ {
+			start, end, class := r.u16(), r.u16(), r.u16()
+			for glyph := start; glyph <= end; glyph++ {
+				classes[glyph] = class
+			}
+		}
+	}
+	return classes
+}
+
+// ignored reports whether the lookup flag of a lookup makes it skip the
+// glyph.
+func (t *gsubTable) ignored(glyph, flag int) bool {
+	switch t.classes[glyph] {
+	case 1:
+		return flag&2 != 0
+	case 2:
+		return flag&4 != 0
+	case 3:
+		return flag&8 != 0
+	}
+	return false
+}
+
+// next returns the position of the glyph that follows, or precedes if step
+// is negative, the glyph at position j and that the lookup flag does not
+// skip, or -1 if there is none.
+func (t *gsubTable) next(glyphs []gsubGlyph, j, step, flag int) int {
+	for j += step; j >= 0 && j < len(glyphs); j += step {
+		if !t.ignored(glyphs[j].glyph, flag) {
+			return j
+		}
+	}
+	return -1
+}
+
+// apply applies the lookup with the specified index to the glyphs whose mask
+// has a bit of mask in common, and returns the glyphs.
+func (t *gsubTable) apply(index int, glyphs []gsubGlyph, mask uint) []gsubGlyph {
+	flag, subtables := gsubLookup(&t.r, index)
+	for j := 0; j < len(glyphs); {
+		advance := 1
+		if glyphs[j].mask&mask != 0 && !t.ignored(glyphs[j].glyph, flag) {
+			for _, subtable := range subtables {
+				var ok bool
+				if glyphs, advance, ok = t.substitute(subtable, flag, glyphs, j, mask, 0); ok {
+					break
+				}
+				advance = 1
+			}
+		}
+		j += maxInt(advance, 1)
+	}
+	return glyphs
+}
+
+// applyAt applies the lookup with the specified index once to the glyph at
+// position j, as the lookup of a contextual substitution.
+func (t *gsubTable) applyAt(index int, glyphs []gsubGlyph, j, depth int) []gsubGlyph {
+	flag, subtables := gsubLookup(&t.r, index)
+	if j >= len(glyphs) || t.ignored(glyphs[j].glyph, flag) {
+		return glyphs
+	}
+	for _, subtable := range subtables {
+		if out, _, ok := t.substitute(subtable, flag, glyphs, j, ^uint(0), depth); ok {
+			return out
+		}
+	}
+	return glyphs
+}
+
+// substitute applies a subtable to the glyph at position j. It returns the
+// glyphs, the number of glyphs from position j that the substitution
+// produced or matched, and whether the subtable applies.
+func (t *gsubTable) substitute(subtable gsubSubtable, flag int, glyphs []gsubGlyph,
+	j int, mask uint, depth int) ([]gsubGlyph, int, bool) {
+	r := &t.r
+	offset := subtable.offset
+	glyph := glyphs[j].glyph
+	r.pos = offset
+	format, coverage := r.u16(), r.u16()
+	var index int
+	var covered bool
+	if format != 3 || subtable.lookupType < 5 {
+		// Contextual substitutions of format 3 have no single coverage
+		index, covered = t.coverage(offset + coverage)[glyph]
+	}
+	switch subtable.lookupType {
+	case 1:
+		if !covered {
+			break
+		}
+		r.pos = offset + 4
+		switch format {
+		case 1:
+			glyphs[j].glyph = (glyph + r.u16()) & 0xffff
+			return glyphs, 1, true
+		case 2:
+			if index < r.u16() {
+				r.pos = offset + 6 + 2*index
+				glyphs[j].glyph = r.u16()
+				return glyphs, 1, true
+			}
+		}
+	case 2:
+		r.pos = offset + 4
+		if !covered || format != 1 || index >= r.u16() {
+			break
+		}
+		r.pos = offset + 6 + 2*index
+		r.pos = offset + r.u16()
+		sequence := make([]gsubGlyph, r.u16())
+		for k := range sequence {
+			sequence[k] = glyphs[j]
+			sequence[k].glyph = r.u16()
+			if k > 0 {
+				sequence[k].chars = ""
+			}
+		}
+		out := append(append(append([]gsubGlyph(nil), glyphs[:j]...), sequence...), glyphs[j+1:]...)
+		return out, len(sequence), true
+	case 4:
+		r.pos = offset + 4
+		if !covered || format != 1 || index >= r.u16() {
+			break
+		}
+		r.pos = offset + 6 + 2*index
+		set := offset + r.u16()
+		r.pos = set
+		for k, n := 0, r.u16(); k < n && r.err == nil; k++ {
+			r.pos = set + 2 + 2*k
+			r.pos = set + r.u16()
+			ligature := r.u16()
+			positions := []int{j}
+			for c, p := r.u16(), j; c > 1; c-- {
+				if p = t.next(glyphs, p, 1, flag); p < 0 || glyphs[p].glyph != r.u16() || glyphs[p].mask&mask == 0 {
+					positions = nil
+					break
+				}
+				positions = append(positions, p)
+			}
+			if positions == nil {
+				continue
+			}
+			// Skipped glyphs between the components follow the ligature
+			out := append([]gsubGlyph(nil), glyphs[:j]...)
+			lig := glyphs[j]
+			lig.glyph = ligature
+			for _, p := range positions[1:] {
+				lig.chars += glyphs[p].chars
+			}
+			out = append(out, lig)
+			for p, c := j+1, 1; p < len(glyphs); p++ {
+				if c < len(positions) && p == positions[c] {
+					c++
+					continue
+				}
+				out = append(out, glyphs[p])
+			}
+			return out, 1, true
+		}
+	case 5, 6:
+		if depth < gsubMaxNesting {
+			return t.substituteContext(subtable, flag, glyphs, j, covered, index, depth)
+		}
+	}
+	return glyphs, 1, false
+}
+
+// gsubMatch reports whether a glyph matches an element of the sequence of a
+// contextual substitution rule.
+type gsubMatch func(glyph int) bool
+
+// substituteContext applies a contextual or chained contextual substitution
+// subtable to the glyph at position j, which is covered with the specified
+// coverage index if covered is true.
+func (t *gsubTable) substituteContext(subtable gsubSubtable, flag int, glyphs []gsubGlyph,
+	j int, covered bool, index, depth int) ([]gsubGlyph, int, bool) {
+	r := &t.r
+	offset := subtable.offset
+	chained := subtable.lookupType == 6
+	r.pos = offset
+	format := r.u16()
+	// sequence reads count values and returns the matches of the glyphs
+	// with the values, which value returns
+	sequence := func(count int, value func(v int) gsubMatch) []gsubMatch {
+		matches := make([]gsubMatch, count)
+		for k := range matches {
+			matches[k] = value(r.u16())
+		}
+		return matches
+	}
+	isGlyph := func(v int) gsubMatch { return func(glyph int) bool { return glyph == v } }
+	isCovered := func(v int) gsubMatch {
+		coverage := t.coverage(offset + v)
+		return func(glyph int) bool {
+			_, ok := coverage[glyph]
+			return ok
+		}
+	}
+	// rule reads a rule and applies it if it matches
+	rule := func(value [3]func(v int) gsubMatch) ([]gsubGlyph, int, bool) {
+		var backtrack, input, lookahead []gsubMatch
+		if chained {
+			backtrack = sequence(r.u16(), value[0])
+		}
+		n := r.u16()
+		if !chained {
+			substCount := r.u16()
+			input = sequence(n-1, value[1])
+			return t.applyRule(glyphs, j, flag, nil, input, nil, substCount, depth)
+		}
+		input = sequence(n-1, value[1])
+		lookahead = sequence(r.u16(), value[2])
+		return t.applyRule(glyphs, j, flag, backtrack, input, lookahead, r.u16(), depth)
+	}
+	// ruleSet applies the first matching rule of the set at the offset
+	ruleSet := func(set int, value [3]func(v int) gsubMatch) ([]gsubGlyph, int, bool) {
+		r.pos = set
+		for k, n := 0, r.u16(); k < n && r.err == nil; k++ {
+			r.pos = set + 2 + 2*k
+			r.pos = set + r.u16()
+			if out, advance, ok := rule(value); ok {
+				return out, advance, true
+			}
+		}
+		return glyphs, 1, false
+	}
+	switch format {
+	case 1:
+		r.pos = offset + 4
+		if !covered || index >= r.u16() {
+			break
+		}
+		r.pos = offset + 6 + 2*index
+		if set := r.u16(); set > 0 {
+			return ruleSet(offset+set, [3]func(v int) gsubMatch{isGlyph, isGlyph, isGlyph})
+		}
+	case 2:
+		if !covered {
+			break
+		}
+		r.pos = offset + 4
+		var classDefs [3]map[int]int
+		if chained {
+			for k := range classDefs {
+				classDefs[k] = t.classDef(offset + r.u16())
+			}
+		} else {
+			classDefs[1] = t.classDef(offset + r.u16())
+		}
+		setsPos := r.pos
+		class := classDefs[1][glyphs[j].glyph]
+		if class >= r.u16() {
+			break
+		}
+		r.pos = setsPos + 2 + 2*class
+		set := r.u16()
+		if set == 0 {
+			break
+		}
+		var value [3]func(v int) gsubMatch
+		for k := range value {
+			classes := classDefs[k]
+			value[k] = func(v int) gsubMatch {
+				return func(glyph int) bool { return classes[glyph] == v }
+			}
+		}
+		return ruleSet(offset+set, value)
+	case 3:
+		var backtrack, input, lookahead []gsubMatch
+		if chained {
+			backtrack = sequence(r.u16(), isCovered)
+			input = sequence(r.u16(), isCovered)
+			lookahead = sequence(r.u16(), isCovered)
+		} else {
+			n := r.u16()
+			substCount := r.u16()
+			input = sequence(n, isCovered)
+			if len(input) == 0 || !input[0](glyphs[j].glyph) {
+				break
+			}
+			return t.applyRule(glyphs, j, flag, nil, input[1:], nil, substCount, depth)
+		}
+		if len(input) == 0 || !input[0](glyphs[j].glyph) {
+			break
+		}
+		return t.applyRule(glyphs, j, flag, backtrack, input[1:], lookahead, r.u16(), depth)
+	}
+	return glyphs, 1, false
+}
+
+// applyRule applies the substitutions of a contextual rule, whose records
+// the reader is positioned at, if the glyphs at position j match the rule.
+// input holds the matches of the glyphs that follow the first.
+func (t *gsubTable) applyRule(glyphs []gsubGlyph, j, flag int, backtrack, input, lookahead []gsubMatch,
+	substCount, depth int) ([]gsubGlyph, int, bool) {
+	positions := []int{j}
+	p := j
+	for _, match := range input {
+		if p = t.next(glyphs, p, 1, flag); p < 0 || !match(glyphs[p].glyph) {
+			return glyphs, 1, false
+		}
+		positions = append(positions, p)
+	}
+	for k, p := 0, j; k < len(backtrack); k++ {
+		if p = t.next(glyphs, p, -1, flag); p < 0 || !backtrack[k](glyphs[p].glyph) {
+			return glyphs, 1, false
+		}
+	}
+	for k, p := 0, positions[len(positions)-1]; k < len(lookahead); k++ {
+		if p = t.next(glyphs, p, 1, flag); p < 0 || !lookahead[k](glyphs[p].glyph) {
+			return glyphs, 1, false
+		}
+	}
+	records := make([][2]int, substCount)
+	for k := range records {
+		records[k] = [2]int{t.r.u16(), t.r.u16()}
+	}
+	end := positions[len(positions)-1] + 1
+	for _, record := range records {
+		if record[0] >= len(positions) {
+			continue
+		}
+		p := positions[record[0]]
+		n := len(glyphs)
+		glyphs = t.applyAt(record[1], glyphs, p, depth+1)
+		// Positions after the substituted glyph shift with the glyphs that
+		// the substitution adds or removes
+		if delta := len(glyphs) - n; delta != 0 {
+			for k := range positions {
+				if positions[k] > p {
+					positions[k] += delta
+				}
+			}
+			end += delta
+		}
+	}
+	return glyphs, end - j, true
+}
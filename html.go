@@ -0,0 +1,1135 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HTMLType renders a subset of HTML and CSS that is larger than that of
+// HTMLBasicType: paragraphs and headings, nested lists, tables, images,
+// horizontal rules, alignment and the fonts and colors of text. See HTMLNew()
+// to create a receiver that is associated with the PDF document instance. In
+// the Link structure, the ClrR, ClrG and ClrB fields (0 through 255) define
+// the color of hyperlinks. The Bold, Italic and Underscore values define the
+// hyperlink style. Indent is the indentation of each level of lists and block
+// quotes, in the unit of measure specified in New().
+type HTMLType struct {
+	pdf  *Fpdf
+	Link struct {
+		ClrR, ClrG, ClrB         int
+		Bold, Italic, Underscore bool
+	}
+	Indent float64
+}
+
+// HTMLNew returns an instance that facilitates writing HTML in the specified
+// PDF file. It supersedes HTMLBasicNew(), whose renderer handles only a few
+// tags within flowing text.
+func (f *Fpdf) HTMLNew() (html HTMLType) {
+	html.pdf = f
+	html.Link.ClrR, html.Link.ClrG, html.Link.ClrB = 0, 0, 128
+	html.Link.Bold, html.Link.Italic, html.Link.Underscore = false, false, true
+	html.Indent = 24 / f.k
+	return
+}
+
+// Write prints htmlStr below the current position, between the left and
+// right margins, starting with the current font and text color. Text is laid
+// out in blocks, each made of lines of text spans as by MultiCellSpans(), and
+// pages are broken as needed. Upon method exit, the current position is at
+// the left margin below the last block, and the font and colors are those
+// in effect before the call.
+//
+// The supported elements are p, div, h1 to h6, blockquote, center, pre, br and
+// hr; ul and ol lists, which may be nested, with li items; table, with
+// thead, tbody, tfoot, tr, th and td elements whose colspan attribute is
+// honored; img, whose src attribute is an image name or file name as accepted
+// by Image(); a with an href attribute; b, strong, i, em, u, ins, s, strike,
+// del, code, tt, small, big, span and font, with its color, size and face
+// attributes. The align attribute sets the alignment of blocks and table
+// cells, the bgcolor attribute their background, and the width and height
+// attributes the size of images, tables and table cells, in pixels (1/96
+// inch) or percent of the available width. A table with a nonzero border
+// attribute has its cells outlined with the current draw color and line
+// width.
+//
+// Styles are taken from style attributes and from style elements, whose rules
+// may have selectors of the form tag, .class and tag.class, separated by
+// commas. The supported properties are color, background-color, background
+// (its color), font-family, font-size, font-weight, font-style,
+// text-decoration, text-align and, for images, tables and table cells, width
+// and height. The generic families serif, sans-serif and monospace stand for
+// Times, Helvetica and Courier, and other families must be core fonts or
+// fonts added with AddFont() or the like; unknown families are ignored.
+// Colors may be named or specified with #rgb, #rrggbb or rgb() notation.
+//
+// Text is translated to the cp1252 code page for core fonts, and to the code
+// page of the encoding of other fonts that are not UTF-8 fonts, if it is one
+// of those embedded in the package.
+//
+// lineHt indicates the line height of text of the initial font size in the
+// unit of measure specified in New(), and grows in proportion for larger
+// text. If lineHt is 0, the line height set with SetLineHeight() or
+// SetLineHeightFactor() is used.
+func (html *HTMLType) Write(lineHt float64, htmlStr string) {
+	f := html.pdf
+	if f.err != nil {
+		return
+	}
+	if f.currentFont.Name == "" {
+		f.SetErrorf("font has not been set; unable to render text")
+		return
+	}
+	root, css := htmlParse(htmlStr)
+	r := htmlRenderer{f: f, html: html, lineHt: f.textLineHt(lineHt), baseSizePt: f.fontSizePt,
+		rules: htmlParseCSS(css), trs: make(map[string]func(string) string)}
+	st := f.spanStateGet()
+	fillColor := f.color.fill
+	base := htmlStyle{family: f.fontFamily, sizePt: f.fontSizePt, align: "L",
+		bold: strings.Contains(f.fontStyle, "B"), italic: strings.Contains(f.fontStyle, "I"),
+		underline: f.underline, strike: f.strikeout}
+	r.block = base
+	if f.x != f.lMargin {
+		f.x = f.lMargin
+		f.y += r.lineHt
+	}
+	r.children(root, base)
+	r.flush()
+	if f.err == nil {
+		f.spanStatePut(st)
+		f.fillColorPut(fillColor)
+	}
+	f.x = f.lMargin
+}
+
+// htmlNode is an element or a text of a parsed HTML document.
+type htmlNode struct {
+	tag      string            // Lower case name of an element, empty for text
+	attrs    map[string]string // Attributes, with lower case names
+	text     string            // Text, with character references decoded
+	children []*htmlNode
+}
+
+// Elements without content
+var htmlVoidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "wbr": true,
+}
+
+// Elements laid out as blocks, below the previous content
+var htmlBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true,
+	"center": true, "dd": true, "div": true, "dl": true, "dt": true, "figure": true,
+	"footer": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "html": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true, "ul": true,
+}
+
+// Blocks followed by half a line of space
+var htmlSpacedTags = map[string]bool{
+	"blockquote": true, "dl": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "p": true, "pre": true,
+}
+
+// htmlParse returns the tree of the elements and text of htmlStr, whose root
+// is an element without a name, and the content of its style elements.
+// Elements left open are closed as HTML specifies for paragraphs, list items
+// and table parts, and unmatched end tags are ignored.
+func htmlParse(htmlStr string) (root *htmlNode, css string) {
+	root = &htmlNode{tag: "#root"}
+	stack := []*htmlNode{root}
+	// closeTo closes the elements up to the innermost one named in tags,
+	// unless one named in stops comes first.
+	closeTo := func(tags, stops string) {
+		for j := len(stack) - 1; j > 0; j-- {
+			tag := " " + stack[j].tag + " "
+			if strings.Contains(" "+tags+" ", tag) {
+				stack = stack[:j]
+				return
+			}
+			if strings.Contains(" "+stops+" ", tag) {
+				return
+			}
+		}
+	}
+	s := strings.Replace(htmlStr, "\r", "", -1)
+	for len(s) > 0 {
+		top := stack[len(stack)-1]
+		pos := strings.IndexByte(s, '<')
+		if pos < 0 {
+			pos = len(s)
+		}
+		if pos == 0 && (len(s) < 2 || !isHTMLTagStart(s[1])) {
+			pos = 1 + strings.IndexByte(s[1:], '<')
+			if pos == 0 {
+				pos = len(s)
+			}
+		}
+		if pos > 0 {
+			top.children = append(top.children, &htmlNode{text: html.UnescapeString(s[:pos])})
+			s = s[pos:]
+			continue
+		}
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			pos = strings.Index(s, "-->")
+			if pos < 0 {
+				pos = len(s) - 3
+			}
+			s = s[pos+3:]
+		case s[1] == '!' || s[1] == '?':
+			pos = strings.IndexByte(s, '>')
+			if pos < 0 {
+				pos = len(s) - 1
+			}
+			s = s[pos+1:]
+		case s[1] == '/':
+			pos = strings.IndexByte(s, '>')
+			if pos < 0 {
+				pos = len(s) - 1
+			}
+			tag := strings.ToLower(strings.TrimSpace(s[2:pos]))
+			s = s[pos+1:]
+			closeTo(tag, "")
+		default:
+			node, selfClose, rest := htmlTag(s)
+			s = rest
+			switch node.tag {
+			case "style", "script", "title":
+				// The content of these elements is not HTML
+				pos = strings.Index(strings.ToLower(s), "</"+node.tag)
+				if pos < 0 {
+					pos = len(s)
+				}
+				if node.tag == "style" {
+					css += s[:pos] + "\n"
+				}
+				s = s[pos:]
+				continue
+			case "li":
+				closeTo("li", "ul ol table")
+			case "dt", "dd":
+				closeTo("dt dd", "dl table")
+			case "td", "th":
+				closeTo("td th", "tr table")
+			case "tr":
+				closeTo("tr", "table")
+			case "thead", "tbody", "tfoot":
+				closeTo("thead tbody tfoot", "table")
+			}
+			if htmlBlockTags[node.tag] && top.tag == "p" {
+				stack = stack[:len(stack)-1]
+			}
+			top = stack[len(stack)-1]
+			top.children = append(top.children, node)
+			if !selfClose && !htmlVoidTags[node.tag] {
+				stack = append(stack, node)
+			}
+		}
+	}
+	return
+}
+
+// isHTMLTagStart returns true if the character c that follows a less-than
+// sign starts a tag, an end tag, a comment or a declaration.
+func isHTMLTagStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '/' || c == '!' || c == '?'
+}
+
+// htmlTag parses the start tag at the beginning of s, returning its element,
+// whether it is self-closing and the rest of s.
+func htmlTag(s string) (node *htmlNode, selfClose bool, rest string) {
+	const spaces = " \t\n\f"
+	j := 1
+	for j < len(s) && !strings.ContainsRune(spaces+"/>", rune(s[j])) {
+		j++
+	}
+	node = &htmlNode{tag: strings.ToLower(s[1:j]), attrs: make(map[string]string)}
+	skip := func() {
+		for j < len(s) && strings.ContainsRune(spaces, rune(s[j])) {
+			j++
+		}
+	}
+	for {
+		skip()
+		if j >= len(s) {
+			break
+		}
+		if s[j] == '>' {
+			j++
+			break
+		}
+		if s[j] == '/' {
+			selfClose = true
+			j++
+			continue
+		}
+		selfClose = false
+		k := j + 1
+		for k < len(s) && !strings.ContainsRune(spaces+"/>=", rune(s[k])) {
+			k++
+		}
+		key := strings.ToLower(s[j:k])
+		j = k
+		skip()
+		val := ""
+		if j < len(s) && s[j] == '=' {
+			j++
+			skip()
+			if j < len(s) && (s[j] == '"' || s[j] == '\'') {
+				k = strings.IndexByte(s[j+1:], s[j])
+				if k < 0 {
+					k = len(s) - j - 1
+				}
+				val = s[j+1 : j+1+k]
+				j = minInt(j+k+2, len(s))
+			} else {
+				k = j
+				for k < len(s) && !strings.ContainsRune(spaces+">", rune(s[k])) {
+					k++
+				}
+				val = s[j:k]
+				j = k
+			}
+		}
+		node.attrs[key] = html.UnescapeString(val)
+	}
+	return node, selfClose, s[j:]
+}
+
+// htmlRule is a rule of a style element, which applies to the elements of
+// its tag, if not empty, and of its class, if not empty.
+type htmlRule struct {
+	tag, class string
+	decls      [][2]string
+}
+
+// htmlParseCSS returns the rules of the style sheet css, in increasing order
+// of precedence. Rules with unsupported selectors are dropped.
+func htmlParseCSS(css string) (rules []htmlRule) {
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(css[start+2:], "*/")
+		if end < 0 {
+			css = css[:start]
+			break
+		}
+		css = css[:start] + " " + css[start+2+end+2:]
+	}
+	for {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(css[open:], '}')
+		if end < 0 {
+			end = len(css) - open
+		}
+		decls := htmlDecls(css[open+1 : open+end])
+		for _, sel := range strings.Split(css[:open], ",") {
+			sel = strings.TrimSpace(sel)
+			if sel == "" || strings.ContainsAny(sel, " \t\n>+~:#[*") {
+				continue
+			}
+			rule := htmlRule{tag: strings.ToLower(sel), decls: decls}
+			if pos := strings.IndexByte(sel, '.'); pos >= 0 {
+				rule.tag, rule.class = strings.ToLower(sel[:pos]), sel[pos+1:]
+				if strings.Contains(rule.class, ".") {
+					continue
+				}
+			}
+			rules = append(rules, rule)
+		}
+		css = css[minInt(open+end+1, len(css)):]
+	}
+	// Class selectors take precedence over tag selectors
+	specificity := func(rule htmlRule) (n int) {
+		if rule.tag != "" {
+			n++
+		}
+		if rule.class != "" {
+			n += 10
+		}
+		return
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return specificity(rules[i]) < specificity(rules[j]) })
+	return
+}
+
+// htmlDecls returns the property names, in lower case, and values of the
+// declarations of a style attribute or rule.
+func htmlDecls(s string) (decls [][2]string) {
+	for _, decl := range strings.Split(s, ";") {
+		if pos := strings.IndexByte(decl, ':'); pos > 0 {
+			val := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(decl[pos+1:]), "!important"))
+			decls = append(decls, [2]string{strings.ToLower(strings.TrimSpace(decl[:pos])), val})
+		}
+	}
+	return
+}
+
+// htmlColor parses a color in hexadecimal, rgb() or named form, returning
+// nil if the color is not recognized or is transparent.
+func htmlColor(s string) *RGBType {
+	paint, ok := svgColor(s)
+	if !ok || paint.none {
+		return nil
+	}
+	return &RGBType{R: int(math.Round(paint.r * 255)), G: int(math.Round(paint.g * 255)),
+		B: int(math.Round(paint.b * 255))}
+}
+
+// htmlStyle holds the properties in effect for an element.
+type htmlStyle struct {
+	family                          string
+	bold, italic, underline, strike bool
+	sizePt                          float64
+	color, background               *RGBType
+	align                           string
+}
+
+// Font sizes of the headings, relative to the initial font size
+var htmlHeadingSizes = map[string]float64{
+	"h1": 2, "h2": 1.5, "h3": 1.17, "h4": 1, "h5": .83, "h6": .67,
+}
+
+// Font sizes of the keywords of CSS and of the size attribute of font
+// elements, relative to the initial font size
+var htmlFontSizes = map[string]float64{
+	"xx-small": .6, "x-small": .75, "small": .89, "medium": 1, "large": 1.2,
+	"x-large": 1.5, "xx-large": 2, "1": .6, "2": .89, "3": 1, "4": 1.2, "5": 1.5,
+	"6": 2, "7": 3,
+}
+
+// htmlRenderer writes the elements of an HTML document to the current page.
+type htmlRenderer struct {
+	f          *Fpdf
+	html       *HTMLType
+	lineHt     float64
+	baseSizePt float64 // Initial font size
+	rules      []htmlRule
+	trs        map[string]func(string) string // Translators by code page
+	spans      []TextSpan                     // Text of the current block not yet printed
+	block      htmlStyle                      // Style of the current block
+	indent     float64                        // Indentation of the current block
+	pre        bool                           // Spaces and newlines of text are preserved
+	cell       bool                           // Blocks are collected within a table cell
+	link       string                         // Link of the current text
+	lists      int                            // Depth of the current list
+}
+
+func (r *htmlRenderer) children(node *htmlNode, st htmlStyle) {
+	for _, child := range node.children {
+		if r.f.err != nil {
+			return
+		}
+		r.element(child, st)
+	}
+}
+
+func (r *htmlRenderer) element(node *htmlNode, parent htmlStyle) {
+	if node.tag == "" {
+		r.text(node.text, parent)
+		return
+	}
+	st := r.style(node, parent)
+	switch node.tag {
+	case "head":
+	case "br":
+		r.spans = append(r.spans, TextSpan{Text: "\n", FontSize: st.sizePt})
+	case "img":
+		r.image(node)
+	case "hr":
+		r.rule(node)
+	case "a":
+		link := r.link
+		if href := node.attrs["href"]; href != "" {
+			r.link = href
+		}
+		r.children(node, st)
+		r.link = link
+	case "ul", "ol":
+		r.list(node, st)
+	case "table":
+		r.table(node, st)
+	default:
+		if htmlBlockTags[node.tag] {
+			r.blockElement(node, st)
+		} else {
+			r.children(node, st)
+		}
+	}
+}
+
+// style returns the properties of node, given those of its parent. Rules of
+// style elements take precedence over presentation attributes, and style
+// attribute declarations over both.
+func (r *htmlRenderer) style(node *htmlNode, parent htmlStyle) (st htmlStyle) {
+	st = parent
+	if htmlBlockTags[node.tag] || node.tag == "td" || node.tag == "th" || node.tag == "tr" {
+		// Backgrounds of blocks are not inherited
+		st.background = nil
+	}
+	switch node.tag {
+	case "b", "strong":
+		st.bold = true
+	case "i", "em", "cite", "var", "dfn":
+		st.italic = true
+	case "u", "ins":
+		st.underline = true
+	case "s", "strike", "del":
+		st.strike = true
+	case "code", "tt", "kbd", "samp", "pre":
+		st.family = "courier"
+	case "small":
+		st.sizePt /= 1.2
+	case "big":
+		st.sizePt *= 1.2
+	case "center":
+		st.align = "C"
+	case "th":
+		st.bold = true
+		st.align = "C"
+	case "a":
+		if node.attrs["href"] != "" {
+			link := r.html.Link
+			st.color = &RGBType{R: link.ClrR, G: link.ClrG, B: link.ClrB}
+			st.bold = st.bold || link.Bold
+			st.italic = st.italic || link.Italic
+			st.underline = st.underline || link.Underscore
+		}
+	case "font":
+		if val, ok := node.attrs["color"]; ok {
+			r.property(&st, parent, "color", val)
+		}
+		if val, ok := node.attrs["face"]; ok {
+			r.property(&st, parent, "font-family", val)
+		}
+		if scale, ok := htmlFontSizes[strings.TrimSpace(node.attrs["size"])]; ok {
+			st.sizePt = r.baseSizePt * scale
+		}
+	}
+	if scale, ok := htmlHeadingSizes[node.tag]; ok {
+		st.bold = true
+		st.sizePt = r.baseSizePt * scale
+	}
+	if val, ok := node.attrs["align"]; ok {
+		r.property(&st, parent, "text-align", val)
+	}
+	if val, ok := node.attrs["bgcolor"]; ok {
+		r.property(&st, parent, "background-color", val)
+	}
+	classes := strings.Fields(node.attrs["class"])
+	for _, rule := range r.rules {
+		if rule.tag != "" && rule.tag != node.tag {
+			continue
+		}
+		if rule.class != "" {
+			found := false
+			for _, class := range classes {
+				found = found || class == rule.class
+			}
+			if !found {
+				continue
+			}
+		}
+		for _, decl := range rule.decls {
+			r.property(&st, parent, decl[0], decl[1])
+		}
+	}
+	for _, decl := range htmlDecls(node.attrs["style"]) {
+		r.property(&st, parent, decl[0], decl[1])
+	}
+	return
+}
+
+// property sets the property key of st to val. Unsupported properties and
+// invalid values are ignored.
+func (r *htmlRenderer) property(st *htmlStyle, parent htmlStyle, key, val string) {
+	val = strings.TrimSpace(val)
+	lower := strings.ToLower(val)
+	switch key {
+	case "color":
+		if c := htmlColor(val); c != nil {
+			st.color = c
+		}
+	case "background-color", "background":
+		st.background = nil
+		for _, field := range strings.Fields(val) {
+			if c := htmlColor(field); c != nil {
+				st.background = c
+			}
+		}
+	case "font-family":
+		if family := r.family(val); family != "" {
+			st.family = family
+		}
+	case "font-size":
+		if size := r.fontSize(lower, parent.sizePt); size > 0 {
+			st.sizePt = size
+		}
+	case "font-weight":
+		switch lower {
+		case "bold", "bolder":
+			st.bold = true
+		case "normal", "lighter":
+			st.bold = false
+		default:
+			if weight, err := strconv.Atoi(lower); err == nil {
+				st.bold = weight >= 600
+			}
+		}
+	case "font-style":
+		st.italic = lower == "italic" || lower == "oblique"
+	case "text-decoration", "text-decoration-line":
+		st.underline = strings.Contains(lower, "underline")
+		st.strike = strings.Contains(lower, "line-through")
+	case "text-align":
+		switch lower {
+		case "left":
+			st.align = "L"
+		case "right":
+			st.align = "R"
+		case "center":
+			st.align = "C"
+		case "justify":
+			st.align = "J"
+		}
+	}
+}
+
+// family returns the font family of the first of the comma separated
+// families of val that is known, or an empty string if none is.
+func (r *htmlRenderer) family(val string) string {
+	for _, family := range strings.Split(val, ",") {
+		family = strings.ToLower(strings.Trim(strings.TrimSpace(family), `"'`))
+		switch family {
+		case "serif":
+			return "times"
+		case "sans-serif":
+			return "helvetica"
+		case "monospace":
+			return "courier"
+		}
+		if r.f.coreFonts[family] || family == "arial" || r.font(family) != nil {
+			return family
+		}
+	}
+	return ""
+}
+
+// font returns the definition of a font of family that has been added to the
+// document, or nil if there is none.
+func (r *htmlRenderer) font(family string) *fontDefType {
+	family = fontFamilyEscape(family)
+	for _, style := range []string{"", "B", "I", "BI"} {
+		if def, ok := r.f.fonts[family+style]; ok {
+			return &def
+		}
+	}
+	return nil
+}
+
+// fontSize returns the font size in points specified by val, relative to the
+// font size of the parent, or 0 if val is invalid.
+func (r *htmlRenderer) fontSize(val string, parentPt float64) float64 {
+	switch {
+	case val == "smaller":
+		return parentPt / 1.2
+	case val == "larger":
+		return parentPt * 1.2
+	case strings.HasSuffix(val, "rem"):
+		return svgLength(strings.TrimSuffix(val, "rem"), 0, 0) * r.baseSizePt
+	case strings.HasSuffix(val, "em"), strings.HasSuffix(val, "%"):
+		return svgLength(val, parentPt, parentPt)
+	}
+	if scale, ok := htmlFontSizes[val]; ok && val[0] > '9' {
+		return r.baseSizePt * scale
+	}
+	// Lengths are converted from pixels to points
+	return svgLength(val, 0, 0) * 72 / 96
+}
+
+// length returns the length of the attribute or style property key of node
+// in the unit of measure specified in New(), percentages being taken of
+// whole, or 0 if node has none. Numbers without a unit are pixels.
+func (r *htmlRenderer) length(node *htmlNode, key string, whole float64) float64 {
+	val := node.attrs[key]
+	for _, decl := range htmlDecls(node.attrs["style"]) {
+		if decl[0] == key {
+			val = decl[1]
+		}
+	}
+	val = strings.ToLower(strings.TrimSpace(val))
+	if strings.HasSuffix(val, "%") {
+		return svgLength(val, whole, 0)
+	}
+	return math.Max(0, svgLength(val, 0, 0)*72/96/r.f.k)
+}
+
+// translate returns txtStr translated to the code page of the font family,
+// as required by fonts that are not UTF-8 fonts.
+func (r *htmlRenderer) translate(family, txtStr string) string {
+	cpStr := ""
+	switch def := r.font(family); {
+	case family == "symbol" || family == "zapfdingbats":
+		return txtStr
+	case def != nil && def.Tp != "Core":
+		if def.Tp == "UTF8" {
+			return txtStr
+		}
+		cpStr = strings.ToLower(def.Enc)
+		if _, ok := embeddedMapList[cpStr]; !ok {
+			return txtStr
+		}
+	case def == nil && !r.f.coreFonts[family] && family != "arial":
+		return txtStr
+	}
+	tr, ok := r.trs[cpStr]
+	if !ok {
+		tr = r.f.UnicodeTranslatorFromDescriptor(cpStr)
+		r.trs[cpStr] = tr
+	}
+	return tr(txtStr)
+}
+
+// span returns the span of the text txtStr in the style st.
+func (r *htmlRenderer) span(st htmlStyle, txtStr string) TextSpan {
+	span := TextSpan{Text: r.translate(st.family, txtStr), FontFamily: st.family, FontSize: st.sizePt,
+		TextColor: st.color, FillColor: st.background, LinkStr: r.link}
+	for _, flag := range []struct {
+		on    bool
+		style string
+	}{{st.bold, "B"}, {st.italic, "I"}, {st.underline, "U"}, {st.strike, "S"}} {
+		if flag.on {
+			span.FontStyle += flag.style
+		}
+	}
+	return span
+}
+
+// text adds the text txtStr of style st to the current block. Unless spaces
+// are preserved, runs of spaces and newlines are collapsed into a space,
+// which is dropped at the beginning of a line.
+func (r *htmlRenderer) text(txtStr string, st htmlStyle) {
+	if !r.pre {
+		var buf strings.Builder
+		space := false
+		for _, c := range txtStr {
+			if strings.ContainsRune(" \t\n\f", c) {
+				space = true
+				continue
+			}
+			if space {
+				buf.WriteByte(' ')
+				space = false
+			}
+			buf.WriteRune(c)
+		}
+		if space {
+			buf.WriteByte(' ')
+		}
+		txtStr = buf.String()
+		if n := len(r.spans); n == 0 || r.spans[n-1].ImageName == "" &&
+			(r.spans[n-1].Text == "" || strings.HasSuffix(r.spans[n-1].Text, " ") ||
+				strings.HasSuffix(r.spans[n-1].Text, "\n")) {
+			txtStr = strings.TrimLeft(txtStr, " ")
+		}
+	}
+	if txtStr != "" {
+		r.spans = append(r.spans, r.span(st, txtStr))
+	}
+}
+
+// image adds the image of an img element to the current block.
+func (r *htmlRenderer) image(node *htmlNode) {
+	src := strings.TrimSpace(node.attrs["src"])
+	if src == "" {
+		return
+	}
+	f := r.f
+	whole := f.w - f.rMargin - f.lMargin - r.indent
+	r.spans = append(r.spans, TextSpan{ImageName: src, ImageWidth: r.length(node, "width", whole),
+		ImageHeight: r.length(node, "height", whole), LinkStr: r.link})
+}
+
+// flush prints the text of the current block not yet printed, or, within a
+// table cell, ends its last line.
+func (r *htmlRenderer) flush() {
+	spans := r.spans
+	for len(spans) > 0 {
+		last := &spans[len(spans)-1]
+		if last.ImageName != "" {
+			break
+		}
+		last.Text = strings.TrimRight(last.Text, " \n")
+		if last.Text != "" {
+			break
+		}
+		spans = spans[:len(spans)-1]
+	}
+	if r.cell {
+		if len(spans) > 0 {
+			r.spans = append(spans, TextSpan{Text: "\n"})
+		}
+		return
+	}
+	r.spans = nil
+	if len(spans) == 0 {
+		return
+	}
+	f := r.f
+	f.x = f.lMargin + r.indent
+	fill := r.block.background != nil
+	if fill {
+		fillColor := f.color.fill
+		defer f.fillColorPut(fillColor)
+		f.setFillColor(r.block.background.R, r.block.background.G, r.block.background.B)
+	}
+	f.MultiCellSpans(f.w-f.rMargin-f.x, r.spansLineHt(spans), spans, "", r.block.align, fill)
+}
+
+// spansLineHt returns the line height of spans, which grows in proportion
+// with their largest font size.
+func (r *htmlRenderer) spansLineHt(spans []TextSpan) float64 {
+	sizePt := r.baseSizePt
+	for _, span := range spans {
+		sizePt = math.Max(sizePt, span.FontSize)
+	}
+	return r.lineHt * sizePt / r.baseSizePt
+}
+
+// blockElement lays out an element that is a block.
+func (r *htmlRenderer) blockElement(node *htmlNode, st htmlStyle) {
+	r.flush()
+	block, indent, pre := r.block, r.indent, r.pre
+	r.block = st
+	switch node.tag {
+	case "blockquote", "dd":
+		r.indent += r.html.Indent
+	case "pre":
+		r.pre = true
+	}
+	// The background of the block is that of its lines
+	st.background = nil
+	r.children(node, st)
+	r.flush()
+	r.block, r.indent, r.pre = block, indent, pre
+	if htmlSpacedTags[node.tag] && !r.cell {
+		r.f.y += r.lineHt / 2
+	}
+}
+
+// rule draws the horizontal rule of an hr element.
+func (r *htmlRenderer) rule(node *htmlNode) {
+	r.flush()
+	if r.cell {
+		return
+	}
+	f := r.f
+	x1, x2 := f.lMargin+r.indent, f.w-f.rMargin
+	if w := r.length(node, "width", x2-x1); w > 0 && w < x2-x1 {
+		switch strings.ToLower(node.attrs["align"]) {
+		case "left":
+			x2 = x1 + w
+		case "right":
+			x1 = x2 - w
+		default:
+			x1 += (x2 - x1 - w) / 2
+			x2 = x1 + w
+		}
+	}
+	y := f.imageFlow(r.lineHt) + r.lineHt/2
+	f.Line(x1, y, x2, y)
+	f.x = f.lMargin
+}
+
+// list lays out the items of a ul or ol element, each preceded by a bullet
+// or its number.
+func (r *htmlRenderer) list(node *htmlNode, st htmlStyle) {
+	r.flush()
+	f := r.f
+	block, indent := r.block, r.indent
+	r.indent += r.html.Indent
+	r.lists++
+	num := 1
+	if start, err := strconv.Atoi(node.attrs["start"]); err == nil {
+		num = start
+	}
+	for _, child := range node.children {
+		if f.err != nil {
+			break
+		}
+		if child.tag != "li" {
+			r.element(child, st)
+			continue
+		}
+		itemSt := r.style(child, st)
+		r.flush()
+		if val, err := strconv.Atoi(child.attrs["value"]); err == nil {
+			num = val
+		}
+		marker := "•"
+		if node.tag == "ol" {
+			marker = htmlListNumber(num, node.attrs["type"]) + "."
+		}
+		page, y := f.page, f.y
+		r.marker(marker, itemSt)
+		r.block = itemSt
+		itemSt.background = nil
+		r.children(child, itemSt)
+		r.flush()
+		if !r.cell && f.page == page && f.y == y {
+			// An empty item takes a line
+			f.y += r.lineHt
+		}
+		num++
+	}
+	r.block, r.indent = block, indent
+	r.lists--
+	if r.lists == 0 && !r.cell {
+		f.y += r.lineHt / 2
+	}
+}
+
+// htmlListNumber returns the number n of a list item in the numbering style
+// typ of the type attribute of an ol element.
+func htmlListNumber(n int, typ string) string {
+	switch typ {
+	case "a", "A":
+		if n < 1 {
+			break
+		}
+		str := ""
+		for ; n > 0; n = (n - 1) / 26 {
+			str = string(rune('a'+(n-1)%26)) + str
+		}
+		if typ == "A" {
+			str = strings.ToUpper(str)
+		}
+		return str
+	case "i", "I":
+		if n < 1 || n >= 4000 {
+			break
+		}
+		str := ""
+		for _, numeral := range []struct {
+			value int
+			str   string
+		}{{1000, "m"}, {900, "cm"}, {500, "d"}, {400, "cd"}, {100, "c"}, {90, "xc"},
+			{50, "l"}, {40, "xl"}, {10, "x"}, {9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"}} {
+			for ; n >= numeral.value; n -= numeral.value {
+				str += numeral.str
+			}
+		}
+		if typ == "I" {
+			str = strings.ToUpper(str)
+		}
+		return str
+	}
+	return strconv.Itoa(n)
+}
+
+// marker prints the marker of a list item in the indentation of the item,
+// ending on the right at the start of the item, or adds it to the text of a
+// table cell.
+func (r *htmlRenderer) marker(marker string, st htmlStyle) {
+	if r.cell {
+		r.spans = append(r.spans, r.span(st, marker+" "))
+		return
+	}
+	f := r.f
+	span := r.span(st, marker)
+	span.LinkStr = ""
+	fontSt := f.spanStateGet()
+	f.spanApply(span, fontSt)
+	f.x = f.lMargin + r.indent - r.html.Indent
+	f.CellFormat(r.html.Indent, r.spansLineHt([]TextSpan{span}), span.Text, "", 0, "R", false, 0, "")
+	f.spanStatePut(fontSt)
+}
+
+// htmlCell is a cell of a table row.
+type htmlCell struct {
+	node  *htmlNode
+	spans []TextSpan
+	st    htmlStyle
+	col   int // Index of the first column of the cell
+	cols  int // Number of columns spanned by the cell
+}
+
+// table lays out a table element, one row below the other. A row is moved
+// to the next page if it does not fit on the current one. Tables within a
+// table cell are laid out as their text.
+func (r *htmlRenderer) table(node *htmlNode, st htmlStyle) {
+	r.flush()
+	if r.cell {
+		r.children(node, st)
+		r.flush()
+		return
+	}
+	f := r.f
+	var rows []*htmlNode
+	for _, child := range node.children {
+		switch child.tag {
+		case "tr":
+			rows = append(rows, child)
+		case "thead", "tbody", "tfoot":
+			for _, row := range child.children {
+				if row.tag == "tr" {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	// Cells
+	cells := make([][]htmlCell, len(rows))
+	cols := 0
+	for j, row := range rows {
+		rowSt := r.style(row, st)
+		col := 0
+		for _, child := range row.children {
+			if child.tag != "td" && child.tag != "th" {
+				continue
+			}
+			cell := htmlCell{node: child, st: r.style(child, rowSt), col: col, cols: 1}
+			if cell.st.background == nil {
+				cell.st.background = rowSt.background
+			}
+			if n, err := strconv.Atoi(child.attrs["colspan"]); err == nil && n > 1 {
+				cell.cols = n
+			}
+			cell.spans = r.cellSpans(child, cell.st)
+			cells[j] = append(cells[j], cell)
+			col += cell.cols
+		}
+		cols = max(cols, col)
+	}
+	if cols == 0 || f.err != nil {
+		return
+	}
+	// Widths of the columns, set by the cells that span a single column, or
+	// shared equally by the columns that have none
+	tableW := f.w - f.rMargin - f.lMargin - r.indent
+	if w := r.length(node, "width", tableW); w > 0 {
+		tableW = math.Min(w, tableW)
+	}
+	widths := make([]float64, cols)
+	for _, row := range cells {
+		for _, cell := range row {
+			if cell.cols == 1 && widths[cell.col] == 0 {
+				widths[cell.col] = r.length(cell.node, "width", tableW)
+			}
+		}
+	}
+	rest, unset := tableW, 0
+	for _, w := range widths {
+		rest -= w
+		if w == 0 {
+			unset++
+		}
+	}
+	for j, w := range widths {
+		if w == 0 {
+			widths[j] = math.Max(0, rest) / float64(unset)
+		}
+	}
+	border := node.attrs["border"] != "" && strings.TrimSpace(node.attrs["border"]) != "0"
+	for j := range rows {
+		r.tableRow(cells[j], widths, border)
+		if f.err != nil {
+			return
+		}
+	}
+	f.x = f.lMargin
+	f.y += r.lineHt / 2
+}
+
+// cellSpans returns the text of the table cell node, whose blocks end lines.
+func (r *htmlRenderer) cellSpans(node *htmlNode, st htmlStyle) []TextSpan {
+	spans, cell := r.spans, r.cell
+	r.spans, r.cell = nil, true
+	// The background of the cell is filled by the row
+	st.background = nil
+	r.children(node, st)
+	r.flush()
+	cellSpans := r.spans
+	r.spans, r.cell = spans, cell
+	if n := len(cellSpans); n > 0 && cellSpans[n-1].Text == "\n" {
+		cellSpans = cellSpans[:n-1]
+	}
+	return cellSpans
+}
+
+// tableRow prints a row of table cells with the widths of the columns, as
+// high as its highest cell.
+func (r *htmlRenderer) tableRow(cells []htmlCell, widths []float64, border bool) {
+	f := r.f
+	cellW := func(cell htmlCell) (w float64) {
+		for _, colW := range widths[cell.col:minInt(cell.col+cell.cols, len(widths))] {
+			w += colW
+		}
+		return
+	}
+	rowHt := r.lineHt
+	for _, cell := range cells {
+		w := cellW(cell) - 2*f.cMargin
+		ht := 0.0
+		lineHt := r.spansLineHt(cell.spans)
+		for _, line := range f.spanLines(cell.spans, func(int) float64 { return w }) {
+			lineHt, _ := f.spanLineHeight(cell.spans, line, lineHt)
+			ht += lineHt
+		}
+		rowHt = math.Max(rowHt, ht)
+	}
+	if f.err != nil {
+		return
+	}
+	y := f.imageFlow(rowHt)
+	x := f.lMargin + r.indent
+	auto := f.autoPageBreak
+	f.autoPageBreak = false
+	for _, cell := range cells {
+		w := cellW(cell)
+		cx := x
+		for _, colW := range widths[:cell.col] {
+			cx += colW
+		}
+		if c := cell.st.background; c != nil {
+			fillColor := f.color.fill
+			f.setFillColor(c.R, c.G, c.B)
+			f.Rect(cx, y, w, rowHt, "F")
+			f.fillColorPut(fillColor)
+		}
+		if len(cell.spans) > 0 {
+			f.x, f.y = cx, y
+			f.MultiCellSpans(w, r.spansLineHt(cell.spans), cell.spans, "", cell.st.align, false)
+		}
+		if border {
+			f.Rect(cx, y, w, rowHt, "D")
+		}
+	}
+	f.autoPageBreak = auto
+	f.x, f.y = f.lMargin, y+rowHt
+}
@@ -95,7 +95,7 @@ func HTMLBasicTokenize(htmlStr string) (list []HTMLBasicSegmentType) {
 // only hyperlinks and bold, italic and underscore attributes. In the Link
 // structure, the ClrR, ClrG and ClrB fields (0 through 255) define the color
 // of hyperlinks. The Bold, Italic and Underscore values define the hyperlink
-// style.
+// style. See HTMLType for a renderer of a larger subset of HTML.
 type HTMLBasicType struct {
 	pdf  *Fpdf
 	Link struct {
@@ -122,7 +122,10 @@ func (f *Fpdf) HTMLBasicNew() (html HTMLBasicType) {
 // current position is left at the end of the text.
 //
 // lineHt indicates the line height in the unit of measure specified in New().
+// If lineHt is 0, the line height set with SetLineHeight() or
+// SetLineHeightFactor() is used.
 func (html *HTMLBasicType) Write(lineHt float64, htmlStr string) {
+	lineHt = html.pdf.textLineHt(lineHt)
 	var boldLvl, italicLvl, underscoreLvl, linkBold, linkItalic, linkUnderscore int
 	var textR, textG, textB = html.pdf.GetTextColor()
 	var hrefStr string
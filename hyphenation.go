@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// HyphenatorType hyphenates words with the patterns of a language, following
+// the algorithm of Frank Liang that TeX uses. Patterns for many languages are
+// available from the hyph-utf8 project of the TeX community. See
+// NewHyphenator() to create an instance and SetHyphenator() to hyphenate the
+// text of a document with it.
+type HyphenatorType struct {
+	// The minimum number of letters before and after a hyphen, which are 2
+	// and 3 by default.
+	LeftMin, RightMin int
+	patterns          map[string][]int // Values between the letters of patterns
+	exceptions        map[string][]int // Hyphen positions of exceptional words
+	maxLen            int              // Number of letters of the longest pattern
+}
+
+// NewHyphenator returns a hyphenator for the patterns read from r, which are
+// given in the format of TeX. Patterns such as "1ba" or "ab5c" are letters
+// interleaved with digits, odd digits allowing a hyphen between the letters
+// and even ones preventing it, and a dot marks the beginning or end of a
+// word. Words with hyphens such as "ta-ble" are exceptions that are
+// hyphenated as given. The patterns may be enclosed by the \patterns{} and
+// \hyphenation{} commands of TeX, as in the pattern files of hyph-utf8. Text
+// from a percent sign to the end of a line is a comment.
+func NewHyphenator(r io.Reader) (h *HyphenatorType, err error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return
+	}
+	h = &HyphenatorType{
+		LeftMin:    2,
+		RightMin:   3,
+		patterns:   make(map[string][]int),
+		exceptions: make(map[string][]int),
+	}
+	comment := regexp.MustCompile(`%[^\n]*`)
+	command := regexp.MustCompile(`\\(patterns|hyphenation)\s*\{([^}]*)\}`)
+	str := comment.ReplaceAllString(string(data), "")
+	if command.MatchString(str) {
+		for _, m := range command.FindAllStringSubmatch(str, -1) {
+			for _, field := range strings.Fields(m[2]) {
+				if m[1] == "patterns" {
+					err = h.addPattern(field)
+				} else {
+					h.addException(field)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		return
+	}
+	for _, field := range strings.Fields(str) {
+		if strings.Contains(field, "-") {
+			h.addException(field)
+		} else if err = h.addPattern(field); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// addPattern adds a pattern such as ".ab1c".
+func (h *HyphenatorType) addPattern(pattern string) error {
+	var letters []rune
+	values := []int{0}
+	for _, r := range strings.ToLower(pattern) {
+		switch {
+		case r >= '0' && r <= '9':
+			values[len(values)-1] = int(r - '0')
+		case r == '.' || unicode.IsLetter(r) || unicode.IsMark(r) || r == '\'' || r == '’':
+			letters = append(letters, r)
+			values = append(values, 0)
+		default:
+			return fmt.Errorf("invalid hyphenation pattern: %s", pattern)
+		}
+	}
+	h.patterns[string(letters)] = values
+	if len(letters) > h.maxLen {
+		h.maxLen = len(letters)
+	}
+	return nil
+}
+
+// addException adds a word with hyphens such as "ta-ble".
+func (h *HyphenatorType) addException(word string) {
+	var letters []rune
+	var positions []int
+	for _, r := range strings.ToLower(word) {
+		if r == '-' {
+			positions = append(positions, len(letters))
+		} else {
+			letters = append(letters, r)
+		}
+	}
+	h.exceptions[string(letters)] = positions
+}
+
+// Hyphenate returns the parts of word between the positions at which it can
+// be hyphenated. Characters other than letters at the beginning and end of
+// word, such as punctuation, are kept with the first and last part.
+func (h *HyphenatorType) Hyphenate(word string) (parts []string) {
+	runes := []rune(word)
+	start := 0
+	for _, p := range h.points(runes) {
+		parts = append(parts, string(runes[start:p]))
+		start = p
+	}
+	return append(parts, string(runes[start:]))
+}
+
+// points returns the positions, in increasing order, before which the
+// characters of word can be hyphenated.
+func (h *HyphenatorType) points(word []rune) (list []int) {
+	// The letters of the word, without punctuation around them
+	start, end := 0, len(word)
+	for start < end && !unicode.IsLetter(word[start]) {
+		start++
+	}
+	for end > start && !unicode.IsLetter(word[end-1]) {
+		end--
+	}
+	letters := []rune(strings.ToLower(string(word[start:end])))
+	if len(letters) != end-start || len(letters) < h.LeftMin+h.RightMin {
+		return
+	}
+	for _, r := range letters {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) {
+			return
+		}
+	}
+	allowed := func(p int) bool {
+		return p >= h.LeftMin && p <= len(letters)-h.RightMin && p > 0
+	}
+	if positions, ok := h.exceptions[string(letters)]; ok {
+		for _, p := range positions {
+			if allowed(p) {
+				list = append(list, start+p)
+			}
+		}
+		return
+	}
+	dotted := append(append([]rune{'.'}, letters...), '.')
+	values := make([]int, len(dotted)+1)
+	for i := range dotted {
+		for j := i + 1; j <= len(dotted) && j-i <= h.maxLen; j++ {
+			for k, v := range h.patterns[string(dotted[i:j])] {
+				if v > values[i+k] {
+					values[i+k] = v
+				}
+			}
+		}
+	}
+	// A hyphen before the letter p is governed by the value that precedes it
+	// in the dotted word
+	for p := 1; p < len(letters); p++ {
+		if values[p+1]%2 == 1 && allowed(p) {
+			list = append(list, start+p)
+		}
+	}
+	return
+}
+
+// SetHyphenator sets the hyphenator with which the words of following text
+// are hyphenated when they do not fit on a line, or disables hyphenation if h
+// is nil, which is the default. Hyphenation applies to MultiCell() and to the
+// paragraphs of ParagraphNew(). Text in single-byte encodings is hyphenated
+// as if encoded in ISO-8859-1, which agrees with cp1252 on letters.
+func (f *Fpdf) SetHyphenator(h *HyphenatorType) {
+	f.hyphenator = h
+}
+
+// hyphenPoints returns the positions, in increasing order, before which the
+// word runes can be hyphenated, or nil if hyphenation is disabled.
+func (f *Fpdf) hyphenPoints(runes []rune) []int {
+	if f.hyphenator == nil {
+		return nil
+	}
+	return f.hyphenator.points(runes)
+}
+
+// runesString returns the text of runes in the encoding of the current font.
+// The runes of text in single-byte encodings are its bytes.
+func (f *Fpdf) runesString(runes []rune) string {
+	if f.isCurrentUTF8 {
+		return string(runes)
+	}
+	b := make([]byte, len(runes))
+	for j, r := range runes {
+		b[j] = byte(r)
+	}
+	return string(b)
+}
+
+// textRunes returns the runes of text in the encoding of the current font.
+func (f *Fpdf) textRunes(txtStr string) []rune {
+	if f.isCurrentUTF8 {
+		return []rune(txtStr)
+	}
+	runes := make([]rune, len(txtStr))
+	for j := 0; j < len(txtStr); j++ {
+		runes[j] = rune(txtStr[j])
+	}
+	return runes
+}
+
+// hyphenBreak returns the position before which the line of runes that
+// starts at j and overflows at i is best hyphenated, given the position sep of
+// the last space of the line or -1, or 0 if the word that overflows cannot be
+// hyphenated to fit in wmax.
+func (f *Fpdf) hyphenBreak(runes []rune, j, sep, i, wmax int) int {
+	if f.hyphenator == nil {
+		return 0
+	}
+	start := j
+	if sep >= j {
+		start = sep + 1
+	}
+	end := i
+	for end < len(runes) && !unicode.IsSpace(runes[end]) && !isChinese(runes[end]) {
+		end++
+	}
+	points := f.hyphenPoints(runes[start:end])
+	for k := len(points) - 1; k >= 0; k-- {
+		if p := start + points[k]; p > j && f.GetSpacedStringWidth(f.runesString(runes[j:p])+"-")*1000/f.fontSize <= float64(wmax) {
+			return p
+		}
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"sync"
+)
+
+// ImageCache retains parsed images, including their compressed data, so that
+// they need not be read and parsed again by other documents. A single cache
+// may be attached to any number of Fpdf instances with SetImageCache(),
+// including instances used concurrently by different goroutines.
+//
+// Images are cached by the name with which they are registered, be it a file
+// name or the name passed to RegisterImageOptionsReader(), together with the
+// options used to parse them. A cached image is therefore assumed not to
+// change. Images registered with RegisterImageOptionsReaderFunc() or
+// RegisterGoImage() are not cached.
+type ImageCache struct {
+	mu     sync.Mutex
+	images map[imageCacheKey]*ImageInfoType
+}
+
+type imageCacheKey struct {
+	name    string
+	options ImageOptions
+}
+
+// NewImageCache returns an empty image cache.
+func NewImageCache() *ImageCache {
+	return &ImageCache{images: make(map[imageCacheKey]*ImageInfoType)}
+}
+
+// Len returns the number of images held by the cache.
+func (c *ImageCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.images)
+}
+
+// Clear removes all images from the cache.
+func (c *ImageCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.images = make(map[imageCacheKey]*ImageInfoType)
+}
+
+func newImageCacheKey(name string, options ImageOptions) imageCacheKey {
+	options.ImageType = normalizeImageType(options.ImageType)
+	// Options that only govern placement or fetching do not affect the parsed
+	// image
+	options.AllowNegativePosition = false
+	options.MaxDPI = 0
+	options.MaxSize = 0
+	options.Fit, options.AlignStr = "", ""
+	options.Rotate, options.FlipH, options.FlipV = 0, false, false
+	return imageCacheKey{name: name, options: options}
+}
+
+// get returns a copy of the cached image for a document with the scale
+// factor k, or nil if there is none. The image data is shared with the cache
+// and must not be modified.
+func (c *ImageCache) get(name string, options ImageOptions, k float64) *ImageInfoType {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	info, ok := c.images[newImageCacheKey(name, options)]
+	if !ok {
+		return nil
+	}
+	return info.clone(k)
+}
+
+func (c *ImageCache) put(name string, options ImageOptions, info *ImageInfoType) {
+	cp := info.clone(info.scale)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.images[newImageCacheKey(name, options)] = cp
+}
+
+// clone returns a copy of info, including the mask and alternates attached to
+// it, for a document with the scale factor k. The object numbers and
+// attachments of the copy are its own, while the image data, which is not
+// modified once parsed, is shared.
+func (info *ImageInfoType) clone(k float64) *ImageInfoType {
+	cp := *info
+	cp.scale = k
+	cp.n = 0
+	cp.placed = false
+	cp.pal = append([]byte(nil), info.pal...)
+	cp.trns = append([]int(nil), info.trns...)
+	cp.icc = append([]byte(nil), info.icc...)
+	if info.mask != nil {
+		cp.mask = info.mask.clone(k)
+	}
+	cp.alts = nil
+	for _, alt := range info.alts {
+		cp.alts = append(cp.alts, imageAltType{img: alt.img.clone(k), print: alt.print})
+	}
+	return &cp
+}
+
+// SetImageCache attaches the specified image cache to the document. Images
+// registered subsequently are taken from the cache if present and are added
+// to it otherwise. Pass nil to detach the cache.
+func (f *Fpdf) SetImageCache(cache *ImageCache) {
+	f.imageCache = cache
+}
+
+// cachedImage registers the image held by the attached cache, if any, as
+// imgName and returns it. Nil is returned if the image is not cached.
+func (f *Fpdf) cachedImage(imgName string, options ImageOptions) (info *ImageInfoType) {
+	if f.imageCache == nil || f.err != nil {
+		return
+	}
+	// The cached image may have been parsed for a document with other units
+	info = f.imageCache.get(imgName, options, f.k)
+	if info == nil {
+		return
+	}
+	f.storeImage(imgName, info)
+	if len(info.smask) > 0 && f.pdfVersion < "1.4" {
+		f.pdfVersion = "1.4"
+	}
+	return
+}
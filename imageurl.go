@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// RegisterImageURL registers the image at urlStr as imgName, adding it to the
+// PDF but not adding it to the page. Use Image() with imgName to add the image
+// to the page. The response body is passed directly to the image parser
+// without being buffered first. The request is canceled if ctx is done before
+// the image has been read.
+//
+// If options.ImageType is empty, the type is taken from the Content-Type
+// header of the response. If options.MaxSize is greater than zero, an image
+// larger than MaxSize bytes is rejected, whether or not the server announces
+// its length. An error is set if the server does not respond with a success
+// status.
+func (f *Fpdf) RegisterImageURL(ctx context.Context, imgName, urlStr string, options ImageOptions) (info *ImageInfoType) {
+	if f.err != nil {
+		return
+	}
+	info, ok := f.images[imgName]
+	if ok {
+		return
+	}
+	req, err := http.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		f.err = err
+		return
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		f.err = err
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		f.err = fmt.Errorf("unable to fetch image %s: %s", urlStr, resp.Status)
+		return
+	}
+	if options.MaxSize > 0 && resp.ContentLength > options.MaxSize {
+		f.err = imageSizeError(urlStr, options.MaxSize)
+		return
+	}
+	if options.ImageType == "" {
+		mimeStr, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			f.err = fmt.Errorf("unable to determine type of image %s: %s", urlStr, err)
+			return
+		}
+		options.ImageType = f.ImageTypeFromMime(mimeStr)
+	}
+	var r io.Reader = resp.Body
+	if options.MaxSize > 0 {
+		r = &imageLimitReader{r: r, n: options.MaxSize, max: options.MaxSize, urlStr: urlStr}
+	}
+	return f.RegisterImageOptionsReader(imgName, options, r)
+}
+
+func imageSizeError(urlStr string, max int64) error {
+	return fmt.Errorf("image %s exceeds maximum size of %d bytes", urlStr, max)
+}
+
+// imageLimitReader is like io.LimitedReader except that reading beyond the
+// limit is reported as an error rather than as the end of the stream, so that
+// a truncated image is not mistaken for a complete one.
+type imageLimitReader struct {
+	r      io.Reader
+	n, max int64
+	urlStr string
+}
+
+func (l *imageLimitReader) Read(p []byte) (n int, err error) {
+	if l.n <= 0 {
+		// Only fail if there is in fact more data
+		var b [1]byte
+		n, err = l.r.Read(b[:])
+		if n > 0 {
+			return 0, imageSizeError(l.urlStr, l.max)
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err = l.r.Read(p)
+	l.n -= int64(n)
+	return
+}
@@ -0,0 +1,300 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// JPEG marker codes of interest when scanning a stream
+const (
+	jpegMarkerSOI   = 0xD8
+	jpegMarkerEOI   = 0xD9
+	jpegMarkerSOS   = 0xDA
+	jpegMarkerDHT   = 0xC4
+	jpegMarkerJPG   = 0xC8
+	jpegMarkerDAC   = 0xCC
+	jpegMarkerAPP1  = 0xE1
+	jpegMarkerAPP14 = 0xEE
+)
+
+// jpegHeaderType holds the information gathered from the marker segments
+// that precede the entropy-coded image data of a JPEG stream.
+type jpegHeaderType struct {
+	width, height  int
+	components     int
+	precision      int
+	sof            byte // start of frame marker, identifying the coding process
+	progressive    bool
+	adobe          bool   // APP14 "Adobe" segment present
+	adobeTransform byte   // color transform flag of the APP14 segment
+	exif           []byte // payload of the APP1 "Exif" segment, if any
+}
+
+// jpegScanner walks the marker segments of a JPEG stream.
+type jpegScanner struct {
+	r   io.Reader
+	buf [8]byte
+}
+
+func (s *jpegScanner) readFull(b []byte) (err error) {
+	_, err = io.ReadFull(s.r, b)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return
+}
+
+func (s *jpegScanner) readByte() (c byte, err error) {
+	err = s.readFull(s.buf[:1])
+	c = s.buf[0]
+	return
+}
+
+// nextMarker skips to the next marker in the stream, discarding any fill
+// bytes, and returns its code.
+func (s *jpegScanner) nextMarker() (m byte, err error) {
+	m, err = s.readByte()
+	if err != nil {
+		return
+	}
+	if m != 0xFF {
+		return 0, fmt.Errorf("expecting JPEG marker, got 0x%02X", m)
+	}
+	for m == 0xFF && err == nil {
+		m, err = s.readByte()
+	}
+	return
+}
+
+// parsejpgstream reads the marker segments of the JPEG stream r up to the
+// start of the scan data. Only the segments needed to describe the image are
+// retained; everything else is skipped as it is read. All bytes consumed,
+// including the remaining scan data, are written to dst.
+func parsejpgstream(r io.Reader, dst io.Writer) (hdr jpegHeaderType, err error) {
+	s := jpegScanner{r: io.TeeReader(r, dst)}
+	var m byte
+	m, err = s.nextMarker()
+	if err != nil {
+		return
+	}
+	if m != jpegMarkerSOI {
+		err = fmt.Errorf("missing JPEG start of image marker")
+		return
+	}
+	haveFrame := false
+	for {
+		m, err = s.nextMarker()
+		if err != nil {
+			return
+		}
+		switch {
+		case m == jpegMarkerEOI:
+			err = fmt.Errorf("JPEG stream ends before image data")
+			return
+		case m == 0x01 || (m >= 0xD0 && m <= 0xD7):
+			// Standalone markers without a length field
+			continue
+		}
+		if err = s.readFull(s.buf[:2]); err != nil {
+			return
+		}
+		n := int(binary.BigEndian.Uint16(s.buf[:2])) - 2
+		if n < 0 {
+			err = fmt.Errorf("invalid JPEG segment length")
+			return
+		}
+		switch {
+		case m == jpegMarkerSOS:
+			if !haveFrame {
+				err = fmt.Errorf("JPEG scan data precedes frame header")
+				return
+			}
+			// Pass the rest of the stream through untouched
+			_, err = io.Copy(ioutil.Discard, s.r)
+			return
+		case m >= 0xC0 && m <= 0xCF && m != jpegMarkerDHT && m != jpegMarkerJPG && m != jpegMarkerDAC:
+			if n < 6 {
+				err = fmt.Errorf("invalid JPEG frame header length")
+				return
+			}
+			if err = s.readFull(s.buf[:6]); err != nil {
+				return
+			}
+			hdr.precision = int(s.buf[0])
+			hdr.height = int(binary.BigEndian.Uint16(s.buf[1:3]))
+			hdr.width = int(binary.BigEndian.Uint16(s.buf[3:5]))
+			hdr.components = int(s.buf[5])
+			hdr.sof = m
+			hdr.progressive = m == 0xC2 || m == 0xC6 || m == 0xCA || m == 0xCE
+			haveFrame = true
+			n -= 6
+		case m == jpegMarkerAPP1 && n >= 6:
+			payload := make([]byte, n)
+			if err = s.readFull(payload); err != nil {
+				return
+			}
+			if bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+				hdr.exif = payload[6:]
+			}
+			n = 0
+		case m == jpegMarkerAPP14 && n >= 12:
+			payload := make([]byte, 12)
+			if err = s.readFull(payload); err != nil {
+				return
+			}
+			if bytes.HasPrefix(payload, []byte("Adobe")) {
+				hdr.adobe = true
+				hdr.adobeTransform = payload[11]
+			}
+			n -= 12
+		}
+		if n > 0 {
+			if _, err = io.CopyN(ioutil.Discard, s.r, int64(n)); err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return
+			}
+		}
+	}
+}
+
+// jpegFileType is a JPEG file whose data is passed through to the document
+// rather than held in memory.
+type jpegFileType struct {
+	file *os.File
+	name string    // absolute path of the file
+	off  int64     // offset of the JPEG stream in the file
+	sum  hash.Hash // checksum of the data, written as it is read
+}
+
+// newJpegFile returns the JPEG file read by r, or nil if r is not a regular
+// file that can be opened again by name when the document is written.
+func newJpegFile(r io.Reader) *jpegFileType {
+	file, ok := r.(*os.File)
+	if !ok {
+		return nil
+	}
+	st, err := file.Stat()
+	if err != nil || !st.Mode().IsRegular() {
+		return nil
+	}
+	off, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil
+	}
+	name, err := filepath.Abs(file.Name())
+	if err != nil {
+		return nil
+	}
+	return &jpegFileType{file: file, name: name, off: off, sum: sha1.New()}
+}
+
+// source sets the source of the data of info to the file, which has been
+// read to its end.
+func (jf *jpegFileType) source(info *ImageInfoType) error {
+	end, err := jf.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	name, off, n := jf.name, jf.off, end-jf.off
+	info.src = func() (io.Reader, error) {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		return sectionFile{io.NewSectionReader(file, off, n), file}, nil
+	}
+	info.srcLen = n
+	info.srcSum = jf.sum.Sum(nil)
+	return nil
+}
+
+// sectionFile reads a section of a file and closes the file.
+type sectionFile struct {
+	*io.SectionReader
+	file *os.File
+}
+
+func (s sectionFile) Close() error {
+	return s.file.Close()
+}
+
+// sourceData reads the data of an image passed through from a file.
+func (info *ImageInfoType) sourceData() (data []byte, err error) {
+	r, err := info.src()
+	if err != nil {
+		return
+	}
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+	data = make([]byte, info.srcLen)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return
+	}
+	if sum := sha1.Sum(data); !bytes.Equal(sum[:], info.srcSum) {
+		err = fmt.Errorf("image data has changed since the image was registered")
+	}
+	return
+}
+
+// exifOrientation returns the value of the orientation tag in the specified
+// EXIF payload, or 0 if it is absent or invalid.
+func exifOrientation(exif []byte) int {
+	var order binary.ByteOrder
+	if len(exif) < 8 {
+		return 0
+	}
+	switch string(exif[0:4]) {
+	case "II\x2A\x00":
+		order = binary.LittleEndian
+	case "MM\x00\x2A":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	off := int(order.Uint32(exif[4:]))
+	if off < 8 || off+2 > len(exif) {
+		return 0
+	}
+	n := int(order.Uint16(exif[off:]))
+	for j := 0; j < n; j++ {
+		pos := off + 2 + 12*j
+		if pos+12 > len(exif) {
+			break
+		}
+		// Orientation tag, type SHORT
+		if order.Uint16(exif[pos:]) == 0x0112 && order.Uint16(exif[pos+2:]) == 3 {
+			v := int(order.Uint16(exif[pos+8:]))
+			if v >= 1 && v <= 8 {
+				return v
+			}
+			return 0
+		}
+	}
+	return 0
+}
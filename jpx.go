@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Leading bytes of a JP2 file (signature box) and of a raw JPEG 2000
+// codestream (SOC and SIZ markers)
+var (
+	jp2Signature = []byte("\x00\x00\x00\x0CjP  \x0D\x0A\x87\x0A")
+	j2kSignature = []byte("\xFF\x4F\xFF\x51")
+)
+
+// parsejpx extracts info from io.Reader with a JPEG 2000 image, either a JP2
+// file or a raw codestream. The image data is embedded as is with the
+// JPXDecode filter.
+func (f *Fpdf) parsejpx(r io.Reader) (info *ImageInfoType) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		f.err = err
+		return
+	}
+	info = f.newImageInfo()
+	var nc int
+	var colr bool
+	switch {
+	case bytes.HasPrefix(data, jp2Signature):
+		nc, colr, err = jp2Header(info, data)
+	case bytes.HasPrefix(data, j2kSignature):
+		nc, err = j2kHeader(info, data)
+	default:
+		err = fmt.Errorf("not a JPEG 2000 image")
+	}
+	if err != nil {
+		f.err = err
+		return
+	}
+	// If the image specifies its color space, the ColorSpace entry is
+	// omitted so that the JPXDecode filter supplies it
+	if !colr {
+		switch nc {
+		case 1:
+			info.cs = "DeviceGray"
+		case 3:
+			info.cs = "DeviceRGB"
+		case 4:
+			info.cs = "DeviceCMYK"
+		default:
+			f.err = fmt.Errorf("unsupported number of JPEG 2000 components: %d", nc)
+			return
+		}
+	}
+	info.f = "JPXDecode"
+	info.data = data
+	if f.pdfVersion < "1.5" {
+		f.pdfVersion = "1.5"
+	}
+	return
+}
+
+// j2kHeader reads the image dimensions from the SIZ marker segment of a raw
+// codestream and returns the number of components.
+func j2kHeader(info *ImageInfoType, data []byte) (nc int, err error) {
+	// Marker, Lsiz, Rsiz, four pairs of 32-bit extents and offsets, Csiz
+	if len(data) < 4+4+32+2 {
+		return 0, fmt.Errorf("truncated JPEG 2000 codestream")
+	}
+	siz := data[4:]
+	xsiz := binary.BigEndian.Uint32(siz[4:])
+	ysiz := binary.BigEndian.Uint32(siz[8:])
+	xosiz := binary.BigEndian.Uint32(siz[12:])
+	yosiz := binary.BigEndian.Uint32(siz[16:])
+	if xsiz <= xosiz || ysiz <= yosiz {
+		return 0, fmt.Errorf("invalid JPEG 2000 image size")
+	}
+	info.w = float64(xsiz - xosiz)
+	info.h = float64(ysiz - yosiz)
+	nc = int(binary.BigEndian.Uint16(siz[36:]))
+	return
+}
+
+// jp2Header reads the image header box of a JP2 file and returns the number
+// of components and whether the file has a color specification box.
+func jp2Header(info *ImageInfoType, data []byte) (nc int, colr bool, err error) {
+	var haveHdr bool
+	for len(data) >= 8 {
+		n, typ, payload, rest, err := jp2Box(data)
+		if err != nil {
+			return 0, false, err
+		}
+		if typ == "jp2h" {
+			sub := payload
+			for len(sub) >= 8 {
+				_, subTyp, subPayload, subRest, err := jp2Box(sub)
+				if err != nil {
+					return 0, false, err
+				}
+				switch {
+				case subTyp == "ihdr" && len(subPayload) >= 10:
+					info.h = float64(binary.BigEndian.Uint32(subPayload[0:]))
+					info.w = float64(binary.BigEndian.Uint32(subPayload[4:]))
+					nc = int(binary.BigEndian.Uint16(subPayload[8:]))
+					haveHdr = true
+				case subTyp == "colr":
+					colr = true
+				}
+				sub = subRest
+			}
+		}
+		if typ == "jp2c" || n == 0 {
+			break
+		}
+		data = rest
+	}
+	if !haveHdr || info.w == 0 || info.h == 0 {
+		return 0, false, fmt.Errorf("missing JP2 image header")
+	}
+	return
+}
+
+// jp2Box splits the box at the start of data into its length, type and
+// payload and returns the data that follows it. A length of zero denotes a
+// box that extends to the end of the data.
+func jp2Box(data []byte) (n uint64, typ string, payload, rest []byte, err error) {
+	n = uint64(binary.BigEndian.Uint32(data))
+	typ = string(data[4:8])
+	hdr := uint64(8)
+	switch n {
+	case 0:
+		return 0, typ, data[hdr:], nil, nil
+	case 1:
+		// Extended length
+		if len(data) < 16 {
+			return 0, "", nil, nil, fmt.Errorf("truncated JP2 box")
+		}
+		n = binary.BigEndian.Uint64(data[8:])
+		hdr = 16
+	}
+	if n < hdr || n > uint64(len(data)) {
+		return 0, "", nil, nil, fmt.Errorf("invalid JP2 box length")
+	}
+	return n, typ, data[hdr:n], data[n:], nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"strings"
+)
+
+// SetLetterJustification enables or disables the justification of text by
+// spacing its characters as well as its words. When enabled, the space added
+// to each space of a justified line is limited to maxWordSpace, in the unit of
+// measure specified in New(), and the rest of the width left on the line is
+// distributed between its characters, which avoids the wide gaps between
+// words of short lines and lines of long words such as URLs. Lines without
+// spaces, such as lines of Chinese, Japanese or Korean text or of a word
+// broken between characters, are justified by spacing their characters only.
+// A value of 0 for maxWordSpace spaces characters only.
+//
+// Letter justification applies to the justified lines of MultiCell(),
+// ParagraphType and the methods that build on them. It is disabled by
+// default.
+func (f *Fpdf) SetLetterJustification(enabled bool, maxWordSpace float64) {
+	f.letterJustify = enabled
+	f.maxWordSpace = maxWordSpace
+}
+
+// GetLetterJustification returns whether letter justification is enabled and
+// the maximum space added to the spaces of justified lines. See
+// SetLetterJustification().
+func (f *Fpdf) GetLetterJustification() (enabled bool, maxWordSpace float64) {
+	return f.letterJustify, f.maxWordSpace
+}
+
+// justifySpacing returns the space to add to each space and to each
+// character of the line txtStr so that it fills the width w with letter
+// justification.
+func (f *Fpdf) justifySpacing(txtStr string, w float64) (ws, cs float64) {
+	slack := w - f.GetSpacedStringWidth(txtStr)
+	if slack <= 0 {
+		return
+	}
+	if spaces := strings.Count(txtStr, " "); spaces > 0 {
+		ws = math.Min(slack/float64(spaces), math.Max(f.maxWordSpace, 0))
+		slack -= ws * float64(spaces)
+	}
+	// The spacing of the last character is left beyond the end of the line
+	if n := len(f.textRunes(txtStr)); n > 1 {
+		cs = slack / float64(n-1)
+	}
+	return
+}
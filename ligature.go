@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"fmt"
+)
+
+// SetFontLigatures enables or disables the standard ligatures of the UTF-8
+// font with the specified family and style, which are disabled by default.
+// When enabled, text printed with the font has sequences of characters such
+// as "fi", "fl" and "ffi" replaced by their ligatures. The ligatures are
+// those of the "liga" feature of the glyph substitution (GSUB) table of the
+// font for the default and Latin scripts; a font without such a table is left
+// unchanged. Ligatures are only formed by consecutive characters, so a zero
+// width non-joiner (U+200C) between two characters prevents their ligature.
+//
+// Each ligature is assigned a code of the Private Use Area that the font
+// does not use. Its width is measured like that of a character, and text
+// extracted from the document maps it back to its characters. Text that
+// contains such a code is printed with the ligature.
+//
+// An error is set if no UTF-8 font has been added with familyStr and
+// styleStr.
+func (f *Fpdf) SetFontLigatures(familyStr, styleStr string, enabled bool) {
+	if f.err != nil {
+		return
+	}
+	font, ok := f.fonts[getFontKey(fontFamilyEscape(familyStr), styleStr)]
+	if !ok || font.Tp != "UTF8" {
+		f.SetErrorf("undefined UTF-8 font: %s %s", familyStr, styleStr)
+		return
+	}
+	features := font.features
+	if enabled && features.ligatureLookups == nil {
+		if err := features.loadLigatures(font); err != nil {
+			f.SetError(err)
+			return
+		}
+		// The widths of the font merged with its fallbacks lack the ligatures
+		f.fallbackWidths = nil
+	}
+	features.ligatures = enabled
+}
+
+// ligature is a ligature of a font and the characters it replaces.
+type ligature struct {
+	chars []rune
+	code  rune
+}
+
+// loadLigatures reads the ligatures of the font from its GSUB table and
+// assigns codes to them.
+func (ft *fontFeatures) loadLigatures(font fontDefType) error {
+	ft.ligatureLookups = make([]map[rune][]ligature, 0)
+	utf := font.utf8File
+	symbolChars := utf.generateCMAP()
+	if symbolChars == nil {
+		return fmt.Errorf("font does not have cmap for Unicode")
+	}
+	gsub := utf.getTableData("GSUB")
+	if gsub == nil {
+		return nil
+	}
+	lookups, err := gsubLigatures(gsub)
+	if err != nil {
+		return err
+	}
+	for _, lookup := range lookups {
+		set := make(map[rune][]ligature)
+		for _, l := range lookup {
+			chars := make([]rune, len(l.components))
+			for j, glyph := range l.components {
+				if chars[j] = glyphChar(symbolChars, glyph); chars[j] <= 0 {
+					chars = nil
+					break
+				}
+			}
+			if chars == nil {
+				continue
+			}
+			if code := font.glyphCode(l.glyph, string(chars)); code > 0 {
+				set[chars[0]] = append(set[chars[0]], ligature{chars: chars, code: code})
+			}
+		}
+		if len(set) > 0 {
+			ft.ligatureLookups = append(ft.ligatureLookups, set)
+		}
+	}
+	return nil
+}
+
+// substituteLigatures returns runes with the ligatures substituted for their
+// characters. The lookups are applied in turn.
+func (ft *fontFeatures) substituteLigatures(runes []rune) []rune {
+	for _, lookup := range ft.ligatureLookups {
+		out := make([]rune, 0, len(runes))
+		for j := 0; j < len(runes); {
+			code := rune(-1)
+			for _, l := range lookup[runes[j]] {
+				if len(l.chars) <= len(runes)-j && string(runes[j:j+len(l.chars)]) == string(l.chars) {
+					code = l.code
+					j += len(l.chars)
+					break
+				}
+			}
+			if code < 0 {
+				code = runes[j]
+				j++
+			}
+			out = append(out, code)
+		}
+		runes = out
+	}
+	return runes
+}
+
+// glyphChar returns the lowest character that the cmap table maps to the
+// glyph, given the characters of the glyphs, or -1 if there is none.
+func glyphChar(symbolChars map[int][]int, glyph int) rune {
+	chars := symbolChars[glyph]
+	if len(chars) == 0 {
+		return -1
+	}
+	c := chars[0]
+	for _, ch := range chars[1:] {
+		if ch < c {
+			c = ch
+		}
+	}
+	return rune(c)
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Line breaking classes of the Unicode line breaking algorithm (UAX #14)
+// that MultiCell() distinguishes. Characters of classes that are not
+// distinguished, such as those of scripts broken with dictionaries, are
+// handled as alphabetic.
+const (
+	lbAL = iota // Alphabetic
+	lbBA        // Break after
+	lbB2        // Break opportunity before and after
+	lbCL        // Close punctuation
+	lbCM        // Combining mark
+	lbCP        // Close parenthesis
+	lbEX        // Exclamation or interrogation
+	lbGL        // Non-breaking ("glue")
+	lbHY        // Hyphen
+	lbID        // Ideographic
+	lbIN        // Inseparable
+	lbIS        // Infix numeric separator
+	lbNS        // Nonstarter
+	lbNU        // Numeric
+	lbOP        // Open punctuation
+	lbPO        // Postfix numeric
+	lbPR        // Prefix numeric
+	lbQU        // Quotation
+	lbSP        // Space
+	lbSY        // Symbols allowing break after
+	lbWJ        // Word joiner
+	lbZW        // Zero width space
+)
+
+// lineBreakClasses are the classes of characters other than alphabetic
+// ones, ideographs and combining marks.
+var lineBreakClasses = map[rune]int{
+	'\t': lbBA, ' ': lbSP, '!': lbEX, '"': lbQU, '$': lbPR, '%': lbPO, '\'': lbQU,
+	'(': lbOP, ')': lbCP, '+': lbPR, ',': lbIS, '-': lbHY, '.': lbIS, '/': lbSY,
+	':': lbIS, ';': lbIS, '?': lbEX, '[': lbOP, '\\': lbPR, ']': lbCP, '{': lbOP,
+	'|': lbBA, '}': lbCL,
+	0x00A0: lbGL, 0x00A1: lbOP, 0x00A2: lbPO, 0x00A3: lbPR, 0x00A5: lbPR, 0x00AB: lbQU,
+	0x00AD: lbBA, 0x00B0: lbPO, 0x00BB: lbQU, 0x00BF: lbOP,
+	0x200B: lbZW, 0x2007: lbGL, 0x2010: lbBA, 0x2011: lbGL, 0x2012: lbBA, 0x2013: lbBA,
+	0x2014: lbB2, 0x2018: lbQU, 0x2019: lbQU, 0x201A: lbOP, 0x201B: lbQU, 0x201C: lbQU,
+	0x201D: lbQU, 0x201E: lbOP, 0x201F: lbQU, 0x2024: lbIN, 0x2025: lbIN, 0x2026: lbIN,
+	0x2030: lbPO, 0x2039: lbQU, 0x203A: lbQU, 0x203C: lbNS, 0x202F: lbGL, 0x2060: lbWJ,
+	0x20AC: lbPR, 0xFEFF: lbWJ,
+	// Punctuation of Chinese, Japanese and Korean text
+	0x3001: lbCL, 0x3002: lbCL, 0x3005: lbNS, 0x3008: lbOP, 0x3009: lbCL, 0x300A: lbOP,
+	0x300B: lbCL, 0x300C: lbOP, 0x300D: lbCL, 0x300E: lbOP, 0x300F: lbCL, 0x3010: lbOP,
+	0x3011: lbCL, 0x3014: lbOP, 0x3015: lbCL, 0x3016: lbOP, 0x3017: lbCL, 0x3018: lbOP,
+	0x3019: lbCL, 0x301A: lbOP, 0x301B: lbCL, 0x301C: lbNS, 0x301D: lbOP, 0x301E: lbCL,
+	0x301F: lbCL, 0x303B: lbNS, 0x30A0: lbNS, 0x30FB: lbNS, 0x30FC: lbNS, 0x309D: lbNS,
+	0x309E: lbNS, 0x30FD: lbNS, 0x30FE: lbNS,
+	0xFF01: lbEX, 0xFF08: lbOP, 0xFF09: lbCP, 0xFF0C: lbCL, 0xFF0E: lbCL, 0xFF1A: lbNS,
+	0xFF1B: lbNS, 0xFF1F: lbEX, 0xFF3B: lbOP, 0xFF3D: lbCP, 0xFF5B: lbOP, 0xFF5D: lbCL,
+	0xFF5F: lbOP, 0xFF60: lbCL, 0xFF61: lbCL, 0xFF62: lbOP, 0xFF63: lbCL, 0xFF64: lbCL,
+}
+
+// lineBreakSmallKana are the small kana, which do not begin lines.
+const lineBreakSmallKana = "ぁぃぅぇぉっゃゅょゎゕゖァィゥェォッャュョヮヵヶㇰㇱㇲㇳㇴㇵㇶㇷㇸㇹㇺㇻㇼㇽㇾㇿ"
+
+// lineBreakCp1252 are the characters of the bytes 0x80 to 0x9F of the cp1252
+// encoding of the core fonts.
+var lineBreakCp1252 = []rune{
+	0x20AC, 0, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021, 0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017D, 0,
+	0, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014, 0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0, 0x017E, 0x0178,
+}
+
+// lineBreakClass returns the line breaking class of r.
+func lineBreakClass(r rune) int {
+	if class, ok := lineBreakClasses[r]; ok {
+		return class
+	}
+	switch {
+	case r >= '0' && r <= '9':
+		return lbNU
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return lbCM
+	case r >= 0x3040 && r <= 0x30FF && strings.ContainsRune(lineBreakSmallKana, r) ||
+		r >= 0x31F0 && r <= 0x31FF:
+		return lbNS
+	case r >= 0x1100 && r <= 0x11FF, r >= 0xA960 && r <= 0xA97F, r >= 0xD7B0 && r <= 0xD7FF,
+		r >= 0x2E80 && r <= 0x2FFF, r >= 0x3000 && r <= 0x33FF, r >= 0x3400 && r <= 0x4DBF,
+		r >= 0x4E00 && r <= 0x9FFF, r >= 0xA000 && r <= 0xA4CF, r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF, r >= 0xFE30 && r <= 0xFE4F, r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6, r >= 0x1F000 && r <= 0x1FAFF, r >= 0x20000 && r <= 0x3FFFD:
+		return lbID
+	case unicode.IsDigit(r):
+		return lbNU
+	}
+	return lbAL
+}
+
+// lineBreaker finds the break opportunities of a line of text, character by
+// character, with the rules of the Unicode line breaking algorithm for the
+// classes of lineBreakClass(). Its zero value is at the beginning of a line.
+type lineBreaker struct {
+	started bool // A character other than a space has been read
+	before  int  // Class of the last character other than a space
+	spaced  bool // Spaces follow the last character other than a space
+}
+
+// next reads the character r, encoded in cp1252 if single is true, and
+// reports whether the line can be broken before it.
+func (lb *lineBreaker) next(r rune, single bool) (ok bool) {
+	if single && r >= 0x80 && r < 0xA0 {
+		r = lineBreakCp1252[r-0x80]
+	}
+	class := lineBreakClass(r)
+	if !lb.started {
+		if class != lbSP {
+			lb.started, lb.before = true, class
+			if class == lbCM {
+				lb.before = lbAL
+			}
+		}
+		return false
+	}
+	if class == lbCM && !lb.spaced {
+		// Combining marks take the class of the character they follow
+		return false
+	}
+	if class == lbCM {
+		class = lbAL
+	}
+	ok = lineBreakPair(lb.before, class, lb.spaced)
+	if class == lbSP {
+		lb.spaced = true
+	} else {
+		lb.before, lb.spaced = class, false
+	}
+	return
+}
+
+// lineBreakPair reports whether a line can be broken before a character of
+// the class after that follows a character of the class before, with spaces
+// between them if spaced is true.
+func lineBreakPair(before, after int, spaced bool) bool {
+	switch {
+	case after == lbSP || after == lbZW:
+		return false
+	case before == lbZW:
+		return true
+	case after == lbWJ || before == lbWJ && !spaced, before == lbGL && !spaced:
+		return false
+	case after == lbGL:
+		return spaced || before == lbBA || before == lbHY
+	case after == lbCL || after == lbCP || after == lbEX || after == lbIS || after == lbSY:
+		return false
+	case before == lbOP:
+		return false
+	case before == lbQU && after == lbOP,
+		(before == lbCL || before == lbCP) && after == lbNS,
+		before == lbB2 && after == lbB2:
+		return false
+	case spaced:
+		return true
+	case after == lbQU || before == lbQU:
+		return false
+	case after == lbBA || after == lbHY || after == lbNS || after == lbIN:
+		return false
+	}
+	switch before {
+	case lbAL, lbNU:
+		return after != lbAL && after != lbNU && after != lbPR && after != lbPO && after != lbOP
+	case lbPR, lbPO:
+		return after != lbAL && after != lbNU
+	case lbHY, lbIS, lbSY:
+		return after != lbNU && !(before == lbIS && after == lbAL)
+	case lbCP:
+		return after != lbAL && after != lbNU
+	}
+	return true
+}
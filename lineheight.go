@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// SetLineHeight sets the height of the lines of text, in the unit of measure
+// specified in New(), that is used by Write(), MultiCell(), WriteAligned(),
+// WriteSpans(), MultiCellSpans(), the paragraphs of ParagraphNew() and the
+// Write() method of HTMLBasicNew() when their line height argument is 0. This
+// keeps the spacing of lines consistent across a document. The line height
+// replaces any factor set with SetLineHeightFactor(). A value of 0, the
+// default, leaves a line height of 0 as it is.
+func (f *Fpdf) SetLineHeight(ht float64) {
+	f.lineHt = ht
+	f.lineHtFactor = 0
+}
+
+// SetLineHeightFactor sets the height of the lines of text as a multiple of
+// the size of the current font at the time the text is printed, such as 1.2
+// for the leading commonly used in typesetting. It replaces any line height
+// set with SetLineHeight(). See SetLineHeight() for the methods concerned.
+func (f *Fpdf) SetLineHeightFactor(factor float64) {
+	f.lineHtFactor = factor
+	f.lineHt = 0
+}
+
+// GetLineHeight returns the height of the lines of text with the current
+// font, in the unit of measure specified in New(), as set with
+// SetLineHeight() or SetLineHeightFactor(). It is 0 if neither is set.
+func (f *Fpdf) GetLineHeight() float64 {
+	if f.lineHtFactor != 0 {
+		return f.lineHtFactor * f.fontSize
+	}
+	return f.lineHt
+}
+
+// textLineHt returns the height of lines of text whose line height argument
+// is h, which is the line height of the document if h is 0.
+func (f *Fpdf) textLineHt(h float64) float64 {
+	if h == 0 {
+		return f.GetLineHeight()
+	}
+	return h
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"strings"
+)
+
+// ListType lays out bulleted and numbered lists, whose items have hanging
+// indents: the lines of an item are indented and its marker, a bullet or a
+// number, is set in the indentation of its first line. Lists can be nested to
+// any depth. The marker of an item is kept on the page of its first lines,
+// which page breaks within the item leave at the indentation of the item. See
+// ListNew() to create a receiver that is associated with the PDF document
+// instance.
+type ListType struct {
+	pdf      *Fpdf
+	indent   float64
+	lineHt   float64
+	alignStr string
+	levels   []listLevel
+}
+
+// listLevel is a level of a list that has begun.
+type listLevel struct {
+	markerStr string
+	num       int // Number of the next item
+}
+
+// listBullets are the default bullets of the levels of lists, in UTF-8 and in
+// the cp1252 encoding of the core fonts.
+var (
+	listBullets       = []string{"•", "–", "·"}
+	listBulletsCp1252 = []string{"\x95", "\x96", "\xb7"}
+)
+
+// ListNew returns an instance that lays out lists whose levels are indented
+// by indent, in the unit of measure specified in New(), from the left margin
+// or from the indentation of the enclosing level. If indent is 0, a width of
+// 24 points is used. The items are printed in lines of height lineHt, as
+// MultiCell() does, with the line height set with SetLineHeight() or
+// SetLineHeightFactor() if lineHt is 0, and aligned as alignStr specifies.
+// See MultiCell() for details about alignStr.
+func (f *Fpdf) ListNew(indent, lineHt float64, alignStr string) (list ListType) {
+	if indent == 0 {
+		indent = 24 / f.k
+	}
+	list.pdf = f
+	list.indent = indent
+	list.lineHt = lineHt
+	list.alignStr = alignStr
+	return
+}
+
+// Begin begins a level of the list, nested in the item printed last if a
+// level has already begun, whose items are marked with markerStr. If
+// markerStr contains one of the characters 1, a, A, i or I, the first of them
+// is replaced with the number of each item, counted from 1, in decimal, in
+// lower or upper case letters or in lower or upper case roman numerals, as in
+// "1.", "a)" or "(i)". Otherwise markerStr is the bullet of each item. If
+// markerStr is empty, the bullets of the levels nested in each other are in
+// turn a bullet, an en dash and a middle dot, in UTF-8 for UTF-8 fonts and in
+// the cp1252 encoding of the core fonts for other fonts.
+func (list *ListType) Begin(markerStr string) {
+	list.levels = append(list.levels, listLevel{markerStr: markerStr, num: 1})
+}
+
+// End ends the level of the list that has begun last.
+func (list *ListType) End() {
+	if len(list.levels) == 0 {
+		list.pdf.SetErrorf("list level ended without beginning")
+		return
+	}
+	list.levels = list.levels[:len(list.levels)-1]
+}
+
+// SetNumber sets the number of the next item of the current level.
+func (list *ListType) SetNumber(n int) {
+	if len(list.levels) > 0 {
+		list.levels[len(list.levels)-1].num = n
+	}
+}
+
+// Item prints an item of the current level with the current font from the
+// current vertical position, and leaves the current position at the left
+// margin below the item. A level marked with bullets begins if none has.
+func (list *ListType) Item(txtStr string) {
+	f := list.pdf
+	if f.err != nil {
+		return
+	}
+	x, w := list.start()
+	h := f.textLineHt(list.lineHt)
+	// The lines of the item that are not left at the bottom of a page, as
+	// MultiCell() does for orphans, carry its marker to the next page
+	lines := 1
+	if f.orphans > 1 {
+		if f.isCurrentUTF8 {
+			lines = len(f.SplitText(txtStr, w))
+		} else {
+			lines = len(f.SplitLines([]byte(txtStr), w))
+		}
+		lines = minInt(lines, f.orphans)
+	}
+	list.marker(x, h, math.Max(float64(lines), 1)*h)
+	f.x = x
+	f.MultiCell(w, h, txtStr, "", list.alignStr, false)
+}
+
+// ItemSpans prints an item of the current level whose text is that of spans,
+// as MultiCellSpans() does. See Item() for details.
+func (list *ListType) ItemSpans(spans []TextSpan) {
+	f := list.pdf
+	if f.err != nil {
+		return
+	}
+	x, w := list.start()
+	h := f.textLineHt(list.lineHt)
+	need := h
+	if lines := f.spanLines(spans, func(int) float64 { return w - 2*f.cMargin }); len(lines) > 0 {
+		need, _ = f.spanLineHeight(spans, lines[0], h)
+	}
+	list.marker(x, h, need)
+	f.x = x
+	f.MultiCellSpans(w, h, spans, "", list.alignStr, false)
+}
+
+// start returns the position and the width of the text of the next item,
+// beginning a level if none has.
+func (list *ListType) start() (x, w float64) {
+	f := list.pdf
+	if len(list.levels) == 0 {
+		list.Begin("")
+	}
+	x = f.lMargin + float64(len(list.levels))*list.indent
+	return x, f.w - f.rMargin - x
+}
+
+// marker prints the marker of the next item at the right of the indentation
+// that ends at x, on the current page if the first lines of the item, of
+// height need, fit on it and on the next page otherwise.
+func (list *ListType) marker(x, h, need float64) {
+	f := list.pdf
+	level := &list.levels[len(list.levels)-1]
+	markerStr := level.markerStr
+	if j := strings.IndexAny(markerStr, "1aAiI"); j >= 0 {
+		markerStr = markerStr[:j] + htmlListNumber(level.num, markerStr[j:j+1]) + markerStr[j+1:]
+	} else if markerStr == "" {
+		bullets := listBullets
+		if !f.isCurrentUTF8 {
+			bullets = listBulletsCp1252
+		}
+		markerStr = bullets[(len(list.levels)-1)%len(bullets)]
+	}
+	level.num++
+	f.x = x
+	f.y = f.imageFlow(need)
+	if f.err != nil {
+		return
+	}
+	f.x = x - list.indent
+	f.CellFormat(list.indent, h, markerStr, "", 0, "R", false, 0, "")
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SetMarkup enables or disables the interpretation of inline markup in the
+// text of Cell(), CellFormat(), MultiCell(), Write() and the methods that
+// build on them, for quick styling of parts of the text without building the
+// spans of WriteSpans() or MultiCellSpans(). Markup is disabled by default.
+//
+// The markup consists of tags in angle brackets, each of which applies to the
+// text up to the corresponding closing tag, or to the end of the text:
+//
+//	<b>bold</b>
+//	<i>italic</i>
+//	<u>underlined</u>
+//	<s>struck out</s>
+//	<color #f00>colored</color>
+//	<bg yellow>highlighted</bg>
+//	<size 14>of a font size in points</size>
+//	<font times>of a font family</font>
+//	<sup>superscript</sup>
+//	<sub>subscript</sub>
+//	<a https://example.com>linked</a>
+//
+// Colors are given as names or in the #rgb, #rrggbb or rgb(r, g, b) forms of
+// CSS. Tags can be nested. Text that looks like a tag but is not one is
+// printed as is; the entities &lt;, &gt; and &amp; stand for the characters
+// <, > and &. An error is set if a closing tag has no opening tag or if the
+// argument of a tag is invalid.
+//
+// Text with markup is laid out as the spans of WriteSpans() are, in a single
+// line for a cell, whose vertical alignment is ignored. Unmarked text is
+// printed as usual.
+func (f *Fpdf) SetMarkup(enabled bool) {
+	f.markup = enabled
+}
+
+// GetMarkup returns whether inline markup is enabled. See SetMarkup().
+func (f *Fpdf) GetMarkup() bool {
+	return f.markup
+}
+
+// markupTags are the names of the tags of markup and whether they take an
+// argument.
+var markupTags = map[string]bool{
+	"b": false, "i": false, "u": false, "s": false, "sup": false, "sub": false,
+	"color": true, "bg": true, "size": true, "font": true, "a": true,
+}
+
+// markupEntities replaces the entities of markup text with their characters.
+var markupEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// markupTag is a tag of markup text that is open.
+type markupTag struct {
+	name, arg string
+}
+
+// markupText reports whether txtStr is to be printed as markup.
+func (f *Fpdf) markupText(txtStr string) bool {
+	return f.markup && strings.ContainsAny(txtStr, "<&")
+}
+
+// markupSpans returns the spans of the markup text txtStr. Text that is not
+// linked by markup is linked to link or linkStr.
+func (f *Fpdf) markupSpans(txtStr string, link int, linkStr string) (spans []TextSpan) {
+	var open []markupTag
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			spans = append(spans, f.markupSpan(markupEntities.Replace(text.String()), open, link, linkStr))
+			text.Reset()
+		}
+	}
+	for len(txtStr) > 0 {
+		end := strings.IndexByte(txtStr, '>')
+		if txtStr[0] != '<' || end < 0 {
+			j := strings.IndexByte(txtStr[1:], '<') + 1
+			if j == 0 {
+				j = len(txtStr)
+			}
+			text.WriteString(txtStr[:j])
+			txtStr = txtStr[j:]
+			continue
+		}
+		tag := txtStr[1:end]
+		closing := strings.HasPrefix(tag, "/")
+		tag = strings.TrimPrefix(tag, "/")
+		name, arg := tag, ""
+		if j := strings.IndexByte(tag, ' '); j >= 0 {
+			name, arg = tag[:j], strings.TrimSpace(tag[j+1:])
+		}
+		name = strings.ToLower(name)
+		if hasArg, ok := markupTags[name]; !ok || hasArg != (arg != "") && !closing ||
+			closing && arg != "" {
+			// Not a tag
+			text.WriteByte('<')
+			txtStr = txtStr[1:]
+			continue
+		}
+		flush()
+		txtStr = txtStr[end+1:]
+		if !closing {
+			if !f.markupArg(name, arg) {
+				return nil
+			}
+			open = append(open, markupTag{name, arg})
+			continue
+		}
+		j := len(open) - 1
+		for j >= 0 && open[j].name != name {
+			j--
+		}
+		if j < 0 {
+			f.SetErrorf("markup closing tag </%s> has no opening tag", name)
+			return nil
+		}
+		open = append(open[:j], open[j+1:]...)
+	}
+	flush()
+	return
+}
+
+// markupArg reports whether arg is a valid argument of the tag name, and sets
+// an error if it is not.
+func (f *Fpdf) markupArg(name, arg string) bool {
+	switch name {
+	case "color", "bg":
+		if htmlColor(arg) == nil {
+			f.SetErrorf("invalid markup color: %s", arg)
+			return false
+		}
+	case "size":
+		if size, err := strconv.ParseFloat(arg, 64); err != nil || size <= 0 || math.IsInf(size, 0) {
+			f.SetErrorf("invalid markup font size: %s", arg)
+			return false
+		}
+	}
+	return true
+}
+
+// markupSpan returns the span of text styled by the open tags.
+func (f *Fpdf) markupSpan(text string, open []markupTag, link int, linkStr string) TextSpan {
+	span := TextSpan{Text: text, Link: link, LinkStr: linkStr}
+	style := f.fontStyle
+	if f.underline {
+		style += "U"
+	}
+	if f.strikeout {
+		style += "S"
+	}
+	for _, tag := range open {
+		switch tag.name {
+		case "b", "i", "u", "s":
+			if s := strings.ToUpper(tag.name); !strings.Contains(style, s) {
+				style += s
+			}
+		case "sup":
+			span.Superscript, span.Subscript = true, false
+		case "sub":
+			span.Superscript, span.Subscript = false, true
+		case "color":
+			span.TextColor = htmlColor(tag.arg)
+		case "bg":
+			span.FillColor = htmlColor(tag.arg)
+		case "size":
+			span.FontSize, _ = strconv.ParseFloat(tag.arg, 64)
+		case "font":
+			span.FontFamily = tag.arg
+		case "a":
+			span.Link, span.LinkStr = 0, tag.arg
+		}
+	}
+	span.FontStyle = style
+	return span
+}
+
+// markupCellFormat prints a cell of markup text as CellFormat() does.
+func (f *Fpdf) markupCellFormat(w, h float64, txtStr, borderStr string, ln int,
+	alignStr string, fill bool, link int, linkStr string) {
+	spans := f.markupSpans(strings.Replace(txtStr, "\n", " ", -1), link, linkStr)
+	if f.err != nil {
+		return
+	}
+	// The text of the spans is printed without markup
+	f.markup = false
+	defer func() {
+		f.markup = true
+	}()
+	if w == 0 {
+		w = f.w - f.rMargin - f.x
+	}
+	x := f.x
+	lines := f.spanLines(spans, func(int) float64 {
+		return math.Inf(1)
+	})
+	lineHt := h
+	if len(lines) > 0 {
+		lineHt = f.spanLinePrint(spans, lines[0], w, h, borderStr, alignStr, fill)
+	}
+	if ln > 0 {
+		f.y += lineHt
+		f.x = x
+		if ln == 1 {
+			f.x = f.lMargin
+		}
+	}
+}
+
+// markupMultiCell prints markup text as MultiCell() does.
+func (f *Fpdf) markupMultiCell(w, h float64, txtStr, borderStr, alignStr string, fill bool) {
+	spans := f.markupSpans(txtStr, 0, "")
+	if f.err != nil {
+		return
+	}
+	f.markup = false
+	defer func() {
+		f.markup = true
+	}()
+	f.MultiCellSpans(w, h, spans, borderStr, alignStr, fill)
+}
+
+// markupWrite prints markup text as Write() does.
+func (f *Fpdf) markupWrite(h float64, txtStr string, link int, linkStr string) {
+	spans := f.markupSpans(txtStr, link, linkStr)
+	if f.err != nil {
+		return
+	}
+	f.markup = false
+	defer func() {
+		f.markup = true
+	}()
+	f.WriteSpans(h, spans)
+}
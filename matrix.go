@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+)
+
+// MatrixType is an affine transformation in the coordinates of the page, in
+// the unit of measure specified in New(), with the origin at the upper left
+// corner of the page and the y axis pointing down. It maps the point (x, y)
+// to the point (A*x + C*y + E, B*x + D*y + F).
+//
+// Transformations are composed with the methods of MatrixType, each of which
+// returns the transformation that applies the receiver followed by another
+// transformation, so that a chain of calls reads in the order in which the
+// transformations are applied. TransformMatrix() applies a transformation to
+// the following text, drawings and images.
+type MatrixType struct {
+	A, B, C, D, E, F float64
+}
+
+// IdentityMatrix returns the transformation that leaves every point in place,
+// from which other transformations are composed.
+func IdentityMatrix() MatrixType {
+	return MatrixType{A: 1, D: 1}
+}
+
+// Multiply returns the transformation that applies m followed by n.
+func (m MatrixType) Multiply(n MatrixType) MatrixType {
+	return MatrixType{
+		A: m.A*n.A + m.B*n.C,
+		B: m.A*n.B + m.B*n.D,
+		C: m.C*n.A + m.D*n.C,
+		D: m.C*n.B + m.D*n.D,
+		E: m.E*n.A + m.F*n.C + n.E,
+		F: m.E*n.B + m.F*n.D + n.F,
+	}
+}
+
+// Translate returns the transformation that applies m followed by a move of
+// tx horizontally and ty vertically.
+func (m MatrixType) Translate(tx, ty float64) MatrixType {
+	return m.Multiply(MatrixType{A: 1, D: 1, E: tx, F: ty})
+}
+
+// Scale returns the transformation that applies m followed by a scaling by
+// the factors sx horizontally and sy vertically around the origin. A negative
+// factor mirrors the coordinate.
+func (m MatrixType) Scale(sx, sy float64) MatrixType {
+	return m.Multiply(MatrixType{A: sx, D: sy})
+}
+
+// Rotate returns the transformation that applies m followed by a rotation
+// around the origin by degAngle degrees, counter-clockwise as seen on the
+// page like TransformRotate().
+func (m MatrixType) Rotate(degAngle float64) MatrixType {
+	sin, cos := math.Sincos(degAngle * math.Pi / 180)
+	return m.Multiply(MatrixType{A: cos, B: -sin, C: sin, D: cos})
+}
+
+// Skew returns the transformation that applies m followed by a skew that
+// keeps the origin in place. As with TransformSkew(), degX ranges from -90
+// degrees (skew to the left) to 90 degrees (skew to the right), and degY from
+// -90 degrees (skew to the bottom) to 90 degrees (skew to the top).
+func (m MatrixType) Skew(degX, degY float64) MatrixType {
+	return m.Multiply(MatrixType{A: 1, B: -math.Tan(degY * math.Pi / 180),
+		C: -math.Tan(degX * math.Pi / 180), D: 1})
+}
+
+// Invert returns the transformation that undoes m. ok is false if m is not
+// invertible, as when it scales a coordinate by 0.
+func (m MatrixType) Invert() (inv MatrixType, ok bool) {
+	det := m.A*m.D - m.B*m.C
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return
+	}
+	inv = MatrixType{A: m.D / det, B: -m.B / det, C: -m.C / det, D: m.A / det}
+	inv.E = -(m.E*inv.A + m.F*inv.C)
+	inv.F = -(m.E*inv.B + m.F*inv.D)
+	return inv, true
+}
+
+// TransformPoint returns the point to which m maps the point (x, y).
+func (m MatrixType) TransformPoint(x, y float64) (float64, float64) {
+	return m.A*x + m.C*y + m.E, m.B*x + m.D*y + m.F
+}
+
+// TransformMatrix transforms the following text, drawings and images by m,
+// which is composed with the transformations already in effect. As with the
+// other transformation methods, it must be called in a transformation context
+// begun with TransformBegin().
+func (f *Fpdf) TransformMatrix(m MatrixType) {
+	if f.err != nil {
+		return
+	}
+	if !f.matrixValid(m) {
+		return
+	}
+	f.Transform(f.pdfMatrix(m))
+}
+
+// matrixValid reports whether the elements of m are finite numbers, and sets
+// the error of f if they are not.
+func (f *Fpdf) matrixValid(m MatrixType) bool {
+	for _, v := range []float64{m.A, m.B, m.C, m.D, m.E, m.F} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			f.SetErrorf("invalid transformation matrix %v", m)
+			return false
+		}
+	}
+	return true
+}
+
+// pdfMatrix returns the transformation m of the user space as a
+// transformation of the points of the page, in points with the y axis
+// pointing up, which is m between the conversions to and from the user
+// space.
+func (f *Fpdf) pdfMatrix(m MatrixType) TransformMatrix {
+	// Subtracting from 0 does not turn zeros into negative zeros
+	return TransformMatrix{A: m.A, B: 0 - m.B, C: 0 - m.C, D: m.D,
+		E: (m.C*f.h + m.E) * f.k, F: (f.h - m.D*f.h - m.F) * f.k}
+}
+
+// GetTransform returns the transformation currently applied to text,
+// drawings and images, which composes all the transformations in effect. It
+// maps a point of the following content to the point of the page where it is
+// painted, and is the identity where no transformation is in effect. Its
+// inverse maps a point of the page, such as the position of a mouse click on
+// a rendering, back to the coordinates of the content.
+func (f *Fpdf) GetTransform() MatrixType {
+	tm := f.ctm
+	return MatrixType{A: tm.A, B: -tm.B, C: -tm.C, D: tm.D,
+		E: tm.E/f.k + tm.C*f.h, F: f.h - tm.D*f.h - tm.F/f.k}
+}
+
+// ctmPush saves the current transformation as the graphics state is saved.
+func (f *Fpdf) ctmPush() {
+	f.ctmStack = append(f.ctmStack, f.ctm)
+}
+
+// ctmPop restores the transformation saved by the last call of ctmPush() as
+// the graphics state is restored.
+func (f *Fpdf) ctmPop() {
+	if n := len(f.ctmStack); n > 0 {
+		f.ctm = f.ctmStack[n-1]
+		f.ctmStack = f.ctmStack[:n-1]
+	}
+}
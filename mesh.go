@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+)
+
+// MeshVertexType is a vertex of a mesh drawn with TriangleMesh() or
+// LatticeMesh(): the point (X, Y), in the unit of measure specified in New(),
+// and the color of the mesh there.
+type MeshVertexType struct {
+	X, Y  float64
+	Color RGBType
+}
+
+// CoonsPatchType is a patch of a mesh drawn with CoonsPatchMesh(): an area
+// bounded by four cubic Bézier curves, with a color at each of its corners.
+// Points are the points of the boundary, in the unit of measure specified in
+// New(), starting at a corner: each corner is followed by the two control
+// points of the curve to the next corner. Colors are the colors of the
+// corners Points[0], Points[3], Points[6] and Points[9].
+type CoonsPatchType struct {
+	Points [12]PointType
+	Colors [4]RGBType
+}
+
+// meshRecord is a vertex of a triangle mesh or a lattice, or a patch of a
+// patch mesh, as encoded in the data of the mesh.
+type meshRecord struct {
+	pts  []PointType
+	clrs []RGBType
+}
+
+// TriangleMesh draws the triangles, each filled with a smooth blending of the
+// colors of its vertices (Gouraud shading). Triangles that share edges blend
+// into each other, so that a mesh of them paints colors that vary freely
+// across an area, such as the values of a measure at scattered points.
+func (f *Fpdf) TriangleMesh(triangles [][3]MeshVertexType) {
+	if f.err != nil {
+		return
+	}
+	if len(triangles) == 0 {
+		f.SetErrorf("triangle mesh has no triangles")
+		return
+	}
+	var list []meshRecord
+	for _, tri := range triangles {
+		for _, v := range tri {
+			list = append(list, meshRecord{[]PointType{{X: v.X, Y: v.Y}}, []RGBType{v.Color}})
+		}
+	}
+	f.mesh(4, list, true, "")
+}
+
+// LatticeMesh draws a mesh of the vertices of rows, which have the same number
+// of vertices. Consecutive vertices of consecutive rows are the corners of
+// quadrilaterals, each made of two triangles filled with a smooth blending of
+// the colors of their vertices, as in TriangleMesh(). A lattice of vertices
+// laid out on a grid paints a heatmap of the values of a measure sampled on
+// the grid.
+func (f *Fpdf) LatticeMesh(rows [][]MeshVertexType) {
+	if f.err != nil {
+		return
+	}
+	if len(rows) < 2 || len(rows[0]) < 2 {
+		f.SetErrorf("lattice mesh needs at least 2 rows of 2 vertices")
+		return
+	}
+	var list []meshRecord
+	for j, row := range rows {
+		if len(row) != len(rows[0]) {
+			f.SetErrorf("lattice mesh row %d has %d vertices instead of %d", j, len(row), len(rows[0]))
+			return
+		}
+		for _, v := range row {
+			list = append(list, meshRecord{[]PointType{{X: v.X, Y: v.Y}}, []RGBType{v.Color}})
+		}
+	}
+	f.mesh(5, list, false, sprintf("/VerticesPerRow %d", len(rows[0])))
+}
+
+// CoonsPatchMesh draws the patches, each filled with a smooth blending of the
+// colors of its corners that follows its curved boundary. Patches that share
+// boundaries blend into each other.
+func (f *Fpdf) CoonsPatchMesh(patches []CoonsPatchType) {
+	if f.err != nil {
+		return
+	}
+	if len(patches) == 0 {
+		f.SetErrorf("patch mesh has no patches")
+		return
+	}
+	var list []meshRecord
+	for _, patch := range patches {
+		list = append(list, meshRecord{patch.Points[:], patch.Colors[:]})
+	}
+	f.mesh(6, list, true, "")
+}
+
+// mesh draws a mesh shading of type tp whose data are the vertices or patches
+// of list, each preceded by a flag if flags is true. keyStr holds the entries
+// of the shading dictionary particular to the type.
+func (f *Fpdf) mesh(tp int, list []meshRecord, flags bool, keyStr string) {
+	// Coordinates are encoded as fractions of the bounding box of the mesh
+	xMin, yMin := math.Inf(1), math.Inf(1)
+	xMax, yMax := math.Inf(-1), math.Inf(-1)
+	for _, rec := range list {
+		for _, pt := range rec.pts {
+			x, y := pt.X*f.k, (f.h-pt.Y)*f.k
+			xMin, xMax = math.Min(xMin, x), math.Max(xMax, x)
+			yMin, yMax = math.Min(yMin, y), math.Max(yMax, y)
+		}
+	}
+	xMax, yMax = math.Max(xMax, xMin+1), math.Max(yMax, yMin+1)
+	var buf bytes.Buffer
+	coord := func(v, min, max float64) {
+		binary.Write(&buf, binary.BigEndian, uint32(math.Round((v-min)/(max-min)*math.MaxUint32)))
+	}
+	for _, rec := range list {
+		if flags {
+			buf.WriteByte(0)
+		}
+		for _, pt := range rec.pts {
+			coord(pt.X*f.k, xMin, xMax)
+			coord((f.h-pt.Y)*f.k, yMin, yMax)
+		}
+		for _, clr := range rec.clrs {
+			for _, v := range []int{clr.R, clr.G, clr.B} {
+				c, _ := colorComp(v)
+				buf.WriteByte(byte(c))
+			}
+		}
+	}
+	if flags {
+		keyStr += " /BitsPerFlag 8"
+	}
+	keyStr += sprintf(" /BitsPerCoordinate 32 /BitsPerComponent 8 /Decode [%.5f %.5f %.5f %.5f 0 1 0 1 0 1]",
+		xMin, xMax, yMin, yMax)
+	f.gradientList = append(f.gradientList, gradientType{tp: tp, meshStr: strings.TrimSpace(keyStr), mesh: buf.Bytes()})
+	f.outf("/Sh%d sh", len(f.gradientList)-1)
+}
+
+// putMesh writes the stream of the mesh shading gr.
+func (f *Fpdf) putMesh(gr gradientType) {
+	data := sliceCompress(gr.mesh)
+	f.newobj()
+	f.outf("<</ShadingType %d /ColorSpace /DeviceRGB %s /Filter /FlateDecode /Length %d>>",
+		gr.tp, gr.meshStr, len(data))
+	f.putstream(data)
+	f.out("endobj")
+}
@@ -0,0 +1,591 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// GlyphOutline returns the outline of the glyph of the character r in the
+// current font, at the current font size and in the unit of measure of the
+// document. The outline is a list of segments with the commands 'M' (move
+// to), 'L' (line to), 'Q' (quadratic Bézier curve), 'C' (cubic Bézier curve)
+// and 'Z' (close path), whose arguments are the absolute coordinates of their
+// control points followed by their end point, as for the segments of
+// SVGBasicType. The origin of the glyph on the baseline is at (0, 0) and, as
+// on the page, y increases downwards, so that the glyph is drawn at (x, y) by
+// adding x and y to the coordinates. The outlines of TrueType fonts are made
+// of quadratic curves and those of OpenType fonts with PostScript outlines of
+// cubic curves.
+//
+// Nil is returned for characters that the font lacks and for glyphs without
+// outline, such as that of the space. Ligatures and small capitals of the
+// font are selected by the codes assigned to them; see SetFontLigatures(). An
+// error is set if the current font is not a UTF-8 font.
+func (f *Fpdf) GlyphOutline(r rune) []SVGBasicSegmentType {
+	if f.err != nil {
+		return nil
+	}
+	if !f.isCurrentUTF8 {
+		f.SetErrorf("glyph outlines require a UTF-8 font")
+		return nil
+	}
+	segs, err := f.currentFont.glyphOutline(r)
+	if err != nil {
+		f.SetError(err)
+		return nil
+	}
+	scale := f.fontSize / float64(f.currentFont.utf8File.fontElementSize)
+	for j := range segs {
+		for k := 0; k < 2*segmentPoints(segs[j].Cmd); k += 2 {
+			segs[j].Arg[k] *= scale
+			segs[j].Arg[k+1] *= -scale
+		}
+	}
+	return segs
+}
+
+// TextToPath draws txtStr with the outlines of its glyphs as a path rather
+// than as text. As with Text(), the origin (x, y) is on the left of the first
+// character at the baseline. styleStr is as for DrawPath(): "F" fills the
+// glyphs with the current fill color, "D" strokes their outlines with the
+// current draw color and line width, and "DF" does both. Text drawn as a path
+// is not affected by the text color and cannot be selected or searched in the
+// document, but it can be painted like any other path, for example with an
+// outline of a different color than its fill, or transformed with
+// TransformBegin().
+//
+// The text is laid out as with Text(), with the fallback fonts, text
+// transform, ligatures and synthetic italic style of the current font.
+// Synthetic bold is not applied. An error is set if the current font is not a
+// UTF-8 font.
+func (f *Fpdf) TextToPath(x, y float64, txtStr, styleStr string) {
+	if f.err != nil {
+		return
+	}
+	if !f.isCurrentUTF8 {
+		f.SetErrorf("text paths require a UTF-8 font")
+		return
+	}
+	txtStr = f.shapeText(txtStr)
+	if f.isRTL {
+		x -= f.GetStringWidth(txtStr)
+	}
+	txtStr = f.visualText(txtStr)
+	skew := 0.0
+	if strings.Contains(f.fontSynthStr, "I") {
+		skew = syntheticSkew
+	}
+	fonts := f.fallbackFonts()
+	cw := f.charWidths()
+	codes := []rune(txtStr)
+	runes := make([]rune, len(codes))
+	for j, code := range codes {
+		runes[j] = code
+		if upper, ok := f.currentFont.features.syntheticCaps[code]; ok {
+			runes[j] = upper
+		}
+	}
+	idx := fallbackIndices(fonts, runes)
+	var s fmtBuffer
+	for j, r := range runes {
+		font, size := fonts[idx[j]], f.fontSize
+		if r != codes[j] {
+			size *= smallCapsScale
+		}
+		segs, err := font.glyphOutline(r)
+		if err != nil {
+			f.SetError(err)
+			return
+		}
+		f.outlinePath(&s, segs, x, y, size/float64(font.utf8File.fontElementSize), skew)
+		x += f.runeAdvance(cw, codes[j])
+	}
+	if s.Len() > 0 {
+		f.out(s.String() + fillDrawOp(styleStr))
+	}
+}
+
+// outlinePath writes the path operators of an outline in font units to s,
+// with the origin of the outline at (x, y) and scaled by scale. A skew
+// slants the outline as for synthetic italics.
+func (f *Fpdf) outlinePath(s *fmtBuffer, segs []SVGBasicSegmentType, x, y, scale, skew float64) {
+	pt := func(seg SVGBasicSegmentType, k int) (float64, float64) {
+		gx, gy := seg.Arg[2*k], seg.Arg[2*k+1]
+		return (x + (gx+skew*gy)*scale) * f.k, (f.h - y + gy*scale) * f.k
+	}
+	// Current point, in points
+	var cx, cy float64
+	for _, seg := range segs {
+		switch seg.Cmd {
+		case 'M':
+			cx, cy = pt(seg, 0)
+			s.printf("%.2f %.2f m ", cx, cy)
+		case 'L':
+			cx, cy = pt(seg, 0)
+			s.printf("%.2f %.2f l ", cx, cy)
+		case 'Q':
+			// A quadratic curve is the cubic curve with control points two
+			// thirds of the way to its control point
+			qx, qy := pt(seg, 0)
+			ex, ey := pt(seg, 1)
+			s.printf("%.5f %.5f %.5f %.5f %.5f %.5f c ", cx+2*(qx-cx)/3, cy+2*(qy-cy)/3,
+				ex+2*(qx-ex)/3, ey+2*(qy-ey)/3, ex, ey)
+			cx, cy = ex, ey
+		case 'C':
+			x0, y0 := pt(seg, 0)
+			x1, y1 := pt(seg, 1)
+			cx, cy = pt(seg, 2)
+			s.printf("%.5f %.5f %.5f %.5f %.5f %.5f c ", x0, y0, x1, y1, cx, cy)
+		case 'Z':
+			s.printf("h ")
+		}
+	}
+}
+
+// runeAdvance returns the advance of the code r of text printed with the
+// current font, given the widths of charWidths(), as GetStringWidth()
+// measures it.
+func (f *Fpdf) runeAdvance(cw []int, r rune) float64 {
+	switch {
+	case int(r) < len(cw) && cw[r] > 0:
+		if cw[r] != 65535 {
+			return float64(cw[r]) * f.fontSize / 1000
+		}
+		return 0
+	case f.currentFont.Desc.MissingWidth != 0:
+		return float64(f.currentFont.Desc.MissingWidth) * f.fontSize / 1000
+	}
+	return 500 * f.fontSize / 1000
+}
+
+// segmentPoints returns the number of points of a segment of an outline.
+func segmentPoints(cmd byte) int {
+	switch cmd {
+	case 'M', 'L':
+		return 1
+	case 'Q':
+		return 2
+	case 'C':
+		return 3
+	}
+	return 0
+}
+
+// glyphOutline returns the outline of the glyph of the character r in font
+// units, with y increasing upwards.
+func (font fontDefType) glyphOutline(r rune) ([]SVGBasicSegmentType, error) {
+	utf := font.utf8File
+	if len(utf.charSymbolDictionary) == 0 && utf.generateCMAP() == nil {
+		return nil, fmt.Errorf("font does not have cmap for Unicode")
+	}
+	symbol, ok := utf.charSymbolDictionary[int(r)]
+	if !ok || symbol == 0 {
+		return nil, nil
+	}
+	return font.symbolOutline(symbol)
+}
+
+// symbolOutline returns the outline of a glyph in font units, with y
+// increasing upwards.
+func (font fontDefType) symbolOutline(symbol int) ([]SVGBasicSegmentType, error) {
+	utf := font.utf8File
+	if !utf.isCFF() {
+		return utf.glyfOutline(symbol, 0)
+	}
+	if utf.cffOutlines == nil {
+		c, err := parseCFF(utf.getTableData("CFF "))
+		if err != nil {
+			return nil, err
+		}
+		utf.cffOutlines = c
+	}
+	return utf.cffOutlines.outline(symbol)
+}
+
+// glyfOutline returns the outline of a glyph of the glyf table. depth is the
+// nesting level of composite glyphs.
+func (utf *utf8FontFile) glyfOutline(symbol, depth int) (segs []SVGBasicSegmentType, err error) {
+	start, end := utf.symbolRange(symbol)
+	glyf := utf.getTableData("glyf")
+	if depth > 8 || end > len(glyf) {
+		return nil, fmt.Errorf("invalid glyf table")
+	}
+	if end <= start {
+		return
+	}
+	var g varGlyph
+	if err = g.parse(glyf[start:end]); err != nil {
+		return
+	}
+	if g.contours >= 0 {
+		return quadraticOutline(g.xs, g.ys, g.onCurve, g.endPts), nil
+	}
+	for _, c := range g.components {
+		var list []SVGBasicSegmentType
+		if list, err = utf.glyfOutline(c.glyph, depth+1); err != nil {
+			return
+		}
+		// Transform of the component. Components positioned by matching
+		// points are not moved.
+		a, b, cc, d := 1.0, 0.0, 0.0, 1.0
+		f2dot14 := func(j int) float64 {
+			return float64(int16(binary.BigEndian.Uint16(c.transform[2*j:]))) / 16384
+		}
+		switch len(c.transform) {
+		case 2:
+			a, d = f2dot14(0), f2dot14(0)
+		case 4:
+			a, d = f2dot14(0), f2dot14(1)
+		case 8:
+			a, b, cc, d = f2dot14(0), f2dot14(1), f2dot14(2), f2dot14(3)
+		}
+		dx, dy := 0.0, 0.0
+		if c.flags&0x0002 != 0 {
+			dx, dy = float64(c.arg1), float64(c.arg2)
+		}
+		for _, seg := range list {
+			for k := 0; k < 2*segmentPoints(seg.Cmd); k += 2 {
+				x, y := seg.Arg[k], seg.Arg[k+1]
+				seg.Arg[k], seg.Arg[k+1] = a*x+cc*y+dx, b*x+d*y+dy
+			}
+			segs = append(segs, seg)
+		}
+	}
+	return
+}
+
+// quadraticOutline returns the outline of the contours of a TrueType glyph,
+// given the coordinates of its points, whether they are on the curve and the
+// last point of each contour. Two consecutive points off the curve imply a
+// point on the curve halfway between them.
+func quadraticOutline(xs, ys []int, onCurve []bool, endPts []int) (segs []SVGBasicSegmentType) {
+	start := 0
+	for _, end := range endPts {
+		if end < start || end >= len(xs) {
+			break
+		}
+		n := end - start + 1
+		pt := func(j int) (float64, float64, bool) {
+			k := start + j%n
+			return float64(xs[k]), float64(ys[k]), onCurve[k]
+		}
+		// The contour starts at a point on the curve, or halfway between its
+		// last and first points if it has none
+		first := -1
+		for j := 0; j < n && first < 0; j++ {
+			if onCurve[start+j] {
+				first = j
+			}
+		}
+		var x0, y0 float64
+		if first >= 0 {
+			x0, y0, _ = pt(first)
+		} else {
+			ax, ay, _ := pt(n - 1)
+			bx, by, _ := pt(0)
+			x0, y0 = (ax+bx)/2, (ay+by)/2
+		}
+		segs = append(segs, SVGBasicSegmentType{Cmd: 'M', Arg: [6]float64{x0, y0}})
+		var px, py float64
+		pending := false
+		add := func(x, y float64, on bool) {
+			switch {
+			case on && pending:
+				segs = append(segs, SVGBasicSegmentType{Cmd: 'Q', Arg: [6]float64{px, py, x, y}})
+				pending = false
+			case on:
+				segs = append(segs, SVGBasicSegmentType{Cmd: 'L', Arg: [6]float64{x, y}})
+			case pending:
+				mx, my := (px+x)/2, (py+y)/2
+				segs = append(segs, SVGBasicSegmentType{Cmd: 'Q', Arg: [6]float64{px, py, mx, my}})
+				px, py = x, y
+			default:
+				px, py, pending = x, y, true
+			}
+		}
+		for j := 1; j < n; j++ {
+			x, y, on := pt(first + j)
+			add(x, y, on)
+		}
+		if first < 0 {
+			x, y, on := pt(n - 1)
+			add(x, y, on)
+		}
+		if pending {
+			add(x0, y0, true)
+		}
+		segs = append(segs, SVGBasicSegmentType{Cmd: 'Z'})
+		start = end + 1
+	}
+	return
+}
+
+// cffSubrBias returns the bias of the numbers of the subroutines of an INDEX.
+func cffSubrBias(subrs [][]byte) int {
+	switch {
+	case len(subrs) < 1240:
+		return 107
+	case len(subrs) < 33900:
+		return 1131
+	}
+	return 32768
+}
+
+// outline returns the outline of a glyph by interpreting its Type 2
+// charstring.
+func (c *cffFont) outline(symbol int) ([]SVGBasicSegmentType, error) {
+	if symbol >= len(c.charStrings) || len(c.fds) == 0 {
+		return nil, nil
+	}
+	fd := c.fds[0]
+	if c.fdSelect != nil && int(c.fdSelect[symbol]) < len(c.fds) {
+		fd = c.fds[c.fdSelect[symbol]]
+	}
+	var (
+		segs      []SVGBasicSegmentType
+		stack     []float64
+		x, y      float64
+		stems     int
+		haveWidth bool
+		open      bool
+		depth     int
+	)
+	bad := fmt.Errorf("invalid CFF charstring")
+	// width drops the advance width that the first stack clearing operator
+	// may be preceded by, when there are more operands than expected.
+	width := func(odd bool) {
+		if !haveWidth && odd && len(stack) > 0 {
+			stack = stack[1:]
+		}
+		haveWidth = true
+	}
+	moveTo := func(dx, dy float64) {
+		if open {
+			segs = append(segs, SVGBasicSegmentType{Cmd: 'Z'})
+		}
+		x, y = x+dx, y+dy
+		segs = append(segs, SVGBasicSegmentType{Cmd: 'M', Arg: [6]float64{x, y}})
+		open = true
+	}
+	lineTo := func(dx, dy float64) {
+		x, y = x+dx, y+dy
+		segs = append(segs, SVGBasicSegmentType{Cmd: 'L', Arg: [6]float64{x, y}})
+	}
+	curveTo := func(dx0, dy0, dx1, dy1, dx2, dy2 float64) {
+		x0, y0 := x+dx0, y+dy0
+		x1, y1 := x0+dx1, y0+dy1
+		x, y = x1+dx2, y1+dy2
+		segs = append(segs, SVGBasicSegmentType{Cmd: 'C', Arg: [6]float64{x0, y0, x1, y1, x, y}})
+	}
+	var run func(cs []byte) (bool, error)
+	run = func(cs []byte) (bool, error) {
+		depth++
+		defer func() { depth-- }()
+		if depth > 10 {
+			return false, bad
+		}
+		for pos := 0; pos < len(cs); {
+			b := int(cs[pos])
+			pos++
+			switch {
+			case b == 28:
+				if pos+2 > len(cs) {
+					return false, bad
+				}
+				stack = append(stack, float64(int16(binary.BigEndian.Uint16(cs[pos:]))))
+				pos += 2
+				continue
+			case b >= 32 && b <= 246:
+				stack = append(stack, float64(b-139))
+				continue
+			case b >= 247 && b <= 254:
+				if pos >= len(cs) {
+					return false, bad
+				}
+				v := (b-247)*256 + int(cs[pos]) + 108
+				if b >= 251 {
+					v = -(b-251)*256 - int(cs[pos]) - 108
+				}
+				stack = append(stack, float64(v))
+				pos++
+				continue
+			case b == 255:
+				if pos+4 > len(cs) {
+					return false, bad
+				}
+				stack = append(stack, float64(int32(binary.BigEndian.Uint32(cs[pos:])))/65536)
+				pos += 4
+				continue
+			}
+			n := len(stack)
+			switch b {
+			case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+				width(n%2 == 1)
+				stems += len(stack) / 2
+			case 19, 20: // hintmask, cntrmask
+				width(n%2 == 1)
+				stems += len(stack) / 2
+				pos += (stems + 7) / 8
+			case 21: // rmoveto
+				width(n > 2)
+				if len(stack) < 2 {
+					return false, bad
+				}
+				moveTo(stack[0], stack[1])
+			case 22: // hmoveto
+				width(n > 1)
+				if len(stack) < 1 {
+					return false, bad
+				}
+				moveTo(stack[0], 0)
+			case 4: // vmoveto
+				width(n > 1)
+				if len(stack) < 1 {
+					return false, bad
+				}
+				moveTo(0, stack[0])
+			case 5: // rlineto
+				for j := 0; j+1 < n; j += 2 {
+					lineTo(stack[j], stack[j+1])
+				}
+			case 6, 7: // hlineto, vlineto
+				horizontal := b == 6
+				for j := 0; j < n; j++ {
+					if horizontal {
+						lineTo(stack[j], 0)
+					} else {
+						lineTo(0, stack[j])
+					}
+					horizontal = !horizontal
+				}
+			case 8: // rrcurveto
+				for j := 0; j+5 < n; j += 6 {
+					curveTo(stack[j], stack[j+1], stack[j+2], stack[j+3], stack[j+4], stack[j+5])
+				}
+			case 24: // rcurveline
+				j := 0
+				for ; j+5 < n-2; j += 6 {
+					curveTo(stack[j], stack[j+1], stack[j+2], stack[j+3], stack[j+4], stack[j+5])
+				}
+				if j+1 < n {
+					lineTo(stack[j], stack[j+1])
+				}
+			case 25: // rlinecurve
+				j := 0
+				for ; j+1 < n-6; j += 2 {
+					lineTo(stack[j], stack[j+1])
+				}
+				if j+5 < n {
+					curveTo(stack[j], stack[j+1], stack[j+2], stack[j+3], stack[j+4], stack[j+5])
+				}
+			case 26, 27: // vvcurveto, hhcurveto
+				j, d := 0, 0.0
+				if n%2 == 1 {
+					j, d = 1, stack[0]
+				}
+				for ; j+3 < n; j += 4 {
+					if b == 26 {
+						curveTo(d, stack[j], stack[j+1], stack[j+2], 0, stack[j+3])
+					} else {
+						curveTo(stack[j], d, stack[j+1], stack[j+2], stack[j+3], 0)
+					}
+					d = 0
+				}
+			case 30, 31: // vhcurveto, hvcurveto
+				horizontal := b == 31
+				for j := 0; j+3 < n; j += 4 {
+					last := 0.0
+					if j+5 == n {
+						last = stack[j+4]
+					}
+					if horizontal {
+						curveTo(stack[j], 0, stack[j+1], stack[j+2], last, stack[j+3])
+					} else {
+						curveTo(0, stack[j], stack[j+1], stack[j+2], stack[j+3], last)
+					}
+					horizontal = !horizontal
+				}
+			case 10, 29: // callsubr, callgsubr
+				if n < 1 {
+					return false, bad
+				}
+				subrs := fd.subrs
+				if b == 29 {
+					subrs = c.globalSubrs
+				}
+				index := int(stack[n-1]) + cffSubrBias(subrs)
+				if index < 0 || index >= len(subrs) {
+					return false, bad
+				}
+				stack = stack[:n-1]
+				if end, err := run(subrs[index]); end || err != nil {
+					return end, err
+				}
+				continue
+			case 11: // return
+				return false, nil
+			case 14: // endchar
+				width(n == 1 || n == 5)
+				return true, nil
+			case 12:
+				if pos >= len(cs) {
+					return false, bad
+				}
+				b = int(cs[pos])
+				pos++
+				a := make([]float64, 12)
+				copy(a, stack)
+				switch b {
+				case 35: // flex
+					curveTo(a[0], a[1], a[2], a[3], a[4], a[5])
+					curveTo(a[6], a[7], a[8], a[9], a[10], a[11])
+				case 34: // hflex
+					curveTo(a[0], 0, a[1], a[2], a[3], 0)
+					curveTo(a[4], 0, a[5], -a[2], a[6], 0)
+				case 36: // hflex1
+					curveTo(a[0], a[1], a[2], a[3], a[4], 0)
+					curveTo(a[5], 0, a[6], a[7], a[8], -(a[1] + a[3] + a[7]))
+				case 37: // flex1
+					dx := a[0] + a[2] + a[4] + a[6] + a[8]
+					dy := a[1] + a[3] + a[5] + a[7] + a[9]
+					x0, y0 := x, y
+					curveTo(a[0], a[1], a[2], a[3], a[4], a[5])
+					curveTo(a[6], a[7], a[8], a[9], 0, 0)
+					if math.Abs(dx) > math.Abs(dy) {
+						x, y = x0+dx+a[10], y0
+					} else {
+						x, y = x0, y0+dy+a[10]
+					}
+					segs[len(segs)-1].Arg[4], segs[len(segs)-1].Arg[5] = x, y
+				}
+			}
+			stack = stack[:0]
+		}
+		return false, nil
+	}
+	if _, err := run(c.charStrings[symbol]); err != nil {
+		return nil, err
+	}
+	if open {
+		segs = append(segs, SVGBasicSegmentType{Cmd: 'Z'})
+	}
+	return segs, nil
+}
@@ -0,0 +1,496 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"strings"
+)
+
+// ParagraphType lays out paragraphs of text with the current font. Unlike
+// MultiCell(), which fills each line in turn with as many words as fit,
+// ParagraphType chooses the line breaks of a paragraph as a whole so as to
+// even out the space left on its lines, which avoids the widely spaced lines
+// that justification otherwise produces. See ParagraphNew() to create a
+// receiver that is associated with the PDF document instance.
+type ParagraphType struct {
+	pdf      *Fpdf
+	w        float64
+	lineHt   float64
+	alignStr string
+	before   float64 // Space before each paragraph
+	after    float64 // Space after each paragraph
+	first    float64 // Indentation of the first line
+	hanging  float64 // Indentation of the other lines
+	drop     int     // Number of lines of the drop cap, 0 for none
+}
+
+// ParagraphNew returns an instance that lays out paragraphs in lines of width
+// w and height lineHt, in the unit of measure specified in New(). If w is 0,
+// lines extend to the right margin from the current position when the
+// paragraph is written. If lineHt is 0, the line height of the document set
+// with SetLineHeight() or SetLineHeightFactor() when the paragraph is written
+// is used. The cell margin is left on both sides of the lines.
+//
+// alignStr specifies the alignment of the lines: "L" or an empty string for
+// left alignment (ragged right), "R" for right alignment (ragged left), "C"
+// for centered lines and "J" for full justification, in which the space
+// between the words of each line but the last is stretched so that the line
+// fills the width. Lines of a paragraph that end with a newline are not
+// justified either.
+func (f *Fpdf) ParagraphNew(w, lineHt float64, alignStr string) (par ParagraphType) {
+	par.pdf = f
+	par.w = w
+	par.lineHt = lineHt
+	par.alignStr = alignStr
+	return
+}
+
+// SetSpacing sets the space left before and after each paragraph written,
+// in the unit of measure specified in New(), so that successive paragraphs
+// are set apart without moving the current position between them. The space
+// before a paragraph that begins a page, after a page break, is dropped. The
+// default values are 0.
+func (par *ParagraphType) SetSpacing(before, after float64) {
+	par.before = before
+	par.after = after
+}
+
+// SetIndent sets the indentation of the first line of each paragraph
+// written, such as the first-line indent of the paragraphs of a book, and
+// that of its other lines, such as the hanging indent of a bibliography, in
+// the unit of measure specified in New(). Indentations are from the left of
+// the lines, or from their right for right-to-left text, and may be
+// negative. The default values are 0.
+func (par *ParagraphType) SetIndent(firstLine, hanging float64) {
+	par.first = firstLine
+	par.hanging = hanging
+}
+
+// SetDropCap sets the number of lines of text that the drop cap of each
+// paragraph written spans, as in magazines: the first letter of the
+// paragraph is set in the current font, large enough for its top to be level
+// with the tops of the capitals of the first line and for its baseline to be
+// that of the last of these lines, and the text that follows wraps around
+// it. The letter is at the first-line indentation of the paragraph, on the
+// right for right-to-left text, and is separated from the text by the width
+// of a space. A paragraph shorter than its drop cap is made as high as the
+// drop cap. A value of lines less than 2, the default, sets no drop cap.
+func (par *ParagraphType) SetDropCap(lines int) {
+	par.drop = lines
+	if lines < 2 {
+		par.drop = 0
+	}
+}
+
+// Write prints the paragraph txtStr from the current position, one line
+// below the other, starting a new page when a line does not fit on the
+// current page if automatic page breaking is enabled. Page breaks avoid
+// widows and orphans as set with SetOrphansWidows(). Lines that follow a
+// newline are lines of the same paragraph, which are indented as its lines
+// other than the first. Upon method exit, the current position is at the
+// left margin below the paragraph and the space after it. The height of the
+// lines printed and of the spaces before and after them is returned.
+func (par *ParagraphType) Write(txtStr string) (ht float64) {
+	f := par.pdf
+	if f.err != nil {
+		return
+	}
+	w := par.width()
+	// The direction of right-to-left text ends its last lines on the right
+	rtl := f.isRTL || f.baseDirStr == "R"
+	capStr, txtStr, capPt, dropW := par.dropCap(txtStr)
+	lines := par.layout(txtStr, w, dropW)
+	lasts := make([]bool, len(lines))
+	for j, line := range lines {
+		lasts[j] = line.last
+	}
+	lineHt := f.textLineHt(par.lineHt)
+	x := f.x
+	page := f.page
+	f.y += par.before
+	keep := f.lineKeeper(lasts, lineHt)
+	for j, line := range lines {
+		if j == 0 && capStr != "" {
+			// The lines of the drop cap are kept on one page
+			f.y = f.imageFlow(float64(par.drop) * lineHt)
+		}
+		keep.next()
+		if j == 0 && capStr != "" {
+			par.dropCapPrint(x, w, rtl, capStr, capPt, lineHt)
+		}
+		indent := par.indent(j, dropW)
+		f.x = x
+		if !rtl {
+			f.x += indent
+		}
+		alignStr := par.alignStr
+		if alignStr == "J" && (line.last || line.spaces == 0 && !f.letterJustify) {
+			alignStr = "L"
+			if rtl {
+				alignStr = "R"
+			}
+		}
+		switch {
+		case alignStr == "J" && f.letterJustify:
+			var ws float64
+			ws, f.cs = f.justifySpacing(line.str, w-indent-2*f.cMargin)
+			if !f.isCurrentUTF8 {
+				f.ws = ws
+				f.wordSpacingPut()
+			}
+		case alignStr == "J" && !f.isCurrentUTF8:
+			// Word spacing stretches the spaces of text in single-byte
+			// encodings
+			f.ws = float64(line.slack) / 1000 * f.fontSize / float64(line.spaces)
+			f.wordSpacingPut()
+		}
+		f.CellFormat(w-indent, lineHt, line.str, "", 2, alignStr, false, 0, "")
+		f.cs = 0
+		if f.ws != 0 {
+			f.ws = 0
+			f.wordSpacingPut()
+		}
+		if j == 0 && f.page == page {
+			// The space before the paragraph is dropped if its first line
+			// breaks the page
+			ht += par.before
+		}
+		ht += lineHt
+	}
+	if capStr != "" && len(lines) < par.drop {
+		f.y += float64(par.drop-len(lines)) * lineHt
+		ht += float64(par.drop-len(lines)) * lineHt
+	}
+	f.y += par.after
+	f.x = f.lMargin
+	return ht + par.after
+}
+
+// Lines returns the lines in which the paragraph txtStr is laid out with the
+// current font, if written from the current position. The drop cap of the
+// paragraph, if set with SetDropCap(), is not part of its first line.
+func (par *ParagraphType) Lines(txtStr string) (lines []string) {
+	_, txtStr, _, dropW := par.dropCap(txtStr)
+	for _, line := range par.layout(txtStr, par.width(), dropW) {
+		lines = append(lines, line.str)
+	}
+	return
+}
+
+// Height returns the height of the paragraph txtStr laid out with the
+// current font, if written from the current position on a single page,
+// including the spaces before and after it.
+func (par *ParagraphType) Height(txtStr string) float64 {
+	_, txtStr, _, dropW := par.dropCap(txtStr)
+	n := len(par.layout(txtStr, par.width(), dropW))
+	if dropW > 0 && n < par.drop {
+		n = par.drop
+	}
+	return float64(n)*par.pdf.textLineHt(par.lineHt) + par.before + par.after
+}
+
+// indent returns the indentation of the line numbered n, counted from 0, of a
+// paragraph whose drop cap takes the width dropW.
+func (par *ParagraphType) indent(n int, dropW float64) float64 {
+	switch {
+	case dropW > 0 && n < par.drop:
+		return par.first + dropW
+	case n == 0:
+		return par.first
+	}
+	return par.hanging
+}
+
+// dropCap returns the drop cap of the paragraph txtStr, its text that
+// follows the drop cap, the font size of the drop cap in points and the width
+// that the drop cap takes from the lines it spans. The drop cap is empty if
+// none is set.
+func (par *ParagraphType) dropCap(txtStr string) (capStr, rest string, capPt, w float64) {
+	f := par.pdf
+	rest = txtStr
+	if par.drop == 0 || f.err != nil || f.currentFont.Name == "" {
+		return
+	}
+	runes := f.textRunes(strings.TrimLeft(txtStr, " "))
+	if len(runes) == 0 || runes[0] == '\n' {
+		return
+	}
+	k := graphemeBreak(graphemeBounds(runes), 0, 0)
+	capStr, rest = f.runesString(runes[:k]), strings.TrimLeft(f.runesString(runes[k:]), " ")
+	// The cap height of the drop cap spans the lines that follow the first
+	// one and the cap height of the first one
+	capHt := f.FontMetrics("", "").CapHeight / f.fontSize
+	if capHt <= 0 {
+		capHt = 0.7
+	}
+	size := float64(par.drop-1)*f.textLineHt(par.lineHt)/capHt + f.fontSize
+	capPt = size * f.k
+	w = float64(f.GetStringSymbolWidth(capStr))*size/1000 + f.GetStringWidth(" ")
+	return
+}
+
+// dropCapPrint prints the drop cap capStr in a font of size capPt points at
+// the beginning of the first line of a paragraph at x of width w, which is on
+// the right if rtl is true.
+func (par *ParagraphType) dropCapPrint(x, w float64, rtl bool, capStr string, capPt, lineHt float64) {
+	f := par.pdf
+	baseline := f.y + (float64(par.drop)-.5)*lineHt + .3*f.fontSize
+	sizePt := f.fontSizePt
+	f.SetFontSize(capPt)
+	if rtl {
+		x += w - par.first - f.cMargin - f.GetStringWidth(capStr)
+	} else {
+		x += par.first + f.cMargin
+	}
+	f.Text(x, baseline, capStr)
+	f.SetFontSize(sizePt)
+}
+
+// width returns the width of the lines written from the current position.
+func (par *ParagraphType) width() float64 {
+	if par.w == 0 {
+		f := par.pdf
+		return f.w - f.rMargin - f.x
+	}
+	return par.w
+}
+
+// paragraphWord is a word of a paragraph, or a character of a script that
+// can be broken between any two characters, followed by the spaces up to the
+// next word.
+type paragraphWord struct {
+	start, end int  // Range of the characters of the word
+	w          int  // Width of the word
+	spaceW     int  // Width of the spaces that follow the word
+	spaces     int  // Number of spaces that follow the word
+	hyphen     bool // Part of a word hyphenated after it
+}
+
+// paragraphLine is a line of a laid out paragraph.
+type paragraphLine struct {
+	str    string
+	slack  int  // Width left on the line
+	spaces int  // Number of spaces between the words of the line
+	last   bool // Last line of the paragraph or line ended by a newline
+}
+
+// layout breaks the paragraph txtStr into lines of width w less their
+// indentation, given the width dropW of its drop cap. Widths are
+// measured in thousandths of the font size, as by GetStringSymbolWidth(), and
+// include character and word spacing.
+func (par *ParagraphType) layout(txtStr string, w, dropW float64) (lines []paragraphLine) {
+	f := par.pdf
+	if f.err != nil {
+		return
+	}
+	// Text in single-byte encodings is handled as characters of its bytes
+	runes := f.textRunes(f.shapeText(strings.Replace(txtStr, "\r", "", -1)))
+	width := func(runes []rune) int {
+		return int(math.Round(f.GetSpacedStringWidth(f.runesString(runes)) * 1000 / f.fontSize))
+	}
+	wmax := func(n int) int {
+		return int(math.Ceil((w - par.indent(n, dropW) - 2*f.cMargin) * 1000 / f.fontSize))
+	}
+	// Lines before the line numbered indented have their own indentation
+	indented := 1
+	if dropW > 0 {
+		indented = par.drop
+	}
+	spaceW, hyphenW := width([]rune{' '}), width([]rune{'-'})
+	for len(runes) > 0 && runes[len(runes)-1] == '\n' {
+		runes = runes[:len(runes)-1]
+	}
+	for _, segment := range splitRunes(runes, '\n') {
+		// The widths of the lines of the segment, the last of which is that
+		// of the lines that follow
+		var widths []int
+		for n := len(lines); n < indented; n++ {
+			widths = append(widths, wmax(n))
+		}
+		widths = append(widths, wmax(max(len(lines), indented)))
+		wmin := widths[0]
+		for _, wd := range widths {
+			wmin = minInt(wmin, wd)
+		}
+		words := paragraphWords(segment, wmin, spaceW, width, f.hyphenPoints)
+		for _, b := range breakParagraph(words, widths, hyphenW) {
+			first, last := words[b[0]], words[b[1]-1]
+			line := paragraphLine{str: f.runesString(segment[first.start:last.end]), last: b[1] == len(words)}
+			natural := 0
+			for _, word := range words[b[0]:b[1]] {
+				natural += word.w + word.spaceW
+				line.spaces += word.spaces
+			}
+			natural -= last.spaceW
+			line.spaces -= last.spaces
+			if last.hyphen {
+				line.str += "-"
+				natural += hyphenW
+			}
+			line.slack = wmax(len(lines)) - natural
+			lines = append(lines, line)
+		}
+		if len(words) == 0 {
+			lines = append(lines, paragraphLine{last: true})
+		}
+	}
+	return
+}
+
+// splitRunes splits runes at each occurrence of sep.
+func splitRunes(runes []rune, sep rune) (list [][]rune) {
+	start := 0
+	for j, r := range runes {
+		if r == sep {
+			list = append(list, runes[start:j])
+			start = j + 1
+		}
+	}
+	return append(list, runes[start:])
+}
+
+// paragraphWords returns the words of a paragraph without newlines. Leading
+// spaces belong to the first word. Words are split into their parts between
+// the hyphenation points that hyphenPoints returns, and parts wider than wmax
+// into as many parts as needed, between grapheme clusters.
+func paragraphWords(runes []rune, wmax, spaceW int, width func([]rune) int,
+	hyphenPoints func([]rune) []int) (words []paragraphWord) {
+	j := 0
+	for j < len(runes) && runes[j] == ' ' {
+		j++
+	}
+	bounds := graphemeBounds(runes)
+	start := 0
+	for j < len(runes) {
+		end := j + 1
+		if !isChinese(runes[j]) {
+			for end < len(runes) && runes[end] != ' ' && !isChinese(runes[end]) {
+				end++
+			}
+		}
+		// Parts of the word that can be hyphenated and that fit on a line
+		points := hyphenPoints(runes[j:end])
+		for n := 0; n <= len(points); n++ {
+			partEnd := end
+			if n < len(points) {
+				partEnd = j + points[n]
+			}
+			for start < partEnd {
+				k := partEnd
+				for k > start+1 && (!bounds[k] || width(runes[start:k]) > wmax) {
+					k--
+				}
+				if !bounds[k] {
+					k = graphemeBreak(bounds, start, start)
+				}
+				words = append(words, paragraphWord{start: start, end: k, w: width(runes[start:k]),
+					hyphen: k == partEnd && partEnd < end})
+				start = k
+			}
+		}
+		for end < len(runes) && runes[end] == ' ' {
+			words[len(words)-1].spaces++
+			words[len(words)-1].spaceW += spaceW
+			end++
+		}
+		j, start = end, end
+	}
+	return
+}
+
+// hyphenDemerits are the demerits added for a hyphenated line, those of the
+// hyphen penalty of TeX.
+const hyphenDemerits = 50 * 50
+
+// breakParagraph returns the ranges of the words of each line of a paragraph
+// that minimize the sum of the demerits of its lines, as in the line breaking
+// algorithm of Knuth and Plass. The last line has no demerits as long as it
+// fits, and the demerits of other lines grow with the space left on them
+// relative to the width of their spaces. The lines are as wide as widths
+// specifies in turn, the last of widths being that of the lines that follow.
+// A hyphenated line ends with a hyphen of width hyphenW.
+func breakParagraph(words []paragraphWord, widths []int, hyphenW int) (lines [][2]int) {
+	n, m := len(words), len(widths)
+	wide := 0
+	for _, w := range widths {
+		wide = max(wide, w)
+	}
+	// best[j][s] are the least demerits of the words before j in lines of
+	// which the next one is as wide as widths[s], and prev[j][s] the word and
+	// the state at which their last line begins
+	best := make([][]float64, n+1)
+	prev := make([][][2]int, n+1)
+	for j := range best {
+		best[j] = make([]float64, m)
+		prev[j] = make([][2]int, m)
+		for s := range best[j] {
+			best[j][s] = math.Inf(1)
+		}
+	}
+	best[0][0] = 0
+	for j := 1; j <= n; j++ {
+		natural, stretch := 0, 0
+		if words[j-1].hyphen {
+			natural = hyphenW
+		}
+		for i := j - 1; i >= 0; i-- {
+			natural += words[i].w
+			if i < j-1 {
+				natural += words[i].spaceW
+				stretch += words[i].spaceW
+			}
+			if natural > wide && i < j-1 {
+				break
+			}
+			for s, wmax := range widths {
+				if math.IsInf(best[i][s], 1) || natural > wmax && i < j-1 {
+					continue
+				}
+				demerits := 0.0
+				if j < n {
+					badness := 10000.0
+					if slack := float64(wmax - natural); stretch > 0 {
+						badness = math.Min(100*math.Pow(slack/float64(stretch), 3), 10000)
+					} else if slack <= 0 {
+						badness = 0
+					}
+					demerits = (10 + badness) * (10 + badness)
+					if words[j-1].hyphen {
+						demerits += hyphenDemerits
+					}
+				}
+				t := minInt(s+1, m-1)
+				if best[i][s]+demerits < best[j][t] {
+					best[j][t], prev[j][t] = best[i][s]+demerits, [2]int{i, s}
+				}
+			}
+		}
+	}
+	s := 0
+	for t := range best[n] {
+		if best[n][t] < best[n][s] {
+			s = t
+		}
+	}
+	for j := n; j > 0; {
+		i := prev[j][s][0]
+		lines = append([][2]int{{i, j}}, lines...)
+		j, s = i, prev[j][s][1]
+	}
+	return
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+)
+
+// PathType is a figure made of subpaths of straight lines, Bézier curves and
+// elliptical arcs, each of which may be open or closed, that is drawn,
+// filled or used as a clipping path as a whole with Path() and ClipPath().
+// Unlike the figures drawn with Polygon(), Curve() or Arc(), the subpaths of
+// a path can be filled together, so that holes are cut out of shapes
+// according to the nonzero winding number or the even-odd rule. Unlike the
+// path that MoveTo() and DrawPath() build in the page, a path is independent
+// of the document, and can be printed several times and on any page.
+// Coordinates are in the unit of measure specified in New(). The zero value
+// is an empty path.
+type PathType struct {
+	segs  []pathSegment
+	start PointType // Start of the current subpath
+	cur   PointType // Current point
+	begun bool      // A subpath has begun
+}
+
+// pathSegment is a segment of a path: the beginning of a subpath ('m'), a
+// straight line ('l') or a cubic Bézier curve ('c') to the last of pts, or
+// the closing of a subpath ('h').
+type pathSegment struct {
+	op  byte
+	pts [3]PointType
+}
+
+// MoveTo begins a new subpath at (x, y).
+func (p *PathType) MoveTo(x, y float64) {
+	p.segs = append(p.segs, pathSegment{op: 'm', pts: [3]PointType{{X: x, Y: y}}})
+	p.start, p.cur, p.begun = PointType{X: x, Y: y}, PointType{X: x, Y: y}, true
+}
+
+// LineTo adds a straight line from the current point to (x, y), which
+// becomes the current point. A subpath begins at (x, y) if none has.
+func (p *PathType) LineTo(x, y float64) {
+	if !p.begun {
+		p.MoveTo(x, y)
+		return
+	}
+	p.segs = append(p.segs, pathSegment{op: 'l', pts: [3]PointType{{X: x, Y: y}}})
+	p.cur = PointType{X: x, Y: y}
+}
+
+// CubicTo adds a cubic Bézier curve from the current point to (x, y), with
+// the control points (cx0, cy0) and (cx1, cy1), as CurveBezierCubicTo() does.
+// A subpath begins at the current point, or at (x, y) if none has.
+func (p *PathType) CubicTo(cx0, cy0, cx1, cy1, x, y float64) {
+	if !p.begun {
+		p.MoveTo(x, y)
+	}
+	p.segs = append(p.segs, pathSegment{op: 'c', pts: [3]PointType{{X: cx0, Y: cy0}, {X: cx1, Y: cy1}, {X: x, Y: y}}})
+	p.cur = PointType{X: x, Y: y}
+}
+
+// QuadTo adds a quadratic Bézier curve from the current point to (x, y),
+// with the control point (cx, cy): the curve is tangent at its ends to the
+// lines from them to the control point.
+func (p *PathType) QuadTo(cx, cy, x, y float64) {
+	if !p.begun {
+		p.MoveTo(x, y)
+	}
+	// The quadratic curve is the cubic curve whose control points are two
+	// thirds of the way from its ends to the quadratic control point
+	p0 := p.cur
+	p.CubicTo(p0.X+2*(cx-p0.X)/3, p0.Y+2*(cy-p0.Y)/3, x+2*(cx-x)/3, y+2*(cy-y)/3, x, y)
+}
+
+// ArcTo adds an elliptical arc centered at (x, y) of horizontal and vertical
+// radii rx and ry, rotated by degRotate degrees, from the angle degStart to
+// the angle degEnd, as ArcTo() of Fpdf does: angles are in degrees,
+// measured counter-clockwise from the 3 o'clock position, and the arc goes
+// clockwise if degEnd is less than degStart. A straight line joins the
+// current point to the start of the arc, at which a subpath begins if none
+// has. The end of the arc becomes the current point.
+func (p *PathType) ArcTo(x, y, rx, ry, degRotate, degStart, degEnd float64) {
+	// Angles are measured clockwise in the page, whose vertical axis points
+	// down, as those of arcCubics() are
+	p.arcCubics(x, y, rx, ry, -degRotate*math.Pi/180, -degStart*math.Pi/180,
+		-(degEnd-degStart)*math.Pi/180, true)
+}
+
+// EllipticalArcTo adds an elliptical arc from the current point to (x, y), as
+// the arc command of SVG paths does. The ellipse has horizontal and vertical
+// radii rx and ry, and is rotated clockwise by degRotate degrees. Of the four
+// arcs of two such ellipses that join the points, largeArc selects one of
+// more than 180 degrees, and sweep one that goes clockwise. The radii are
+// scaled up if no such ellipse joins the points, and an arc of radius 0 is a
+// straight line. A subpath begins at (x, y) if none has.
+func (p *PathType) EllipticalArcTo(rx, ry, degRotate float64, largeArc, sweep bool, x, y float64) {
+	if !p.begun {
+		p.MoveTo(x, y)
+		return
+	}
+	x1, y1 := p.cur.X, p.cur.Y
+	if x1 == x && y1 == y {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		p.LineTo(x, y)
+		return
+	}
+	// The center of the ellipse is found as in the implementation notes of
+	// the SVG specification, in the coordinates of the unrotated ellipse
+	// centered between the points
+	rot := degRotate * math.Pi / 180
+	sinRot, cosRot := math.Sincos(rot)
+	dx, dy := (x1-x)/2, (y1-y)/2
+	x1p, y1p := cosRot*dx+sinRot*dy, -sinRot*dx+cosRot*dy
+	if l := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); l > 1 {
+		rx, ry = rx*math.Sqrt(l), ry*math.Sqrt(l)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+	den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+	coef := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cxp, cyp := coef*rx*y1p/ry, -coef*ry*x1p/rx
+	cx := cosRot*cxp - sinRot*cyp + (x1+x)/2
+	cy := sinRot*cxp + cosRot*cyp + (y1+y)/2
+	start := math.Atan2((y1p-cyp)/ry, (x1p-cxp)/rx)
+	sweepAngle := math.Atan2((-y1p-cyp)/ry, (-x1p-cxp)/rx) - start
+	switch {
+	case sweep && sweepAngle < 0:
+		sweepAngle += 2 * math.Pi
+	case !sweep && sweepAngle > 0:
+		sweepAngle -= 2 * math.Pi
+	}
+	p.arcCubics(cx, cy, rx, ry, rot, start, sweepAngle, false)
+	// The arc ends exactly at (x, y)
+	if last := &p.segs[len(p.segs)-1]; last.op == 'c' {
+		last.pts[2] = PointType{X: x, Y: y}
+	}
+	p.cur = PointType{X: x, Y: y}
+}
+
+// arcCubics adds the arc centered at (x, y) of radii rx and ry, rotated by
+// rot radians, from the angle start through the angle sweep, as cubic
+// Bézier curves. Angles are measured clockwise, as the vertical axis of the
+// page points down. A straight line joins the current point to the start of
+// the arc if join is true.
+func (p *PathType) arcCubics(x, y, rx, ry, rot, start, sweep float64, join bool) {
+	sinRot, cosRot := math.Sincos(rot)
+	// point returns the point of the arc at the angle t and the derivative
+	// of the arc there
+	point := func(t float64) (pt, d PointType) {
+		sin, cos := math.Sincos(t)
+		pt = PointType{X: x + rx*cos*cosRot - ry*sin*sinRot, Y: y + rx*cos*sinRot + ry*sin*cosRot}
+		d = PointType{X: -rx*sin*cosRot - ry*cos*sinRot, Y: -rx*sin*sinRot + ry*cos*cosRot}
+		return
+	}
+	pt0, d0 := point(start)
+	if join {
+		p.LineTo(pt0.X, pt0.Y)
+	}
+	// Each segment of at most a quarter of the ellipse is a cubic curve
+	// whose control points are along the tangents at its ends
+	segments := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2)))
+	dt := sweep / float64(max(segments, 1))
+	k := 4.0 / 3 * math.Tan(dt/4)
+	for j := 1; j <= segments; j++ {
+		pt1, d1 := point(start + float64(j)*dt)
+		p.CubicTo(pt0.X+k*d0.X, pt0.Y+k*d0.Y, pt1.X-k*d1.X, pt1.Y-k*d1.Y, pt1.X, pt1.Y)
+		pt0, d0 = pt1, d1
+	}
+}
+
+// Close closes the current subpath with a straight line from the current
+// point to the start of the subpath, if not the same, and joins its ends.
+// The start of the subpath becomes the current point.
+func (p *PathType) Close() {
+	if !p.begun {
+		return
+	}
+	p.segs = append(p.segs, pathSegment{op: 'h'})
+	p.cur = p.start
+}
+
+// Rect adds a closed subpath of the rectangle of width w and height h whose
+// upper left corner is at (x, y).
+func (p *PathType) Rect(x, y, w, h float64) {
+	p.MoveTo(x, y)
+	p.LineTo(x+w, y)
+	p.LineTo(x+w, y+h)
+	p.LineTo(x, y+h)
+	p.Close()
+}
+
+// Ellipse adds a closed subpath of the ellipse centered at (x, y) of
+// horizontal and vertical radii rx and ry, drawn counter-clockwise from the
+// 3 o'clock position.
+func (p *PathType) Ellipse(x, y, rx, ry float64) {
+	p.MoveTo(x+rx, y)
+	p.ArcTo(x, y, rx, ry, 0, 0, 360)
+	p.Close()
+}
+
+// Path draws the path, fills it or both, as styleStr specifies: "D" or an
+// empty string for a path outlined with the current draw color, line width
+// and cap and join styles, "F" for a path filled with the current fill
+// color, using the nonzero winding number rule, or "F*" for one filled using
+// the even-odd rule, and "FD" or "FD*" for a path filled and outlined.
+// Subpaths are outlined as they are, open or closed, and filled as if
+// closed. See DrawPath() for other values of styleStr. Outlines have the
+// decorations set with SetLineDecoration(). Nothing is drawn for an empty
+// path.
+func (f *Fpdf) Path(path PathType, styleStr string) {
+	if f.err != nil || len(path.segs) == 0 {
+		return
+	}
+	f.decoratedPath(path, fillDrawOp(styleStr))
+}
+
+// ClipPath begins a clipping operation within the area of the path, as
+// filled using the nonzero winding number rule, or the even-odd rule if
+// evenOdd is true. outline is true to draw the path with the current draw
+// color and line width. Only the half of the outline outside the area will
+// be shown. After calling this method, all rendering operations will be
+// clipped by the path. Call ClipEnd() to restore unclipped operations. Within
+// another clipping operation, the clipping area is the intersection of the
+// path with the area of that operation. ClipRestore() ends several nested
+// clipping operations at once. An empty path clips out everything.
+func (f *Fpdf) ClipPath(path PathType, evenOdd, outline bool) {
+	if f.err != nil {
+		return
+	}
+	if len(path.segs) == 0 {
+		// The clipping path must not be empty
+		path.MoveTo(0, 0)
+		path.Close()
+	}
+	f.clipNest++
+	f.ctmPush()
+	f.outf("q %sW%s %s", f.pathOps(path), strIf(evenOdd, "*", ""), strIf(outline, "S", "n"))
+}
+
+// pathOps returns the operators that construct path in the current page.
+func (f *Fpdf) pathOps(path PathType) string {
+	var s fmtBuffer
+	k, h := f.k, f.h
+	for _, seg := range path.segs {
+		switch seg.op {
+		case 'm', 'l':
+			s.printf("%.5f %.5f %c ", seg.pts[0].X*k, (h-seg.pts[0].Y)*k, seg.op)
+		case 'c':
+			for _, pt := range seg.pts {
+				s.printf("%.5f %.5f ", pt.X*k, (h-pt.Y)*k)
+			}
+			s.printf("c ")
+		case 'h':
+			s.printf("h ")
+		}
+	}
+	return s.String()
+}
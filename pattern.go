@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+)
+
+// patternType is a tiling pattern that repeats an image, or hatch lines if
+// img is nil
+type patternType struct {
+	img       *ImageInfoType
+	wPt, hPt  float64 // tile size in points
+	originYPt float64 // vertical position, in points, of the tile origin
+	hatch     hatchType
+	objNum    int
+}
+
+// hatchType describes the lines of a hatch pattern
+type hatchType struct {
+	angle   float64 // angle of the lines, in degrees counter-clockwise
+	widthPt float64 // width of the lines in points
+	clrStr  string  // stroking color of the lines
+	double  bool    // lines are crossed by perpendicular lines
+}
+
+// SetFillPatternImage sets the current fill color to a tiling pattern that
+// repeats the image imageNameStr. Subsequent fills, such as those of Rect(),
+// Polygon() and the other drawing methods, are painted with the pattern
+// rather than with a solid color. The pattern remains in effect until another
+// fill color is set.
+//
+// imageNameStr may be the name of a registered image or the name of an image
+// file, as with Image(). Each tile is the image at its natural extent, as
+// given by its resolution (see SetDpi() and ImageOptions.ReadDpi), scaled
+// horizontally by scaleX and vertically by scaleY. The tiles are aligned with
+// the upper left corner of the page.
+//
+// Since the image is embedded only once and referenced by the pattern, this
+// is far more compact than placing the image repeatedly.
+func (f *Fpdf) SetFillPatternImage(imageNameStr string, scaleX, scaleY float64) {
+	if f.err != nil {
+		return
+	}
+	if scaleX <= 0 || scaleY <= 0 {
+		f.SetErrorf("invalid pattern image scale %.3f by %.3f", scaleX, scaleY)
+		return
+	}
+	info := f.RegisterImageOptions(imageNameStr, ImageOptions{})
+	if f.err != nil {
+		return
+	}
+	info.placed = true
+	w, h := f.imageExtent(info, -1, -1)
+	pat := patternType{img: info, wPt: w * scaleX * f.k, hPt: h * scaleY * f.k, originYPt: f.h * f.k}
+	f.patternFill(pat)
+}
+
+// SetFillPatternHatch sets the current fill color to a tiling pattern of
+// parallel lines drawn with the current draw color, such as the hatches of
+// sections of engineering drawings or of areas of maps. Subsequent fills are
+// painted with the pattern, as with SetFillPatternImage(), and the area
+// between the lines is left unpainted. The pattern remains in effect until
+// another fill color is set.
+//
+// The lines are at the angle degAngle, in degrees counter-clockwise from the
+// horizontal, and spacing apart, and have the width lineWidth, both in user
+// units. If double is true, the lines are crossed by perpendicular lines of
+// the same spacing. The lines are aligned with the upper left corner of the
+// page, so that adjoining areas filled with the same hatch match.
+func (f *Fpdf) SetFillPatternHatch(degAngle, spacing, lineWidth float64, double bool) {
+	if f.err != nil {
+		return
+	}
+	if spacing <= 0 || lineWidth <= 0 {
+		f.SetErrorf("invalid hatch spacing %.3f or line width %.3f", spacing, lineWidth)
+		return
+	}
+	draw := f.color.draw
+	f.patternFill(patternType{wPt: spacing * f.k, hPt: spacing * f.k, originYPt: f.h * f.k,
+		hatch: hatchType{angle: degAngle, widthPt: lineWidth * f.k, double: double,
+			clrStr: sprintf("%.3f %.3f %.3f RG", draw.r, draw.g, draw.b)}})
+}
+
+// patternFill sets the current fill color to the pattern pat, which is added
+// to the patterns of the document if it is not one of them yet.
+func (f *Fpdf) patternFill(pat patternType) {
+	pos := 0
+	for j := 1; j < len(f.patternList) && pos == 0; j++ {
+		p := f.patternList[j]
+		if p.img == pat.img && p.wPt == pat.wPt && p.hPt == pat.hPt && p.originYPt == pat.originYPt &&
+			p.hatch == pat.hatch {
+			pos = j
+		}
+	}
+	if pos == 0 {
+		pos = len(f.patternList)
+		f.patternList = append(f.patternList, pat)
+	}
+	f.color.fill.mode = colorModePattern
+	f.color.fill.str = sprintf("/Pattern cs /P%d scn", pos)
+	f.colorFlag = f.color.fill.str != f.color.text.str
+	if f.page > 0 {
+		f.out(f.color.fill.str)
+	}
+}
+
+func (f *Fpdf) putPatterns() {
+	for j := 1; j < len(f.patternList); j++ {
+		pat := f.patternList[j]
+		if pat.img == nil {
+			f.putHatchPattern(j)
+			continue
+		}
+		// The image fills the unit square of pattern space, which the pattern
+		// matrix scales to the tile size
+		content := sprintf("/I%s Do", pat.img.i)
+		f.newobj()
+		f.patternList[j].objNum = f.n
+		f.outf("<</Type /Pattern /PatternType 1 /PaintType 1 /TilingType 1")
+		f.out("/BBox [0 0 1 1] /XStep 1 /YStep 1")
+		f.outf("/Matrix [%.5f 0 0 %.5f 0 %.5f]", pat.wPt, pat.hPt, pat.originYPt)
+		f.outf("/Resources <</XObject <</I%s %d 0 R>>>>", pat.img.i, pat.img.n)
+		f.outf("/Length %d>>", len(content))
+		f.putstream([]byte(content))
+		f.out("endobj")
+	}
+}
+
+// putHatchPattern writes the hatch pattern numbered j.
+func (f *Fpdf) putHatchPattern(j int) {
+	pat := f.patternList[j]
+	// The tile is a square of the spacing of the lines, crossed by a line
+	// through its middle, which the pattern matrix rotates
+	s := pat.wPt
+	content := sprintf("%s %.5f w 0 %.5f m %.5f %.5f l S", pat.hatch.clrStr, pat.hatch.widthPt, s/2, s, s/2)
+	if pat.hatch.double {
+		content += sprintf(" %.5f 0 m %.5f %.5f l S", s/2, s/2, s)
+	}
+	sin, cos := math.Sincos(pat.hatch.angle * math.Pi / 180)
+	f.newobj()
+	f.patternList[j].objNum = f.n
+	f.outf("<</Type /Pattern /PatternType 1 /PaintType 1 /TilingType 1")
+	f.outf("/BBox [0 0 %.5f %.5f] /XStep %.5f /YStep %.5f", s, s, s, s)
+	f.outf("/Matrix [%.5f %.5f %.5f %.5f 0 %.5f]", cos, sin, -sin, cos, pat.originYPt)
+	f.out("/Resources <<>>")
+	f.outf("/Length %d>>", len(content))
+	f.putstream([]byte(content))
+	f.out("endobj")
+}
+
+func (f *Fpdf) patternPutResourceDict() {
+	if len(f.patternList) > 1 {
+		f.out("/Pattern <<")
+		for j := 1; j < len(f.patternList); j++ {
+			f.outf("/P%d %d 0 R", j, f.patternList[j].objNum)
+		}
+		f.out(">>")
+	}
+}
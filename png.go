@@ -18,6 +18,7 @@ package gofpdf
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"strings"
 )
@@ -38,7 +39,7 @@ func (f *Fpdf) pngColorSpace(ct byte) (colspace string, colorVal int) {
 	return
 }
 
-func (f *Fpdf) parsepngstream(buf *bytes.Buffer, readdpi bool) (info *ImageInfoType) {
+func (f *Fpdf) parsepngstream(buf *bytes.Buffer, readdpi bool, frame int) (info *ImageInfoType) {
 	info = f.newImageInfo()
 	// 	Check signature
 	if string(buf.Next(8)) != "\x89PNG\x0d\x0a\x1a\x0a" {
@@ -54,8 +55,9 @@ func (f *Fpdf) parsepngstream(buf *bytes.Buffer, readdpi bool) (info *ImageInfoT
 	w := f.readBeInt32(buf)
 	h := f.readBeInt32(buf)
 	bpc := f.readByte(buf)
-	if bpc > 8 {
-		f.err = fmt.Errorf("16-bit depth not supported in PNG file")
+	if bpc != 1 && bpc != 2 && bpc != 4 && bpc != 8 && bpc != 16 {
+		f.err = fmt.Errorf("unsupported bit depth in PNG buffer: %d", bpc)
+		return
 	}
 	ct := f.readByte(buf)
 	var colspace string
@@ -72,16 +74,22 @@ func (f *Fpdf) parsepngstream(buf *bytes.Buffer, readdpi bool) (info *ImageInfoT
 		f.err = fmt.Errorf("'unknown filter method in PNG buffer")
 		return
 	}
-	if f.readByte(buf) != 0 {
-		f.err = fmt.Errorf("interlacing not supported in PNG buffer")
+	interlace := f.readByte(buf)
+	if interlace > 1 {
+		f.err = fmt.Errorf("unknown interlace method in PNG buffer")
 		return
 	}
 	_ = buf.Next(4)
-	dp := sprintf("/Predictor 15 /Colors %d /BitsPerComponent %d /Columns %d", colorVal, bpc, w)
 	// Scan chunks looking for palette, transparency and image data
 	pal := make([]byte, 0, 32)
 	var trns []int
-	data := make([]byte, 0, 32)
+	// Animated PNG (APNG) frames are counted by their fcTL chunks, and by the
+	// IDAT chunks of the default image if it is not part of the animation, in
+	// which case it has no fcTL chunk and comes first. The image data of the
+	// selected frame is gathered in frameData.
+	var frameData []byte
+	frameCount := 0
+	curFrame := -1
 	loop := true
 	for loop {
 		n := int(f.readBeInt32(buf))
@@ -96,11 +104,16 @@ func (f *Fpdf) parsepngstream(buf *bytes.Buffer, readdpi bool) (info *ImageInfoT
 			// dbg("tRNS")
 			// Read transparency info
 			t := buf.Next(n)
+			// 16-bit samples are reduced to their high-order byte below
+			lo := 1
+			if bpc == 16 {
+				lo = 0
+			}
 			switch ct {
 			case 0:
-				trns = []int{int(t[1])} // ord(substr($t,1,1)));
+				trns = []int{int(t[lo])} // ord(substr($t,1,1)));
 			case 2:
-				trns = []int{int(t[1]), int(t[3]), int(t[5])} // array(ord(substr($t,1,1)), ord(substr($t,3,1)), ord(substr($t,5,1)));
+				trns = []int{int(t[lo]), int(t[lo+2]), int(t[lo+4])} // array(ord(substr($t,1,1)), ord(substr($t,3,1)), ord(substr($t,5,1)));
 			default:
 				pos := strings.Index(string(t), "\x00")
 				if pos >= 0 {
@@ -111,7 +124,44 @@ func (f *Fpdf) parsepngstream(buf *bytes.Buffer, readdpi bool) (info *ImageInfoT
 		case "IDAT":
 			// dbg("IDAT")
 			// Read image data block
-			data = append(data, buf.Next(n)...)
+			b := buf.Next(n)
+			if curFrame < 0 {
+				curFrame = frameCount
+				frameCount++
+			}
+			if curFrame == frame {
+				frameData = append(frameData, b...)
+			}
+			_ = buf.Next(4)
+		case "iCCP":
+			// Profile name, compression method and zlib-compressed profile
+			b := buf.Next(n)
+			pos := bytes.IndexByte(b, 0)
+			if pos >= 0 && pos+2 <= len(b) && b[pos+1] == 0 {
+				icc, err := sliceUncompress(b[pos+2:])
+				if err != nil {
+					f.err = fmt.Errorf("invalid ICC profile in PNG buffer: %s", err)
+					return
+				}
+				info.icc = icc
+			}
+			_ = buf.Next(4)
+		case "fcTL":
+			// Frame control: sequence, width, height, x and y offsets, ...
+			curFrame = frameCount
+			frameCount++
+			b := buf.Next(n)
+			if curFrame == frame && len(b) >= 12 {
+				w = int32(binary.BigEndian.Uint32(b[4:8]))
+				h = int32(binary.BigEndian.Uint32(b[8:12]))
+			}
+			_ = buf.Next(4)
+		case "fdAT":
+			// Frame data: sequence number followed by image data
+			b := buf.Next(n)
+			if curFrame == frame && len(b) > 4 {
+				frameData = append(frameData, b[4:]...)
+			}
 			_ = buf.Next(4)
 		case "IEND":
 			// dbg("IEND")
@@ -149,12 +199,34 @@ func (f *Fpdf) parsepngstream(buf *bytes.Buffer, readdpi bool) (info *ImageInfoT
 	if colspace == "Indexed" && len(pal) == 0 {
 		f.err = fmt.Errorf("missing palette in PNG buffer")
 	}
+	if frame < 0 || frame >= frameCount {
+		f.err = fmt.Errorf("frame %d not found in PNG buffer", frame)
+		return
+	}
+	data := frameData
+	if interlace == 1 || bpc == 16 {
+		var err error
+		data, err = sliceUncompress(data)
+		if err == nil && interlace == 1 {
+			// Convert Adam7 passes to sequential scanlines
+			data, err = pngDeinterlace(data, int(w), int(h), int(bpc)*pngChannels(ct))
+		}
+		if err == nil && bpc == 16 {
+			data, err = pngReduce16(data, int(w), int(h), pngChannels(ct))
+			bpc = 8
+		}
+		if err != nil {
+			f.err = err
+			return
+		}
+		data = sliceCompress(data)
+	}
 	info.w = float64(w)
 	info.h = float64(h)
 	info.cs = colspace
 	info.bpc = int(bpc)
 	info.f = "FlateDecode"
-	info.dp = dp
+	info.dp = sprintf("/Predictor 15 /Colors %d /BitsPerComponent %d /Columns %d", colorVal, bpc, w)
 	info.pal = pal
 	info.trns = trns
 	// dbg("ct [%d]", ct)
@@ -211,3 +283,150 @@ func (f *Fpdf) parsepngstream(buf *bytes.Buffer, readdpi bool) (info *ImageInfoT
 	info.data = data
 	return
 }
+
+// pngChannels returns the number of samples per pixel for PNG color type ct
+func pngChannels(ct byte) int {
+	switch ct {
+	case 2:
+		return 3
+	case 4:
+		return 2
+	case 6:
+		return 4
+	}
+	return 1
+}
+
+// pngUnfilter reverses, in place, the PNG filter ft applied to the scanline
+// cur. prev is the reconstructed previous scanline, or zeros for the first
+// scanline. bpp is the number of bytes per complete pixel, rounded up to one.
+func pngUnfilter(ft byte, cur, prev []byte, bpp int) error {
+	switch ft {
+	case 0:
+	case 1: // Sub
+		for j := bpp; j < len(cur); j++ {
+			cur[j] += cur[j-bpp]
+		}
+	case 2: // Up
+		for j := range cur {
+			cur[j] += prev[j]
+		}
+	case 3: // Average
+		for j := range cur {
+			var left int
+			if j >= bpp {
+				left = int(cur[j-bpp])
+			}
+			cur[j] += byte((left + int(prev[j])) / 2)
+		}
+	case 4: // Paeth
+		for j := range cur {
+			var a, c int
+			if j >= bpp {
+				a = int(cur[j-bpp])
+				c = int(prev[j-bpp])
+			}
+			b := int(prev[j])
+			p := a + b - c
+			pa, pb, pc := p-a, p-b, p-c
+			if pa < 0 {
+				pa = -pa
+			}
+			if pb < 0 {
+				pb = -pb
+			}
+			if pc < 0 {
+				pc = -pc
+			}
+			if pa <= pb && pa <= pc {
+				cur[j] += byte(a)
+			} else if pb <= pc {
+				cur[j] += byte(b)
+			} else {
+				cur[j] += byte(c)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown filter type in PNG image data: %d", ft)
+	}
+	return nil
+}
+
+// adam7Passes lists the x offset, y offset, x step and y step of each pass of
+// an Adam7-interlaced PNG image
+var adam7Passes = [7][4]int{
+	{0, 0, 8, 8}, {4, 0, 8, 8}, {0, 4, 4, 8}, {2, 0, 4, 4},
+	{0, 2, 2, 4}, {1, 0, 2, 2}, {0, 1, 1, 2},
+}
+
+// pngDeinterlace converts the inflated image data of an Adam7-interlaced PNG
+// image with the specified dimensions and bits per pixel into sequential
+// scanlines, each of which is prefixed with filter type 0 (none).
+func pngDeinterlace(data []byte, w, h, bitsPP int) (out []byte, err error) {
+	bpp := (bitsPP + 7) / 8
+	rowLen := (w*bitsPP + 7) / 8
+	out = make([]byte, (1+rowLen)*h)
+	mask := byte(1<<uint(bitsPP) - 1)
+	pos := 0
+	for _, pass := range adam7Passes {
+		x0, y0, dx, dy := pass[0], pass[1], pass[2], pass[3]
+		pw := (w - x0 + dx - 1) / dx
+		ph := (h - y0 + dy - 1) / dy
+		if pw <= 0 || ph <= 0 {
+			continue
+		}
+		passLen := (pw*bitsPP + 7) / 8
+		prev := make([]byte, passLen)
+		for j := 0; j < ph; j++ {
+			if pos+1+passLen > len(data) {
+				return nil, fmt.Errorf("truncated interlaced PNG image data")
+			}
+			cur := data[pos+1 : pos+1+passLen]
+			if err = pngUnfilter(data[pos], cur, prev, bpp); err != nil {
+				return
+			}
+			pos += 1 + passLen
+			row := out[(y0+j*dy)*(1+rowLen)+1:]
+			for k := 0; k < pw; k++ {
+				x := x0 + k*dx
+				if bitsPP >= 8 {
+					copy(row[x*bpp:x*bpp+bpp], cur[k*bpp:k*bpp+bpp])
+				} else {
+					src := k * bitsPP
+					dst := x * bitsPP
+					v := (cur[src/8] >> uint(8-bitsPP-src%8)) & mask
+					row[dst/8] |= v << uint(8-bitsPP-dst%8)
+				}
+			}
+			prev = cur
+		}
+	}
+	return
+}
+
+// pngReduce16 converts the inflated, non-interlaced image data of a PNG image
+// with 16-bit samples to 8-bit samples by retaining the high-order byte of
+// each sample. The returned scanlines are each prefixed with filter type 0
+// (none).
+func pngReduce16(data []byte, w, h, channels int) (out []byte, err error) {
+	bpp := 2 * channels
+	rowLen := w * bpp
+	if len(data) < (1+rowLen)*h {
+		return nil, fmt.Errorf("truncated 16-bit PNG image data")
+	}
+	out = make([]byte, 0, (1+rowLen/2)*h)
+	prev := make([]byte, rowLen)
+	for j := 0; j < h; j++ {
+		pos := j * (1 + rowLen)
+		cur := data[pos+1 : pos+1+rowLen]
+		if err = pngUnfilter(data[pos], cur, prev, bpp); err != nil {
+			return
+		}
+		out = append(out, 0)
+		for k := 0; k < rowLen; k += 2 {
+			out = append(out, cur[k])
+		}
+		prev = cur
+	}
+	return
+}
@@ -20,9 +20,11 @@
 package gofpdf
 
 import (
+	"crypto/cipher"
 	"crypto/md5"
 	"crypto/rc4"
 	"encoding/binary"
+	"io"
 	"math/rand"
 )
 
@@ -54,6 +56,13 @@ func (p *protectType) rc4(n uint32, buf *[]byte) {
 	p.rc4cipher.XORKeyStream(*buf, *buf)
 }
 
+// rc4Reader returns a reader that encrypts the data read from r as the
+// stream of object n.
+func (p *protectType) rc4Reader(n uint32, r io.Reader) io.Reader {
+	c, _ := rc4.NewCipher(p.objectKey(n))
+	return cipher.StreamReader{S: c, R: r}
+}
+
 func (p *protectType) objectKey(n uint32) []byte {
 	var nbuf, b []byte
 	nbuf = make([]byte, 8, 8)
@@ -0,0 +1,360 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"math"
+	"strings"
+)
+
+// downsampleImage replaces the image registered as fileStr with a resampled
+// copy if its effective resolution, when placed with the width and height w
+// and h (in user units), exceeds options.MaxDPI. The copy is resampled from
+// the samples of the registered image, channel by channel in its own color
+// space, rather than from the file. JPEG images in gray scale or RGB are
+// re-encoded as JPEG; all others are compressed as FlateDecode samples.
+// Images whose samples cannot be recovered, such as JPEG 2000 and bilevel
+// images, are left as they are.
+func (f *Fpdf) downsampleImage(fileStr string, info *ImageInfoType, w, h float64, options ImageOptions) *ImageInfoType {
+	iw, _ := info.size()
+	inches := w * f.k / 72
+	if inches <= 0 || iw/inches <= options.MaxDPI {
+		return info
+	}
+	scale := options.MaxDPI * inches / iw
+	tw := int(math.Max(1, math.Round(info.w*scale)))
+	th := int(math.Max(1, math.Round(info.h*scale)))
+	src, ok := imageSamplesOf(info)
+	if !ok {
+		return info
+	}
+	var alpha *imageSamples
+	if len(info.smask) > 0 {
+		mask := &ImageInfoType{w: info.w, h: info.h, cs: "DeviceGray", bpc: 8, f: "FlateDecode",
+			dp: "/Predictor 15", data: info.smask}
+		a, ok := imageSamplesOf(mask)
+		if !ok {
+			return info
+		}
+		alpha = &a
+	}
+	// Palette indices and color key masked samples cannot be averaged
+	nearest := info.cs == "Indexed" || len(info.trns) > 0
+	dst := src.resample(tw, th, alpha, nearest)
+	var rs *ImageInfoType
+	if info.f == "DCTDecode" && dst.n != 4 {
+		quality := 90
+		if options.Recompress.Quality > 0 {
+			quality = options.Recompress.Quality
+		}
+		var buf bytes.Buffer
+		if f.err = jpeg.Encode(&buf, dst.image(), &jpeg.Options{Quality: quality}); f.err != nil {
+			return info
+		}
+		if rs = f.parsejpg(&buf); f.err != nil {
+			return info
+		}
+	} else {
+		rs = f.newImageInfo()
+		rs.w, rs.h = float64(tw), float64(th)
+		rs.cs = info.cs
+		rs.bpc = 8
+		rs.f = "FlateDecode"
+		rs.dp = sprintf("/Predictor 15 /Colors %d /BitsPerComponent 8 /Columns %d", dst.n, tw)
+		rs.data = dst.compress()
+		rs.pal = info.pal
+		rs.trns = info.trns
+		if info.f != "DCTDecode" {
+			// The samples of a JPEG image are decoded as they are meant to
+			// be seen, so that its decode array no longer applies
+			rs.dec = info.dec
+		}
+		if alpha != nil {
+			rs.smask = alpha.resample(tw, th, nil, false).compress()
+		}
+	}
+	// The copy keeps the natural size of the image
+	ratio := float64(tw) / info.w
+	rs.dpi = info.dpi * ratio
+	rs.rsRatio = ratio
+	if info.rsRatio > 0 {
+		rs.rsRatio *= info.rsRatio
+	}
+	rs.ori = info.ori
+	rs.icc = info.icc
+	if rs.i, f.err = generateImageID(rs); f.err != nil {
+		return info
+	}
+	if info.mask != nil {
+		rs.mask = info.mask
+		rs.smask, rs.trns = nil, nil
+		rs.i = attachedImageID(rs.i, "mask:"+rs.mask.i)
+	}
+	for _, alt := range info.alts {
+		rs.alts = append(rs.alts, alt)
+		rs.i = attachedImageID(rs.i, sprintf("alt:%s:%v", alt.img.i, alt.print))
+	}
+	f.images[fileStr] = rs
+	return rs
+}
+
+// imageSamples holds the samples of an image, one byte per sample and n
+// samples per pixel, row by row.
+type imageSamples struct {
+	w, h, n int
+	pix     []byte
+}
+
+// imageSamplesOf returns the samples of the color data of info. Samples of
+// fewer than 8 bits are widened to 8 bits; palette indices are kept as they
+// are. False is returned if the samples cannot be recovered from the
+// encoding of the image.
+func imageSamplesOf(info *ImageInfoType) (s imageSamples, ok bool) {
+	if info.stencil || info.open != nil {
+		return
+	}
+	s.w, s.h = int(info.w), int(info.h)
+	switch info.f {
+	case "DCTDecode":
+		data := info.data
+		if info.src != nil {
+			var err error
+			if data, err = info.sourceData(); err != nil {
+				return
+			}
+		}
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		switch img := img.(type) {
+		case *image.Gray:
+			s.n = 1
+			s.pix = make([]byte, 0, s.w*s.h)
+			for y := 0; y < s.h; y++ {
+				s.pix = append(s.pix, img.Pix[y*img.Stride:y*img.Stride+s.w]...)
+			}
+		case *image.CMYK:
+			s.n = 4
+			s.pix = make([]byte, 0, 4*s.w*s.h)
+			for y := 0; y < s.h; y++ {
+				s.pix = append(s.pix, img.Pix[y*img.Stride:y*img.Stride+4*s.w]...)
+			}
+		default:
+			s.n = 3
+			s.pix = make([]byte, 0, 3*s.w*s.h)
+			b := img.Bounds()
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X; x++ {
+					c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+					s.pix = append(s.pix, c.R, c.G, c.B)
+				}
+			}
+		}
+		return s, true
+	case "FlateDecode":
+	default:
+		return
+	}
+	switch info.cs {
+	case "DeviceGray", "Indexed":
+		s.n = 1
+	case "DeviceRGB":
+		s.n = 3
+	case "DeviceCMYK":
+		s.n = 4
+	default:
+		return
+	}
+	switch info.bpc {
+	case 1, 2, 4, 8:
+	default:
+		return
+	}
+	data, err := sliceUncompress(info.data)
+	if err != nil {
+		return
+	}
+	rowLen := (s.w*s.n*info.bpc + 7) / 8
+	predicted := strings.Contains(info.dp, "/Predictor")
+	if predicted {
+		rowLen++
+	}
+	if len(data) < rowLen*s.h {
+		return
+	}
+	bpp := s.n * info.bpc / 8
+	if bpp < 1 {
+		bpp = 1
+	}
+	max := 1<<uint(info.bpc) - 1
+	s.pix = make([]byte, 0, s.w*s.n*s.h)
+	prev := make([]byte, rowLen)
+	for y := 0; y < s.h; y++ {
+		row := data[y*rowLen : (y+1)*rowLen]
+		if predicted {
+			if pngUnfilter(row[0], row[1:], prev[1:], bpp) != nil {
+				return s, false
+			}
+			prev = row
+			row = row[1:]
+		}
+		if info.bpc == 8 {
+			s.pix = append(s.pix, row[:s.w*s.n]...)
+			continue
+		}
+		for j := 0; j < s.w*s.n; j++ {
+			bit := j * info.bpc
+			v := int(row[bit/8]>>uint(8-info.bpc-bit%8)) & max
+			if info.cs != "Indexed" {
+				v = v * 255 / max
+			}
+			s.pix = append(s.pix, byte(v))
+		}
+	}
+	return s, true
+}
+
+// resample returns the samples scaled to w by h pixels. Each sample of a
+// destination pixel is the average of the samples of the source pixels that
+// fall within it, weighted by the alpha samples of those pixels if alpha is
+// not nil, or, if nearest is true, the sample of the source pixel at its
+// center.
+func (s imageSamples) resample(w, h int, alpha *imageSamples, nearest bool) imageSamples {
+	dst := imageSamples{w: w, h: h, n: s.n, pix: make([]byte, w*h*s.n)}
+	sum := make([]uint64, s.n)
+	for y := 0; y < h; y++ {
+		y0 := y * s.h / h
+		y1 := (y + 1) * s.h / h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < w; x++ {
+			x0 := x * s.w / w
+			x1 := (x + 1) * s.w / w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			out := dst.pix[(y*w+x)*s.n : (y*w+x+1)*s.n]
+			if nearest {
+				pos := (((y0+y1)/2)*s.w + (x0+x1)/2) * s.n
+				copy(out, s.pix[pos:pos+s.n])
+				continue
+			}
+			for c := range sum {
+				sum[c] = 0
+			}
+			var weight uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					// Weight samples by alpha so transparent pixels do not bleed
+					a := uint64(1)
+					if alpha != nil {
+						a = uint64(alpha.pix[sy*s.w+sx])
+					}
+					pos := (sy*s.w + sx) * s.n
+					for c := range sum {
+						sum[c] += uint64(s.pix[pos+c]) * a
+					}
+					weight += a
+				}
+			}
+			if weight > 0 {
+				for c := range sum {
+					out[c] = uint8(sum[c] / weight)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// compress returns the samples compressed as unfiltered PNG scanlines, the
+// form in which FlateDecode images and soft masks are written.
+func (s imageSamples) compress() []byte {
+	rowLen := s.w * s.n
+	data := make([]byte, 0, (1+rowLen)*s.h)
+	for y := 0; y < s.h; y++ {
+		data = append(data, 0)
+		data = append(data, s.pix[y*rowLen:(y+1)*rowLen]...)
+	}
+	return sliceCompress(data)
+}
+
+// image returns the samples of a gray scale or RGB image as an image.Image.
+func (s imageSamples) image() image.Image {
+	r := image.Rect(0, 0, s.w, s.h)
+	if s.n == 1 {
+		return &image.Gray{Pix: s.pix, Stride: s.w, Rect: r}
+	}
+	img := image.NewRGBA(r)
+	for j := 0; j < s.w*s.h; j++ {
+		copy(img.Pix[4*j:], s.pix[3*j:3*j+3])
+		img.Pix[4*j+3] = 255
+	}
+	return img
+}
+
+// recompressImage returns the image info of img re-encoded as specified by
+// rc. The resolution, orientation and color profile of info are retained,
+// except that the profile of a CMYK image does not apply to its RGB rendition.
+func (f *Fpdf) recompressImage(info *ImageInfoType, img image.Image, rc ImageRecompressType) *ImageInfoType {
+	switch strings.ToLower(rc.ImageType) {
+	case "jpg", "jpeg":
+	default:
+		f.err = fmt.Errorf("unsupported recompression image type: %s", rc.ImageType)
+		return info
+	}
+	quality := rc.Quality
+	if quality == 0 {
+		quality = jpeg.DefaultQuality
+	}
+	if quality < 1 || quality > 100 {
+		f.err = fmt.Errorf("JPEG quality must be between 1 and 100, got %d", quality)
+		return info
+	}
+	var dst draw.Image
+	b := img.Bounds()
+	if info.cs == "DeviceGray" {
+		dst = image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	}
+	// JPEG has no alpha channel, so the image is composited over white
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	var buf bytes.Buffer
+	if f.err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: quality}); f.err != nil {
+		return info
+	}
+	rs := f.parsejpg(&buf)
+	if f.err != nil {
+		return info
+	}
+	rs.dpi = info.dpi
+	rs.ori = info.ori
+	if info.cs != "DeviceCMYK" {
+		rs.icc = info.icc
+	}
+	return rs
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+)
+
+// ShadowType describes the drop shadow cast by the content drawn between
+// BeginShadow() and EndShadow().
+//
+// OffsetX and OffsetY are the distances, in the unit of measure specified in
+// New(), by which the shadow is shifted right and down from the content. Blur
+// is the distance over which the edge of the shadow fades out, half of it
+// inside of the outline of the content and half outside, or 0 for a sharp
+// shadow. Color is the color of the shadow, and Alpha its opacity, from 0.0
+// (invisible) to 1.0 (fully opaque), where it is not faded by its edge.
+type ShadowType struct {
+	OffsetX, OffsetY float64
+	Blur             float64
+	Color            RGBType
+	Alpha            float64
+}
+
+// BeginShadow begins the content that casts the drop shadow shadow: the
+// rectangles, paths, text and images that follow, up to the call of
+// EndShadow(), are painted on the page over a shadow of their shape, such as
+// that of a card or a caption lifted above the page.
+//
+// The content is collected in a transparency group, as with
+// BeginTransparencyGroup(), that is both painted and used as a mask for the
+// shadow, so that its shape is not drawn over again. The blur is approximated
+// by layers of the shadow, spread around its position and composited so that
+// they add up to the alpha of the shadow where they all overlap. Within the
+// content, drawing begins fully opaque with the "Normal" blend mode, and the
+// content and its shadow are blended with the page with the alpha and blend
+// mode in effect when this method is called.
+//
+// Shadows lie on a single page and can be nested, and can hold transparency
+// groups and soft masks. The document cannot be successfully output while a
+// shadow is active.
+func (f *Fpdf) BeginShadow(shadow ShadowType) {
+	if f.err != nil {
+		return
+	}
+	if shadow.Alpha < 0 || shadow.Alpha > 1 {
+		f.SetErrorf("shadow alpha value (0.0 - 1.0) is out of range: %.3f", shadow.Alpha)
+		return
+	}
+	if shadow.Blur < 0 {
+		f.SetErrorf("invalid shadow blur %.3f", shadow.Blur)
+		return
+	}
+	f.groupBegin(transparencyGroupNest{isolated: true, kindStr: "shadow", shadow: shadow})
+}
+
+// EndShadow ends the content begun by the last call of BeginShadow() and
+// paints its shadow, then the content itself, on the page. The alpha and
+// blend mode in effect when the shadow was begun are restored, while the
+// other parameters set within it, such as the colors, the line width and
+// the font, remain in effect after it.
+//
+// The BeginShadow() example demonstrates this method.
+func (f *Fpdf) EndShadow() {
+	var shadow ShadowType
+	if count := len(f.groupNest); count > 0 {
+		shadow = f.groupNest[count-1].shadow
+	}
+	pos := f.groupEnd("shadow")
+	if pos == 0 {
+		return
+	}
+	layers := shadowLayers(shadow.Blur)
+	f.softMaskList = append(f.softMaskList, softMaskType{group: pos, alpha: true})
+	maskPos := len(f.softMaskList) - 1
+	// Layers of alpha a, all of which cover the middle of the shadow, add up
+	// to the alpha of the shadow there: 1 - (1 - a)^n = alpha
+	alpha := shadow.Alpha * f.alpha
+	layerAlpha := 1 - math.Pow(1-alpha, 1/float64(len(layers)))
+	alphaPos := f.blendStatePos(layerAlpha, layerAlpha, f.blendMode)
+	clr := rgbColorValue(shadow.Color.R, shadow.Color.G, shadow.Color.B, "g", "rg")
+	f.outf("q %s", clr.str)
+	for _, pt := range layers {
+		// The mask is in the coordinates in effect when it is set, which are
+		// shifted along with the shadow
+		f.outf("q 1 0 0 1 %.2f %.2f cm /SM%d gs /GS%d gs 0 0 %.2f %.2f re f Q",
+			(shadow.OffsetX+pt.X)*f.k, (0-shadow.OffsetY-pt.Y)*f.k, maskPos, alphaPos, f.wPt, f.hPt)
+	}
+	f.out("Q")
+	f.outf("/TG%d Do", pos)
+	f.groupStatePut()
+}
+
+// shadowLayers returns the offsets of the layers of a shadow whose edge fades
+// out over the distance blur: one in the middle, for a sharp shadow, and rings
+// around it half as far and as far as half of blur otherwise.
+func shadowLayers(blur float64) []PointType {
+	list := []PointType{{}}
+	if blur <= 0 {
+		return list
+	}
+	for _, ring := range []struct {
+		r     float64
+		count int
+	}{{blur / 4, 8}, {blur / 2, 12}} {
+		for j := 0; j < ring.count; j++ {
+			sin, cos := math.Sincos(2 * math.Pi * float64(j) / float64(ring.count))
+			list = append(list, PointType{X: ring.r * cos, Y: ring.r * sin})
+		}
+	}
+	return list
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+)
+
+// RegularPolygon draws a regular polygon of sides sides, at least 3, centered
+// at point (cx, cy), whose vertices are on the circle of radius r. The first
+// vertex is at the 12 o'clock position, from which the polygon is rotated
+// counter-clockwise by degRotate degrees, so that a square stands on a corner
+// unless rotated by 45 degrees. See Path() for styleStr.
+func (f *Fpdf) RegularPolygon(cx, cy, r float64, sides int, degRotate float64, styleStr string) {
+	if f.err != nil {
+		return
+	}
+	if sides < 3 {
+		f.SetErrorf("regular polygon of %d sides, at least 3 are needed", sides)
+		return
+	}
+	var path PathType
+	path.RegularPolygon(cx, cy, r, sides, degRotate)
+	f.Path(path, styleStr)
+}
+
+// Star draws a star of points points, at least 2, centered at point (cx, cy),
+// such as those of ratings and badges. The tips of the points are on the
+// circle of radius rOuter, with the first one at the 12 o'clock position, and
+// the inner corners between them on the circle of radius rInner. A five
+// pointed star whose sides are aligned two by two has an inner radius of
+// about 0.382 times the outer one. See Path() for styleStr.
+func (f *Fpdf) Star(cx, cy, rOuter, rInner float64, points int, styleStr string) {
+	if f.err != nil {
+		return
+	}
+	if points < 2 {
+		f.SetErrorf("star of %d points, at least 2 are needed", points)
+		return
+	}
+	var path PathType
+	path.Star(cx, cy, rOuter, rInner, points)
+	f.Path(path, styleStr)
+}
+
+// RegularPolygon adds a closed subpath of the regular polygon drawn by
+// RegularPolygon() of Fpdf, drawn counter-clockwise from its first vertex.
+func (p *PathType) RegularPolygon(cx, cy, r float64, sides int, degRotate float64) {
+	if sides < 3 {
+		return
+	}
+	p.Polygon(radialPoints(cx, cy, []float64{r}, sides, degRotate))
+}
+
+// Star adds a closed subpath of the star drawn by Star() of Fpdf, drawn
+// counter-clockwise from the tip of its first point.
+func (p *PathType) Star(cx, cy, rOuter, rInner float64, points int) {
+	if points < 2 {
+		return
+	}
+	p.Polygon(radialPoints(cx, cy, []float64{rOuter, rInner}, 2*points, 0))
+}
+
+// radialPoints returns count points evenly spaced around (cx, cy), from the
+// 12 o'clock position rotated counter-clockwise by degRotate degrees, at the
+// distances radii from the center in turn.
+func radialPoints(cx, cy float64, radii []float64, count int, degRotate float64) []PointType {
+	pts := make([]PointType, count)
+	for j := range pts {
+		sin, cos := math.Sincos((90 + degRotate + 360*float64(j)/float64(count)) * math.Pi / 180)
+		r := radii[j%len(radii)]
+		pts[j] = PointType{X: cx + r*cos, Y: cy - r*sin}
+	}
+	return pts
+}
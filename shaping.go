@@ -0,0 +1,610 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetTextShaping enables or disables the shaping of Arabic, Devanagari and
+// Thai text, which is disabled by default. Shaping applies to text printed
+// with a UTF-8 font.
+//
+// When enabled, each Arabic letter is replaced by its isolated, initial,
+// medial or final form, according to the letters that it joins, and lam
+// followed by alef replaced by their ligature. The forms are those of the
+// Arabic Presentation Forms blocks of Unicode, and a letter is left unchanged
+// if neither the current font nor its fallbacks have the form. Combining marks
+// such as vowel signs do not interrupt joining.
+//
+// Devanagari and Thai text is shaped with the glyph substitution (GSUB) table
+// of the current font. Devanagari syllables take the conjuncts, half forms,
+// below-base forms and reph of the font, and the short i vowel sign is moved
+// before its consonants. Thai sara am is decomposed into nikhahit and sara
+// aa, as in its compatibility decomposition, and the contextual forms of Thai marks are substituted. Glyphs that no
+// character maps to are assigned codes of the Private Use Area, as ligatures
+// are; text extracted from the document maps them back to their characters,
+// though in the order of the glyphs. Glyph positioning (GPOS) tables are not
+// applied, so marks are drawn where their glyphs place them. Text that the
+// current font cannot print in full is left to its fallbacks unshaped.
+//
+// Text is shaped in logical order, before it is reversed in right-to-left
+// mode.
+func (f *Fpdf) SetTextShaping(enabled bool) {
+	f.textShaping = enabled
+}
+
+// Joining types of Arabic characters
+const (
+	joinNone        = iota // Does not join
+	joinRight              // Joins the preceding character only
+	joinDual               // Joins the preceding and the following character
+	joinCausing            // Joins both sides without changing form, like tatweel
+	joinTransparent        // Combining mark that joining skips
+)
+
+// arabicForms holds the isolated, final, initial and medial forms of the
+// Arabic letters that have presentation forms. Right-joining letters have no
+// initial or medial forms.
+var arabicForms = map[rune][4]rune{
+	0x0621: {0xFE80},
+	0x0622: {0xFE81, 0xFE82},
+	0x0623: {0xFE83, 0xFE84},
+	0x0624: {0xFE85, 0xFE86},
+	0x0625: {0xFE87, 0xFE88},
+	0x0626: {0xFE89, 0xFE8A, 0xFE8B, 0xFE8C},
+	0x0627: {0xFE8D, 0xFE8E},
+	0x0628: {0xFE8F, 0xFE90, 0xFE91, 0xFE92},
+	0x0629: {0xFE93, 0xFE94},
+	0x062A: {0xFE95, 0xFE96, 0xFE97, 0xFE98},
+	0x062B: {0xFE99, 0xFE9A, 0xFE9B, 0xFE9C},
+	0x062C: {0xFE9D, 0xFE9E, 0xFE9F, 0xFEA0},
+	0x062D: {0xFEA1, 0xFEA2, 0xFEA3, 0xFEA4},
+	0x062E: {0xFEA5, 0xFEA6, 0xFEA7, 0xFEA8},
+	0x062F: {0xFEA9, 0xFEAA},
+	0x0630: {0xFEAB, 0xFEAC},
+	0x0631: {0xFEAD, 0xFEAE},
+	0x0632: {0xFEAF, 0xFEB0},
+	0x0633: {0xFEB1, 0xFEB2, 0xFEB3, 0xFEB4},
+	0x0634: {0xFEB5, 0xFEB6, 0xFEB7, 0xFEB8},
+	0x0635: {0xFEB9, 0xFEBA, 0xFEBB, 0xFEBC},
+	0x0636: {0xFEBD, 0xFEBE, 0xFEBF, 0xFEC0},
+	0x0637: {0xFEC1, 0xFEC2, 0xFEC3, 0xFEC4},
+	0x0638: {0xFEC5, 0xFEC6, 0xFEC7, 0xFEC8},
+	0x0639: {0xFEC9, 0xFECA, 0xFECB, 0xFECC},
+	0x063A: {0xFECD, 0xFECE, 0xFECF, 0xFED0},
+	0x0641: {0xFED1, 0xFED2, 0xFED3, 0xFED4},
+	0x0642: {0xFED5, 0xFED6, 0xFED7, 0xFED8},
+	0x0643: {0xFED9, 0xFEDA, 0xFEDB, 0xFEDC},
+	0x0644: {0xFEDD, 0xFEDE, 0xFEDF, 0xFEE0},
+	0x0645: {0xFEE1, 0xFEE2, 0xFEE3, 0xFEE4},
+	0x0646: {0xFEE5, 0xFEE6, 0xFEE7, 0xFEE8},
+	0x0647: {0xFEE9, 0xFEEA, 0xFEEB, 0xFEEC},
+	0x0648: {0xFEED, 0xFEEE},
+	0x0649: {0xFEEF, 0xFEF0, 0xFBE8, 0xFBE9},
+	0x064A: {0xFEF1, 0xFEF2, 0xFEF3, 0xFEF4},
+	0x067E: {0xFB56, 0xFB57, 0xFB58, 0xFB59},
+	0x0686: {0xFB7A, 0xFB7B, 0xFB7C, 0xFB7D},
+	0x0698: {0xFB8A, 0xFB8B},
+	0x06A9: {0xFB8E, 0xFB8F, 0xFB90, 0xFB91},
+	0x06AF: {0xFB92, 0xFB93, 0xFB94, 0xFB95},
+	0x06CC: {0xFBFC, 0xFBFD, 0xFBFE, 0xFBFF},
+}
+
+// lamAlefForms holds the isolated and final forms of the ligatures of lam with
+// the alef variants.
+var lamAlefForms = map[rune][2]rune{
+	0x0622: {0xFEF5, 0xFEF6},
+	0x0623: {0xFEF7, 0xFEF8},
+	0x0625: {0xFEF9, 0xFEFA},
+	0x0627: {0xFEFB, 0xFEFC},
+}
+
+func arabicJoining(r rune) int {
+	switch {
+	case r == 0x0640 || r == 0x200D:
+		return joinCausing
+	case r >= 0x064B && r <= 0x065F, r == 0x0670, r >= 0x06D6 && r <= 0x06DC,
+		r >= 0x06DF && r <= 0x06E4, r == 0x06E7, r == 0x06E8, r >= 0x06EA && r <= 0x06ED:
+		return joinTransparent
+	}
+	forms, ok := arabicForms[r]
+	switch {
+	case !ok || forms[1] == 0:
+		return joinNone
+	case forms[2] == 0:
+		return joinRight
+	}
+	return joinDual
+}
+
+// shapeText returns txtStr with the text transform applied, its Arabic,
+// Devanagari and Thai text shaped and the ligatures of the current font, if enabled,
+// substituted for their characters. Text that needs none of them is returned
+// as is.
+func (f *Fpdf) shapeText(txtStr string) string {
+	txtStr = f.transformText(txtStr)
+	if !f.isCurrentUTF8 {
+		return txtStr
+	}
+	arabic, complex := false, false
+	if f.textShaping {
+		for _, r := range txtStr {
+			switch {
+			case r >= 0x0621 && r <= 0x06CC:
+				arabic = true
+			case r >= 0x0900 && r <= 0x097F, isThai(r):
+				complex = true
+			}
+		}
+	}
+	if arabic {
+		cw := f.charWidths()
+		txtStr = string(shapeArabic([]rune(txtStr), func(r rune) bool {
+			return int(r) < len(cw) && cw[r] > 0
+		}))
+	}
+	if complex {
+		txtStr = string(f.shapeComplex([]rune(txtStr)))
+	}
+	if ft := f.currentFont.features; ft != nil && ft.ligatures && len(ft.ligatureLookups) > 0 {
+		txtStr = string(ft.substituteLigatures([]rune(txtStr)))
+	}
+	return txtStr
+}
+
+// shapeArabic replaces the Arabic letters in runes by the presentation forms
+// that has() reports to be available.
+func shapeArabic(runes []rune, has func(rune) bool) []rune {
+	types := make([]int, len(runes))
+	for j, r := range runes {
+		types[j] = arabicJoining(r)
+	}
+	// neighbor returns the index of the nearest character in the direction
+	// step that is not transparent, or -1 if there is none
+	neighbor := func(j, step int) int {
+		for j += step; j >= 0 && j < len(runes); j += step {
+			if types[j] != joinTransparent {
+				return j
+			}
+		}
+		return -1
+	}
+	out := make([]rune, 0, len(runes))
+	for j := 0; j < len(runes); j++ {
+		r := runes[j]
+		if types[j] != joinDual && types[j] != joinRight {
+			out = append(out, r)
+			continue
+		}
+		prev, next := neighbor(j, -1), neighbor(j, 1)
+		joinsPrev := prev >= 0 && (types[prev] == joinDual || types[prev] == joinCausing)
+		joinsNext := types[j] == joinDual && next >= 0 && types[next] != joinNone
+		if r == 0x0644 && next >= 0 {
+			if lig, ok := lamAlefForms[runes[next]]; ok {
+				form := lig[0]
+				if joinsPrev {
+					form = lig[1]
+				}
+				if has(form) {
+					// Marks between lam and alef follow the ligature
+					out = append(out, form)
+					out = append(out, runes[j+1:next]...)
+					j = next
+					continue
+				}
+			}
+		}
+		forms := arabicForms[r]
+		var form rune
+		switch {
+		case joinsPrev && joinsNext:
+			form = forms[3]
+		case joinsPrev:
+			form = forms[1]
+		case joinsNext:
+			form = forms[2]
+		default:
+			form = forms[0]
+		}
+		if form == 0 || !has(form) {
+			form = r
+		}
+		out = append(out, form)
+	}
+	return out
+}
+
+// shapeComplex returns runes with their Devanagari and Thai text shaped with
+// the current font.
+func (f *Fpdf) shapeComplex(runes []rune) []rune {
+	font := f.currentFont
+	ft := font.features
+	if ft == nil {
+		return runes
+	}
+	if ft.shaper == nil {
+		if err := ft.loadShaper(font); err != nil {
+			f.SetError(err)
+			return runes
+		}
+	}
+	n := len(font.utf8File.assignedCodes)
+	runes = ft.shaper.shape(font, runes)
+	if len(font.utf8File.assignedCodes) != n {
+		// The widths of the font merged with its fallbacks lack the new glyphs
+		f.fallbackWidths = nil
+	}
+	return runes
+}
+
+// Features that apply to the glyphs of a Devanagari syllable
+const (
+	shapeGlobal uint = 1 << iota // Every glyph
+	shapeReph                    // Ra and virama that form the reph
+	shapeHalf                    // Consonants and viramas before the base consonant
+	shapeBelow                   // Glyphs after the base consonant
+)
+
+// devanagariFeatures are the features that form the conjuncts and the
+// consonant forms of Devanagari syllables, in the order in which they apply.
+var devanagariFeatures = []struct {
+	tag  string
+	mask uint
+}{
+	{"locl", shapeGlobal},
+	{"ccmp", shapeGlobal},
+	{"nukt", shapeGlobal},
+	{"akhn", shapeGlobal},
+	{"rphf", shapeReph},
+	{"rkrf", shapeGlobal},
+	{"blwf", shapeBelow},
+	{"half", shapeHalf},
+	{"vatu", shapeGlobal},
+	{"cjct", shapeGlobal},
+}
+
+// Categories of Devanagari characters
+const (
+	devaOther     = iota
+	devaConsonant // Consonant
+	devaVowel     // Independent vowel
+	devaNukta     // Nukta
+	devaVirama    // Virama
+	devaMatra     // Dependent vowel sign
+	devaModifier  // Candrabindu, anusvara, visarga or stress sign
+	devaJoiner    // Zero width joiner or non-joiner
+)
+
+func devanagariCategory(r rune) int {
+	switch {
+	case r >= 0x0915 && r <= 0x0939, r >= 0x0958 && r <= 0x095F, r >= 0x0978 && r <= 0x097F:
+		return devaConsonant
+	case r >= 0x0904 && r <= 0x0914, r == 0x0960, r == 0x0961, r >= 0x0972 && r <= 0x0977:
+		return devaVowel
+	case r == 0x093C:
+		return devaNukta
+	case r == 0x094D:
+		return devaVirama
+	case r == 0x093D:
+		return devaOther
+	case r >= 0x093A && r <= 0x094F, r >= 0x0955 && r <= 0x0957, r == 0x0962, r == 0x0963:
+		return devaMatra
+	case r >= 0x0900 && r <= 0x0903, r >= 0x0951 && r <= 0x0954:
+		return devaModifier
+	case r == 0x200C, r == 0x200D:
+		return devaJoiner
+	}
+	return devaOther
+}
+
+// devanagariSyllable returns the number of characters of the Devanagari
+// syllable at the start of runes.
+func devanagariSyllable(runes []rune) int {
+	cat := func(j int) int {
+		if j < len(runes) {
+			return devanagariCategory(runes[j])
+		}
+		return -1
+	}
+	n := 1
+	switch cat(0) {
+	case devaConsonant:
+		if cat(n) == devaNukta {
+			n++
+		}
+		for cat(n) == devaVirama {
+			k := n + 1
+			if cat(k) == devaJoiner {
+				k++
+			}
+			if cat(k) != devaConsonant {
+				// A virama that ends the syllable
+				n = k
+				break
+			}
+			n = k + 1
+			if cat(n) == devaNukta {
+				n++
+			}
+		}
+	case devaVowel:
+		if cat(n) == devaNukta {
+			n++
+		}
+	default:
+		return 1
+	}
+	for c := cat(n); c == devaMatra || c == devaNukta || c == devaModifier; c = cat(n) {
+		n++
+	}
+	return n
+}
+
+func isThai(r rune) bool {
+	return r >= 0x0E01 && r <= 0x0E5B
+}
+
+// thaiAboveMark reports whether r is a Thai vowel sign or tone mark written
+// above its consonant.
+func thaiAboveMark(r rune) bool {
+	return r == 0x0E31 || r >= 0x0E34 && r <= 0x0E37 || r >= 0x0E47 && r <= 0x0E4E
+}
+
+// complexShaper shapes Devanagari and Thai text with the GSUB table of a
+// UTF-8 font.
+type complexShaper struct {
+	gsub       *gsubTable       // GSUB table, nil if the font has none
+	scripts    []string         // Scripts of the GSUB table
+	charGlyphs map[int]int      // Glyphs of the characters of the font
+	lookups    map[string][]int // Indices of the lookups of scripts and features
+}
+
+// loadShaper reads the GSUB table of the font for the shaping of complex
+// scripts.
+func (ft *fontFeatures) loadShaper(font fontDefType) error {
+	utf := font.utf8File
+	if utf.generateCMAP() == nil {
+		return fmt.Errorf("font does not have cmap for Unicode")
+	}
+	s := &complexShaper{charGlyphs: utf.charSymbolDictionary, lookups: make(map[string][]int)}
+	if gsub := utf.getTableData("GSUB"); gsub != nil {
+		t, err := newGsubTable(gsub, utf.getTableData("GDEF"))
+		if err != nil {
+			return err
+		}
+		if s.scripts = gsubScripts(&t.r); t.r.err != nil {
+			return fmt.Errorf("invalid GSUB table")
+		}
+		s.gsub = t
+	}
+	ft.shaper = s
+	return nil
+}
+
+// script returns the scripts of the GSUB table whose features apply to the
+// script with the specified tags, in order of preference.
+func (s *complexShaper) script(tags ...string) []string {
+	for _, tag := range tags {
+		if gsubHasTag(s.scripts, tag) {
+			return []string{tag}
+		}
+	}
+	return []string{"DFLT"}
+}
+
+// applyFeatures applies the lookups of the features of the scripts to the
+// glyphs whose mask has a bit of mask in common. The lookups of the features
+// apply together, in the order of the lookups.
+func (s *complexShaper) applyFeatures(glyphs []gsubGlyph, scripts []string, mask uint, tags ...string) []gsubGlyph {
+	if s.gsub == nil {
+		return glyphs
+	}
+	key := strings.Join(scripts, " ") + ":" + strings.Join(tags, " ")
+	indices, ok := s.lookups[key]
+	if !ok {
+		indices = s.gsub.lookupIndices(scripts, tags...)
+		s.lookups[key] = indices
+	}
+	for _, index := range indices {
+		glyphs = s.gsub.apply(index, glyphs, mask)
+	}
+	return glyphs
+}
+
+// glyphs returns the glyphs of the characters, with the masks, or nil if
+// the font lacks a character. Joiners that the font lacks are left out.
+func (s *complexShaper) glyphs(chars []rune, masks []uint) []gsubGlyph {
+	glyphs := make([]gsubGlyph, 0, len(chars))
+	for j, r := range chars {
+		glyph := s.charGlyphs[int(r)]
+		if glyph == 0 {
+			if r == 0x200C || r == 0x200D {
+				continue
+			}
+			return nil
+		}
+		glyphs = append(glyphs, gsubGlyph{glyph: glyph, chars: string(r), mask: masks[j]})
+	}
+	return glyphs
+}
+
+// codes returns the codes that print the glyphs with the font: a glyph that
+// represents a character in its place is printed with the character, and
+// other glyphs with the codes assigned to them. unshaped is returned if no
+// code is left to assign.
+func (s *complexShaper) codes(font fontDefType, glyphs []gsubGlyph, unshaped []rune) []rune {
+	codes := make([]rune, 0, len(glyphs))
+	for _, g := range glyphs {
+		if chars := []rune(g.chars); !g.moved && len(chars) == 1 && s.charGlyphs[int(chars[0])] == g.glyph {
+			codes = append(codes, chars[0])
+			continue
+		}
+		code := font.glyphCode(g.glyph, g.chars)
+		if code < 0 {
+			return unshaped
+		}
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// shape returns runes with their Devanagari and Thai text shaped.
+func (s *complexShaper) shape(font fontDefType, runes []rune) []rune {
+	out := make([]rune, 0, len(runes))
+	for j := 0; j < len(runes); {
+		r := runes[j]
+		n := 1
+		switch {
+		case r >= 0x0900 && r <= 0x097F && devanagariCategory(r) != devaOther:
+			n = devanagariSyllable(runes[j:])
+			out = append(out, s.shapeDevanagari(font, runes[j:j+n])...)
+		case isThai(r):
+			for j+n < len(runes) && isThai(runes[j+n]) {
+				n++
+			}
+			out = append(out, s.shapeThai(font, runes[j:j+n])...)
+		default:
+			out = append(out, r)
+		}
+		j += n
+	}
+	return out
+}
+
+// shapeDevanagari returns the codes that print the Devanagari syllable.
+func (s *complexShaper) shapeDevanagari(font fontDefType, syllable []rune) []rune {
+	masks := make([]uint, len(syllable))
+	for j := range masks {
+		masks[j] = shapeGlobal
+	}
+	reph := false
+	if devanagariCategory(syllable[0]) == devaConsonant {
+		var consonants []int
+		for j, r := range syllable {
+			if devanagariCategory(r) == devaConsonant {
+				consonants = append(consonants, j)
+			}
+		}
+		// A syllable that starts with ra and virama before a consonant has
+		// a reph
+		reph = len(consonants) > 1 && syllable[0] == 0x0930 && consonants[1] == 2
+		// The base consonant is the last one, apart from a ra that follows
+		// a virama, which takes its below-base form
+		base := consonants[len(consonants)-1]
+		if len(consonants) > 1 && syllable[base] == 0x0930 && syllable[base-1] == 0x094D &&
+			!(reph && len(consonants) == 2) {
+			base = consonants[len(consonants)-2]
+		}
+		for j, r := range syllable {
+			switch {
+			case reph && j < 2:
+				masks[j] |= shapeReph
+			case j < base:
+				masks[j] |= shapeHalf
+			case j > base:
+				masks[j] |= shapeBelow
+			}
+			// A zero width non-joiner after a virama prevents the half form
+			if r == 0x200C && j >= 2 {
+				masks[j-1] &^= shapeHalf
+				masks[j-2] &^= shapeHalf
+			}
+		}
+	}
+	glyphs := s.glyphs(syllable, masks)
+	if glyphs == nil {
+		return syllable
+	}
+	scripts := s.script("dev2", "deva")
+	for _, feature := range devanagariFeatures {
+		glyphs = s.applyFeatures(glyphs, scripts, feature.mask, feature.tag)
+	}
+	// The reph moves to the end of the syllable, before its modifiers
+	if reph && glyphs[0].chars == string(syllable[:2]) {
+		end := len(glyphs) - 1
+		for end > 0 && len(glyphs[end].chars) > 0 && devanagariCategory([]rune(glyphs[end].chars)[0]) == devaModifier {
+			end--
+		}
+		moveGlyph(glyphs, 0, end)
+	}
+	// The short i vowel sign moves before the consonants, after any virama
+	// that remains before it
+	for j, g := range glyphs {
+		if g.chars == "\u093F" || g.chars == "\u094E" {
+			to := 0
+			for k := 0; k < j; k++ {
+				if glyphs[k].glyph == s.charGlyphs[0x094D] {
+					to = k + 1
+				}
+			}
+			moveGlyph(glyphs, j, to)
+			break
+		}
+	}
+	glyphs = s.applyFeatures(glyphs, scripts, shapeGlobal, "pres", "abvs", "blws", "psts", "haln", "calt")
+	return s.codes(font, glyphs, syllable)
+}
+
+// shapeThai returns the codes that print the Thai text.
+func (s *complexShaper) shapeThai(font fontDefType, text []rune) []rune {
+	var chars []rune
+	var masks []uint
+	var moved []int
+	for _, r := range text {
+		if r == 0x0E33 && s.charGlyphs[0x0E4D] != 0 && s.charGlyphs[0x0E32] != 0 {
+			// Sara am is decomposed, its nikhahit being written before the
+			// marks above the consonant that precede it
+			j := len(chars)
+			for j > 0 && thaiAboveMark(chars[j-1]) {
+				j--
+			}
+			if j < len(chars) {
+				moved = append(moved, j)
+			}
+			chars = append(chars[:j], append([]rune{0x0E4D}, chars[j:]...)...)
+			chars = append(chars, 0x0E32)
+			masks = append(masks, shapeGlobal, shapeGlobal)
+			continue
+		}
+		chars = append(chars, r)
+		masks = append(masks, shapeGlobal)
+	}
+	glyphs := s.glyphs(chars, masks)
+	if glyphs == nil {
+		return text
+	}
+	for _, j := range moved {
+		glyphs[j].moved = true
+	}
+	glyphs = s.applyFeatures(glyphs, s.script("thai"), shapeGlobal, "ccmp", "locl", "rlig", "calt")
+	return s.codes(font, glyphs, text)
+}
+
+// moveGlyph moves the glyph at position from to position to, shifting the
+// glyphs in between.
+func moveGlyph(glyphs []gsubGlyph, from, to int) {
+	g := glyphs[from]
+	g.moved = true
+	if from < to {
+		copy(glyphs[from:to], glyphs[from+1:to+1])
+	} else {
+		copy(glyphs[to+1:from+1], glyphs[to:from])
+	}
+	glyphs[to] = g
+}
@@ -0,0 +1,522 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"strings"
+)
+
+// TextSpan is a run of text printed with its own font, color and link by
+// WriteSpans() and MultiCellSpans(), so that bold, italic or colored words
+// can be mixed within the lines of a paragraph. A span may also be an image,
+// such as an icon, that flows with the text.
+type TextSpan struct {
+	Text string
+	// The font of the span, as selected by SetFont(). The current font family
+	// and size are used if FontFamily is empty or FontSize is 0. FontStyle is
+	// regular if empty and may include "U" and "S" for underlined and struck
+	// out text.
+	FontFamily string
+	FontStyle  string
+	FontSize   float64
+	// The color of the text, the current text color if nil.
+	TextColor *RGBType
+	// The color of the background of the text, which has none if nil. The
+	// background fills the part of the line that the text takes, unless
+	// FillPadding is not zero: the background is then a rectangle around the
+	// text, of the height of its font, that extends by FillPadding, in the
+	// unit of measure specified in New(), beyond the text on each side, as
+	// for highlighted words. The backgrounds of a line are painted before
+	// its text, so that padding does not cover neighboring text.
+	FillColor   *RGBType
+	FillPadding float64
+	// The rise of the baseline of the text above the baseline of the line, in
+	// the unit of measure specified in New(), as set with SetTextRise(). The
+	// text is set as a superscript or a subscript, as by WriteSuperscript()
+	// or WriteSubscript(), if Superscript or Subscript is true.
+	TextRise               float64
+	Superscript, Subscript bool
+	// A link identifier returned by AddLink() or a URL that the text of the
+	// span links to, if not zero.
+	Link    int
+	LinkStr string
+	// An image printed in place of the text of the span, which flows with
+	// the text of the other spans, standing on their baseline. ImageName is
+	// an image name or file name as accepted by Image(), and ImageWidth and
+	// ImageHeight are the size of the image, computed as by Image() when
+	// zero. A line is made taller than the line height if its images do not
+	// fit in it.
+	ImageName               string
+	ImageWidth, ImageHeight float64
+}
+
+// WriteSpans prints the text of spans in flowing mode, as Write() does, each
+// span with its own font, color and link. Lines are broken between words
+// regardless of the spans they belong to, and the text of all the spans of a
+// line is set on a common baseline, that of the largest font of the line. The
+// current font and text color are restored upon method exit.
+//
+// h indicates the line height in the unit of measure specified in New(). If h
+// is 0, the line height set with SetLineHeight() or SetLineHeightFactor() is
+// used.
+func (f *Fpdf) WriteSpans(h float64, spans []TextSpan) {
+	if f.err != nil {
+		return
+	}
+	h = f.textLineHt(h)
+	x := f.x
+	lines := f.spanLines(spans, func(n int) float64 {
+		if n == 0 {
+			return f.w - f.rMargin - x - 2*f.cMargin
+		}
+		return f.w - f.rMargin - f.lMargin - 2*f.cMargin
+	})
+	lineHt := 0.0
+	for n, line := range lines {
+		if n > 0 {
+			f.x = f.lMargin
+			f.y += lineHt
+		}
+		// As with Write(), the cells of the lines are as wide as their text
+		lineHt = f.spanLinePrint(spans, line, line.w, h, "", "L", false)
+	}
+}
+
+// MultiCellSpans prints the text of spans in lines of width w, as
+// MultiCell() does, each span with its own font, color and link. Lines are
+// broken between words regardless of the spans they belong to, and the text
+// of all the spans of a line is set on a common baseline, that of the largest
+// font of the line. The current font and text color are restored upon method
+// exit.
+//
+// See MultiCell() for details about w, h, borderStr, alignStr and fill.
+func (f *Fpdf) MultiCellSpans(w, h float64, spans []TextSpan, borderStr, alignStr string, fill bool) {
+	if f.err != nil {
+		return
+	}
+	h = f.textLineHt(h)
+	if alignStr == "" {
+		alignStr = "J"
+	}
+	if w == 0 {
+		w = f.w - f.rMargin - f.x
+	}
+	borderStr = strings.ToUpper(borderStr)
+	if borderStr == "1" {
+		borderStr = "LTRB"
+	}
+	sides := ""
+	for _, side := range "LR" {
+		if strings.ContainsRune(borderStr, side) {
+			sides += string(side)
+		}
+	}
+	lines := f.spanLines(spans, func(int) float64 {
+		return w - 2*f.cMargin
+	})
+	x := f.x
+	for n, line := range lines {
+		b := sides
+		if n == 0 && strings.Contains(borderStr, "T") {
+			b += "T"
+		}
+		if n == len(lines)-1 && strings.Contains(borderStr, "B") {
+			b += "B"
+		}
+		f.x = x
+		f.y += f.spanLinePrint(spans, line, w, h, b, alignStr, fill)
+	}
+	f.x = f.lMargin
+}
+
+// spanPiece is a word, the spaces between words or the image of a span.
+type spanPiece struct {
+	span   int     // Index of the span
+	str    string  // Text of the piece
+	w      float64 // Width of the text or image
+	h      float64 // Height of an image
+	spaces int     // Number of spaces of a piece of spaces
+}
+
+// spanLine is a line of laid out spans.
+type spanLine struct {
+	pieces []spanPiece
+	w      float64 // Width of the text of the line
+	spaces int     // Number of spaces between the words of the line
+	last   bool    // Last line or line ended by a newline
+	spaced bool    // Broken at spaces, which are dropped
+}
+
+// spanStateType holds the font, text color and text rise that printing
+// spans changes.
+type spanStateType struct {
+	familyStr, styleStr string
+	sizePt              float64
+	textColor           colorType
+	colorFlag           bool
+	rise                float64
+}
+
+// spanStateGet returns the current font, text color and text rise.
+func (f *Fpdf) spanStateGet() (st spanStateType) {
+	st.familyStr, st.styleStr, st.sizePt = f.fontFamily, f.fontStyle, f.fontSizePt
+	if f.underline {
+		st.styleStr += "U"
+	}
+	if f.strikeout {
+		st.styleStr += "S"
+	}
+	st.textColor, st.colorFlag = f.color.text, f.colorFlag
+	st.rise = f.textRise
+	return
+}
+
+// spanStatePut restores the font, text color and text rise st.
+func (f *Fpdf) spanStatePut(st spanStateType) {
+	if f.textRise != st.rise {
+		f.SetTextRise(st.rise)
+	}
+	if st.familyStr != "" && f.spanStateGet() != st {
+		f.SetFont(st.familyStr, st.styleStr, st.sizePt)
+	}
+	f.color.text, f.colorFlag = st.textColor, st.colorFlag
+}
+
+// spanApply selects the font, text color and text rise of span, given the
+// state st from which unset attributes are taken.
+func (f *Fpdf) spanApply(span TextSpan, st spanStateType) {
+	next := st
+	next.styleStr = strings.ToUpper(span.FontStyle)
+	if span.FontFamily != "" {
+		next.familyStr = strings.ToLower(span.FontFamily)
+	}
+	if span.FontSize != 0 {
+		next.sizePt = span.FontSize
+	}
+	rise := st.rise + span.TextRise
+	switch {
+	case span.Superscript:
+		rise += superscriptRise * next.sizePt / f.k
+		next.sizePt *= scriptSize
+	case span.Subscript:
+		rise += subscriptRise * next.sizePt / f.k
+		next.sizePt *= scriptSize
+	}
+	if cur := f.spanStateGet(); cur.familyStr != next.familyStr || cur.styleStr != next.styleStr ||
+		cur.sizePt != next.sizePt {
+		f.SetFont(next.familyStr, next.styleStr, next.sizePt)
+	}
+	if c := span.TextColor; c != nil {
+		f.setTextColor(c.R, c.G, c.B)
+	} else {
+		f.color.text, f.colorFlag = st.textColor, st.colorFlag
+	}
+	if f.textRise != rise {
+		f.SetTextRise(rise)
+	}
+}
+
+// spanLines breaks the text of spans into lines of the widths that width
+// returns for each line by number, counted from 0.
+func (f *Fpdf) spanLines(spans []TextSpan, width func(n int) float64) (lines []spanLine) {
+	st := f.spanStateGet()
+	defer f.spanStatePut(st)
+	measure := func(n int, str string) spanPiece {
+		f.spanApply(spans[n], st)
+		return spanPiece{span: n, str: str, w: f.GetSpacedStringWidth(str)}
+	}
+	var line spanLine
+	// newLine ends the line, without the spaces at its end if broken between
+	// words.
+	newLine := func(last bool) {
+		end := len(line.pieces)
+		for !last && end > 0 && line.pieces[end-1].spaces > 0 {
+			end--
+			line.w -= line.pieces[end].w
+			line.spaces -= line.pieces[end].spaces
+			line.spaced = true
+		}
+		line.pieces = line.pieces[:end]
+		line.last = last
+		lines = append(lines, line)
+		line = spanLine{}
+	}
+	var word []spanPiece // Pieces of the word being read, which spans may split
+	// addWord appends the word to the line, breaking the line before it if it
+	// does not fit.
+	addWord := func() {
+		wordW := 0.0
+		for _, piece := range word {
+			wordW += piece.w
+		}
+		if len(line.pieces) > 0 && line.w+wordW > width(len(lines)) ||
+			len(lines) == 0 && wordW > width(0) && wordW <= width(1) {
+			newLine(false)
+		}
+		for _, piece := range word {
+			if line.w+piece.w <= width(len(lines)) {
+				line.pieces = append(line.pieces, piece)
+				line.w += piece.w
+				continue
+			}
+			// A word wider than the line is broken between characters
+			if piece.h > 0 {
+				if len(line.pieces) > 0 {
+					newLine(false)
+				}
+				line.pieces = append(line.pieces, piece)
+				line.w += piece.w
+				continue
+			}
+			runes := []rune(piece.str)
+			bounds := graphemeBounds(runes)
+			for k := 0; k < len(runes); {
+				end := graphemeBreak(bounds, k, k)
+				c := measure(piece.span, string(runes[k:end]))
+				if len(line.pieces) > 0 && line.w+c.w > width(len(lines)) {
+					newLine(false)
+				}
+				line.pieces = append(line.pieces, c)
+				line.w += c.w
+				k = end
+			}
+		}
+		word = word[:0]
+	}
+	for n, span := range spans {
+		if span.ImageName != "" {
+			info := f.RegisterImageOptions(span.ImageName, ImageOptions{})
+			if f.err != nil {
+				return nil
+			}
+			w, h := f.imageExtent(info, span.ImageWidth, span.ImageHeight)
+			word = append(word, spanPiece{span: n, w: w, h: h})
+			continue
+		}
+		txtStr := strings.Replace(span.Text, "\r", "", -1)
+		for len(txtStr) > 0 {
+			var str string
+			switch txtStr[0] {
+			case '\n':
+				addWord()
+				newLine(true)
+				str = "\n"
+			case ' ':
+				str = txtStr[:len(txtStr)-len(strings.TrimLeft(txtStr, " "))]
+				addWord()
+				// Spaces at the beginning of a line broken between words are
+				// dropped
+				if len(line.pieces) > 0 || len(lines) == 0 || lines[len(lines)-1].last {
+					piece := measure(n, str)
+					piece.spaces = len(str)
+					line.pieces = append(line.pieces, piece)
+					line.w += piece.w
+					line.spaces += piece.spaces
+				}
+			default:
+				end := strings.IndexAny(txtStr, " \n")
+				if end < 0 {
+					end = len(txtStr)
+				}
+				str = txtStr[:end]
+				word = append(word, measure(n, str))
+			}
+			if f.err != nil {
+				return nil
+			}
+			txtStr = txtStr[len(str):]
+		}
+	}
+	addWord()
+	newLine(true)
+	return
+}
+
+// spanLinesSpans returns spans whose text is that of lines of spans, as
+// broken by spanLines(), each line but the last followed by the newline or
+// the space at which it is broken, so that spanLines() breaks the text again
+// in the same lines.
+func spanLinesSpans(spans []TextSpan, lines []spanLine) (list []TextSpan) {
+	last := -1 // Index of the span of the text added last
+	add := func(n int, str string) {
+		if n == last && spans[n].ImageName == "" {
+			list[len(list)-1].Text += str
+			return
+		}
+		span := spans[n]
+		span.Text = str
+		list = append(list, span)
+		last = n
+	}
+	for j, line := range lines {
+		for _, piece := range line.pieces {
+			add(piece.span, piece.str)
+		}
+		sep := ""
+		switch {
+		case j == len(lines)-1:
+		case line.last:
+			sep = "\n"
+		case line.spaced:
+			sep = " "
+		}
+		switch {
+		case sep == "":
+		case last >= 0 && spans[last].ImageName == "":
+			list[len(list)-1].Text += sep
+		default:
+			list = append(list, TextSpan{Text: sep})
+			last = -1
+		}
+	}
+	return
+}
+
+// spanLinePrint prints the line of spans in a cell of width w and of height
+// h, or more if the line has images that do not fit, with the border
+// borderStr, from the current position, which is left at the end of the
+// line. The text of the line is aligned as alignStr specifies. The height of
+// the line is returned.
+func (f *Fpdf) spanLinePrint(spans []TextSpan, line spanLine, w, h float64, borderStr, alignStr string,
+	fill bool) (lineHt float64) {
+	st := f.spanStateGet()
+	defer f.spanStatePut(st)
+	lineHt, maxSize := f.spanLineHeight(spans, line, h)
+	// The cell of the line breaks the page if needed
+	x := f.x
+	f.CellFormat(w, lineHt, "", borderStr, 0, "", fill, 0, "")
+	if f.err != nil {
+		return
+	}
+	y := f.y
+	baseline := y + lineHt - .5*h + .3*maxSize
+	f.x = x + f.cMargin
+	stretch := 0.0
+	switch {
+	case strings.Contains(alignStr, "R"):
+		f.x += w - 2*f.cMargin - line.w
+	case strings.Contains(alignStr, "C"):
+		f.x += (w - 2*f.cMargin - line.w) / 2
+	case strings.Contains(alignStr, "J") && !line.last && line.spaces > 0:
+		stretch = (w - 2*f.cMargin - line.w) / float64(line.spaces)
+	}
+	cMargin := f.cMargin
+	f.cMargin = 0
+	// The padded backgrounds of the spans are painted in a first pass and
+	// their text in a second one
+	left := f.x
+	for _, padded := range []bool{true, false} {
+		f.x = left
+		f.spanLinePieces(spans, line, st, stretch, baseline, y, lineHt, padded)
+	}
+	f.cMargin = cMargin
+	f.x, f.y = x+w, y
+	return
+}
+
+// spanLinePieces prints the pieces of the line of spans, with the text
+// stretched by stretch at each space, from the current position. The top of
+// the line is y and its baseline is baseline. If padded is true, only the
+// backgrounds of the spans with padding are painted.
+func (f *Fpdf) spanLinePieces(spans []TextSpan, line spanLine, st spanStateType, stretch, baseline,
+	y, lineHt float64, padded bool) {
+	for j := 0; j < len(line.pieces); j++ {
+		piece := line.pieces[j]
+		span := spans[piece.span]
+		if piece.h > 0 {
+			if !padded {
+				// Images stand on the baseline
+				info := f.RegisterImageOptions(span.ImageName, ImageOptions{})
+				if f.err != nil {
+					break
+				}
+				f.imageOut(info, f.x, baseline-piece.h, piece.w, piece.h, false, false, span.Link, span.LinkStr)
+			}
+			f.x += piece.w
+			continue
+		}
+		pw := piece.w + stretch*float64(piece.spaces)
+		// Consecutive pieces of a span are printed together unless their
+		// spaces are stretched
+		for stretch == 0 && j+1 < len(line.pieces) && line.pieces[j+1].span == piece.span {
+			j++
+			piece.str += line.pieces[j].str
+			pw += line.pieces[j].w
+		}
+		c := span.FillColor
+		if padded {
+			if c != nil && span.FillPadding != 0 {
+				// The text is centered on the font size above its baseline,
+				// as in a cell
+				f.spanApply(span, st)
+				pad := span.FillPadding
+				fillColor := f.color.fill
+				f.setFillColor(c.R, c.G, c.B)
+				f.Rect(f.x-pad, baseline-f.textRise-.8*f.fontSize-pad, pw+2*pad, f.fontSize+2*pad, "F")
+				f.fillColorPut(fillColor)
+			}
+			f.x += pw
+			continue
+		}
+		f.spanApply(span, st)
+		// The cell of the text ends at the bottom of the line and has the
+		// baseline of the line
+		cellHt := 2 * (y + lineHt + .3*f.fontSize - baseline)
+		f.y = y + lineHt - cellHt
+		if c != nil && span.FillPadding == 0 {
+			fillColor := f.color.fill
+			f.setFillColor(c.R, c.G, c.B)
+			f.CellFormat(pw, cellHt, piece.str, "", 0, "L", true, span.Link, span.LinkStr)
+			f.fillColorPut(fillColor)
+		} else {
+			f.CellFormat(pw, cellHt, piece.str, "", 0, "L", false, span.Link, span.LinkStr)
+		}
+	}
+}
+
+// spanLineHeight returns the height of the line of spans of height h, more
+// if the line has images that do not fit, and the size of its largest font.
+func (f *Fpdf) spanLineHeight(spans []TextSpan, line spanLine, h float64) (lineHt, maxSize float64) {
+	st := f.spanStateGet()
+	defer f.spanStatePut(st)
+	// The baseline of the line is that of the largest font of the line, which
+	// is lowered for images taller than the space above it
+	imageHt := 0.0
+	for _, piece := range line.pieces {
+		if piece.h > 0 {
+			imageHt = math.Max(imageHt, piece.h)
+			continue
+		}
+		f.spanApply(spans[piece.span], st)
+		maxSize = math.Max(maxSize, f.fontSize)
+	}
+	if maxSize == 0 {
+		maxSize = f.fontSize
+	}
+	lineHt = h + math.Max(0, imageHt-(.5*h+.3*maxSize))
+	return
+}
+
+// fillColorPut restores the fill color c.
+func (f *Fpdf) fillColorPut(c colorType) {
+	f.color.fill = c
+	f.colorFlag = f.color.fill.str != f.color.text.str
+	if f.page > 0 {
+		f.out(c.str)
+	}
+}
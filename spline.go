@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// CurveThroughPoints draws a smooth curve that passes through points, in the
+// order of the slice, such as the line of a chart or a stroke of a
+// signature, as PathType.CurveThroughPoints() builds it. The curve is closed
+// if closed is true. See Path() for styleStr.
+func (f *Fpdf) CurveThroughPoints(points []PointType, tension float64, closed bool, styleStr string) {
+	if f.err != nil {
+		return
+	}
+	if len(points) < 2 {
+		f.SetErrorf("curve through %d points, at least 2 are needed", len(points))
+		return
+	}
+	var path PathType
+	path.CurveThroughPoints(points, tension, closed)
+	f.Path(path, styleStr)
+}
+
+// CurveThroughPoints adds a subpath of a smooth curve that passes through
+// points, made of a cubic Bézier curve from each point to the next (a
+// cardinal spline). At each point, the curve is parallel to the line joining
+// the points on either side of it, or, at the ends of an open curve, to the
+// line joining the end to the next point. tension sets how tight the curve
+// is: 0 gives the Catmull-Rom spline, 1 straight lines between the points,
+// and values between them curves in between. Negative values give looser
+// curves. If closed is true, the curve goes on from the last point back to
+// the first and the subpath is closed.
+func (p *PathType) CurveThroughPoints(points []PointType, tension float64, closed bool) {
+	count := len(points)
+	if count == 0 {
+		return
+	}
+	p.MoveTo(points[0].X, points[0].Y)
+	// at returns the point j, wrapped around for closed curves and clamped
+	// to the ends for open ones
+	at := func(j int) PointType {
+		if closed {
+			return points[(j+count)%count]
+		}
+		return points[max(0, minInt(j, count-1))]
+	}
+	// The control points are a third of the way along the tangents, which
+	// are (1 - tension) / 2 times the line joining the neighbors
+	s := (1 - tension) / 6
+	last := count - 1
+	if closed {
+		last = count
+	}
+	for j := 0; j < last; j++ {
+		p0, p1, p2, p3 := at(j-1), at(j), at(j+1), at(j+2)
+		p.CubicTo(p1.X+s*(p2.X-p0.X), p1.Y+s*(p2.Y-p0.Y), p2.X-s*(p3.X-p1.X), p2.Y-s*(p3.Y-p1.Y), p2.X, p2.Y)
+	}
+	if closed {
+		p.Close()
+	}
+}
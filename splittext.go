@@ -11,7 +11,8 @@ import (
 // function can be used to determine the total height of wrapped text for
 // vertical placement purposes.
 func (f *Fpdf) SplitText(txt string, w float64) (lines []string) {
-	cw := f.currentFont.Cw
+	txt = f.shapeText(txt)
+	cw := f.charWidths()
 	wmax := int(math.Ceil((w - 2*f.cMargin) * 1000 / f.fontSize))
 	s := []rune(txt) // Return slice of UTF-8 runes
 	nb := len(s)
@@ -19,21 +20,25 @@ func (f *Fpdf) SplitText(txt string, w float64) (lines []string) {
 		nb--
 	}
 	s = s[0:nb]
+	bounds := graphemeBounds(s)
 	sep := -1
 	i := 0
 	j := 0
-	l := 0
+	l := 0.0
 	for i < nb {
 		c := s[i]
-		l += cw[c]
+		// Marker width 65535 used for zero width symbols, such as
+		// combining marks
+		if int(c) < len(cw) && cw[c] != 65535 {
+			l += float64(cw[c])
+		}
+		l += f.spacingWidth(c)
 		if unicode.IsSpace(c) || isChinese(c) {
 			sep = i
 		}
-		if c == '\n' || l > wmax {
+		if c == '\n' || l > float64(wmax) {
 			if sep == -1 {
-				if i == j {
-					i++
-				}
+				i = graphemeBreak(bounds, j, i)
 				sep = i
 			} else {
 				i = sep + 1
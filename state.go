@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// graphicsStateType is a graphics state saved by PushState(), along with the
+// nesting of the clipping operations, transformations and transparency groups
+// in effect when it was saved.
+type graphicsStateType struct {
+	page                     int
+	color                    struct{ draw, fill, text colorType }
+	colorFlag                bool
+	lineWidth                float64
+	capStyle, joinStyle      int
+	dashArray                []float64
+	dashPhase                float64
+	lineDecoration           LineDecorationType
+	alpha                    float64
+	blendMode                string
+	fontFamily, fontStyle    string
+	fontSynthStr             string
+	fontSizePt, fontSize     float64
+	currentFont              fontDefType
+	underline, strikeout     bool
+	charSpacing, wordSpacing float64
+	textRise                 float64
+	textMode                 int
+	clipNest, transformNest  int
+	groupNest                int
+}
+
+// PushState saves the current graphics state: the draw, fill and text colors,
+// the line width, cap style, join style, dash pattern and decorations, the
+// alpha and blend mode, the font, its style and size, the character and word
+// spacing, the text rise and rendering mode, the clipping area and the
+// transformation. The state is restored by the matching call of PopState(),
+// so that a function can change any of these parameters without affecting
+// the text, drawings and images that follow it. States can be nested.
+//
+// A state must be restored on the page on which it was saved. The document
+// cannot be successfully output while a state is saved.
+func (f *Fpdf) PushState() {
+	if f.err != nil {
+		return
+	}
+	if f.page == 0 {
+		f.SetErrorf("no page for the graphics state")
+		return
+	}
+	f.stateStack = append(f.stateStack, f.graphicsState())
+	f.ctmPush()
+	f.out("q")
+}
+
+// PopState restores the graphics state saved by the last call of
+// PushState(). Clipping operations and transformations begun since that call
+// and not yet ended are ended along with it, while transparency groups,
+// soft masks and shadows begun since must be ended first.
+//
+// The PushState() example demonstrates this method.
+func (f *Fpdf) PopState() {
+	if f.err != nil {
+		return
+	}
+	if len(f.stateStack) == 0 {
+		f.SetErrorf("no graphics state to restore")
+		return
+	}
+	st := f.stateStack[len(f.stateStack)-1]
+	switch {
+	case f.page != st.page:
+		f.SetErrorf("graphics state saved on page %d restored on page %d", st.page, f.page)
+		return
+	case f.clipNest < st.clipNest || f.transformNest < st.transformNest:
+		f.SetErrorf("clipping operation or transformation begun before the graphics state was saved has been ended")
+		return
+	case len(f.groupNest) != st.groupNest:
+		f.SetErrorf("transparency group, soft mask or shadow must be ended before the graphics state is restored")
+		return
+	}
+	f.stateStack = f.stateStack[:len(f.stateStack)-1]
+	// Each clipping operation and transformation saved the state, and is
+	// ended with the matching restoration
+	for j := st.clipNest + st.transformNest; j < f.clipNest+f.transformNest; j++ {
+		f.ctmPop()
+		f.out("Q")
+	}
+	f.ctmPop()
+	f.out("Q")
+	f.clipNest, f.transformNest = st.clipNest, st.transformNest
+	f.setGraphicsState(st)
+}
+
+// graphicsState returns the current graphics state.
+func (f *Fpdf) graphicsState() graphicsStateType {
+	return graphicsStateType{
+		page:           f.page,
+		color:          f.color,
+		colorFlag:      f.colorFlag,
+		lineWidth:      f.lineWidth,
+		capStyle:       f.capStyle,
+		joinStyle:      f.joinStyle,
+		dashArray:      append([]float64(nil), f.dashArray...),
+		dashPhase:      f.dashPhase,
+		lineDecoration: f.lineDecoration,
+		alpha:          f.alpha,
+		blendMode:      f.blendMode,
+		fontFamily:     f.fontFamily,
+		fontStyle:      f.fontStyle,
+		fontSynthStr:   f.fontSynthStr,
+		fontSizePt:     f.fontSizePt,
+		fontSize:       f.fontSize,
+		currentFont:    f.currentFont,
+		underline:      f.underline,
+		strikeout:      f.strikeout,
+		charSpacing:    f.charSpacing,
+		wordSpacing:    f.wordSpacing,
+		textRise:       f.textRise,
+		textMode:       f.textMode,
+		clipNest:       f.clipNest,
+		transformNest:  f.transformNest,
+		groupNest:      len(f.groupNest),
+	}
+}
+
+// setGraphicsState sets the parameters of the graphics state st, other than
+// the page and the nesting, as the current ones.
+func (f *Fpdf) setGraphicsState(st graphicsStateType) {
+	f.color, f.colorFlag = st.color, st.colorFlag
+	f.lineWidth, f.capStyle, f.joinStyle = st.lineWidth, st.capStyle, st.joinStyle
+	f.dashArray, f.dashPhase = st.dashArray, st.dashPhase
+	f.lineDecoration = st.lineDecoration
+	f.alpha, f.blendMode = st.alpha, st.blendMode
+	f.fontFamily, f.fontStyle, f.fontSynthStr = st.fontFamily, st.fontStyle, st.fontSynthStr
+	f.fontSizePt, f.fontSize, f.currentFont = st.fontSizePt, st.fontSize, st.currentFont
+	f.underline, f.strikeout = st.underline, st.strikeout
+	f.charSpacing, f.wordSpacing = st.charSpacing, st.wordSpacing
+	f.textRise, f.textMode = st.textRise, st.textMode
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+)
+
+// Superellipse draws a superellipse centered at point (x, y), the curve of
+// the points where |dx/rx|^n + |dy/ry|^n = 1, with horizontal and vertical
+// radii rx and ry. The exponent n shapes the curve: 2 gives an ellipse,
+// greater values give rectangles with increasingly tight rounded corners,
+// such as the "squircle" of exponent 4, and values between 0 and 1 give stars
+// with concave sides. See Path() for styleStr.
+func (f *Fpdf) Superellipse(x, y, rx, ry, n float64, styleStr string) {
+	if f.err != nil {
+		return
+	}
+	if n <= 0 {
+		f.SetErrorf("invalid superellipse exponent %.3f", n)
+		return
+	}
+	var path PathType
+	path.Superellipse(x, y, rx, ry, n)
+	f.Path(path, styleStr)
+}
+
+// SuperellipseRect draws a rectangle of width w and height h, whose upper left
+// corner is at point (x, y), with the rounded corners of radii rTL (upper
+// left), rTR (upper right), rBR (lower right) and rBL (lower left), as
+// RoundedRectExt() does, except that each corner is a quarter of a
+// superellipse of exponent n rather than of a circle. With n greater than 2,
+// the corners blend smoothly into the sides, as those of the "squircles" of
+// user interfaces, of exponent 4 or 5. See Superellipse() for the exponent and
+// Path() for styleStr.
+//
+// A zero radius gives a square corner. Radii are reduced in proportion where
+// the radii of the corners of a side add up to more than its length.
+func (f *Fpdf) SuperellipseRect(x, y, w, h, rTL, rTR, rBR, rBL, n float64, styleStr string) {
+	if f.err != nil {
+		return
+	}
+	if n <= 0 {
+		f.SetErrorf("invalid superellipse exponent %.3f", n)
+		return
+	}
+	var path PathType
+	path.RoundedRect(x, y, w, h, rTL, rTR, rBR, rBL, n)
+	f.Path(path, styleStr)
+}
+
+// Superellipse adds a closed subpath of the superellipse centered at (x, y)
+// of horizontal and vertical radii rx and ry and exponent n, drawn
+// counter-clockwise from the 3 o'clock position. See Superellipse() of Fpdf
+// for the exponent.
+func (p *PathType) Superellipse(x, y, rx, ry, n float64) {
+	p.MoveTo(x+rx, y)
+	p.superellipseArc(x, y, rx, ry, n, 0, 360)
+	p.Close()
+}
+
+// RoundedRect adds a closed subpath of the rectangle of width w and height h
+// whose upper left corner is at (x, y), with rounded corners of radii rTL,
+// rTR, rBR and rBL, drawn clockwise from the upper left corner. Each corner
+// is a quarter of a circle if n is 2, and of a superellipse of exponent n
+// otherwise, as SuperellipseRect() describes.
+func (p *PathType) RoundedRect(x, y, w, h, rTL, rTR, rBR, rBL, n float64) {
+	rTL, rTR, rBR, rBL = math.Max(rTL, 0), math.Max(rTR, 0), math.Max(rBR, 0), math.Max(rBL, 0)
+	// Radii are scaled down together so that those of each side fit it
+	scale := 1.0
+	for _, side := range [][3]float64{{w, rTL, rTR}, {h, rTR, rBR}, {w, rBR, rBL}, {h, rBL, rTL}} {
+		if sum := side[1] + side[2]; sum > side[0] {
+			scale = math.Min(scale, side[0]/sum)
+		}
+	}
+	rTL, rTR, rBR, rBL = rTL*scale, rTR*scale, rBR*scale, rBL*scale
+	corner := func(cx, cy, r, start float64) {
+		switch {
+		case r == 0:
+			p.LineTo(cx, cy)
+		case n == 2:
+			p.ArcTo(cx, cy, r, r, 0, start, start-90)
+		default:
+			p.superellipseArc(cx, cy, r, r, n, start, start-90)
+		}
+	}
+	p.MoveTo(x+rTL, y)
+	p.LineTo(x+w-rTR, y)
+	corner(x+w-rTR, y+rTR, rTR, 90)
+	p.LineTo(x+w, y+h-rBR)
+	corner(x+w-rBR, y+h-rBR, rBR, 0)
+	p.LineTo(x+rBL, y+h)
+	corner(x+rBL, y+h-rBL, rBL, -90)
+	p.LineTo(x, y+rTL)
+	corner(x+rTL, y+rTL, rTL, 180)
+	p.Close()
+}
+
+// superellipseArc adds the arc of the superellipse centered at (x, y) of
+// radii rx and ry and exponent n from the angle degStart to degEnd, in
+// degrees measured counter-clockwise from the 3 o'clock position, to the
+// current point, which is the start of the arc.
+func (p *PathType) superellipseArc(x, y, rx, ry, n, degStart, degEnd float64) {
+	// The arc is approximated by cubic curves through points of the curve,
+	// each tangent to the line joining the points on either side of its
+	// ends (Catmull-Rom spline). By symmetry, the tangents at multiples of
+	// 90 degrees are exact.
+	point := func(deg float64) PointType {
+		sin, cos := math.Sincos(deg * math.Pi / 180)
+		pow := func(v float64) float64 {
+			if math.Abs(v) < 1e-12 {
+				// The ends of quarters are exactly on the axes
+				return 0
+			}
+			return math.Copysign(math.Pow(math.Abs(v), 2/n), v)
+		}
+		return PointType{X: x + rx*pow(cos), Y: y - ry*pow(sin)}
+	}
+	segments := max(int(math.Ceil(math.Abs(degEnd-degStart)/90))*8, 1)
+	dt := (degEnd - degStart) / float64(segments)
+	for j := 0; j < segments; j++ {
+		t := degStart + float64(j)*dt
+		p0, p1, p2, p3 := point(t-dt), point(t), point(t+dt), point(t+2*dt)
+		p.CubicTo(p1.X+(p2.X-p0.X)/6, p1.Y+(p2.Y-p0.Y)/6,
+			p2.X-(p3.X-p1.X)/6, p2.Y-(p3.Y-p1.Y)/6, p2.X, p2.Y)
+	}
+}
@@ -0,0 +1,1181 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SVGType holds an SVG image parsed by SVGParse() or SVGFileParse(). Unlike
+// SVGBasicType, which retains only the outlines of paths, it retains the
+// structure of the document so that SVGWrite() can render it with its shapes,
+// groups, transforms, fills, strokes, opacity and text.
+type SVGType struct {
+	Wd, Ht  float64 // Natural size of the image in pixels (1/96 inch)
+	viewBox [4]float64
+	aspect  string
+	root    *svgNode
+	ids     map[string]*svgNode
+}
+
+// svgNode is an element of a parsed SVG document.
+type svgNode struct {
+	name     string
+	attrs    map[string]string
+	children []*svgNode
+	text     string
+}
+
+// SVGParse parses a buffer that contains an SVG image. The supported elements
+// are svg, g, use, path, rect, circle, ellipse, line, polyline, polygon and
+// text. Other elements, such as clip paths, masks and filters, are ignored. A
+// fill or stroke that refers to a gradient is approximated with the color and
+// opacity of its first stop.
+func SVGParse(buf []byte) (svg SVGType, err error) {
+	dec := xml.NewDecoder(bytes.NewReader(buf))
+	dec.Entity = xml.HTMLEntity
+	var stack []*svgNode
+	var tok xml.Token
+	for {
+		tok, err = dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &svgNode{name: t.Name.Local, attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				node.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if svg.root == nil {
+				svg.root = node
+			}
+			if id, ok := node.attrs["id"]; ok {
+				if svg.ids == nil {
+					svg.ids = make(map[string]*svgNode)
+				}
+				svg.ids[id] = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			// The content of tspan elements is rendered as part of the
+			// enclosing text element
+			for j := len(stack) - 1; j >= 0; j-- {
+				if stack[j].name == "text" {
+					stack[j].text += string(t)
+					break
+				}
+			}
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+	if svg.root == nil || svg.root.name != "svg" {
+		err = fmt.Errorf("svg element not found")
+		return
+	}
+	attrs := svg.root.attrs
+	if list, ok := svgNumbers(attrs["viewBox"]); ok && len(list) == 4 && list[2] > 0 && list[3] > 0 {
+		copy(svg.viewBox[:], list)
+	}
+	svg.Wd = svgLength(attrs["width"], svg.viewBox[2], 16)
+	svg.Ht = svgLength(attrs["height"], svg.viewBox[3], 16)
+	if svg.Wd <= 0 || svg.Ht <= 0 {
+		err = fmt.Errorf("SVG image has no size")
+		return
+	}
+	if svg.viewBox[2] == 0 {
+		svg.viewBox = [4]float64{0, 0, svg.Wd, svg.Ht}
+	}
+	svg.aspect = strings.TrimSpace(attrs["preserveAspectRatio"])
+	return
+}
+
+// SVGFileParse parses the SVG image in the specified file. See SVGParse() for
+// the supported content.
+func SVGFileParse(svgFileStr string) (svg SVGType, err error) {
+	var buf []byte
+	buf, err = ioutil.ReadFile(svgFileStr)
+	if err == nil {
+		svg, err = SVGParse(buf)
+	}
+	return
+}
+
+// SVGWrite renders the SVG image specified by svg as vector content with its
+// upper left corner at (x, y) and with the width and height w and h, in the
+// unit of measure specified in New(). If w and h are both zero, the natural
+// size of the image is used. If only one of them is zero, it is calculated so
+// that the aspect ratio of the image is retained. Unless the image specifies a
+// preserveAspectRatio value of "none", its content is scaled uniformly and
+// aligned within the area as the value specifies, by default to fit the area
+// and be centered in it. The content is clipped to the area.
+//
+// The current colors, line width and font of the document are neither used
+// nor changed. An element with an opacity less than one, such as a group, is
+// painted as a transparency group, so that the opacity applies to the element
+// as a whole.
+func (f *Fpdf) SVGWrite(svg *SVGType, x, y, w, h float64) {
+	if f.err != nil || svg.root == nil {
+		return
+	}
+	if w == 0 && h == 0 {
+		w = svg.Wd * 72 / 96 / f.k
+		h = svg.Ht * 72 / 96 / f.k
+	} else if w == 0 {
+		w = h * svg.Wd / svg.Ht
+	} else if h == 0 {
+		h = w * svg.Ht / svg.Wd
+	}
+	vb := svg.viewBox
+	sx, sy := w*f.k/vb[2], h*f.k/vb[3]
+	tx, ty := x*f.k, (f.h-y)*f.k
+	if alignX, alignY, slice, ok := svgAspect(svg.aspect); ok {
+		s := math.Min(sx, sy)
+		if slice {
+			s = math.Max(sx, sy)
+		}
+		tx += (w*f.k - vb[2]*s) * alignX
+		ty -= (h*f.k - vb[3]*s) * alignY
+		sx, sy = s, s
+	}
+	f.out("q")
+	f.ctmPush()
+	f.outf("%.2f %.2f %.2f %.2f re W n", x*f.k, (f.h-y)*f.k, w*f.k, -h*f.k)
+	r := svgRenderer{f: f, svg: svg}
+	r.concat(TransformMatrix{A: sx, D: -sy, E: tx - vb[0]*sx, F: ty + vb[1]*sy})
+	r.children(svg.root, r.style(svg.root, newSVGStyle()), 0)
+	f.ctmPop()
+	f.out("Q")
+}
+
+// svgAspect parses a preserveAspectRatio value. It returns the alignment of
+// the view box within the viewport along each axis, from 0 for the minimum to
+// 1 for the maximum, and whether the view box is to cover the viewport rather
+// than fit in it. ok is false if the view box is to be stretched to the
+// viewport.
+func svgAspect(val string) (alignX, alignY float64, slice, ok bool) {
+	fields := strings.Fields(val)
+	if len(fields) > 0 && fields[0] == "defer" {
+		fields = fields[1:]
+	}
+	alignX, alignY = 0.5, 0.5
+	if len(fields) == 0 {
+		return alignX, alignY, false, true
+	}
+	align := fields[0]
+	if align == "none" {
+		return
+	}
+	if len(align) == 8 {
+		positions := map[string]float64{"Min": 0, "Mid": 0.5, "Max": 1}
+		x, okX := positions[align[1:4]]
+		y, okY := positions[align[5:8]]
+		if align[0] == 'x' && align[4] == 'Y' && okX && okY {
+			alignX, alignY = x, y
+		}
+	}
+	slice = len(fields) > 1 && fields[1] == "slice"
+	return alignX, alignY, slice, true
+}
+
+// svgPaint is a fill or stroke color. A paint that refers to a gradient has
+// the opacity of its first stop.
+type svgPaint struct {
+	none    bool
+	r, g, b float64
+	alpha   float64
+}
+
+// svgStyle holds the properties in effect for an element.
+type svgStyle struct {
+	color             svgPaint
+	fill, stroke      svgPaint
+	fillOpacity       float64
+	strokeOpacity     float64
+	opacity           float64
+	strokeWidth       float64
+	lineCap, lineJoin string
+	miterLimit        float64
+	dash              []float64
+	dashOffset        float64
+	evenOdd           bool
+	hidden            bool
+	fontFamily        string
+	fontSize          float64
+	bold, italic      bool
+	anchor            string
+}
+
+func newSVGStyle() svgStyle {
+	return svgStyle{
+		fill:          svgPaint{alpha: 1},
+		stroke:        svgPaint{none: true, alpha: 1},
+		color:         svgPaint{alpha: 1},
+		fillOpacity:   1,
+		strokeOpacity: 1,
+		opacity:       1,
+		strokeWidth:   1,
+		miterLimit:    4,
+		fontSize:      16,
+		anchor:        "start",
+	}
+}
+
+// svgRenderer writes the elements of an SVG image to the current page.
+type svgRenderer struct {
+	f   *Fpdf
+	svg *SVGType
+	tr  func(string) string
+}
+
+// Elements that reference others, such as use, are not followed beyond this
+// depth so that circular references terminate
+const svgMaxDepth = 16
+
+func (r *svgRenderer) children(node *svgNode, st svgStyle, depth int) {
+	for _, child := range node.children {
+		if r.f.err != nil {
+			return
+		}
+		r.element(child, st, depth)
+	}
+}
+
+func (r *svgRenderer) element(node *svgNode, parent svgStyle, depth int) {
+	switch node.name {
+	case "g", "svg", "use", "path", "rect", "circle", "ellipse", "line",
+		"polyline", "polygon", "text":
+	default:
+		return
+	}
+	st := r.style(node, parent)
+	if st.hidden {
+		return
+	}
+	f := r.f
+	f.out("q")
+	f.ctmPush()
+	defer func() {
+		f.ctmPop()
+		f.out("Q")
+	}()
+	if m, ok := svgTransform(node.attrs["transform"]); ok {
+		r.concat(TransformMatrix{A: m[0], B: m[1], C: m[2], D: m[3], E: m[4], F: m[5]})
+	}
+	if st.opacity < 1 {
+		// The element is painted opaque in a transparency group, which is
+		// painted with the opacity of the element
+		f.groupBegin(transparencyGroupNest{kindStr: "SVG element"})
+		defer func() {
+			if pos := f.groupEnd("SVG element"); pos > 0 {
+				f.outf("/GS%d gs", f.blendStatePos(st.opacity, st.opacity, "Normal"))
+				f.outf("/TG%d Do", pos)
+			}
+		}()
+	}
+	switch node.name {
+	case "g":
+		r.children(node, st, depth)
+	case "svg":
+		// Nested viewports are positioned but not scaled or clipped
+		x, y := r.length(node, "x", 0), r.length(node, "y", 1)
+		r.concat(TransformMatrix{A: 1, D: 1, E: x, F: y})
+		r.children(node, st, depth)
+	case "use":
+		ref := r.ref(node)
+		if ref == nil || depth >= svgMaxDepth {
+			return
+		}
+		x, y := r.length(node, "x", 0), r.length(node, "y", 1)
+		r.concat(TransformMatrix{A: 1, D: 1, E: x, F: y})
+		if ref.name == "symbol" {
+			r.children(ref, r.style(ref, st), depth+1)
+		} else {
+			r.element(ref, st, depth+1)
+		}
+	case "text":
+		r.text(node, st)
+	default:
+		r.paint(r.shape(node), st, node.name != "line")
+	}
+}
+
+// concat applies the transformation tm to the content that follows, in the
+// current coordinates of the image.
+func (r *svgRenderer) concat(tm TransformMatrix) {
+	f := r.f
+	f.outf("%s %s %s %s %s %s cm", svgNum(tm.A), svgNum(tm.B), svgNum(tm.C),
+		svgNum(tm.D), svgNum(tm.E), svgNum(tm.F))
+	f.ctm = TransformMatrix(MatrixType(tm).Multiply(MatrixType(f.ctm)))
+}
+
+// ref returns the element referenced by the href attribute of node.
+func (r *svgRenderer) ref(node *svgNode) *svgNode {
+	href := node.attrs["href"]
+	if !strings.HasPrefix(href, "#") {
+		return nil
+	}
+	return r.svg.ids[href[1:]]
+}
+
+// length returns the coordinate or length attribute key of node. Percentages
+// refer to the width (axis 0), height (axis 1) or diagonal (axis 2) of the
+// view box.
+func (r *svgRenderer) length(node *svgNode, key string, axis int) float64 {
+	vb := r.svg.viewBox
+	ref := vb[2]
+	switch axis {
+	case 1:
+		ref = vb[3]
+	case 2:
+		ref = math.Sqrt((vb[2]*vb[2] + vb[3]*vb[3]) / 2)
+	}
+	return svgLength(node.attrs[key], ref, 16)
+}
+
+// style returns the properties of node, given those of its parent. Style
+// attribute declarations take precedence over presentation attributes.
+func (r *svgRenderer) style(node *svgNode, parent svgStyle) (st svgStyle) {
+	st = parent
+	st.opacity = 1
+	props := make(map[string]string)
+	for key, val := range node.attrs {
+		props[key] = val
+	}
+	for _, decl := range strings.Split(node.attrs["style"], ";") {
+		if pos := strings.Index(decl, ":"); pos > 0 {
+			props[strings.TrimSpace(decl[:pos])] = strings.TrimSpace(decl[pos+1:])
+		}
+	}
+	if val, ok := props["font-size"]; ok {
+		if size := svgLength(val, parent.fontSize, parent.fontSize); size > 0 {
+			st.fontSize = size
+		}
+	}
+	if val, ok := props["color"]; ok {
+		if paint, ok := r.paintValue(val, parent.color); ok {
+			st.color = paint
+		}
+	}
+	for key, val := range props {
+		if val == "inherit" {
+			continue
+		}
+		switch key {
+		case "fill":
+			if paint, ok := r.paintValue(val, st.color); ok {
+				st.fill = paint
+			}
+		case "stroke":
+			if paint, ok := r.paintValue(val, st.color); ok {
+				st.stroke = paint
+			}
+		case "fill-opacity":
+			st.fillOpacity = svgOpacity(val)
+		case "stroke-opacity":
+			st.strokeOpacity = svgOpacity(val)
+		case "opacity":
+			st.opacity = svgOpacity(val)
+		case "stroke-width":
+			st.strokeWidth = svgLength(val, 1, st.fontSize)
+		case "stroke-linecap":
+			st.lineCap = val
+		case "stroke-linejoin":
+			st.lineJoin = val
+		case "stroke-miterlimit":
+			if v, err := strconv.ParseFloat(val, 64); err == nil && v >= 1 {
+				st.miterLimit = v
+			}
+		case "stroke-dasharray":
+			st.dash = nil
+			if list, ok := svgNumbers(val); ok {
+				st.dash = list
+			}
+		case "stroke-dashoffset":
+			st.dashOffset = svgLength(val, 1, st.fontSize)
+		case "fill-rule":
+			st.evenOdd = val == "evenodd"
+		case "display":
+			st.hidden = st.hidden || val == "none"
+		case "visibility":
+			// Visibility is approximated by hiding the element and its
+			// descendants
+			st.hidden = st.hidden || val == "hidden" || val == "collapse"
+		case "font-family":
+			st.fontFamily = val
+		case "font-weight":
+			st.bold = val == "bold" || val == "bolder" || val >= "600" && val <= "999"
+		case "font-style":
+			st.italic = val == "italic" || val == "oblique"
+		case "text-anchor":
+			st.anchor = val
+		}
+	}
+	return
+}
+
+// paintValue parses a fill or stroke value. The second return value is false
+// if the value is not recognized.
+func (r *svgRenderer) paintValue(val string, current svgPaint) (paint svgPaint, ok bool) {
+	val = strings.TrimSpace(val)
+	switch {
+	case val == "none":
+		return svgPaint{none: true, alpha: 1}, true
+	case val == "currentColor":
+		return current, true
+	case strings.HasPrefix(val, "url("):
+		end := strings.Index(val, ")")
+		if end < 0 {
+			return
+		}
+		id := strings.Trim(strings.TrimSpace(val[4:end]), `'"`)
+		if paint, ok = r.gradientPaint(strings.TrimPrefix(id, "#")); ok {
+			return
+		}
+		// Fall back to the color that follows the reference, if any
+		if fallback := strings.TrimSpace(val[end+1:]); fallback != "" {
+			return r.paintValue(fallback, current)
+		}
+		return svgPaint{none: true, alpha: 1}, true
+	}
+	return svgColor(val)
+}
+
+// gradientPaint approximates the gradient with the specified id by its first
+// stop. Stops inherited from another gradient through href are followed.
+func (r *svgRenderer) gradientPaint(id string) (paint svgPaint, ok bool) {
+	node := r.svg.ids[id]
+	for depth := 0; node != nil && depth < svgMaxDepth; depth++ {
+		if node.name != "linearGradient" && node.name != "radialGradient" {
+			return
+		}
+		for _, stop := range node.children {
+			if stop.name != "stop" {
+				continue
+			}
+			props := map[string]string{"stop-color": "black", "stop-opacity": "1"}
+			for key := range props {
+				if val, ok := stop.attrs[key]; ok {
+					props[key] = val
+				}
+			}
+			for _, decl := range strings.Split(stop.attrs["style"], ";") {
+				if pos := strings.Index(decl, ":"); pos > 0 {
+					props[strings.TrimSpace(decl[:pos])] = strings.TrimSpace(decl[pos+1:])
+				}
+			}
+			if paint, ok = svgColor(props["stop-color"]); ok {
+				paint.alpha = svgOpacity(props["stop-opacity"])
+			}
+			return
+		}
+		node = r.ref(node)
+	}
+	return
+}
+
+// shape returns the outline of a basic shape or path element as a list of
+// absolute M, L, C and Z segments.
+func (r *svgRenderer) shape(node *svgNode) (segs []svgSegment) {
+	switch node.name {
+	case "path":
+		segs = svgPathParse(node.attrs["d"])
+	case "rect":
+		x, y := r.length(node, "x", 0), r.length(node, "y", 1)
+		w, h := r.length(node, "width", 0), r.length(node, "height", 1)
+		if w <= 0 || h <= 0 {
+			return
+		}
+		_, okX := node.attrs["rx"]
+		_, okY := node.attrs["ry"]
+		rx, ry := r.length(node, "rx", 0), r.length(node, "ry", 1)
+		if !okX {
+			rx = ry
+		} else if !okY {
+			ry = rx
+		}
+		rx, ry = math.Min(math.Max(rx, 0), w/2), math.Min(math.Max(ry, 0), h/2)
+		if rx == 0 || ry == 0 {
+			return svgPolygon([]float64{x, y, x + w, y, x + w, y + h, x, y + h}, true)
+		}
+		segs = svgPathParse(sprintf("M%g %gH%gA%g %g 0 0 1 %g %gV%gA%g %g 0 0 1 %g %gH%g"+
+			"A%g %g 0 0 1 %g %gV%gA%g %g 0 0 1 %g %gZ",
+			x+rx, y, x+w-rx, rx, ry, x+w, y+ry, y+h-ry, rx, ry, x+w-rx, y+h, x+rx,
+			rx, ry, x, y+h-ry, y+ry, rx, ry, x+rx, y))
+	case "circle", "ellipse":
+		cx, cy := r.length(node, "cx", 0), r.length(node, "cy", 1)
+		var rx, ry float64
+		if node.name == "circle" {
+			rx = r.length(node, "r", 2)
+			ry = rx
+		} else {
+			rx, ry = r.length(node, "rx", 0), r.length(node, "ry", 1)
+		}
+		if rx <= 0 || ry <= 0 {
+			return
+		}
+		segs = svgPathParse(sprintf("M%g %gA%g %g 0 0 1 %g %gA%g %g 0 0 1 %g %gZ",
+			cx+rx, cy, rx, ry, cx-rx, cy, rx, ry, cx+rx, cy))
+	case "line":
+		segs = svgPolygon([]float64{r.length(node, "x1", 0), r.length(node, "y1", 1),
+			r.length(node, "x2", 0), r.length(node, "y2", 1)}, false)
+	case "polyline", "polygon":
+		if list, ok := svgNumbers(node.attrs["points"]); ok {
+			segs = svgPolygon(list[:len(list)&^1], node.name == "polygon")
+		}
+	}
+	return
+}
+
+// paint outputs the outline specified by segs and fills and strokes it as
+// specified by st. Lines are never filled.
+func (r *svgRenderer) paint(segs []svgSegment, st svgStyle, fillable bool) {
+	f := r.f
+	fill := fillable && !st.fill.none
+	stroke := !st.stroke.none && st.strokeWidth > 0
+	if len(segs) == 0 || !fill && !stroke {
+		return
+	}
+	r.setAlpha(st.fillOpacity*st.fill.alpha, st.strokeOpacity*st.stroke.alpha)
+	if fill {
+		f.outf("%.3f %.3f %.3f rg", st.fill.r, st.fill.g, st.fill.b)
+	}
+	if stroke {
+		f.outf("%.3f %.3f %.3f RG", st.stroke.r, st.stroke.g, st.stroke.b)
+		f.outf("%s w", svgNum(st.strokeWidth))
+		switch st.lineCap {
+		case "round":
+			f.out("1 J")
+		case "square":
+			f.out("2 J")
+		default:
+			f.out("0 J")
+		}
+		switch st.lineJoin {
+		case "round":
+			f.out("1 j")
+		case "bevel":
+			f.out("2 j")
+		default:
+			f.out("0 j")
+		}
+		f.outf("%s M", svgNum(st.miterLimit))
+		if len(st.dash) > 0 {
+			list := make([]string, len(st.dash))
+			for j, v := range st.dash {
+				list[j] = svgNum(v)
+			}
+			dashStr := strings.Join(list, " ")
+			if len(list)%2 == 1 {
+				// An odd list is repeated to yield an even one
+				dashStr += " " + dashStr
+			}
+			f.outf("[%s] %s d", dashStr, svgNum(st.dashOffset))
+		}
+	}
+	var buf bytes.Buffer
+	for _, seg := range segs {
+		switch seg.cmd {
+		case 'M':
+			fmt.Fprintf(&buf, "%s %s m ", svgNum(seg.arg[0]), svgNum(seg.arg[1]))
+		case 'L':
+			fmt.Fprintf(&buf, "%s %s l ", svgNum(seg.arg[0]), svgNum(seg.arg[1]))
+		case 'C':
+			fmt.Fprintf(&buf, "%s %s %s %s %s %s c ", svgNum(seg.arg[0]), svgNum(seg.arg[1]),
+				svgNum(seg.arg[2]), svgNum(seg.arg[3]), svgNum(seg.arg[4]), svgNum(seg.arg[5]))
+		case 'Z':
+			buf.WriteString("h ")
+		}
+	}
+	op := "S"
+	switch {
+	case fill && stroke:
+		op = "B"
+	case fill:
+		op = "f"
+	}
+	if fill && st.evenOdd {
+		op += "*"
+	}
+	buf.WriteString(op)
+	f.out(buf.String())
+}
+
+// setAlpha selects a graphics state with the specified fill and stroke
+// opacity if either is less than one.
+func (r *svgRenderer) setAlpha(fillAlpha, strokeAlpha float64) {
+	if fillAlpha >= 1 && strokeAlpha >= 1 {
+		return
+	}
+	r.f.outf("/GS%d gs", r.f.blendStatePos(math.Max(fillAlpha, 0), math.Max(strokeAlpha, 0), "Normal"))
+}
+
+// text outputs the content of a text element on a single line with one of the
+// core fonts, chosen by the generic family of the font-family property.
+func (r *svgRenderer) text(node *svgNode, st svgStyle) {
+	f := r.f
+	txt := strings.Join(strings.Fields(node.text), " ")
+	if txt == "" || st.fill.none {
+		return
+	}
+	family := "Helvetica"
+families:
+	for _, name := range strings.Split(strings.ToLower(st.fontFamily), ",") {
+		switch strings.Trim(strings.TrimSpace(name), `'"`) {
+		case "serif", "times", "times new roman", "georgia":
+			family = "Times"
+		case "monospace", "courier", "courier new":
+			family = "Courier"
+		case "sans-serif", "helvetica", "arial":
+		default:
+			continue
+		}
+		break families
+	}
+	styleStr := ""
+	if st.bold {
+		styleStr += "B"
+	}
+	if st.italic {
+		styleStr += "I"
+	}
+	if r.tr == nil {
+		r.tr = f.UnicodeTranslatorFromDescriptor("")
+	}
+	txt = r.tr(txt)
+	// The font is selected for its metrics and the selection of the document
+	// is restored afterward; the font operator is undone by the enclosing
+	// graphics state
+	fontFamily, fontStyle, fontSizePt, fontSize := f.fontFamily, f.fontStyle, f.fontSizePt, f.fontSize
+	currentFont, isCurrentUTF8 := f.currentFont, f.isCurrentUTF8
+	underline, strikeout := f.underline, f.strikeout
+	f.SetFont(family, styleStr, st.fontSize)
+	if f.err != nil {
+		return
+	}
+	fontIndex := f.currentFont.i
+	width := f.GetStringWidth(txt) * f.k
+	f.fontFamily, f.fontStyle, f.fontSizePt, f.fontSize = fontFamily, fontStyle, fontSizePt, fontSize
+	f.currentFont, f.isCurrentUTF8 = currentFont, isCurrentUTF8
+	f.underline, f.strikeout = underline, strikeout
+	var x, y float64
+	if list, ok := svgNumbers(node.attrs["x"]); ok && len(list) > 0 {
+		x = list[0]
+	}
+	if list, ok := svgNumbers(node.attrs["y"]); ok && len(list) > 0 {
+		y = list[0]
+	}
+	switch st.anchor {
+	case "middle":
+		x -= width / 2
+	case "end":
+		x -= width
+	}
+	r.setAlpha(st.fillOpacity*st.fill.alpha, 1)
+	f.outf("%.3f %.3f %.3f rg", st.fill.r, st.fill.g, st.fill.b)
+	// The text matrix flips the text upright in the downward y axis of SVG
+	f.outf("BT /F%s %s Tf 1 0 0 -1 %s %s Tm (%s) Tj ET", fontIndex, svgNum(st.fontSize),
+		svgNum(x), svgNum(y), f.escape(txt))
+}
+
+// svgSegment is a segment of an outline in absolute coordinates. The command
+// is one of M (move), L (line), C (cubic Bézier curve) or Z (close).
+type svgSegment struct {
+	cmd byte
+	arg [6]float64
+}
+
+// svgPolygon returns the outline through the points in list, a sequence of
+// coordinate pairs.
+func svgPolygon(list []float64, closed bool) (segs []svgSegment) {
+	for j := 0; j+1 < len(list); j += 2 {
+		cmd := byte('L')
+		if j == 0 {
+			cmd = 'M'
+		}
+		segs = append(segs, svgSegment{cmd: cmd, arg: [6]float64{list[j], list[j+1]}})
+	}
+	if closed && len(segs) > 0 {
+		segs = append(segs, svgSegment{cmd: 'Z'})
+	}
+	return
+}
+
+// svgScanner reads the numbers and flags of path data and number lists.
+type svgScanner struct {
+	s   string
+	pos int
+}
+
+func (sc *svgScanner) skip() {
+	for sc.pos < len(sc.s) && strings.IndexByte(" \t\r\n,", sc.s[sc.pos]) >= 0 {
+		sc.pos++
+	}
+}
+
+// number reads the next number. Numbers need not be separated when the
+// boundary is unambiguous, as in "1-2" or "1.5.5".
+func (sc *svgScanner) number() (v float64, ok bool) {
+	sc.skip()
+	start := sc.pos
+	j := sc.pos
+	if j < len(sc.s) && (sc.s[j] == '+' || sc.s[j] == '-') {
+		j++
+	}
+	digits, dot := false, false
+	for ; j < len(sc.s); j++ {
+		c := sc.s[j]
+		if c >= '0' && c <= '9' {
+			digits = true
+		} else if c == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+	}
+	if !digits {
+		return 0, false
+	}
+	if j < len(sc.s) && (sc.s[j] == 'e' || sc.s[j] == 'E') {
+		k := j + 1
+		if k < len(sc.s) && (sc.s[k] == '+' || sc.s[k] == '-') {
+			k++
+		}
+		if k < len(sc.s) && sc.s[k] >= '0' && sc.s[k] <= '9' {
+			for k < len(sc.s) && sc.s[k] >= '0' && sc.s[k] <= '9' {
+				k++
+			}
+			j = k
+		}
+	}
+	v, err := strconv.ParseFloat(sc.s[start:j], 64)
+	if err != nil {
+		return 0, false
+	}
+	sc.pos = j
+	return v, true
+}
+
+// flag reads an arc flag, which may be immediately followed by the next
+// argument, as in "a1 1 0 015 5".
+func (sc *svgScanner) flag() (v bool, ok bool) {
+	sc.skip()
+	if sc.pos < len(sc.s) && (sc.s[sc.pos] == '0' || sc.s[sc.pos] == '1') {
+		v = sc.s[sc.pos] == '1'
+		sc.pos++
+		return v, true
+	}
+	return false, false
+}
+
+// svgNumbers returns the numbers in a list separated by white space or
+// commas. The second return value is false if the list is empty or contains
+// anything other than numbers.
+func svgNumbers(s string) (list []float64, ok bool) {
+	sc := svgScanner{s: s}
+	for {
+		v, ok := sc.number()
+		if !ok {
+			break
+		}
+		list = append(list, v)
+	}
+	sc.skip()
+	return list, len(list) > 0 && sc.pos == len(s)
+}
+
+// svgPathParse converts path data to absolute M, L, C and Z segments. Parsing
+// stops at the first error, as specified for SVG path data, and the segments
+// read up to that point are returned.
+func svgPathParse(d string) (segs []svgSegment) {
+	sc := svgScanner{s: d}
+	var cmd byte
+	var x, y, startX, startY float64
+	// Reflected control point for S and T commands
+	var ctrlX, ctrlY float64
+	var prev byte
+	var arg [7]float64
+	read := func(n int) bool {
+		for j := 0; j < n; j++ {
+			v, ok := sc.number()
+			if !ok {
+				return false
+			}
+			arg[j] = v
+		}
+		return true
+	}
+	curve := func(x1, y1, x2, y2, x3, y3 float64) {
+		segs = append(segs, svgSegment{cmd: 'C', arg: [6]float64{x1, y1, x2, y2, x3, y3}})
+	}
+	for {
+		sc.skip()
+		if sc.pos >= len(d) {
+			return
+		}
+		c := d[sc.pos]
+		if c >= 'A' && c <= 'z' && c != 'e' && c != 'E' {
+			cmd = c
+			sc.pos++
+		} else if cmd == 0 || cmd == 'Z' || cmd == 'z' {
+			return
+		}
+		rel := cmd >= 'a'
+		var dx, dy float64
+		if rel {
+			dx, dy = x, y
+		}
+		upper := cmd &^ 0x20
+		switch upper {
+		case 'M':
+			if !read(2) {
+				return
+			}
+			x, y = arg[0]+dx, arg[1]+dy
+			startX, startY = x, y
+			segs = append(segs, svgSegment{cmd: 'M', arg: [6]float64{x, y}})
+			// Subsequent pairs are implicit line commands
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L':
+			if !read(2) {
+				return
+			}
+			x, y = arg[0]+dx, arg[1]+dy
+			segs = append(segs, svgSegment{cmd: 'L', arg: [6]float64{x, y}})
+		case 'H':
+			if !read(1) {
+				return
+			}
+			x = arg[0] + dx
+			segs = append(segs, svgSegment{cmd: 'L', arg: [6]float64{x, y}})
+		case 'V':
+			if !read(1) {
+				return
+			}
+			y = arg[0] + dy
+			segs = append(segs, svgSegment{cmd: 'L', arg: [6]float64{x, y}})
+		case 'C':
+			if !read(6) {
+				return
+			}
+			curve(arg[0]+dx, arg[1]+dy, arg[2]+dx, arg[3]+dy, arg[4]+dx, arg[5]+dy)
+			ctrlX, ctrlY = arg[2]+dx, arg[3]+dy
+			x, y = arg[4]+dx, arg[5]+dy
+		case 'S':
+			if !read(4) {
+				return
+			}
+			x1, y1 := x, y
+			if prev == 'C' || prev == 'S' {
+				x1, y1 = 2*x-ctrlX, 2*y-ctrlY
+			}
+			curve(x1, y1, arg[0]+dx, arg[1]+dy, arg[2]+dx, arg[3]+dy)
+			ctrlX, ctrlY = arg[0]+dx, arg[1]+dy
+			x, y = arg[2]+dx, arg[3]+dy
+		case 'Q', 'T':
+			var qx, qy, nx, ny float64
+			if upper == 'Q' {
+				if !read(4) {
+					return
+				}
+				qx, qy, nx, ny = arg[0]+dx, arg[1]+dy, arg[2]+dx, arg[3]+dy
+			} else {
+				if !read(2) {
+					return
+				}
+				qx, qy = x, y
+				if prev == 'Q' || prev == 'T' {
+					qx, qy = 2*x-ctrlX, 2*y-ctrlY
+				}
+				nx, ny = arg[0]+dx, arg[1]+dy
+			}
+			// A quadratic curve is raised to a cubic one
+			curve(x+2*(qx-x)/3, y+2*(qy-y)/3, nx+2*(qx-nx)/3, ny+2*(qy-ny)/3, nx, ny)
+			ctrlX, ctrlY = qx, qy
+			x, y = nx, ny
+		case 'A':
+			if !read(3) {
+				return
+			}
+			rx, ry, phi := arg[0], arg[1], arg[2]
+			large, ok1 := sc.flag()
+			sweep, ok2 := sc.flag()
+			if !ok1 || !ok2 || !read(2) {
+				return
+			}
+			nx, ny := arg[0]+dx, arg[1]+dy
+			for _, c := range svgArc(x, y, rx, ry, phi, large, sweep, nx, ny) {
+				curve(c[0], c[1], c[2], c[3], c[4], c[5])
+			}
+			x, y = nx, ny
+		case 'Z':
+			segs = append(segs, svgSegment{cmd: 'Z'})
+			x, y = startX, startY
+		default:
+			return
+		}
+		prev = upper
+	}
+}
+
+// svgArc converts the elliptical arc from (x1, y1) to (x2, y2) to cubic
+// Bézier curves of at most a quarter turn each, following the conversion from
+// endpoint to center parameterization in the SVG specification. A straight
+// line is returned if a radius is zero.
+func svgArc(x1, y1, rx, ry, phiDeg float64, large, sweep bool, x2, y2 float64) (list [][6]float64) {
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return [][6]float64{{x1, y1, x2, y2, x2, y2}}
+	}
+	sinPhi, cosPhi := math.Sincos(phiDeg * math.Pi / 180)
+	hx, hy := (x1-x2)/2, (y1-y2)/2
+	x1p := cosPhi*hx + sinPhi*hy
+	y1p := -sinPhi*hx + cosPhi*hy
+	// Radii that are too small are scaled up to just reach the end point
+	if lambda := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); lambda > 1 {
+		rx *= math.Sqrt(lambda)
+		ry *= math.Sqrt(lambda)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+	den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+	coef := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		coef = -coef
+	}
+	cxp, cyp := coef*rx*y1p/ry, -coef*ry*x1p/rx
+	cx := cosPhi*cxp - sinPhi*cyp + (x1+x2)/2
+	cy := sinPhi*cxp + cosPhi*cyp + (y1+y2)/2
+	theta := math.Atan2((y1p-cyp)/ry, (x1p-cxp)/rx)
+	delta := math.Atan2((-y1p-cyp)/ry, (-x1p-cxp)/rx) - theta
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+	n := math.Ceil(math.Abs(delta)/(math.Pi/2) - 1e-9)
+	step := delta / n
+	t := 4.0 / 3.0 * math.Tan(step/4)
+	pt := func(ux, uy float64) (float64, float64) {
+		return cx + rx*cosPhi*ux - ry*sinPhi*uy, cy + rx*sinPhi*ux + ry*cosPhi*uy
+	}
+	for j := 0; j < int(n); j++ {
+		a1 := theta + float64(j)*step
+		a2 := a1 + step
+		sin1, cos1 := math.Sincos(a1)
+		sin2, cos2 := math.Sincos(a2)
+		c1x, c1y := pt(cos1-t*sin1, sin1+t*cos1)
+		c2x, c2y := pt(cos2+t*sin2, sin2-t*cos2)
+		ex, ey := pt(cos2, sin2)
+		if j == int(n)-1 {
+			ex, ey = x2, y2
+		}
+		list = append(list, [6]float64{c1x, c1y, c2x, c2y, ex, ey})
+	}
+	return
+}
+
+var svgTransformRe = regexp.MustCompile(`([a-zA-Z]+)\s*\(([^)]*)\)`)
+
+// svgTransform returns the matrix [a b c d e f] of a transform attribute. The
+// second return value is false if the attribute is empty or invalid.
+func svgTransform(s string) (m [6]float64, ok bool) {
+	m = [6]float64{1, 0, 0, 1, 0, 0}
+	matches := svgTransformRe.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return
+	}
+	for _, match := range matches {
+		args, _ := svgNumbers(match[2])
+		var t [6]float64
+		switch {
+		case match[1] == "matrix" && len(args) == 6:
+			copy(t[:], args)
+		case match[1] == "translate" && len(args) == 1:
+			t = [6]float64{1, 0, 0, 1, args[0], 0}
+		case match[1] == "translate" && len(args) == 2:
+			t = [6]float64{1, 0, 0, 1, args[0], args[1]}
+		case match[1] == "scale" && len(args) == 1:
+			t = [6]float64{args[0], 0, 0, args[0], 0, 0}
+		case match[1] == "scale" && len(args) == 2:
+			t = [6]float64{args[0], 0, 0, args[1], 0, 0}
+		case match[1] == "rotate" && (len(args) == 1 || len(args) == 3):
+			sin, cos := math.Sincos(args[0] * math.Pi / 180)
+			t = [6]float64{cos, sin, -sin, cos, 0, 0}
+			if len(args) == 3 {
+				// Rotation about (cx, cy)
+				cx, cy := args[1], args[2]
+				t[4] = cx - cos*cx + sin*cy
+				t[5] = cy - sin*cx - cos*cy
+			}
+		case match[1] == "skewX" && len(args) == 1:
+			t = [6]float64{1, 0, math.Tan(args[0] * math.Pi / 180), 1, 0, 0}
+		case match[1] == "skewY" && len(args) == 1:
+			t = [6]float64{1, math.Tan(args[0] * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			return m, false
+		}
+		// Transforms listed later apply first
+		m = [6]float64{
+			m[0]*t[0] + m[2]*t[1],
+			m[1]*t[0] + m[3]*t[1],
+			m[0]*t[2] + m[2]*t[3],
+			m[1]*t[2] + m[3]*t[3],
+			m[0]*t[4] + m[2]*t[5] + m[4],
+			m[1]*t[4] + m[3]*t[5] + m[5],
+		}
+	}
+	return m, true
+}
+
+// svgLength converts a length with an optional unit to pixels. Percentages
+// are taken of ref and em units of fontSize. Zero is returned for an empty or
+// invalid value.
+func svgLength(s string, ref, fontSize float64) float64 {
+	s = strings.TrimSpace(s)
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"%", ref / 100}, {"px", 1}, {"pt", 96.0 / 72}, {"pc", 16},
+		{"mm", 96 / 25.4}, {"cm", 96 / 2.54}, {"in", 96}, {"em", fontSize},
+		{"ex", fontSize / 2},
+	}
+	scale := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			scale = u.scale
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return v * scale
+}
+
+// svgOpacity parses an opacity value, a number or percentage clamped to the
+// range 0 to 1.
+func svgOpacity(s string) float64 {
+	v := svgLength(s, 1, 0)
+	if strings.TrimSpace(s) == "" {
+		v = 1
+	}
+	return math.Min(math.Max(v, 0), 1)
+}
+
+var svgColorNames = map[string]string{
+	"black": "#000000", "white": "#ffffff", "red": "#ff0000", "lime": "#00ff00",
+	"green": "#008000", "blue": "#0000ff", "yellow": "#ffff00", "cyan": "#00ffff",
+	"aqua": "#00ffff", "magenta": "#ff00ff", "fuchsia": "#ff00ff", "gray": "#808080",
+	"grey": "#808080", "silver": "#c0c0c0", "maroon": "#800000", "olive": "#808000",
+	"navy": "#000080", "purple": "#800080", "teal": "#008080", "orange": "#ffa500",
+	"brown": "#a52a2a", "pink": "#ffc0cb", "gold": "#ffd700", "lightgray": "#d3d3d3",
+	"lightgrey": "#d3d3d3", "darkgray": "#a9a9a9", "darkgrey": "#a9a9a9",
+	"transparent": "none",
+}
+
+// svgColor parses a color in hexadecimal, rgb() or named form. The second
+// return value is false if the color is not recognized.
+func svgColor(s string) (paint svgPaint, ok bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if name, found := svgColorNames[s]; found {
+		s = name
+	}
+	paint.alpha = 1
+	switch {
+	case s == "none":
+		paint.none = true
+		return paint, true
+	case strings.HasPrefix(s, "#"):
+		hex := s[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 {
+			return
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return
+		}
+		paint.r = float64(v>>16) / 255
+		paint.g = float64(v>>8&0xff) / 255
+		paint.b = float64(v&0xff) / 255
+		return paint, true
+	case strings.HasPrefix(s, "rgb(") && strings.HasSuffix(s, ")"):
+		parts := strings.Split(s[4:len(s)-1], ",")
+		if len(parts) != 3 {
+			return
+		}
+		var c [3]float64
+		for j, part := range parts {
+			part = strings.TrimSpace(part)
+			if strings.HasSuffix(part, "%") {
+				c[j] = svgLength(part, 1, 0)
+			} else {
+				v, err := strconv.ParseFloat(part, 64)
+				if err != nil {
+					return
+				}
+				c[j] = v / 255
+			}
+			c[j] = math.Min(math.Max(c[j], 0), 1)
+		}
+		paint.r, paint.g, paint.b = c[0], c[1], c[2]
+		return paint, true
+	}
+	return
+}
+
+// svgNum formats a coordinate compactly with up to four decimal places.
+func svgNum(v float64) string {
+	v = math.Round(v*1e4) / 1e4
+	if v == 0 {
+		// Avoid negative zero
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// symbolType is a symbol defined with DefineSymbol(), output as a form
+// XObject.
+type symbolType struct {
+	content []byte
+	h       float64   // Height of the page on which the symbol is defined
+	bbox    boundsBox // Bounds of the content, in points
+	objNum  int
+}
+
+// DefineSymbol defines the symbol nameStr, such as a map marker, an icon or a
+// bullet, as the text, drawings and images that fnc draws with the methods of
+// f, so that it can be drawn any number of times with UseSymbol(). The
+// content of the symbol is output once, as a form XObject to which each use
+// of the symbol refers, however many times it is used and on whatever page.
+// Nothing is drawn on the page by this method.
+//
+// The symbol is drawn in the coordinates of the page, around its origin, the
+// point placed where the symbol is used, which is the upper left corner of
+// the page: a marker centered on the point where it is used is drawn around
+// the point (0, 0). Drawing begins with the colors, the line width, the font
+// and the other parameters of the graphics state in effect when this method
+// is called, except for the alpha and the blend mode, which are those in
+// effect where the symbol is used unless they are set within fnc. These
+// parameters and the position are restored once fnc returns, so that
+// defining a symbol has no effect on what follows. The symbol is clipped to
+// the bounds of its content, as MeasureBounds() measures them, widened by a
+// point on each side.
+//
+// A symbol is defined while a page is open, and automatic page breaks are
+// disabled while fnc is called. The clipping operations, transformations,
+// transparency groups and graphics states that fnc begins must be ended
+// within it. Symbols can be used in other symbols, but each name can be
+// defined only once.
+func (f *Fpdf) DefineSymbol(nameStr string, fnc func()) {
+	if f.err != nil {
+		return
+	}
+	if _, ok := f.symbolMap[nameStr]; ok {
+		f.SetErrorf("symbol %q is already defined", nameStr)
+		return
+	}
+	// The boxes of text are written in the content, as they are when it is
+	// measured, so that the bounds of the symbol hold its text
+	content, ok := f.captureContent("symbol", func() {
+		f.measuring = true
+		f.groupStatePut()
+		fnc()
+	})
+	if !ok {
+		return
+	}
+	f.symbolList = append(f.symbolList, symbolType{content: content, h: f.h,
+		bbox: f.contentBounds(content, boundsState{ctm: TransformMatrix{A: 1, D: 1}, lineWidth: 1})})
+	f.symbolMap[nameStr] = len(f.symbolList) - 1
+}
+
+// UseSymbol draws the symbol nameStr defined with DefineSymbol() with its
+// origin at point (x, y), scaled by the factor scale around its origin and
+// rotated counter-clockwise by degRotate degrees around it. A scale of 1
+// draws the symbol at the size at which it was defined. The transformations
+// in effect apply to the symbol as to other content.
+func (f *Fpdf) UseSymbol(nameStr string, x, y, scale, degRotate float64) {
+	f.UseSymbolTransform(nameStr, IdentityMatrix().Scale(scale, scale).Rotate(degRotate).Translate(x, y))
+}
+
+// UseSymbolTransform draws the symbol nameStr defined with DefineSymbol()
+// transformed by m, which maps its coordinates to those of the page, such as
+// to mirror or skew it.
+//
+// The UseSymbol() example demonstrates this method.
+func (f *Fpdf) UseSymbolTransform(nameStr string, m MatrixType) {
+	if f.err != nil {
+		return
+	}
+	pos, ok := f.symbolMap[nameStr]
+	if !ok {
+		f.SetErrorf("undefined symbol %q", nameStr)
+		return
+	}
+	if f.page == 0 {
+		f.SetErrorf("no page for the symbol %q", nameStr)
+		return
+	}
+	if !f.matrixValid(m) {
+		return
+	}
+	// The content of the symbol is in the points of the page on which it
+	// was defined, which is moved to the bottom of this page if their
+	// heights differ
+	tm := MatrixType{A: 1, D: 1, F: (f.h - f.symbolList[pos].h) * f.k}.Multiply(MatrixType(f.pdfMatrix(m)))
+	f.outf("q %.5f %.5f %.5f %.5f %.5f %.5f cm /SY%d Do Q", tm.A, tm.B, tm.C, tm.D, tm.E, tm.F, pos)
+}
+
+func (f *Fpdf) putSymbols() {
+	for j := 1; j < len(f.symbolList); j++ {
+		sym := f.symbolList[j]
+		content := sym.content
+		filter := ""
+		if f.compress {
+			content = sliceCompress(content)
+			filter = "/Filter /FlateDecode "
+		}
+		var bbox [4]float64
+		if b := sym.bbox; b.set {
+			bbox = [4]float64{b.x0 - 1, b.y0 - 1, b.x1 + 1, b.y1 + 1}
+		}
+		f.newobj()
+		f.symbolList[j].objNum = f.n
+		f.outf("<<%s/Type /XObject /Subtype /Form /BBox [%.2f %.2f %.2f %.2f] /Resources 2 0 R",
+			filter, bbox[0], bbox[1], bbox[2], bbox[3])
+		f.outf("/Length %d>>", len(content))
+		f.putstream(content)
+		f.out("endobj")
+	}
+}
+
+func (f *Fpdf) symbolPutXObjectDict() {
+	for j := 1; j < len(f.symbolList); j++ {
+		f.outf("/SY%d %d 0 R", j, f.symbolList[j].objNum)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"strings"
+)
+
+const (
+	// syntheticSkew is the horizontal skew of synthetic italics, the tangent
+	// of their slant of 12 degrees
+	syntheticSkew = 0.21256
+	// syntheticStroke is the width of the outline stroked around the glyphs
+	// of synthetic bold text, relative to the font size
+	syntheticStroke = 0.03
+)
+
+// SetSyntheticStyle enables or disables synthetic styles, which are disabled
+// by default. When enabled, SetFont() selects a font that has been added for
+// a family but not in the requested style, such as a font family of which
+// only the regular style has been added, and synthesizes the missing styles.
+// Bold text is emboldened by stroking the outlines of its glyphs with the
+// text color in addition to filling them, and italic text is slanted by a
+// skew of 12 degrees. "BI" is synthesized from the bold or italic style if
+// the family has one of them, and from the regular style otherwise.
+//
+// The widths of synthesized text are those of the font it is synthesized
+// from. The core fonts, which have all their styles, are not affected.
+func (f *Fpdf) SetSyntheticStyle(enabled bool) {
+	f.syntheticStyle = enabled
+}
+
+// syntheticFontKey returns the key of the font from which the styles of
+// styleStr that the family lacks can be synthesized, and those styles. An
+// empty key is returned if there is no such font.
+func (f *Fpdf) syntheticFontKey(familyStr, styleStr string) (fontKey, synthStr string) {
+	if !f.syntheticStyle || f.coreFonts[familyStr] || familyStr == "arial" {
+		return
+	}
+	for _, baseStr := range []string{strings.Replace(styleStr, "I", "", 1), strings.Replace(styleStr, "B", "", 1), ""} {
+		if _, ok := f.fonts[familyStr+baseStr]; ok && baseStr != styleStr {
+			for _, s := range []string{"B", "I"} {
+				if strings.Contains(styleStr, s) && !strings.Contains(baseStr, s) {
+					synthStr += s
+				}
+			}
+			return familyStr + baseStr, synthStr
+		}
+	}
+	return
+}
+
+// textOrigin returns the operator that places the origin of the text of a
+// text object at (x, y), in points. Text with a synthetic italic style is
+// skewed about its origin.
+func (f *Fpdf) textOrigin(x, y float64) string {
+	if strings.Contains(f.fontSynthStr, "I") {
+		return sprintf("1 0 %.5f 1 %.2f %.2f Tm", syntheticSkew, x, y)
+	}
+	return sprintf("%.2f %.2f Td", x, y)
+}
+
+// syntheticBold returns the operators, followed by a space, that set up the
+// stroking of text with a synthetic bold style, or an empty string if the
+// current font is not emboldened. The operators change the graphics state,
+// which is to be saved before them. Text is emboldened only in the fill
+// mode of SetTextRenderingMode(), as other modes stroke text themselves or
+// do not show it.
+func (f *Fpdf) syntheticBold() string {
+	if f.textMode != TextModeFill || !strings.Contains(f.fontSynthStr, "B") {
+		return ""
+	}
+	// The stroking color operators are the upper case forms of the
+	// nonstroking ones
+	ops := strings.Fields(f.color.text.str)
+	for j, op := range ops {
+		switch op {
+		case "g", "rg", "k", "cs", "sc", "scn":
+			ops[j] = strings.ToUpper(op)
+		}
+	}
+	return sprintf("%s %.2f w 2 Tr ", strings.Join(ops, " "), syntheticStroke*f.fontSizePt)
+}
+
+// syntheticMode returns the text rendering mode of visible text in the
+// current font, which strokes text of a synthetic bold style.
+func (f *Fpdf) syntheticMode() int {
+	if f.textMode == TextModeFill && strings.Contains(f.fontSynthStr, "B") {
+		return TextModeFillStroke
+	}
+	return f.textMode
+}
@@ -0,0 +1,740 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"strings"
+)
+
+// TableType lays out tables, one row below the other, whose cells wrap their
+// text as MultiCell() does and may span several columns and rows. The height
+// of each row is that of its highest cell. A row that does not fit on the
+// current page is moved to the next page, on which the header rows of the
+// table are printed again. Rows are not split across pages unless set with
+// SetRowSplit(), and rows that cells span are kept together. See TableNew() to create a receiver that is
+// associated with the PDF document instance.
+type TableType struct {
+	pdf       *Fpdf
+	cols      []TableColumnType
+	w         float64 // Width of the table, 0 for the width between the margins
+	minW      []float64
+	maxW      []float64
+	lineHt    float64
+	padX      float64 // Padding on the left and right of the text of cells
+	padY      float64 // Padding above and below the text of cells
+	borderStr string
+	fills     []RGBType // Backgrounds of the rows in turn
+	styleFnc  func(row, col int) TableCellStyleType
+	split     bool // Rows are split across pages
+	headers   []tableRow
+	pending   []tableRow // Rows that wait for the last row that their cells span
+	spanEnd   int        // Number of pending rows that their cells span
+	rows      int        // Number of rows printed other than header rows
+}
+
+// TableColumnType defines a column of a table laid out by TableType.
+type TableColumnType struct {
+	// The width of the column, in the unit of measure specified in New(), or
+	// if Width is 0, the percentage Percent of the width of the table. The
+	// columns of neither width are sized to fit their content, as measured
+	// by FitColumns(), in the width left by the other columns, or share it
+	// equally if their content is not measured.
+	Width   float64
+	Percent float64
+	// The alignment of the text of the cells of the column: "L", "C" or "R"
+	// for left, centered or right alignment, or "J" for justified lines as
+	// with MultiCell(), combined with "T", "M" or "B" for top, middle or
+	// bottom vertical alignment, as in "RM". Text is aligned to the top left
+	// by default.
+	AlignStr string
+	// The background color of the cells of the column, which have none if
+	// nil.
+	FillColor *RGBType
+}
+
+// TableCellType is a cell of a table row printed by TableType.
+type TableCellType struct {
+	// The text of the cell, printed with the current font and text color, or
+	// the spans of text printed as with MultiCellSpans() if Spans is not nil.
+	Text  string
+	Spans []TextSpan
+	// The number of columns and rows that the cell spans, 1 if 0. A cell that
+	// spans rows takes its columns in the rows that follow, whose cells are
+	// set in the columns that are left, and is as high as these rows
+	// together, the last of which is made higher if the text of the cell
+	// needs it.
+	ColSpan, RowSpan int
+	// The alignment and background color of the cell, those of the column
+	// of the cell if empty or nil.
+	AlignStr  string
+	FillColor *RGBType
+}
+
+// TableCellStyleType is the style of a table cell returned by the function
+// set with SetCellStyleFunc(). Fields left empty or nil leave the style of
+// the cell unchanged.
+type TableCellStyleType struct {
+	// The background and text colors of the cell. The text color of spans
+	// that have their own prevails.
+	FillColor, TextColor *RGBType
+	// The alignment of the text of the cell, as for the AlignStr field of
+	// TableColumnType.
+	AlignStr string
+	// The borders of the cell, as for SetBorder(), or "0" for none.
+	BorderStr string
+}
+
+// tableRow is a row of a table and the font and text color with which it is
+// printed.
+type tableRow struct {
+	cells  []TableCellType
+	st     spanStateType
+	header bool
+}
+
+// TableNew returns an instance that lays out tables of the columns cols,
+// whose text is printed in lines of height lineHt, in the unit of measure
+// specified in New(). If lineHt is 0, the line height set with
+// SetLineHeight() or SetLineHeightFactor() when a row is printed is used.
+// Tables begin at the left margin. Rows are at least one line high. Cells are
+// padded horizontally by the cell margin and have no borders or backgrounds
+// by default.
+func (f *Fpdf) TableNew(lineHt float64, cols ...TableColumnType) (tbl TableType) {
+	tbl.pdf = f
+	tbl.cols = cols
+	tbl.lineHt = lineHt
+	tbl.padX = f.cMargin
+	return
+}
+
+// SetWidth sets the width of the table, in the unit of measure specified in
+// New(). If w is 0, the default, the table is as wide as the space between
+// the left and right margins, or narrower if its columns sized to fit their
+// content need less.
+func (tbl *TableType) SetWidth(w float64) {
+	tbl.w = w
+}
+
+// SetPadding sets the space between the text of the cells and their left and
+// right edges, and between the text and their top and bottom edges, in the
+// unit of measure specified in New().
+func (tbl *TableType) SetPadding(horizontal, vertical float64) {
+	tbl.padX = horizontal
+	tbl.padY = vertical
+}
+
+// SetBorder sets the borders of each cell of the rows printed, drawn with
+// the current draw color and line width: "1" for a full frame, or a string
+// containing some or all of the characters "L", "T", "R" and "B" for the
+// left, top, right and bottom sides, as with CellFormat(). An empty string,
+// the default, indicates no borders.
+func (tbl *TableType) SetBorder(borderStr string) {
+	tbl.borderStr = borderStr
+}
+
+// SetRowFillColors sets the background colors of the rows printed other than
+// header rows, which are used in turn, as for the alternating backgrounds of
+// banded rows. The rows have no background if no colors are specified, the
+// default. The background of a column or cell is painted over that of its
+// row.
+func (tbl *TableType) SetRowFillColors(colors ...RGBType) {
+	tbl.fills = colors
+}
+
+// SetRowSplit sets whether a row that does not fit on the current page is
+// split between the lines of its cells, rather than moved to the next page,
+// so that cells of long text fill the page and rows taller than a page do
+// not extend beyond it. The lines of the cells that fit on the page are
+// printed as a row and the other lines as a row on the next page, with the
+// header rows of the table, which is split again if need be. A row is moved
+// to the next page nonetheless if not even the first line of each of its
+// cells fits. Rows that cells span together are not split. Rows are not
+// split by default.
+func (tbl *TableType) SetRowSplit(split bool) {
+	tbl.split = split
+}
+
+// SetCellStyleFunc sets a function that returns the style of each cell of the
+// rows printed, given the number of its row among the rows other than header
+// rows, counted from 0, or -1 for header rows, and the number of its first
+// column, counted from 0. The style returned prevails over those of the
+// columns and rows of the table, and the alignment and background color of
+// a TableCellType over the style, so that cells can be formatted according to
+// their content, such as negative amounts in red, and rows banded with other
+// colors than those of SetRowFillColors(), without changing the cells. If
+// fnc is nil, the default, cells have the style of their columns and rows.
+func (tbl *TableType) SetCellStyleFunc(fnc func(row, col int) TableCellStyleType) {
+	tbl.styleFnc = fnc
+}
+
+// Header prints a header row whose cells have the text of cells, as
+// HeaderCells() does.
+func (tbl *TableType) Header(cells ...string) {
+	tbl.HeaderCells(tableCells(cells)...)
+}
+
+// HeaderCells prints a header row of the table with the current font and
+// text color, as RowCells() does. The header rows of a table are printed
+// again, with the same font and text color, at the top of each page onto
+// which a page break moves the rows that follow them. The cells of header
+// rows span no rows beyond the last header row.
+func (tbl *TableType) HeaderCells(cells ...TableCellType) {
+	f := tbl.pdf
+	if f.err != nil {
+		return
+	}
+	row := tableRow{cells: cells, st: f.spanStateGet(), header: true}
+	tbl.headers = append(tbl.headers, row)
+	tbl.add(row)
+}
+
+// Row prints a row whose cells have the text of cells, as RowCells() does.
+func (tbl *TableType) Row(cells ...string) {
+	tbl.RowCells(tableCells(cells)...)
+}
+
+// RowCells prints a row of the table with the cells cells, from the left to
+// the right column, from the current vertical position, and leaves the
+// current position at the left margin below the row. Columns left without a
+// cell are empty. The row is printed with the current font and text color.
+//
+// A row whose cells, or those of the rows above it, span rows that have not
+// been added yet is not printed until the last of these rows is, and then
+// printed with them on one page. End() prints such rows if fewer rows than
+// their cells span follow them.
+func (tbl *TableType) RowCells(cells ...TableCellType) {
+	f := tbl.pdf
+	if f.err != nil {
+		return
+	}
+	tbl.add(tableRow{cells: cells, st: f.spanStateGet()})
+}
+
+// End prints the rows of the table that wait for the rows that their cells
+// span, as if the last of them were the last of these rows. Rows are printed
+// as soon as they can otherwise, so that End() need only be called if cells
+// may span more rows than follow them.
+func (tbl *TableType) End() {
+	if len(tbl.pending) > 0 {
+		tbl.group(tbl.pending)
+	}
+	tbl.pending, tbl.spanEnd = nil, 0
+}
+
+// add adds row to the pending rows and prints them if the cells of none span
+// rows that follow.
+func (tbl *TableType) add(row tableRow) {
+	if len(tbl.pending) > 0 && tbl.pending[0].header != row.header {
+		// The spans of header rows end with them
+		tbl.End()
+	}
+	tbl.pending = append(tbl.pending, row)
+	for _, cell := range row.cells {
+		tbl.spanEnd = max(tbl.spanEnd, len(tbl.pending)-1+cell.RowSpan)
+	}
+	if len(tbl.pending) >= tbl.spanEnd {
+		tbl.End()
+	}
+}
+
+// tableCells returns cells of the text of strs.
+func tableCells(strs []string) (cells []TableCellType) {
+	for _, str := range strs {
+		cells = append(cells, TableCellType{Text: str})
+	}
+	return
+}
+
+// FitColumns measures the content of rows, as FitColumnsCells() does.
+func (tbl *TableType) FitColumns(rows ...[]string) {
+	cells := make([][]TableCellType, len(rows))
+	for j, row := range rows {
+		cells[j] = tableCells(row)
+	}
+	tbl.FitColumnsCells(cells...)
+}
+
+// FitColumnsCells measures the content of the cells of rows with the current
+// font, so that the columns without a width or percentage are sized to fit
+// the content measured as an HTML table is: the width left for them is
+// shared so that no column is narrower than its longest word, and their
+// text is wrapped in as few lines as possible. The columns are as wide as
+// their widest line of text if the width left is enough. Calling
+// FitColumnsCells() again measures more content, such as header rows with
+// their own font. Cells that span several columns are not measured. The rows
+// are not printed.
+func (tbl *TableType) FitColumnsCells(rows ...[]TableCellType) {
+	f := tbl.pdf
+	if f.err != nil {
+		return
+	}
+	if tbl.minW == nil {
+		tbl.minW = make([]float64, len(tbl.cols))
+		tbl.maxW = make([]float64, len(tbl.cols))
+	}
+	places, _ := tbl.place(rows)
+	for r, row := range rows {
+		for j, cell := range row {
+			if place := places[r][j]; place[0] >= 0 && place[1] == 1 {
+				minW, maxW := tbl.contentWidths(cell)
+				tbl.minW[place[0]] = math.Max(tbl.minW[place[0]], minW+2*tbl.padX)
+				tbl.maxW[place[0]] = math.Max(tbl.maxW[place[0]], maxW+2*tbl.padX)
+			}
+		}
+	}
+}
+
+// contentWidths returns the width of the longest word and that of the
+// longest line of the text of cell.
+func (tbl *TableType) contentWidths(cell TableCellType) (minW, maxW float64) {
+	f := tbl.pdf
+	if cell.Spans == nil {
+		for _, line := range strings.Split(cell.Text, "\n") {
+			maxW = math.Max(maxW, f.GetSpacedStringWidth(line))
+			for _, word := range strings.Fields(line) {
+				minW = math.Max(minW, f.GetSpacedStringWidth(word))
+			}
+		}
+		return
+	}
+	for _, line := range f.spanLines(cell.Spans, func(int) float64 { return math.MaxFloat32 }) {
+		maxW = math.Max(maxW, line.w)
+	}
+	st := f.spanStateGet()
+	for _, span := range cell.Spans {
+		f.spanApply(span, st)
+		for _, word := range strings.Fields(span.Text) {
+			minW = math.Max(minW, f.GetSpacedStringWidth(word))
+		}
+		if span.ImageName != "" {
+			minW = math.Max(minW, span.ImageWidth)
+		}
+	}
+	f.spanStatePut(st)
+	return
+}
+
+// ColumnWidths returns the widths of the columns of the table, in the unit of
+// measure specified in New(), if printed from the current position.
+func (tbl *TableType) ColumnWidths() []float64 {
+	return tbl.widths()
+}
+
+// widths returns the widths of the columns of the table.
+func (tbl *TableType) widths() []float64 {
+	f := tbl.pdf
+	widths := make([]float64, len(tbl.cols))
+	total := tbl.w
+	if total == 0 {
+		total = f.w - f.rMargin - f.lMargin
+	}
+	rest := total
+	var auto []int
+	for j, col := range tbl.cols {
+		switch {
+		case col.Width != 0:
+			widths[j] = col.Width
+		case col.Percent != 0:
+			widths[j] = col.Percent * total / 100
+		default:
+			auto = append(auto, j)
+		}
+		rest -= widths[j]
+	}
+	rest = math.Max(0, rest)
+	if tbl.minW == nil {
+		for _, j := range auto {
+			widths[j] = rest / float64(len(auto))
+		}
+		return widths
+	}
+	// As in the automatic layout of HTML tables, columns get their widest
+	// line if there is room for it, and otherwise their longest word plus a
+	// share of the width left in proportion to how much more they want
+	var minSum, maxSum float64
+	for _, j := range auto {
+		minSum += tbl.minW[j]
+		maxSum += tbl.maxW[j]
+	}
+	for _, j := range auto {
+		switch {
+		case maxSum <= rest && tbl.w != 0 && maxSum > 0:
+			widths[j] = tbl.maxW[j] * rest / maxSum
+		case maxSum <= rest && tbl.w != 0:
+			widths[j] = rest / float64(len(auto))
+		case maxSum <= rest:
+			widths[j] = tbl.maxW[j]
+		case minSum >= rest:
+			widths[j] = tbl.minW[j]
+		default:
+			widths[j] = tbl.minW[j] + (tbl.maxW[j]-tbl.minW[j])*(rest-minSum)/(maxSum-minSum)
+		}
+	}
+	return widths
+}
+
+// place returns the first column and the number of columns of each of the
+// cells of rows, the first column being -1 for cells for which no column is
+// left, and the columns that no cell takes in each row.
+func (tbl *TableType) place(rows [][]TableCellType) (places [][][2]int, free [][]bool) {
+	n := len(tbl.cols)
+	// Number of rows that follow the current one taken by the cell above
+	spanned := make([]int, n)
+	places = make([][][2]int, len(rows))
+	free = make([][]bool, len(rows))
+	for r, row := range rows {
+		free[r] = make([]bool, n)
+		for col := range spanned {
+			free[r][col] = spanned[col] == 0
+			spanned[col] = max(spanned[col]-1, 0)
+		}
+		col := 0
+		for _, cell := range row {
+			for col < n && !free[r][col] {
+				col++
+			}
+			if col == n {
+				places[r] = append(places[r], [2]int{-1, 0})
+				continue
+			}
+			span := 1
+			for span < cell.ColSpan && col+span < n && free[r][col+span] {
+				span++
+			}
+			places[r] = append(places[r], [2]int{col, span})
+			for k := col; k < col+span; k++ {
+				free[r][k] = false
+				spanned[k] = max(cell.RowSpan-1, 0)
+			}
+			col += span
+		}
+	}
+	return
+}
+
+// tableCellLayout is a cell of a row laid out in its columns.
+type tableCellLayout struct {
+	TableCellType
+	x, w float64 // Position and width of the cell
+	ht   float64 // Height of the text of the cell
+	col  int     // Index of the first column of the cell
+	rows int     // Number of rows of the cell
+}
+
+// group prints rows, whose cells span no rows that follow, on one page,
+// printing the header rows of the table again before them if they are not
+// header rows and move to the next page.
+func (tbl *TableType) group(rows []tableRow) {
+	f := tbl.pdf
+	st := f.spanStateGet()
+	defer f.spanStatePut(st)
+	lineHt := f.textLineHt(tbl.lineHt)
+	layouts, heights := tbl.layout(rows, lineHt)
+	if f.err != nil {
+		return
+	}
+	groupHt := 0.0
+	for _, h := range heights {
+		groupHt += h
+	}
+	// split splits a row that does not fit at y
+	split := func(y float64) bool {
+		f.y = y
+		return tbl.split && len(rows) == 1 && y+groupHt > f.pageBreakTrigger && f.autoPageBreak &&
+			!f.inHeader && !f.inFooter && tbl.splitRow(rows[0], layouts[0], lineHt)
+	}
+	if split(f.y) {
+		return
+	}
+	page, y0 := f.page, f.y
+	y := f.imageFlow(groupHt)
+	if f.err != nil {
+		return
+	}
+	if f.page != page || y < y0 {
+		headers := !rows[0].header && len(tbl.headers) > 0
+		if headers {
+			// The rows that follow a page or column break are preceded by
+			// the header rows, printed with their own font
+			f.y = y
+			tbl.group(tbl.headers)
+			y = f.y
+		}
+		// The margins of a column that the rows move to may differ
+		layouts, heights = tbl.layout(rows, lineHt)
+		groupHt = 0
+		for _, h := range heights {
+			groupHt += h
+		}
+		if split(y) {
+			return
+		}
+		f.y = y
+		if headers {
+			y = f.imageFlow(groupHt)
+		} else {
+			f.y += groupHt
+		}
+	}
+	auto := f.autoPageBreak
+	f.autoPageBreak = false
+	cMargin := f.cMargin
+	f.cMargin = tbl.padX
+	// Cells are printed from the top of their rows, with the background of
+	// the first of them
+	tops := make([]float64, len(rows)+1)
+	nums := make([]int, len(rows)) // Numbers of the rows other than header rows
+	tops[0] = y
+	for r, row := range rows {
+		tops[r+1] = tops[r] + heights[r]
+		nums[r] = -1
+		if !row.header {
+			nums[r] = tbl.rows
+			tbl.rows++
+		}
+	}
+	styles := make([][]TableCellStyleType, len(rows))
+	for r, row := range rows {
+		f.spanStatePut(row.st)
+		for _, cell := range layouts[r] {
+			style := tbl.cellStyle(cell, nums[r])
+			tbl.cellPrint(cell, tops[r], tops[r+cell.rows]-tops[r], lineHt, style)
+			styles[r] = append(styles[r], style)
+		}
+	}
+	for r := range rows {
+		for j, cell := range layouts[r] {
+			if borderStr := styles[r][j].BorderStr; borderStr != "" {
+				f.x, f.y = cell.x, tops[r]
+				f.CellFormat(cell.w, tops[r+cell.rows]-tops[r], "", borderStr, 0, "", false, 0, "")
+			}
+		}
+	}
+	f.cMargin = cMargin
+	f.autoPageBreak = auto
+	f.x, f.y = f.lMargin, tops[len(rows)]
+}
+
+// splitRow prints the row whose cells are laid out as layout in lines of
+// height lineHt split in two rows, that of the lines of its cells that fit
+// on the current page and that of the other lines, which it prints as
+// group() does. It reports whether the row is split.
+func (tbl *TableType) splitRow(row tableRow, layout []tableCellLayout, lineHt float64) bool {
+	f := tbl.pdf
+	avail := f.pageBreakTrigger - f.y - 2*tbl.padY
+	cMargin := f.cMargin
+	f.cMargin = tbl.padX
+	top, rest := row, row
+	top.cells, rest.cells = nil, nil
+	// The row is split if some cell has lines that do not fit and each one
+	// has lines that do
+	split, fits := false, true
+	for _, cell := range layout[:len(row.cells)] {
+		topCell, restCell := cell.TableCellType, cell.TableCellType
+		n := 0
+		if cell.Spans != nil {
+			lines := f.spanLines(cell.Spans, func(int) float64 { return cell.w - 2*f.cMargin })
+			ht := 0.0
+			for n < len(lines) {
+				h, _ := f.spanLineHeight(cell.Spans, lines[n], lineHt)
+				if ht+h > avail {
+					break
+				}
+				ht += h
+				n++
+			}
+			topCell.Spans = spanLinesSpans(cell.Spans, lines[:n])
+			restCell.Spans = spanLinesSpans(cell.Spans, lines[n:])
+			split = split || n < len(lines)
+			fits = fits && (n > 0 || len(lines) == 0)
+		} else if cell.Text != "" {
+			lines := f.BreakLines(cell.Text, cell.w)
+			n = minInt(int((avail+1e-9)/lineHt), len(lines))
+			fits = fits && n > 0
+			if n == 0 {
+				break
+			}
+			topCell.Text = joinTextLines(lines[:n-1]) + lines[n-1].Str
+			restCell.Text = ""
+			if n < len(lines) {
+				restCell.Text = joinTextLines(lines[n:len(lines)-1]) + lines[len(lines)-1].Str
+				split = true
+			}
+		}
+		top.cells = append(top.cells, topCell)
+		rest.cells = append(rest.cells, restCell)
+	}
+	f.cMargin = cMargin
+	if !split || !fits || f.err != nil {
+		return false
+	}
+	tbl.group([]tableRow{top})
+	// Both parts of the row have its background
+	tbl.rows--
+	tbl.group([]tableRow{rest})
+	return true
+}
+
+// cellStyle returns the style of cell of the row numbered num, as set by the
+// cell, the function set with SetCellStyleFunc(), its column and its row in
+// turn.
+func (tbl *TableType) cellStyle(cell tableCellLayout, num int) (style TableCellStyleType) {
+	col := tbl.cols[cell.col]
+	style.FillColor, style.AlignStr, style.BorderStr = col.FillColor, col.AlignStr, tbl.borderStr
+	if style.FillColor == nil && len(tbl.fills) > 0 && num >= 0 {
+		style.FillColor = &tbl.fills[num%len(tbl.fills)]
+	}
+	if tbl.styleFnc != nil {
+		s := tbl.styleFnc(num, cell.col)
+		if s.FillColor != nil {
+			style.FillColor = s.FillColor
+		}
+		if s.AlignStr != "" {
+			style.AlignStr = s.AlignStr
+		}
+		if s.BorderStr != "" {
+			style.BorderStr = s.BorderStr
+		}
+		style.TextColor = s.TextColor
+	}
+	if cell.FillColor != nil {
+		style.FillColor = cell.FillColor
+	}
+	if cell.AlignStr != "" {
+		style.AlignStr = cell.AlignStr
+	}
+	if style.BorderStr == "0" {
+		style.BorderStr = ""
+	}
+	return
+}
+
+// cellPrint prints the background and the text of cell with the style style
+// at y with the height h, in lines of height lineHt.
+func (tbl *TableType) cellPrint(cell tableCellLayout, y, h, lineHt float64, style TableCellStyleType) {
+	f := tbl.pdf
+	tbl.fillRect(style.FillColor, cell.x, y, cell.w, h)
+	if c := style.TextColor; c != nil {
+		textColor, colorFlag := f.color.text, f.colorFlag
+		f.setTextColor(c.R, c.G, c.B)
+		defer func() { f.color.text, f.colorFlag = textColor, colorFlag }()
+	}
+	alignStr := strings.ToUpper(style.AlignStr)
+	dy := tbl.padY
+	switch {
+	case strings.Contains(alignStr, "M"):
+		dy += (h - 2*tbl.padY - cell.ht) / 2
+	case strings.Contains(alignStr, "B"):
+		dy += h - 2*tbl.padY - cell.ht
+	}
+	alignStr = strings.Trim(alignStr, "TMB")
+	if alignStr == "" {
+		alignStr = "L"
+	}
+	f.x, f.y = cell.x, y+dy
+	if cell.Spans != nil {
+		f.MultiCellSpans(cell.w, lineHt, cell.Spans, "", alignStr, false)
+	} else if cell.Text != "" {
+		f.MultiCell(cell.w, lineHt, cell.Text, "", alignStr, false)
+	}
+}
+
+// layout returns the cells of rows laid out in the columns of the table,
+// with the height of their text in lines of height lineHt, and the heights
+// of the rows.
+func (tbl *TableType) layout(rows []tableRow, lineHt float64) (layouts [][]tableCellLayout, heights []float64) {
+	f := tbl.pdf
+	widths := tbl.widths()
+	xs := make([]float64, len(widths)+1)
+	xs[0] = f.lMargin
+	for col, w := range widths {
+		xs[col+1] = xs[col] + w
+	}
+	cells := make([][]TableCellType, len(rows))
+	for r, row := range rows {
+		cells[r] = row.cells
+	}
+	places, free := tbl.place(cells)
+	cMargin := f.cMargin
+	f.cMargin = tbl.padX
+	defer func() { f.cMargin = cMargin }()
+	layouts = make([][]tableCellLayout, len(rows))
+	heights = make([]float64, len(rows))
+	for r, row := range rows {
+		f.spanStatePut(row.st)
+		heights[r] = lineHt
+		for j, cell := range row.cells {
+			col, span := places[r][j][0], places[r][j][1]
+			if col < 0 {
+				f.SetErrorf("table row has more cells than the %d columns of the table", len(widths))
+				return
+			}
+			c := tableCellLayout{TableCellType: cell, x: xs[col], w: xs[col+span] - xs[col], col: col,
+				rows: minInt(max(cell.RowSpan, 1), len(rows)-r)}
+			if cell.Spans != nil {
+				h := lineHt
+				for _, line := range f.spanLines(cell.Spans, func(int) float64 { return c.w - 2*f.cMargin }) {
+					lineHt, _ := f.spanLineHeight(cell.Spans, line, h)
+					c.ht += lineHt
+				}
+			} else if cell.Text != "" {
+				c.ht = float64(len(f.BreakLines(cell.Text, c.w))) * lineHt
+			}
+			if c.rows == 1 {
+				heights[r] = math.Max(heights[r], c.ht)
+			}
+			layouts[r] = append(layouts[r], c)
+		}
+		// Columns left without a cell have empty cells
+		for col, ok := range free[r] {
+			if ok {
+				layouts[r] = append(layouts[r], tableCellLayout{x: xs[col], w: widths[col], col: col, rows: 1})
+			}
+		}
+	}
+	for r := range heights {
+		heights[r] += 2 * tbl.padY
+	}
+	// The last of the rows that a cell spans is made higher if the text of
+	// the cell does not fit in them
+	for r := range layouts {
+		for _, cell := range layouts[r] {
+			need := cell.ht + 2*tbl.padY
+			for _, h := range heights[r : r+cell.rows] {
+				need -= h
+			}
+			if need > 0 {
+				heights[r+cell.rows-1] += need
+			}
+		}
+	}
+	return
+}
+
+// fillRect fills the rectangle at x, y of width w and height h with the
+// color c, if not nil, leaving the fill color unchanged.
+func (tbl *TableType) fillRect(c *RGBType, x, y, w, h float64) {
+	if c == nil {
+		return
+	}
+	f := tbl.pdf
+	fillColor := f.color.fill
+	f.setFillColor(c.R, c.G, c.B)
+	f.Rect(x, y, w, h, "F")
+	f.fillColorPut(fillColor)
+}
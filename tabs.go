@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"sort"
+	"strings"
+)
+
+// TabStopType is a tab stop at which the text that follows a tab character
+// is aligned. See SetTabStops().
+type TabStopType struct {
+	// The position of the tab stop, in the unit of measure specified in New(),
+	// from the start of the text of a line.
+	Pos float64
+	// The alignment of the text at the tab stop: "L" or an empty string for
+	// text that starts at the tab stop, "R" for text that ends at it, "C" for
+	// text centered on it and "D" for text whose decimal point, the first
+	// period, is at the tab stop, or its end if it has none.
+	AlignStr string
+}
+
+// SetTabStops sets the tab stops at which Cell(), CellFormat() and Write()
+// align the text that follows each tab character ("\t") of their text. The
+// text is set at the first tab stop that follows the text that precedes the
+// tab, or after a space if there is none. Positions are measured from the
+// start of the text of a cell, inside its cell margin, and for Write() from
+// the start of text at the left margin. Text with tabs is aligned left in a
+// cell, whatever the alignment specified. Without arguments, SetTabStops()
+// removes the tab stops, which is the default, and tab characters are
+// printed as other characters are.
+func (f *Fpdf) SetTabStops(stops ...TabStopType) {
+	f.tabStops = append([]TabStopType(nil), stops...)
+	sort.SliceStable(f.tabStops, func(i, j int) bool { return f.tabStops[i].Pos < f.tabStops[j].Pos })
+}
+
+// GetTabStops returns the tab stops set with SetTabStops(), in increasing
+// order of position.
+func (f *Fpdf) GetTabStops() []TabStopType {
+	return append([]TabStopType(nil), f.tabStops...)
+}
+
+// tabPos returns the position at which the text txtStr that follows a tab is
+// set, given the position pos at which the text before the tab ends.
+func (f *Fpdf) tabPos(txtStr string, pos float64) float64 {
+	if j := strings.IndexByte(txtStr, '\n'); j >= 0 {
+		txtStr = txtStr[:j]
+	}
+	for _, stop := range f.tabStops {
+		if stop.Pos <= pos {
+			continue
+		}
+		start := stop.Pos
+		switch strings.ToUpper(stop.AlignStr) {
+		case "R":
+			start -= f.GetSpacedStringWidth(txtStr)
+		case "C":
+			start -= f.GetSpacedStringWidth(txtStr) / 2
+		case "D":
+			if j := strings.IndexByte(txtStr, '.'); j >= 0 {
+				txtStr = txtStr[:j]
+			}
+			start -= f.GetSpacedStringWidth(txtStr)
+		}
+		if start >= pos {
+			return start
+		}
+	}
+	return pos + f.GetSpacedStringWidth(" ")
+}
+
+// tabCellFormat prints a cell whose text has tabs. See CellFormat() for the
+// arguments.
+func (f *Fpdf) tabCellFormat(w, h float64, txtStr, borderStr string, ln int,
+	alignStr string, fill bool, link int, linkStr string) {
+	if w == 0 {
+		w = f.w - f.rMargin - f.x
+	}
+	// The cell breaks the page if needed
+	f.CellFormat(w, h, "", borderStr, 0, "", fill, 0, "")
+	if f.err != nil {
+		return
+	}
+	x := f.x - w
+	vAlignStr := ""
+	for _, r := range "TMBA" {
+		if strings.ContainsRune(strings.ToUpper(alignStr), r) {
+			vAlignStr = string(r)
+		}
+	}
+	cMargin := f.cMargin
+	pos := 0.0
+	for j, str := range strings.Split(txtStr, "\t") {
+		if j > 0 {
+			pos = f.tabPos(str, pos)
+		}
+		strW := f.GetSpacedStringWidth(str)
+		f.x = x + cMargin + pos
+		f.cMargin = 0
+		f.CellFormat(strW, h, str, "", 0, "L"+vAlignStr, false, link, linkStr)
+		f.cMargin = cMargin
+		pos += strW
+	}
+	f.x = x
+	if ln > 0 {
+		f.y += h
+		if ln == 1 {
+			f.x = f.lMargin
+		}
+	} else {
+		f.x += w
+	}
+}
+
+// tabWrite writes text with tabs in flowing mode. See Write() for the
+// arguments.
+func (f *Fpdf) tabWrite(h float64, txtStr string, link int, linkStr string) {
+	for j, str := range strings.Split(txtStr, "\t") {
+		if j > 0 {
+			// Written text starts after the cell margin and the current
+			// position is the end of the text less the cell margin
+			pos := f.tabPos(str, f.x-f.lMargin)
+			f.x = f.lMargin + pos
+		}
+		f.write(h, str, link, linkStr)
+	}
+}
@@ -295,6 +295,9 @@ func (t *Tpl) loadParamsFromFpdf(f *Fpdf) {
 	t.Fpdf.fontSize = f.fontSize
 	t.Fpdf.fontSizePt = f.fontSizePt
 	t.Fpdf.fontStyle = f.fontStyle
+	t.Fpdf.fontSynthStr = f.fontSynthStr
+	t.Fpdf.syntheticStyle = f.syntheticStyle
+	t.Fpdf.textTransform = f.textTransform
 	t.Fpdf.ws = f.ws
 
 	for key, value := range f.images {
@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"math"
+	"strings"
+)
+
+// TextOnPath prints txtStr along path with the current font, each character
+// set on the path and rotated to follow its direction, so that text can run
+// along curves such as the circle of a seal or stamp. The baseline of each
+// character is the chord of the path between its ends, and the top of the
+// characters is on the left of the direction of the path.
+//
+// path is a sequence of segments whose coordinates are in the unit of
+// measure specified in New(), with the commands M, L, H, V, C, Q and Z of SVG
+// paths, or their relative forms, as described by SVGBasicSegmentType. Lines
+// and curves of the subpaths that M commands start are followed one after
+// the other, and characters that would extend beyond the end of the path are
+// not printed. See ArcPath() for the path of an arc of a circle or an
+// ellipse.
+//
+// alignStr specifies the position of the text along the path: "L" or an
+// empty string to start the text at the start of the path, "C" to center it
+// and "R" to end it at the end of the path.
+func (f *Fpdf) TextOnPath(path []SVGBasicSegmentType, txtStr, alignStr string) {
+	if f.err != nil {
+		return
+	}
+	if f.currentFont.Name == "" {
+		f.SetErrorf("font has not been set; unable to render text")
+		return
+	}
+	lines := pathLines(path)
+	if len(lines) == 0 {
+		return
+	}
+	length := 0.0
+	for _, line := range lines {
+		length += line.length
+	}
+	txtStr = f.shapeText(txtStr)
+	if f.isCurrentUTF8 {
+		txtStr = f.visualText(txtStr)
+	}
+	runes := f.textRunes(txtStr)
+	widths := make([]float64, len(runes))
+	textW := 0.0
+	for j, r := range runes {
+		widths[j] = f.GetStringWidth(f.runesString([]rune{r}))
+		textW += widths[j]
+	}
+	pos := 0.0
+	switch {
+	case strings.Contains(alignStr, "R"):
+		pos = length - textW
+	case strings.Contains(alignStr, "C"):
+		pos = (length - textW) / 2
+	}
+	for j, r := range runes {
+		start, end := pos, pos+widths[j]
+		pos = end
+		if start < -1e-9 || end > length+1e-9 {
+			continue
+		}
+		x1, y1 := pathPoint(lines, start)
+		x2, y2 := pathPoint(lines, end)
+		// The y axis points down the page
+		angle := math.Atan2(y1-y2, x2-x1) * 180 / math.Pi
+		if f.isRTL && f.isCurrentUTF8 {
+			// Text() sets right-to-left text to the left of its position
+			x1 += widths[j]
+		}
+		f.TransformBegin()
+		f.TransformRotate(angle, x1, y1)
+		f.Text(x1, y1, f.runesString([]rune{r}))
+		f.TransformEnd()
+	}
+}
+
+// ArcPath returns the path, for TextOnPath() or other uses of paths, of the
+// arc of the ellipse centered at (x, y) with horizontal and vertical radii rx
+// and ry that goes from the angle degStart to the angle degEnd. The angles
+// are specified in degrees and measured counter-clockwise from the 3 o'clock
+// position, as for Arc(). The path runs clockwise if degEnd is less than
+// degStart, so that the path of the top half of a circle from left to right,
+// along which text reads upright, goes from 180 to 0 degrees, and that of its
+// bottom half from left to right from 180 to 360 degrees.
+func ArcPath(x, y, rx, ry, degStart, degEnd float64) (path []SVGBasicSegmentType) {
+	point := func(t float64) (float64, float64) {
+		return x + rx*math.Cos(t), y - ry*math.Sin(t)
+	}
+	// Each curve spans at most a quarter of a turn
+	n := int(math.Ceil(math.Abs(degEnd-degStart) / 90))
+	if n < 1 {
+		n = 1
+	}
+	t0 := degStart * math.Pi / 180
+	dt := (degEnd - degStart) * math.Pi / 180 / float64(n)
+	// Length of the tangents of the control points per radian
+	k := 4.0 / 3 * math.Tan(dt/4)
+	x0, y0 := point(t0)
+	path = append(path, SVGBasicSegmentType{Cmd: 'M', Arg: [6]float64{x0, y0}})
+	for j := 0; j < n; j++ {
+		t1 := t0 + dt
+		x1, y1 := point(t1)
+		path = append(path, SVGBasicSegmentType{Cmd: 'C', Arg: [6]float64{
+			x0 - k*rx*math.Sin(t0), y0 - k*ry*math.Cos(t0),
+			x1 + k*rx*math.Sin(t1), y1 + k*ry*math.Cos(t1),
+			x1, y1,
+		}})
+		t0, x0, y0 = t1, x1, y1
+	}
+	return
+}
+
+// pathLine is a straight line of a path flattened by pathLines().
+type pathLine struct {
+	x1, y1, x2, y2 float64
+	length         float64
+}
+
+// pathCurveSteps is the number of lines that approximate a curve of a path.
+const pathCurveSteps = 32
+
+// pathLines returns the lines that approximate path, in the order in which
+// they are followed.
+func pathLines(path []SVGBasicSegmentType) (lines []pathLine) {
+	segs := append([]SVGBasicSegmentType(nil), path...)
+	absolutizePath(segs)
+	var x, y, x0, y0 float64 // Current point and start of the subpath
+	lineTo := func(x2, y2 float64) {
+		if length := math.Hypot(x2-x, y2-y); length > 0 {
+			lines = append(lines, pathLine{x, y, x2, y2, length})
+		}
+		x, y = x2, y2
+	}
+	for _, seg := range segs {
+		a := seg.Arg
+		switch seg.Cmd {
+		case 'M':
+			x, y = a[0], a[1]
+			x0, y0 = x, y
+		case 'L':
+			lineTo(a[0], a[1])
+		case 'H':
+			lineTo(a[0], y)
+		case 'V':
+			lineTo(x, a[0])
+		case 'Z':
+			lineTo(x0, y0)
+		case 'Q', 'C':
+			// A quadratic curve is the cubic curve with control points two
+			// thirds of the way to its control point
+			px, py := x, y
+			c := a
+			if seg.Cmd == 'Q' {
+				c = [6]float64{x + 2*(a[0]-x)/3, y + 2*(a[1]-y)/3,
+					a[2] + 2*(a[0]-a[2])/3, a[3] + 2*(a[1]-a[3])/3, a[2], a[3]}
+			}
+			for j := 1; j <= pathCurveSteps; j++ {
+				t := float64(j) / pathCurveSteps
+				u := 1 - t
+				lineTo(u*u*u*px+3*u*u*t*c[0]+3*u*t*t*c[2]+t*t*t*c[4],
+					u*u*u*py+3*u*u*t*c[1]+3*u*t*t*c[3]+t*t*t*c[5])
+			}
+		}
+	}
+	return
+}
+
+// pathPoint returns the point at distance pos along lines.
+func pathPoint(lines []pathLine, pos float64) (x, y float64) {
+	for j, line := range lines {
+		if pos <= line.length || j == len(lines)-1 {
+			t := math.Min(pos/line.length, 1)
+			return line.x1 + t*(line.x2-line.x1), line.y1 + t*(line.y2-line.y1)
+		}
+		pos -= line.length
+	}
+	return
+}
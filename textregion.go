@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// TextRegionType is a rectangle of a page in which FlowText() lays out text,
+// given by its upper left corner and its size in the unit of measure
+// specified in New().
+type TextRegionType struct {
+	X, Y   float64
+	Wd, Ht float64
+}
+
+// FlowText prints the text of spans in the region, in lines of its width
+// and of height h, as MultiCellSpans() does, and continues in other regions
+// when it is full, like text in the linked frames of a page layout
+// application. When the text overflows a region, next is called with the
+// number of the region that follows, counted from 0 for the first one, and
+// returns the region in which the text continues, such as another column or
+// a box on a page that next adds with AddPage() or selects with SetPage(). If
+// next is nil or returns false, the text that does not fit is returned so
+// that it can be continued later, in another call to FlowText(); otherwise
+// nil is returned.
+//
+// Regions are filled from their top, and a line is moved to the next region
+// if it extends below the bottom of the current one, but the first line of a
+// region is printed in any case so that the text always progresses. The
+// regions are not subject to automatic page breaks. Upon method exit, the
+// current position is at the left of the last region, below the last line
+// printed.
+//
+// If h is 0, the line height set with SetLineHeight() or
+// SetLineHeightFactor() is used. See MultiCell() for details about alignStr.
+func (f *Fpdf) FlowText(region TextRegionType, h float64, spans []TextSpan, alignStr string,
+	next func(n int) (TextRegionType, bool)) (rest []TextSpan) {
+	if f.err != nil {
+		return
+	}
+	h = f.textLineHt(h)
+	if alignStr == "" {
+		alignStr = "J"
+	}
+	auto := f.autoPageBreak
+	f.autoPageBreak = false
+	defer func() {
+		f.autoPageBreak = auto
+	}()
+	for n := 1; ; n++ {
+		lines := f.spanLines(spans, func(int) float64 {
+			return region.Wd - 2*f.cMargin
+		})
+		f.x, f.y = region.X, region.Y
+		j := 0
+		for ; j < len(lines) && f.err == nil; j++ {
+			if lineHt, _ := f.spanLineHeight(spans, lines[j], h); j > 0 && f.y+lineHt > region.Y+region.Ht+1e-9 {
+				break
+			}
+			f.x = region.X
+			f.y += f.spanLinePrint(spans, lines[j], region.Wd, h, "", alignStr, false)
+		}
+		f.x = region.X
+		if j == len(lines) || f.err != nil {
+			return nil
+		}
+		spans = spanLinesText(spans, lines[j:])
+		var ok bool
+		if next != nil {
+			region, ok = next(n)
+		}
+		if !ok || f.err != nil {
+			return spans
+		}
+	}
+}
+
+// spanLinesText returns the text of the lines of spans as spans, so that it
+// can be laid out again. Lines broken at spaces are joined with a space.
+func spanLinesText(spans []TextSpan, lines []spanLine) (list []TextSpan) {
+	prev := -1 // Index of the span of the last text appended to list
+	add := func(n int, str string) {
+		if n == prev {
+			list[len(list)-1].Text += str
+			return
+		}
+		span := spans[n]
+		span.Text, span.ImageName = str, ""
+		list = append(list, span)
+		prev = n
+	}
+	for j, line := range lines {
+		for _, piece := range line.pieces {
+			if piece.h > 0 {
+				list = append(list, spans[piece.span])
+				prev = -1
+				continue
+			}
+			add(piece.span, piece.str)
+		}
+		switch {
+		case j == len(lines)-1:
+		case line.last:
+			list = append(list, TextSpan{Text: "\n"})
+			prev = -1
+		case line.spaced && len(line.pieces) > 0:
+			add(line.pieces[len(line.pieces)-1].span, " ")
+		}
+	}
+	return
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// Size of superscript and subscript text relative to the size of the text
+// around it, and the rise of their baseline relative to that size
+const (
+	scriptSize      = 0.65
+	superscriptRise = 0.35
+	subscriptRise   = -0.15
+)
+
+// SetTextRise sets the distance, in the unit of measure specified in New(),
+// by which the baseline of following text is raised, or lowered if negative.
+// Underlines and strike-out lines follow the text. The rise is kept from page
+// to page. The default value is 0. See WriteSuperscript() and
+// WriteSubscript() to print superscripts and subscripts with the size and
+// rise commonly used for them.
+func (f *Fpdf) SetTextRise(rise float64) {
+	f.textRise = rise
+	if f.page > 0 {
+		f.outf("%.5f Ts", rise*f.k)
+	}
+}
+
+// GetTextRise returns the text rise set with SetTextRise().
+func (f *Fpdf) GetTextRise() float64 {
+	return f.textRise
+}
+
+// WriteSuperscript prints text from the current position as Write() does, as
+// a superscript such as a footnote marker or an exponent: in a font 65
+// percent of the size of the current font, with its baseline raised by 35
+// percent of that size. The current font size and text rise are restored upon
+// method exit.
+//
+// h is the line height of the text around the superscript in the unit of
+// measure specified in New(). If h is 0, the line height set with
+// SetLineHeight() or SetLineHeightFactor() is used. See Write() for details
+// about link and linkStr. The Superscript field of TextSpan sets superscripts
+// among the spans of WriteSpans() and MultiCellSpans().
+func (f *Fpdf) WriteSuperscript(h float64, txtStr string, link int, linkStr string) {
+	f.writeScript(h, txtStr, superscriptRise, link, linkStr)
+}
+
+// WriteSubscript prints text from the current position as Write() does, as a
+// subscript such as the digits of a chemical formula: in a font 65 percent of
+// the size of the current font, with its baseline lowered by 15 percent of
+// that size. The current font size and text rise are restored upon method
+// exit. See WriteSuperscript() for details about h, link and linkStr.
+func (f *Fpdf) WriteSubscript(h float64, txtStr string, link int, linkStr string) {
+	f.writeScript(h, txtStr, subscriptRise, link, linkStr)
+}
+
+// writeScript writes a superscript or a subscript whose baseline is raised by
+// rise times the current font size.
+func (f *Fpdf) writeScript(h float64, txtStr string, rise float64, link int, linkStr string) {
+	if f.err != nil {
+		return
+	}
+	h = f.textLineHt(h)
+	sizePt, size, textRise := f.fontSizePt, f.fontSize, f.textRise
+	f.SetFontSize(sizePt * scriptSize)
+	// Write() centers text vertically on its line, which raises the baseline
+	// of a smaller font
+	f.SetTextRise(textRise + rise*size - .3*(size-f.fontSize))
+	f.write(h, txtStr, link, linkStr)
+	f.SetFontSize(sizePt)
+	f.SetTextRise(textRise)
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TOCType lays out a table of contents of the headings of the document, each
+// with dot leaders and the number of its page, and linked to the heading.
+// Headings are registered with Entry() as the document is generated, and the
+// table of contents is printed with Write() once they all are, on pages
+// reserved for it with Reserve() before the headings, or at the current
+// position. The document need not be generated twice for the table of
+// contents to refer to the pages that follow it. See TOCNew() to create a
+// receiver that is associated with the PDF document instance.
+type TOCType struct {
+	pdf       *Fpdf
+	lineHt    float64
+	indent    float64
+	leaderStr string
+	entries   []TOCEntryType
+	page      int     // First page reserved, 0 if none
+	pages     int     // Number of pages reserved
+	y         float64 // Position of the table of contents on its first page
+}
+
+// TOCEntryType is a heading registered with the Entry() method of TOCType.
+type TOCEntryType struct {
+	Text  string
+	Level int // Level of the heading, 0 for the top level
+	Page  int // Number of the page of the heading
+	Link  int // Internal link to the heading, as returned by AddLink()
+}
+
+// TOCNew returns an instance that lays out a table of contents in lines of
+// height lineHt whose entries are indented by indent for each level below
+// the top level, in the unit of measure specified in New(). If lineHt is 0,
+// the line height set with SetLineHeight() or SetLineHeightFactor() is used.
+// The dot leaders are periods by default.
+func (f *Fpdf) TOCNew(lineHt, indent float64) (toc TOCType) {
+	toc.pdf = f
+	toc.lineHt = lineHt
+	toc.indent = indent
+	toc.leaderStr = "."
+	return
+}
+
+// SetLeader sets the string that is repeated between the text of each entry
+// and the number of its page, such as "." for dot leaders or " " for none.
+func (toc *TOCType) SetLeader(leaderStr string) {
+	toc.leaderStr = leaderStr
+}
+
+// Reserve reserves the space below the current position on the current page
+// and pages-1 pages that follow for the table of contents, which Write()
+// prints there, and adds a page after them on which the document continues.
+// A title of the table of contents can thus be printed before Reserve() is
+// called. The number of pages needed, which should be enough for the
+// entries, is not known in advance: Write() fails if the table of contents
+// does not fit in them.
+func (toc *TOCType) Reserve(pages int) {
+	f := toc.pdf
+	if f.err != nil {
+		return
+	}
+	if f.page == 0 {
+		f.SetErrorf("no page for the table of contents")
+		return
+	}
+	toc.page, toc.pages, toc.y = f.page, max(pages, 1), f.y
+	for j := 0; j < toc.pages; j++ {
+		f.AddPage()
+	}
+}
+
+// Entry registers a heading of the text txtStr and the level level, 0 for
+// the top level, at the current position, at which the entry of the heading
+// in the table of contents links. It is called before the heading is
+// printed, and Bookmark() may be called with the same arguments to add the
+// heading to the outline that PDF readers display in a sidebar.
+func (toc *TOCType) Entry(txtStr string, level int) {
+	f := toc.pdf
+	link := f.AddLink()
+	f.SetLink(link, -1, -1)
+	toc.entries = append(toc.entries, TOCEntryType{Text: txtStr, Level: level, Page: f.PageNo(), Link: link})
+}
+
+// Entries returns the headings registered with Entry(), for tables of
+// contents laid out otherwise.
+func (toc *TOCType) Entries() []TOCEntryType {
+	return toc.entries
+}
+
+// Write prints the table of contents with the current font and text color,
+// one entry per line. The text of each entry is followed by leaders up to
+// the number of its page at the right margin, and truncated with the
+// ellipsis set with SetEllipsis() if it does not fit. The table of contents
+// is printed on the pages reserved with Reserve(), upon which the current
+// page and position are restored, or at the current position if no pages
+// are reserved, starting a new page when a line does not fit on the current
+// page if automatic page breaking is enabled.
+func (toc *TOCType) Write() {
+	f := toc.pdf
+	if f.err != nil {
+		return
+	}
+	lineHt := f.textLineHt(toc.lineHt)
+	if toc.page == 0 {
+		for _, e := range toc.entries {
+			f.y = f.imageFlow(lineHt)
+			toc.line(e, lineHt)
+		}
+		f.x = f.lMargin
+		return
+	}
+	page, x, y := f.page, f.x, f.y
+	auto := f.autoPageBreak
+	f.autoPageBreak = false
+	defer func() {
+		f.autoPageBreak = auto
+		f.page, f.x, f.y = page, x, y
+	}()
+	toc.pageBegin(toc.page)
+	f.y = toc.y
+	for _, e := range toc.entries {
+		if f.y+lineHt > f.pageBreakTrigger {
+			f.out("Q")
+			if f.page == toc.page+toc.pages-1 {
+				f.SetErrorf("table of contents does not fit on the %d pages reserved", toc.pages)
+				return
+			}
+			toc.pageBegin(f.page + 1)
+			f.y = f.tMargin
+		}
+		toc.line(e, lineHt)
+	}
+	f.out("Q")
+}
+
+// pageBegin makes the reserved page numbered page the current one and sets
+// the font and colors of the table of contents in its content, in which the
+// graphics state at the end of the page is saved.
+func (toc *TOCType) pageBegin(page int) {
+	f := toc.pdf
+	f.page = page
+	f.out("q")
+	if f.currentFont.Name != "" {
+		f.outf("BT /F%s %.2f Tf ET", f.currentFont.i, f.fontSizePt)
+	}
+	f.out(f.color.fill.str)
+}
+
+// line prints the entry e in a line of height lineHt at the current vertical
+// position, which is left below the line.
+func (toc *TOCType) line(e TOCEntryType, lineHt float64) {
+	f := toc.pdf
+	x := f.lMargin + float64(e.Level)*toc.indent
+	w := f.w - f.rMargin - x
+	numStr := strconv.Itoa(e.Page)
+	numW := f.GetStringWidth(numStr)
+	spaceW := f.GetStringWidth(" ")
+	txtStr := f.truncate(e.Text, w-2*f.cMargin-numW-2*spaceW)
+	y := f.y
+	f.x = x
+	f.CellFormat(w, lineHt, txtStr, "", 0, "L", false, 0, "")
+	// The leaders end at the same distance from the page numbers on each
+	// line, so that they line up
+	start := x + f.cMargin + f.GetStringWidth(txtStr) + spaceW
+	end := x + w - f.cMargin - numW - spaceW
+	if leaderW := f.GetStringWidth(toc.leaderStr); leaderW > 0 && end > start {
+		leaders := strings.Repeat(toc.leaderStr, int((end-start)/leaderW))
+		f.x = start - f.cMargin
+		f.CellFormat(end-start+2*f.cMargin, lineHt, leaders, "", 0, "R", false, 0, "")
+	}
+	f.x = x
+	f.CellFormat(w, lineHt, numStr, "", 0, "R", false, 0, "")
+	f.Link(x, y, w, lineHt, e.Link)
+	f.x, f.y = f.lMargin, y+lineHt
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Values for SetTextTransform()
+const (
+	TextTransformNone      = ""
+	TextTransformUppercase = "uppercase"
+	TextTransformLowercase = "lowercase"
+	TextTransformSmallCaps = "smallcaps"
+)
+
+// smallCapsScale is the size of synthetic small capitals relative to the
+// font size.
+const smallCapsScale = 0.7
+
+// SetTextTransform sets the transform of the case of following text, which
+// is one of TextTransformNone, TextTransformUppercase,
+// TextTransformLowercase and TextTransformSmallCaps. The transform is applied
+// when text is laid out, so that text output methods such as Cell(),
+// MultiCell(), Write() and Text() as well as GetStringWidth() and SplitText()
+// take it into account, while the text passed to them is left as written.
+//
+// With TextTransformSmallCaps, lowercase letters are shown as small capitals
+// and other characters are left unchanged. The small capitals are those of
+// the "smcp" feature of the glyph substitution (GSUB) table of a UTF-8 font.
+// Small capitals that the font lacks are synthesized by showing capitals at
+// 70% of the font size; like the glyphs of the font, they are assigned codes
+// of the Private Use Area that the font does not use. Text extracted from the
+// document has the small capitals of the font as lowercase letters and the
+// synthetic ones as capitals.
+//
+// Text printed with fonts other than UTF-8 fonts only has its ASCII letters
+// transformed, and shows capitals in place of small capitals.
+func (f *Fpdf) SetTextTransform(transformStr string) {
+	switch transformStr {
+	case TextTransformNone, TextTransformUppercase, TextTransformLowercase, TextTransformSmallCaps:
+		f.textTransform = transformStr
+	default:
+		f.SetErrorf("invalid text transform: %s", transformStr)
+	}
+}
+
+// transformText returns txtStr with the text transform applied for the
+// current font.
+func (f *Fpdf) transformText(txtStr string) string {
+	switch {
+	case f.textTransform == TextTransformNone:
+		return txtStr
+	case !f.isCurrentUTF8:
+		// Text in single-byte encodings
+		b := []byte(txtStr)
+		for j, c := range b {
+			if f.textTransform == TextTransformLowercase {
+				if c >= 'A' && c <= 'Z' {
+					b[j] = c + 'a' - 'A'
+				}
+			} else if c >= 'a' && c <= 'z' {
+				b[j] = c - 'a' + 'A'
+			}
+		}
+		return string(b)
+	case f.textTransform == TextTransformUppercase:
+		return strings.ToUpper(txtStr)
+	case f.textTransform == TextTransformLowercase:
+		return strings.ToLower(txtStr)
+	}
+	return f.smallCapsText(txtStr)
+}
+
+// smallCapsText returns txtStr with its lowercase letters replaced by the
+// codes of their small capitals in the current font, synthesizing the small
+// capitals that the font lacks.
+func (f *Fpdf) smallCapsText(txtStr string) string {
+	font := f.currentFont
+	ft := font.features
+	if ft.smallCaps == nil {
+		if err := ft.loadSmallCaps(font); err != nil {
+			f.SetError(err)
+			return txtStr
+		}
+	}
+	runes := []rune(txtStr)
+	for j, r := range runes {
+		if code, ok := ft.smallCaps[r]; ok {
+			runes[j] = code
+			continue
+		}
+		upper := unicode.ToUpper(r)
+		if upper == r || !unicode.IsLower(r) || int(upper) >= len(font.Cw) || font.Cw[upper] == 0 {
+			continue
+		}
+		code := font.freeCode()
+		if code < 0 {
+			continue
+		}
+		font.Cw[code] = font.Cw[upper]
+		if font.Cw[code] != 65535 {
+			font.Cw[code] = int(math.Round(float64(font.Cw[upper]) * smallCapsScale))
+		}
+		ft.smallCaps[r] = code
+		ft.syntheticCaps[code] = upper
+		runes[j] = code
+		// The widths of the font merged with its fallbacks lack the code
+		f.fallbackWidths = nil
+	}
+	return string(runes)
+}
+
+// loadSmallCaps reads the small capitals of the font from its GSUB table and
+// assigns codes to them.
+func (ft *fontFeatures) loadSmallCaps(font fontDefType) error {
+	ft.smallCaps = make(map[rune]rune)
+	ft.syntheticCaps = make(map[rune]rune)
+	utf := font.utf8File
+	symbolChars := utf.generateCMAP()
+	if symbolChars == nil {
+		return fmt.Errorf("font does not have cmap for Unicode")
+	}
+	gsub := utf.getTableData("GSUB")
+	if gsub == nil {
+		return nil
+	}
+	substitutes, err := gsubSingleSubstitutions(gsub, "smcp")
+	if err != nil {
+		return err
+	}
+	glyphs := make([]int, 0, len(substitutes))
+	for glyph := range substitutes {
+		glyphs = append(glyphs, glyph)
+	}
+	sort.Ints(glyphs)
+	for _, glyph := range glyphs {
+		for _, char := range symbolChars[glyph] {
+			if code := font.glyphCode(substitutes[glyph], string(rune(char))); code > 0 {
+				ft.smallCaps[rune(char)] = code
+			}
+		}
+	}
+	return nil
+}
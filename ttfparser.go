@@ -44,6 +44,7 @@ type TtfType struct {
 	UnderlineThickness     int16
 	Xmin, Ymin, Xmax, Ymax int16
 	CapHeight              int16
+	XHeight                int16
 	Widths                 []uint16
 	Chars                  map[uint16]uint16
 }
@@ -308,10 +309,12 @@ func (t *ttfParser) ParseOS2() (err error) {
 		t.rec.TypoAscender = t.ReadShort()
 		t.rec.TypoDescender = t.ReadShort()
 		if version >= 2 {
-			t.Skip(3*2 + 2*4 + 2)
+			t.Skip(3*2 + 2*4)
+			t.rec.XHeight = t.ReadShort()
 			t.rec.CapHeight = t.ReadShort()
 		} else {
 			t.rec.CapHeight = 0
+			t.rec.XHeight = 0
 		}
 	}
 	return
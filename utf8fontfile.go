@@ -18,8 +18,10 @@ package gofpdf
 
 import (
 	"bytes"
+	"crypto/sha1"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 	"sort"
 )
@@ -46,6 +48,8 @@ type utf8FontFile struct {
 	fontElementSize      int
 	Bbox                 fontBoxType
 	CapHeight            int
+	XHeight              int
+	EmbeddingFlags       int
 	StemV                int
 	ItalicAngle          int
 	Flags                int
@@ -55,6 +59,10 @@ type utf8FontFile struct {
 	DefaultWidth         float64
 	symbolData           map[int]map[string][]int
 	CodeSymbolDictionary map[int]int
+	assignedCodes        map[int]rune   // Codes assigned to glyphs that no character maps to
+	assignedSymbols      map[int]int    // Glyphs of the assigned codes
+	assignedChars        map[int]string // Characters represented by the assigned codes
+	cffOutlines          *cffFont       // CFF table read for glyph outlines, nil until needed
 }
 
 type tableDescription struct {
@@ -67,9 +75,26 @@ type tableDescription struct {
 type fileReader struct {
 	readerPosition int64
 	array          []byte
+	r              io.ReaderAt // Font read on demand if array is nil
+	size           int64       // Size of the font read from r
+	err            error       // First error reading from r
 }
 
 func (fr *fileReader) Read(s int) []byte {
+	if fr.array == nil {
+		b := make([]byte, s)
+		if fr.err == nil {
+			n, err := fr.r.ReadAt(b, fr.readerPosition)
+			if n < s {
+				if err == nil || err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				fr.err = err
+			}
+		}
+		fr.readerPosition += int64(s)
+		return b
+	}
 	b := fr.array[fr.readerPosition : fr.readerPosition+int64(s)]
 	fr.readerPosition += int64(s)
 	return b
@@ -81,11 +106,33 @@ func (fr *fileReader) seek(shift int64, flag int) (int64, error) {
 	} else if flag == 1 {
 		fr.readerPosition += shift
 	} else if flag == 2 {
-		fr.readerPosition = int64(len(fr.array)) - shift
+		fr.readerPosition = fr.length() - shift
 	}
 	return int64(fr.readerPosition), nil
 }
 
+// length returns the size of the font.
+func (fr *fileReader) length() int64 {
+	if fr.array == nil {
+		return fr.size
+	}
+	return int64(len(fr.array))
+}
+
+// sum returns the SHA-1 checksum of the font. A font read on demand is
+// streamed through the hash rather than being held in memory.
+func (fr *fileReader) sum() ([]byte, error) {
+	if fr.array != nil {
+		sum := sha1.Sum(fr.array)
+		return sum[:], nil
+	}
+	h := sha1.New()
+	if _, err := io.Copy(h, io.NewSectionReader(fr.r, 0, fr.size)); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
 func newUTF8Font(reader *fileReader) *utf8FontFile {
 	utf := utf8FontFile{
 		fileReader: reader,
@@ -102,8 +149,17 @@ func (utf *utf8FontFile) parseFile() error {
 	utf.Ascent = 0
 	utf.Descent = 0
 	codeType := uint32(utf.readUint32())
+	if utf.fileReader.err != nil {
+		return utf.fileReader.err
+	}
 	if codeType == 0x4F54544F {
-		return fmt.Errorf("not supported\n ")
+		// OpenType with PostScript outlines
+		utf.generateTableDescriptions()
+		if !utf.isCFF() {
+			return fmt.Errorf("OpenType font has no CFF table")
+		}
+		utf.parseTables()
+		return utf.fileReader.err
 	}
 	if codeType == 0x74746366 {
 		return fmt.Errorf("not supported\n ")
@@ -113,7 +169,7 @@ func (utf *utf8FontFile) parseFile() error {
 	}
 	utf.generateTableDescriptions()
 	utf.parseTables()
-	return nil
+	return utf.fileReader.err
 }
 
 func (utf *utf8FontFile) generateTableDescriptions() {
@@ -386,11 +442,7 @@ func (utf *utf8FontFile) parseOS2Table() int {
 		utf.skip(2)
 		weightType = utf.readUint16()
 		utf.skip(2)
-		fsType := utf.readUint16()
-		if fsType == 0x0002 || (fsType&0x0300) != 0 {
-			fmt.Printf("ERROR - copyright restrictions.\n")
-			return 0
-		}
+		utf.EmbeddingFlags = utf.readUint16()
 		utf.skip(20)
 		_ = utf.readInt16()
 
@@ -404,11 +456,11 @@ func (utf *utf8FontFile) parseOS2Table() int {
 			utf.Descent = int(float64(sTypoDescender) * scale)
 		}
 		if version > 1 {
-			utf.skip(16)
+			utf.skip(14)
+			sxHeight := utf.readInt16()
+			utf.XHeight = int(float64(sxHeight) * scale)
 			sCapHeight := utf.readInt16()
 			utf.CapHeight = int(float64(sCapHeight) * scale)
-		} else {
-			utf.CapHeight = utf.Ascent
 		}
 	} else {
 		weightType = 500
@@ -418,7 +470,6 @@ func (utf *utf8FontFile) parseOS2Table() int {
 		if utf.Descent == 0 {
 			utf.Descent = int(float64(utf.Bbox.Ymin) * scale)
 		}
-		utf.CapHeight = utf.Ascent
 	}
 	utf.StemV = 50 + int(math.Pow(float64(weightType)/65.0, 2))
 	return weightType
@@ -447,31 +498,44 @@ func (utf *utf8FontFile) parsePOSTTable(weight int) {
 }
 
 func (utf *utf8FontFile) parseCMAPTable(format int) int {
+	cidCMAPPosition := utf.findCMAP()
+	if cidCMAPPosition == 0 {
+		fmt.Printf("Font does not have cmap for Unicode\n")
+		return cidCMAPPosition
+	}
+	return cidCMAPPosition
+}
+
+// findCMAP returns the position of the Unicode subtable of the cmap table, or
+// 0 if there is none. A format 12 subtable, which covers the characters
+// outside the Basic Multilingual Plane, is preferred to a format 4 subtable,
+// which only covers the Basic Multilingual Plane.
+func (utf *utf8FontFile) findCMAP() int {
 	cmapPosition := utf.SeekTable("cmap")
 	utf.skip(2)
 	cmapTableCount := utf.readUint16()
-	cidCMAPPosition := 0
+	bmpPosition, fullPosition := 0, 0
 	for i := 0; i < cmapTableCount; i++ {
 		system := utf.readUint16()
 		coded := utf.readUint16()
 		position := utf.readUint32()
 		oldReaderPosition := utf.fileReader.readerPosition
-		if (system == 3 && coded == 1) || system == 0 { // Microsoft, Unicode
-			format = utf.getUint16(cmapPosition + position)
-			if format == 4 {
-				if cidCMAPPosition == 0 {
-					cidCMAPPosition = cmapPosition + position
-				}
-				break
+		switch format := utf.getUint16(cmapPosition + position); {
+		case format == 4 && ((system == 3 && coded == 1) || system == 0): // Microsoft, Unicode
+			if bmpPosition == 0 {
+				bmpPosition = cmapPosition + position
+			}
+		case format == 12 && ((system == 3 && coded == 10) || system == 0):
+			if fullPosition == 0 {
+				fullPosition = cmapPosition + position
 			}
 		}
 		utf.seek(int(oldReaderPosition))
 	}
-	if cidCMAPPosition == 0 {
-		fmt.Printf("Font does not have cmap for Unicode\n")
-		return cidCMAPPosition
+	if fullPosition != 0 {
+		return fullPosition
 	}
-	return cidCMAPPosition
+	return bmpPosition
 }
 
 func (utf *utf8FontFile) parseTables() {
@@ -491,29 +555,22 @@ func (utf *utf8FontFile) parseTables() {
 	utf.generateSCCSDictionaries(runeCMAPPosition, symbolCharDictionary, charSymbolDictionary)
 
 	scale := 1000.0 / float64(utf.fontElementSize)
+	// Heights that the OS/2 table lacks are those of the outlines of
+	// characters
+	if utf.XHeight == 0 {
+		utf.XHeight = utf.symbolHeight(charSymbolDictionary['x'], scale)
+	}
+	if utf.CapHeight == 0 {
+		utf.CapHeight = utf.symbolHeight(charSymbolDictionary['H'], scale)
+	}
+	if utf.CapHeight == 0 {
+		utf.CapHeight = utf.Ascent
+	}
 	utf.parseHMTXTable(n, numSymbols, symbolCharDictionary, scale)
 }
 
 func (utf *utf8FontFile) generateCMAP() map[int][]int {
-	cmapPosition := utf.SeekTable("cmap")
-	utf.skip(2)
-	cmapTableCount := utf.readUint16()
-	runeCmapPosition := 0
-	for i := 0; i < cmapTableCount; i++ {
-		system := utf.readUint16()
-		coder := utf.readUint16()
-		position := utf.readUint32()
-		oldPosition := utf.fileReader.readerPosition
-		if (system == 3 && coder == 1) || system == 0 {
-			format := utf.getUint16(cmapPosition + position)
-			if format == 4 {
-				runeCmapPosition = cmapPosition + position
-				break
-			}
-		}
-		utf.seek(int(oldPosition))
-	}
-
+	runeCmapPosition := utf.findCMAP()
 	if runeCmapPosition == 0 {
 		fmt.Printf("Font does not have cmap for Unicode\n")
 		return nil
@@ -522,6 +579,10 @@ func (utf *utf8FontFile) generateCMAP() map[int][]int {
 	symbolCharDictionary := make(map[int][]int)
 	charSymbolDictionary := make(map[int]int)
 	utf.generateSCCSDictionaries(runeCmapPosition, symbolCharDictionary, charSymbolDictionary)
+	for char, symbol := range utf.assignedSymbols {
+		charSymbolDictionary[char] = symbol
+		symbolCharDictionary[symbol] = append(symbolCharDictionary[symbol], char)
+	}
 
 	utf.charSymbolDictionary = charSymbolDictionary
 
@@ -647,6 +708,9 @@ func (utf *utf8FontFile) GenerateCutFont(usedRunes map[int]int) []byte {
 	utf.skip(4)
 	utf.LastRune = 0
 	utf.generateTableDescriptions()
+	if utf.fileReader.err != nil {
+		return nil
+	}
 
 	utf.SeekTable("head")
 	utf.skip(50)
@@ -687,7 +751,15 @@ func (utf *utf8FontFile) GenerateCutFont(usedRunes map[int]int) []byte {
 
 	delete(cidSymbolPairCollection, 0)
 
-	utf.setOutTable("cmap", utf.generateCMAPTable(cidSymbolPairCollection, numSymbols))
+	// The format 4 cmap of the subset only maps the characters of the Basic
+	// Multilingual Plane; PDF viewers select glyphs through the CIDToGIDMap
+	bmpSymbolPairCollection := make(map[int]int, len(cidSymbolPairCollection))
+	for char, symbol := range cidSymbolPairCollection {
+		if char <= 0xFFFF {
+			bmpSymbolPairCollection[char] = symbol
+		}
+	}
+	utf.setOutTable("cmap", utf.generateCMAPTable(bmpSymbolPairCollection, numSymbols))
 
 	symbolData := utf.getTableData("glyf")
 
@@ -836,7 +908,17 @@ func (utf *utf8FontFile) parseHMTXTable(numberOfHMetrics, numSymbols int, symbol
 	start := utf.SeekTable("hmtx")
 	arrayWidths := 0
 	var arr []int
-	utf.CharWidths = make([]int, 256*256)
+	// The widths cover the Basic Multilingual Plane and the characters of the
+	// font beyond it
+	size := 256 * 256
+	for _, chars := range symbolToChar {
+		for _, char := range chars {
+			if char < 196608 && char >= size {
+				size = char + 1
+			}
+		}
+	}
+	utf.CharWidths = make([]int, size)
 	charCount := 0
 	arr = unpackUint16Array(utf.getRange(start, numberOfHMetrics*4))
 	for symbol := 0; symbol < numberOfHMetrics; symbol++ {
@@ -922,6 +1004,10 @@ func (utf *utf8FontFile) parseLOCATable(format, numSymbols int) {
 }
 
 func (utf *utf8FontFile) generateSCCSDictionaries(runeCmapPosition int, symbolCharDictionary map[int][]int, charSymbolDictionary map[int]int) {
+	if utf.getUint16(runeCmapPosition) == 12 {
+		utf.generateSCCSDictionaries12(runeCmapPosition, symbolCharDictionary, charSymbolDictionary)
+		return
+	}
 	maxRune := 0
 	utf.seek(runeCmapPosition + 2)
 	size := utf.readUint16()
@@ -975,6 +1061,27 @@ func (utf *utf8FontFile) generateSCCSDictionaries(runeCmapPosition int, symbolCh
 	}
 }
 
+// generateSCCSDictionaries12 fills the dictionaries from a format 12 cmap
+// subtable, which maps ranges of characters to ranges of glyphs. Characters
+// beyond the Supplementary Ideographic Plane are left out.
+func (utf *utf8FontFile) generateSCCSDictionaries12(runeCmapPosition int, symbolCharDictionary map[int][]int, charSymbolDictionary map[int]int) {
+	utf.seek(runeCmapPosition + 12)
+	groupCount := utf.readUint32()
+	for n := 0; n < groupCount; n++ {
+		first := utf.readUint32()
+		last := utf.readUint32()
+		symbol := utf.readUint32()
+		if last >= 196608 {
+			last = 196607
+		}
+		for char := first; char <= last; char++ {
+			charSymbolDictionary[char] = symbol
+			symbolCharDictionary[symbol] = append(symbolCharDictionary[symbol], char)
+			symbol++
+		}
+	}
+}
+
 func max(i, n int) int {
 	if n > i {
 		return n
@@ -1152,3 +1259,47 @@ func UTF8CutFont(inBuf []byte, cutset string) (outBuf []byte) {
 	outBuf = f.GenerateCutFont(runes)
 	return
 }
+
+// symbolWidth returns the advance width of a glyph in thousandths of the
+// font size, with 65535 for a zero width, as in CharWidths.
+func (utf *utf8FontFile) symbolWidth(symbol int) int {
+	utf.SeekTable("hhea")
+	utf.skip(34)
+	metricsCount := utf.readUint16()
+	if symbol >= metricsCount {
+		symbol = metricsCount - 1
+	}
+	start := utf.SeekTable("hmtx")
+	width := int(math.Round(1000 * float64(utf.getUint16(start+4*symbol)) / float64(utf.fontElementSize)))
+	if width == 0 {
+		width = 65535
+	}
+	return width
+}
+
+// symbolHeight returns the height of the top of the outline of a glyph above
+// the baseline, in thousandths of the font size, or zero if the glyph has no
+// outline or the font has no glyf table.
+func (utf *utf8FontFile) symbolHeight(symbol int, scale float64) int {
+	glyf, ok := utf.tableDescriptions["glyf"]
+	if !ok || symbol <= 0 {
+		return 0
+	}
+	start, end := utf.symbolRange(symbol)
+	if end <= start {
+		return 0
+	}
+	// yMax of the glyph header
+	return int(float64(int16(utf.getUint16(glyf.position+start+8))) * scale)
+}
+
+// symbolRange returns the start and end of the data of a glyph in the glyf
+// table, as given by the loca table.
+func (utf *utf8FontFile) symbolRange(symbol int) (start, end int) {
+	if utf.getUint16(utf.SeekTable("head")+50) == 0 {
+		utf.seekTable("loca", 2*symbol)
+		return 2 * utf.readUint16(), 2 * utf.readUint16()
+	}
+	utf.seekTable("loca", 4*symbol)
+	return utf.readUint32(), utf.readUint32()
+}
@@ -259,10 +259,14 @@ func UnicodeTranslatorFromFile(fileStr string) (f func(string) string, err error
 // translate, where possible, utf-8 strings to a form that is compatible with
 // the specified code page. See UnicodeTranslator for more details.
 //
-// cpStr identifies a code page. A descriptor file in the font directory, set
-// with the fontDirStr argument in the call to New(), should have this name
-// plus the extension ".map". If cpStr is empty, it will be replaced with
-// "cp1252", the gofpdf code page default.
+// cpStr identifies a code page. The code pages cp874, cp1250 to cp1255,
+// cp1257, cp1258, iso-8859-1, iso-8859-2, iso-8859-4, iso-8859-5,
+// iso-8859-7, iso-8859-9, iso-8859-11, iso-8859-15, iso-8859-16, koi8-r and
+// koi8-u are embedded in the package and need no font directory. For other
+// code pages, a descriptor file in the font directory, set with the
+// fontDirStr argument in the call to New(), should have this name plus the
+// extension ".map". If cpStr is empty, it will be replaced with "cp1252", the
+// gofpdf code page default.
 //
 // If an error occurs reading the descriptor, the returned function is valid
 // but does not perform any rune translation.
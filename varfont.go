@@ -0,0 +1,946 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// VariableFontInstance returns a static instance of a TrueType variable font,
+// which can be added to a document with AddUTF8FontFromBytes(). axes gives
+// the value of the axes of the instance by tag, for example
+//
+//	map[string]float64{"wght": 600, "wdth": 87.5}
+//
+// Axes that are not given take their default value, and values are limited
+// to the range of their axis. The outlines and advance widths of the glyphs
+// are those of the instance, and the weight and width classes of the font are
+// set from the "wght" and "wdth" axes. The PostScript name of the instance is
+// that of the variable font followed by the values of the axes that differ
+// from their default, as in "Inter_600wght", so that several instances of a
+// font can be used in a document. Variations of font-wide metrics and of
+// hinting are not applied.
+//
+// An error is returned if the font is not a variable font, if it has
+// PostScript (CFF2) outlines or if axes names an axis it does not have. The
+// font may be packaged as a WOFF or WOFF2 web font.
+func VariableFontInstance(font []byte, axes map[string]float64) ([]byte, error) {
+	font, err := sfntFromWebFont(font)
+	if err != nil {
+		return nil, err
+	}
+	flavor, tables, err := sfntTables(font)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := tables["fvar"]; !ok {
+		return nil, fmt.Errorf("font is not a variable font")
+	}
+	if _, ok := tables["CFF2"]; ok {
+		return nil, fmt.Errorf("variable fonts with PostScript outlines are not supported")
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "loca", "glyf"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("font has no %s table", tag)
+		}
+	}
+	v := varInstance{tables: tables}
+	err = v.parseAxes(axes)
+	if err == nil {
+		err = v.parseGlyphs()
+	}
+	if err == nil {
+		err = v.applyVariations()
+	}
+	if err != nil {
+		return nil, err
+	}
+	v.build()
+	list := make([]sfntTable, 0, len(tables))
+	for tag, data := range v.tables {
+		list = append(list, sfntTable{tag: tag, data: data})
+	}
+	return sfntAssemble(flavor, list), nil
+}
+
+// AddUTF8FontInstance imports a static instance of a TrueType variable font
+// with the axis values given by axes, and makes it available as a UTF-8 font
+// with the specified family and style. This allows the regular, bold and other
+// styles of a family to be derived from a single variable font, for example
+//
+//	pdf.AddUTF8FontInstance("inter", "", "InterVariable.ttf", nil)
+//	pdf.AddUTF8FontInstance("inter", "B", "InterVariable.ttf", map[string]float64{"wght": 700})
+//
+// The file is loaded from the font directory as with AddUTF8Font(). See
+// VariableFontInstance() for the instancing of the font.
+func (f *Fpdf) AddUTF8FontInstance(familyStr, styleStr, fileStr string, axes map[string]float64) {
+	if f.err != nil {
+		return
+	}
+	if _, ok := f.fonts[getFontKey(fontFamilyEscape(familyStr), styleStr)]; ok {
+		return
+	}
+	data, err := ioutil.ReadFile(path.Join(f.fontpath, fileStr))
+	if err == nil {
+		data, err = VariableFontInstance(data, axes)
+	}
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	f.AddUTF8FontFromBytes(familyStr, styleStr, data)
+}
+
+// sfntTables returns the flavor and the tables of a TrueType or OpenType font.
+func sfntTables(font []byte) (flavor uint32, tables map[string][]byte, err error) {
+	r := sfntReader{data: font}
+	flavor = uint32(r.u32())
+	n := r.u16()
+	r.bytes(6)
+	tables = make(map[string][]byte, n)
+	for j := 0; j < n && r.err == nil; j++ {
+		tag := string(r.bytes(4))
+		r.u32()
+		offset, length := r.u32(), r.u32()
+		if offset+length > len(font) {
+			return 0, nil, fmt.Errorf("invalid font data")
+		}
+		tables[tag] = font[offset : offset+length]
+	}
+	return flavor, tables, r.err
+}
+
+// varAxis is an axis of a variable font.
+type varAxis struct {
+	tag               string
+	min, def, max     float64
+	value, normalized float64
+}
+
+// varComponent is a component of a composite glyph.
+type varComponent struct {
+	flags, glyph int
+	arg1, arg2   int
+	transform    []byte
+}
+
+// varGlyph is a glyph of a variable font.
+type varGlyph struct {
+	contours     int // -1 for composite glyphs
+	endPts       []int
+	instructions []byte
+	xs, ys       []int // Simple glyphs
+	onCurve      []bool
+	overlap      bool
+	components   []varComponent // Composite glyphs
+	// Phantom points, of which the first two give the horizontal origin and
+	// advance of the glyph
+	phantomX   [4]float64
+	xMin, yMin int
+	xMax, yMax int
+}
+
+// varInstance holds the tables of a variable font while an instance is
+// built from them.
+type varInstance struct {
+	tables   map[string][]byte
+	axes     []varAxis
+	glyphs   []varGlyph
+	advances []int
+	lsbs     []int
+}
+
+func (v *varInstance) parseAxes(values map[string]float64) error {
+	r := sfntReader{data: v.tables["fvar"]}
+	r.u32()
+	axesOffset := r.u16()
+	r.u16()
+	count, size := r.u16(), r.u16()
+	if r.err != nil || size < 20 {
+		return fmt.Errorf("invalid fvar table")
+	}
+	fixed := func() float64 { return float64(int32(r.u32())) / 65536 }
+	for j := 0; j < count; j++ {
+		r.pos = axesOffset + j*size
+		var a varAxis
+		a.tag = string(r.bytes(4))
+		a.min, a.def, a.max = fixed(), fixed(), fixed()
+		v.axes = append(v.axes, a)
+	}
+	if r.err != nil {
+		return fmt.Errorf("invalid fvar table")
+	}
+	for tag := range values {
+		found := false
+		for _, a := range v.axes {
+			found = found || a.tag == tag
+		}
+		if !found {
+			return fmt.Errorf("font has no %s axis", tag)
+		}
+	}
+	for j := range v.axes {
+		a := &v.axes[j]
+		a.value = a.def
+		if value, ok := values[a.tag]; ok {
+			a.value = math.Max(a.min, math.Min(a.max, value))
+		}
+		switch {
+		case a.value < a.def:
+			a.normalized = (a.value - a.def) / (a.def - a.min)
+		case a.value > a.def:
+			a.normalized = (a.value - a.def) / (a.max - a.def)
+		}
+	}
+	// The avar table maps the normalized values piecewise linearly
+	if avar, ok := v.tables["avar"]; ok {
+		r = sfntReader{data: avar}
+		r.bytes(6)
+		n := r.u16()
+		f2dot14 := func() float64 { return float64(int16(r.u16())) / 16384 }
+		for j := 0; j < n && j < len(v.axes) && r.err == nil; j++ {
+			pairs := make([][2]float64, r.u16())
+			for k := range pairs {
+				pairs[k] = [2]float64{f2dot14(), f2dot14()}
+			}
+			a := &v.axes[j]
+			for k := 1; k < len(pairs); k++ {
+				from0, from1 := pairs[k-1][0], pairs[k][0]
+				if a.normalized <= from1 && from1 > from0 {
+					to0, to1 := pairs[k-1][1], pairs[k][1]
+					a.normalized = to0 + (a.normalized-from0)*(to1-to0)/(from1-from0)
+					break
+				}
+			}
+		}
+		if r.err != nil {
+			return fmt.Errorf("invalid avar table")
+		}
+	}
+	for j := range v.axes {
+		v.axes[j].normalized = math.Round(v.axes[j].normalized*16384) / 16384
+	}
+	return nil
+}
+
+func (v *varInstance) parseGlyphs() error {
+	head, hhea, maxp := v.tables["head"], v.tables["hhea"], v.tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return fmt.Errorf("invalid font data")
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	longLoca := binary.BigEndian.Uint16(head[50:]) == 1
+	numHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	loca, glyf, hmtx := v.tables["loca"], v.tables["glyf"], v.tables["hmtx"]
+	if numHMetrics < 1 || numHMetrics > numGlyphs || len(hmtx) < 2*numGlyphs+2*numHMetrics {
+		return fmt.Errorf("invalid hmtx table")
+	}
+	v.glyphs = make([]varGlyph, numGlyphs)
+	v.advances = make([]int, numGlyphs)
+	v.lsbs = make([]int, numGlyphs)
+	lr := sfntReader{data: loca}
+	offset := func() int {
+		if longLoca {
+			return lr.u32()
+		}
+		return 2 * lr.u16()
+	}
+	start := offset()
+	for gid := range v.glyphs {
+		end := offset()
+		if lr.err != nil || end < start || end > len(glyf) {
+			return fmt.Errorf("invalid loca table")
+		}
+		g := &v.glyphs[gid]
+		if err := g.parse(glyf[start:end]); err != nil {
+			return err
+		}
+		start = end
+		if gid < numHMetrics {
+			v.advances[gid] = int(binary.BigEndian.Uint16(hmtx[4*gid:]))
+			v.lsbs[gid] = int(int16(binary.BigEndian.Uint16(hmtx[4*gid+2:])))
+		} else {
+			v.advances[gid] = v.advances[numHMetrics-1]
+			v.lsbs[gid] = int(int16(binary.BigEndian.Uint16(hmtx[2*numHMetrics+2*gid:])))
+		}
+		g.phantomX[0] = float64(g.xMin - v.lsbs[gid])
+		g.phantomX[1] = g.phantomX[0] + float64(v.advances[gid])
+	}
+	return nil
+}
+
+// parse reads the glyph from its glyf table data.
+func (g *varGlyph) parse(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	r := sfntReader{data: data}
+	g.contours = int(int16(r.u16()))
+	g.xMin, g.yMin = int(int16(r.u16())), int(int16(r.u16()))
+	g.xMax, g.yMax = int(int16(r.u16())), int(int16(r.u16()))
+	if g.contours >= 0 {
+		g.endPts = make([]int, g.contours)
+		for j := range g.endPts {
+			g.endPts[j] = r.u16()
+		}
+		g.instructions = r.bytes(r.u16())
+		n := 0
+		if g.contours > 0 {
+			n = g.endPts[g.contours-1] + 1
+		}
+		if r.err != nil || n > len(data) {
+			return fmt.Errorf("invalid glyf table")
+		}
+		flags := make([]int, 0, n)
+		for len(flags) < n && r.err == nil {
+			flag := r.u8()
+			flags = append(flags, flag)
+			if flag&0x08 != 0 {
+				for k := r.u8(); k > 0 && len(flags) < n; k-- {
+					flags = append(flags, flag)
+				}
+			}
+		}
+		g.xs, g.ys, g.onCurve = make([]int, n), make([]int, n), make([]bool, n)
+		g.overlap = n > 0 && flags[0]&0x40 != 0
+		coords := func(values []int, shortBit, sameBit int) {
+			c := 0
+			for j, flag := range flags {
+				switch {
+				case flag&shortBit != 0 && flag&sameBit != 0:
+					c += r.u8()
+				case flag&shortBit != 0:
+					c -= r.u8()
+				case flag&sameBit == 0:
+					c += int(int16(r.u16()))
+				}
+				values[j] = c
+			}
+		}
+		coords(g.xs, 0x02, 0x10)
+		coords(g.ys, 0x04, 0x20)
+		for j, flag := range flags {
+			g.onCurve[j] = flag&0x01 != 0
+		}
+	} else {
+		haveInstructions := false
+		for more := true; more && r.err == nil; {
+			var c varComponent
+			c.flags, c.glyph = r.u16(), r.u16()
+			switch {
+			case c.flags&0x0001 != 0 && c.flags&0x0002 != 0:
+				c.arg1, c.arg2 = int(int16(r.u16())), int(int16(r.u16()))
+			case c.flags&0x0001 != 0:
+				c.arg1, c.arg2 = r.u16(), r.u16()
+			case c.flags&0x0002 != 0:
+				c.arg1, c.arg2 = int(int8(r.u8())), int(int8(r.u8()))
+			default:
+				c.arg1, c.arg2 = r.u8(), r.u8()
+			}
+			switch {
+			case c.flags&0x0008 != 0:
+				c.transform = r.bytes(2)
+			case c.flags&0x0040 != 0:
+				c.transform = r.bytes(4)
+			case c.flags&0x0080 != 0:
+				c.transform = r.bytes(8)
+			}
+			g.components = append(g.components, c)
+			more = c.flags&0x0020 != 0
+			haveInstructions = haveInstructions || c.flags&0x0100 != 0
+		}
+		if haveInstructions {
+			g.instructions = r.bytes(r.u16())
+		}
+	}
+	if r.err != nil {
+		return fmt.Errorf("invalid glyf table")
+	}
+	return nil
+}
+
+// points returns the number of points of the glyph that have deltas, which
+// are the points of a simple glyph or the components of a composite glyph,
+// followed by the four phantom points.
+func (g *varGlyph) points() int {
+	if g.contours < 0 {
+		return len(g.components) + 4
+	}
+	return len(g.xs) + 4
+}
+
+// applyVariations applies the deltas of the gvar table to the glyphs.
+func (v *varInstance) applyVariations() error {
+	gvar, ok := v.tables["gvar"]
+	if !ok {
+		return nil
+	}
+	bad := fmt.Errorf("invalid gvar table")
+	r := sfntReader{data: gvar}
+	r.u32()
+	axisCount, sharedCount := r.u16(), r.u16()
+	sharedOffset := r.u32()
+	glyphCount, flags := r.u16(), r.u16()
+	dataOffset := r.u32()
+	if r.err != nil || axisCount != len(v.axes) || glyphCount > len(v.glyphs) {
+		return bad
+	}
+	offsets := make([]int, glyphCount+1)
+	for j := range offsets {
+		if flags&1 != 0 {
+			offsets[j] = r.u32()
+		} else {
+			offsets[j] = 2 * r.u16()
+		}
+	}
+	tuple := func(r *sfntReader) []float64 {
+		t := make([]float64, axisCount)
+		for j := range t {
+			t[j] = float64(int16(r.u16())) / 16384
+		}
+		return t
+	}
+	r.pos = sharedOffset
+	shared := make([][]float64, sharedCount)
+	for j := range shared {
+		shared[j] = tuple(&r)
+	}
+	if r.err != nil {
+		return bad
+	}
+	for gid := 0; gid < glyphCount; gid++ {
+		start, end := dataOffset+offsets[gid], dataOffset+offsets[gid+1]
+		if end <= start {
+			continue
+		}
+		if end > len(gvar) {
+			return bad
+		}
+		g := &v.glyphs[gid]
+		n := g.points()
+		dx, dy := make([]float64, n), make([]float64, n)
+		hr := sfntReader{data: gvar[start:end]}
+		count := hr.u16()
+		dr := sfntReader{data: hr.data, pos: hr.u16()}
+		var sharedPoints []int
+		if count&0x8000 != 0 {
+			sharedPoints = varPoints(&dr)
+		}
+		for j := 0; j < count&0x0fff && hr.err == nil; j++ {
+			size, index := hr.u16(), hr.u16()
+			var peak, lo, hi []float64
+			if index&0x8000 != 0 {
+				peak = tuple(&hr)
+			} else if index&0x0fff < len(shared) {
+				peak = shared[index&0x0fff]
+			} else {
+				return bad
+			}
+			if index&0x4000 != 0 {
+				lo, hi = tuple(&hr), tuple(&hr)
+			}
+			next := dr.pos + size
+			scalar := v.scalar(peak, lo, hi)
+			if scalar != 0 {
+				points := sharedPoints
+				if index&0x2000 != 0 {
+					points = varPoints(&dr)
+				}
+				m := len(points)
+				if points == nil {
+					m = n
+				}
+				xs, ys := varDeltas(&dr, m), varDeltas(&dr, m)
+				if dr.err != nil {
+					return bad
+				}
+				g.addDeltas(dx, dy, points, xs, ys, scalar)
+			}
+			dr.pos = next
+		}
+		if hr.err != nil || dr.err != nil {
+			return bad
+		}
+		g.move(dx, dy)
+	}
+	return nil
+}
+
+// scalar returns the factor by which the deltas of the tuple variation with
+// the specified peak and intermediate region are applied to the instance.
+func (v *varInstance) scalar(peak, lo, hi []float64) float64 {
+	scalar := 1.0
+	for j, a := range v.axes {
+		p, c := peak[j], a.normalized
+		if p == 0 {
+			continue
+		}
+		s, e := math.Min(0, p), math.Max(0, p)
+		if lo != nil {
+			s, e = lo[j], hi[j]
+			if s > p || p > e || s < 0 && e > 0 {
+				continue
+			}
+		}
+		switch {
+		case c < s || c > e:
+			return 0
+		case c < p:
+			scalar *= (c - s) / (p - s)
+		case c > p:
+			scalar *= (e - c) / (e - p)
+		}
+	}
+	return scalar
+}
+
+// varPoints reads packed point numbers. nil is returned for all points.
+func varPoints(r *sfntReader) []int {
+	n := r.u8()
+	if n&0x80 != 0 {
+		n = (n&0x7f)<<8 | r.u8()
+	}
+	if n == 0 {
+		return nil
+	}
+	points := make([]int, 0, n)
+	p := 0
+	for len(points) < n && r.err == nil {
+		control := r.u8()
+		for k := control&0x7f + 1; k > 0 && len(points) < n; k-- {
+			if control&0x80 != 0 {
+				p += r.u16()
+			} else {
+				p += r.u8()
+			}
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// varDeltas reads n packed deltas.
+func varDeltas(r *sfntReader, n int) []int {
+	deltas := make([]int, 0, n)
+	for len(deltas) < n && r.err == nil {
+		control := r.u8()
+		for k := control&0x3f + 1; k > 0 && len(deltas) < n; k-- {
+			switch {
+			case control&0x80 != 0:
+				deltas = append(deltas, 0)
+			case control&0x40 != 0:
+				deltas = append(deltas, int(int16(r.u16())))
+			default:
+				deltas = append(deltas, int(int8(r.u8())))
+			}
+		}
+	}
+	return deltas
+}
+
+// addDeltas adds the scaled deltas of a tuple variation for the specified
+// points, or for all points if points is nil, to dx and dy. The deltas of
+// the points of a simple glyph that are not specified are interpolated.
+func (g *varGlyph) addDeltas(dx, dy []float64, points, xs, ys []int, scalar float64) {
+	if points == nil {
+		for j := range dx {
+			dx[j] += scalar * float64(xs[j])
+			dy[j] += scalar * float64(ys[j])
+		}
+		return
+	}
+	n := len(dx)
+	touched := make([]bool, n)
+	tx, ty := make([]float64, n), make([]float64, n)
+	for k, p := range points {
+		if p < n {
+			touched[p] = true
+			tx[p], ty[p] = float64(xs[k]), float64(ys[k])
+		}
+	}
+	if g.contours > 0 {
+		start := 0
+		for _, end := range g.endPts {
+			if end >= len(g.xs) || end < start {
+				break
+			}
+			varInterpolate(g.xs[start:end+1], tx[start:end+1], touched[start:end+1])
+			varInterpolate(g.ys[start:end+1], ty[start:end+1], touched[start:end+1])
+			start = end + 1
+		}
+	}
+	for j := range dx {
+		dx[j] += scalar * tx[j]
+		dy[j] += scalar * ty[j]
+	}
+}
+
+// varInterpolate infers the deltas of the points of a contour that are not
+// touched from those of the nearest touched points before and after them,
+// with the coordinates cs of the points.
+func varInterpolate(cs []int, deltas []float64, touched []bool) {
+	n := len(cs)
+	first := -1
+	for j := 0; j < n; j++ {
+		if touched[j] {
+			first = j
+			break
+		}
+	}
+	if first < 0 {
+		return
+	}
+	prev := first
+	for k := 1; k <= n; k++ {
+		j := (first + k) % n
+		if !touched[j] {
+			continue
+		}
+		// Points strictly between prev and j
+		for m := (prev + 1) % n; m != j; m = (m + 1) % n {
+			deltas[m] = varInterpolated(cs[m], cs[prev], cs[j], deltas[prev], deltas[j])
+		}
+		prev = j
+	}
+}
+
+func varInterpolated(c, c1, c2 int, d1, d2 float64) float64 {
+	if c1 > c2 {
+		c1, c2, d1, d2 = c2, c1, d2, d1
+	}
+	switch {
+	case c1 == c2:
+		if d1 == d2 {
+			return d1
+		}
+		return 0
+	case c <= c1:
+		return d1
+	case c >= c2:
+		return d2
+	}
+	return d1 + float64(c-c1)*(d2-d1)/float64(c2-c1)
+}
+
+// move applies the accumulated deltas to the glyph.
+func (g *varGlyph) move(dx, dy []float64) {
+	round := func(v float64) int { return int(math.Floor(v + 0.5)) }
+	n := len(dx) - 4
+	if g.contours < 0 {
+		for j := range g.components {
+			c := &g.components[j]
+			if c.flags&0x0002 != 0 {
+				c.arg1 = round(float64(c.arg1) + dx[j])
+				c.arg2 = round(float64(c.arg2) + dy[j])
+			}
+		}
+	} else {
+		for j := range g.xs {
+			g.xs[j] = round(float64(g.xs[j]) + dx[j])
+			g.ys[j] = round(float64(g.ys[j]) + dy[j])
+		}
+	}
+	for j := 0; j < 2; j++ {
+		g.phantomX[j] += dx[n+j]
+	}
+}
+
+// outline returns the points of the glyph, resolving components.
+func (v *varInstance) outline(gid, depth int) (xs, ys []float64) {
+	if gid >= len(v.glyphs) || depth > 8 {
+		return
+	}
+	g := &v.glyphs[gid]
+	if g.contours >= 0 {
+		for j := range g.xs {
+			xs = append(xs, float64(g.xs[j]))
+			ys = append(ys, float64(g.ys[j]))
+		}
+		return
+	}
+	for _, c := range g.components {
+		cxs, cys := v.outline(c.glyph, depth+1)
+		a, b, cc, d := 1.0, 0.0, 0.0, 1.0
+		t := func(j int) float64 { return float64(int16(binary.BigEndian.Uint16(c.transform[2*j:]))) / 16384 }
+		switch len(c.transform) {
+		case 2:
+			a, d = t(0), t(0)
+		case 4:
+			a, d = t(0), t(1)
+		case 8:
+			a, b, cc, d = t(0), t(1), t(2), t(3)
+		}
+		for j := range cxs {
+			cxs[j], cys[j] = a*cxs[j]+cc*cys[j], b*cxs[j]+d*cys[j]
+		}
+		var ox, oy float64
+		if c.flags&0x0002 != 0 {
+			ox, oy = float64(c.arg1), float64(c.arg2)
+		} else if c.arg1 < len(xs) && c.arg2 < len(cxs) {
+			// The components are positioned by matching points
+			ox, oy = xs[c.arg1]-cxs[c.arg2], ys[c.arg1]-cys[c.arg2]
+		}
+		for j := range cxs {
+			xs = append(xs, cxs[j]+ox)
+			ys = append(ys, cys[j]+oy)
+		}
+	}
+	return
+}
+
+// build replaces the tables of the variable font by those of the instance.
+func (v *varInstance) build() {
+	var glyf, loca, hmtx bytes.Buffer
+	wr := func(buf *bytes.Buffer, value interface{}) { binary.Write(buf, binary.BigEndian, value) }
+	bbox := [4]int{math.MaxInt16, math.MaxInt16, math.MinInt16, math.MinInt16}
+	advanceMax, minLsb, minRsb, maxExtent := 0, math.MaxInt16, math.MaxInt16, math.MinInt16
+	for gid := range v.glyphs {
+		g := &v.glyphs[gid]
+		wr(&loca, uint32(glyf.Len()))
+		xs, ys := v.outline(gid, 0)
+		advance := int(math.Floor(g.phantomX[1]+0.5) - math.Floor(g.phantomX[0]+0.5))
+		if advance < 0 {
+			advance = 0
+		}
+		advanceMax = maxInt(advanceMax, advance)
+		lsb := 0
+		if len(xs) > 0 {
+			g.xMin, g.yMin = math.MaxInt16, math.MaxInt16
+			g.xMax, g.yMax = math.MinInt16, math.MinInt16
+			for j := range xs {
+				x, y := int(math.Floor(xs[j]+0.5)), int(math.Floor(ys[j]+0.5))
+				g.xMin, g.yMin = minInt(g.xMin, x), minInt(g.yMin, y)
+				g.xMax, g.yMax = maxInt(g.xMax, x), maxInt(g.yMax, y)
+			}
+			bbox = [4]int{minInt(bbox[0], g.xMin), minInt(bbox[1], g.yMin),
+				maxInt(bbox[2], g.xMax), maxInt(bbox[3], g.yMax)}
+			lsb = g.xMin - int(math.Floor(g.phantomX[0]+0.5))
+			minLsb = minInt(minLsb, lsb)
+			minRsb = minInt(minRsb, advance-lsb-(g.xMax-g.xMin))
+			maxExtent = maxInt(maxExtent, lsb+g.xMax-g.xMin)
+		}
+		wr(&hmtx, uint16(advance))
+		wr(&hmtx, int16(lsb))
+		if g.contours == 0 && len(g.xs) == 0 && len(g.instructions) == 0 || len(xs) == 0 && g.contours >= 0 {
+			// Empty glyph
+			continue
+		}
+		wr(&glyf, int16(g.contours))
+		wr(&glyf, [4]int16{int16(g.xMin), int16(g.yMin), int16(g.xMax), int16(g.yMax)})
+		if g.contours >= 0 {
+			for _, end := range g.endPts {
+				wr(&glyf, uint16(end))
+			}
+			wr(&glyf, uint16(len(g.instructions)))
+			glyf.Write(g.instructions)
+			woff2WritePoints(&glyf, g.xs, g.ys, g.onCurve, g.overlap)
+		} else {
+			for _, c := range g.components {
+				flags := c.flags &^ 0x0001
+				fits := func(v int) bool { return v >= -128 && v <= 127 }
+				if c.flags&0x0002 == 0 {
+					fits = func(v int) bool { return v >= 0 && v <= 255 }
+				}
+				words := !fits(c.arg1) || !fits(c.arg2)
+				if words {
+					flags |= 0x0001
+				}
+				wr(&glyf, uint16(flags))
+				wr(&glyf, uint16(c.glyph))
+				if words {
+					wr(&glyf, [2]uint16{uint16(c.arg1), uint16(c.arg2)})
+				} else {
+					glyf.Write([]byte{byte(c.arg1), byte(c.arg2)})
+				}
+				glyf.Write(c.transform)
+			}
+			if g.instructions != nil {
+				wr(&glyf, uint16(len(g.instructions)))
+				glyf.Write(g.instructions)
+			}
+		}
+		for glyf.Len()%4 != 0 {
+			glyf.WriteByte(0)
+		}
+	}
+	wr(&loca, uint32(glyf.Len()))
+	if bbox[0] > bbox[2] {
+		bbox = [4]int{}
+	}
+	head := append([]byte(nil), v.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	for j, value := range bbox {
+		binary.BigEndian.PutUint16(head[36+2*j:], uint16(int16(value)))
+	}
+	binary.BigEndian.PutUint16(head[50:], 1)
+	hhea := append([]byte(nil), v.tables["hhea"]...)
+	binary.BigEndian.PutUint16(hhea[10:], uint16(advanceMax))
+	if maxExtent > math.MinInt16 {
+		binary.BigEndian.PutUint16(hhea[12:], uint16(int16(minLsb)))
+		binary.BigEndian.PutUint16(hhea[14:], uint16(int16(minRsb)))
+		binary.BigEndian.PutUint16(hhea[16:], uint16(int16(maxExtent)))
+	}
+	binary.BigEndian.PutUint16(hhea[34:], uint16(len(v.glyphs)))
+	v.tables["glyf"], v.tables["loca"], v.tables["hmtx"] = glyf.Bytes(), loca.Bytes(), hmtx.Bytes()
+	v.tables["head"], v.tables["hhea"] = head, hhea
+	if os2 := v.tables["OS/2"]; len(os2) >= 8 {
+		os2 = append([]byte(nil), os2...)
+		for _, a := range v.axes {
+			switch a.tag {
+			case "wght":
+				binary.BigEndian.PutUint16(os2[4:], uint16(math.Max(1, math.Min(1000, math.Round(a.value)))))
+			case "wdth":
+				// Width classes 1 to 9 by percentage of the normal width
+				class := 1
+				for j, pct := range []float64{56.25, 68.75, 81.25, 93.75, 106.25, 118.75, 137.5, 175} {
+					if a.value >= pct {
+						class = j + 2
+					}
+				}
+				binary.BigEndian.PutUint16(os2[6:], uint16(class))
+			}
+		}
+		v.tables["OS/2"] = os2
+	}
+	if name, ok := v.tables["name"]; ok {
+		v.tables["name"] = v.renamed(name)
+	}
+	// Tables of variations and of metrics that the instance invalidates
+	for _, tag := range []string{"fvar", "gvar", "avar", "cvar", "HVAR", "VVAR", "MVAR", "STAT",
+		"hdmx", "LTSH", "VDMX", "DSIG"} {
+		delete(v.tables, tag)
+	}
+}
+
+// renamed returns the name table with the PostScript name of the instance.
+func (v *varInstance) renamed(name []byte) []byte {
+	type record struct {
+		platform, encoding, language, id int
+		str                              []byte
+	}
+	r := sfntReader{data: name}
+	format, count, storage := r.u16(), r.u16(), r.u16()
+	records := make([]record, count)
+	for j := range records {
+		rec := &records[j]
+		rec.platform, rec.encoding, rec.language, rec.id = r.u16(), r.u16(), r.u16(), r.u16()
+		length, offset := r.u16(), r.u16()
+		if storage+offset+length <= len(name) {
+			rec.str = name[storage+offset : storage+offset+length]
+		}
+	}
+	var langTags [][]byte
+	if format == 1 {
+		langTags = make([][]byte, r.u16())
+		for j := range langTags {
+			length, offset := r.u16(), r.u16()
+			if storage+offset+length <= len(name) {
+				langTags[j] = name[storage+offset : storage+offset+length]
+			}
+		}
+	}
+	if r.err != nil {
+		return name
+	}
+	decode := func(rec record) string {
+		if rec.platform == 1 {
+			return string(rec.str)
+		}
+		units := make([]uint16, len(rec.str)/2)
+		for j := range units {
+			units[j] = binary.BigEndian.Uint16(rec.str[2*j:])
+		}
+		return string(utf16.Decode(units))
+	}
+	// The name is built from the variations PostScript name prefix or the
+	// PostScript name of the font
+	prefix := ""
+	for _, id := range []int{25, 6} {
+		for _, rec := range records {
+			if rec.id == id && prefix == "" {
+				prefix = decode(rec)
+			}
+		}
+	}
+	if prefix == "" {
+		return name
+	}
+	psName := prefix
+	for _, a := range v.axes {
+		if a.value != a.def {
+			psName += "_" + strconv.FormatFloat(a.value, 'f', -1, 64) + strings.TrimRight(a.tag, " ")
+		}
+	}
+	if len(psName) > 63 {
+		psName = psName[:63]
+	}
+	var strs bytes.Buffer
+	var out bytes.Buffer
+	wr := func(value int) { binary.Write(&out, binary.BigEndian, uint16(value)) }
+	wr(format)
+	wr(count)
+	head := 6 + 12*count
+	if format == 1 {
+		head += 2 + 4*len(langTags)
+	}
+	wr(head)
+	for _, rec := range records {
+		str := rec.str
+		if rec.id == 6 {
+			if rec.platform == 1 {
+				str = []byte(psName)
+			} else {
+				str = nil
+				for _, unit := range utf16.Encode([]rune(psName)) {
+					str = append(str, byte(unit>>8), byte(unit))
+				}
+			}
+		}
+		wr(rec.platform)
+		wr(rec.encoding)
+		wr(rec.language)
+		wr(rec.id)
+		wr(len(str))
+		wr(strs.Len())
+		strs.Write(str)
+	}
+	if format == 1 {
+		wr(len(langTags))
+		for _, tag := range langTags {
+			wr(len(tag))
+			wr(strs.Len())
+			strs.Write(tag)
+		}
+	}
+	out.Write(strs.Bytes())
+	return out.Bytes()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
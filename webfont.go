@@ -0,0 +1,618 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+)
+
+// Signatures of WOFF and WOFF2 font files
+var (
+	woffSignature  = []byte("wOFF")
+	woff2Signature = []byte("wOF2")
+)
+
+var brotli struct {
+	sync.Mutex
+	decoder func(io.Reader) io.Reader
+}
+
+// SetBrotliDecoder registers the function used to decompress WOFF2 fonts.
+// The data of a WOFF2 font is compressed with Brotli, for which the standard
+// library has no decoder. Without a registered decoder, WOFF2 fonts are
+// rejected with an error. WOFF fonts need no decoder. For example, with the
+// github.com/andybalholm/brotli package:
+//
+//	gofpdf.SetBrotliDecoder(func(r io.Reader) io.Reader {
+//		return brotli.NewReader(r)
+//	})
+func SetBrotliDecoder(fn func(io.Reader) io.Reader) {
+	brotli.Lock()
+	brotli.decoder = fn
+	brotli.Unlock()
+}
+
+// sfntFromWebFont returns the TrueType or OpenType font contained in a WOFF
+// or WOFF2 file. Other data is returned as is.
+func sfntFromWebFont(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, woffSignature):
+		return woffDecode(data)
+	case bytes.HasPrefix(data, woff2Signature):
+		return woff2Decode(data)
+	}
+	return data, nil
+}
+
+// sfntTable is a table of a TrueType or OpenType font.
+type sfntTable struct {
+	tag      string
+	checksum uint32
+	data     []byte
+}
+
+// sfntAssemble builds a font file from the flavor, which is the version tag
+// of the font, and its tables. Checksums that are zero are calculated.
+func sfntAssemble(flavor uint32, tables []sfntTable) []byte {
+	sort.Slice(tables, func(i, j int) bool { return tables[i].tag < tables[j].tag })
+	n := len(tables)
+	entrySelector := 0
+	for 2<<uint(entrySelector) <= n {
+		entrySelector++
+	}
+	searchRange := 16 << uint(entrySelector)
+	var buf bytes.Buffer
+	wr := func(v interface{}) { binary.Write(&buf, binary.BigEndian, v) }
+	wr(flavor)
+	wr(uint16(n))
+	wr(uint16(searchRange))
+	wr(uint16(entrySelector))
+	wr(uint16(n*16 - searchRange))
+	offset := 12 + 16*n
+	for _, t := range tables {
+		sum := t.checksum
+		if sum == 0 {
+			sum = sfntChecksum(t.data)
+		}
+		buf.WriteString(t.tag)
+		wr(sum)
+		wr(uint32(offset))
+		wr(uint32(len(t.data)))
+		offset += (len(t.data) + 3) &^ 3
+	}
+	for _, t := range tables {
+		buf.Write(t.data)
+		buf.Write(make([]byte, (4-len(t.data)%4)%4))
+	}
+	return buf.Bytes()
+}
+
+func sfntChecksum(data []byte) (sum uint32) {
+	for j := 0; j < len(data); j += 4 {
+		var word [4]byte
+		copy(word[:], data[j:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return
+}
+
+// woffDecode extracts the font from a WOFF file, in which each table is
+// compressed with zlib unless that would not make it smaller.
+func woffDecode(data []byte) ([]byte, error) {
+	bad := fmt.Errorf("invalid WOFF font")
+	if len(data) < 44 {
+		return nil, bad
+	}
+	flavor := binary.BigEndian.Uint32(data[4:])
+	numTables := int(binary.BigEndian.Uint16(data[12:]))
+	if len(data) < 44+20*numTables {
+		return nil, bad
+	}
+	tables := make([]sfntTable, numTables)
+	for j := range tables {
+		entry := data[44+20*j:]
+		offset := int(binary.BigEndian.Uint32(entry[4:]))
+		compLength := int(binary.BigEndian.Uint32(entry[8:]))
+		origLength := int(binary.BigEndian.Uint32(entry[12:]))
+		if offset < 0 || compLength < 0 || offset+compLength > len(data) || compLength > origLength {
+			return nil, bad
+		}
+		t := sfntTable{tag: string(entry[:4]), checksum: binary.BigEndian.Uint32(entry[16:])}
+		t.data = data[offset : offset+compLength]
+		if compLength < origLength {
+			r, err := zlib.NewReader(bytes.NewReader(t.data))
+			if err != nil {
+				return nil, err
+			}
+			if t.data, err = ioutil.ReadAll(r); err != nil {
+				return nil, err
+			}
+		}
+		if len(t.data) != origLength {
+			return nil, bad
+		}
+		tables[j] = t
+	}
+	return sfntAssemble(flavor, tables), nil
+}
+
+// Tags of the tables of a WOFF2 font that are identified by their index
+var woff2KnownTags = []string{
+	"cmap", "head", "hhea", "hmtx", "maxp", "name", "OS/2", "post", "cvt ",
+	"fpgm", "glyf", "loca", "prep", "CFF ", "VORG", "EBDT", "EBLC", "gasp",
+	"hdmx", "kern", "LTSH", "PCLT", "VDMX", "vhea", "vmtx", "BASE", "GDEF",
+	"GPOS", "GSUB", "EBSC", "JSTF", "MATH", "CBDT", "CBLC", "COLR", "CPAL",
+	"SVG ", "sbix", "acnt", "avar", "bdat", "bloc", "bsln", "cvar", "fdsc",
+	"feat", "fmtx", "fvar", "gvar", "hsty", "just", "lcar", "mort", "morx",
+	"opbd", "prop", "trak", "Zapf", "Silf", "Glat", "Gloc", "Feat", "Sill",
+}
+
+// sfntReader reads the big-endian and variable-length values of font data.
+type sfntReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *sfntReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || r.pos+n > len(r.data) {
+		r.err = fmt.Errorf("invalid font data")
+		return make([]byte, n&0xffff)
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *sfntReader) u8() int {
+	return int(r.bytes(1)[0])
+}
+
+func (r *sfntReader) u16() int {
+	return int(binary.BigEndian.Uint16(r.bytes(2)))
+}
+
+func (r *sfntReader) u32() int {
+	return int(binary.BigEndian.Uint32(r.bytes(4)))
+}
+
+// base128 reads a UIntBase128 value.
+func (r *sfntReader) base128() (v int) {
+	for j := 0; j < 5; j++ {
+		b := r.u8()
+		if j == 0 && b == 0x80 || v > 0x1ffffff {
+			r.err = fmt.Errorf("invalid WOFF2 font")
+			return 0
+		}
+		v = v<<7 | b&0x7f
+		if b&0x80 == 0 {
+			return
+		}
+	}
+	r.err = fmt.Errorf("invalid WOFF2 font")
+	return 0
+}
+
+// u255 reads a 255UInt16 value.
+func (r *sfntReader) u255() int {
+	switch code := r.u8(); code {
+	case 253:
+		return r.u16()
+	case 255:
+		return r.u8() + 253
+	case 254:
+		return r.u8() + 506
+	default:
+		return code
+	}
+}
+
+// woff2Decode extracts the font from a WOFF2 file. The glyf and loca tables
+// and the hmtx table may have been transformed by the encoder; they are
+// reconstructed.
+func woff2Decode(data []byte) ([]byte, error) {
+	brotli.Lock()
+	decoder := brotli.decoder
+	brotli.Unlock()
+	if decoder == nil {
+		return nil, fmt.Errorf("WOFF2 fonts require a Brotli decoder; see SetBrotliDecoder()")
+	}
+	hdr := sfntReader{data: data}
+	hdr.bytes(4)
+	flavor := uint32(hdr.u32())
+	hdr.bytes(4) // length
+	numTables := hdr.u16()
+	hdr.bytes(2 + 4) // reserved, totalSfntSize
+	compressedSize := hdr.u32()
+	hdr.bytes(2 + 2 + 5*4) // version, metadata and private data
+	if hdr.err != nil {
+		return nil, hdr.err
+	}
+	if flavor == 0x74746366 {
+		return nil, fmt.Errorf("WOFF2 font collections are not supported")
+	}
+	type entry struct {
+		tag       string
+		transform int
+		length    int // Length of the data in the decompressed stream
+		origLen   int
+	}
+	entries := make([]entry, numTables)
+	for j := range entries {
+		e := &entries[j]
+		flags := hdr.u8()
+		if flags&0x3f == 0x3f {
+			e.tag = string(hdr.bytes(4))
+		} else if int(flags&0x3f) < len(woff2KnownTags) {
+			e.tag = woff2KnownTags[flags&0x3f]
+		} else {
+			return nil, fmt.Errorf("invalid WOFF2 table tag")
+		}
+		e.transform = flags >> 6
+		e.origLen = hdr.base128()
+		e.length = e.origLen
+		// Transform version 0 is the null transform except for glyf and
+		// loca, for which version 3 is
+		transformed := e.transform != 0
+		if e.tag == "glyf" || e.tag == "loca" {
+			transformed = e.transform != 3
+		}
+		if transformed {
+			e.length = hdr.base128()
+		}
+	}
+	if hdr.err != nil {
+		return nil, hdr.err
+	}
+	compressed := hdr.bytes(compressedSize)
+	if hdr.err != nil {
+		return nil, hdr.err
+	}
+	stream, err := ioutil.ReadAll(decoder(bytes.NewReader(compressed)))
+	if err != nil {
+		return nil, err
+	}
+	raw := make(map[string][]byte)
+	pos := 0
+	for _, e := range entries {
+		if pos+e.length > len(stream) {
+			return nil, fmt.Errorf("invalid WOFF2 font")
+		}
+		raw[e.tag] = stream[pos : pos+e.length]
+		pos += e.length
+	}
+	tables := make([]sfntTable, 0, len(entries))
+	var glyf, loca []byte
+	var xMins []int16
+	for _, e := range entries {
+		if e.tag == "glyf" && e.transform == 0 {
+			if glyf, loca, xMins, err = woff2Glyf(raw["glyf"]); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	for _, e := range entries {
+		t := sfntTable{tag: e.tag, data: raw[e.tag]}
+		switch {
+		case e.tag == "glyf" && glyf != nil:
+			t.data = glyf
+		case e.tag == "loca" && glyf != nil:
+			t.data = loca
+		case e.tag == "hmtx" && e.transform == 1:
+			if t.data, err = woff2Hmtx(t.data, raw["hhea"], xMins); err != nil {
+				return nil, err
+			}
+		case e.tag == "head" && glyf != nil && len(t.data) >= 54:
+			// The format of the reconstructed loca table
+			t.data = append([]byte(nil), t.data...)
+			format := 0
+			if len(loca) > 2*(len(xMins)+1) {
+				format = 1
+			}
+			binary.BigEndian.PutUint16(t.data[50:], uint16(format))
+		}
+		if e.tag == "head" && len(t.data) >= 12 {
+			// The checksum adjustment of the original font no longer holds
+			t.data = append([]byte(nil), t.data...)
+			binary.BigEndian.PutUint32(t.data[8:], 0)
+		}
+		tables = append(tables, t)
+	}
+	return sfntAssemble(flavor, tables), nil
+}
+
+// woff2Glyf reconstructs the glyf and loca tables from a transformed glyf
+// table. The minimum x coordinate of each glyph is returned for the
+// reconstruction of the hmtx table.
+func woff2Glyf(data []byte) (glyf, loca []byte, xMins []int16, err error) {
+	hdr := sfntReader{data: data}
+	hdr.u16() // reserved
+	optionFlags := hdr.u16()
+	numGlyphs := hdr.u16()
+	indexFormat := hdr.u16()
+	var sizes [7]int
+	for j := range sizes {
+		sizes[j] = hdr.u32()
+	}
+	// Streams of contour counts, point counts, flags, glyph data, composite
+	// glyph data, bounding boxes and instructions, in that order
+	var streams [7]sfntReader
+	for j := range streams {
+		streams[j].data = hdr.bytes(sizes[j])
+	}
+	if hdr.err != nil {
+		return nil, nil, nil, hdr.err
+	}
+	nContourStream, nPointsStream, flagStream := &streams[0], &streams[1], &streams[2]
+	glyphStream, compositeStream, bboxStream := &streams[3], &streams[4], &streams[5]
+	instructionStream := &streams[6]
+	bboxBitmap := bboxStream.bytes(4 * ((numGlyphs + 31) / 32))
+	var overlapBitmap []byte
+	if optionFlags&1 != 0 {
+		overlapBitmap = hdr.bytes((numGlyphs + 7) / 8)
+	}
+	var out bytes.Buffer
+	wr := func(v interface{}) { binary.Write(&out, binary.BigEndian, v) }
+	offsets := make([]int, numGlyphs+1)
+	xMins = make([]int16, numGlyphs)
+	for gid := 0; gid < numGlyphs; gid++ {
+		offsets[gid] = out.Len()
+		nContours := int16(nContourStream.u16())
+		hasBBox := bboxBitmap[gid>>3]&(0x80>>uint(gid&7)) != 0
+		switch {
+		case nContours == 0:
+			if hasBBox {
+				return nil, nil, nil, fmt.Errorf("invalid WOFF2 glyph data")
+			}
+		case nContours > 0:
+			endPts := make([]int, nContours)
+			nPoints := 0
+			for j := range endPts {
+				nPoints += nPointsStream.u255()
+				endPts[j] = nPoints - 1
+			}
+			xs, ys, onCurve := make([]int, nPoints), make([]int, nPoints), make([]bool, nPoints)
+			x, y := 0, 0
+			for j := 0; j < nPoints; j++ {
+				flag := flagStream.u8()
+				dx, dy := woff2Triplet(flag&0x7f, glyphStream)
+				x += dx
+				y += dy
+				xs[j], ys[j], onCurve[j] = x, y, flag&0x80 == 0
+			}
+			instructions := instructionStream.bytes(glyphStream.u255())
+			var bbox [4]int16
+			if hasBBox {
+				for j := range bbox {
+					bbox[j] = int16(bboxStream.u16())
+				}
+			} else if nPoints > 0 {
+				bbox = [4]int16{int16(xs[0]), int16(ys[0]), int16(xs[0]), int16(ys[0])}
+				for j := range xs {
+					bbox[0] = minInt16(bbox[0], int16(xs[j]))
+					bbox[1] = minInt16(bbox[1], int16(ys[j]))
+					bbox[2] = maxInt16(bbox[2], int16(xs[j]))
+					bbox[3] = maxInt16(bbox[3], int16(ys[j]))
+				}
+			}
+			xMins[gid] = bbox[0]
+			wr(nContours)
+			wr(bbox)
+			for _, end := range endPts {
+				wr(uint16(end))
+			}
+			wr(uint16(len(instructions)))
+			out.Write(instructions)
+			overlap := overlapBitmap != nil && overlapBitmap[gid>>3]&(0x80>>uint(gid&7)) != 0
+			woff2WritePoints(&out, xs, ys, onCurve, overlap)
+		default:
+			// Composite glyph, which always has an explicit bounding box
+			if !hasBBox {
+				return nil, nil, nil, fmt.Errorf("invalid WOFF2 composite glyph")
+			}
+			var bbox [4]int16
+			for j := range bbox {
+				bbox[j] = int16(bboxStream.u16())
+			}
+			xMins[gid] = bbox[0]
+			start := compositeStream.pos
+			haveInstructions := false
+			for more := true; more && compositeStream.err == nil; {
+				flags := compositeStream.u16()
+				more = flags&0x0020 != 0
+				haveInstructions = haveInstructions || flags&0x0100 != 0
+				n := 2 + 2 // glyph index and byte arguments
+				if flags&0x0001 != 0 {
+					n += 2
+				}
+				switch {
+				case flags&0x0008 != 0:
+					n += 2
+				case flags&0x0040 != 0:
+					n += 4
+				case flags&0x0080 != 0:
+					n += 8
+				}
+				compositeStream.bytes(n)
+			}
+			wr(nContours)
+			wr(bbox)
+			if compositeStream.err == nil {
+				out.Write(compositeStream.data[start:compositeStream.pos])
+			}
+			if haveInstructions {
+				instructions := instructionStream.bytes(glyphStream.u255())
+				wr(uint16(len(instructions)))
+				out.Write(instructions)
+			}
+		}
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+	offsets[numGlyphs] = out.Len()
+	for _, s := range streams {
+		if s.err != nil {
+			return nil, nil, nil, s.err
+		}
+	}
+	var locaBuf bytes.Buffer
+	for _, offset := range offsets {
+		if indexFormat == 0 {
+			binary.Write(&locaBuf, binary.BigEndian, uint16(offset/2))
+		} else {
+			binary.Write(&locaBuf, binary.BigEndian, uint32(offset))
+		}
+	}
+	return out.Bytes(), locaBuf.Bytes(), xMins, nil
+}
+
+// woff2Triplet decodes the coordinate deltas of a point of a simple glyph
+// that are encoded with the specified flag, without its on-curve bit.
+func woff2Triplet(flag int, r *sfntReader) (dx, dy int) {
+	withSign := func(flag, v int) int {
+		if flag&1 != 0 {
+			return v
+		}
+		return -v
+	}
+	switch {
+	case flag < 10:
+		dy = withSign(flag, (flag&14)<<7+r.u8())
+	case flag < 20:
+		dx = withSign(flag, ((flag-10)&14)<<7+r.u8())
+	case flag < 84:
+		b0, b1 := flag-20, r.u8()
+		dx = withSign(flag, 1+(b0&0x30)+b1>>4)
+		dy = withSign(flag>>1, 1+(b0&0x0c)<<2+b1&0x0f)
+	case flag < 120:
+		b0 := flag - 84
+		dx = withSign(flag, 1+(b0/12)<<8+r.u8())
+		dy = withSign(flag>>1, 1+((b0%12)>>2)<<8+r.u8())
+	case flag < 124:
+		b := r.bytes(3)
+		dx = withSign(flag, int(b[0])<<4+int(b[1])>>4)
+		dy = withSign(flag>>1, int(b[1]&0x0f)<<8+int(b[2]))
+	default:
+		b := r.bytes(4)
+		dx = withSign(flag, int(b[0])<<8+int(b[1]))
+		dy = withSign(flag>>1, int(b[2])<<8+int(b[3]))
+	}
+	return
+}
+
+// woff2WritePoints writes the flags and coordinates of the points of a simple
+// glyph in the form of the glyf table.
+func woff2WritePoints(out *bytes.Buffer, xs, ys []int, onCurve []bool, overlap bool) {
+	var flags, xBuf, yBuf bytes.Buffer
+	coord := func(buf *bytes.Buffer, d int, shortBit, sameBit byte) (flag byte) {
+		switch {
+		case d == 0:
+			return sameBit
+		case d > -256 && d < 256:
+			if d > 0 {
+				flag = shortBit | sameBit
+			} else {
+				flag = shortBit
+				d = -d
+			}
+			buf.WriteByte(byte(d))
+			return
+		}
+		binary.Write(buf, binary.BigEndian, int16(d))
+		return 0
+	}
+	prevX, prevY := 0, 0
+	for j := range xs {
+		var flag byte
+		if onCurve[j] {
+			flag = 0x01
+		}
+		if overlap && j == 0 {
+			flag |= 0x40
+		}
+		flag |= coord(&xBuf, xs[j]-prevX, 0x02, 0x10)
+		flag |= coord(&yBuf, ys[j]-prevY, 0x04, 0x20)
+		prevX, prevY = xs[j], ys[j]
+		flags.WriteByte(flag)
+	}
+	out.Write(flags.Bytes())
+	out.Write(xBuf.Bytes())
+	out.Write(yBuf.Bytes())
+}
+
+// woff2Hmtx reconstructs an hmtx table from which the left side bearings
+// that equal the minimum x coordinate of their glyph have been removed.
+func woff2Hmtx(data, hhea []byte, xMins []int16) ([]byte, error) {
+	bad := fmt.Errorf("invalid WOFF2 hmtx table")
+	if len(hhea) < 36 || xMins == nil {
+		return nil, bad
+	}
+	numHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	numGlyphs := len(xMins)
+	if numHMetrics < 1 || numHMetrics > numGlyphs {
+		return nil, bad
+	}
+	r := sfntReader{data: data}
+	flags := r.u8()
+	advances := make([]int, numHMetrics)
+	for j := range advances {
+		advances[j] = r.u16()
+	}
+	lsbs := make([]int16, numGlyphs)
+	for j := range lsbs {
+		switch {
+		case j < numHMetrics && flags&1 == 0, j >= numHMetrics && flags&2 == 0:
+			lsbs[j] = int16(r.u16())
+		default:
+			lsbs[j] = xMins[j]
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	var out bytes.Buffer
+	for j, lsb := range lsbs {
+		if j < numHMetrics {
+			binary.Write(&out, binary.BigEndian, uint16(advances[j]))
+		}
+		binary.Write(&out, binary.BigEndian, lsb)
+	}
+	return out.Bytes(), nil
+}
+
+func minInt16(a, b int16) int16 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt16(a, b int16) int16 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2013-2016 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gofpdf
+
+// SetOrphansWidows sets the minimum number of lines of a paragraph that are
+// left at the bottom of a page before an automatic page break, the orphans,
+// and that are carried to the top of the next page, the widows. A paragraph
+// whose first lines would be orphaned starts on the next page instead, and
+// lines are pushed to the next page before their time to keep the last lines
+// of a paragraph from being widowed, as long as this does not orphan its
+// first lines. A value of 1 or less disables the control, which is the
+// default for both. The control applies to MultiCell() and to the paragraphs
+// of ParagraphNew(), in which lines ended by a newline also end a paragraph,
+// and only takes effect if automatic page breaking is enabled.
+func (f *Fpdf) SetOrphansWidows(orphans, widows int) {
+	f.orphans = orphans
+	f.widows = widows
+}
+
+// GetOrphansWidows returns the minimum numbers of lines of a paragraph left
+// at the bottom and carried to the top of a page. See SetOrphansWidows().
+func (f *Fpdf) GetOrphansWidows() (orphans, widows int) {
+	return f.orphans, f.widows
+}
+
+// lineKeeperType issues the page breaks between the lines of paragraphs that
+// avoid widows and orphans.
+type lineKeeperType struct {
+	f         *Fpdf
+	h         float64
+	rest      []int // Number of lines of the paragraph from each line on
+	i         int   // Index of the next line
+	page      int   // Page of the previous line
+	y         float64
+	breakLine int // Index of the line before which the page is broken
+}
+
+// lineKeeper returns a keeper of the lines of height h whose last lines of
+// paragraphs are flagged by lasts, the final line ending a paragraph in any
+// case. Its next() method is called before each line is output.
+func (f *Fpdf) lineKeeper(lasts []bool, h float64) *lineKeeperType {
+	k := &lineKeeperType{f: f, h: h, rest: make([]int, len(lasts)), breakLine: -1}
+	for j := len(lasts) - 1; j >= 0; j-- {
+		k.rest[j] = 1
+		if j < len(lasts)-1 && !lasts[j] {
+			k.rest[j] += k.rest[j+1]
+		}
+	}
+	return k
+}
+
+// next breaks the page before the next line if this avoids a widow or an
+// orphan.
+func (k *lineKeeperType) next() {
+	f := k.f
+	i := k.i
+	k.i++
+	if f.orphans <= 1 && f.widows <= 1 || !f.autoPageBreak || f.inHeader || f.inFooter || i >= len(k.rest) {
+		return
+	}
+	first := i == 0 || k.rest[i-1] == 1
+	if i == k.breakLine {
+		k.breakPage()
+		k.plan(i, false)
+	} else if first || f.page != k.page || f.y < k.y {
+		// The first line of a paragraph, page or column sets where the page
+		// is broken within the paragraph
+		k.plan(i, first)
+	}
+	k.page, k.y = f.page, f.y
+}
+
+// plan sets the line before which the page is broken when the line i is the
+// first of the paragraph or of the page or column, breaking the page before
+// line i if a paragraph that starts with it would be orphaned.
+func (k *lineKeeperType) plan(i int, first bool) {
+	f := k.f
+	k.breakLine = -1
+	fit := int((f.pageBreakTrigger - f.y + 1e-9) / k.h)
+	rest := k.rest[i]
+	if fit >= rest || fit == 0 {
+		return
+	}
+	minLines := 1
+	if first {
+		minLines = f.orphans
+	}
+	if rest-fit < f.widows {
+		fit -= f.widows - (rest - fit)
+	}
+	if fit >= minLines {
+		k.breakLine = i + fit
+	} else if first {
+		k.breakPage()
+		k.plan(i, false)
+	}
+}
+
+// breakPage breaks the page, as an automatic page break would.
+func (k *lineKeeperType) breakPage() {
+	f := k.f
+	k.breakLine = -1
+	if !f.acceptBreak() {
+		return
+	}
+	x := f.x
+	if f.ws > 0 {
+		f.ws = 0
+		f.wordSpacingPut()
+	}
+	f.AddPageFormat(f.curOrientation, f.curPageSize)
+	f.x = x
+}